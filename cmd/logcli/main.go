@@ -593,6 +593,8 @@ func newQueryClient(app *kingpin.Application) client.Client {
 	app.Flag("proxy-url", "The http or https proxy to use when making requests. Can also be set using LOKI_HTTP_PROXY_URL env var.").Default("").Envar("LOKI_HTTP_PROXY_URL").StringVar(&client.ProxyURL)
 	app.Flag("compress", "Request that Loki compress returned data in transit. Can also be set using LOKI_HTTP_COMPRESSION env var.").Default("false").Envar("LOKI_HTTP_COMPRESSION").BoolVar(&client.Compression)
 	app.Flag("envproxy", "Use ProxyFromEnvironment to use net/http ProxyFromEnvironment configuration, eg HTTP_PROXY").Default("false").Envar("LOKI_ENV_PROXY").BoolVar(&client.EnvironmentProxy)
+	app.Flag("tail-transport", "Transport used to tail logs: websocket, sse (Server-Sent Events) or grpc. Use sse or grpc when a proxy in front of Loki does not support websockets. Can also be set using LOKI_TAIL_TRANSPORT env var.").Default("websocket").Envar("LOKI_TAIL_TRANSPORT").EnumVar(&client.TailTransport, "websocket", "sse", "grpc")
+	app.Flag("grpc-addr", "Server gRPC address (host:port) used to tail logs with the grpc transport. Defaults to the host of --addr on port 9095. TLS is used when --addr is an https URL. Can also be set using LOKI_GRPC_ADDR env var.").Default("").Envar("LOKI_GRPC_ADDR").StringVar(&client.GRPCAddress)
 
 	return client
 }
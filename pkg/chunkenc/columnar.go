@@ -0,0 +1,433 @@
+package chunkenc
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/bits-and-blooms/bloom/v3"
+	"github.com/pkg/errors"
+	"github.com/prometheus/otlptranslator"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/v3/pkg/compression"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/log"
+	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
+)
+
+// Blocks of ChunkFormatV5 chunks store structured metadata in columns, separately from the log lines:
+//
+//	┌──────────────────────┬─────────────────────────────────┬──────────────────────────────────────────┐
+//	│ len(lines) <uvarint> │ lines <compressed>              │ structured metadata columns <compressed> │
+//	└──────────────────────┴─────────────────────────────────┴──────────────────────────────────────────┘
+//
+// The lines section holds the timestamp, length and content of each entry, like blocks of ChunkFormatV2.
+// The structured metadata section starts with the number of columns, followed by the name symbol and
+// the length in bytes of each column, followed by the columns. A column holds one uvarint per entry,
+// which is 0 if the entry has no structured metadata with the name of the column, or the value symbol + 1.
+//
+// The block metas of ChunkFormatV5 chunks additionally hold the blockStats of each block.
+
+// falsePositiveRate is the false positive rate of the bloom filters of blockStats.
+const falsePositiveRate = 0.01
+
+// serialiseColumnar serialises the entries of the head block into a block of ChunkFormatV5 and
+// returns the statistics of its structured metadata.
+func (hb *unorderedHeadBlock) serialiseColumnar(pool compression.WriterPool) ([]byte, *blockStats, error) {
+	linesBuf := serializeBytesBufferPool.Get().(*bytes.Buffer)
+	defer func() {
+		linesBuf.Reset()
+		serializeBytesBufferPool.Put(linesBuf)
+	}()
+
+	columnsBuf := serializeBytesBufferPool.Get().(*bytes.Buffer)
+	defer func() {
+		columnsBuf.Reset()
+		serializeBytesBufferPool.Put(columnsBuf)
+	}()
+
+	var (
+		encBuf = make([]byte, binary.MaxVarintLen64)
+
+		entries int
+		// columns holds the value symbol + 1 of each entry by name symbol.
+		columns = map[uint32][]uint64{}
+		names   []uint32
+
+		statsBuilder    = newBlockStatsBuilder()
+		labelNamer      = otlptranslator.LabelNamer{}
+		normalizedNames = map[uint32]string{}
+	)
+
+	err := hb.forEntries(
+		context.Background(),
+		logproto.FORWARD,
+		0,
+		math.MaxInt64,
+		func(_ *stats.Context, ts int64, line string, structuredMetadataSymbols symbols) error {
+			n := binary.PutVarint(encBuf, ts)
+			linesBuf.Write(encBuf[:n])
+
+			n = binary.PutUvarint(encBuf, uint64(len(line)))
+			linesBuf.Write(encBuf[:n])
+
+			linesBuf.WriteString(line)
+
+			for _, s := range structuredMetadataSymbols {
+				column, ok := columns[s.Name]
+				if !ok {
+					names = append(names, s.Name)
+				}
+				if len(column) > entries {
+					// The entry has the same name multiple times, the last one wins.
+					column = column[:entries]
+				}
+				for len(column) < entries {
+					column = append(column, 0)
+				}
+				columns[s.Name] = append(column, uint64(s.Value)+1)
+			}
+			entries++
+
+			for _, s := range structuredMetadataSymbols {
+				// Names are normalized when entries are read, see symbolizer.Lookup.
+				name, ok := normalizedNames[s.Name]
+				if !ok {
+					var err error
+					name, err = labelNamer.Build(hb.symbolizer.lookup(s.Name))
+					if err != nil {
+						return err
+					}
+					normalizedNames[s.Name] = name
+				}
+				statsBuilder.add(name, hb.symbolizer.lookup(s.Value))
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "iterating entries")
+	}
+
+	slices.SortFunc(names, func(a, b uint32) int {
+		return strings.Compare(hb.symbolizer.lookup(a), hb.symbolizer.lookup(b))
+	})
+
+	eb := EncodeBufferPool.Get().(*encbuf)
+	defer EncodeBufferPool.Put(eb)
+
+	// Encode the columns first to know their lengths for the header.
+	encodedColumns := make([][]byte, 0, len(names))
+	for _, name := range names {
+		eb.reset()
+		column := columns[name]
+		for i := 0; i < entries; i++ {
+			var v uint64
+			if i < len(column) {
+				v = column[i]
+			}
+			eb.putUvarint64(v)
+		}
+		encodedColumns = append(encodedColumns, slices.Clone(eb.get()))
+	}
+
+	eb.reset()
+	eb.putUvarint(len(names))
+	for i, name := range names {
+		eb.putUvarint64(uint64(name))
+		eb.putUvarint(len(encodedColumns[i]))
+	}
+	columnsBuf.Write(eb.get())
+	for _, column := range encodedColumns {
+		columnsBuf.Write(column)
+	}
+
+	lines, err := compress(pool, linesBuf.Bytes())
+	if err != nil {
+		return nil, nil, err
+	}
+	structuredMetadata, err := compress(pool, columnsBuf.Bytes())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	eb.reset()
+	eb.putUvarint(len(lines))
+	out := make([]byte, 0, len(eb.get())+len(lines)+len(structuredMetadata))
+	out = append(out, eb.get()...)
+	out = append(out, lines...)
+	out = append(out, structuredMetadata...)
+
+	return out, statsBuilder.build(), nil
+}
+
+func compress(pool compression.WriterPool, b []byte) ([]byte, error) {
+	outBuf := &bytes.Buffer{}
+	compressedWriter := pool.GetWriter(outBuf)
+	defer pool.PutWriter(compressedWriter)
+
+	if _, err := compressedWriter.Write(b); err != nil {
+		return nil, errors.Wrap(err, "appending entry")
+	}
+	if err := compressedWriter.Close(); err != nil {
+		return nil, errors.Wrap(err, "flushing pending compress buffer")
+	}
+	return outBuf.Bytes(), nil
+}
+
+// splitColumnarBlock returns the lines and structured metadata sections of a block of ChunkFormatV5.
+func splitColumnarBlock(b []byte) (lines, structuredMetadata []byte, err error) {
+	l, n := binary.Uvarint(b)
+	if n <= 0 || uint64(len(b)-n) < l {
+		return nil, nil, fmt.Errorf("invalid lines section length in block")
+	}
+	return b[n : n+int(l)], b[n+int(l):], nil
+}
+
+// metadataColumn is the remainder of a structured metadata column of a block while it is being read.
+type metadataColumn struct {
+	name uint32
+	b    []byte
+}
+
+// readMetadataColumns decompresses the structured metadata section of a block of ChunkFormatV5.
+func readMetadataColumns(pool compression.ReaderPool, b []byte) ([]metadataColumn, error) {
+	r, err := pool.GetReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	defer pool.PutReader(r)
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "decompressing structured metadata")
+	}
+
+	db := decbuf{b: data}
+	num := db.uvarint()
+	columns := make([]metadataColumn, num)
+	lengths := make([]int, num)
+	for i := 0; i < num && db.err() == nil; i++ {
+		columns[i].name = uint32(db.uvarint64())
+		lengths[i] = db.uvarint()
+	}
+	for i := 0; i < num && db.err() == nil; i++ {
+		columns[i].b = db.bytes(lengths[i])
+	}
+	if err := db.err(); err != nil {
+		return nil, errors.Wrap(err, "decoding structured metadata columns")
+	}
+	return columns, nil
+}
+
+// nextSymbols reads the structured metadata of the next entry from the columns into buf.
+// It returns the number of bytes read.
+func nextSymbols(columns []metadataColumn, buf symbols) (symbols, int, error) {
+	var read int
+	for i := range columns {
+		v, n := binary.Uvarint(columns[i].b)
+		if n <= 0 {
+			return buf, read, fmt.Errorf("invalid data in structured metadata column")
+		}
+		columns[i].b = columns[i].b[n:]
+		read += n
+		if v == 0 {
+			continue
+		}
+		buf = append(buf, symbol{Name: columns[i].name, Value: uint32(v - 1)})
+	}
+	return buf, read, nil
+}
+
+// labelFiltersPipeline is implemented by pipelines which can report label filters that lines
+// must pass, see [log.StreamPipeline].
+type labelFiltersPipeline interface {
+	LabelFiltersBeforeParser() []log.LabelFilterer
+}
+
+// labelFiltersBeforeParser returns the label filters that lines must pass to be returned by the pipeline.
+func labelFiltersBeforeParser(pipeline log.StreamPipeline) []log.LabelFilterer {
+	if p, ok := pipeline.(labelFiltersPipeline); ok {
+		return p.LabelFiltersBeforeParser()
+	}
+	return nil
+}
+
+// blockStats summarises the structured metadata of a block, so that blocks without entries
+// matching a label filter can be skipped without decompressing them.
+type blockStats struct {
+	// columns holds the value range of each structured metadata name of the block, sorted by name.
+	columns []columnStats
+	// bloom holds all name and value pairs of the structured metadata of the block.
+	// It is nil if the block has no structured metadata.
+	bloom *bloom.BloomFilter
+}
+
+type columnStats struct {
+	name     string
+	min, max string
+}
+
+func bloomKey(name, value string) string {
+	// Label names can't contain "=", so keys are unique.
+	return name + "=" + value
+}
+
+// mayMatch returns false if no entry of the block can pass all filters.
+// stream are the labels of the stream of the chunk.
+func (s *blockStats) mayMatch(filters []log.LabelFilterer, stream labels.Labels) bool {
+	if s == nil {
+		return true
+	}
+	for _, filter := range filters {
+		if !s.mayMatchFilter(filter, stream) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *blockStats) mayMatchFilter(filter log.LabelFilterer, stream labels.Labels) bool {
+	switch filter := filter.(type) {
+	case *log.BinaryLabelFilter:
+		if filter.And {
+			return s.mayMatchFilter(filter.Left, stream) && s.mayMatchFilter(filter.Right, stream)
+		}
+		return s.mayMatchFilter(filter.Left, stream) || s.mayMatchFilter(filter.Right, stream)
+	case *log.StringLabelFilter:
+		return s.mayMatchEqual(filter.Matcher, stream)
+	case *log.LineFilterLabelFilter:
+		return s.mayMatchEqual(filter.Matcher, stream)
+	default:
+		return true
+	}
+}
+
+func (s *blockStats) mayMatchEqual(m *labels.Matcher, stream labels.Labels) bool {
+	// Only equality matchers with a non-empty value can be tested. Stream labels and internal labels
+	// like __error__ are not part of the statistics.
+	if m.Type != labels.MatchEqual || m.Value == "" || strings.HasPrefix(m.Name, "__") || stream.Has(m.Name) {
+		return true
+	}
+
+	idx, found := slices.BinarySearchFunc(s.columns, m.Name, func(c columnStats, name string) int {
+		return strings.Compare(c.name, name)
+	})
+	if !found {
+		return false
+	}
+	if column := s.columns[idx]; m.Value < column.min || m.Value > column.max {
+		return false
+	}
+	return s.bloom == nil || s.bloom.TestString(bloomKey(m.Name, m.Value))
+}
+
+// size returns the maximum number of bytes of the encoded statistics.
+func (s *blockStats) size() int {
+	size := binary.MaxVarintLen32 // number of columns
+	for _, column := range s.columns {
+		size += 3*binary.MaxVarintLen32 + len(column.name) + len(column.min) + len(column.max)
+	}
+	size += binary.MaxVarintLen32 // length of bloom filter
+	if s.bloom != nil {
+		// bloom filters are encoded as m, k and the number of words of the bitset followed by the words.
+		size += 3*8 + int((s.bloom.Cap()+63)/64)*8
+	}
+	return size
+}
+
+func (s *blockStats) encode(eb *encbuf) error {
+	eb.putUvarint(len(s.columns))
+	for _, column := range s.columns {
+		eb.putUvarintStr(column.name)
+		eb.putUvarintStr(column.min)
+		eb.putUvarintStr(column.max)
+	}
+
+	if s.bloom == nil {
+		eb.putUvarint(0)
+		return nil
+	}
+	b, err := s.bloom.MarshalBinary()
+	if err != nil {
+		return errors.Wrap(err, "encoding bloom filter")
+	}
+	eb.putUvarint(len(b))
+	eb.b = append(eb.b, b...)
+	return nil
+}
+
+func decodeBlockStats(db *decbuf) (*blockStats, error) {
+	var s blockStats
+
+	num := db.uvarint()
+	if db.err() != nil {
+		return nil, db.err()
+	}
+	s.columns = make([]columnStats, 0, num)
+	for i := 0; i < num && db.err() == nil; i++ {
+		s.columns = append(s.columns, columnStats{
+			name: db.uvarintStr(),
+			min:  db.uvarintStr(),
+			max:  db.uvarintStr(),
+		})
+	}
+
+	if l := db.uvarint(); l > 0 && db.err() == nil {
+		b := db.bytes(l)
+		if db.err() != nil {
+			return nil, db.err()
+		}
+		s.bloom = &bloom.BloomFilter{}
+		if err := s.bloom.UnmarshalBinary(b); err != nil {
+			return nil, errors.Wrap(err, "decoding bloom filter")
+		}
+	}
+	return &s, db.err()
+}
+
+type blockStatsBuilder struct {
+	columns map[string]*columnStats
+	keys    map[string]struct{}
+}
+
+func newBlockStatsBuilder() *blockStatsBuilder {
+	return &blockStatsBuilder{
+		columns: map[string]*columnStats{},
+		keys:    map[string]struct{}{},
+	}
+}
+
+func (b *blockStatsBuilder) add(name, value string) {
+	b.keys[bloomKey(name, value)] = struct{}{}
+
+	column, ok := b.columns[name]
+	if !ok {
+		b.columns[name] = &columnStats{name: name, min: value, max: value}
+		return
+	}
+	column.min = min(column.min, value)
+	column.max = max(column.max, value)
+}
+
+func (b *blockStatsBuilder) build() *blockStats {
+	s := &blockStats{columns: make([]columnStats, 0, len(b.columns))}
+	for _, column := range b.columns {
+		s.columns = append(s.columns, *column)
+	}
+	slices.SortFunc(s.columns, func(a, b columnStats) int { return strings.Compare(a.name, b.name) })
+
+	if len(b.keys) > 0 {
+		s.bloom = bloom.NewWithEstimates(uint(len(b.keys)), falsePositiveRate)
+		for key := range b.keys {
+			s.bloom.AddString(key)
+		}
+	}
+	return s
+}
@@ -0,0 +1,174 @@
+package chunkenc
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/push"
+
+	"github.com/grafana/loki/v3/pkg/compression"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
+)
+
+func TestBlockStats_Encoding(t *testing.T) {
+	builder := newBlockStatsBuilder()
+	builder.add("trace_id", "bbb")
+	builder.add("trace_id", "aaa")
+	builder.add("pod", "pod-1")
+	expected := builder.build()
+
+	eb := &encbuf{}
+	require.NoError(t, expected.encode(eb))
+	require.LessOrEqual(t, len(eb.get()), expected.size())
+
+	db := decbuf{b: eb.get()}
+	actual, err := decodeBlockStats(&db)
+	require.NoError(t, err)
+	require.Empty(t, db.b)
+
+	require.Equal(t, []columnStats{
+		{name: "pod", min: "pod-1", max: "pod-1"},
+		{name: "trace_id", min: "aaa", max: "bbb"},
+	}, actual.columns)
+	require.True(t, expected.bloom.Equal(actual.bloom))
+}
+
+func TestBlockStats_MayMatch(t *testing.T) {
+	builder := newBlockStatsBuilder()
+	builder.add("trace_id", "bbb")
+	builder.add("trace_id", "ddd")
+	s := builder.build()
+
+	for _, tc := range []struct {
+		query  string
+		stream labels.Labels
+		match  bool
+	}{
+		{query: `{app="foo"} | trace_id="bbb"`, match: true},
+		{query: `{app="foo"} | trace_id="ccc"`, match: false},
+		{query: `{app="foo"} | trace_id="aaa"`, match: false},
+		{query: `{app="foo"} | trace_id="eee"`, match: false},
+		{query: `{app="foo"} | pod="pod-1"`, match: false},
+		{query: `{app="foo"} | pod="pod-1"`, stream: labels.FromStrings("pod", "pod-1"), match: true},
+		{query: `{app="foo"} | pod=""`, match: true},
+		{query: `{app="foo"} | trace_id!="bbb"`, match: true},
+		{query: `{app="foo"} | trace_id="ccc" or trace_id="ddd"`, match: true},
+		{query: `{app="foo"} | trace_id="ccc" or trace_id="eee"`, match: false},
+		{query: `{app="foo"} | trace_id="bbb" and pod="pod-1"`, match: false},
+		{query: `{app="foo"} |= "foo" | trace_id="ccc"`, match: false},
+		{query: `{app="foo"} | logfmt | trace_id="ccc"`, match: true},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			expr, err := syntax.ParseLogSelector(tc.query, true)
+			require.NoError(t, err)
+			p, err := expr.Pipeline()
+			require.NoError(t, err)
+
+			filters := labelFiltersBeforeParser(p.ForStream(tc.stream))
+			require.Equal(t, tc.match, s.mayMatch(filters, tc.stream))
+		})
+	}
+}
+
+func TestMemChunk_IteratorSkipsBlocks(t *testing.T) {
+	for _, enc := range testEncodings {
+		t.Run(enc.String(), func(t *testing.T) {
+			chk := NewMemChunk(ChunkFormatV5, enc, UnorderedWithStructuredMetadataHeadBlockFmt, 256, 0)
+
+			// Every block holds the entries of one trace.
+			const entriesPerBlock = 10
+			for trace := 0; trace < 5; trace++ {
+				for i := 0; i < entriesPerBlock; i++ {
+					_, err := chk.Append(&logproto.Entry{
+						Timestamp:          time.Unix(0, int64(trace*entriesPerBlock+i)),
+						Line:               fmt.Sprintf("trace %d line %d", trace, i),
+						StructuredMetadata: push.LabelsAdapter{{Name: "trace_id", Value: fmt.Sprintf("trace-%d", trace)}},
+					})
+					require.NoError(t, err)
+				}
+				require.NoError(t, chk.cut())
+			}
+			require.NoError(t, chk.Close())
+
+			b, err := chk.Bytes()
+			require.NoError(t, err)
+			decoded, err := NewByteChunk(b, 0, 0)
+			require.NoError(t, err)
+			require.Equal(t, ChunkFormatV5, decoded.format)
+			require.Len(t, decoded.blocks, 5)
+
+			expr, err := syntax.ParseLogSelector(`{app="foo"} | trace_id="trace-3"`, true)
+			require.NoError(t, err)
+			p, err := expr.Pipeline()
+			require.NoError(t, err)
+
+			statsCtx, ctx := stats.NewContext(context.Background())
+			it, err := decoded.Iterator(ctx, time.Unix(0, 0), time.Unix(0, 100), logproto.FORWARD, p.ForStream(labels.FromStrings("app", "foo")))
+			require.NoError(t, err)
+
+			var lines []string
+			for it.Next() {
+				lines = append(lines, it.At().Line)
+				require.Equal(t, push.LabelsAdapter{{Name: "trace_id", Value: "trace-3"}}, it.At().StructuredMetadata)
+			}
+			require.NoError(t, it.Err())
+			require.NoError(t, it.Close())
+
+			require.Len(t, lines, entriesPerBlock)
+			require.Equal(t, "trace 3 line 0", lines[0])
+			// Only the block of the trace is decompressed.
+			require.Equal(t, int64(entriesPerBlock), statsCtx.Result(0, 0, 0).TotalDecompressedLines())
+		})
+	}
+}
+
+func TestMemChunk_ReadsV4AndV5(t *testing.T) {
+	entries := []logproto.Entry{
+		{Timestamp: time.Unix(0, 1), Line: "one", StructuredMetadata: push.LabelsAdapter{{Name: "a", Value: "1"}, {Name: "b", Value: "2"}}},
+		{Timestamp: time.Unix(0, 2), Line: "two"},
+		{Timestamp: time.Unix(0, 3), Line: "three", StructuredMetadata: push.LabelsAdapter{{Name: "b", Value: "3"}}},
+	}
+
+	for _, format := range []byte{ChunkFormatV4, ChunkFormatV5} {
+		t.Run(fmt.Sprintf("v%d", format), func(t *testing.T) {
+			chk := NewMemChunk(format, compression.Snappy, UnorderedWithStructuredMetadataHeadBlockFmt, testBlockSize, 0)
+			for _, e := range entries {
+				_, err := chk.Append(&e)
+				require.NoError(t, err)
+			}
+			require.NoError(t, chk.Close())
+
+			b, err := chk.Bytes()
+			require.NoError(t, err)
+			decoded, err := NewByteChunk(b, 0, 0)
+			require.NoError(t, err)
+
+			expr, err := syntax.ParseLogSelector(`{app="foo"}`, true)
+			require.NoError(t, err)
+			p, err := expr.Pipeline()
+			require.NoError(t, err)
+
+			it, err := decoded.Iterator(context.Background(), time.Unix(0, 0), time.Unix(0, 10), logproto.FORWARD, p.ForStream(labels.FromStrings("app", "foo")))
+			require.NoError(t, err)
+
+			var actual []logproto.Entry
+			for it.Next() {
+				e := it.At()
+				if len(e.StructuredMetadata) == 0 {
+					e.StructuredMetadata = nil
+				}
+				e.Parsed = nil
+				actual = append(actual, e)
+			}
+			require.NoError(t, it.Close())
+			require.Equal(t, entries, actual)
+		})
+	}
+}
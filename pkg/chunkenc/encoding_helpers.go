@@ -35,6 +35,11 @@ func (e *encbuf) putUvarint64(x uint64) {
 	e.b = append(e.b, e.c[:n]...)
 }
 
+func (e *encbuf) putUvarintStr(s string) {
+	e.putUvarint(len(s))
+	e.b = append(e.b, s...)
+}
+
 func (e *encbuf) putVarint64(x int64) {
 	n := binary.PutVarint(e.c[:], x)
 	e.b = append(e.b, e.c[:n]...)
@@ -131,4 +136,9 @@ func (d *decbuf) bytes(n int) []byte {
 	return x
 }
 
+func (d *decbuf) uvarintStr() string {
+	l := d.uvarint()
+	return string(d.bytes(l))
+}
+
 func (d *decbuf) err() error { return d.e }
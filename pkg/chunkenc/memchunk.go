@@ -34,6 +34,9 @@ const (
 	ChunkFormatV2
 	ChunkFormatV3
 	ChunkFormatV4
+	// ChunkFormatV5 stores structured metadata in columns inside each block and
+	// adds statistics of the structured metadata of each block to the block metas.
+	ChunkFormatV5
 
 	blocksPerChunk = 10
 	maxLineLength  = 1024 * 1024 * 1024
@@ -137,6 +140,9 @@ type MemChunk struct {
 	encoding compression.Codec
 	headFmt  HeadBlockFmt
 
+	// dictionary compresses new blocks when the encoding is compression.ZstdDict.
+	dictionary *compression.Dictionary
+
 	// compressed size of chunk. Set when chunk is cut or while decoding chunk from storage.
 	compressedSize int
 }
@@ -150,6 +156,9 @@ type block struct {
 
 	offset           int // The offset of the block in the chunk.
 	uncompressedSize int // Total uncompressed size in bytes when the chunk is cut.
+
+	// stats summarises the structured metadata of the block. Only set for chunk format v5+.
+	stats *blockStats
 }
 
 // This block holds the un-compressed entries. Once it has enough data, this is
@@ -306,7 +315,7 @@ func (hb *headBlock) LoadBytes(b []byte) error {
 		return errors.Wrap(db.err(), "verifying headblock header")
 	}
 	switch version {
-	case ChunkFormatV1, ChunkFormatV2, ChunkFormatV3, ChunkFormatV4:
+	case ChunkFormatV1, ChunkFormatV2, ChunkFormatV3, ChunkFormatV4, ChunkFormatV5:
 	default:
 		return errors.Errorf("incompatible headBlock version (%v), only V1,V2,V3 is currently supported", version)
 	}
@@ -369,6 +378,9 @@ func panicIfInvalidFormat(chunkFmt byte, head HeadBlockFmt) {
 		fmt.Println("received head fmt", head.String())
 		panic("only UnorderedWithStructuredMetadataHeadBlockFmt is supported for V4 chunks")
 	}
+	if chunkFmt == ChunkFormatV5 && head != UnorderedWithStructuredMetadataHeadBlockFmt {
+		panic("only UnorderedWithStructuredMetadataHeadBlockFmt is supported for V5 chunks")
+	}
 }
 
 // NewMemChunk returns a new in-mem chunk.
@@ -417,7 +429,7 @@ func newByteChunk(b []byte, blockSize, targetSize int, fromCheckpoint bool) (*Me
 	switch version {
 	case ChunkFormatV1:
 		bc.encoding = compression.GZIP
-	case ChunkFormatV2, ChunkFormatV3, ChunkFormatV4:
+	case ChunkFormatV2, ChunkFormatV3, ChunkFormatV4, ChunkFormatV5:
 		// format v2+ has a byte for block encoding.
 		enc := compression.Codec(db.byte())
 		if db.err() != nil {
@@ -486,6 +498,13 @@ func newByteChunk(b []byte, blockSize, targetSize int, fromCheckpoint bool) (*Me
 			blk.uncompressedSize = db.uvarint()
 		}
 		l := db.uvarint()
+		if version >= ChunkFormatV5 {
+			stats, err := decodeBlockStats(&db)
+			if err != nil {
+				return nil, errors.Wrap(err, "decoding block stats")
+			}
+			blk.stats = stats
+		}
 
 		invalidBlockErr := validateBlock(b, blk.offset, l)
 		if invalidBlockErr != nil {
@@ -586,6 +605,9 @@ func (c *MemChunk) BytesSize() int {
 			size += binary.MaxVarintLen32 // uncompressed size
 		}
 		size += binary.MaxVarintLen32 // len(b)
+		if c.format >= ChunkFormatV5 && b.stats != nil {
+			size += b.stats.size() // block stats
+		}
 	}
 
 	// blockmeta
@@ -656,7 +678,7 @@ func (c *MemChunk) writeTo(w io.Writer, forCheckpoint bool) (int64, error) {
 			}
 		} else {
 			var err error
-			n, crcHash, err = c.symbolizer.SerializeTo(w, compression.GetWriterPool(c.encoding))
+			n, crcHash, err = c.symbolizer.SerializeTo(w, c.writerPool())
 			if err != nil {
 				return offset, errors.Wrap(err, "write structured metadata")
 			}
@@ -703,6 +725,15 @@ func (c *MemChunk) writeTo(w io.Writer, forCheckpoint bool) (int64, error) {
 			eb.putUvarint(b.uncompressedSize)
 		}
 		eb.putUvarint(len(b.b))
+		if c.format >= ChunkFormatV5 {
+			stats := b.stats
+			if stats == nil {
+				stats = &blockStats{}
+			}
+			if err := stats.encode(eb); err != nil {
+				return offset, errors.Wrap(err, "write block stats")
+			}
+		}
 	}
 	metasLen := len(eb.get())
 	eb.putHash(crc32Hash)
@@ -783,6 +814,20 @@ func (c *MemChunk) Encoding() compression.Codec {
 	return c.encoding
 }
 
+// SetDictionary sets the dictionary used to compress blocks cut from now on.
+// It is ignored unless the chunk encoding is compression.ZstdDict. Readers
+// load the dictionary with compression.LoadDictionary.
+func (c *MemChunk) SetDictionary(d *compression.Dictionary) {
+	c.dictionary = d
+}
+
+func (c *MemChunk) writerPool() compression.WriterPool {
+	if c.encoding == compression.ZstdDict && c.dictionary != nil {
+		return c.dictionary
+	}
+	return compression.GetWriterPool(c.encoding)
+}
+
 // Size implements Chunk.
 func (c *MemChunk) Size() int {
 	ne := 0
@@ -925,6 +970,7 @@ func (c *MemChunk) reorder() error {
 	}
 
 	newChunk := NewMemChunk(c.format, c.Encoding(), c.headFmt, c.blockSize, c.targetSize)
+	newChunk.SetDictionary(c.dictionary)
 	for itr.Next() {
 		entry := itr.At()
 		if _, err := newChunk.Append(&entry); err != nil {
@@ -959,7 +1005,20 @@ func (c *MemChunk) cut() error {
 		return nil
 	}
 
-	b, err := c.head.Serialise(compression.GetWriterPool(c.encoding))
+	var (
+		b     []byte
+		stats *blockStats
+		err   error
+	)
+	if c.format >= ChunkFormatV5 {
+		hb, ok := c.head.(*unorderedHeadBlock)
+		if !ok {
+			return fmt.Errorf("unsupported head block format %s for chunk format v%d", c.head.Format(), c.format)
+		}
+		b, stats, err = hb.serialiseColumnar(c.writerPool())
+	} else {
+		b, err = c.head.Serialise(c.writerPool())
+	}
 	if err != nil {
 		return err
 	}
@@ -971,6 +1030,7 @@ func (c *MemChunk) cut() error {
 		mint:             mint,
 		maxt:             maxt,
 		uncompressedSize: c.head.UncompressedSize(),
+		stats:            stats,
 	})
 
 	c.cutBlockSize += len(b)
@@ -1010,6 +1070,16 @@ func (c *MemChunk) Iterator(ctx context.Context, mintT, maxtT time.Time, directi
 	}
 	var headIterator iter.EntryIterator
 
+	// Blocks of chunk format v5+ can be skipped if their structured metadata can't pass the label filters.
+	var (
+		filters []log.LabelFilterer
+		stream  labels.Labels
+	)
+	if c.format >= ChunkFormatV5 {
+		filters = labelFiltersBeforeParser(pipeline)
+		stream = pipeline.BaseLabels().Stream()
+	}
+
 	var lastMax int64 // placeholder to check order across blocks
 	ordered := true
 	for _, b := range c.blocks {
@@ -1018,6 +1088,9 @@ func (c *MemChunk) Iterator(ctx context.Context, mintT, maxtT time.Time, directi
 		if maxt < b.mint || b.maxt < mint {
 			continue
 		}
+		if len(filters) > 0 && !b.stats.mayMatch(filters, stream) {
+			continue
+		}
 
 		if b.mint < lastMax {
 			ordered = false
@@ -1159,6 +1232,7 @@ func (c *MemChunk) Blocks(mintT, maxtT time.Time) []Block {
 // The new chunk would have data in the same order as the original chunk.
 func (c *MemChunk) Rewrite(filter filter.Func) (Chunk, error) {
 	newChunk := NewMemChunk(c.format, c.Encoding(), c.headFmt, math.MaxInt, math.MaxInt)
+	newChunk.SetDictionary(c.dictionary)
 
 	// iterate through the entries block-by-block to avoid re-encoding unchanged blocks
 	for _, b := range c.blocks {
@@ -1229,6 +1303,7 @@ func (c *MemChunk) Rebound(start, end time.Time, filter filter.Func) (Chunk, err
 		// For target chunk size I am using compressed size of original chunk since the newChunk should anyways be lower in size than that.
 		newChunk = NewMemChunk(c.format, c.Encoding(), c.headFmt, defaultBlockSize, c.CompressedSize())
 	}
+	newChunk.SetDictionary(c.dictionary)
 
 	for itr.Next() {
 		entry := itr.At()
@@ -1466,6 +1541,8 @@ type bufferedIterator struct {
 	symbolsBuf             []symbol      // The buffer for a single entry's symbols.
 	currStructuredMetadata labels.Labels // The current labels.
 
+	columns []metadataColumn // The structured metadata columns of chunk format v5+ blocks.
+
 	closed bool
 }
 
@@ -1488,9 +1565,27 @@ func (si *bufferedIterator) Next() bool {
 	}
 
 	if !si.closed && si.reader == nil {
+		lines := si.origBytes
+		if si.format >= ChunkFormatV5 {
+			var (
+				structuredMetadata []byte
+				err                error
+			)
+			lines, structuredMetadata, err = splitColumnarBlock(si.origBytes)
+			if err != nil {
+				si.err = err
+				return false
+			}
+			si.columns, err = readMetadataColumns(si.pool, structuredMetadata)
+			if err != nil {
+				si.err = err
+				return false
+			}
+		}
+
 		// initialize reader now, hopefully reusing one of the previous readers
 		var err error
-		si.reader, err = si.pool.GetReader(bytes.NewBuffer(si.origBytes))
+		si.reader, err = si.pool.GetReader(bytes.NewBuffer(lines))
 		if err != nil {
 			si.err = err
 			return false
@@ -1586,6 +1681,10 @@ func (si *bufferedIterator) moveNext() (int64, []byte, labels.Labels, bool) {
 		return ts, si.buf[:lineSize], labels.EmptyLabels(), true
 	}
 
+	if si.format >= ChunkFormatV5 {
+		return si.moveNextColumns(ts, lineSize, decompressedBytes)
+	}
+
 	lastAttempt = 0
 	var symbolsSectionLengthWidth, nSymbolsWidth, nSymbols int
 	for nSymbolsWidth == 0 { // Read until we have enough bytes for the labels.
@@ -1701,6 +1800,35 @@ func (si *bufferedIterator) moveNext() (int64, []byte, labels.Labels, bool) {
 	return ts, si.buf[:lineSize], lbls, true
 }
 
+// moveNextColumns reads the structured metadata of the current entry from the columns of chunk format v5+ blocks.
+func (si *bufferedIterator) moveNextColumns(ts int64, lineSize int, decompressedBytes int64) (int64, []byte, labels.Labels, bool) {
+	if si.symbolsBuf == nil {
+		si.symbolsBuf = SymbolsPool.Get(len(si.columns)).([]symbol)
+	}
+
+	var (
+		read int
+		err  error
+	)
+	si.symbolsBuf, read, err = nextSymbols(si.columns, si.symbolsBuf[:0])
+	if err != nil {
+		si.err = err
+		return 0, nil, labels.EmptyLabels(), false
+	}
+
+	decompressedStructuredMetadataBytes := int64(read)
+	si.stats.AddDecompressedLines(1)
+	si.stats.AddDecompressedStructuredMetadataBytes(decompressedStructuredMetadataBytes)
+	si.stats.AddDecompressedBytes(decompressedBytes + decompressedStructuredMetadataBytes)
+
+	lbls, err := si.symbolizer.Lookup(si.symbolsBuf, nil)
+	if err != nil {
+		si.err = fmt.Errorf("symbolizer lookup: %w", err)
+		return 0, nil, labels.EmptyLabels(), false
+	}
+	return ts, si.buf[:lineSize], lbls, true
+}
+
 func (si *bufferedIterator) Err() error { return si.err }
 
 func (si *bufferedIterator) Close() error {
@@ -1726,6 +1854,7 @@ func (si *bufferedIterator) close() {
 		SymbolsPool.Put(si.symbolsBuf)
 		si.symbolsBuf = nil
 	}
+	si.columns = nil
 
 	if !si.currStructuredMetadata.IsEmpty() {
 		si.currStructuredMetadata = labels.EmptyLabels()
@@ -45,6 +45,7 @@ var testEncodings = []compression.Codec{
 	compression.Snappy,
 	compression.Flate,
 	compression.Zstd,
+	compression.ZstdDict,
 }
 
 var (
@@ -79,6 +80,10 @@ var (
 			headBlockFmt: UnorderedWithStructuredMetadataHeadBlockFmt,
 			chunkFormat:  ChunkFormatV4,
 		},
+		{
+			headBlockFmt: UnorderedWithStructuredMetadataHeadBlockFmt,
+			chunkFormat:  ChunkFormatV5,
+		},
 	}
 )
 
@@ -2303,6 +2308,9 @@ func TestDecodeChunkIncorrectBlockOffset(t *testing.T) {
 							eb.putUvarint(b.uncompressedSize)
 						}
 						eb.putUvarint(len(b.b))
+						if chk.format >= ChunkFormatV5 {
+							require.NoError(t, b.stats.encode(eb))
+						}
 					}
 					metasLen := len(eb.get())
 					eb.putHash(crc32Hash)
@@ -2468,3 +2476,45 @@ func BenchmarkChunkRewrite(b *testing.B) {
 		})
 	}
 }
+
+func TestMemChunk_ZstdDictionary(t *testing.T) {
+	samples := make([][]byte, 0, 500)
+	for i := 0; i < 500; i++ {
+		samples = append(samples, []byte(fmt.Sprintf(`level=info caller=handler.go:%d msg="request served" status=200 path=/api/v1/items/%d`, i%17, i)))
+	}
+	dict, err := compression.TrainDictionary(compression.MinDictionaryID+100, samples, 8<<10)
+	require.NoError(t, err)
+
+	chk := NewMemChunk(ChunkFormatV4, compression.ZstdDict, UnorderedWithStructuredMetadataHeadBlockFmt, testBlockSize, testTargetSize)
+	chk.SetDictionary(dict)
+	for i := 0; i < 100; i++ {
+		_, err := chk.Append(logprotoEntry(int64(i), fmt.Sprintf(`level=info caller=handler.go:%d msg="request served" status=200 path=/api/v1/items/%d`, i%17, 1000+i)))
+		require.NoError(t, err)
+	}
+	require.NoError(t, chk.Close())
+	b, err := chk.Bytes()
+	require.NoError(t, err)
+
+	decoded, err := NewByteChunk(b, testBlockSize, testTargetSize)
+	require.NoError(t, err)
+	noopStreamPipeline := log.NewNoopPipeline().ForStream(labels.Labels{})
+
+	// Blocks cannot be read until the dictionary is registered.
+	it, err := decoded.Iterator(context.Background(), time.Unix(0, 0), time.Unix(0, 100), logproto.FORWARD, noopStreamPipeline)
+	require.NoError(t, err)
+	require.False(t, it.Next())
+	require.Error(t, it.Err())
+	require.NoError(t, it.Close())
+
+	require.NoError(t, compression.RegisterDictionary(dict))
+	it, err = decoded.Iterator(context.Background(), time.Unix(0, 0), time.Unix(0, 100), logproto.FORWARD, noopStreamPipeline)
+	require.NoError(t, err)
+	var count int
+	for it.Next() {
+		require.Equal(t, fmt.Sprintf(`level=info caller=handler.go:%d msg="request served" status=200 path=/api/v1/items/%d`, count%17, 1000+count), it.At().Line)
+		count++
+	}
+	require.NoError(t, it.Err())
+	require.NoError(t, it.Close())
+	require.Equal(t, 100, count)
+}
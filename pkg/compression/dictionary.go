@@ -0,0 +1,310 @@
+package compression
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	zstdlib "github.com/klauspost/compress/zstd"
+)
+
+const (
+	// MinDictionaryID is the lowest dictionary ID that may be used for trained
+	// dictionaries. Lower IDs are reserved by the zstd format.
+	MinDictionaryID = 1 << 15
+	// MaxDictionaryID is the highest dictionary ID that may be used for trained
+	// dictionaries. Higher IDs are reserved by the zstd format.
+	MaxDictionaryID = 1<<31 - 1
+
+	minDictionarySize = 256
+)
+
+// Dictionary is a zstd dictionary. Data compressed with a dictionary carries
+// its ID in the frame header, which is used to look up the dictionary again
+// when decompressing.
+//
+// A Dictionary is also a WriterPool whose writers compress with the dictionary.
+type Dictionary struct {
+	ID uint32
+
+	raw     []byte
+	writers sync.Pool
+	readers sync.Pool
+	decoder func() (*zstdlib.Decoder, error)
+}
+
+// NewDictionary parses a serialized zstd dictionary.
+func NewDictionary(raw []byte) (*Dictionary, error) {
+	header, err := zstdlib.InspectDictionary(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid zstd dictionary: %w", err)
+	}
+	if header.ID() == 0 {
+		return nil, errors.New("invalid zstd dictionary: missing dictionary ID")
+	}
+	d := &Dictionary{ID: header.ID(), raw: raw}
+	d.decoder = sync.OnceValues(func() (*zstdlib.Decoder, error) {
+		// Using a concurrency of 0 will use GOMAXPROCS workers.
+		decoder, err := zstdlib.NewReader(nil, zstdlib.WithDecoderConcurrency(0), zstdlib.WithDecoderDicts(raw))
+		if err != nil {
+			return nil, err
+		}
+		runtime.SetFinalizer(decoder, (*zstdlib.Decoder).Close)
+		return decoder, nil
+	})
+	return d, nil
+}
+
+// TrainDictionary builds a dictionary with the given ID out of samples. The
+// dictionary content is made up of the most recent samples up to size bytes.
+func TrainDictionary(id uint32, samples [][]byte, size int) (*Dictionary, error) {
+	if id < MinDictionaryID || id > MaxDictionaryID {
+		return nil, fmt.Errorf("dictionary ID %d out of range [%d, %d]", id, MinDictionaryID, MaxDictionaryID)
+	}
+	if len(samples) == 0 {
+		return nil, errors.New("no samples provided")
+	}
+
+	// zstd favours the end of the history, so the samples are appended from
+	// the oldest to the newest one that fit in the dictionary.
+	var total, first int
+	for first = len(samples); first > 0; first-- {
+		if total+len(samples[first-1]) > size {
+			break
+		}
+		total += len(samples[first-1])
+	}
+	history := bytes.Join(samples[first:], nil)
+	if len(history) < minDictionarySize {
+		return nil, fmt.Errorf("not enough sample data to train a dictionary: %d bytes", len(history))
+	}
+
+	raw, err := zstdlib.BuildDict(zstdlib.BuildDictOptions{
+		ID:       id,
+		Contents: samples,
+		History:  history,
+		Offsets:  [3]int{1, 4, 8},
+		Level:    zstdlib.SpeedDefault,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("building zstd dictionary: %w", err)
+	}
+	return NewDictionary(raw)
+}
+
+// Bytes returns the serialized dictionary.
+func (d *Dictionary) Bytes() []byte {
+	return d.raw
+}
+
+// GetWriter gets or creates a new CompressionWriter using the dictionary and
+// reset it to write to dst.
+func (d *Dictionary) GetWriter(dst io.Writer) io.WriteCloser {
+	if w := d.writers.Get(); w != nil {
+		writer := w.(*zstdlib.Encoder)
+		writer.Reset(dst)
+		return writer
+	}
+
+	w, err := zstdlib.NewWriter(dst, zstdlib.WithEncoderDict(d.raw))
+	if err != nil {
+		panic(err) // never happens, the dictionary is validated when created.
+	}
+	return w
+}
+
+// PutWriter places back in the pool a CompressionWriter
+func (d *Dictionary) PutWriter(writer io.WriteCloser) {
+	d.writers.Put(writer)
+}
+
+// Decoder returns a decoder using the dictionary for DecodeAll, which is safe
+// for concurrent use.
+func (d *Dictionary) Decoder() (*zstdlib.Decoder, error) {
+	return d.decoder()
+}
+
+// getReader gets or creates a streaming decoder using the dictionary and reset
+// it to read from src.
+func (d *Dictionary) getReader(src io.Reader) (io.Reader, error) {
+	if r := d.readers.Get(); r != nil {
+		reader := r.(*zstdlib.Decoder)
+		if err := reader.Reset(src); err != nil {
+			return nil, err
+		}
+		return reader, nil
+	}
+	reader, err := zstdlib.NewReader(src, zstdlib.WithDecoderDicts(d.raw))
+	if err != nil {
+		return nil, err
+	}
+	runtime.SetFinalizer(reader, (*zstdlib.Decoder).Close)
+	return reader, nil
+}
+
+func (d *Dictionary) putReader(reader io.Reader) {
+	d.readers.Put(reader)
+}
+
+// maxCachedDictionaries is the number of dictionaries kept in memory to
+// decompress data. Evicted dictionaries are loaded again when data compressed
+// with them is read.
+const maxCachedDictionaries = 256
+
+// DictionaryLoader loads the dictionary with the given ID, for example from
+// object storage.
+type DictionaryLoader func(id uint32) (*Dictionary, error)
+
+// dictionaries caches the dictionaries used by the process. Readers of the
+// ZstdDict codec look up the dictionary of each frame by the ID in its header
+// and load it with the registered loader when it isn't cached.
+var dictionaries = newDictionaryRegistry(maxCachedDictionaries)
+
+type dictionaryRegistry struct {
+	cache *lru.Cache[uint32, *Dictionary]
+
+	mtx    sync.RWMutex
+	loader DictionaryLoader
+}
+
+func newDictionaryRegistry(size int) *dictionaryRegistry {
+	cache, err := lru.New[uint32, *Dictionary](size)
+	if err != nil {
+		panic(err) // never happens, the size is positive.
+	}
+	return &dictionaryRegistry{cache: cache}
+}
+
+// SetDictionaryLoader sets the loader of the dictionaries that aren't cached.
+func SetDictionaryLoader(loader DictionaryLoader) {
+	dictionaries.mtx.Lock()
+	defer dictionaries.mtx.Unlock()
+	dictionaries.loader = loader
+}
+
+// RegisterDictionary caches a dictionary for decompression. Registering the
+// same dictionary twice is a no-op, registering a different dictionary with an
+// ID that is cached is an error.
+func RegisterDictionary(d *Dictionary) error {
+	existing, ok, _ := dictionaries.cache.PeekOrAdd(d.ID, d)
+	if ok && !bytes.Equal(existing.raw, d.raw) {
+		return fmt.Errorf("a different dictionary with ID %d is already registered", d.ID)
+	}
+	return nil
+}
+
+// LookupDictionary returns the cached dictionary with the given ID.
+func LookupDictionary(id uint32) (*Dictionary, bool) {
+	return dictionaries.cache.Get(id)
+}
+
+// LoadDictionary returns the dictionary with the given ID from the cache, or
+// loads it with the registered loader.
+func LoadDictionary(id uint32) (*Dictionary, error) {
+	if d, ok := dictionaries.cache.Get(id); ok {
+		return d, nil
+	}
+
+	dictionaries.mtx.RLock()
+	loader := dictionaries.loader
+	dictionaries.mtx.RUnlock()
+	if loader == nil {
+		return nil, fmt.Errorf("unknown zstd dictionary %d", id)
+	}
+
+	d, err := loader(id)
+	if err != nil {
+		return nil, fmt.Errorf("loading zstd dictionary %d: %w", id, err)
+	}
+	if d.ID != id {
+		return nil, fmt.Errorf("loading zstd dictionary %d: got dictionary %d", id, d.ID)
+	}
+	// A dictionary loaded concurrently is kept, so that its decoders are shared.
+	if existing, ok, _ := dictionaries.cache.PeekOrAdd(id, d); ok {
+		return existing, nil
+	}
+	return d, nil
+}
+
+// FrameDictionaryID returns the ID of the dictionary a zstd frame was
+// compressed with, or 0 if it was compressed without a dictionary. frame
+// should hold at least the first zstd.HeaderMaxSize bytes of the frame.
+func FrameDictionaryID(frame []byte) uint32 {
+	var header zstdlib.Header
+	if err := header.Decode(frame); err != nil {
+		// Invalid frames are reported by the decoder.
+		return 0
+	}
+	return header.DictionaryID
+}
+
+// ZstdDictPool is a zstd compression pool whose readers decompress frames
+// with the dictionary referenced in their header. Its writers do not use a
+// dictionary, use the Dictionary itself as a WriterPool to compress with one.
+type ZstdDictPool struct {
+	ZstdPool
+	readers sync.Pool
+}
+
+type zstdDictReader struct {
+	src     *bufio.Reader
+	decoder io.Reader
+	// dict is the dictionary the decoder belongs to, nil for a decoder of the
+	// ZstdPool.
+	dict *Dictionary
+}
+
+func (r *zstdDictReader) Read(p []byte) (int, error) {
+	return r.decoder.Read(p)
+}
+
+// GetReader gets or creates a new CompressionReader and reset it to read from src
+func (pool *ZstdDictPool) GetReader(src io.Reader) (io.Reader, error) {
+	reader, _ := pool.readers.Get().(*zstdDictReader)
+	if reader == nil {
+		reader = &zstdDictReader{src: bufio.NewReader(src)}
+	} else {
+		reader.src.Reset(src)
+	}
+
+	// A short or invalid header is reported by the decoder.
+	header, _ := reader.src.Peek(zstdlib.HeaderMaxSize)
+	id := FrameDictionaryID(header)
+	if id == 0 {
+		decoder, err := pool.ZstdPool.GetReader(reader.src)
+		if err != nil {
+			return nil, err
+		}
+		reader.decoder, reader.dict = decoder, nil
+		return reader, nil
+	}
+
+	d, err := LoadDictionary(id)
+	if err != nil {
+		return nil, err
+	}
+	decoder, err := d.getReader(reader.src)
+	if err != nil {
+		return nil, err
+	}
+	reader.decoder, reader.dict = decoder, d
+	return reader, nil
+}
+
+// PutReader places back in the pool a CompressionReader
+func (pool *ZstdDictPool) PutReader(reader io.Reader) {
+	r := reader.(*zstdDictReader)
+	if r.dict != nil {
+		r.dict.putReader(r.decoder)
+	} else {
+		pool.ZstdPool.PutReader(r.decoder)
+	}
+	r.decoder, r.dict = nil, nil
+	r.src.Reset(nil)
+	pool.readers.Put(r)
+}
@@ -0,0 +1,127 @@
+package compression
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func dictionarySamples(n int) [][]byte {
+	samples := make([][]byte, 0, n)
+	for i := 0; i < n; i++ {
+		samples = append(samples, []byte(fmt.Sprintf(`level=info ts=2025-01-01T00:00:%02dZ caller=handler.go:%d msg="request served" method=GET status=200 path=/api/v1/items/%d duration=%dms`, i%60, i%17, i, i%300)))
+	}
+	return samples
+}
+
+func compressWith(t *testing.T, pool WriterPool, data []byte) []byte {
+	var buf bytes.Buffer
+	w := pool.GetWriter(&buf)
+	defer pool.PutWriter(w)
+	_, err := w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestTrainDictionary(t *testing.T) {
+	samples := dictionarySamples(500)
+
+	_, err := TrainDictionary(1, samples, 4<<10)
+	require.Error(t, err, "reserved IDs must be rejected")
+	_, err = TrainDictionary(MinDictionaryID, nil, 4<<10)
+	require.Error(t, err)
+
+	d, err := TrainDictionary(MinDictionaryID+1, samples, 4<<10)
+	require.NoError(t, err)
+	require.Equal(t, uint32(MinDictionaryID+1), d.ID)
+
+	parsed, err := NewDictionary(d.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, d.ID, parsed.ID)
+}
+
+func TestZstdDictPool(t *testing.T) {
+	samples := dictionarySamples(500)
+	d, err := TrainDictionary(MinDictionaryID+2, samples, 4<<10)
+	require.NoError(t, err)
+
+	line := []byte(`level=info ts=2025-01-02T00:00:00Z caller=handler.go:3 msg="request served" method=GET status=200 path=/api/v1/items/9999 duration=12ms`)
+	withDict := compressWith(t, d, line)
+	withoutDict := compressWith(t, GetWriterPool(Zstd), line)
+	require.Less(t, len(withDict), len(withoutDict))
+
+	pool := GetReaderPool(ZstdDict)
+
+	// The dictionary is unknown to readers until it is registered.
+	_, err = pool.GetReader(bytes.NewReader(withDict))
+	require.Error(t, err)
+
+	require.NoError(t, RegisterDictionary(d))
+	require.NoError(t, RegisterDictionary(d))
+	other, err := TrainDictionary(MinDictionaryID+2, samples[:100], 2<<10)
+	require.NoError(t, err)
+	require.Error(t, RegisterDictionary(other))
+
+	looked, ok := LookupDictionary(d.ID)
+	require.True(t, ok)
+	require.Same(t, d, looked)
+
+	for _, data := range [][]byte{withDict, withoutDict, compressWith(t, GetWriterPool(ZstdDict), line)} {
+		r, err := pool.GetReader(bytes.NewReader(data))
+		require.NoError(t, err)
+		actual, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, line, actual)
+		pool.PutReader(r)
+	}
+}
+
+func TestLoadDictionary(t *testing.T) {
+	samples := dictionarySamples(500)
+	d, err := TrainDictionary(MinDictionaryID+3, samples, 4<<10)
+	require.NoError(t, err)
+	raw := d.Bytes()
+
+	loads := 0
+	SetDictionaryLoader(func(id uint32) (*Dictionary, error) {
+		loads++
+		if id != d.ID {
+			return nil, fmt.Errorf("unknown dictionary %d", id)
+		}
+		return NewDictionary(raw)
+	})
+	t.Cleanup(func() { SetDictionaryLoader(nil) })
+
+	_, err = LoadDictionary(MinDictionaryID + 4)
+	require.Error(t, err)
+
+	line := []byte(`level=info ts=2025-01-02T00:00:00Z caller=handler.go:3 msg="request served" method=GET status=200 path=/api/v1/items/9999 duration=12ms`)
+	data := compressWith(t, d, line)
+	require.Equal(t, d.ID, FrameDictionaryID(data))
+	require.Zero(t, FrameDictionaryID(compressWith(t, GetWriterPool(Zstd), line)))
+
+	// Dictionaries are loaded once, when data compressed with them is read.
+	pool := GetReaderPool(ZstdDict)
+	for i := 0; i < 2; i++ {
+		r, err := pool.GetReader(bytes.NewReader(data))
+		require.NoError(t, err)
+		actual, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, line, actual)
+		pool.PutReader(r)
+	}
+	require.Equal(t, 2, loads)
+
+	loaded, err := LoadDictionary(d.ID)
+	require.NoError(t, err)
+	decoder, err := loaded.Decoder()
+	require.NoError(t, err)
+	actual, err := decoder.DecodeAll(data, nil)
+	require.NoError(t, err)
+	require.Equal(t, line, actual)
+	require.Equal(t, 2, loads)
+}
@@ -23,7 +23,7 @@ func ToFileExtension(e Codec) string {
 		return ExtSnappy
 	case Flate:
 		return ExtFlate
-	case Zstd:
+	case Zstd, ZstdDict:
 		return ExtZstd
 	default:
 		panic(fmt.Sprintf("invalid codec: %d, supported: %s", e, SupportedCodecs()))
@@ -45,6 +45,8 @@ var (
 	flate = FlatePool{}
 	// zstd is the zstd compression pool
 	zstd = ZstdPool{}
+	// zstdDict is the zstd compression pool aware of registered dictionaries
+	zstdDict = ZstdDictPool{}
 	// snappy is the snappy compression pool
 	snappy = SnappyPool{}
 	// noop is the no compression pool
@@ -79,6 +81,8 @@ func GetPool(enc Codec) ReaderWriterPool {
 		return &flate
 	case Zstd:
 		return &zstd
+	case ZstdDict:
+		return &zstdDict
 	default:
 		panic("unknown encoding")
 	}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/grafana/loki/pkg/push"
 
+	"github.com/grafana/loki/v3/pkg/compression"
 	"github.com/grafana/loki/v3/pkg/dataobj"
 	"github.com/grafana/loki/v3/pkg/dataobj/metastore/multitenancy"
 	"github.com/grafana/loki/v3/pkg/dataobj/sections/logs"
@@ -78,6 +79,10 @@ type BuilderConfig struct {
 	// DataobjSortOrder defines the order in which the rows of the logs sections are sorted.
 	// They can either be sorted by [streamID ASC, timestamp DESC] or [timestamp DESC, streamID ASC].
 	DataobjSortOrder string `yaml:"dataobj_sort_order" doc:"hidden"`
+
+	// ZstdDictionary optionally returns the zstd dictionary to compress the
+	// logs sections of a tenant with.
+	ZstdDictionary func(tenant string) *compression.Dictionary `yaml:"-"`
 }
 
 // RegisterFlagsWithPrefix registers flags with the given prefix.
@@ -213,12 +218,20 @@ func (b *Builder) initBuilder(tenant string) {
 			BufferSize:       int(b.cfg.BufferSize),
 			StripeMergeLimit: b.cfg.SectionStripeMergeLimit,
 			SortOrder:        parseSortOrder(b.cfg.DataobjSortOrder),
+			ZstdDictionary:   b.zstdDictionary(tenant),
 		})
 		lb.SetTenant(tenant)
 		b.logs[tenant] = lb
 	}
 }
 
+func (b *Builder) zstdDictionary(tenant string) *compression.Dictionary {
+	if b.cfg.ZstdDictionary == nil {
+		return nil
+	}
+	return b.cfg.ZstdDictionary(tenant)
+}
+
 func (b *Builder) GetEstimatedSize() int {
 	return b.currentSizeEstimate
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/golang/snappy"
 	"github.com/klauspost/compress/zstd"
 
+	"github.com/grafana/loki/v3/pkg/compression"
 	"github.com/grafana/loki/v3/pkg/dataobj/internal/metadata/datasetmd"
 	"github.com/grafana/loki/v3/pkg/dataobj/internal/util/bufpool"
 )
@@ -111,7 +112,7 @@ func (p *MemPage) reader(compression datasetmd.CompressionType) (presence io.Rea
 		}}, nil
 
 	case datasetmd.COMPRESSION_TYPE_ZSTD:
-		zr, err := getZstdDecoder()
+		zr, err := getZstdDecoder(compressedValuesData)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -158,9 +159,9 @@ type closerFunc struct {
 
 func (c *closerFunc) Close() error { return c.onClose() }
 
-// getZstdDecoder lazily initializes a global Zstd decoder. It is only safe to
+// zstdDecoder lazily initializes a global Zstd decoder. It is only safe to
 // use DecodeAll concurrently.
-var getZstdDecoder = sync.OnceValues(func() (*zstd.Decoder, error) {
+var zstdDecoder = sync.OnceValues(func() (*zstd.Decoder, error) {
 	// NOTE(rfratto): We used to use pooled decoders here with streaming decodes
 	// (Decode rather than DecodeAll), but using pooled decoders made it
 	// difficult to control total allocations.
@@ -168,3 +169,18 @@ var getZstdDecoder = sync.OnceValues(func() (*zstd.Decoder, error) {
 	// Using a concurrency of 0 will use GOMAXPROCS workers.
 	return zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
 })
+
+// getZstdDecoder returns the decoder for the zstd frame in src: the global
+// decoder for frames compressed without a dictionary, or the decoder of the
+// dictionary referenced by ID in the frame header.
+func getZstdDecoder(src []byte) (*zstd.Decoder, error) {
+	id := compression.FrameDictionaryID(src)
+	if id == 0 {
+		return zstdDecoder()
+	}
+	dict, err := compression.LoadDictionary(id)
+	if err != nil {
+		return nil, err
+	}
+	return dict.Decoder()
+}
@@ -4,6 +4,7 @@ import (
 	"compress/gzip"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"math/rand"
 	"os"
@@ -12,8 +13,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/loki/v3/pkg/compression"
 	"github.com/grafana/loki/v3/pkg/dataobj/internal/metadata/datasetmd"
 )
 
@@ -185,6 +188,41 @@ func Test_pageBuilder_WriteRead(t *testing.T) {
 	require.Equal(t, in, actual)
 }
 
+func Test_pageBuilder_ZstdDictionary(t *testing.T) {
+	var samples [][]byte
+	for i := 0; i < 500; i++ {
+		samples = append(samples, []byte(fmt.Sprintf(`level=info caller=handler.go:%d msg="request served" status=200 path=/api/v1/items/%d`, i%17, i)))
+	}
+	dict, err := compression.TrainDictionary(compression.MinDictionaryID+200, samples, 8<<10)
+	require.NoError(t, err)
+
+	opts := BuilderOptions{
+		PageSizeHint: 1 << 20,
+		Type:         ColumnType{Physical: datasetmd.PHYSICAL_TYPE_BINARY, Logical: "data"},
+		Compression:  datasetmd.COMPRESSION_TYPE_ZSTD,
+		Encoding:     datasetmd.ENCODING_TYPE_PLAIN,
+		CompressionOptions: CompressionOptions{
+			Zstd: []zstd.EOption{zstd.WithEncoderDict(dict.Bytes())},
+		},
+	}
+	b, err := newPageBuilder(opts)
+	require.NoError(t, err)
+	for i := 0; i < 100; i++ {
+		require.True(t, b.Append(BinaryValue([]byte(fmt.Sprintf(`level=info caller=handler.go:%d msg="request served" status=200 path=/api/v1/items/%d`, i%17, 1000+i)))))
+	}
+	page, err := b.Flush()
+	require.NoError(t, err)
+
+	// The page can't be decoded until the dictionary is registered.
+	_, _, err = page.reader(opts.Compression)
+	require.Error(t, err)
+
+	require.NoError(t, compression.RegisterDictionary(dict))
+	_, values, err := page.reader(opts.Compression)
+	require.NoError(t, err)
+	require.NoError(t, values.Close())
+}
+
 func Test_pageBuilder_Fill(t *testing.T) {
 	opts := BuilderOptions{
 		PageSizeHint: 1_500_000,
@@ -0,0 +1,48 @@
+package multitenancy
+
+import (
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/v3/pkg/dataobj"
+)
+
+// TenantLabel is the label holding the tenant of the streams returned by
+// queries across several tenants.
+const TenantLabel = "__tenant_id__"
+
+// FilterTenants returns the tenants matching the matchers on [TenantLabel]
+// and the other matchers.
+func FilterTenants(tenants []string, matchers []*labels.Matcher) ([]string, []*labels.Matcher) {
+	var (
+		tenantMatchers []*labels.Matcher
+		otherMatchers  = make([]*labels.Matcher, 0, len(matchers))
+	)
+	for _, m := range matchers {
+		if m.Name == TenantLabel {
+			tenantMatchers = append(tenantMatchers, m)
+			continue
+		}
+		otherMatchers = append(otherMatchers, m)
+	}
+
+	matched := make([]string, 0, len(tenants))
+outer:
+	for _, tenant := range tenants {
+		for _, m := range tenantMatchers {
+			if !m.Matches(tenant) {
+				continue outer
+			}
+		}
+		matched = append(matched, tenant)
+	}
+	return matched, otherMatchers
+}
+
+// CheckTenantSection returns a predicate matching the sections of tenant
+// which pass check, to read the sections of a tenant from objects shared by
+// several tenants.
+func CheckTenantSection(tenant string, check func(*dataobj.Section) bool) func(*dataobj.Section) bool {
+	return func(sec *dataobj.Section) bool {
+		return sec.Tenant == tenant && check(sec)
+	}
+}
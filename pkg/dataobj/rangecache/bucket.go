@@ -0,0 +1,192 @@
+// Package rangecache caches byte ranges and attributes of data objects read
+// from object storage.
+package rangecache
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
+	"github.com/grafana/loki/v3/pkg/storage/chunk/cache"
+	"github.com/grafana/loki/v3/pkg/util/constants"
+)
+
+// Config configures the caches for data object reads. The disk cache is
+// queried first, the shared cache, usually memcached, second.
+type Config struct {
+	Disk  cache.DiskCacheConfig `yaml:"disk_cache" doc:"description=Configures a cache on local disk, for example an SSD, for data object reads."`
+	Cache cache.Config          `yaml:"cache" doc:"description=Configures a shared cache, for example memcached, for data object reads. It is queried after the disk cache."`
+}
+
+// RegisterFlagsWithPrefix registers flags for the config with the given
+// prefix.
+func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	cfg.Disk.RegisterFlagsWithPrefix(prefix+"disk.", "Data object disk cache: ", f)
+	cfg.Cache.RegisterFlagsWithPrefix(prefix, "Data object cache: ", f)
+}
+
+// Validate checks the config for errors.
+func (cfg *Config) Validate() error {
+	return cfg.Disk.Validate()
+}
+
+// IsEnabled returns true if at least one cache tier is configured.
+func (cfg *Config) IsEnabled() bool {
+	return cfg.Disk.Enabled || cache.IsCacheConfigured(cfg.Cache)
+}
+
+// NewBucket wraps bucket with caches for the byte ranges and attributes of
+// objects, as read by [dataobj.FromBucket] for the file metadata, section
+// metadata and pages of data objects. Data objects are immutable, so cache
+// entries never need to be invalidated.
+//
+// Cache entries are keyed by the object path, and the offset and length of
+// the range. bucket is returned as-is if no cache is configured.
+func NewBucket(cfg Config, bucket objstore.Bucket, reg prometheus.Registerer, logger log.Logger) (objstore.Bucket, error) {
+	if !cfg.IsEnabled() {
+		return bucket, nil
+	}
+
+	var caches []cache.Cache
+	if cfg.Disk.Enabled {
+		const name = "dataobj-disk-cache"
+		disk, err := cache.NewDiskCache(name, cfg.Disk, reg, logger, stats.DataObjCache)
+		if err != nil {
+			return nil, fmt.Errorf("creating disk cache: %w", err)
+		}
+		caches = append(caches, cache.Instrument(name, disk, reg))
+	}
+	if cache.IsCacheConfigured(cfg.Cache) {
+		c, err := cache.New(cfg.Cache, reg, logger, stats.DataObjCache, constants.Loki)
+		if err != nil {
+			return nil, fmt.Errorf("creating cache: %w", err)
+		}
+		caches = append(caches, c)
+	}
+
+	return &cachingBucket{
+		Bucket: bucket,
+		cache:  cache.NewTiered(caches),
+		logger: logger,
+	}, nil
+}
+
+type cachingBucket struct {
+	objstore.Bucket
+
+	cache  cache.Cache
+	logger log.Logger
+}
+
+// GetRange returns the range of the object from the cache, or reads it from
+// the bucket and stores it in the cache.
+func (b *cachingBucket) GetRange(ctx context.Context, name string, off, length int64) (io.ReadCloser, error) {
+	// Ranges until the end of the object are not cached, as the key would not
+	// determine the length of the value.
+	if off < 0 || length <= 0 {
+		return b.Bucket.GetRange(ctx, name, off, length)
+	}
+
+	key := rangeKey(name, off, length)
+	if value, ok := b.fetch(ctx, key); ok && int64(len(value)) == length {
+		return io.NopCloser(bytes.NewReader(value)), nil
+	}
+
+	rc, err := b.Bucket.GetRange(ctx, name, off, length)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	value, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+	// Ranges exceeding the end of the object return less data. They are not
+	// stored to be able to detect truncated entries on fetch.
+	if int64(len(value)) == length {
+		b.store(ctx, key, value)
+	}
+	return io.NopCloser(bytes.NewReader(value)), nil
+}
+
+// Attributes returns the attributes of the object from the cache, or reads
+// them from the bucket and stores them in the cache.
+func (b *cachingBucket) Attributes(ctx context.Context, name string) (objstore.ObjectAttributes, error) {
+	key := attributesKey(name)
+	if value, ok := b.fetch(ctx, key); ok {
+		if attrs, err := decodeAttributes(value); err == nil {
+			return attrs, nil
+		}
+	}
+
+	attrs, err := b.Bucket.Attributes(ctx, name)
+	if err != nil {
+		return attrs, err
+	}
+	b.store(ctx, key, encodeAttributes(attrs))
+	return attrs, nil
+}
+
+// Close stops the caches and closes the underlying bucket.
+func (b *cachingBucket) Close() error {
+	b.cache.Stop()
+	return b.Bucket.Close()
+}
+
+func (b *cachingBucket) fetch(ctx context.Context, key string) ([]byte, bool) {
+	found, bufs, _, err := b.cache.Fetch(ctx, []string{key})
+	if err != nil {
+		level.Warn(b.logger).Log("msg", "failed to fetch from data object cache", "err", err)
+		return nil, false
+	}
+	if len(found) != 1 {
+		return nil, false
+	}
+	return bufs[0], true
+}
+
+func (b *cachingBucket) store(ctx context.Context, key string, value []byte) {
+	if err := b.cache.Store(ctx, []string{key}, [][]byte{value}); err != nil {
+		level.Warn(b.logger).Log("msg", "failed to store in data object cache", "err", err)
+	}
+}
+
+// rangeKey returns the cache key of a range of an object. Keys are hashed to
+// be valid memcached keys regardless of the length of the object path.
+func rangeKey(name string, off, length int64) string {
+	return cache.HashKey(fmt.Sprintf("dataobj:range:%s:%d:%d", name, off, length))
+}
+
+func attributesKey(name string) string {
+	return cache.HashKey("dataobj:attributes:" + name)
+}
+
+const encodedAttributesSize = 16
+
+func encodeAttributes(attrs objstore.ObjectAttributes) []byte {
+	buf := make([]byte, encodedAttributesSize)
+	binary.LittleEndian.PutUint64(buf[0:], uint64(attrs.Size))
+	binary.LittleEndian.PutUint64(buf[8:], uint64(attrs.LastModified.UnixNano()))
+	return buf
+}
+
+func decodeAttributes(buf []byte) (objstore.ObjectAttributes, error) {
+	if len(buf) != encodedAttributesSize {
+		return objstore.ObjectAttributes{}, fmt.Errorf("invalid attributes cache entry of %d bytes", len(buf))
+	}
+	return objstore.ObjectAttributes{
+		Size:         int64(binary.LittleEndian.Uint64(buf[0:])),
+		LastModified: time.Unix(0, int64(binary.LittleEndian.Uint64(buf[8:]))).UTC(),
+	}, nil
+}
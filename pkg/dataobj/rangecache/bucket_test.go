@@ -0,0 +1,85 @@
+package rangecache
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/loki/v3/pkg/storage/chunk/cache"
+)
+
+type countingBucket struct {
+	objstore.Bucket
+	getRangeCalls, attributesCalls int
+}
+
+func (b *countingBucket) GetRange(ctx context.Context, name string, off, length int64) (io.ReadCloser, error) {
+	b.getRangeCalls++
+	return b.Bucket.GetRange(ctx, name, off, length)
+}
+
+func (b *countingBucket) Attributes(ctx context.Context, name string) (objstore.ObjectAttributes, error) {
+	b.attributesCalls++
+	return b.Bucket.Attributes(ctx, name)
+}
+
+func TestNewBucket(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingBucket{Bucket: objstore.NewInMemBucket()}
+	require.NoError(t, inner.Upload(ctx, "objects/obj", bytes.NewReader([]byte("0123456789"))))
+
+	cfg := Config{Disk: cache.DiskCacheConfig{Enabled: true, Directory: t.TempDir(), MaxSizeMB: 1}}
+	bucket, err := NewBucket(cfg, inner, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	readRange := func(off, length int64) string {
+		rc, err := bucket.GetRange(ctx, "objects/obj", off, length)
+		require.NoError(t, err)
+		defer rc.Close()
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	require.Equal(t, "234", readRange(2, 3))
+	require.Equal(t, "234", readRange(2, 3))
+	require.Equal(t, 1, inner.getRangeCalls)
+
+	// Ranges are keyed by offset and length.
+	require.Equal(t, "2345", readRange(2, 4))
+	require.Equal(t, "345", readRange(3, 3))
+	require.Equal(t, 3, inner.getRangeCalls)
+
+	// Ranges exceeding the object are not cached.
+	require.Equal(t, "89", readRange(8, 5))
+	require.Equal(t, "89", readRange(8, 5))
+	require.Equal(t, 5, inner.getRangeCalls)
+
+	expect, err := inner.Bucket.Attributes(ctx, "objects/obj")
+	require.NoError(t, err)
+	for range 2 {
+		attrs, err := bucket.Attributes(ctx, "objects/obj")
+		require.NoError(t, err)
+		require.Equal(t, expect.Size, attrs.Size)
+		require.True(t, expect.LastModified.Equal(attrs.LastModified))
+	}
+	require.Equal(t, 1, inner.attributesCalls)
+
+	// Errors are not cached.
+	_, err = bucket.GetRange(ctx, "objects/missing", 0, 1)
+	require.True(t, bucket.IsObjNotFoundErr(err))
+	_, err = bucket.Attributes(ctx, "objects/missing")
+	require.True(t, bucket.IsObjNotFoundErr(err))
+}
+
+func TestNewBucket_Disabled(t *testing.T) {
+	inner := objstore.NewInMemBucket()
+	bucket, err := NewBucket(Config{}, inner, nil, log.NewNopLogger())
+	require.NoError(t, err)
+	require.Same(t, inner, bucket)
+}
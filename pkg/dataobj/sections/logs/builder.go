@@ -10,6 +10,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/prometheus/model/labels"
 
+	"github.com/grafana/loki/v3/pkg/compression"
 	"github.com/grafana/loki/v3/pkg/dataobj"
 	"github.com/grafana/loki/v3/pkg/dataobj/internal/dataset"
 	datasetmd_v2 "github.com/grafana/loki/v3/pkg/dataobj/internal/metadata/datasetmd"
@@ -66,6 +67,12 @@ type BuilderOptions struct {
 	// SortOrder defines the order in which the rows of the logs sections are sorted.
 	// They can either be sorted by [streamID ASC, timestamp DESC] ([SortStreamASC]) or [timestamp DESC, streamID ASC] ([SortTimestampDESC]).
 	SortOrder SortOrder
+
+	// ZstdDictionary is an optional zstd dictionary to compress the pages of
+	// the section with. Readers look the dictionary up by the ID stored in the
+	// compressed pages, so it must be loadable with
+	// [compression.LoadDictionary] when reading.
+	ZstdDictionary *compression.Dictionary
 }
 
 // Builder accumulate a set of [Record]s within a data object.
@@ -187,13 +194,24 @@ func (b *Builder) flushRecords(encLevel zstd.EncoderLevel) {
 	b.recordsSize = 0
 }
 
+// sectionZstdOptions returns the zstd options to compress the pages of the
+// final section with. Stripes are never compressed with a dictionary, as they
+// are only kept in memory.
+func (b *Builder) sectionZstdOptions() []zstd.EOption {
+	opts := []zstd.EOption{zstd.WithEncoderLevel(zstd.SpeedDefault)}
+	if b.opts.ZstdDictionary != nil {
+		opts = append(opts, zstd.WithEncoderDict(b.opts.ZstdDictionary.Bytes()))
+	}
+	return opts
+}
+
 func (b *Builder) flushSection() *table {
 	if len(b.stripes) == 0 {
 		return nil
 	}
 
 	compressionOpts := dataset.CompressionOptions{
-		Zstd: []zstd.EOption{zstd.WithEncoderLevel(zstd.SpeedDefault)},
+		Zstd: b.sectionZstdOptions(),
 	}
 
 	section, err := mergeTablesIncremental(&b.sectionBuffer, b.opts.PageSizeHint, b.opts.PageMaxRowCount, compressionOpts, b.stripes, b.opts.StripeMergeLimit, b.opts.SortOrder)
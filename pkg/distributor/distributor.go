@@ -185,6 +185,9 @@ type Distributor struct {
 	replicationFactor                     prometheus.Gauge
 	streamShardCount                      prometheus.Counter
 	tenantPushSanitizedStructuredMetadata *prometheus.CounterVec
+	ingestPipelines                       *ingestPipelineCache
+	ingestPipelineMetrics                 *ingestPipelineMetrics
+	redactions                            *prometheus.CounterVec
 
 	usageTracker   push.UsageTracker
 	ingesterTasks  chan pushIngesterTask
@@ -328,6 +331,13 @@ func New(
 			Name:      "distributor_push_structured_metadata_sanitized_total",
 			Help:      "The total number of times we've had to sanitize structured metadata (names or values) at ingestion time per tenant.",
 		}, []string{"tenant", "format"}),
+		ingestPipelines:       newIngestPipelineCache(logger),
+		ingestPipelineMetrics: newIngestPipelineMetrics(registerer),
+		redactions: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: constants.Loki,
+			Name:      "distributor_redactions_total",
+			Help:      "The total number of values redacted from log lines and structured metadata at ingestion time per tenant and detector.",
+		}, []string{"tenant", "detector"}),
 		kafkaAppends: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
 			Namespace: constants.Loki,
 			Name:      "distributor_kafka_appends_total",
@@ -424,11 +434,19 @@ func (d *Distributor) running(ctx context.Context) error {
 	for i := 0; i < d.cfg.PushWorkerCount; i++ {
 		go d.pushIngesterWorker(ctx)
 	}
-	select {
-	case <-ctx.Done():
-		return nil
-	case err := <-d.subservicesWatcher.Chan():
-		return errors.Wrap(err, "distributor subservice failed")
+
+	// Ingest pipelines of tenants are pruned once their config is reloaded.
+	pruneTicker := time.NewTicker(ingestPipelinesPruneInterval)
+	defer pruneTicker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-d.subservicesWatcher.Chan():
+			return errors.Wrap(err, "distributor subservice failed")
+		case <-pruneTicker.C:
+			d.ingestPipelines.prune(d.validator.IngestPipelines)
+		}
 	}
 }
 
@@ -505,6 +523,23 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 // Push a set of streams.
 // The returned error is the last one seen.
 func (d *Distributor) PushWithResolver(ctx context.Context, req *logproto.PushRequest, streamResolver *requestScopedStreamResolver, format string) (*logproto.PushResponse, error) {
+	return d.pushWithOutcome(ctx, req, streamResolver, format, &pushOutcome{})
+}
+
+// pushOutcome describes which parts of a push request were not accepted.
+// It is used by protocols that can report partial success back to the client.
+type pushOutcome struct {
+	// rejectedEntries is the number of entries that were not accepted.
+	rejectedEntries int
+	// rejectedByReason holds the number of discarded samples per discard reason.
+	// It matches what was reported to the discarded samples metric for the request.
+	rejectedByReason map[string]int
+	// written is set once the accepted entries have been written successfully.
+	written bool
+}
+
+// pushWithOutcome is PushWithResolver, additionally recording the entries rejected during validation in outcome.
+func (d *Distributor) pushWithOutcome(ctx context.Context, req *logproto.PushRequest, streamResolver *requestScopedStreamResolver, format string, outcome *pushOutcome) (*logproto.PushResponse, error) {
 	tenantID, err := tenant.TenantID(ctx)
 	if err != nil {
 		return nil, err
@@ -527,9 +562,17 @@ func (d *Distributor) PushWithResolver(ctx context.Context, req *logproto.PushRe
 
 	now := time.Now()
 	validationContext := d.validator.getValidationContextForTime(now, tenantID)
+	outcome.rejectedByReason = validationContext.validationMetrics.discardedSamples
 	fieldDetector := newFieldDetector(validationContext)
 	shouldDiscoverLevels := fieldDetector.shouldDiscoverLogLevels()
 	shouldDiscoverGenericFields := fieldDetector.shouldDiscoverGenericFields()
+	ingestPipelines, releaseIngestPipelines := d.ingestPipelines.get(tenantID, validationContext.ingestPipelines)
+	defer releaseIngestPipelines()
+	redactor, err := d.redactorFor(validationContext.redaction)
+	if err != nil {
+		// Fail closed so sensitive data is never written without being redacted.
+		return nil, httpgrpc.Errorf(http.StatusInternalServerError, "invalid redaction config: %s", err)
+	}
 
 	shardStreamsCfg := d.validator.ShardStreams(tenantID)
 	maybeShardByRate := func(stream logproto.Stream, pushSize int) {
@@ -584,6 +627,7 @@ func (d *Distributor) PushWithResolver(ctx context.Context, req *logproto.PushRe
 			if err != nil {
 				d.writeFailuresManager.Log(tenantID, err)
 				validationErrors.Add(err)
+				outcome.rejectedEntries += len(stream.Entries)
 				discardedBytes := util.EntriesTotalSize(stream.Entries)
 				d.validator.reportDiscardedDataWithTracker(ctx, validation.InvalidLabels, validationContext, lbs, retentionHours, policy, discardedBytes, len(stream.Entries), format)
 				continue
@@ -594,6 +638,7 @@ func (d *Distributor) PushWithResolver(ctx context.Context, req *logproto.PushRe
 					err := fmt.Errorf(validation.MissingEnforcedLabelsErrorMsg, strings.Join(lbsMissing, ","), tenantID, stream.Labels)
 					d.writeFailuresManager.Log(tenantID, err)
 					validationErrors.Add(err)
+					outcome.rejectedEntries += len(stream.Entries)
 					discardedBytes := util.EntriesTotalSize(stream.Entries)
 					d.validator.reportDiscardedDataWithTracker(ctx, validation.MissingEnforcedLabels, validationContext, lbs, retentionHours, policy, discardedBytes, len(stream.Entries), format)
 					continue
@@ -604,6 +649,7 @@ func (d *Distributor) PushWithResolver(ctx context.Context, req *logproto.PushRe
 				d.writeFailuresManager.Log(tenantID, err)
 				discardedBytes := util.EntriesTotalSize(stream.Entries)
 				d.validator.reportDiscardedDataWithTracker(ctx, reason, validationContext, lbs, retentionHours, policy, discardedBytes, len(stream.Entries), format)
+				outcome.rejectedEntries += len(stream.Entries)
 
 				// If the status code is 200, return no error.
 				// Note that we still log the error and increment the metrics.
@@ -617,6 +663,19 @@ func (d *Distributor) PushWithResolver(ctx context.Context, req *logproto.PushRe
 				continue
 			}
 
+			// Ingest pipelines run before the entries are validated, so their output is validated like any pushed entry.
+			for _, p := range ingestPipelines {
+				if p.matches(lbs) {
+					p.apply(tenantID, lbs, &stream, d.ingestPipelineMetrics)
+				}
+			}
+			if len(stream.Entries) == 0 {
+				continue
+			}
+			if redactor != nil {
+				d.redactEntries(tenantID, redactor, stream.Entries)
+			}
+
 			n := 0
 			pushSize := 0
 			prevTs := stream.Entries[0].Timestamp
@@ -626,6 +685,7 @@ func (d *Distributor) PushWithResolver(ctx context.Context, req *logproto.PushRe
 				if err := d.validator.ValidateEntry(ctx, validationContext, lbs, entry, retentionHours, policy, format); err != nil {
 					d.writeFailuresManager.Log(tenantID, err)
 					validationErrors.Add(err)
+					outcome.rejectedEntries++
 					continue
 				}
 
@@ -737,6 +797,7 @@ func (d *Distributor) PushWithResolver(ctx context.Context, req *logproto.PushRe
 	if d.cfg.IngestLimitsEnabled {
 		accepted, err := d.ingestLimits.EnforceLimits(ctx, tenantID, streams)
 		if err == nil && !d.cfg.IngestLimitsDryRunEnabled {
+			outcome.rejectedEntries += d.reportIngestLimitsRejections(ctx, validationContext, streams, accepted, streamResolver, format)
 			if len(accepted) == 0 {
 				// All streams were rejected, the request should be failed.
 				return nil, httpgrpc.Error(http.StatusTooManyRequests, "request exceeded limits")
@@ -837,6 +898,7 @@ func (d *Distributor) PushWithResolver(ctx context.Context, req *logproto.PushRe
 	case err := <-tracker.err:
 		return nil, err
 	case <-tracker.done:
+		outcome.written = true
 		return &logproto.PushResponse{}, validationErr
 	case <-ctx.Done():
 		return nil, ctx.Err()
@@ -889,6 +951,7 @@ func (d *Distributor) trackDiscardedData(
 		for retentionHours, stats := range retentionToStats {
 			validation.DiscardedSamples.WithLabelValues(reason, tenantID, retentionHours, policy, format).Add(float64(stats.lineCount))
 			validation.DiscardedBytes.WithLabelValues(reason, tenantID, retentionHours, policy, format).Add(float64(stats.lineSize))
+			validationMetrics.discarded(reason, stats.lineCount)
 		}
 	}
 
@@ -908,6 +971,36 @@ func (d *Distributor) trackDiscardedData(
 	}
 }
 
+// reportIngestLimitsRejections reports the streams that were dropped because
+// they exceeded the ingest limits and returns the number of dropped entries.
+func (d *Distributor) reportIngestLimitsRejections(ctx context.Context, vCtx validationContext, streams, accepted []KeyedStream, streamResolver push.StreamResolver, format string) int {
+	if len(streams) == len(accepted) {
+		return 0
+	}
+
+	acceptedHashes := make(map[uint64]struct{}, len(accepted))
+	for _, s := range accepted {
+		acceptedHashes[s.HashKeyNoShard] = struct{}{}
+	}
+
+	var rejected int
+	for _, s := range streams {
+		if _, ok := acceptedHashes[s.HashKeyNoShard]; ok {
+			continue
+		}
+		rejected += len(s.Stream.Entries)
+
+		lbs, err := syntax.ParseLabels(s.Stream.Labels)
+		if err != nil {
+			continue
+		}
+		retentionHours := d.tenantsRetention.RetentionHoursFor(vCtx.userID, lbs)
+		policy := streamResolver.PolicyFor(lbs)
+		d.validator.reportDiscardedDataWithTracker(ctx, validation.StreamLimit, vCtx, lbs, retentionHours, policy, util.EntriesTotalSize(s.Stream.Entries), len(s.Stream.Entries), format)
+	}
+	return rejected
+}
+
 type streamWithTimeShard struct {
 	logproto.Stream
 	linesTotalLen int
@@ -1722,7 +1722,8 @@ func TestDistributor_PushIngestionBlocked(t *testing.T) {
 
 			distributors, _ := prepare(t, 1, 5, limits, nil)
 			request := makeWriteRequest(1, 1024)
-			response, err := distributors[0].Push(ctx, request)
+			outcome := &pushOutcome{}
+			response, err := distributors[0].pushWithOutcome(ctx, request, newRequestScopedStreamResolver("test", distributors[0].validator.Limits, distributors[0].logger), constants.Loki, outcome)
 
 			if tc.expectError {
 				expectedErr := fmt.Sprintf(validation.BlockedIngestionErrorMsg, "test", tc.blockUntil.Format(time.RFC3339), tc.blockStatusCode)
@@ -1731,6 +1732,16 @@ func TestDistributor_PushIngestionBlocked(t *testing.T) {
 				require.NoError(t, err)
 				require.Equal(t, success, response)
 			}
+
+			// The rejected entries are reported consistently, whatever the status code.
+			rejectedByReason := 0
+			for _, count := range outcome.rejectedByReason {
+				rejectedByReason += count
+			}
+			require.Equal(t, rejectedByReason, outcome.rejectedEntries)
+			if tc.blockStatusCode != 0 {
+				require.Equal(t, 1, outcome.rejectedEntries)
+			}
 		})
 	}
 }
@@ -148,7 +148,22 @@ func (d *Distributor) pushHandler(w http.ResponseWriter, r *http.Request, pushRe
 		}
 	}
 
-	_, err = d.PushWithResolver(r.Context(), req, streamResolver, format)
+	outcome := &pushOutcome{}
+	_, err = d.pushWithOutcome(r.Context(), req, streamResolver, format, outcome)
+	if format == constants.OTLP {
+		// OTLP clients expect a partial success response when only some log records were rejected.
+		trackOTLPParseRejections(tenantID, pushStats, outcome)
+		if rejected, msg, ok := otlpPartialSuccess(outcome, err); ok {
+			if d.tenantConfigs.LogPushRequest(tenantID) {
+				level.Debug(logger).Log(
+					"msg", "push request partially successful",
+					"rejected", rejected,
+				)
+			}
+			push.OTLPPartialSuccess(w, r, rejected, msg, logger)
+			return
+		}
+	}
 	if err == nil {
 		if d.tenantConfigs.LogPushRequest(tenantID) {
 			level.Debug(logger).Log(
@@ -0,0 +1,335 @@
+package distributor
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	logql_log "github.com/grafana/loki/v3/pkg/logql/log"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
+	"github.com/grafana/loki/v3/pkg/util/constants"
+	"github.com/grafana/loki/v3/pkg/validation"
+)
+
+// ingestPipelinesPruneInterval is how often the ingest pipelines of tenants
+// whose config changed are removed from the cache.
+const ingestPipelinesPruneInterval = time.Minute
+
+type ingestPipelineMetrics struct {
+	entriesDropped  *prometheus.CounterVec
+	entriesModified *prometheus.CounterVec
+}
+
+func newIngestPipelineMetrics(reg prometheus.Registerer) *ingestPipelineMetrics {
+	return &ingestPipelineMetrics{
+		entriesDropped: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: constants.Loki,
+			Name:      "distributor_ingest_pipeline_entries_dropped_total",
+			Help:      "The total number of entries dropped by a stage of a tenant ingest pipeline.",
+		}, []string{"tenant", "pipeline", "stage"}),
+		entriesModified: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: constants.Loki,
+			Name:      "distributor_ingest_pipeline_entries_modified_total",
+			Help:      "The total number of entries whose line or labels were changed by a stage of a tenant ingest pipeline.",
+		}, []string{"tenant", "pipeline", "stage"}),
+	}
+}
+
+// ingestPipelineCache caches the ingest pipelines of tenants so they are not
+// parsed and built for every push request. Stages are not safe for concurrent
+// use, so each tenant has a pool of pipelines built from its current config.
+type ingestPipelineCache struct {
+	logger log.Logger
+
+	mtx     sync.Mutex
+	tenants map[string]*tenantIngestPipelines
+}
+
+type tenantIngestPipelines struct {
+	key  string
+	pool sync.Pool
+}
+
+func newIngestPipelineCache(logger log.Logger) *ingestPipelineCache {
+	return &ingestPipelineCache{
+		logger:  logger,
+		tenants: map[string]*tenantIngestPipelines{},
+	}
+}
+
+// get returns the ingest pipelines of the tenant for cfgs and a function
+// releasing them once the push request is processed.
+func (c *ingestPipelineCache) get(tenantID string, cfgs []validation.IngestPipeline) ([]*ingestPipeline, func()) {
+	if len(cfgs) == 0 {
+		c.mtx.Lock()
+		delete(c.tenants, tenantID)
+		c.mtx.Unlock()
+		return nil, func() {}
+	}
+
+	key := ingestPipelinesKey(cfgs)
+	c.mtx.Lock()
+	t, ok := c.tenants[tenantID]
+	if !ok || t.key != key {
+		// The config of the tenant changed, pipelines built from the previous
+		// config are released to the old pool and collected.
+		t = &tenantIngestPipelines{key: key}
+		t.pool.New = func() any {
+			return newIngestPipelines(c.logger, tenantID, cfgs)
+		}
+		c.tenants[tenantID] = t
+	}
+	c.mtx.Unlock()
+
+	pipelines := t.pool.Get().([]*ingestPipeline)
+	return pipelines, func() { t.pool.Put(pipelines) }
+}
+
+// prune removes the pipelines of the tenants whose config was changed or
+// removed since they were built, so that tenants which stopped pushing don't
+// keep pipelines in the cache.
+func (c *ingestPipelineCache) prune(limits func(tenantID string) []validation.IngestPipeline) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	for tenantID, t := range c.tenants {
+		cfgs := limits(tenantID)
+		if len(cfgs) == 0 || t.key != ingestPipelinesKey(cfgs) {
+			delete(c.tenants, tenantID)
+		}
+	}
+}
+
+// ingestPipelinesKey identifies the config of the ingest pipelines of a tenant.
+func ingestPipelinesKey(cfgs []validation.IngestPipeline) string {
+	var sb strings.Builder
+	for _, cfg := range cfgs {
+		sb.WriteString(cfg.Name)
+		sb.WriteByte(0xff)
+		sb.WriteString(cfg.Query)
+		sb.WriteByte(0xfe)
+	}
+	return sb.String()
+}
+
+// ingestPipeline applies the stages of a tenant ingest pipeline to the entries
+// of the streams matching its stream selector.
+//
+// Stages are not safe for concurrent use, a pipeline is used by a single push
+// request at a time.
+type ingestPipeline struct {
+	name     string
+	matchers []*labels.Matcher
+	stages   []ingestStage
+}
+
+type ingestStage struct {
+	name  string
+	stage logql_log.Stage
+	// changesLabels is false for stages that can only change the line or filter
+	// the entry, so the labels don't need to be compared.
+	changesLabels bool
+}
+
+// newIngestPipelines builds the ingest pipelines configured for a tenant.
+// Pipelines that cannot be built are logged and skipped so they never block ingestion.
+func newIngestPipelines(logger log.Logger, tenantID string, cfgs []validation.IngestPipeline) []*ingestPipeline {
+	if len(cfgs) == 0 {
+		return nil
+	}
+
+	pipelines := make([]*ingestPipeline, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		p, err := newIngestPipeline(cfg)
+		if err != nil {
+			level.Warn(logger).Log("msg", "skipping invalid ingest pipeline", "tenant", tenantID, "pipeline", cfg.Name, "err", err)
+			continue
+		}
+		pipelines = append(pipelines, p)
+	}
+	return pipelines
+}
+
+func newIngestPipeline(cfg validation.IngestPipeline) (*ingestPipeline, error) {
+	expr := cfg.Expr
+	if expr == nil {
+		var err error
+		if expr, err = syntax.ParseLogSelector(cfg.Query, true); err != nil {
+			return nil, err
+		}
+	}
+
+	p := &ingestPipeline{
+		name:     cfg.Name,
+		matchers: expr.Matchers(),
+	}
+
+	pipelineExpr, ok := expr.(*syntax.PipelineExpr)
+	if !ok {
+		return p, nil
+	}
+
+	// Stages are kept in the configured order, unlike in queries, so that the
+	// metrics are attributed to the stage the user wrote.
+	for i, e := range pipelineExpr.MultiStages {
+		stage, err := e.Stage()
+		if err != nil {
+			return nil, fmt.Errorf("stage %d: %w", i, err)
+		}
+		name, changesLabels := ingestStageName(e)
+		p.stages = append(p.stages, ingestStage{
+			name:          fmt.Sprintf("%d:%s", i, name),
+			stage:         stage,
+			changesLabels: changesLabels,
+		})
+	}
+	return p, nil
+}
+
+// ingestStageName returns the name of a stage used in metrics and whether the
+// stage can change labels.
+func ingestStageName(e syntax.StageExpr) (string, bool) {
+	switch e := e.(type) {
+	case *syntax.LineFilterExpr:
+		return "line_filter", false
+	case *syntax.LabelFilterExpr:
+		return "label_filter", false
+	case *syntax.LineFmtExpr:
+		return "line_format", false
+	case *syntax.DecolorizeExpr:
+		return "decolorize", false
+	case *syntax.LabelFmtExpr:
+		return "label_format", true
+	case *syntax.DropLabelsExpr:
+		return "drop", true
+	case *syntax.KeepLabelsExpr:
+		return "keep", true
+	case *syntax.LogfmtParserExpr, *syntax.LogfmtExpressionParserExpr:
+		return syntax.OpParserTypeLogfmt, true
+	case *syntax.JSONExpressionParserExpr:
+		return syntax.OpParserTypeJSON, true
+	case *syntax.XMLExpressionParserExpr:
+		return syntax.OpParserTypeXML, true
+	case *syntax.CSVParserExpr:
+		return syntax.OpParserTypeCSV, true
+	case *syntax.LineParserExpr:
+		return e.Op, true
+	default:
+		return "unknown", true
+	}
+}
+
+func (p *ingestPipeline) matches(lbs labels.Labels) bool {
+	for _, m := range p.matchers {
+		if !m.Matches(lbs.Get(m.Name)) {
+			return false
+		}
+	}
+	return true
+}
+
+// apply runs the entries of the stream through the pipeline. Entries filtered
+// out by a stage are removed from the stream. Labels added or changed by the
+// pipeline are stored as structured metadata of the entry, the stream labels
+// never change. Entries for which a stage fails, such as a parser on a line
+// in another format, are kept unchanged.
+func (p *ingestPipeline) apply(tenantID string, lbs labels.Labels, stream *logproto.Stream, metrics *ingestPipelineMetrics) {
+	builder := logql_log.NewBaseLabelsBuilder().ForLabels(lbs, lbs.Hash())
+	var buf []labels.Label
+	// modifiedBy holds the stages that changed the current entry. They are
+	// only counted once the entry is kept with their changes.
+	var modifiedBy []string
+
+	n := 0
+	for _, entry := range stream.Entries {
+		builder.Reset()
+		builder.Add(logql_log.StructuredMetadataLabel, logproto.FromLabelAdaptersToLabels(entry.StructuredMetadata))
+
+		line := []byte(entry.Line)
+		ts := entry.Timestamp.UnixNano()
+		kept, modified := true, false
+		modifiedBy = modifiedBy[:0]
+		for _, s := range p.stages {
+			var before uint64
+			if s.changesLabels {
+				buf = builder.UnsortedLabels(buf)
+				before = labelsFingerprint(buf)
+			}
+
+			out, ok := s.stage.Process(ts, line, builder)
+			if !ok {
+				metrics.entriesDropped.WithLabelValues(tenantID, p.name, s.name).Inc()
+				kept = false
+				break
+			}
+			if builder.HasErr() {
+				modified = false
+				break
+			}
+
+			changed := !bytes.Equal(out, line)
+			if !changed && s.changesLabels {
+				buf = builder.UnsortedLabels(buf)
+				changed = labelsFingerprint(buf) != before
+			}
+			if changed {
+				modifiedBy = append(modifiedBy, s.name)
+				modified = true
+			}
+			line = out
+		}
+		if !kept {
+			continue
+		}
+
+		if modified {
+			entry.Line = string(line)
+			buf = builder.UnsortedLabels(buf)
+			entry.StructuredMetadata = ingestPipelineStructuredMetadata(lbs, buf)
+			for _, stage := range modifiedBy {
+				metrics.entriesModified.WithLabelValues(tenantID, p.name, stage).Inc()
+			}
+		}
+		stream.Entries[n] = entry
+		n++
+	}
+	stream.Entries = stream.Entries[:n]
+}
+
+// ingestPipelineStructuredMetadata returns the labels of the pipeline result
+// that are not stream labels. Changes to stream labels are discarded.
+func ingestPipelineStructuredMetadata(stream labels.Labels, result []labels.Label) []logproto.LabelAdapter {
+	metadata := make([]logproto.LabelAdapter, 0, len(result))
+	for _, l := range result {
+		if l.Name == logqlmodel.ErrorLabel || l.Name == logqlmodel.ErrorDetailsLabel {
+			continue
+		}
+		if stream.Has(l.Name) {
+			continue
+		}
+		metadata = append(metadata, logproto.LabelAdapter{Name: l.Name, Value: l.Value})
+	}
+	return metadata
+}
+
+// labelsFingerprint returns a hash of the labels that doesn't depend on their order.
+func labelsFingerprint(lbs []labels.Label) uint64 {
+	var fp uint64
+	for _, l := range lbs {
+		if l.Name == logqlmodel.ErrorLabel || l.Name == logqlmodel.ErrorDetailsLabel {
+			continue
+		}
+		fp ^= xxhash.Sum64String(l.Name + "\xff" + l.Value)
+	}
+	return fp
+}
@@ -0,0 +1,187 @@
+package distributor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/flagext"
+	ring_client "github.com/grafana/dskit/ring/client"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/validation"
+)
+
+func TestIngestPipeline_Apply(t *testing.T) {
+	p, err := newIngestPipeline(validation.IngestPipeline{
+		Name:  "test",
+		Query: `{app="api"} | json | level != "debug" | line_format "{{.msg}}"`,
+	})
+	require.NoError(t, err)
+
+	lbs, err := syntax.ParseLabels(`{app="api"}`)
+	require.NoError(t, err)
+	require.True(t, p.matches(lbs))
+
+	ts := time.Unix(0, 1)
+	stream := logproto.Stream{
+		Labels: lbs.String(),
+		Entries: []logproto.Entry{
+			{Timestamp: ts, Line: `{"level":"debug","msg":"a"}`},
+			{Timestamp: ts, Line: `{"level":"info","msg":"b"}`, StructuredMetadata: []logproto.LabelAdapter{{Name: "trace_id", Value: "1"}}},
+			{Timestamp: ts, Line: `not json`},
+		},
+	}
+
+	metrics := newIngestPipelineMetrics(prometheus.NewRegistry())
+	p.apply("tenant", lbs, &stream, metrics)
+
+	require.Len(t, stream.Entries, 2)
+	require.Equal(t, "b", stream.Entries[0].Line)
+	require.ElementsMatch(t, []logproto.LabelAdapter{
+		{Name: "trace_id", Value: "1"},
+		{Name: "level", Value: "info"},
+		{Name: "msg", Value: "b"},
+	}, stream.Entries[0].StructuredMetadata)
+	// The entry that failed to parse is kept unchanged.
+	require.Equal(t, "not json", stream.Entries[1].Line)
+	require.Empty(t, stream.Entries[1].StructuredMetadata)
+
+	require.Equal(t, 1.0, testutil.ToFloat64(metrics.entriesDropped.WithLabelValues("tenant", "test", "1:label_filter")))
+	// Only the entries kept with the changes of the stages are counted as modified.
+	require.Equal(t, 1.0, testutil.ToFloat64(metrics.entriesModified.WithLabelValues("tenant", "test", "0:json")))
+	require.Equal(t, 1.0, testutil.ToFloat64(metrics.entriesModified.WithLabelValues("tenant", "test", "2:line_format")))
+}
+
+func TestIngestPipeline_ApplyDiscardsFailedChanges(t *testing.T) {
+	p, err := newIngestPipeline(validation.IngestPipeline{
+		Name:  "test",
+		Query: `{app="api"} | line_format "{{.app}} {{__line__}}" | json`,
+	})
+	require.NoError(t, err)
+
+	lbs, err := syntax.ParseLabels(`{app="api"}`)
+	require.NoError(t, err)
+	stream := logproto.Stream{
+		Labels:  lbs.String(),
+		Entries: []logproto.Entry{{Timestamp: time.Unix(0, 1), Line: `{"msg":"a"}`}},
+	}
+
+	metrics := newIngestPipelineMetrics(prometheus.NewRegistry())
+	p.apply("tenant", lbs, &stream, metrics)
+
+	// The json stage fails on the formatted line, so the entry is kept unchanged
+	// and the change of the line_format stage is not counted.
+	require.Len(t, stream.Entries, 1)
+	require.Equal(t, `{"msg":"a"}`, stream.Entries[0].Line)
+	require.Equal(t, 0.0, testutil.ToFloat64(metrics.entriesModified.WithLabelValues("tenant", "test", "0:line_format")))
+}
+
+func TestNewIngestPipelines_SkipsInvalid(t *testing.T) {
+	pipelines := newIngestPipelines(log.NewNopLogger(), "tenant", []validation.IngestPipeline{
+		{Name: "invalid", Query: `{app="api"} | json |`},
+		{Name: "valid", Query: `{app="api"} |= "foo"`},
+	})
+	require.Len(t, pipelines, 1)
+	require.Equal(t, "valid", pipelines[0].name)
+}
+
+func TestIngestPipelineCache(t *testing.T) {
+	cache := newIngestPipelineCache(log.NewNopLogger())
+	cfgs := []validation.IngestPipeline{{Name: "drop_debug", Query: `{app="api"} != "debug"`}}
+
+	pipelines, release := cache.get("tenant", cfgs)
+	require.Len(t, pipelines, 1)
+	release()
+
+	// Released pipelines are reused while the config doesn't change. The pool
+	// may drop them, so the test only checks that a built pipeline is returned.
+	again, release := cache.get("tenant", cfgs)
+	require.Len(t, again, 1)
+	release()
+	require.Equal(t, ingestPipelinesKey(cfgs), cache.tenants["tenant"].key)
+
+	changed := []validation.IngestPipeline{{Name: "drop_info", Query: `{app="api"} != "info"`}}
+	pipelines, release = cache.get("tenant", changed)
+	defer release()
+	require.Len(t, pipelines, 1)
+	require.Equal(t, "drop_info", pipelines[0].name)
+	require.Equal(t, ingestPipelinesKey(changed), cache.tenants["tenant"].key)
+
+	pipelines, release = cache.get("other", cfgs)
+	defer release()
+	require.Len(t, pipelines, 1)
+
+	// Tenants whose config was changed or removed are pruned.
+	cache.prune(func(tenantID string) []validation.IngestPipeline {
+		if tenantID == "tenant" {
+			return changed
+		}
+		return nil
+	})
+	require.Contains(t, cache.tenants, "tenant")
+	require.NotContains(t, cache.tenants, "other")
+
+	cache.prune(func(string) []validation.IngestPipeline { return cfgs })
+	require.Empty(t, cache.tenants)
+
+	pipelines, release = cache.get("tenant", changed)
+	defer release()
+	require.Len(t, pipelines, 1)
+	pipelines, release = cache.get("tenant", nil)
+	defer release()
+	require.Empty(t, pipelines)
+	require.Empty(t, cache.tenants)
+}
+
+func TestDistributor_IngestPipelines(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.IngestPipelines = []validation.IngestPipeline{
+		{Name: "drop_debug", Query: `{foo="bar"} != "debug"`},
+		{Name: "other_stream", Query: `{foo="baz"} |= "nothing"`},
+	}
+
+	ingester := &mockIngester{}
+	distributors, _ := prepare(t, 1, 3, limits, func(_ string) (ring_client.PoolClient, error) { return ingester, nil })
+
+	ts := time.Now()
+	req := &logproto.PushRequest{Streams: []logproto.Stream{
+		{
+			Labels: `{foo="bar"}`,
+			Entries: []logproto.Entry{
+				{Timestamp: ts, Line: "debug: hello"},
+				{Timestamp: ts.Add(time.Millisecond), Line: "info: hello"},
+			},
+		},
+		{
+			Labels: `{foo="qux"}`,
+			Entries: []logproto.Entry{
+				{Timestamp: ts, Line: "debug: hello"},
+			},
+		},
+	}}
+
+	ctx := user.InjectOrgID(context.Background(), "test")
+	_, err := distributors[0].Push(ctx, req)
+	require.NoError(t, err)
+
+	ingester.mu.Lock()
+	defer ingester.mu.Unlock()
+	pushed := map[string][]logproto.Entry{}
+	for _, r := range ingester.pushed {
+		for _, s := range r.Streams {
+			pushed[s.Labels] = s.Entries
+		}
+	}
+	require.Len(t, pushed, 2)
+	require.Len(t, pushed[`{foo="bar"}`], 1)
+	require.Equal(t, "info: hello", pushed[`{foo="bar"}`][0].Line)
+	require.Len(t, pushed[`{foo="qux"}`], 1)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/compactor/retention"
 	"github.com/grafana/loki/v3/pkg/distributor/shardstreams"
 	"github.com/grafana/loki/v3/pkg/loghttp/push"
+	"github.com/grafana/loki/v3/pkg/validation"
 )
 
 // Limits is an interface for distributor limits/related configs
@@ -26,6 +27,8 @@ type Limits interface {
 	IncrementDuplicateTimestamps(userID string) bool
 	DiscoverServiceName(userID string) []string
 	DiscoverGenericFields(userID string) map[string][]string
+	IngestPipelines(userID string) []validation.IngestPipeline
+	Redaction(userID string) validation.RedactionConfig
 	DiscoverLogLevels(userID string) bool
 	LogLevelFields(userID string) []string
 	LogLevelFromJSONMaxDepth(userID string) int
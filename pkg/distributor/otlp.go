@@ -0,0 +1,63 @@
+package distributor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/grafana/dskit/httpgrpc"
+
+	"github.com/grafana/loki/v3/pkg/loghttp/push"
+	"github.com/grafana/loki/v3/pkg/util/constants"
+	"github.com/grafana/loki/v3/pkg/validation"
+)
+
+// trackOTLPParseRejections reports the log records that were dropped while
+// parsing an OTLP request because they resulted in invalid stream labels, and
+// adds them to the outcome of the push.
+func trackOTLPParseRejections(tenantID string, pushStats *push.Stats, outcome *pushOutcome) {
+	if pushStats == nil || pushStats.InvalidLabelsRecords == 0 {
+		return
+	}
+
+	// The labels are unknown, so there is no retention_hours or policy to report.
+	validation.DiscardedSamples.WithLabelValues(validation.InvalidLabels, tenantID, "", "", constants.OTLP).Add(float64(pushStats.InvalidLabelsRecords))
+
+	if outcome.rejectedByReason == nil {
+		outcome.rejectedByReason = make(map[string]int)
+	}
+	outcome.rejectedByReason[validation.InvalidLabels] += int(pushStats.InvalidLabelsRecords)
+	outcome.rejectedEntries += int(pushStats.InvalidLabelsRecords)
+}
+
+// otlpPartialSuccess returns the number of rejected log records and the message
+// to report them with when an OTLP request was only partially accepted.
+// It returns false if nothing was rejected or if the push failed as a whole.
+func otlpPartialSuccess(outcome *pushOutcome, err error) (int64, string, bool) {
+	if outcome.rejectedEntries == 0 || (err != nil && !outcome.written) {
+		return 0, "", false
+	}
+
+	msg := outcome.summary()
+	if err != nil {
+		if resp, ok := httpgrpc.HTTPResponseFromError(err); ok {
+			msg += "\n" + string(resp.Body)
+		} else {
+			msg += "\n" + err.Error()
+		}
+	}
+	return int64(outcome.rejectedEntries), msg, true
+}
+
+// summary returns the number of rejected entries and the number of discarded
+// samples per reason, using the same reasons as the discarded samples metric.
+func (o *pushOutcome) summary() string {
+	reasons := make([]string, 0, len(o.rejectedByReason))
+	for reason, count := range o.rejectedByReason {
+		if count > 0 {
+			reasons = append(reasons, fmt.Sprintf("%s=%d", reason, count))
+		}
+	}
+	sort.Strings(reasons)
+	return fmt.Sprintf("%d log records rejected (%s)", o.rejectedEntries, strings.Join(reasons, ", "))
+}
@@ -0,0 +1,116 @@
+package distributor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/tenant"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/loki/v3/pkg/loghttp/push"
+	"github.com/grafana/loki/v3/pkg/util/constants"
+	util_log "github.com/grafana/loki/v3/pkg/util/log"
+)
+
+// OTLPGRPCServer implements the OTLP LogsService, so OpenTelemetry Collectors can
+// push logs over OTLP/gRPC instead of OTLP/HTTP.
+type OTLPGRPCServer struct {
+	plogotlp.UnimplementedGRPCServer
+
+	d *Distributor
+}
+
+// NewOTLPGRPCServer returns an OTLP LogsService backed by the given distributor.
+func NewOTLPGRPCServer(d *Distributor) *OTLPGRPCServer {
+	return &OTLPGRPCServer{d: d}
+}
+
+// Export implements plogotlp.GRPCServer.
+//
+// Log records rejected while the rest of the request was written are reported
+// as a partial success, as required by the OTLP specification.
+func (s *OTLPGRPCServer) Export(ctx context.Context, exportReq plogotlp.ExportRequest) (plogotlp.ExportResponse, error) {
+	resp := plogotlp.NewExportResponse()
+	d := s.d
+
+	logger := util_log.WithContext(ctx, util_log.Logger)
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		level.Error(logger).Log("msg", "error getting tenant id", "err", err)
+		return resp, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	totalRecords := exportReq.Logs().LogRecordCount()
+	if totalRecords == 0 {
+		return resp, nil
+	}
+
+	streamResolver := newRequestScopedStreamResolver(tenantID, d.validator.Limits, logger)
+	req, pushStats, err := push.ParseOTLPExportRequest(ctx, logger, tenantID, exportReq, d.validator.Limits, d.tenantConfigs, d.usageTracker, streamResolver, userAgentFromContext(ctx))
+	if err != nil {
+		d.writeFailuresManager.Log(tenantID, fmt.Errorf("couldn't parse push request: %w", err))
+		return resp, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	outcome := &pushOutcome{}
+	if len(req.Streams) > 0 {
+		_, err = d.pushWithOutcome(ctx, req, streamResolver, constants.OTLP, outcome)
+	}
+	trackOTLPParseRejections(tenantID, pushStats, outcome)
+
+	rejected, msg, partial := otlpPartialSuccess(outcome, err)
+	switch {
+	case partial && rejected >= int64(totalRecords):
+		return resp, status.Error(codes.InvalidArgument, msg)
+	case partial:
+		resp.PartialSuccess().SetRejectedLogRecords(rejected)
+		resp.PartialSuccess().SetErrorMessage(msg)
+	case err != nil:
+		code, msg := http.StatusInternalServerError, err.Error()
+		if httpResp, ok := httpgrpc.HTTPResponseFromError(err); ok {
+			code, msg = int(httpResp.Code), string(httpResp.Body)
+		}
+		if d.tenantConfigs.LogPushRequest(tenantID) {
+			level.Debug(logger).Log("msg", "push request failed", "code", code, "err", msg)
+		}
+		return resp, status.Error(otlpGRPCCode(code), msg)
+	}
+
+	if d.tenantConfigs.LogPushRequest(tenantID) {
+		level.Debug(logger).Log("msg", "push request successful", "rejected", rejected)
+	}
+	return resp, nil
+}
+
+// otlpGRPCCode maps the HTTP status code of a push error to the gRPC code that
+// OTLP clients expect. Rate limiting is mapped to Unavailable because OTLP
+// clients only retry ResourceExhausted when the server provides retry info.
+func otlpGRPCCode(httpCode int) codes.Code {
+	switch {
+	case httpCode == http.StatusTooManyRequests:
+		return codes.Unavailable
+	case httpCode >= http.StatusInternalServerError:
+		return codes.Unavailable
+	case httpCode >= http.StatusBadRequest:
+		return codes.InvalidArgument
+	default:
+		return codes.Unknown
+	}
+}
+
+func userAgentFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if ua := md.Get("user-agent"); len(ua) > 0 {
+		return ua[0]
+	}
+	return ""
+}
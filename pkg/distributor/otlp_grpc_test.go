@@ -0,0 +1,109 @@
+package distributor
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+	ring_client "github.com/grafana/dskit/ring/client"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/loki/v3/pkg/loghttp/push"
+	"github.com/grafana/loki/v3/pkg/validation"
+)
+
+func makeOTLPExportRequest(lines ...string) plogotlp.ExportRequest {
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "checkout")
+	sl := rl.ScopeLogs().AppendEmpty()
+	for _, line := range lines {
+		lr := sl.LogRecords().AppendEmpty()
+		lr.Body().SetStr(line)
+		lr.SetTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+	}
+	return plogotlp.NewExportRequestFromLogs(ld)
+}
+
+func TestOTLPGRPCServer_Export(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.MaxLineSize = 10
+	otlpConfig := push.DefaultOTLPConfig(push.GlobalOTLPConfig{
+		DefaultOTLPResourceAttributesAsIndexLabels: []string{"service.name"},
+	})
+	limits.OTLPConfig = &otlpConfig
+
+	for _, tc := range []struct {
+		name             string
+		lines            []string
+		expectedCode     codes.Code
+		expectedRejected int64
+		expectedPushed   int
+	}{
+		{
+			name:           "all records accepted",
+			lines:          []string{"a", "b", "c"},
+			expectedCode:   codes.OK,
+			expectedPushed: 3,
+		},
+		{
+			name:             "some records rejected",
+			lines:            []string{"a", strings.Repeat("x", 20), "c"},
+			expectedCode:     codes.OK,
+			expectedRejected: 1,
+			expectedPushed:   2,
+		},
+		{
+			name:         "all records rejected",
+			lines:        []string{strings.Repeat("x", 20), strings.Repeat("y", 20)},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "empty request",
+			expectedCode: codes.OK,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ingester := &mockIngester{}
+			distributors, _ := prepare(t, 1, 3, limits, func(_ string) (ring_client.PoolClient, error) { return ingester, nil })
+			srv := NewOTLPGRPCServer(distributors[0])
+
+			ctx := user.InjectOrgID(context.Background(), "test")
+			resp, err := srv.Export(ctx, makeOTLPExportRequest(tc.lines...))
+			require.Equal(t, tc.expectedCode, status.Code(err))
+			if err != nil {
+				return
+			}
+
+			require.Equal(t, tc.expectedRejected, resp.PartialSuccess().RejectedLogRecords())
+			if tc.expectedRejected > 0 {
+				require.Contains(t, resp.PartialSuccess().ErrorMessage(), "line_too_long=1")
+			}
+
+			pushed := 0
+			if req := ingester.Peek(); req != nil {
+				for _, s := range req.Streams {
+					pushed += len(s.Entries)
+				}
+			}
+			require.Equal(t, tc.expectedPushed, pushed)
+		})
+	}
+}
+
+func TestOTLPGRPCCode(t *testing.T) {
+	require.Equal(t, codes.InvalidArgument, otlpGRPCCode(400))
+	require.Equal(t, codes.InvalidArgument, otlpGRPCCode(413))
+	require.Equal(t, codes.Unavailable, otlpGRPCCode(429))
+	require.Equal(t, codes.Unavailable, otlpGRPCCode(500))
+	require.Equal(t, codes.Unavailable, otlpGRPCCode(503))
+}
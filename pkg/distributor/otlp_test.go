@@ -0,0 +1,119 @@
+package distributor
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/httpgrpc"
+	ring_client "github.com/grafana/dskit/ring/client"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+
+	"github.com/grafana/loki/v3/pkg/loghttp/push"
+	"github.com/grafana/loki/v3/pkg/validation"
+)
+
+func TestPushOutcome_Summary(t *testing.T) {
+	outcome := &pushOutcome{
+		rejectedEntries: 3,
+		rejectedByReason: map[string]int{
+			validation.StreamLimit: 1,
+			validation.LineTooLong: 2,
+			validation.RateLimited: 0,
+		},
+	}
+	require.Equal(t, "3 log records rejected (line_too_long=2, stream_limit=1)", outcome.summary())
+}
+
+func TestOTLPPartialSuccess(t *testing.T) {
+	validationErr := httpgrpc.Errorf(http.StatusBadRequest, "line too long")
+
+	for _, tc := range []struct {
+		name             string
+		outcome          *pushOutcome
+		err              error
+		expectedPartial  bool
+		expectedRejected int64
+		expectedMsg      string
+	}{
+		{
+			name:    "nothing rejected",
+			outcome: &pushOutcome{written: true},
+		},
+		{
+			name: "push failed as a whole",
+			outcome: &pushOutcome{
+				rejectedEntries:  1,
+				rejectedByReason: map[string]int{validation.LineTooLong: 1},
+			},
+			err: validationErr,
+		},
+		{
+			name: "some entries rejected by validation",
+			outcome: &pushOutcome{
+				rejectedEntries:  1,
+				rejectedByReason: map[string]int{validation.LineTooLong: 1},
+				written:          true,
+			},
+			err:              validationErr,
+			expectedPartial:  true,
+			expectedRejected: 1,
+			expectedMsg:      "1 log records rejected (line_too_long=1)\nline too long",
+		},
+		{
+			name: "streams rejected by ingest limits",
+			outcome: &pushOutcome{
+				rejectedEntries:  2,
+				rejectedByReason: map[string]int{validation.StreamLimit: 2},
+				written:          true,
+			},
+			expectedPartial:  true,
+			expectedRejected: 2,
+			expectedMsg:      "2 log records rejected (stream_limit=2)",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rejected, msg, partial := otlpPartialSuccess(tc.outcome, tc.err)
+			require.Equal(t, tc.expectedPartial, partial)
+			require.Equal(t, tc.expectedRejected, rejected)
+			require.Equal(t, tc.expectedMsg, msg)
+		})
+	}
+}
+
+func TestOTLPPushHandler_PartialSuccess(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.MaxLineSize = 10
+	otlpConfig := push.DefaultOTLPConfig(push.GlobalOTLPConfig{
+		DefaultOTLPResourceAttributesAsIndexLabels: []string{"service.name"},
+	})
+	limits.OTLPConfig = &otlpConfig
+
+	ingester := &mockIngester{}
+	distributors, _ := prepare(t, 1, 3, limits, func(_ string) (ring_client.PoolClient, error) { return ingester, nil })
+
+	body, err := makeOTLPExportRequest("a", strings.Repeat("x", 20), "c").MarshalProto()
+	require.NoError(t, err)
+
+	ctx := user.InjectOrgID(context.Background(), "test")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/otlp/v1/logs", bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-protobuf")
+
+	rec := httptest.NewRecorder()
+	distributors[0].OTLPPushHandler(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	resp := plogotlp.NewExportResponse()
+	require.NoError(t, resp.UnmarshalProto(rec.Body.Bytes()))
+	require.Equal(t, int64(1), resp.PartialSuccess().RejectedLogRecords())
+	require.Contains(t, resp.PartialSuccess().ErrorMessage(), "line_too_long=1")
+	require.Len(t, ingester.Peek().Streams[0].Entries, 2)
+}
@@ -0,0 +1,32 @@
+package distributor
+
+import (
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/log"
+	"github.com/grafana/loki/v3/pkg/validation"
+)
+
+// redactorFor returns the redactor of the tenant redaction config, or nil if redaction is disabled.
+// The redactor is built when the limits were not validated, which only happens when they are not loaded from a config file.
+func (d *Distributor) redactorFor(cfg validation.RedactionConfig) (*log.Redactor, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+	if cfg.Redactor != nil {
+		return cfg.Redactor, nil
+	}
+	return log.NewRedactor(cfg.Detectors, cfg.Patterns)
+}
+
+// redactEntries redacts the lines and structured metadata values of the entries in place.
+func (d *Distributor) redactEntries(tenantID string, redactor *log.Redactor, entries []logproto.Entry) {
+	matched := func(detector string) {
+		d.redactions.WithLabelValues(tenantID, detector).Inc()
+	}
+	for i := range entries {
+		entries[i].Line = redactor.RedactString(entries[i].Line, matched)
+		for j := range entries[i].StructuredMetadata {
+			entries[i].StructuredMetadata[j].Value = redactor.RedactString(entries[i].StructuredMetadata[j].Value, matched)
+		}
+	}
+}
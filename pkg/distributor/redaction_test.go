@@ -0,0 +1,63 @@
+package distributor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+	ring_client "github.com/grafana/dskit/ring/client"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/validation"
+)
+
+func TestDistributor_Redaction(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.Redaction = validation.RedactionConfig{
+		Detectors: []string{"email", "credit_card"},
+		Patterns:  map[string]string{"token": `tok_[a-z0-9]+`},
+	}
+
+	ingester := &mockIngester{}
+	distributors, _ := prepare(t, 1, 3, limits, func(_ string) (ring_client.PoolClient, error) { return ingester, nil })
+
+	req := &logproto.PushRequest{Streams: []logproto.Stream{{
+		Labels: `{foo="bar"}`,
+		Entries: []logproto.Entry{{
+			Timestamp:          time.Now(),
+			Line:               "user jane@example.com paid with 4111111111111111",
+			StructuredMetadata: []logproto.LabelAdapter{{Name: "session", Value: "tok_abc123"}},
+		}},
+	}}}
+
+	ctx := user.InjectOrgID(context.Background(), "test")
+	_, err := distributors[0].Push(ctx, req)
+	require.NoError(t, err)
+
+	pushed := ingester.Peek()
+	require.Len(t, pushed.Streams, 1)
+	entry := pushed.Streams[0].Entries[0]
+	require.Equal(t, "user <redacted:email> paid with <redacted:credit_card>", entry.Line)
+	require.Contains(t, entry.StructuredMetadata, logproto.LabelAdapter{Name: "session", Value: "<redacted:token>"})
+
+	require.Equal(t, 1.0, testutil.ToFloat64(distributors[0].redactions.WithLabelValues("test", "email")))
+	require.Equal(t, 1.0, testutil.ToFloat64(distributors[0].redactions.WithLabelValues("test", "credit_card")))
+	require.Equal(t, 1.0, testutil.ToFloat64(distributors[0].redactions.WithLabelValues("test", "token")))
+}
+
+func TestDistributor_RedactionInvalidConfig(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.Redaction = validation.RedactionConfig{Detectors: []string{"phone_number"}}
+
+	distributors, _ := prepare(t, 1, 3, limits, nil)
+
+	ctx := user.InjectOrgID(context.Background(), "test")
+	_, err := distributors[0].Push(ctx, makeWriteRequest(1, 10))
+	require.ErrorContains(t, err, "invalid redaction config")
+}
@@ -14,15 +14,27 @@ type validationMetrics struct {
 	policyPushStats      map[string]map[string]pushStats // policy -> retentionHours -> lineSize
 	tenantRetentionHours string
 	aggregatedPushStats  pushStats
+	discardedSamples     map[string]int // reason -> number of discarded samples, mirrors validation.DiscardedSamples
 }
 
 func newValidationMetrics(tenantRetentionHours string) validationMetrics {
 	return validationMetrics{
 		policyPushStats:      make(map[string]map[string]pushStats),
 		tenantRetentionHours: tenantRetentionHours,
+		discardedSamples:     make(map[string]int),
 	}
 }
 
+// discarded records samples discarded for the given reason.
+// The map is shared between copies of validationMetrics, so this is also visible to the caller of
+// functions that receive the validation context by value.
+func (v *validationMetrics) discarded(reason string, count int) {
+	if v.discardedSamples == nil {
+		return
+	}
+	v.discardedSamples[reason] += count
+}
+
 func (v *validationMetrics) compute(entry logproto.Entry, retentionHours string, policy string) {
 	if _, ok := v.policyPushStats[policy]; !ok {
 		v.policyPushStats[policy] = make(map[string]pushStats)
@@ -48,6 +48,8 @@ type validationContext struct {
 	incrementDuplicateTimestamps bool
 	discoverServiceName          []string
 	discoverGenericFields        map[string][]string
+	ingestPipelines              []validation.IngestPipeline
+	redaction                    validation.RedactionConfig
 	discoverLogLevels            bool
 	logLevelFields               []string
 	logLevelFromJSONMaxDepth     int
@@ -85,6 +87,8 @@ func (v Validator) getValidationContextForTime(now time.Time, userID string) val
 		logLevelFields:                v.LogLevelFields(userID),
 		logLevelFromJSONMaxDepth:      v.LogLevelFromJSONMaxDepth(userID),
 		discoverGenericFields:         v.DiscoverGenericFields(userID),
+		ingestPipelines:               v.IngestPipelines(userID),
+		redaction:                     v.Redaction(userID),
 		allowStructuredMetadata:       v.AllowStructuredMetadata(userID),
 		maxStructuredMetadataSize:     v.MaxStructuredMetadataSize(userID),
 		maxStructuredMetadataCount:    v.MaxStructuredMetadataCount(userID),
@@ -163,6 +167,7 @@ func (v Validator) ValidateLabels(vCtx validationContext, ls labels.Labels, stre
 	if ls.IsEmpty() {
 		// TODO: is this one correct?
 		validation.DiscardedSamples.WithLabelValues(validation.MissingLabels, vCtx.userID, retentionHours, policy, format).Inc()
+		vCtx.validationMetrics.discarded(validation.MissingLabels, 1)
 		return fmt.Errorf(validation.MissingLabelsErrorMsg)
 	}
 
@@ -206,6 +211,7 @@ func (v Validator) ValidateLabels(vCtx validationContext, ls labels.Labels, stre
 func (v Validator) reportDiscardedData(reason string, vCtx validationContext, retentionHours string, policy string, entrySize, entryCount int, format string) {
 	validation.DiscardedSamples.WithLabelValues(reason, vCtx.userID, retentionHours, policy, format).Add(float64(entryCount))
 	validation.DiscardedBytes.WithLabelValues(reason, vCtx.userID, retentionHours, policy, format).Add(float64(entrySize))
+	vCtx.validationMetrics.discarded(reason, entryCount)
 }
 
 func (v Validator) reportDiscardedDataWithTracker(ctx context.Context, reason string, vCtx validationContext, labels labels.Labels, retentionHours string, policy string, entrySize, entryCount int, format string) {
@@ -326,3 +326,27 @@ func collectSamplesFromRow(builder *labels.Builder, rec arrow.Record, i int) (pr
 	sample.Metric = builder.Labels()
 	return sample, true
 }
+
+// scaledResultBuilder scales the samples of a metric query result, to
+// extrapolate the result of a query sampling its logs to all logs.
+type scaledResultBuilder struct {
+	ResultBuilder
+	scale float64
+}
+
+func (b *scaledResultBuilder) Build(s stats.Result, md *metadata.Context) logqlmodel.Result {
+	res := b.ResultBuilder.Build(s, md)
+	switch data := res.Data.(type) {
+	case promql.Vector:
+		for i := range data {
+			data[i].F *= b.scale
+		}
+	case promql.Matrix:
+		for _, series := range data {
+			for i := range series.Floats {
+				series.Floats[i].F *= b.scale
+			}
+		}
+	}
+	return res
+}
@@ -16,10 +16,16 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
 
 	dskit_flagext "github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/tenant"
 
 	"github.com/grafana/loki/v3/pkg/dataobj/metastore"
+	"github.com/grafana/loki/v3/pkg/dataobj/rangecache"
+	"github.com/grafana/loki/v3/pkg/engine/internal/distributed"
+	"github.com/grafana/loki/v3/pkg/engine/internal/distributed/distributedpb"
 	"github.com/grafana/loki/v3/pkg/engine/internal/executor"
 	"github.com/grafana/loki/v3/pkg/engine/internal/planner/logical"
 	"github.com/grafana/loki/v3/pkg/engine/internal/planner/physical"
@@ -37,8 +43,16 @@ var tracer = otel.Tracer("pkg/engine")
 
 var ErrNotSupported = errors.New("feature not supported in new query engine")
 
+// IngesterQuerier queries the ingesters for logs which are not flushed to data
+// objects yet. It is used when [Config.QueryIngesters] is enabled.
+type IngesterQuerier = executor.IngesterQuerier
+
 // New creates a new instance of the query engine that implements the [logql.Engine] interface.
-func New(cfg Config, metastoreCfg metastore.Config, bucket objstore.Bucket, limits logql.Limits, reg prometheus.Registerer, logger log.Logger) *QueryEngine {
+//
+// ingesters may be nil if [Config.QueryIngesters] is disabled. runner may be
+// nil if [distributed.Config.Enabled] is disabled, in which case queries are
+// executed locally.
+func New(cfg Config, metastoreCfg metastore.Config, bucket objstore.Bucket, ingesters IngesterQuerier, runner *FragmentRunner, limits logql.Limits, reg prometheus.Registerer, logger log.Logger) *QueryEngine {
 	var ms metastore.Metastore
 	if bucket != nil {
 		indexBucket := bucket
@@ -55,14 +69,54 @@ func New(cfg Config, metastoreCfg metastore.Config, bucket objstore.Bucket, limi
 		cfg.RangeConfig = rangeio.DefaultConfig
 	}
 
-	return &QueryEngine{
+	e := &QueryEngine{
 		logger:    logger,
 		metrics:   newMetrics(reg),
 		limits:    limits,
 		metastore: ms,
 		bucket:    bucket,
+		ingesters: ingesters,
 		cfg:       cfg,
 	}
+	if runner != nil {
+		e.fragmentRunner = runner.runner
+	}
+
+	return e
+}
+
+// FragmentRunner is a service executing the plan fragments of queries on the
+// workers of [distributed.Config]. It must be running while the engine it is
+// passed to executes queries.
+type FragmentRunner struct {
+	services.Service
+
+	runner *distributed.Runner
+}
+
+// NewFragmentRunner returns a runner executing plan fragments on the workers
+// configured in cfg.Distributed.
+func NewFragmentRunner(cfg Config, reg prometheus.Registerer, logger log.Logger) (*FragmentRunner, error) {
+	runner, err := distributed.NewRunner(cfg.Distributed, reg, log.With(logger, "component", "engine-fragment-runner"))
+	if err != nil {
+		return nil, err
+	}
+	return &FragmentRunner{Service: runner, runner: runner}, nil
+}
+
+// RegisterFragmentExecutor registers the gRPC service executing plan fragments
+// sent by queriers with distributed execution enabled.
+func RegisterFragmentExecutor(s *grpc.Server, cfg Config, bucket objstore.Bucket, ingesters IngesterQuerier, logger log.Logger) {
+	if cfg.RangeConfig.IsZero() {
+		cfg.RangeConfig = rangeio.DefaultConfig
+	}
+	executorCfg := executor.Config{
+		BatchSize:          int64(cfg.BatchSize),
+		MergePrefetchCount: cfg.MergePrefetchCount,
+		Bucket:             bucket,
+		IngesterQuerier:    ingesters,
+	}
+	distributedpb.RegisterFragmentExecutorServer(s, distributed.NewServer(executorCfg, cfg.RangeConfig, log.With(logger, "component", "engine-fragment-executor")))
 }
 
 // Config holds the configuration options to use with the next generation Loki Query Engine.
@@ -73,6 +127,10 @@ type Config struct {
 	DataobjStorageLag   time.Duration      `yaml:"dataobj_storage_lag" category:"experimental"`
 	DataobjStorageStart dskit_flagext.Time `yaml:"dataobj_storage_start" category:"experimental"`
 
+	// QueryIngesters enables reading logs newer than DataobjStorageLag from
+	// the ingesters, so that queries do not need to be split between engines.
+	QueryIngesters bool `yaml:"query_ingesters" category:"experimental"`
+
 	// Batch size of the v2 execution engine.
 	BatchSize int `yaml:"batch_size" category:"experimental"`
 
@@ -81,6 +139,12 @@ type Config struct {
 
 	// RangeConfig determines how to optimize range reads in the V2 engine.
 	RangeConfig rangeio.Config `yaml:"range_reads" category:"experimental" doc:"description=Configures how to read byte ranges from object storage when using the V2 engine."`
+
+	// Distributed configures the execution of plan fragments on other queriers.
+	Distributed distributed.Config `yaml:"distributed" category:"experimental" doc:"description=Configures the execution of the scans and partial aggregations of queries on other queriers when using the V2 engine."`
+
+	// DataObjCache configures the caches for reads of data objects.
+	DataObjCache rangecache.Config `yaml:"dataobj_cache" category:"experimental" doc:"description=Configures the caches for the metadata and pages of data objects read by the V2 engine."`
 }
 
 func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
@@ -88,12 +152,24 @@ func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
 	f.IntVar(&cfg.BatchSize, prefix+"batch-size", 100, "Experimental: Batch size of the next generation query engine.")
 	f.IntVar(&cfg.MergePrefetchCount, prefix+"merge-prefetch-count", 0, "Experimental: The number of inputs that are prefetched simultaneously by any Merge node. A value of 0 means that only the currently processed input is prefetched, 1 means that only the next input is prefetched, and so on. A negative value means that all inputs are be prefetched in parallel.")
 	cfg.RangeConfig.RegisterFlags(prefix+"range-reads.", f)
+	cfg.Distributed.RegisterFlagsWithPrefix(prefix+"distributed.", f)
+	cfg.DataObjCache.RegisterFlagsWithPrefix(prefix+"dataobj-cache.", f)
 
 	f.DurationVar(&cfg.DataobjStorageLag, prefix+"dataobj-storage-lag", 1*time.Hour, "Amount of time until data objects are available.")
+	f.BoolVar(&cfg.QueryIngesters, prefix+"query-ingesters", false, "Experimental: Read logs newer than the data object storage lag from the ingesters, so that queries up to now can be executed by the next generation query engine.")
 	f.Var(&cfg.DataobjStorageStart, prefix+"dataobj-storage-start", "Initial date when data objects became available. Format YYYY-MM-DD. If not set, assume data objects are always available no matter how far back.")
 }
 
+// maxQueryEnd is the end of the valid query range if recent logs are read
+// from the ingesters. It leaves headroom for aligning query ranges to steps.
+var maxQueryEnd = time.Unix(0, 1<<62).UTC()
+
+// ValidQueryRange returns the time range which can be queried with the next
+// generation query engine.
 func (cfg *Config) ValidQueryRange() (time.Time, time.Time) {
+	if cfg.QueryIngesters {
+		return time.Time(cfg.DataobjStorageStart).UTC(), maxQueryEnd
+	}
 	return time.Time(cfg.DataobjStorageStart).UTC(), time.Now().UTC().Add(-cfg.DataobjStorageLag)
 }
 
@@ -104,7 +180,12 @@ type QueryEngine struct {
 	limits    logql.Limits
 	metastore metastore.Metastore
 	bucket    objstore.Bucket
+	ingesters IngesterQuerier
 	cfg       Config
+
+	// fragmentRunner executes plan fragments on other queriers. Queries are
+	// executed locally if it is nil.
+	fragmentRunner executor.FragmentRunner
 }
 
 // Query implements [logql.Engine].
@@ -152,6 +233,29 @@ func (e *QueryEngine) Execute(ctx context.Context, params logql.Params) (logqlmo
 	// [rangeio.ReadRanges] to make use of.
 	ctx = rangeio.WithConfig(ctx, &e.cfg.RangeConfig)
 
+	// Queries across several tenants read the data of each tenant, and
+	// honour the most restrictive limits of these tenants.
+	tenants, _ := tenant.TenantIDs(ctx)
+	limits := newQueryLimits(ctx, e.limits, tenants)
+	if limits.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, limits.timeout)
+		defer cancel()
+	}
+	if err := limits.checkInterval(params.GetExpression()); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "query exceeds limits")
+		return logqlmodel.Result{}, err
+	}
+	if redactionEnabled(e.limits, tenants) {
+		// The engine doesn't redact entries, the legacy engine executes the
+		// queries of these tenants.
+		level.Debug(logger).Log("msg", "redaction is not supported")
+		e.metrics.subqueries.WithLabelValues(statusNotImplemented).Inc()
+		span.SetStatus(codes.Error, "redaction is not supported")
+		return logqlmodel.Result{}, ErrNotSupported
+	}
+
 	logicalPlan, err := func() (*logical.Plan, error) {
 		_, span := tracer.Start(ctx, "QueryEngine.Execute.logicalPlan")
 		defer span.End()
@@ -188,7 +292,13 @@ func (e *QueryEngine) Execute(ctx context.Context, params logql.Params) (logqlmo
 		timer := prometheus.NewTimer(e.metrics.physicalPlanning)
 
 		catalog := physical.NewMetastoreCatalog(ctx, e.metastore)
-		planner := physical.NewPlanner(physical.NewContext(params.Start(), params.End()), catalog)
+		plannerCtx := physical.NewContext(params.Start(), params.End())
+		if e.cfg.QueryIngesters {
+			// Logs newer than the lag are read from the ingesters, older logs
+			// from data objects.
+			plannerCtx = plannerCtx.WithIngestersFrom(time.Now().Add(-e.cfg.DataobjStorageLag))
+		}
+		planner := physical.NewPlanner(plannerCtx, catalog)
 		plan, err := planner.Build(logicalPlan)
 		if err != nil {
 			level.Warn(logger).Log("msg", "failed to create physical plan", "err", err)
@@ -207,6 +317,17 @@ func (e *QueryEngine) Execute(ctx context.Context, params logql.Params) (logqlmo
 			return nil, ErrNotSupported
 		}
 
+		if e.fragmentRunner != nil {
+			plan, err = physical.Distribute(plan)
+			if err != nil {
+				level.Warn(logger).Log("msg", "failed to distribute physical plan", "err", err)
+				e.metrics.subqueries.WithLabelValues(statusFailure).Inc()
+				span.RecordError(err)
+				span.SetStatus(codes.Error, "failed to distribute physical plan")
+				return nil, ErrNotSupported
+			}
+		}
+
 		durPhysicalPlanning = timer.ObserveDuration()
 		level.Info(logger).Log(
 			"msg", "finished physical planning",
@@ -234,6 +355,8 @@ func (e *QueryEngine) Execute(ctx context.Context, params logql.Params) (logqlmo
 			BatchSize:          int64(e.cfg.BatchSize),
 			MergePrefetchCount: e.cfg.MergePrefetchCount,
 			Bucket:             e.bucket,
+			FragmentRunner:     e.fragmentRunner,
+			IngesterQuerier:    e.ingesters,
 		}
 		pipeline := executor.Run(ctx, cfg, physicalPlan, logger)
 		defer pipeline.Close()
@@ -248,6 +371,11 @@ func (e *QueryEngine) Execute(ctx context.Context, params logql.Params) (logqlmo
 			} else {
 				builder = newVectorResultBuilder()
 			}
+			// Only sums and counts are supported, which scale linearly with
+			// the sampling ratio.
+			if scale := syntax.SamplingScale(params.GetExpression()); scale != 1 {
+				builder = &scaledResultBuilder{ResultBuilder: builder, scale: scale}
+			}
 		default:
 			// should never happen as we already check the expression type in the logical planner
 			panic(fmt.Sprintf("failed to execute. Invalid exprression type (%T)", params.GetExpression()))
@@ -284,8 +412,14 @@ func (e *QueryEngine) Execute(ctx context.Context, params logql.Params) (logqlmo
 	)
 
 	metadataCtx.AddWarning("Query was executed using the new experimental query engine and dataobj storage.")
+	result := builder.Build(stats, metadataCtx)
+	if err := limits.checkSeries(result.Data); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "query exceeds limits")
+		return logqlmodel.Result{}, err
+	}
 	span.SetStatus(codes.Ok, "")
-	return builder.Build(stats, metadataCtx), nil
+	return result, nil
 }
 
 func IsQuerySupported(params logql.Params) bool {
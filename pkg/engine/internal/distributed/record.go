@@ -0,0 +1,43 @@
+package distributed
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// encodeRecord encodes rec in the Arrow IPC streaming format. Every record
+// is encoded with its schema, since the schema of the records of a pipeline
+// may differ between batches.
+func encodeRecord(buf *bytes.Buffer, rec arrow.Record) error {
+	w := ipc.NewWriter(buf, ipc.WithSchema(rec.Schema()))
+	if err := w.Write(rec); err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// decodeRecord decodes a record encoded with [encodeRecord]. The caller owns
+// the returned record and must release it.
+func decodeRecord(data []byte) (arrow.Record, error) {
+	r, err := ipc.NewReader(bytes.NewReader(data), ipc.WithAllocator(memory.DefaultAllocator))
+	if err != nil {
+		return nil, fmt.Errorf("reading record schema: %w", err)
+	}
+	defer r.Release()
+
+	if !r.Next() {
+		if err := r.Err(); err != nil {
+			return nil, fmt.Errorf("reading record: %w", err)
+		}
+		return nil, errors.New("no record found")
+	}
+	rec := r.Record()
+	rec.Retain()
+	return rec, nil
+}
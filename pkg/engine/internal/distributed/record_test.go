@@ -0,0 +1,49 @@
+package distributed
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/require"
+)
+
+func testRecord(t *testing.T, values ...string) arrow.Record {
+	t.Helper()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "message", Type: arrow.BinaryTypes.String, Nullable: true, Metadata: arrow.NewMetadata([]string{"column_type"}, []string{"builtin"})},
+	}, nil)
+	rb := array.NewRecordBuilder(memory.NewGoAllocator(), schema)
+	defer rb.Release()
+	for _, v := range values {
+		if v == "" {
+			rb.Field(0).(*array.StringBuilder).AppendNull()
+			continue
+		}
+		rb.Field(0).(*array.StringBuilder).Append(v)
+	}
+	return rb.NewRecord()
+}
+
+func TestEncodeRecord(t *testing.T) {
+	rec := testRecord(t, "foo", "", "bar")
+	defer rec.Release()
+
+	var buf bytes.Buffer
+	require.NoError(t, encodeRecord(&buf, rec))
+
+	decoded, err := decodeRecord(buf.Bytes())
+	require.NoError(t, err)
+	defer decoded.Release()
+
+	require.True(t, rec.Schema().Equal(decoded.Schema()), "schema mismatch: %s != %s", rec.Schema(), decoded.Schema())
+	require.True(t, array.RecordEqual(rec, decoded))
+}
+
+func TestDecodeRecord_Invalid(t *testing.T) {
+	_, err := decodeRecord([]byte("not a record"))
+	require.Error(t, err)
+}
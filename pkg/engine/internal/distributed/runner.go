@@ -0,0 +1,440 @@
+package distributed
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/grpcclient"
+	"github.com/grafana/dskit/middleware"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+
+	"github.com/grafana/loki/v3/pkg/engine/internal/distributed/distributedpb"
+	"github.com/grafana/loki/v3/pkg/engine/internal/executor"
+	"github.com/grafana/loki/v3/pkg/engine/internal/planner/physical"
+	"github.com/grafana/loki/v3/pkg/queue"
+	"github.com/grafana/loki/v3/pkg/util"
+	"github.com/grafana/loki/v3/pkg/util/constants"
+)
+
+// Config configures the distributed execution of plan fragments.
+type Config struct {
+	Enabled                 bool              `yaml:"enabled"`
+	WorkersAddress          string            `yaml:"workers_address"`
+	DNSLookupPeriod         time.Duration     `yaml:"dns_lookup_duration"`
+	MaxConcurrentPerWorker  int               `yaml:"max_concurrent_fragments_per_worker"`
+	MaxOutstandingPerTenant int               `yaml:"max_outstanding_fragments_per_tenant"`
+	RecordBufferSize        int               `yaml:"record_buffer_size"`
+	GRPCClientConfig        grpcclient.Config `yaml:"grpc_client_config" doc:"description=Configures the gRPC client used to send plan fragments to workers."`
+}
+
+// RegisterFlagsWithPrefix registers flags for the config with the given prefix.
+func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, prefix+"enabled", false, "Experimental: Execute the scans and partial aggregations of queries as plan fragments on the queriers at -"+prefix+"workers-address instead of executing whole queries in a single querier.")
+	f.StringVar(&cfg.WorkersAddress, prefix+"workers-address", "", "Hostname and port of the queriers executing plan fragments, which is periodically resolved. Use a DNS name resolving to all queriers, for example the headless service of the queriers, to spread fragments across all of them.")
+	f.DurationVar(&cfg.DNSLookupPeriod, prefix+"dns-lookup-period", 10*time.Second, "How often to resolve the workers address.")
+	f.IntVar(&cfg.MaxConcurrentPerWorker, prefix+"max-concurrent-fragments-per-worker", 4, "Maximum number of plan fragments of all queries executed by a querier at the same time on each worker.")
+	f.IntVar(&cfg.MaxOutstandingPerTenant, prefix+"max-outstanding-fragments-per-tenant", 100000, "Maximum number of plan fragments per tenant waiting for a worker. Queries creating more fragments fail.")
+	f.IntVar(&cfg.RecordBufferSize, prefix+"record-buffer-size", 4, "Number of records of each plan fragment buffered before the worker executing it is paused.")
+	cfg.GRPCClientConfig.RegisterFlagsWithPrefix(prefix+"grpc-client", f)
+}
+
+// Validate validates the config.
+func (cfg *Config) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.WorkersAddress == "" {
+		return errors.New("workers address is required to execute plan fragments on workers")
+	}
+	if cfg.MaxConcurrentPerWorker <= 0 {
+		return errors.New("max concurrent fragments per worker must be greater than 0")
+	}
+	return cfg.GRPCClientConfig.Validate()
+}
+
+// errNoWorkers is returned for fragments submitted while no worker is
+// resolved from the workers address.
+var errNoWorkers = errors.New("no workers available to execute plan fragments")
+
+// Runner is an [executor.FragmentRunner] executing plan fragments on a pool of
+// workers.
+//
+// Fragments are put into a per-tenant queue and handed out to the workers in
+// the order they were submitted. Each worker executes up to
+// MaxConcurrentPerWorker fragments at the same time. Workers are paused when
+// the records of a fragment are not read, so that fragments are only executed
+// as fast as their results are consumed.
+type Runner struct {
+	services.Service
+
+	cfg    Config
+	logger log.Logger
+
+	queue              *queue.RequestQueue
+	subservices        *services.Manager
+	subservicesWatcher *services.FailureWatcher
+
+	mtx     sync.Mutex
+	workers map[string]*worker
+
+	fragments *prometheus.CounterVec
+	duration  prometheus.Histogram
+}
+
+var (
+	_ executor.FragmentRunner = (*Runner)(nil)
+	_ util.DNSNotifications   = (*Runner)(nil)
+)
+
+// unlimitedConsumers allows all workers to execute the fragments of every
+// tenant.
+type unlimitedConsumers struct{}
+
+func (unlimitedConsumers) MaxConsumers(string, int) int { return 0 }
+
+// NewRunner returns a runner executing fragments on the workers resolved from
+// cfg.WorkersAddress.
+func NewRunner(cfg Config, reg prometheus.Registerer, logger log.Logger) (*Runner, error) {
+	r := &Runner{
+		cfg:     cfg,
+		logger:  logger,
+		workers: make(map[string]*worker),
+		queue: queue.NewRequestQueue(
+			cfg.MaxOutstandingPerTenant,
+			0,
+			unlimitedConsumers{},
+			queue.NewMetrics(reg, constants.Loki, "engine_v2_fragments"),
+		),
+		fragments: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: constants.Loki,
+			Name:      "engine_v2_fragments_total",
+			Help:      "Total number of plan fragments executed on workers.",
+		}, []string{"status"}),
+		duration: promauto.With(reg).NewHistogram(prometheus.HistogramOpts{
+			Namespace: constants.Loki,
+			Name:      "engine_v2_fragment_duration_seconds",
+			Help:      "Time spent executing plan fragments on workers, including the time workers were paused.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+	}
+
+	watcher, err := util.NewDNSWatcher(cfg.WorkersAddress, cfg.DNSLookupPeriod, r)
+	if err != nil {
+		return nil, err
+	}
+	r.subservices, err = services.NewManager(r.queue, watcher)
+	if err != nil {
+		return nil, err
+	}
+	r.subservicesWatcher = services.NewFailureWatcher()
+	r.subservicesWatcher.WatchManager(r.subservices)
+
+	r.Service = services.NewBasicService(r.starting, r.running, r.stopping).WithName("engine fragment runner")
+	return r, nil
+}
+
+func (r *Runner) starting(ctx context.Context) error {
+	return services.StartManagerAndAwaitHealthy(ctx, r.subservices)
+}
+
+func (r *Runner) running(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-r.subservicesWatcher.Chan():
+		return fmt.Errorf("engine fragment runner subservice failed: %w", err)
+	}
+}
+
+func (r *Runner) stopping(_ error) error {
+	err := services.StopManagerAndAwaitStopped(context.Background(), r.subservices)
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	for addr, w := range r.workers {
+		w.stop()
+		delete(r.workers, addr)
+	}
+	return err
+}
+
+// AddressAdded implements [util.DNSNotifications]. It starts executing
+// fragments on the worker at address.
+func (r *Runner) AddressAdded(address string) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if _, ok := r.workers[address]; ok {
+		return
+	}
+
+	level.Info(r.logger).Log("msg", "adding plan fragment worker", "addr", address)
+	w, err := r.startWorker(address)
+	if err != nil {
+		level.Error(r.logger).Log("msg", "failed to connect to plan fragment worker", "addr", address, "err", err)
+		return
+	}
+	r.workers[address] = w
+}
+
+// AddressRemoved implements [util.DNSNotifications]. Fragments being executed
+// on the worker at address are canceled.
+func (r *Runner) AddressRemoved(address string) {
+	r.mtx.Lock()
+	w, ok := r.workers[address]
+	delete(r.workers, address)
+	r.mtx.Unlock()
+
+	if ok {
+		level.Info(r.logger).Log("msg", "removing plan fragment worker", "addr", address)
+		w.stop()
+	}
+}
+
+// RunFragment implements [executor.FragmentRunner]. The fragment is queued
+// for execution immediately, and its records are read from the returned
+// pipeline. Reading the pipeline fails right away if there are no workers.
+func (r *Runner) RunFragment(ctx context.Context, fragment *physical.Plan) executor.Pipeline {
+	ctx, cancel := context.WithCancel(ctx)
+	t := &task{
+		ctx:     ctx,
+		cancel:  cancel,
+		records: make(chan arrow.Record, r.cfg.RecordBufferSize),
+		created: time.Now(),
+	}
+
+	plan, err := physical.MarshalPlan(fragment)
+	if err != nil {
+		t.finish(fmt.Errorf("encoding plan fragment: %w", err))
+		return t
+	}
+	t.plan = plan
+
+	tenantID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		t.finish(fmt.Errorf("missing org ID: %w", err))
+		return t
+	}
+
+	r.mtx.Lock()
+	workers := len(r.workers)
+	r.mtx.Unlock()
+	if workers == 0 {
+		t.finish(errNoWorkers)
+		return t
+	}
+	if err := r.queue.Enqueue(tenantID, nil, t, nil); err != nil {
+		t.finish(fmt.Errorf("queueing plan fragment: %w", err))
+		return t
+	}
+	return t
+}
+
+type worker struct {
+	conn   *grpc.ClientConn
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func (r *Runner) startWorker(address string) (*worker, error) {
+	opts, err := r.cfg.GRPCClientConfig.DialOption(
+		nil,
+		[]grpc.StreamClientInterceptor{middleware.StreamClientUserHeaderInterceptor},
+		middleware.NoOpInvalidClusterValidationReporter,
+	)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := grpc.NewClient(address, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &worker{conn: conn, cancel: cancel}
+	client := distributedpb.NewFragmentExecutorClient(conn)
+
+	for i := 0; i < r.cfg.MaxConcurrentPerWorker; i++ {
+		w.wg.Add(1)
+		go func() {
+			defer w.wg.Done()
+			r.processFragments(ctx, address, client)
+		}()
+	}
+	return w, nil
+}
+
+// stop stops executing fragments and waits for the fragments being executed
+// to be canceled.
+func (w *worker) stop() {
+	w.cancel()
+	w.wg.Wait()
+	_ = w.conn.Close()
+}
+
+// processFragments executes fragments from the queue on the worker until ctx
+// is canceled.
+func (r *Runner) processFragments(ctx context.Context, address string, client distributedpb.FragmentExecutorClient) {
+	r.queue.RegisterConsumerConnection(address)
+	defer r.queue.UnregisterConsumerConnection(address)
+
+	last := queue.StartIndex
+	for {
+		req, idx, err := r.queue.Dequeue(ctx, last, address)
+		if err != nil {
+			// The worker was removed or the queue stopped.
+			return
+		}
+		last = idx
+
+		t := req.(*task)
+		if !t.start() {
+			// The pipeline was closed while the fragment was queued.
+			r.fragments.WithLabelValues("canceled").Inc()
+			continue
+		}
+		err = t.execute(ctx, client)
+		t.finish(err)
+
+		switch {
+		case err == nil:
+			r.fragments.WithLabelValues("success").Inc()
+		case t.ctx.Err() != nil:
+			r.fragments.WithLabelValues("canceled").Inc()
+		default:
+			r.fragments.WithLabelValues("failure").Inc()
+			level.Warn(r.logger).Log("msg", "plan fragment failed", "addr", address, "err", err)
+		}
+		r.duration.Observe(time.Since(t.created).Seconds())
+	}
+}
+
+// task is a plan fragment queued for execution. It implements
+// [executor.Pipeline] to read the records of the fragment.
+type task struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	plan    []byte
+	created time.Time
+
+	records chan arrow.Record
+	// err is set before records is closed.
+	err      error
+	finished sync.Once
+
+	mtx sync.Mutex
+	// started is set once a worker executes the fragment, and closed once the
+	// pipeline is closed. Fragments closed before they started are never
+	// executed.
+	started, closed bool
+}
+
+var _ executor.Pipeline = (*task)(nil)
+
+// execute executes the fragment on the worker. It stops when either the
+// worker or the fragment is canceled.
+func (t *task) execute(workerCtx context.Context, client distributedpb.FragmentExecutorClient) error {
+	if err := t.ctx.Err(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(t.ctx)
+	defer cancel()
+	stop := context.AfterFunc(workerCtx, cancel)
+	defer stop()
+
+	stream, err := client.Execute(ctx, &distributedpb.ExecuteRequest{Plan: t.plan})
+	if err != nil {
+		return err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		rec, err := decodeRecord(resp.Record)
+		if err != nil {
+			return err
+		}
+		select {
+		case t.records <- rec:
+		case <-ctx.Done():
+			rec.Release()
+			return ctx.Err()
+		}
+	}
+}
+
+// start marks the fragment as executed by a worker. It returns false if the
+// pipeline was closed while the fragment was queued.
+func (t *task) start() bool {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.closed {
+		return false
+	}
+	t.started = true
+	return true
+}
+
+func (t *task) finish(err error) {
+	t.finished.Do(func() {
+		t.err = err
+		close(t.records)
+	})
+}
+
+// Read implements [executor.Pipeline].
+func (t *task) Read(ctx context.Context) (arrow.Record, error) {
+	select {
+	case rec, ok := <-t.records:
+		if !ok {
+			if t.err != nil {
+				return nil, t.err
+			}
+			return nil, executor.EOF
+		}
+		return rec, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Close implements [executor.Pipeline]. It cancels the fragment if it is
+// still queued or executing.
+func (t *task) Close() {
+	t.cancel()
+
+	t.mtx.Lock()
+	t.closed = true
+	started := t.started
+	t.mtx.Unlock()
+	if !started {
+		// Workers skip the queued fragment, so it is finished here.
+		t.finish(context.Canceled)
+	}
+
+	// Release records that were buffered but not read. The channel is closed
+	// as soon as the fragment stops executing.
+	go func() {
+		for rec := range t.records {
+			rec.Release()
+		}
+	}()
+}
@@ -0,0 +1,218 @@
+package distributed
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/middleware"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/grafana/loki/v3/pkg/engine/internal/distributed/distributedpb"
+	"github.com/grafana/loki/v3/pkg/engine/internal/executor"
+	"github.com/grafana/loki/v3/pkg/engine/internal/planner/physical"
+	"github.com/grafana/loki/v3/pkg/util/rangeio"
+)
+
+// staticServer responds to every fragment with the same records.
+type staticServer struct {
+	records []arrow.Record
+	tenants chan string
+}
+
+func (s *staticServer) Execute(req *distributedpb.ExecuteRequest, srv distributedpb.FragmentExecutor_ExecuteServer) error {
+	if _, err := physical.UnmarshalPlan(req.Plan); err != nil {
+		return err
+	}
+	tenantID, err := user.ExtractOrgID(srv.Context())
+	if err != nil {
+		return err
+	}
+	s.tenants <- tenantID
+
+	for _, rec := range s.records {
+		var buf bytes.Buffer
+		if err := encodeRecord(&buf, rec); err != nil {
+			return err
+		}
+		if err := srv.Send(&distributedpb.ExecuteResponse{Record: buf.Bytes()}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startWorker(t *testing.T, srv distributedpb.FragmentExecutorServer) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := grpc.NewServer(grpc.StreamInterceptor(middleware.StreamServerUserHeaderInterceptor))
+	distributedpb.RegisterFragmentExecutorServer(s, srv)
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+
+	return lis.Addr().String()
+}
+
+func startRunner(t *testing.T, address string, concurrency int) *Runner {
+	t.Helper()
+
+	var cfg Config
+	cfg.RegisterFlagsWithPrefix("", flag.NewFlagSet("", flag.PanicOnError))
+	cfg.Enabled = true
+	cfg.WorkersAddress = address
+	cfg.DNSLookupPeriod = 100 * time.Millisecond
+	cfg.MaxConcurrentPerWorker = concurrency
+	require.NoError(t, cfg.Validate())
+
+	runner, err := NewRunner(cfg, prometheus.NewRegistry(), log.NewNopLogger())
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), runner))
+	t.Cleanup(func() {
+		require.NoError(t, services.StopAndAwaitTerminated(context.Background(), runner))
+	})
+
+	// Fragments fail until the workers address is resolved.
+	require.Eventually(t, func() bool {
+		runner.mtx.Lock()
+		defer runner.mtx.Unlock()
+		return len(runner.workers) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	return runner
+}
+
+// scanFragment returns a fragment with a single DataObjScan.
+func scanFragment(t *testing.T) *physical.Plan {
+	t.Helper()
+
+	plan, err := physical.UnmarshalPlan([]byte(`{"nodes":[{"type":"DataObjScan","node":{"location":"obj","section":0}}]}`))
+	require.NoError(t, err)
+	return plan
+}
+
+func TestRunner(t *testing.T) {
+	rec1 := testRecord(t, "foo", "bar")
+	defer rec1.Release()
+	rec2 := testRecord(t, "baz")
+	defer rec2.Release()
+
+	srv := &staticServer{records: []arrow.Record{rec1, rec2}, tenants: make(chan string, 10)}
+	runner := startRunner(t, startWorker(t, srv), 2)
+
+	ctx := user.InjectOrgID(context.Background(), "tenant")
+	pipelines := []executor.Pipeline{
+		runner.RunFragment(ctx, scanFragment(t)),
+		runner.RunFragment(ctx, scanFragment(t)),
+	}
+	for _, pipeline := range pipelines {
+		for _, expected := range []arrow.Record{rec1, rec2} {
+			rec, err := pipeline.Read(ctx)
+			require.NoError(t, err)
+			require.True(t, array.RecordEqual(expected, rec))
+			rec.Release()
+		}
+		_, err := pipeline.Read(ctx)
+		require.ErrorIs(t, err, executor.EOF)
+		pipeline.Close()
+
+		require.Equal(t, "tenant", <-srv.tenants)
+	}
+}
+
+func TestRunner_MissingOrgID(t *testing.T) {
+	srv := &staticServer{tenants: make(chan string, 10)}
+	runner := startRunner(t, startWorker(t, srv), 2)
+
+	pipeline := runner.RunFragment(context.Background(), scanFragment(t))
+	defer pipeline.Close()
+	_, err := pipeline.Read(context.Background())
+	require.ErrorContains(t, err, "missing org ID")
+}
+
+func TestRunner_ExecutionError(t *testing.T) {
+	// Without a bucket, executing the DataObjScan of the fragment fails.
+	srv := NewServer(executor.Config{BatchSize: 100}, rangeio.DefaultConfig, log.NewNopLogger())
+	runner := startRunner(t, startWorker(t, srv), 2)
+
+	ctx := user.InjectOrgID(context.Background(), "tenant")
+	pipeline := runner.RunFragment(ctx, scanFragment(t))
+	defer pipeline.Close()
+	_, err := pipeline.Read(ctx)
+	require.ErrorContains(t, err, "no object store bucket configured")
+	require.False(t, errors.Is(err, executor.EOF))
+}
+
+func TestRunner_Close(t *testing.T) {
+	rec := testRecord(t, "foo")
+	defer rec.Release()
+
+	// More records than can be buffered pause the worker until the pipeline
+	// is closed.
+	records := make([]arrow.Record, 100)
+	for i := range records {
+		records[i] = rec
+	}
+	srv := &staticServer{records: records, tenants: make(chan string, 10)}
+	runner := startRunner(t, startWorker(t, srv), 1)
+
+	ctx := user.InjectOrgID(context.Background(), "tenant")
+	pipeline := runner.RunFragment(ctx, scanFragment(t))
+	first, err := pipeline.Read(ctx)
+	require.NoError(t, err)
+	first.Release()
+	pipeline.Close()
+
+	// The only worker slot is free to execute the next fragment.
+	pipeline = runner.RunFragment(ctx, scanFragment(t))
+	defer pipeline.Close()
+	next, err := pipeline.Read(ctx)
+	require.NoError(t, err)
+	next.Release()
+}
+
+func TestRunner_NoWorkers(t *testing.T) {
+	var cfg Config
+	cfg.RegisterFlagsWithPrefix("", flag.NewFlagSet("", flag.PanicOnError))
+	cfg.WorkersAddress = "localhost:9095"
+	runner, err := NewRunner(cfg, prometheus.NewRegistry(), log.NewNopLogger())
+	require.NoError(t, err)
+
+	ctx := user.InjectOrgID(context.Background(), "tenant")
+	pipeline := runner.RunFragment(ctx, scanFragment(t))
+	defer pipeline.Close()
+	_, err = pipeline.Read(ctx)
+	require.ErrorIs(t, err, errNoWorkers)
+}
+
+func TestRunner_CloseQueued(t *testing.T) {
+	var cfg Config
+	cfg.RegisterFlagsWithPrefix("", flag.NewFlagSet("", flag.PanicOnError))
+	cfg.WorkersAddress = "localhost:9095"
+	runner, err := NewRunner(cfg, prometheus.NewRegistry(), log.NewNopLogger())
+	require.NoError(t, err)
+	// The worker never dequeues the fragment.
+	runner.workers["localhost:9095"] = &worker{}
+
+	ctx := user.InjectOrgID(context.Background(), "tenant")
+	pipeline := runner.RunFragment(ctx, scanFragment(t))
+	pipeline.Close()
+
+	// The queued fragment is finished when the pipeline is closed, and skipped
+	// by workers.
+	_, err = pipeline.Read(context.Background())
+	require.ErrorIs(t, err, context.Canceled)
+	require.False(t, pipeline.(*task).start())
+}
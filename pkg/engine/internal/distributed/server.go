@@ -0,0 +1,78 @@
+package distributed
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/httpgrpc"
+
+	"github.com/grafana/loki/v3/pkg/engine/internal/distributed/distributedpb"
+	"github.com/grafana/loki/v3/pkg/engine/internal/executor"
+	"github.com/grafana/loki/v3/pkg/engine/internal/planner/physical"
+	util_log "github.com/grafana/loki/v3/pkg/util/log"
+	"github.com/grafana/loki/v3/pkg/util/rangeio"
+)
+
+// Server executes plan fragments on behalf of other queriers and streams the
+// resulting records back.
+type Server struct {
+	cfg         executor.Config
+	rangeConfig rangeio.Config
+	logger      log.Logger
+}
+
+var _ distributedpb.FragmentExecutorServer = (*Server)(nil)
+
+// NewServer returns a server executing fragments with the given executor
+// configuration. Fragments are always executed locally, so the FragmentRunner
+// of cfg is ignored.
+func NewServer(cfg executor.Config, rangeConfig rangeio.Config, logger log.Logger) *Server {
+	cfg.FragmentRunner = nil
+	return &Server{
+		cfg:         cfg,
+		rangeConfig: rangeConfig,
+		logger:      logger,
+	}
+}
+
+// Execute implements distributedpb.FragmentExecutorServer.
+func (s *Server) Execute(req *distributedpb.ExecuteRequest, srv distributedpb.FragmentExecutor_ExecuteServer) error {
+	ctx := srv.Context()
+	logger := util_log.WithContext(ctx, s.logger)
+
+	plan, err := physical.UnmarshalPlan(req.Plan)
+	if err != nil {
+		return httpgrpc.Errorf(http.StatusBadRequest, "invalid plan fragment: %s", err)
+	}
+
+	// Inject the range config into the context for any calls to
+	// [rangeio.ReadRanges] to make use of.
+	ctx = rangeio.WithConfig(ctx, &s.rangeConfig)
+
+	pipeline := executor.Run(ctx, s.cfg, plan, logger)
+	defer pipeline.Close()
+
+	var buf bytes.Buffer
+	for {
+		rec, err := pipeline.Read(ctx)
+		if errors.Is(err, executor.EOF) {
+			return nil
+		} else if err != nil {
+			level.Warn(logger).Log("msg", "failed to execute plan fragment", "err", err)
+			return err
+		}
+
+		buf.Reset()
+		err = encodeRecord(&buf, rec)
+		rec.Release()
+		if err != nil {
+			return err
+		}
+		if err := srv.Send(&distributedpb.ExecuteResponse{Record: buf.Bytes()}); err != nil {
+			return err
+		}
+	}
+}
@@ -10,10 +10,12 @@ import (
 	"time"
 
 	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/memory"
 	"github.com/apache/arrow-go/v18/arrow/scalar"
 	"github.com/go-kit/log"
 
+	"github.com/grafana/loki/v3/pkg/dataobj/metastore/multitenancy"
 	"github.com/grafana/loki/v3/pkg/dataobj/sections/logs"
 	"github.com/grafana/loki/v3/pkg/dataobj/sections/streams"
 	"github.com/grafana/loki/v3/pkg/engine/internal/planner/physical"
@@ -27,6 +29,7 @@ type dataobjScanOptions struct {
 	StreamIDs      []int64                     // Stream IDs to match from logs sections.
 	Predicates     []logs.Predicate            // Predicate to apply to the logs.
 	Projections    []physical.ColumnExpression // Columns to include. An empty slice means all columns.
+	Tenant         string                      // Value of the tenant label column. An empty string means no tenant label column.
 
 	Allocator memory.Allocator // Allocator to use for reading sections and building records.
 
@@ -41,6 +44,7 @@ type dataobjScan struct {
 	initializedAt   time.Time
 	streams         *streamsView
 	streamsInjector *streamInjector
+	tenantColumn    bool
 	reader          *logs.Reader
 	desiredSchema   *arrow.Schema
 }
@@ -84,6 +88,7 @@ func (s *dataobjScan) init() error {
 		return fmt.Errorf("initializing logs: %w", err)
 	}
 
+	s.tenantColumn = s.opts.Tenant != "" && projectsTenantLabel(s.opts.Projections)
 	s.initialized = true
 	s.initializedAt = time.Now().UTC()
 	return nil
@@ -368,15 +373,66 @@ func (s *dataobjScan) read(ctx context.Context) (arrow.Record, error) {
 	}
 	defer rec.Release()
 
-	if s.streamsInjector == nil {
-		// No streams injector needed, so we return the record as-is.
-		// We add an extra retain to counteract the Release() call above (for ease
-		// of readability).
-		rec.Retain()
-		return rec, nil
+	if s.streamsInjector != nil {
+		rec, err = s.streamsInjector.Inject(ctx, rec)
+		if err != nil {
+			return nil, err
+		}
+		defer rec.Release()
+	}
+
+	if s.tenantColumn {
+		return withTenantLabel(s.opts.Allocator, rec, s.opts.Tenant), nil
 	}
 
-	return s.streamsInjector.Inject(ctx, rec)
+	// We add an extra retain to counteract the Release() calls above (for
+	// ease of readability).
+	rec.Retain()
+	return rec, nil
+}
+
+// projectsTenantLabel returns true if the projections include the
+// [multitenancy.TenantLabel] label, or all columns.
+func projectsTenantLabel(projections []physical.ColumnExpression) bool {
+	if len(projections) == 0 {
+		return true
+	}
+	for _, projection := range projections {
+		expr, ok := projection.(*physical.ColumnExpr)
+		if !ok {
+			continue
+		}
+		if expr.Ref.Type != types.ColumnTypeLabel && expr.Ref.Type != types.ColumnTypeAmbiguous {
+			continue
+		}
+		if expr.Ref.Column == multitenancy.TenantLabel {
+			return true
+		}
+	}
+	return false
+}
+
+// withTenantLabel returns a copy of rec with a [multitenancy.TenantLabel]
+// label column set to tenant. The tenant label isn't stored in data objects,
+// it is only added to the records of queries across several tenants.
+func withTenantLabel(alloc memory.Allocator, rec arrow.Record, tenant string) arrow.Record {
+	builder := array.NewStringBuilder(alloc)
+	defer builder.Release()
+	builder.Reserve(int(rec.NumRows()))
+	for range rec.NumRows() {
+		builder.Append(tenant)
+	}
+	arr := builder.NewArray()
+	defer arr.Release()
+
+	ident := semconv.NewIdentifier(multitenancy.TenantLabel, types.ColumnTypeLabel, types.Loki.String)
+	var (
+		fields = append([]arrow.Field{semconv.FieldFromIdent(ident, true)}, rec.Schema().Fields()...)
+		arrs   = append([]arrow.Array{arr}, rec.Columns()...)
+		md     = rec.Schema().Metadata()
+	)
+	schema := arrow.NewSchemaWithEndian(fields, &md, rec.Schema().Endianness())
+	return array.NewRecord(schema, arrs, rec.NumRows())
 }
 
 // Close closes s and releases all resources.
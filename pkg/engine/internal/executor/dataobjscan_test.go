@@ -192,6 +192,66 @@ prod`
 
 		AssertPipelinesEqual(t, pipeline, NewBufferedPipeline(expectRecord))
 	})
+
+	t.Run("Tenant label", func(t *testing.T) {
+		pipeline := newDataobjScanPipeline(dataobjScanOptions{
+			StreamsSection: streamsSection,
+			LogsSection:    logsSection,
+			StreamIDs:      []int64{1, 2}, // All streams
+			Projections: []physical.ColumnExpression{
+				&physical.ColumnExpr{Ref: types.ColumnRef{Column: "__tenant_id__", Type: types.ColumnTypeAmbiguous}},
+				&physical.ColumnExpr{Ref: types.ColumnRef{Column: "service", Type: types.ColumnTypeLabel}},
+			},
+			Tenant:    "tenant",
+			BatchSize: 512,
+		}, log.NewNopLogger())
+
+		expectFields := []arrow.Field{
+			semconv.FieldFromFQN("utf8.label.__tenant_id__", true),
+			semconv.FieldFromFQN("utf8.label.service", true),
+		}
+
+		expectCSV := `tenant,loki
+tenant,loki
+tenant,notloki
+tenant,notloki`
+
+		expectRecord, err := CSVToArrow(expectFields, expectCSV)
+		require.NoError(t, err)
+		defer expectRecord.Release()
+
+		AssertPipelinesEqual(t, pipeline, NewBufferedPipeline(expectRecord))
+	})
+
+	t.Run("Tenant label not projected", func(t *testing.T) {
+		pipeline := newDataobjScanPipeline(dataobjScanOptions{
+			StreamsSection: streamsSection,
+			LogsSection:    logsSection,
+			StreamIDs:      []int64{1, 2}, // All streams
+			Projections: []physical.ColumnExpression{
+				&physical.ColumnExpr{Ref: types.ColumnRef{Column: "timestamp", Type: types.ColumnTypeBuiltin}},
+			},
+			Tenant:    "tenant",
+			BatchSize: 512,
+		}, log.NewNopLogger())
+
+		// Without label columns the stream IDs aren't resolved to labels.
+		expectFields := []arrow.Field{
+			semconv.FieldFromFQN("int64.generated.stream_id", true),
+			semconv.FieldFromFQN("timestamp_ns.builtin.timestamp", true),
+		}
+
+		expectCSV := `1,1970-01-01 00:00:10
+1,1970-01-01 00:00:05
+2,1970-01-01 00:00:03
+2,1970-01-01 00:00:02`
+
+		expectRecord, err := CSVToArrow(expectFields, expectCSV)
+		require.NoError(t, err)
+		defer expectRecord.Release()
+
+		AssertPipelinesEqual(t, pipeline, NewBufferedPipeline(expectRecord))
+	})
 }
 
 func Test_dataobjScan_DuplicateColumns(t *testing.T) {
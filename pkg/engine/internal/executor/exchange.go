@@ -0,0 +1,39 @@
+package executor
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/loki/v3/pkg/engine/internal/planner/physical"
+)
+
+// FragmentRunner runs the plan fragments of [physical.Exchange] nodes, for
+// example by sending them to remote workers.
+type FragmentRunner interface {
+	// RunFragment starts running the fragment and returns a pipeline reading
+	// its results. Closing the pipeline stops running the fragment.
+	RunFragment(ctx context.Context, fragment *physical.Plan) Pipeline
+}
+
+func (c *Context) executeExchange(ctx context.Context, node *physical.Exchange, inputs []Pipeline) Pipeline {
+	if len(inputs) > 0 {
+		return errorPipeline(ctx, errors.New("exchange expects no inputs"))
+	}
+	if node.Fragment == nil {
+		return errorPipeline(ctx, errors.New("exchange has no fragment"))
+	}
+
+	if c.fragmentRunner != nil {
+		return c.fragmentRunner.RunFragment(ctx, node.Fragment)
+	}
+
+	// Without a runner the fragment is executed as part of the plan it
+	// belongs to.
+	root, err := node.Fragment.Root()
+	if err != nil {
+		return errorPipeline(ctx, err)
+	}
+	local := *c
+	local.plan = node.Fragment
+	return local.execute(ctx, root)
+}
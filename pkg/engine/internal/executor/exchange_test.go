@@ -0,0 +1,66 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/engine/internal/planner/physical"
+	"github.com/grafana/loki/v3/pkg/engine/internal/types"
+)
+
+type fakeFragmentRunner struct {
+	fragments []*physical.Plan
+	records   []arrow.Record
+}
+
+func (r *fakeFragmentRunner) RunFragment(_ context.Context, fragment *physical.Plan) Pipeline {
+	r.fragments = append(r.fragments, fragment)
+	return NewBufferedPipeline(r.records...)
+}
+
+func TestExecuteExchange(t *testing.T) {
+	t.Run("with inputs", func(t *testing.T) {
+		ctx := t.Context()
+		c := &Context{}
+		pipeline := c.executeExchange(ctx, &physical.Exchange{Fragment: &physical.Plan{}}, []Pipeline{emptyPipeline()})
+		_, err := pipeline.Read(ctx)
+		require.ErrorContains(t, err, "exchange expects no inputs")
+	})
+
+	t.Run("without fragment", func(t *testing.T) {
+		ctx := t.Context()
+		c := &Context{}
+		pipeline := c.executeExchange(ctx, &physical.Exchange{}, nil)
+		_, err := pipeline.Read(ctx)
+		require.ErrorContains(t, err, "exchange has no fragment")
+	})
+
+	t.Run("executes fragment locally without runner", func(t *testing.T) {
+		ctx := t.Context()
+		c := &Context{}
+		pipeline := c.executeExchange(ctx, &physical.Exchange{Fragment: &physical.Plan{}}, nil)
+		_, err := pipeline.Read(ctx)
+		require.ErrorContains(t, err, "plan has no root node")
+	})
+
+	t.Run("runs fragment with runner", func(t *testing.T) {
+		ctx := t.Context()
+
+		fields := []arrow.Field{{Name: "name", Type: types.Arrow.String}}
+		record, err := CSVToArrow(fields, "Alice\nBob")
+		require.NoError(t, err)
+		defer record.Release()
+
+		runner := &fakeFragmentRunner{records: []arrow.Record{record}}
+		c := &Context{fragmentRunner: runner}
+		fragment := &physical.Plan{}
+		pipeline := c.executeExchange(ctx, &physical.Exchange{Fragment: fragment}, nil)
+		defer pipeline.Close()
+
+		require.Equal(t, []*physical.Plan{fragment}, runner.fragments)
+		AssertPipelinesEqual(t, pipeline, NewBufferedPipeline(record))
+	})
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/apache/arrow-go/v18/arrow/memory"
 	"github.com/go-kit/log"
+	"github.com/grafana/dskit/tenant"
 	"github.com/grafana/dskit/user"
 	"github.com/thanos-io/objstore"
 	"go.opentelemetry.io/otel"
@@ -14,6 +16,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/grafana/loki/v3/pkg/dataobj"
+	"github.com/grafana/loki/v3/pkg/dataobj/metastore/multitenancy"
 	"github.com/grafana/loki/v3/pkg/dataobj/sections/logs"
 	"github.com/grafana/loki/v3/pkg/dataobj/sections/streams"
 	"github.com/grafana/loki/v3/pkg/engine/internal/planner/physical"
@@ -26,6 +29,13 @@ type Config struct {
 	Bucket    objstore.Bucket
 
 	MergePrefetchCount int
+
+	// FragmentRunner runs the fragments of Exchange nodes. Fragments are
+	// executed locally if FragmentRunner is nil.
+	FragmentRunner FragmentRunner
+
+	// IngesterQuerier reads the logs of IngesterScan nodes.
+	IngesterQuerier IngesterQuerier
 }
 
 func Run(ctx context.Context, cfg Config, plan *physical.Plan, logger log.Logger) Pipeline {
@@ -34,6 +44,8 @@ func Run(ctx context.Context, cfg Config, plan *physical.Plan, logger log.Logger
 		batchSize:          cfg.BatchSize,
 		mergePrefetchCount: cfg.MergePrefetchCount,
 		bucket:             cfg.Bucket,
+		fragmentRunner:     cfg.FragmentRunner,
+		ingesterQuerier:    cfg.IngesterQuerier,
 		logger:             logger,
 	}
 	if plan == nil {
@@ -56,6 +68,8 @@ type Context struct {
 	bucket    objstore.Bucket
 
 	mergePrefetchCount int
+	fragmentRunner     FragmentRunner
+	ingesterQuerier    IngesterQuerier
 }
 
 func (c *Context) execute(ctx context.Context, node physical.Node) Pipeline {
@@ -76,6 +90,12 @@ func (c *Context) execute(ctx context.Context, node physical.Node) Pipeline {
 		return newLazyPipeline(func(ctx context.Context, _ []Pipeline) Pipeline {
 			return tracePipeline("physical.DataObjScan", c.executeDataObjScan(ctx, n))
 		}, inputs)
+	case *physical.IngesterScan:
+		// Ingesters are queried when the pipeline is first read, like the
+		// objects of DataObjScan nodes.
+		return newLazyPipeline(func(ctx context.Context, _ []Pipeline) Pipeline {
+			return tracePipeline("physical.IngesterScan", c.executeIngesterScan(ctx, n))
+		}, inputs)
 
 	case *physical.SortMerge:
 		return tracePipeline("physical.SortMerge", c.executeSortMerge(ctx, n, inputs))
@@ -99,11 +119,35 @@ func (c *Context) execute(ctx context.Context, node physical.Node) Pipeline {
 		return tracePipeline("physical.ColumnCompat", c.executeColumnCompat(ctx, n, inputs))
 	case *physical.Parallelize:
 		return tracePipeline("physical.Parallelize", c.executeParallelize(ctx, n, inputs))
+	case *physical.Exchange:
+		return tracePipeline("physical.Exchange", c.executeExchange(ctx, n, inputs))
 	default:
 		return errorPipeline(ctx, fmt.Errorf("invalid node type: %T", node))
 	}
 }
 
+// scanTenant returns the tenant whose sections node reads: the tenant of
+// the node for queries across several tenants, which must be one of the
+// tenants of the query, or the tenant of the query otherwise.
+func scanTenant(ctx context.Context, node *physical.DataObjScan) (string, error) {
+	if node.Tenant == "" {
+		tenantID, err := user.ExtractOrgID(ctx)
+		if err != nil {
+			return "", fmt.Errorf("missing org ID: %w", err)
+		}
+		return tenantID, nil
+	}
+
+	tenants, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return "", fmt.Errorf("missing org ID: %w", err)
+	}
+	if !slices.Contains(tenants, node.Tenant) {
+		return "", fmt.Errorf("tenant %s is not queried", node.Tenant)
+	}
+	return node.Tenant, nil
+}
+
 func (c *Context) executeDataObjScan(ctx context.Context, node *physical.DataObjScan) Pipeline {
 	ctx, span := tracer.Start(ctx, "Context.executeDataObjScan", trace.WithAttributes(
 		attribute.String("location", string(node.Location)),
@@ -129,16 +173,12 @@ func (c *Context) executeDataObjScan(ctx context.Context, node *physical.DataObj
 		logsSection    *logs.Section
 	)
 
-	tenant, err := user.ExtractOrgID(ctx)
+	tenantID, err := scanTenant(ctx, node)
 	if err != nil {
-		return errorPipeline(ctx, fmt.Errorf("missing org ID: %w", err))
+		return errorPipeline(ctx, err)
 	}
 
-	for _, sec := range obj.Sections().Filter(streams.CheckSection) {
-		if sec.Tenant != tenant {
-			continue
-		}
-
+	for _, sec := range obj.Sections().Filter(multitenancy.CheckTenantSection(tenantID, streams.CheckSection)) {
 		if streamsSection != nil {
 			return errorPipeline(ctx, fmt.Errorf("multiple streams sections found in data object %q", node.Location))
 		}
@@ -159,6 +199,9 @@ func (c *Context) executeDataObjScan(ctx context.Context, node *physical.DataObj
 		if i != node.Section {
 			continue
 		}
+		if sec.Tenant != tenantID {
+			return errorPipeline(ctx, fmt.Errorf("logs section %d of data object %q belongs to another tenant", node.Section, node.Location))
+		}
 
 		var err error
 		logsSection, err = logs.Open(ctx, sec)
@@ -197,6 +240,7 @@ func (c *Context) executeDataObjScan(ctx context.Context, node *physical.DataObj
 		StreamIDs:   node.StreamIDs,
 		Predicates:  predicates,
 		Projections: node.Projections,
+		Tenant:      node.Tenant,
 
 		// TODO(rfratto): pass custom allocator
 		Allocator: memory.DefaultAllocator,
@@ -7,10 +7,12 @@ import (
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/prometheus/prometheus/model/labels"
 
 	"github.com/grafana/loki/v3/pkg/engine/internal/planner/physical"
 	"github.com/grafana/loki/v3/pkg/engine/internal/semconv"
 	"github.com/grafana/loki/v3/pkg/engine/internal/types"
+	"github.com/grafana/loki/v3/pkg/logql/log"
 )
 
 type expressionEvaluator struct{}
@@ -125,6 +127,10 @@ func (e expressionEvaluator) eval(expr physical.Expression, input arrow.Record)
 		return fn.Evaluate(lhr)
 
 	case *physical.BinaryExpr:
+		if expr.Op == types.BinaryOpSample {
+			return e.evalSample(expr, input)
+		}
+
 		lhs, err := e.eval(expr.Left, input)
 		if err != nil {
 			return nil, err
@@ -154,6 +160,82 @@ func (e expressionEvaluator) eval(expr physical.Expression, input arrow.Record)
 	return nil, fmt.Errorf("unknown expression: %v", expr)
 }
 
+// evalSample evaluates a sampling expression, keeping the rows of input that
+// are part of a sample of the ratio given by the RHS. Like the sample stage of
+// the v1 engine, rows are sampled by a hash of their stream labels, their
+// timestamp given by the LHS and their line, see [log.SampleEntry].
+func (e expressionEvaluator) evalSample(expr *physical.BinaryExpr, input arrow.Record) (ColumnVector, error) {
+	literal, ok := expr.Right.(*physical.LiteralExpr)
+	if !ok {
+		return nil, fmt.Errorf("sampling ratio must be a literal, got %v", expr.Right)
+	}
+	ratio, ok := literal.Literal.Any().(float64)
+	if !ok {
+		return nil, fmt.Errorf("sampling ratio must be a float, got %v", literal.Literal)
+	}
+
+	lhs, err := e.eval(expr.Left, input)
+	if err != nil {
+		return nil, err
+	}
+	defer lhs.Release()
+	lhsArr := lhs.ToArray()
+	defer lhsArr.Release()
+	timestamps, ok := lhsArr.(*array.Timestamp)
+	if !ok {
+		return nil, arrow.ErrType
+	}
+
+	var (
+		messages    *array.String
+		labelNames  []string
+		labelValues []*array.String
+	)
+	for idx, field := range input.Schema().Fields() {
+		ident, err := semconv.ParseFQN(field.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse column %s: %w", field.Name, err)
+		}
+		switch {
+		case ident.ColumnType() == types.ColumnTypeLabel:
+			values, ok := input.Column(idx).(*array.String)
+			if !ok {
+				return nil, fmt.Errorf("label column %s has type %s, expected string", ident.ShortName(), field.Type)
+			}
+			labelNames = append(labelNames, ident.ShortName())
+			labelValues = append(labelValues, values)
+		case ident.Equal(semconv.ColumnIdentMessage):
+			if messages, ok = input.Column(idx).(*array.String); !ok {
+				return nil, fmt.Errorf("message column has type %s, expected string", field.Type)
+			}
+		}
+	}
+	if messages == nil {
+		return nil, fmt.Errorf("sampling requires the %s column", semconv.ColumnIdentMessage.ShortName())
+	}
+
+	builder := array.NewBooleanBuilder(memory.NewGoAllocator())
+	defer builder.Release()
+
+	lbs := labels.NewScratchBuilder(len(labelNames))
+	for i := range int(input.NumRows()) {
+		if timestamps.IsNull(i) {
+			builder.Append(false)
+			continue
+		}
+		lbs.Reset()
+		for j, values := range labelValues {
+			if values.IsValid(i) && values.Value(i) != "" {
+				lbs.Add(labelNames[j], values.Value(i))
+			}
+		}
+		lbs.Sort()
+		builder.Append(log.SampleEntry(labels.StableHash(lbs.Labels()), int64(timestamps.Value(i)), unsafeBytes(messages.Value(i)), ratio))
+	}
+
+	return &Array{array: builder.NewArray()}, nil
+}
+
 // newFunc returns a new function that can evaluate an input against a binded expression.
 func (e expressionEvaluator) newFunc(expr physical.Expression) evalFunc {
 	return func(input arrow.Record) (ColumnVector, error) {
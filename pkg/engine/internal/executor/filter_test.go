@@ -1,15 +1,19 @@
 package executor
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/loki/v3/pkg/engine/internal/planner/physical"
 	"github.com/grafana/loki/v3/pkg/engine/internal/semconv"
 	"github.com/grafana/loki/v3/pkg/engine/internal/types"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
 	"github.com/grafana/loki/v3/pkg/util/arrowtest"
 )
 
@@ -347,3 +351,80 @@ func TestNewFilterPipeline(t *testing.T) {
 		require.ElementsMatch(t, expectedRows, rows)
 	})
 }
+
+func TestFilterPipeline_Sample(t *testing.T) {
+	var (
+		colTs      = "timestamp_ns.builtin.timestamp"
+		colMessage = "utf8.builtin.message"
+		colApp     = "utf8.label.app"
+		colEnv     = "utf8.label.env"
+	)
+	fields := []arrow.Field{
+		semconv.FieldFromFQN(colTs, true),
+		semconv.FieldFromFQN(colMessage, true),
+		semconv.FieldFromFQN(colApp, true),
+		semconv.FieldFromFQN(colEnv, true),
+	}
+	streams := []labels.Labels{
+		labels.FromStrings("app", "foo", "env", "prod"),
+		labels.FromStrings("app", "bar"),
+	}
+
+	var inputRows arrowtest.Rows
+	for i := range 1000 {
+		stream := streams[i%len(streams)]
+		inputRows = append(inputRows, arrowtest.Row{
+			colTs:      time.Unix(0, int64(i/len(streams))).UTC(),
+			colMessage: fmt.Sprintf("line %d", i%10),
+			colApp:     stream.Get("app"),
+			colEnv:     nilIfEmpty(stream.Get("env")),
+		})
+	}
+
+	// The v1 engine samples the same rows, given the same stream labels,
+	// timestamps and lines.
+	expr, err := syntax.ParseLogSelector(`{app=~".+"} | sample 0.3`, true)
+	require.NoError(t, err)
+	v1, err := expr.Pipeline()
+	require.NoError(t, err)
+	var expected arrowtest.Rows
+	for i, row := range inputRows {
+		stream := streams[i%len(streams)]
+		ts := row[colTs].(time.Time).UnixNano()
+		if _, _, ok := v1.ForStream(stream).ProcessString(ts, row[colMessage].(string), labels.EmptyLabels()); ok {
+			expected = append(expected, row)
+		}
+	}
+	require.InDelta(t, 300, len(expected), 50)
+
+	alloc := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer alloc.AssertSize(t, 0)
+
+	input := NewArrowtestPipeline(alloc, arrow.NewSchema(fields, nil), inputRows)
+	filter := &physical.Filter{
+		Predicates: []physical.Expression{
+			&physical.BinaryExpr{
+				Left:  &physical.ColumnExpr{Ref: types.ColumnRef{Column: types.ColumnNameBuiltinTimestamp, Type: types.ColumnTypeBuiltin}},
+				Right: physical.NewLiteral(0.3),
+				Op:    types.BinaryOpSample,
+			},
+		},
+	}
+	pipeline := NewFilterPipeline(filter, input, expressionEvaluator{}, alloc)
+	defer pipeline.Close()
+
+	record, err := pipeline.Read(t.Context())
+	require.NoError(t, err)
+	defer record.Release()
+
+	rows, err := arrowtest.RecordRows(record)
+	require.NoError(t, err)
+	require.Equal(t, expected, rows)
+}
+
+func nilIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,346 @@
+package executor
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/grafana/dskit/tenant"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/prometheus/model/labels"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/grafana/loki/v3/pkg/dataobj/metastore/multitenancy"
+	"github.com/grafana/loki/v3/pkg/engine/internal/planner/physical"
+	"github.com/grafana/loki/v3/pkg/engine/internal/semconv"
+	"github.com/grafana/loki/v3/pkg/engine/internal/types"
+	"github.com/grafana/loki/v3/pkg/iter"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/querier/plan"
+)
+
+// IngesterQuerier queries the ingesters for logs which are not flushed to
+// data objects yet.
+type IngesterQuerier interface {
+	SelectLogs(ctx context.Context, params logql.SelectLogParams) (iter.EntryIterator, error)
+}
+
+func (c *Context) executeIngesterScan(ctx context.Context, node *physical.IngesterScan) Pipeline {
+	ctx, span := tracer.Start(ctx, "Context.executeIngesterScan", trace.WithAttributes(
+		attribute.String("selector", node.Selector),
+		attribute.Stringer("start", node.Start),
+		attribute.Stringer("end", node.End),
+		attribute.Int("num_predicates", len(node.Predicates)),
+		attribute.Int("num_projections", len(node.Projections)),
+	))
+	defer span.End()
+
+	if c.ingesterQuerier == nil {
+		return errorPipeline(ctx, errors.New("no ingester querier configured"))
+	}
+
+	selector, err := syntax.ParseLogSelector(node.Selector, true)
+	if err != nil {
+		return errorPipeline(ctx, fmt.Errorf("parsing selector: %w", err))
+	}
+
+	tenants, _ := tenant.TenantIDs(ctx)
+	if len(tenants) <= 1 {
+		return c.ingesterScanPipeline(ctx, node, selector, "")
+	}
+
+	// Queries across several tenants read the streams of each tenant matching
+	// the tenant label matchers separately, as ingesters serve a single tenant
+	// per request.
+	tenants, matchers := multitenancy.FilterTenants(tenants, selector.Matchers())
+	selector, err = syntax.ParseLogSelector(syntax.MatchersString(matchers), true)
+	if err != nil {
+		return errorPipeline(ctx, fmt.Errorf("parsing selector: %w", err))
+	}
+
+	inputs := make([]Pipeline, 0, len(tenants))
+	for _, tenantID := range tenants {
+		inputs = append(inputs, c.ingesterScanPipeline(user.InjectOrgID(ctx, tenantID), node, selector, tenantID))
+	}
+	if len(inputs) == 0 {
+		return emptyPipeline()
+	}
+	pipeline, err := newMergePipeline(inputs, 0)
+	if err != nil {
+		return errorPipeline(ctx, err)
+	}
+	return pipeline
+}
+
+// ingesterScanPipeline returns the pipeline reading the logs of the streams
+// matching selector from the ingesters. The records have a
+// [multitenancy.TenantLabel] label column if tenantID is not empty.
+func (c *Context) ingesterScanPipeline(ctx context.Context, node *physical.IngesterScan, selector syntax.LogSelectorExpr, tenantID string) Pipeline {
+	direction := logproto.FORWARD
+	if node.Direction == physical.DESC {
+		direction = logproto.BACKWARD
+	}
+
+	req := &logproto.QueryRequest{
+		Selector: selector.String(),
+		// The end of query requests is exclusive.
+		Start:     node.Start,
+		End:       node.End.Add(time.Nanosecond),
+		Direction: direction,
+		// All matching logs are needed to evaluate the rest of the plan.
+		Limit: 0,
+		Plan:  &plan.QueryPlan{AST: selector},
+	}
+	if node.Shard.Of > 1 {
+		req.Shards = []string{node.Shard.String()}
+	}
+
+	it, err := c.ingesterQuerier.SelectLogs(ctx, logql.SelectLogParams{QueryRequest: req})
+	if err != nil {
+		return errorPipeline(ctx, fmt.Errorf("querying ingesters: %w", err))
+	}
+
+	var pipeline Pipeline = newIngesterScanPipeline(it, ingesterScanOptions{
+		Projections: node.Projections,
+		Predicates:  node.Predicates,
+		Tenant:      tenantID,
+		BatchSize:   c.batchSize,
+	})
+	if len(node.Predicates) > 0 {
+		pipeline = NewFilterPipeline(&physical.Filter{Predicates: node.Predicates}, pipeline, c.evaluator, memory.DefaultAllocator)
+	}
+	return pipeline
+}
+
+type ingesterScanOptions struct {
+	// Projections are the columns to include. An empty slice means all
+	// columns.
+	Projections []physical.ColumnExpression
+	// Predicates are applied to the records after they are read. The columns
+	// they reference are always included.
+	Predicates []physical.Expression
+	// Tenant is the value of the tenant label added to the streams. An empty
+	// string means no tenant label.
+	Tenant string
+
+	Allocator memory.Allocator
+	BatchSize int64
+}
+
+// ingesterScan converts the entries of an entry iterator to records with the
+// columns of a [dataobjScan]: stream labels, structured metadata, timestamp
+// and message.
+type ingesterScan struct {
+	it   iter.EntryIterator
+	opts ingesterScanOptions
+
+	// columns are the columns included in the records. nil means all columns.
+	columns []types.ColumnRef
+	// streams caches the parsed labels of streams.
+	streams map[string]labels.Labels
+}
+
+var _ Pipeline = (*ingesterScan)(nil)
+
+func newIngesterScanPipeline(it iter.EntryIterator, opts ingesterScanOptions) *ingesterScan {
+	if opts.Allocator == nil {
+		opts.Allocator = memory.DefaultAllocator
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+
+	var columns []types.ColumnRef
+	if len(opts.Projections) > 0 {
+		for _, projection := range opts.Projections {
+			columns = appendColumnRefs(columns, projection)
+		}
+		for _, predicate := range opts.Predicates {
+			columns = appendColumnRefs(columns, predicate)
+		}
+	}
+
+	return &ingesterScan{
+		it:      it,
+		opts:    opts,
+		columns: columns,
+		streams: make(map[string]labels.Labels),
+	}
+}
+
+// appendColumnRefs appends the columns referenced by expr to refs.
+func appendColumnRefs(refs []types.ColumnRef, expr physical.Expression) []types.ColumnRef {
+	switch expr := expr.(type) {
+	case *physical.ColumnExpr:
+		return append(refs, expr.Ref)
+	case *physical.UnaryExpr:
+		return appendColumnRefs(refs, expr.Left)
+	case *physical.BinaryExpr:
+		refs = appendColumnRefs(refs, expr.Left)
+		return appendColumnRefs(refs, expr.Right)
+	default:
+		return refs
+	}
+}
+
+// includes returns true if the column with the given name and type is part of
+// the records.
+func (s *ingesterScan) includes(name string, ct types.ColumnType) bool {
+	if s.columns == nil {
+		return true
+	}
+	for _, ref := range s.columns {
+		if ref.Column != name {
+			continue
+		}
+		if ref.Type == ct || (ref.Type == types.ColumnTypeAmbiguous && ct != types.ColumnTypeBuiltin) {
+			return true
+		}
+	}
+	return false
+}
+
+type ingesterScanColumn struct {
+	field   arrow.Field
+	builder *array.StringBuilder
+}
+
+// Read implements [Pipeline].
+func (s *ingesterScan) Read(ctx context.Context) (arrow.Record, error) {
+	var (
+		labelColumns    = make(map[string]*ingesterScanColumn)
+		metadataColumns = make(map[string]*ingesterScanColumn)
+
+		timestamps = array.NewTimestampBuilder(s.opts.Allocator, types.Arrow.Timestamp.(*arrow.TimestampType))
+		messages   = array.NewStringBuilder(s.opts.Allocator)
+	)
+	defer timestamps.Release()
+	defer messages.Release()
+	defer func() {
+		for _, col := range labelColumns {
+			col.builder.Release()
+		}
+		for _, col := range metadataColumns {
+			col.builder.Release()
+		}
+	}()
+
+	getColumn := func(columns map[string]*ingesterScanColumn, name string, ct types.ColumnType) *ingesterScanColumn {
+		if col, ok := columns[name]; ok {
+			return col
+		}
+		col := &ingesterScanColumn{
+			field:   semconv.FieldFromIdent(semconv.NewIdentifier(name, ct, types.Loki.String), true),
+			builder: array.NewStringBuilder(s.opts.Allocator),
+		}
+		columns[name] = col
+		return col
+	}
+	appendValue := func(col *ingesterScanColumn, row int, value string) {
+		if missing := row - col.builder.Len(); missing > 0 {
+			col.builder.AppendNulls(missing)
+		}
+		col.builder.Append(value)
+	}
+
+	var rows int
+	for rows < int(s.opts.BatchSize) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !s.it.Next() {
+			break
+		}
+
+		stream, err := s.streamLabels(s.it.Labels())
+		if err != nil {
+			return nil, err
+		}
+		stream.Range(func(l labels.Label) {
+			if s.includes(l.Name, types.ColumnTypeLabel) {
+				appendValue(getColumn(labelColumns, l.Name, types.ColumnTypeLabel), rows, l.Value)
+			}
+		})
+
+		entry := s.it.At()
+		for _, md := range entry.StructuredMetadata {
+			if s.includes(md.Name, types.ColumnTypeMetadata) {
+				appendValue(getColumn(metadataColumns, md.Name, types.ColumnTypeMetadata), rows, md.Value)
+			}
+		}
+		timestamps.Append(arrow.Timestamp(entry.Timestamp.UnixNano()))
+		messages.Append(entry.Line)
+		rows++
+	}
+	if err := s.it.Err(); err != nil {
+		return nil, err
+	}
+	if rows == 0 {
+		return nil, EOF
+	}
+
+	var (
+		fields []arrow.Field
+		arrs   []arrow.Array
+	)
+	defer func() {
+		for _, arr := range arrs {
+			arr.Release()
+		}
+	}()
+
+	for _, columns := range []map[string]*ingesterScanColumn{labelColumns, metadataColumns} {
+		sorted := make([]*ingesterScanColumn, 0, len(columns))
+		for _, col := range columns {
+			sorted = append(sorted, col)
+		}
+		slices.SortFunc(sorted, func(a, b *ingesterScanColumn) int { return cmp.Compare(a.field.Name, b.field.Name) })
+
+		for _, col := range sorted {
+			if missing := rows - col.builder.Len(); missing > 0 {
+				col.builder.AppendNulls(missing)
+			}
+			fields = append(fields, col.field)
+			arrs = append(arrs, col.builder.NewArray())
+		}
+	}
+	if s.includes(types.ColumnNameBuiltinTimestamp, types.ColumnTypeBuiltin) {
+		fields = append(fields, semconv.FieldFromIdent(semconv.ColumnIdentTimestamp, true))
+		arrs = append(arrs, timestamps.NewArray())
+	}
+	if s.includes(types.ColumnNameBuiltinMessage, types.ColumnTypeBuiltin) {
+		fields = append(fields, semconv.FieldFromIdent(semconv.ColumnIdentMessage, true))
+		arrs = append(arrs, messages.NewArray())
+	}
+
+	return array.NewRecord(arrow.NewSchema(fields, nil), arrs, int64(rows)), nil
+}
+
+func (s *ingesterScan) streamLabels(stream string) (labels.Labels, error) {
+	if lbls, ok := s.streams[stream]; ok {
+		return lbls, nil
+	}
+	lbls, err := syntax.ParseLabels(stream)
+	if err != nil {
+		return labels.EmptyLabels(), fmt.Errorf("parsing stream labels: %w", err)
+	}
+	if s.opts.Tenant != "" {
+		lbls = labels.NewBuilder(lbls).Set(multitenancy.TenantLabel, s.opts.Tenant).Labels()
+	}
+	s.streams[stream] = lbls
+	return lbls, nil
+}
+
+// Close implements [Pipeline].
+func (s *ingesterScan) Close() {
+	_ = s.it.Close()
+}
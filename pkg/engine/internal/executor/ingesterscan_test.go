@@ -0,0 +1,218 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/engine/internal/planner/physical"
+	"github.com/grafana/loki/v3/pkg/engine/internal/semconv"
+	"github.com/grafana/loki/v3/pkg/engine/internal/types"
+	"github.com/grafana/loki/v3/pkg/iter"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql"
+
+	"github.com/grafana/loki/pkg/push"
+)
+
+var ingesterScanStreams = []logproto.Stream{
+	{
+		Labels: `{env="prod", service="loki"}`,
+		Entries: []logproto.Entry{
+			{
+				Timestamp:          time.Unix(10, 0),
+				Line:               "goodbye world",
+				StructuredMetadata: []push.LabelAdapter{{Name: "guid", Value: "eeee-ffff-aaaa-bbbb"}},
+			},
+			{
+				Timestamp:          time.Unix(5, 0),
+				Line:               "hello world",
+				StructuredMetadata: []push.LabelAdapter{{Name: "guid", Value: "aaaa-bbbb-cccc-dddd"}},
+			},
+		},
+	},
+	{
+		Labels: `{env="prod", service="notloki"}`,
+		Entries: []logproto.Entry{
+			{
+				Timestamp:          time.Unix(3, 0),
+				Line:               "goodbye world",
+				StructuredMetadata: []push.LabelAdapter{{Name: "pod", Value: "notloki-pod-1"}},
+			},
+			{
+				Timestamp: time.Unix(2, 0),
+				Line:      "hello world",
+			},
+		},
+	},
+}
+
+func Test_ingesterScan(t *testing.T) {
+	t.Run("All columns", func(t *testing.T) {
+		pipeline := newIngesterScanPipeline(iter.NewStreamsIterator(ingesterScanStreams, logproto.BACKWARD), ingesterScanOptions{
+			BatchSize: 512,
+		})
+
+		expectFields := []arrow.Field{
+			semconv.FieldFromFQN("utf8.label.env", true),
+			semconv.FieldFromFQN("utf8.label.service", true),
+			semconv.FieldFromFQN("utf8.metadata.guid", true),
+			semconv.FieldFromFQN("utf8.metadata.pod", true),
+			semconv.FieldFromFQN("timestamp_ns.builtin.timestamp", true),
+			semconv.FieldFromFQN("utf8.builtin.message", true),
+		}
+
+		expectCSV := `prod,loki,eeee-ffff-aaaa-bbbb,NULL,1970-01-01 00:00:10,goodbye world
+prod,loki,aaaa-bbbb-cccc-dddd,NULL,1970-01-01 00:00:05,hello world
+prod,notloki,NULL,notloki-pod-1,1970-01-01 00:00:03,goodbye world
+prod,notloki,NULL,NULL,1970-01-01 00:00:02,hello world`
+
+		expectRecord, err := CSVToArrow(expectFields, expectCSV)
+		require.NoError(t, err)
+		defer expectRecord.Release()
+
+		AssertPipelinesEqual(t, pipeline, NewBufferedPipeline(expectRecord))
+	})
+
+	t.Run("Column subset", func(t *testing.T) {
+		pipeline := newIngesterScanPipeline(iter.NewStreamsIterator(ingesterScanStreams, logproto.BACKWARD), ingesterScanOptions{
+			Projections: []physical.ColumnExpression{
+				&physical.ColumnExpr{Ref: types.ColumnRef{Column: "service", Type: types.ColumnTypeAmbiguous}},
+				&physical.ColumnExpr{Ref: types.ColumnRef{Column: "timestamp", Type: types.ColumnTypeBuiltin}},
+			},
+			BatchSize: 512,
+		})
+
+		expectFields := []arrow.Field{
+			semconv.FieldFromFQN("utf8.label.service", true),
+			semconv.FieldFromFQN("timestamp_ns.builtin.timestamp", true),
+		}
+
+		expectCSV := `loki,1970-01-01 00:00:10
+loki,1970-01-01 00:00:05
+notloki,1970-01-01 00:00:03
+notloki,1970-01-01 00:00:02`
+
+		expectRecord, err := CSVToArrow(expectFields, expectCSV)
+		require.NoError(t, err)
+		defer expectRecord.Release()
+
+		AssertPipelinesEqual(t, pipeline, NewBufferedPipeline(expectRecord))
+	})
+}
+
+type fakeIngesterQuerier struct {
+	streams []logproto.Stream
+	req     *logproto.QueryRequest
+}
+
+func (q *fakeIngesterQuerier) SelectLogs(_ context.Context, params logql.SelectLogParams) (iter.EntryIterator, error) {
+	q.req = params.QueryRequest
+	return iter.NewStreamsIterator(q.streams, params.Direction), nil
+}
+
+func TestExecutor_IngesterScan(t *testing.T) {
+	querier := &fakeIngesterQuerier{streams: ingesterScanStreams}
+	c := &Context{batchSize: 512, ingesterQuerier: querier}
+
+	pipeline := c.executeIngesterScan(t.Context(), &physical.IngesterScan{
+		Selector:  `{env="prod"}`,
+		Shard:     physical.ShardInfo{Shard: 1, Of: 4},
+		Start:     time.Unix(0, 0),
+		End:       time.Unix(10, 0),
+		Direction: physical.DESC,
+		Predicates: []physical.Expression{
+			&physical.BinaryExpr{
+				Left:  &physical.ColumnExpr{Ref: types.ColumnRef{Column: "message", Type: types.ColumnTypeBuiltin}},
+				Right: physical.NewLiteral("hello world"),
+				Op:    types.BinaryOpEq,
+			},
+		},
+	})
+
+	expectFields := []arrow.Field{
+		semconv.FieldFromFQN("utf8.label.env", true),
+		semconv.FieldFromFQN("utf8.label.service", true),
+		semconv.FieldFromFQN("utf8.metadata.guid", true),
+		semconv.FieldFromFQN("utf8.metadata.pod", true),
+		semconv.FieldFromFQN("timestamp_ns.builtin.timestamp", true),
+		semconv.FieldFromFQN("utf8.builtin.message", true),
+	}
+
+	expectCSV := `prod,loki,aaaa-bbbb-cccc-dddd,NULL,1970-01-01 00:00:05,hello world
+prod,notloki,NULL,NULL,1970-01-01 00:00:02,hello world`
+
+	expectRecord, err := CSVToArrow(expectFields, expectCSV)
+	require.NoError(t, err)
+	defer expectRecord.Release()
+
+	AssertPipelinesEqual(t, pipeline, NewBufferedPipeline(expectRecord))
+
+	require.Equal(t, `{env="prod"}`, querier.req.Selector)
+	require.Equal(t, logproto.BACKWARD, querier.req.Direction)
+	require.Equal(t, time.Unix(10, 1), querier.req.End)
+	require.Equal(t, []string{"1_of_4"}, querier.req.Shards)
+	require.NotNil(t, querier.req.Plan)
+}
+
+func TestExecutor_IngesterScan_NoQuerier(t *testing.T) {
+	c := &Context{batchSize: 512}
+	pipeline := c.executeIngesterScan(t.Context(), &physical.IngesterScan{Selector: `{env="prod"}`})
+	defer pipeline.Close()
+
+	_, err := pipeline.Read(t.Context())
+	require.ErrorContains(t, err, "no ingester querier configured")
+}
+
+// tenantIngesterQuerier returns the streams of the tenant of the request.
+type tenantIngesterQuerier struct {
+	streams   map[string][]logproto.Stream
+	selectors []string
+}
+
+func (q *tenantIngesterQuerier) SelectLogs(ctx context.Context, params logql.SelectLogParams) (iter.EntryIterator, error) {
+	tenantID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	q.selectors = append(q.selectors, params.Selector)
+	return iter.NewStreamsIterator(q.streams[tenantID], params.Direction), nil
+}
+
+func TestExecutor_IngesterScan_MultiTenant(t *testing.T) {
+	querier := &tenantIngesterQuerier{streams: map[string][]logproto.Stream{
+		"tenant-a": {{Labels: `{env="prod"}`, Entries: []logproto.Entry{{Timestamp: time.Unix(5, 0), Line: "a"}}}},
+		"tenant-b": {{Labels: `{env="prod"}`, Entries: []logproto.Entry{{Timestamp: time.Unix(3, 0), Line: "b"}}}},
+		"tenant-c": {{Labels: `{env="prod"}`, Entries: []logproto.Entry{{Timestamp: time.Unix(4, 0), Line: "c"}}}},
+	}}
+	c := &Context{batchSize: 512, ingesterQuerier: querier}
+
+	ctx := user.InjectOrgID(t.Context(), "tenant-a|tenant-b|tenant-c")
+	pipeline := c.executeIngesterScan(ctx, &physical.IngesterScan{
+		Selector:  `{env="prod", __tenant_id__!="tenant-c"}`,
+		Start:     time.Unix(0, 0),
+		End:       time.Unix(10, 0),
+		Direction: physical.DESC,
+	})
+
+	expectFields := []arrow.Field{
+		semconv.FieldFromFQN("utf8.label.__tenant_id__", true),
+		semconv.FieldFromFQN("utf8.label.env", true),
+		semconv.FieldFromFQN("timestamp_ns.builtin.timestamp", true),
+		semconv.FieldFromFQN("utf8.builtin.message", true),
+	}
+
+	expectCSV := `tenant-a,prod,1970-01-01 00:00:05,a
+tenant-b,prod,1970-01-01 00:00:03,b`
+
+	expectRecord, err := CSVToArrow(expectFields, expectCSV)
+	require.NoError(t, err)
+	defer expectRecord.Release()
+
+	AssertPipelinesEqual(t, pipeline, NewBufferedPipeline(expectRecord))
+	require.Equal(t, []string{`{env="prod"}`, `{env="prod"}`}, querier.selectors)
+}
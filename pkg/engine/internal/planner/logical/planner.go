@@ -115,6 +115,16 @@ func buildPlanForLogQuery(
 				}
 			}
 			return true
+		case *syntax.SamplingExpr:
+			if e.Mode == log.SampleStreams {
+				// Streams are sampled by only reading the first shard of the
+				// sample, see below.
+				return true
+			}
+			// Sampling entries must be applied after all other filters, because
+			// it is not a predicate that can be pushed down to the scan.
+			postParsePredicates = append(postParsePredicates, convertSamplingExpr(e))
+			return true
 			//TODO Support logfmt and json expression parset expressions
 		case *syntax.LogfmtExpressionParserExpr, *syntax.JSONExpressionParserExpr,
 			*syntax.LineFmtExpr, *syntax.LabelFmtExpr,
@@ -134,6 +144,9 @@ func buildPlanForLogQuery(
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse shard: %w", err)
 	}
+	if _, ratio := syntax.SamplingRatios(expr); ratio < 1 && shard == noShard {
+		shard = NewShard(0, 1<<log.StreamSampleBits(ratio))
+	}
 
 	// MAKETABLE -> DataObjScan
 	builder := NewBuilder(
@@ -362,6 +375,14 @@ func convertLineMatchType(op log.LineMatchType) types.BinaryOp {
 	}
 }
 
+func convertSamplingExpr(expr *syntax.SamplingExpr) Value {
+	return &BinOp{
+		Left:  timestampColumnRef(),
+		Right: NewLiteral(expr.Ratio),
+		Op:    types.BinaryOpSample,
+	}
+}
+
 func timestampColumnRef() *ColumnRef {
 	return NewColumnRef(types.ColumnNameBuiltinTimestamp, types.ColumnTypeBuiltin)
 }
@@ -190,6 +190,14 @@ func TestCanExecuteQuery(t *testing.T) {
 		{
 			statement: `{env="prod"} | json foo="bar"`,
 		},
+		{
+			statement: `{env="prod"} | sample 0.1`,
+			expected:  true,
+		},
+		{
+			statement: `{env="prod"} | sample 0.1 by stream`,
+			expected:  true,
+		},
 		{
 			statement: `{env="prod"} | logfmt`,
 			expected:  true,
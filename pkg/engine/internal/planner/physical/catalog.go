@@ -6,9 +6,12 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/grafana/dskit/tenant"
+	"github.com/grafana/dskit/user"
 	"github.com/prometheus/prometheus/model/labels"
 
 	"github.com/grafana/loki/v3/pkg/dataobj/metastore"
+	"github.com/grafana/loki/v3/pkg/dataobj/metastore/multitenancy"
 	"github.com/grafana/loki/v3/pkg/engine/internal/types"
 )
 
@@ -69,6 +72,10 @@ type FilteredShardDescriptor struct {
 	Streams   []int64
 	Sections  []int
 	TimeRange TimeRange
+
+	// Tenant owning the sections, only set for queries across several
+	// tenants.
+	Tenant string
 }
 
 // Catalog is an interface that provides methods for interacting with
@@ -136,6 +143,11 @@ func (c *MetastoreCatalog) resolveShardDescriptorsWithIndex(selector Expression,
 		predicateMatchers = append(predicateMatchers, matchers...)
 	}
 
+	tenants, _ := tenant.TenantIDs(c.ctx)
+	if len(tenants) > 1 {
+		return c.resolveMultiTenantShardDescriptors(tenants, matchers, predicateMatchers, shard, from, through)
+	}
+
 	sectionDescriptors, err := c.metastore.Sections(c.ctx, from, through, matchers, predicateMatchers)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve data object sections: %w", err)
@@ -144,6 +156,36 @@ func (c *MetastoreCatalog) resolveShardDescriptorsWithIndex(selector Expression,
 	return filterDescriptorsForShard(shard, sectionDescriptors)
 }
 
+// resolveMultiTenantShardDescriptors resolves the sections of each tenant
+// matching the [multitenancy.TenantLabel] matchers. Objects hold the sections
+// of several tenants, so the descriptors of different tenants may point to
+// the same object.
+func (c *MetastoreCatalog) resolveMultiTenantShardDescriptors(tenants []string, matchers, predicateMatchers []*labels.Matcher, shard ShardInfo, from, through time.Time) ([]FilteredShardDescriptor, error) {
+	tenants, matchers = multitenancy.FilterTenants(tenants, matchers)
+	// The tenant label is not stored in the objects, so the metastore
+	// can't use it to filter sections.
+	_, predicateMatchers = multitenancy.FilterTenants(nil, predicateMatchers)
+
+	var filteredDescriptors []FilteredShardDescriptor
+	for _, tenantID := range tenants {
+		ctx := user.InjectOrgID(c.ctx, tenantID)
+		sectionDescriptors, err := c.metastore.Sections(ctx, from, through, matchers, predicateMatchers)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve data object sections of tenant %s: %w", tenantID, err)
+		}
+
+		descriptors, err := filterDescriptorsForShard(shard, sectionDescriptors)
+		if err != nil {
+			return nil, err
+		}
+		for i := range descriptors {
+			descriptors[i].Tenant = tenantID
+		}
+		filteredDescriptors = append(filteredDescriptors, descriptors...)
+	}
+	return filteredDescriptors, nil
+}
+
 // filterDescriptorsForShard filters the section descriptors for a given shard.
 // It returns the locations, streams, and sections for the shard.
 // TODO: Improve filtering: this method could be improved because it doesn't resolve the stream IDs to sections, even though this information is available. Instead, it resolves streamIDs to the whole object.
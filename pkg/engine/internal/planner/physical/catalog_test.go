@@ -1,9 +1,11 @@
 package physical
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"github.com/grafana/dskit/user"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/require"
 
@@ -294,3 +296,68 @@ func TestCatalog_FilterDescriptorsForShard(t *testing.T) {
 	})
 
 }
+
+// tenantSectionsMetastore returns one section per tenant.
+type tenantSectionsMetastore struct {
+	metastore.Metastore
+
+	matchers, predicates []*labels.Matcher
+}
+
+func (m *tenantSectionsMetastore) Sections(ctx context.Context, start, end time.Time, matchers []*labels.Matcher, predicates []*labels.Matcher) ([]*metastore.DataobjSectionDescriptor, error) {
+	tenant, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m.matchers, m.predicates = matchers, predicates
+
+	desc := &metastore.DataobjSectionDescriptor{StreamIDs: []int64{1}, Start: start, End: end}
+	desc.ObjectPath = "objects/" + tenant
+	return []*metastore.DataobjSectionDescriptor{desc}, nil
+}
+
+func TestCatalog_ResolveMultiTenantShardDescriptors(t *testing.T) {
+	from, through := time.Unix(0, 0), time.Unix(3600, 0)
+	tr, err := newTimeRange(from, through)
+	require.NoError(t, err)
+
+	selector := &BinaryExpr{
+		Left: &BinaryExpr{
+			Left:  newColumnExpr("app", types.ColumnTypeLabel),
+			Right: NewLiteral("foo"),
+			Op:    types.BinaryOpEq,
+		},
+		Right: &BinaryExpr{
+			Left:  newColumnExpr("__tenant_id__", types.ColumnTypeLabel),
+			Right: NewLiteral("tenant-c"),
+			Op:    types.BinaryOpNeq,
+		},
+		Op: types.BinaryOpAnd,
+	}
+	predicates := []Expression{
+		&BinaryExpr{
+			Left:  newColumnExpr("__tenant_id__", types.ColumnTypeAmbiguous),
+			Right: NewLiteral("tenant-a"),
+			Op:    types.BinaryOpEq,
+		},
+	}
+
+	ms := &tenantSectionsMetastore{}
+	ctx := user.InjectOrgID(context.Background(), "tenant-a|tenant-b|tenant-c")
+	res, err := NewMetastoreCatalog(ctx, ms).ResolveShardDescriptorsWithShard(selector, predicates, noShard, from, through)
+	require.NoError(t, err)
+	require.Equal(t, []FilteredShardDescriptor{
+		{Location: "objects/tenant-a", Streams: []int64{1}, Sections: []int{0}, TimeRange: tr, Tenant: "tenant-a"},
+		{Location: "objects/tenant-b", Streams: []int64{1}, Sections: []int{0}, TimeRange: tr, Tenant: "tenant-b"},
+	}, res)
+	require.Equal(t, []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, "app", "foo")}, ms.matchers)
+	require.Empty(t, ms.predicates)
+
+	// Single tenant queries are resolved as before.
+	ctx = user.InjectOrgID(context.Background(), "tenant-a")
+	res, err = NewMetastoreCatalog(ctx, ms).ResolveShardDescriptorsWithShard(selector, nil, noShard, from, through)
+	require.NoError(t, err)
+	require.Equal(t, []FilteredShardDescriptor{
+		{Location: "objects/tenant-a", Streams: []int64{1}, Sections: []int{0}, TimeRange: tr},
+	}, res)
+}
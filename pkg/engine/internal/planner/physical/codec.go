@@ -0,0 +1,587 @@
+package physical
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/grafana/loki/v3/pkg/engine/internal/types"
+	"github.com/grafana/loki/v3/pkg/engine/internal/util/dag"
+)
+
+// MarshalPlan encodes the plan so that it can be sent to and executed by
+// another process. The plan must have exactly one root node. Node IDs are not
+// preserved.
+func MarshalPlan(p *Plan) ([]byte, error) {
+	enc, err := encodePlan(p)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(enc)
+}
+
+// UnmarshalPlan decodes a plan encoded by [MarshalPlan].
+func UnmarshalPlan(data []byte) (*Plan, error) {
+	var enc planJSON
+	if err := json.Unmarshal(data, &enc); err != nil {
+		return nil, fmt.Errorf("decoding plan: %w", err)
+	}
+	return decodePlan(&enc)
+}
+
+type planJSON struct {
+	// Nodes of the plan in depth-first pre-order, starting with the root.
+	Nodes []nodeJSON `json:"nodes"`
+}
+
+type nodeJSON struct {
+	Type string          `json:"type"`
+	Node json.RawMessage `json:"node,omitempty"`
+	// Children are the indexes of the children of the node in
+	// [planJSON.Nodes], in order.
+	Children []int `json:"children,omitempty"`
+}
+
+func encodePlan(p *Plan) (*planJSON, error) {
+	root, err := p.Root()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		enc     = &planJSON{}
+		indexes = make(map[Node]int, p.Len())
+	)
+	err = p.graph.Walk(root, func(n Node) error {
+		indexes[n] = len(enc.Nodes)
+		v, err := encodeNode(n)
+		if err != nil {
+			return err
+		}
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		enc.Nodes = append(enc.Nodes, nodeJSON{Type: n.Type().String(), Node: raw})
+		return nil
+	}, dag.PreOrderWalk)
+	if err != nil {
+		return nil, err
+	}
+
+	for n, i := range indexes {
+		for _, child := range p.Children(n) {
+			enc.Nodes[i].Children = append(enc.Nodes[i].Children, indexes[child])
+		}
+	}
+	return enc, nil
+}
+
+func decodePlan(enc *planJSON) (*Plan, error) {
+	p := &Plan{}
+	nodes := make([]Node, len(enc.Nodes))
+	for i, n := range enc.Nodes {
+		node, err := decodeNode(n.Type, n.Node)
+		if err != nil {
+			return nil, fmt.Errorf("decoding node %d: %w", i, err)
+		}
+		nodes[i] = p.graph.Add(node)
+	}
+	for i, n := range enc.Nodes {
+		for _, child := range n.Children {
+			if child < 0 || child >= len(nodes) {
+				return nil, fmt.Errorf("node %d has invalid child %d", i, child)
+			}
+			if err := p.graph.AddEdge(dag.Edge[Node]{Parent: nodes[i], Child: nodes[child]}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return p, nil
+}
+
+type dataObjScanJSON struct {
+	Location    DataObjLocation `json:"location"`
+	Section     int             `json:"section"`
+	StreamIDs   []int64         `json:"stream_ids,omitempty"`
+	Projections []*exprJSON     `json:"projections,omitempty"`
+	Predicates  []*exprJSON     `json:"predicates,omitempty"`
+	Tenant      string          `json:"tenant,omitempty"`
+}
+
+type ingesterScanJSON struct {
+	Selector    string      `json:"selector"`
+	Shard       ShardInfo   `json:"shard"`
+	Start       time.Time   `json:"start"`
+	End         time.Time   `json:"end"`
+	Direction   SortOrder   `json:"direction"`
+	Projections []*exprJSON `json:"projections,omitempty"`
+	Predicates  []*exprJSON `json:"predicates,omitempty"`
+}
+
+type sortMergeJSON struct {
+	Column *exprJSON `json:"column"`
+	Order  SortOrder `json:"order"`
+}
+
+type projectionJSON struct {
+	Columns []*exprJSON `json:"columns"`
+}
+
+type filterJSON struct {
+	Predicates []*exprJSON `json:"predicates"`
+}
+
+type rangeAggregationJSON struct {
+	PartitionBy []*exprJSON                `json:"partition_by,omitempty"`
+	Operation   types.RangeAggregationType `json:"operation"`
+	Start       time.Time                  `json:"start"`
+	End         time.Time                  `json:"end"`
+	Step        time.Duration              `json:"step"`
+	Range       time.Duration              `json:"range"`
+}
+
+type vectorAggregationJSON struct {
+	GroupBy   []*exprJSON                 `json:"group_by,omitempty"`
+	Operation types.VectorAggregationType `json:"operation"`
+}
+
+type topKJSON struct {
+	SortBy     *exprJSON `json:"sort_by"`
+	Ascending  bool      `json:"ascending"`
+	NullsFirst bool      `json:"nulls_first"`
+	K          int       `json:"k"`
+}
+
+type exchangeJSON struct {
+	Fragment *planJSON `json:"fragment"`
+}
+
+// encodeNode returns the value to encode for the node. Nodes without
+// expressions are encoded as they are.
+func encodeNode(n Node) (any, error) {
+	switch n := n.(type) {
+	case *DataObjScan:
+		return &dataObjScanJSON{
+			Location:    n.Location,
+			Section:     n.Section,
+			StreamIDs:   n.StreamIDs,
+			Projections: encodeColumns(n.Projections),
+			Predicates:  encodeExprs(n.Predicates),
+			Tenant:      n.Tenant,
+		}, nil
+	case *IngesterScan:
+		return &ingesterScanJSON{
+			Selector:    n.Selector,
+			Shard:       n.Shard,
+			Start:       n.Start,
+			End:         n.End,
+			Direction:   n.Direction,
+			Projections: encodeColumns(n.Projections),
+			Predicates:  encodeExprs(n.Predicates),
+		}, nil
+	case *SortMerge:
+		return &sortMergeJSON{Column: encodeExpr(n.Column), Order: n.Order}, nil
+	case *Projection:
+		return &projectionJSON{Columns: encodeColumns(n.Columns)}, nil
+	case *Filter:
+		return &filterJSON{Predicates: encodeExprs(n.Predicates)}, nil
+	case *RangeAggregation:
+		return &rangeAggregationJSON{
+			PartitionBy: encodeColumns(n.PartitionBy),
+			Operation:   n.Operation,
+			Start:       n.Start,
+			End:         n.End,
+			Step:        n.Step,
+			Range:       n.Range,
+		}, nil
+	case *VectorAggregation:
+		return &vectorAggregationJSON{GroupBy: encodeColumns(n.GroupBy), Operation: n.Operation}, nil
+	case *TopK:
+		return &topKJSON{SortBy: encodeExpr(n.SortBy), Ascending: n.Ascending, NullsFirst: n.NullsFirst, K: n.K}, nil
+	case *Exchange:
+		fragment, err := encodePlan(n.Fragment)
+		if err != nil {
+			return nil, fmt.Errorf("encoding fragment: %w", err)
+		}
+		return &exchangeJSON{Fragment: fragment}, nil
+	case *Limit, *Merge, *ParseNode, *ColumnCompat, *Parallelize:
+		return n, nil
+	default:
+		return nil, fmt.Errorf("unsupported node type %T", n)
+	}
+}
+
+func decodeNode(typ string, raw json.RawMessage) (Node, error) {
+	unmarshal := func(v any) error {
+		if len(raw) == 0 {
+			return nil
+		}
+		return json.Unmarshal(raw, v)
+	}
+
+	switch typ {
+	case NodeTypeDataObjScan.String():
+		var v dataObjScanJSON
+		if err := unmarshal(&v); err != nil {
+			return nil, err
+		}
+		projections, err := decodeColumns(v.Projections)
+		if err != nil {
+			return nil, err
+		}
+		predicates, err := decodeExprs(v.Predicates)
+		if err != nil {
+			return nil, err
+		}
+		return &DataObjScan{
+			Location:    v.Location,
+			Section:     v.Section,
+			StreamIDs:   v.StreamIDs,
+			Projections: projections,
+			Predicates:  predicates,
+			Tenant:      v.Tenant,
+		}, nil
+
+	case NodeTypeIngesterScan.String():
+		var v ingesterScanJSON
+		if err := unmarshal(&v); err != nil {
+			return nil, err
+		}
+		projections, err := decodeColumns(v.Projections)
+		if err != nil {
+			return nil, err
+		}
+		predicates, err := decodeExprs(v.Predicates)
+		if err != nil {
+			return nil, err
+		}
+		return &IngesterScan{
+			Selector:    v.Selector,
+			Shard:       v.Shard,
+			Start:       v.Start,
+			End:         v.End,
+			Direction:   v.Direction,
+			Projections: projections,
+			Predicates:  predicates,
+		}, nil
+
+	case NodeTypeSortMerge.String():
+		var v sortMergeJSON
+		if err := unmarshal(&v); err != nil {
+			return nil, err
+		}
+		column, err := decodeColumn(v.Column)
+		if err != nil {
+			return nil, err
+		}
+		return &SortMerge{Column: column, Order: v.Order}, nil
+
+	case NodeTypeProjection.String():
+		var v projectionJSON
+		if err := unmarshal(&v); err != nil {
+			return nil, err
+		}
+		columns, err := decodeColumns(v.Columns)
+		if err != nil {
+			return nil, err
+		}
+		return &Projection{Columns: columns}, nil
+
+	case NodeTypeFilter.String():
+		var v filterJSON
+		if err := unmarshal(&v); err != nil {
+			return nil, err
+		}
+		predicates, err := decodeExprs(v.Predicates)
+		if err != nil {
+			return nil, err
+		}
+		return &Filter{Predicates: predicates}, nil
+
+	case NodeTypeRangeAggregation.String():
+		var v rangeAggregationJSON
+		if err := unmarshal(&v); err != nil {
+			return nil, err
+		}
+		partitionBy, err := decodeColumns(v.PartitionBy)
+		if err != nil {
+			return nil, err
+		}
+		return &RangeAggregation{
+			PartitionBy: partitionBy,
+			Operation:   v.Operation,
+			Start:       v.Start,
+			End:         v.End,
+			Step:        v.Step,
+			Range:       v.Range,
+		}, nil
+
+	case NodeTypeVectorAggregation.String():
+		var v vectorAggregationJSON
+		if err := unmarshal(&v); err != nil {
+			return nil, err
+		}
+		groupBy, err := decodeColumns(v.GroupBy)
+		if err != nil {
+			return nil, err
+		}
+		return &VectorAggregation{GroupBy: groupBy, Operation: v.Operation}, nil
+
+	case NodeTypeTopK.String():
+		var v topKJSON
+		if err := unmarshal(&v); err != nil {
+			return nil, err
+		}
+		sortBy, err := decodeColumn(v.SortBy)
+		if err != nil {
+			return nil, err
+		}
+		return &TopK{SortBy: sortBy, Ascending: v.Ascending, NullsFirst: v.NullsFirst, K: v.K}, nil
+
+	case NodeTypeExchange.String():
+		var v exchangeJSON
+		if err := unmarshal(&v); err != nil {
+			return nil, err
+		}
+		if v.Fragment == nil {
+			return nil, fmt.Errorf("exchange without fragment")
+		}
+		fragment, err := decodePlan(v.Fragment)
+		if err != nil {
+			return nil, fmt.Errorf("decoding fragment: %w", err)
+		}
+		return &Exchange{Fragment: fragment}, nil
+
+	case NodeTypeLimit.String():
+		var v Limit
+		return &v, unmarshal(&v)
+	case NodeTypeMerge.String():
+		return &Merge{}, nil
+	case NodeTypeParse.String():
+		var v ParseNode
+		return &v, unmarshal(&v)
+	case NodeTypeCompat.String():
+		var v ColumnCompat
+		return &v, unmarshal(&v)
+	case NodeTypeParallelize.String():
+		return &Parallelize{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported node type %q", typ)
+	}
+}
+
+// exprJSON encodes an [Expression]. Exactly one of the fields is set.
+type exprJSON struct {
+	Unary   *unaryExprJSON   `json:"unary,omitempty"`
+	Binary  *binaryExprJSON  `json:"binary,omitempty"`
+	Literal *literalJSON     `json:"literal,omitempty"`
+	Column  *types.ColumnRef `json:"column,omitempty"`
+}
+
+type unaryExprJSON struct {
+	Op   types.UnaryOp `json:"op"`
+	Left *exprJSON     `json:"left"`
+}
+
+type binaryExprJSON struct {
+	Op    types.BinaryOp `json:"op"`
+	Left  *exprJSON      `json:"left"`
+	Right *exprJSON      `json:"right"`
+}
+
+// literalJSON encodes a [types.Literal]. Values are encoded as strings so that
+// the exact type and value, including special floats, survive encoding.
+type literalJSON struct {
+	Type  string `json:"type"`
+	Value string `json:"value,omitempty"`
+}
+
+const (
+	literalNull      = "null"
+	literalBool      = "bool"
+	literalString    = "string"
+	literalInteger   = "integer"
+	literalFloat     = "float"
+	literalTimestamp = "timestamp"
+	literalDuration  = "duration"
+	literalBytes     = "bytes"
+)
+
+func encodeExprs(exprs []Expression) []*exprJSON {
+	if len(exprs) == 0 {
+		return nil
+	}
+	enc := make([]*exprJSON, len(exprs))
+	for i, expr := range exprs {
+		enc[i] = encodeExpr(expr)
+	}
+	return enc
+}
+
+func encodeColumns(columns []ColumnExpression) []*exprJSON {
+	if len(columns) == 0 {
+		return nil
+	}
+	enc := make([]*exprJSON, len(columns))
+	for i, column := range columns {
+		enc[i] = encodeExpr(column)
+	}
+	return enc
+}
+
+func encodeExpr(expr Expression) *exprJSON {
+	switch expr := expr.(type) {
+	case nil:
+		return nil
+	case *UnaryExpr:
+		return &exprJSON{Unary: &unaryExprJSON{Op: expr.Op, Left: encodeExpr(expr.Left)}}
+	case *BinaryExpr:
+		return &exprJSON{Binary: &binaryExprJSON{Op: expr.Op, Left: encodeExpr(expr.Left), Right: encodeExpr(expr.Right)}}
+	case *LiteralExpr:
+		return &exprJSON{Literal: encodeLiteral(expr.Literal)}
+	case *ColumnExpr:
+		ref := expr.Ref
+		return &exprJSON{Column: &ref}
+	default:
+		panic(fmt.Sprintf("unknown expression type %T", expr))
+	}
+}
+
+func encodeLiteral(lit types.Literal) *literalJSON {
+	switch lit := lit.(type) {
+	case types.BoolLiteral:
+		return &literalJSON{Type: literalBool, Value: strconv.FormatBool(bool(lit))}
+	case types.StringLiteral:
+		return &literalJSON{Type: literalString, Value: string(lit)}
+	case types.IntegerLiteral:
+		return &literalJSON{Type: literalInteger, Value: strconv.FormatInt(int64(lit), 10)}
+	case types.FloatLiteral:
+		return &literalJSON{Type: literalFloat, Value: strconv.FormatFloat(float64(lit), 'g', -1, 64)}
+	case types.TimestampLiteral:
+		return &literalJSON{Type: literalTimestamp, Value: strconv.FormatInt(int64(lit), 10)}
+	case types.DurationLiteral:
+		return &literalJSON{Type: literalDuration, Value: strconv.FormatInt(int64(lit), 10)}
+	case types.BytesLiteral:
+		return &literalJSON{Type: literalBytes, Value: strconv.FormatInt(int64(lit), 10)}
+	default:
+		return &literalJSON{Type: literalNull}
+	}
+}
+
+func decodeExprs(enc []*exprJSON) ([]Expression, error) {
+	if len(enc) == 0 {
+		return nil, nil
+	}
+	exprs := make([]Expression, len(enc))
+	for i := range enc {
+		expr, err := decodeExpr(enc[i])
+		if err != nil {
+			return nil, err
+		}
+		exprs[i] = expr
+	}
+	return exprs, nil
+}
+
+func decodeColumns(enc []*exprJSON) ([]ColumnExpression, error) {
+	if len(enc) == 0 {
+		return nil, nil
+	}
+	columns := make([]ColumnExpression, len(enc))
+	for i := range enc {
+		column, err := decodeColumn(enc[i])
+		if err != nil {
+			return nil, err
+		}
+		columns[i] = column
+	}
+	return columns, nil
+}
+
+func decodeColumn(enc *exprJSON) (ColumnExpression, error) {
+	if enc == nil {
+		return nil, nil
+	}
+	if enc.Column == nil {
+		return nil, fmt.Errorf("expected column expression")
+	}
+	return &ColumnExpr{Ref: *enc.Column}, nil
+}
+
+func decodeExpr(enc *exprJSON) (Expression, error) {
+	switch {
+	case enc == nil:
+		return nil, nil
+	case enc.Unary != nil:
+		left, err := decodeExpr(enc.Unary.Left)
+		if err != nil {
+			return nil, err
+		}
+		return &UnaryExpr{Left: left, Op: enc.Unary.Op}, nil
+	case enc.Binary != nil:
+		left, err := decodeExpr(enc.Binary.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := decodeExpr(enc.Binary.Right)
+		if err != nil {
+			return nil, err
+		}
+		return &BinaryExpr{Left: left, Right: right, Op: enc.Binary.Op}, nil
+	case enc.Literal != nil:
+		lit, err := decodeLiteral(enc.Literal)
+		if err != nil {
+			return nil, err
+		}
+		return &LiteralExpr{Literal: lit}, nil
+	case enc.Column != nil:
+		return &ColumnExpr{Ref: *enc.Column}, nil
+	default:
+		return nil, fmt.Errorf("empty expression")
+	}
+}
+
+func decodeLiteral(enc *literalJSON) (types.Literal, error) {
+	var (
+		lit types.Literal
+		err error
+	)
+	switch enc.Type {
+	case literalNull:
+		lit = types.NewNullLiteral()
+	case literalBool:
+		var v bool
+		v, err = strconv.ParseBool(enc.Value)
+		lit = types.BoolLiteral(v)
+	case literalString:
+		lit = types.StringLiteral(enc.Value)
+	case literalInteger:
+		var v int64
+		v, err = strconv.ParseInt(enc.Value, 10, 64)
+		lit = types.IntegerLiteral(v)
+	case literalFloat:
+		var v float64
+		v, err = strconv.ParseFloat(enc.Value, 64)
+		lit = types.FloatLiteral(v)
+	case literalTimestamp:
+		var v int64
+		v, err = strconv.ParseInt(enc.Value, 10, 64)
+		lit = types.TimestampLiteral(v)
+	case literalDuration:
+		var v int64
+		v, err = strconv.ParseInt(enc.Value, 10, 64)
+		lit = types.DurationLiteral(v)
+	case literalBytes:
+		var v int64
+		v, err = strconv.ParseInt(enc.Value, 10, 64)
+		lit = types.BytesLiteral(v)
+	default:
+		return nil, fmt.Errorf("unsupported literal type %q", enc.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s literal: %w", enc.Type, err)
+	}
+	return lit, nil
+}
@@ -0,0 +1,151 @@
+package physical
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/engine/internal/types"
+	"github.com/grafana/loki/v3/pkg/engine/internal/util/dag"
+)
+
+func TestMarshalPlan(t *testing.T) {
+	fragment := &Plan{}
+	{
+		rangeAgg := fragment.graph.Add(&RangeAggregation{
+			PartitionBy: []ColumnExpression{newColumnExpr("app", types.ColumnTypeAmbiguous)},
+			Operation:   types.RangeAggregationTypeSum,
+			Start:       time.Unix(10, 0).UTC(),
+			End:         time.Unix(3600, 0).UTC(),
+			Step:        time.Minute,
+			Range:       5 * time.Minute,
+		})
+		parse := fragment.graph.Add(&ParseNode{Kind: ParserLogfmt, RequestedKeys: []string{"duration"}})
+		compat := fragment.graph.Add(&ColumnCompat{Source: types.ColumnTypeParsed, Destination: types.ColumnTypeParsed, Collision: types.ColumnTypeLabel})
+		topK := fragment.graph.Add(&TopK{SortBy: newColumnExpr(types.ColumnNameBuiltinTimestamp, types.ColumnTypeBuiltin), Ascending: true, K: 10})
+		scan1 := fragment.graph.Add(&DataObjScan{
+			Location:    "objects/00/1",
+			Section:     2,
+			StreamIDs:   []int64{1, 2, 3},
+			Projections: []ColumnExpression{newColumnExpr("message", types.ColumnTypeBuiltin)},
+			Predicates: []Expression{
+				&BinaryExpr{
+					Left:  newColumnExpr(types.ColumnNameBuiltinTimestamp, types.ColumnTypeBuiltin),
+					Right: NewLiteral(types.Timestamp(1000)),
+					Op:    types.BinaryOpGte,
+				},
+				&UnaryExpr{
+					Left: &BinaryExpr{
+						Left:  newColumnExpr("message", types.ColumnTypeBuiltin),
+						Right: NewLiteral("debug"),
+						Op:    types.BinaryOpMatchSubstr,
+					},
+					Op: types.UnaryOpNot,
+				},
+			},
+		})
+		scan2 := fragment.graph.Add(&DataObjScan{Location: "objects/00/2", Tenant: "tenant-b"})
+		scan3 := fragment.graph.Add(&IngesterScan{
+			Selector:    `{app="foo"}`,
+			Shard:       ShardInfo{Shard: 1, Of: 4},
+			Start:       time.Unix(3000, 0).UTC(),
+			End:         time.Unix(3600, 0).UTC(),
+			Direction:   ASC,
+			Projections: []ColumnExpression{newColumnExpr("message", types.ColumnTypeBuiltin)},
+			Predicates: []Expression{
+				&BinaryExpr{
+					Left:  newColumnExpr(types.ColumnNameBuiltinTimestamp, types.ColumnTypeBuiltin),
+					Right: NewLiteral(types.Timestamp(1000)),
+					Op:    types.BinaryOpGte,
+				},
+			},
+		})
+
+		_ = fragment.graph.AddEdge(dag.Edge[Node]{Parent: rangeAgg, Child: parse})
+		_ = fragment.graph.AddEdge(dag.Edge[Node]{Parent: parse, Child: compat})
+		_ = fragment.graph.AddEdge(dag.Edge[Node]{Parent: compat, Child: topK})
+		_ = fragment.graph.AddEdge(dag.Edge[Node]{Parent: topK, Child: scan1})
+		_ = fragment.graph.AddEdge(dag.Edge[Node]{Parent: topK, Child: scan2})
+		_ = fragment.graph.AddEdge(dag.Edge[Node]{Parent: topK, Child: scan3})
+	}
+
+	plan := &Plan{}
+	{
+		limit := plan.graph.Add(&Limit{Skip: 5, Fetch: 100})
+		projection := plan.graph.Add(&Projection{Columns: []ColumnExpression{newColumnExpr("app", types.ColumnTypeLabel)}})
+		filter := plan.graph.Add(&Filter{Predicates: []Expression{
+			&BinaryExpr{Left: newColumnExpr("a", types.ColumnTypeParsed), Right: NewLiteral(int64(-3)), Op: types.BinaryOpGt},
+			&BinaryExpr{Left: newColumnExpr("b", types.ColumnTypeParsed), Right: NewLiteral(math.Inf(-1)), Op: types.BinaryOpLt},
+			&BinaryExpr{Left: newColumnExpr("c", types.ColumnTypeParsed), Right: NewLiteral(true), Op: types.BinaryOpEq},
+			&BinaryExpr{Left: newColumnExpr("d", types.ColumnTypeParsed), Right: NewLiteral(types.Duration(time.Second)), Op: types.BinaryOpEq},
+			&BinaryExpr{Left: newColumnExpr("e", types.ColumnTypeParsed), Right: NewLiteral(types.Bytes(1024)), Op: types.BinaryOpEq},
+			&BinaryExpr{Left: newColumnExpr("f", types.ColumnTypeParsed), Right: NewLiteral(nil), Op: types.BinaryOpEq},
+		}})
+		vectorAgg := plan.graph.Add(&VectorAggregation{Operation: types.VectorAggregationTypeMax})
+		sortMerge := plan.graph.Add(&SortMerge{Column: newColumnExpr(types.ColumnNameBuiltinTimestamp, types.ColumnTypeBuiltin), Order: DESC})
+		parallelize := plan.graph.Add(&Parallelize{})
+		merge := plan.graph.Add(&Merge{})
+		exchange := plan.graph.Add(&Exchange{Fragment: fragment})
+
+		_ = plan.graph.AddEdge(dag.Edge[Node]{Parent: limit, Child: projection})
+		_ = plan.graph.AddEdge(dag.Edge[Node]{Parent: projection, Child: filter})
+		_ = plan.graph.AddEdge(dag.Edge[Node]{Parent: filter, Child: vectorAgg})
+		_ = plan.graph.AddEdge(dag.Edge[Node]{Parent: vectorAgg, Child: sortMerge})
+		_ = plan.graph.AddEdge(dag.Edge[Node]{Parent: sortMerge, Child: parallelize})
+		_ = plan.graph.AddEdge(dag.Edge[Node]{Parent: parallelize, Child: merge})
+		_ = plan.graph.AddEdge(dag.Edge[Node]{Parent: merge, Child: exchange})
+	}
+
+	data, err := MarshalPlan(plan)
+	require.NoError(t, err)
+
+	decoded, err := UnmarshalPlan(data)
+	require.NoError(t, err)
+	require.Equal(t, plan.Len(), decoded.Len())
+	require.Equal(t, PrintAsTree(plan), PrintAsTree(decoded))
+
+	// Node IDs are not preserved, so compare nodes by their contents.
+	root, err := plan.Root()
+	require.NoError(t, err)
+	decodedRoot, err := decoded.Root()
+	require.NoError(t, err)
+	requireEqualNodes(t, plan, root, decoded, decodedRoot)
+}
+
+func requireEqualNodes(t *testing.T, expectedPlan *Plan, expected Node, actualPlan *Plan, actual Node) {
+	t.Helper()
+
+	switch expected := expected.(type) {
+	case *Exchange:
+		actual, ok := actual.(*Exchange)
+		require.True(t, ok, "expected Exchange, got %T", actual)
+		expectedRoot, err := expected.Fragment.Root()
+		require.NoError(t, err)
+		actualRoot, err := actual.Fragment.Root()
+		require.NoError(t, err)
+		requireEqualNodes(t, expected.Fragment, expectedRoot, actual.Fragment, actualRoot)
+	default:
+		require.Equal(t, expected, actual)
+	}
+
+	expectedChildren, actualChildren := expectedPlan.Children(expected), actualPlan.Children(actual)
+	require.Len(t, actualChildren, len(expectedChildren))
+	for i := range expectedChildren {
+		requireEqualNodes(t, expectedPlan, expectedChildren[i], actualPlan, actualChildren[i])
+	}
+}
+
+func TestUnmarshalPlan_Invalid(t *testing.T) {
+	for _, data := range []string{
+		`not json`,
+		`{"nodes":[{"type":"Unknown"}]}`,
+		`{"nodes":[{"type":"Merge","children":[1]}]}`,
+		`{"nodes":[{"type":"Filter","node":{"predicates":[{"literal":{"type":"float","value":"x"}}]}}]}`,
+		`{"nodes":[{"type":"Projection","node":{"columns":[{"literal":{"type":"string","value":"x"}}]}}]}`,
+	} {
+		_, err := UnmarshalPlan([]byte(data))
+		require.Error(t, err, data)
+	}
+}
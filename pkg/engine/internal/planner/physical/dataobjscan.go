@@ -28,6 +28,10 @@ type DataObjScan struct {
 	// returned. Predicates would almost always contain a time range filter to
 	// only read the logs for the requested time range.
 	Predicates []Expression
+	// Tenant is the tenant whose sections are read for queries across several
+	// tenants, which also adds the tenant label to the rows. It is empty for
+	// single tenant queries.
+	Tenant string
 }
 
 // ID implements the [Node] interface.
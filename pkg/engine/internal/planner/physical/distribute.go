@@ -0,0 +1,242 @@
+package physical
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/grafana/loki/v3/pkg/engine/internal/types"
+	"github.com/grafana/loki/v3/pkg/engine/internal/util/dag"
+)
+
+// Distribute rewrites the plan so that the branches below each [Parallelize]
+// node can be executed independently, for example by different workers.
+//
+// Each branch of the Merge below a Parallelize node (typically a DataObjScan,
+// or a TopK over overlapping DataObjScans) is moved into its own plan fragment
+// and replaced by an [Exchange] node. Nodes that operate on individual rows,
+// like Filter, Parse, ColumnCompat and Projection, are moved into every
+// fragment, both from between the Parallelize and the Merge and from directly
+// above the Parallelize.
+//
+// A RangeAggregation above the Parallelize is pushed into the fragments too,
+// if its partial results can be combined: the fragments compute the
+// aggregation over their own rows, and a VectorAggregation replacing the
+// RangeAggregation merges the partial results of all fragments.
+//
+// The resulting plan only contains the nodes that need to see the results of
+// all fragments.
+func Distribute(plan *Plan) (*Plan, error) {
+	var parallelizeNodes []*Parallelize
+	for n := range plan.graph.Nodes() {
+		if p, ok := n.(*Parallelize); ok {
+			parallelizeNodes = append(parallelizeNodes, p)
+		}
+	}
+
+	for _, p := range parallelizeNodes {
+		if err := distributeParallelize(plan, p); err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+func distributeParallelize(plan *Plan, p *Parallelize) error {
+	children := plan.Children(p)
+	if len(children) != 1 {
+		return fmt.Errorf("parallelize node %s must have exactly one child, got %d", p.ID(), len(children))
+	}
+
+	// Row-wise nodes between the Parallelize and the Merge apply to every
+	// branch.
+	var lower []Node
+	cur := children[0]
+	for isRowWise(cur) && len(plan.Children(cur)) == 1 {
+		lower = append(lower, cur)
+		cur = plan.Children(cur)[0]
+	}
+
+	// The Merge may have been removed by the optimizer when there is only a
+	// single branch.
+	merge, isMerge := cur.(*Merge)
+	branches := []Node{cur}
+	if isMerge {
+		branches = slices.Clone(plan.Children(merge))
+	}
+
+	// Collect the row-wise nodes directly above the Parallelize, bottom to
+	// top, and the RangeAggregation above them if it can be computed
+	// partially.
+	var (
+		upper   []Node
+		partial *RangeAggregation
+	)
+	for n := Node(p); ; {
+		parents := plan.Parent(n)
+		if len(parents) != 1 || len(plan.Children(parents[0])) != 1 {
+			break
+		}
+		parent := parents[0]
+		if isRowWise(parent) {
+			upper = append(upper, parent)
+			n = parent
+			continue
+		}
+		if agg, ok := parent.(*RangeAggregation); ok && canAggregatePartially(agg) {
+			partial = agg
+		}
+		break
+	}
+
+	// The chain of nodes, top to bottom, every branch is wrapped with in its
+	// fragment.
+	var chain []Node
+	if partial != nil {
+		chain = append(chain, partial)
+	}
+	for i := len(upper) - 1; i >= 0; i-- {
+		chain = append(chain, upper[i])
+	}
+	chain = append(chain, lower...)
+
+	exchanges := make([]Node, 0, len(branches))
+	for _, branch := range branches {
+		fragment, err := buildFragment(plan, chain, branch)
+		if err != nil {
+			return err
+		}
+		exchanges = append(exchanges, &Exchange{Fragment: fragment})
+	}
+
+	// Remove everything that moved into the fragments from the plan.
+	for _, branch := range branches {
+		removeSubtree(plan, branch)
+	}
+	for _, n := range lower {
+		plan.graph.Eliminate(n)
+	}
+	for _, n := range upper {
+		plan.graph.Eliminate(n)
+	}
+
+	var target Node = p
+	if isMerge {
+		target = merge
+	}
+	for _, exchange := range exchanges {
+		plan.graph.Add(exchange)
+		if err := plan.graph.AddEdge(dag.Edge[Node]{Parent: target, Child: exchange}); err != nil {
+			return err
+		}
+	}
+
+	if partial != nil {
+		combine := &VectorAggregation{
+			GroupBy:   partial.PartitionBy,
+			Operation: combineOperation(partial.Operation),
+		}
+		plan.graph.Inject(partial, combine)
+		plan.graph.Eliminate(partial)
+	}
+	return nil
+}
+
+// isRowWise returns true for nodes which produce the same results whether
+// they are applied to all rows at once or to any partition of them.
+func isRowWise(n Node) bool {
+	switch n.(type) {
+	case *Filter, *Projection, *ParseNode, *ColumnCompat:
+		return true
+	default:
+		return false
+	}
+}
+
+// canAggregatePartially returns true if the results of the range aggregation
+// computed over partitions of its input can be combined to the result over
+// the whole input.
+func canAggregatePartially(agg *RangeAggregation) bool {
+	// The executor requires explicit partitioning to build the output
+	// columns the combining aggregation groups by.
+	if len(agg.PartitionBy) == 0 {
+		return false
+	}
+	switch agg.Operation {
+	case types.RangeAggregationTypeCount, types.RangeAggregationTypeSum,
+		types.RangeAggregationTypeMax, types.RangeAggregationTypeMin:
+		return true
+	default:
+		return false
+	}
+}
+
+// combineOperation returns the vector aggregation combining the partial
+// results of a range aggregation.
+func combineOperation(op types.RangeAggregationType) types.VectorAggregationType {
+	switch op {
+	case types.RangeAggregationTypeMax:
+		return types.VectorAggregationTypeMax
+	case types.RangeAggregationTypeMin:
+		return types.VectorAggregationTypeMin
+	default:
+		// Partial counts and sums are both summed up.
+		return types.VectorAggregationTypeSum
+	}
+}
+
+// buildFragment returns a new plan with the chain of nodes on top of a copy of
+// the subtree starting at branch.
+func buildFragment(plan *Plan, chain []Node, branch Node) (*Plan, error) {
+	fragment := &Plan{}
+
+	var parent Node
+	for _, n := range chain {
+		fragment.graph.Add(n)
+		if parent != nil {
+			if err := fragment.graph.AddEdge(dag.Edge[Node]{Parent: parent, Child: n}); err != nil {
+				return nil, err
+			}
+		}
+		parent = n
+	}
+
+	if err := copySubtree(plan, fragment, branch); err != nil {
+		return nil, err
+	}
+	if parent != nil {
+		if err := fragment.graph.AddEdge(dag.Edge[Node]{Parent: parent, Child: branch}); err != nil {
+			return nil, err
+		}
+	}
+	return fragment, nil
+}
+
+// copySubtree adds n and all its descendants in src to dst, preserving the
+// order of children.
+func copySubtree(src, dst *Plan, n Node) error {
+	dst.graph.Add(n)
+	for _, child := range src.Children(n) {
+		if err := copySubtree(src, dst, child); err != nil {
+			return err
+		}
+		if err := dst.graph.AddEdge(dag.Edge[Node]{Parent: n, Child: child}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeSubtree removes n and all its descendants from the plan.
+func removeSubtree(plan *Plan, n Node) {
+	var nodes []Node
+	_ = plan.graph.Walk(n, func(n Node) error {
+		nodes = append(nodes, n)
+		return nil
+	}, dag.PostOrderWalk)
+
+	// Eliminating the nodes while walking would modify the children being
+	// iterated over.
+	for _, n := range nodes {
+		plan.graph.Eliminate(n)
+	}
+}
@@ -0,0 +1,130 @@
+package physical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/engine/internal/types"
+	"github.com/grafana/loki/v3/pkg/engine/internal/util/dag"
+)
+
+func TestDistribute(t *testing.T) {
+	t.Run("partial range aggregation", func(t *testing.T) {
+		plan := &Plan{}
+		rangeAgg := plan.graph.Add(&RangeAggregation{
+			id:          "range",
+			PartitionBy: []ColumnExpression{newColumnExpr("app", types.ColumnTypeLabel)},
+			Operation:   types.RangeAggregationTypeCount,
+			Start:       time.Unix(0, 0).UTC(),
+			End:         time.Unix(3600, 0).UTC(),
+			Step:        time.Minute,
+			Range:       5 * time.Minute,
+		})
+		filter := plan.graph.Add(&Filter{id: "filter", Predicates: []Expression{
+			&BinaryExpr{
+				Left:  newColumnExpr("level", types.ColumnTypeAmbiguous),
+				Right: NewLiteral("error"),
+				Op:    types.BinaryOpEq,
+			},
+		}})
+		parallelize := plan.graph.Add(&Parallelize{id: "parallelize"})
+		compat := plan.graph.Add(&ColumnCompat{id: "compat", Source: types.ColumnTypeMetadata, Destination: types.ColumnTypeMetadata, Collision: types.ColumnTypeLabel})
+		merge := plan.graph.Add(&Merge{id: "merge"})
+		scan1 := plan.graph.Add(&DataObjScan{id: "scan1", Location: "obj1"})
+		scan2 := plan.graph.Add(&DataObjScan{id: "scan2", Location: "obj2"})
+
+		_ = plan.graph.AddEdge(dag.Edge[Node]{Parent: rangeAgg, Child: filter})
+		_ = plan.graph.AddEdge(dag.Edge[Node]{Parent: filter, Child: parallelize})
+		_ = plan.graph.AddEdge(dag.Edge[Node]{Parent: parallelize, Child: compat})
+		_ = plan.graph.AddEdge(dag.Edge[Node]{Parent: compat, Child: merge})
+		_ = plan.graph.AddEdge(dag.Edge[Node]{Parent: merge, Child: scan1})
+		_ = plan.graph.AddEdge(dag.Edge[Node]{Parent: merge, Child: scan2})
+
+		plan, err := Distribute(plan)
+		require.NoError(t, err)
+
+		root, err := plan.Root()
+		require.NoError(t, err)
+		combine, ok := root.(*VectorAggregation)
+		require.True(t, ok, "expected root to be a VectorAggregation, got %T", root)
+		require.Equal(t, types.VectorAggregationTypeSum, combine.Operation)
+		require.Equal(t, []ColumnExpression{newColumnExpr("app", types.ColumnTypeLabel)}, combine.GroupBy)
+
+		require.Equal(t, []Node{parallelize}, plan.Children(combine))
+		require.Equal(t, []Node{merge}, plan.Children(parallelize))
+		require.Equal(t, 5, plan.Len()) // VectorAggregation, Parallelize, Merge and 2 Exchanges
+
+		exchanges := plan.Children(merge)
+		require.Len(t, exchanges, 2)
+		for i, scan := range []Node{scan1, scan2} {
+			exchange, ok := exchanges[i].(*Exchange)
+			require.True(t, ok, "expected Exchange, got %T", exchanges[i])
+
+			fragment := exchange.Fragment
+			require.Equal(t, 4, fragment.Len())
+			fragmentRoot, err := fragment.Root()
+			require.NoError(t, err)
+			require.Equal(t, rangeAgg, fragmentRoot)
+			require.Equal(t, []Node{filter}, fragment.Children(rangeAgg))
+			require.Equal(t, []Node{compat}, fragment.Children(filter))
+			require.Equal(t, []Node{scan}, fragment.Children(compat))
+		}
+	})
+
+	t.Run("range aggregation without partitioning is not pushed down", func(t *testing.T) {
+		plan := &Plan{}
+		rangeAgg := plan.graph.Add(&RangeAggregation{id: "range", Operation: types.RangeAggregationTypeCount})
+		parallelize := plan.graph.Add(&Parallelize{id: "parallelize"})
+		merge := plan.graph.Add(&Merge{id: "merge"})
+		scan1 := plan.graph.Add(&DataObjScan{id: "scan1"})
+		scan2 := plan.graph.Add(&DataObjScan{id: "scan2"})
+
+		_ = plan.graph.AddEdge(dag.Edge[Node]{Parent: rangeAgg, Child: parallelize})
+		_ = plan.graph.AddEdge(dag.Edge[Node]{Parent: parallelize, Child: merge})
+		_ = plan.graph.AddEdge(dag.Edge[Node]{Parent: merge, Child: scan1})
+		_ = plan.graph.AddEdge(dag.Edge[Node]{Parent: merge, Child: scan2})
+
+		plan, err := Distribute(plan)
+		require.NoError(t, err)
+
+		root, err := plan.Root()
+		require.NoError(t, err)
+		require.Equal(t, rangeAgg, root)
+		for i, scan := range []Node{scan1, scan2} {
+			exchange := plan.Children(merge)[i].(*Exchange)
+			require.Equal(t, 1, exchange.Fragment.Len())
+			require.Equal(t, []Node{scan}, exchange.Fragment.Roots())
+		}
+	})
+
+	t.Run("single branch without merge", func(t *testing.T) {
+		plan := &Plan{}
+		topK := plan.graph.Add(&TopK{id: "topk", SortBy: newColumnExpr(types.ColumnNameBuiltinTimestamp, types.ColumnTypeBuiltin), K: 100})
+		parallelize := plan.graph.Add(&Parallelize{id: "parallelize"})
+		scanTopK := plan.graph.Add(&TopK{id: "scan_topk", SortBy: newColumnExpr(types.ColumnNameBuiltinTimestamp, types.ColumnTypeBuiltin)})
+		scan := plan.graph.Add(&DataObjScan{id: "scan"})
+
+		_ = plan.graph.AddEdge(dag.Edge[Node]{Parent: topK, Child: parallelize})
+		_ = plan.graph.AddEdge(dag.Edge[Node]{Parent: parallelize, Child: scanTopK})
+		_ = plan.graph.AddEdge(dag.Edge[Node]{Parent: scanTopK, Child: scan})
+
+		plan, err := Distribute(plan)
+		require.NoError(t, err)
+
+		// The TopK above the Parallelize needs the results of all branches and
+		// stays in the plan.
+		require.Equal(t, 3, plan.Len())
+		require.Equal(t, []Node{parallelize}, plan.Children(topK))
+		children := plan.Children(parallelize)
+		require.Len(t, children, 1)
+		exchange := children[0].(*Exchange)
+		require.Equal(t, []Node{scanTopK}, exchange.Fragment.Roots())
+		require.Equal(t, []Node{scan}, exchange.Fragment.Children(scanTopK))
+
+		out := PrintAsTree(plan)
+		require.True(t, strings.Contains(out, "Exchange nodes=2"), out)
+	})
+}
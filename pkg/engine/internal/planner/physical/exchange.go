@@ -0,0 +1,30 @@
+package physical
+
+import "fmt"
+
+// Exchange represents the results of a plan fragment that is executed
+// independently of the plan containing the Exchange, possibly by a remote
+// worker. The output of the fragment is the output of the Exchange node.
+//
+// Exchange nodes are leaf nodes; they are created by [Distribute] and never
+// have children in the plan they belong to.
+type Exchange struct {
+	id string
+
+	// Fragment is the plan executed to produce the results of the node.
+	Fragment *Plan
+}
+
+// ID returns a string that uniquely identifies the node in the plan.
+func (e *Exchange) ID() string {
+	if e.id == "" {
+		return fmt.Sprintf("%p", e)
+	}
+	return e.id
+}
+
+// Type returns [NodeTypeExchange].
+func (e *Exchange) Type() NodeType { return NodeTypeExchange }
+
+// Accept implements the [Node] interface, dispatching itself to v.
+func (e *Exchange) Accept(v Visitor) error { return v.VisitExchange(e) }
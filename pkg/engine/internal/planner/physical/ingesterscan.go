@@ -0,0 +1,53 @@
+package physical
+
+import (
+	"fmt"
+	"time"
+)
+
+// IngesterScan represents a physical plan operation for reading logs which
+// are not yet flushed to data objects from the ingesters.
+//
+// The output of an IngesterScan has the same columns as the output of a
+// [DataObjScan], so that both can be combined in the same plan.
+type IngesterScan struct {
+	id string
+
+	// Selector is the stream selector of the streams to read, in LogQL
+	// syntax.
+	Selector string
+	// Shard is the shard of the streams to read.
+	Shard ShardInfo
+	// Start and End are the inclusive time range of the logs to read.
+	Start time.Time
+	End   time.Time
+	// Direction is the order the ingesters return logs in.
+	Direction SortOrder
+	// Projections are used to limit the columns that are returned to the ones
+	// provided in the column expressions.
+	Projections []ColumnExpression
+	// Predicates are used to filter rows to reduce the amount of rows that are
+	// returned.
+	Predicates []Expression
+}
+
+// ID implements the [Node] interface.
+// Returns a string that uniquely identifies the node in the plan.
+func (s *IngesterScan) ID() string {
+	if s.id == "" {
+		return fmt.Sprintf("%p", s)
+	}
+	return s.id
+}
+
+// Type implements the [Node] interface.
+// Returns the type of the node.
+func (*IngesterScan) Type() NodeType {
+	return NodeTypeIngesterScan
+}
+
+// Accept implements the [Node] interface.
+// Dispatches itself to the provided [Visitor] v
+func (s *IngesterScan) Accept(v Visitor) error {
+	return v.VisitIngesterScan(s)
+}
@@ -85,6 +85,12 @@ func (r *predicatePushdown) applyPredicatePushdown(node Node, predicate Expressi
 			return true
 		}
 		return false
+	case *IngesterScan:
+		if canApplyPredicate(predicate) {
+			node.Predicates = append(node.Predicates, predicate)
+			return true
+		}
+		return false
 	}
 	for _, child := range r.plan.Children(node) {
 		if ok := r.applyPredicatePushdown(child, predicate); !ok {
@@ -97,6 +103,10 @@ func (r *predicatePushdown) applyPredicatePushdown(node Node, predicate Expressi
 func canApplyPredicate(predicate Expression) bool {
 	switch pred := predicate.(type) {
 	case *BinaryExpr:
+		if pred.Op == types.BinaryOpSample {
+			// Scans do not support sampling.
+			return false
+		}
 		return canApplyPredicate(pred.Left) && canApplyPredicate(pred.Right)
 	case *ColumnExpr:
 		return pred.Ref.Type == types.ColumnTypeBuiltin || pred.Ref.Type == types.ColumnTypeMetadata
@@ -152,6 +162,12 @@ type projectionPushdown struct {
 
 // apply implements rule.
 func (r *projectionPushdown) apply(node Node) bool {
+	if r.hasSampling() {
+		// Entries are sampled by a hash of all their stream labels and their
+		// line, so scans must read all columns.
+		return false
+	}
+
 	switch node := node.(type) {
 	case *VectorAggregation:
 		if len(node.GroupBy) == 0 {
@@ -225,7 +241,9 @@ func (r *projectionPushdown) applyProjectionPushdown(
 ) bool {
 	switch node := node.(type) {
 	case *DataObjScan:
-		return r.handleDataObjScan(node, projections, applyIfNotEmpty)
+		return r.handleScan(&node.Projections, projections, applyIfNotEmpty)
+	case *IngesterScan:
+		return r.handleScan(&node.Projections, projections, applyIfNotEmpty)
 	case *ParseNode:
 		return r.handleParseNode(node, projections, applyIfNotEmpty)
 	case *RangeAggregation:
@@ -238,8 +256,9 @@ func (r *projectionPushdown) applyProjectionPushdown(
 	return false
 }
 
-// handleDataObjScan handles projection pushdown for DataObjScan nodes
-func (r *projectionPushdown) handleDataObjScan(node *DataObjScan, projections []ColumnExpression, applyIfNotEmpty bool) bool {
+// handleScan handles projection pushdown for the projections of DataObjScan
+// and IngesterScan nodes
+func (r *projectionPushdown) handleScan(scanProjections *[]ColumnExpression, projections []ColumnExpression, applyIfNotEmpty bool) bool {
 	shouldNotApply := len(projections) == 0 && applyIfNotEmpty
 	if !r.isMetricQuery() || shouldNotApply {
 		return false
@@ -254,7 +273,7 @@ func (r *projectionPushdown) handleDataObjScan(node *DataObjScan, projections []
 		}
 
 		var wasAdded bool
-		node.Projections, wasAdded = addUniqueProjection(node.Projections, colExpr)
+		*scanProjections, wasAdded = addUniqueProjection(*scanProjections, colExpr)
 		if wasAdded {
 			changed = true
 		}
@@ -262,7 +281,7 @@ func (r *projectionPushdown) handleDataObjScan(node *DataObjScan, projections []
 
 	if changed {
 		// Sort projections by column name for deterministic order
-		slices.SortFunc(node.Projections, sortProjections)
+		slices.SortFunc(*scanProjections, sortProjections)
 	}
 
 	return changed
@@ -375,6 +394,22 @@ func (r *projectionPushdown) isMetricQuery() bool {
 	return false
 }
 
+// hasSampling checks if the plan contains a Filter node sampling entries.
+func (r *projectionPushdown) hasSampling() bool {
+	for node := range r.plan.graph.Nodes() {
+		filter, ok := node.(*Filter)
+		if !ok {
+			continue
+		}
+		for _, predicate := range filter.Predicates {
+			if expr, ok := predicate.(*BinaryExpr); ok && expr.Op == types.BinaryOpSample {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 var _ rule = (*projectionPushdown)(nil)
 
 // disambiguateColumns splits columns into ambiguous and unambiguous columns
@@ -63,6 +63,14 @@ func TestCanApplyPredicate(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			predicate: &BinaryExpr{
+				Left:  newColumnExpr("timestamp", types.ColumnTypeBuiltin),
+				Right: NewLiteral(0.1),
+				Op:    types.BinaryOpSample,
+			},
+			want: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.predicate.String(), func(t *testing.T) {
@@ -397,6 +405,45 @@ func TestOptimizer(t *testing.T) {
 		require.Equal(t, expected, actual)
 	})
 
+	t.Run("projection pushdown is skipped when sampling", func(t *testing.T) {
+		buildPlan := func() *Plan {
+			plan := &Plan{}
+			scan := plan.graph.Add(&DataObjScan{id: "scan1"})
+			filter := plan.graph.Add(&Filter{
+				id: "filter1",
+				Predicates: []Expression{
+					&BinaryExpr{
+						Left:  newColumnExpr(types.ColumnNameBuiltinTimestamp, types.ColumnTypeBuiltin),
+						Right: NewLiteral(0.1),
+						Op:    types.BinaryOpSample,
+					},
+				},
+			})
+			rangeAgg := plan.graph.Add(&RangeAggregation{
+				id:          "range1",
+				Operation:   types.RangeAggregationTypeCount,
+				PartitionBy: []ColumnExpression{&ColumnExpr{Ref: types.ColumnRef{Column: "level", Type: types.ColumnTypeLabel}}},
+			})
+
+			_ = plan.graph.AddEdge(dag.Edge[Node]{Parent: rangeAgg, Child: filter})
+			_ = plan.graph.AddEdge(dag.Edge[Node]{Parent: filter, Child: scan})
+			return plan
+		}
+
+		plan := buildPlan()
+		optimizations := []*optimization{
+			newOptimization("projection pushdown", plan).withRules(
+				&projectionPushdown{plan: plan},
+			),
+		}
+		o := newOptimizer(plan, optimizations)
+		o.optimize(plan.Roots()[0])
+
+		// Sampled entries are hashed with all stream labels and the line, so
+		// scans read all columns.
+		require.Equal(t, PrintAsTree(buildPlan()), PrintAsTree(plan))
+	})
+
 	t.Run("predicate column projection pushdown with existing projections", func(t *testing.T) {
 		// Predicate columns should be projected when there are existing projections (metric query)
 		partitionBy := []ColumnExpression{
@@ -19,6 +19,8 @@ const (
 	NodeTypeCompat
 	NodeTypeTopK
 	NodeTypeParallelize
+	NodeTypeExchange
+	NodeTypeIngesterScan
 )
 
 func (t NodeType) String() string {
@@ -47,6 +49,10 @@ func (t NodeType) String() string {
 		return "TopK"
 	case NodeTypeParallelize:
 		return "Parallelize"
+	case NodeTypeExchange:
+		return "Exchange"
+	case NodeTypeIngesterScan:
+		return "IngesterScan"
 	default:
 		return "Undefined"
 	}
@@ -84,6 +90,8 @@ var _ Node = (*ParseNode)(nil)
 var _ Node = (*ColumnCompat)(nil)
 var _ Node = (*TopK)(nil)
 var _ Node = (*Parallelize)(nil)
+var _ Node = (*Exchange)(nil)
+var _ Node = (*IngesterScan)(nil)
 
 func (*DataObjScan) isNode()       {}
 func (*Merge) isNode()             {}
@@ -97,6 +105,8 @@ func (*ParseNode) isNode()         {}
 func (*ColumnCompat) isNode()      {}
 func (*TopK) isNode()              {}
 func (*Parallelize) isNode()       {}
+func (*Exchange) isNode()          {}
+func (*IngesterScan) isNode()      {}
 
 // WalkOrder defines the order for how a node and its children are visited.
 type WalkOrder uint8
@@ -10,6 +10,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/engine/internal/planner/logical"
 	"github.com/grafana/loki/v3/pkg/engine/internal/types"
 	"github.com/grafana/loki/v3/pkg/engine/internal/util/dag"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
 )
 
 // Context carries planning state that needs to be propagated down the plan tree.
@@ -25,6 +26,11 @@ type Context struct {
 	rangeInterval time.Duration
 	direction     SortOrder
 	v1Compatible  bool
+
+	// ingestersFrom is the time from which on logs are read from the
+	// ingesters instead of data objects, because they may not be flushed to
+	// data objects yet. Logs are only read from data objects if it is zero.
+	ingestersFrom time.Time
 }
 
 func NewContext(from, through time.Time) *Context {
@@ -40,6 +46,7 @@ func (pc *Context) Clone() *Context {
 		through:       pc.through,
 		rangeInterval: pc.rangeInterval,
 		direction:     pc.direction,
+		ingestersFrom: pc.ingestersFrom,
 	}
 }
 
@@ -62,6 +69,14 @@ func (pc *Context) WithTimeRange(from, through time.Time) *Context {
 	return cloned
 }
 
+// WithIngestersFrom returns a copy of the context which reads logs newer
+// than or equal to from from the ingesters.
+func (pc *Context) WithIngestersFrom(from time.Time) *Context {
+	cloned := pc.Clone()
+	cloned.ingestersFrom = from
+	return cloned
+}
+
 func (pc *Context) GetResolveTimeRange() (from, through time.Time) {
 	return pc.from.Add(-pc.rangeInterval), pc.through
 }
@@ -169,11 +184,27 @@ func (p *Planner) buildNodeGroup(currentGroup []FilteredShardDescriptor, baseNod
 				Location:  descriptor.Location,
 				StreamIDs: descriptor.Streams,
 				Section:   section,
+				Tenant:    descriptor.Tenant,
+			}
+			if !ctx.ingestersFrom.IsZero() {
+				// Newer logs are read from the ingesters. Reading them from
+				// data objects as well would return them twice.
+				scan.Predicates = append(scan.Predicates, &BinaryExpr{
+					Left:  newColumnExpr(types.ColumnNameBuiltinTimestamp, types.ColumnTypeBuiltin),
+					Right: NewLiteral(types.Timestamp(ctx.ingestersFrom.UTC().UnixNano())),
+					Op:    types.BinaryOpLt,
+				})
 			}
 			p.plan.graph.Add(scan)
 			scans = append(scans, scan)
 		}
 	}
+	return p.addScans(scans, baseNode, ctx)
+}
+
+// addScans adds scans, which may return overlapping time ranges, as children
+// of baseNode.
+func (p *Planner) addScans(scans []Node, baseNode Node, ctx *Context) error {
 	if len(scans) > 1 && ctx.direction != UNSORTED {
 		// a single topK for overlapping scan nodes.
 		topK := &TopK{
@@ -251,13 +282,46 @@ func (p *Planner) processMakeTable(lp *logical.MakeTable, ctx *Context) ([]Node,
 	}
 
 	from, through := ctx.GetResolveTimeRange()
+	selector := p.convertPredicate(lp.Selector)
 
-	filteredShardDescriptors, err := p.catalog.ResolveShardDescriptorsWithShard(p.convertPredicate(lp.Selector), predicates, ShardInfo(*shard), from, through)
-	if err != nil {
-		return nil, err
+	readIngesters := !ctx.ingestersFrom.IsZero() && !through.Before(ctx.ingestersFrom)
+	readObjects := ctx.ingestersFrom.IsZero() || from.Before(ctx.ingestersFrom)
+
+	var filteredShardDescriptors []FilteredShardDescriptor
+	if readObjects {
+		objectsThrough := through
+		if readIngesters {
+			objectsThrough = ctx.ingestersFrom
+		}
+
+		var err error
+		filteredShardDescriptors, err = p.catalog.ResolveShardDescriptorsWithShard(selector, predicates, ShardInfo(*shard), from, objectsThrough)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	groups := overlappingShardDescriptors(filteredShardDescriptors)
+	// Groups are ordered by time descending. The logs read from the ingesters
+	// are the most recent ones, so they form the first group.
+	var groups []func(merge Node) error
+	if readIngesters {
+		ingestersFrom := from
+		if ingestersFrom.Before(ctx.ingestersFrom) {
+			ingestersFrom = ctx.ingestersFrom
+		}
+		scan, err := p.ingesterScan(selector, ShardInfo(*shard), ingestersFrom, through, ctx)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, func(merge Node) error {
+			return p.addScans([]Node{scan}, merge, ctx)
+		})
+	}
+	for _, gr := range overlappingShardDescriptors(filteredShardDescriptors) {
+		groups = append(groups, func(merge Node) error {
+			return p.buildNodeGroup(gr, merge, ctx)
+		})
+	}
 	if ctx.direction == ASC {
 		slices.Reverse(groups)
 	}
@@ -269,8 +333,8 @@ func (p *Planner) processMakeTable(lp *logical.MakeTable, ctx *Context) ([]Node,
 
 	var merge Node = &Merge{}
 	p.plan.graph.Add(merge)
-	for _, gr := range groups {
-		if err := p.buildNodeGroup(gr, merge, ctx); err != nil {
+	for _, addGroup := range groups {
+		if err := addGroup(merge); err != nil {
 			return nil, err
 		}
 	}
@@ -281,6 +345,7 @@ func (p *Planner) processMakeTable(lp *logical.MakeTable, ctx *Context) ([]Node,
 			Destination: types.ColumnTypeMetadata,
 			Collision:   types.ColumnTypeLabel,
 		}
+		var err error
 		merge, err = p.wrapNodeWith(merge, compat)
 		if err != nil {
 			return nil, err
@@ -295,6 +360,25 @@ func (p *Planner) processMakeTable(lp *logical.MakeTable, ctx *Context) ([]Node,
 	return []Node{parallelize}, nil
 }
 
+// ingesterScan returns an [IngesterScan] for the streams matching selector
+// between from and through.
+func (p *Planner) ingesterScan(selector Expression, shard ShardInfo, from, through time.Time, ctx *Context) (*IngesterScan, error) {
+	matchers, err := expressionToMatchers(selector, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert selector expression into matchers: %w", err)
+	}
+
+	scan := &IngesterScan{
+		Selector:  syntax.MatchersString(matchers),
+		Shard:     shard,
+		Start:     from,
+		End:       through,
+		Direction: ctx.direction,
+	}
+	p.plan.graph.Add(scan)
+	return scan, nil
+}
+
 // Convert [logical.Select] into one [Filter] node.
 func (p *Planner) processSelect(lp *logical.Select, ctx *Context) ([]Node, error) {
 	node := &Filter{
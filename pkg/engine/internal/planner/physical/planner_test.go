@@ -614,3 +614,75 @@ func TestPlanner_OverlappingShardDescriptors(t *testing.T) {
 		})
 	}
 }
+
+func TestPlanner_MakeTable_IngestersFrom(t *testing.T) {
+	now := time.Now()
+	ingestersFrom := now.Add(-time.Hour)
+	catalog := &catalog{
+		sectionDescriptors: []*metastore.DataobjSectionDescriptor{
+			{SectionKey: metastore.SectionKey{ObjectPath: "obj1", SectionIdx: 1}, StreamIDs: []int64{1, 2}, Start: now.Add(-3 * time.Hour), End: now.Add(-2 * time.Hour)},
+		},
+	}
+
+	b := logical.NewBuilder(
+		&logical.MakeTable{
+			Selector: &logical.BinOp{
+				Left:  logical.NewColumnRef("app", types.ColumnTypeLabel),
+				Right: logical.NewLiteral("users"),
+				Op:    types.BinaryOpEq,
+			},
+			Shard: logical.NewShard(0, 1), // no sharding
+		},
+	)
+	logicalPlan, err := b.ToPlan()
+	require.NoError(t, err)
+
+	scans := func(t *testing.T, from, through time.Time) ([]*DataObjScan, []*IngesterScan) {
+		planner := NewPlanner(NewContext(from, through).WithIngestersFrom(ingestersFrom), catalog)
+		plan, err := planner.Build(logicalPlan)
+		require.NoError(t, err)
+
+		var (
+			objectScans   []*DataObjScan
+			ingesterScans []*IngesterScan
+		)
+		for node := range plan.graph.Nodes() {
+			switch node := node.(type) {
+			case *DataObjScan:
+				objectScans = append(objectScans, node)
+			case *IngesterScan:
+				ingesterScans = append(ingesterScans, node)
+			}
+		}
+		return objectScans, ingesterScans
+	}
+
+	t.Run("spanning both", func(t *testing.T) {
+		objectScans, ingesterScans := scans(t, now.Add(-4*time.Hour), now)
+
+		require.Len(t, objectScans, 1)
+		require.Equal(t, []Expression{&BinaryExpr{
+			Left:  newColumnExpr(types.ColumnNameBuiltinTimestamp, types.ColumnTypeBuiltin),
+			Right: NewLiteral(types.Timestamp(ingestersFrom.UTC().UnixNano())),
+			Op:    types.BinaryOpLt,
+		}}, objectScans[0].Predicates)
+
+		require.Len(t, ingesterScans, 1)
+		require.Equal(t, `{app="users"}`, ingesterScans[0].Selector)
+		require.Equal(t, ingestersFrom, ingesterScans[0].Start)
+		require.Equal(t, now, ingesterScans[0].End)
+	})
+
+	t.Run("only recent logs", func(t *testing.T) {
+		objectScans, ingesterScans := scans(t, now.Add(-30*time.Minute), now)
+		require.Empty(t, objectScans)
+		require.Len(t, ingesterScans, 1)
+		require.Equal(t, now.Add(-30*time.Minute), ingesterScans[0].Start)
+	})
+
+	t.Run("only flushed logs", func(t *testing.T) {
+		objectScans, ingesterScans := scans(t, now.Add(-4*time.Hour), now.Add(-2*time.Hour))
+		require.Len(t, objectScans, 1)
+		require.Empty(t, ingesterScans)
+	})
+}
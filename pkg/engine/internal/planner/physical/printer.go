@@ -35,6 +35,21 @@ func toTreeNode(n Node) *tree.Node {
 			tree.NewProperty("section_id", false, node.Section),
 			tree.NewProperty("projections", true, toAnySlice(node.Projections)...),
 		}
+		if node.Tenant != "" {
+			treeNode.Properties = append(treeNode.Properties, tree.NewProperty("tenant", false, node.Tenant))
+		}
+		for i := range node.Predicates {
+			treeNode.Properties = append(treeNode.Properties, tree.NewProperty(fmt.Sprintf("predicate[%d]", i), false, node.Predicates[i].String()))
+		}
+	case *IngesterScan:
+		treeNode.Properties = []tree.Property{
+			tree.NewProperty("selector", false, node.Selector),
+			tree.NewProperty("shard", false, node.Shard),
+			tree.NewProperty("start", false, node.Start),
+			tree.NewProperty("end", false, node.End),
+			tree.NewProperty("direction", false, node.Direction),
+			tree.NewProperty("projections", true, toAnySlice(node.Projections)...),
+		}
 		for i := range node.Predicates {
 			treeNode.Properties = append(treeNode.Properties, tree.NewProperty(fmt.Sprintf("predicate[%d]", i), false, node.Predicates[i].String()))
 		}
@@ -92,6 +107,17 @@ func toTreeNode(n Node) *tree.Node {
 			tree.NewProperty("nulls_first", false, node.NullsFirst),
 			tree.NewProperty("k", false, node.K),
 		}
+	case *Exchange:
+		if node.Fragment != nil {
+			treeNode.Properties = []tree.Property{
+				tree.NewProperty("nodes", false, node.Fragment.Len()),
+			}
+			// Print the fragment like a subtree of the Exchange so that
+			// distributed plans remain readable in logs.
+			if root, err := node.Fragment.Root(); err == nil {
+				treeNode.Children = append(treeNode.Children, toTree(node.Fragment, root))
+			}
+		}
 	}
 	return treeNode
 }
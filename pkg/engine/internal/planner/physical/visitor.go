@@ -17,4 +17,6 @@ type Visitor interface {
 	VisitCompat(*ColumnCompat) error
 	VisitTopK(*TopK) error
 	VisitParallelize(*Parallelize) error
+	VisitExchange(*Exchange) error
+	VisitIngesterScan(*IngesterScan) error
 }
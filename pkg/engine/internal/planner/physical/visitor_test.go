@@ -114,3 +114,13 @@ func (v *nodeCollectVisitor) VisitParallelize(n *Parallelize) error {
 	v.visited = append(v.visited, fmt.Sprintf("%s.%s", n.Type().String(), n.ID()))
 	return nil
 }
+
+func (v *nodeCollectVisitor) VisitExchange(n *Exchange) error {
+	v.visited = append(v.visited, fmt.Sprintf("%s.%s", n.Type().String(), n.ID()))
+	return nil
+}
+
+func (v *nodeCollectVisitor) VisitIngesterScan(n *IngesterScan) error {
+	v.visited = append(v.visited, fmt.Sprintf("%s.%s", n.Type().String(), n.ID()))
+	return nil
+}
@@ -58,6 +58,8 @@ const (
 	BinaryOpNotMatchRe      // Regular expression non-matching operation (!~). Used for regex match filter and label matcher.
 	BinaryOpMatchPattern    // Pattern matching operation (|>). Used for pattern match filter.
 	BinaryOpNotMatchPattern // Pattern non-matching operation (!>). Use for pattern match filter.
+
+	BinaryOpSample // Sampling operation (| sample). Keeps a deterministic ratio of rows, hashed by stream labels, timestamp and line.
 )
 
 // String returns a human-readable representation of the binary operation kind.
@@ -105,6 +107,8 @@ func (t BinaryOp) String() string {
 		return "MATCH_PAT"
 	case BinaryOpNotMatchPattern:
 		return "NOT_MATCH_PAT" // convenience for NOT(MATCH_PAT(...))
+	case BinaryOpSample:
+		return "SAMPLE"
 	default:
 		panic(fmt.Sprintf("unknown binary operator %d", t))
 	}
@@ -0,0 +1,96 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/loki/v3/pkg/logql"
+	"github.com/grafana/loki/v3/pkg/logql/log"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
+	"github.com/grafana/loki/v3/pkg/util/validation"
+)
+
+// queryLimits are the limits of a query. The limits of queries across
+// several tenants are the most restrictive limits of these tenants.
+type queryLimits struct {
+	timeout     time.Duration
+	maxInterval time.Duration
+	maxSeries   int
+}
+
+func newQueryLimits(ctx context.Context, limits logql.Limits, tenants []string) queryLimits {
+	if limits == nil {
+		return queryLimits{}
+	}
+	return queryLimits{
+		timeout: validation.SmallestPositiveNonZeroDurationPerTenant(tenants, func(id string) time.Duration {
+			return limits.QueryTimeout(ctx, id)
+		}),
+		maxInterval: validation.SmallestPositiveNonZeroDurationPerTenant(tenants, func(id string) time.Duration {
+			return limits.MaxQueryRange(ctx, id)
+		}),
+		maxSeries: validation.SmallestPositiveIntPerTenant(tenants, func(id string) int {
+			return limits.MaxQuerySeries(ctx, id)
+		}),
+	}
+}
+
+// checkInterval returns an error if a range of expr is larger than the
+// maximum query range.
+func (l queryLimits) checkInterval(expr syntax.Expr) error {
+	if l.maxInterval == 0 {
+		return nil
+	}
+
+	var err error
+	expr.Walk(func(e syntax.Expr) bool {
+		if e, ok := e.(*syntax.LogRangeExpr); ok && e.Interval > l.maxInterval {
+			err = fmt.Errorf("%w: [%s] > [%s]", logqlmodel.ErrIntervalLimit, model.Duration(e.Interval), model.Duration(l.maxInterval))
+		}
+		return true
+	})
+	return err
+}
+
+// checkSeries returns an error if a metric query result has more series than
+// the maximum number of series.
+func (l queryLimits) checkSeries(data parser.Value) error {
+	if l.maxSeries == 0 {
+		return nil
+	}
+
+	var series int
+	switch data := data.(type) {
+	case promql.Vector:
+		series = len(data)
+	case promql.Matrix:
+		series = len(data)
+	}
+	if series > l.maxSeries {
+		return logqlmodel.NewSeriesLimitError(l.maxSeries)
+	}
+	return nil
+}
+
+// redactionEnabled returns whether the entries of one of the tenants are
+// redacted at query time. Tenants with an invalid redaction config are
+// reported too, so that their queries fail in the legacy engine.
+func redactionEnabled(limits logql.Limits, tenants []string) bool {
+	redaction, ok := limits.(log.RedactionLimits)
+	if !ok {
+		return false
+	}
+	for _, id := range tenants {
+		redactor, _, err := redaction.QueryRedaction(id)
+		if err != nil || redactor != nil {
+			return true
+		}
+	}
+	return false
+}
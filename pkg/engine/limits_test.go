@@ -0,0 +1,83 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logql/log"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
+	"github.com/grafana/loki/v3/pkg/util/validation"
+)
+
+type tenantLimits map[string]queryLimits
+
+func (l tenantLimits) MaxQuerySeries(_ context.Context, id string) int { return l[id].maxSeries }
+func (l tenantLimits) MaxQueryRange(_ context.Context, id string) time.Duration {
+	return l[id].maxInterval
+}
+func (l tenantLimits) QueryTimeout(_ context.Context, id string) time.Duration { return l[id].timeout }
+func (l tenantLimits) BlockedQueries(context.Context, string) []*validation.BlockedQuery {
+	return nil
+}
+func (l tenantLimits) EnableMultiVariantQueries(string) bool { return false }
+
+func TestQueryLimits(t *testing.T) {
+	limits := tenantLimits{
+		"tenant-a": {timeout: time.Minute, maxInterval: time.Hour, maxSeries: 5},
+		"tenant-b": {timeout: 2 * time.Minute, maxInterval: 0, maxSeries: 2},
+	}
+
+	l := newQueryLimits(context.Background(), limits, []string{"tenant-a", "tenant-b"})
+	require.Equal(t, queryLimits{timeout: time.Minute, maxInterval: time.Hour, maxSeries: 2}, l)
+
+	require.NoError(t, l.checkInterval(syntax.MustParseExpr(`count_over_time({app="foo"}[1h])`)))
+	require.ErrorIs(t, l.checkInterval(syntax.MustParseExpr(`count_over_time({app="foo"}[2h])`)), logqlmodel.ErrIntervalLimit)
+
+	vector := promql.Vector{
+		{Metric: labels.FromStrings("app", "foo")},
+		{Metric: labels.FromStrings("app", "bar")},
+	}
+	require.NoError(t, l.checkSeries(vector))
+	require.Equal(t, logqlmodel.NewSeriesLimitError(2), l.checkSeries(append(vector, promql.Sample{Metric: labels.FromStrings("app", "baz")})))
+
+	// Single tenant queries only honour the limits of the tenant.
+	l = newQueryLimits(context.Background(), limits, []string{"tenant-b"})
+	require.Equal(t, queryLimits{timeout: 2 * time.Minute, maxSeries: 2}, l)
+	require.NoError(t, l.checkInterval(syntax.MustParseExpr(`count_over_time({app="foo"}[2h])`)))
+}
+
+type redactionLimits struct {
+	tenantLimits
+	redacted map[string]error
+}
+
+func (l redactionLimits) QueryRedaction(id string) (*log.Redactor, map[string]string, error) {
+	err, ok := l.redacted[id]
+	if !ok {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	redactor, err := log.NewRedactor(nil, nil)
+	return redactor, nil, err
+}
+
+func TestRedactionEnabled(t *testing.T) {
+	require.False(t, redactionEnabled(tenantLimits{}, []string{"tenant-a"}), "limits without redaction")
+
+	limits := redactionLimits{redacted: map[string]error{
+		"tenant-b": nil,
+		"tenant-c": errors.New("invalid redaction config"),
+	}}
+	require.False(t, redactionEnabled(limits, []string{"tenant-a"}))
+	require.True(t, redactionEnabled(limits, []string{"tenant-a", "tenant-b"}))
+	require.True(t, redactionEnabled(limits, []string{"tenant-c"}), "invalid configs fail closed")
+}
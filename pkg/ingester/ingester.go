@@ -125,6 +125,15 @@ type Config struct {
 	// Optional wrapper that can be used to modify the behaviour of the ingester
 	Wrapper Wrapper `yaml:"-"`
 
+	// Optional provider of per-tenant dictionaries used with the zstd-dict chunk encoding.
+	Dictionaries DictionaryProvider `yaml:"-"`
+
+	// Optional provider of the lookup tables used by the lookup stage of queries.
+	LookupTables lokilog.LookupTables `yaml:"-"`
+
+	// Optional redaction config of tenants applied by queries.
+	RedactionLimits lokilog.RedactionLimits `yaml:"-"`
+
 	IndexShards int `yaml:"index_shards"`
 
 	MaxDroppedStreams int `yaml:"max_dropped_streams"`
@@ -203,6 +212,13 @@ func (cfg *KafkaIngestionConfig) RegisterFlags(f *flag.FlagSet) {
 	f.BoolVar(&cfg.Enabled, "ingester.kafka-ingestion-enabled", false, "Whether the ingester will consume data from kafka.")
 }
 
+// DictionaryProvider provides the zstd dictionaries to compress the chunks of
+// a tenant with and collects the samples to train them from.
+type DictionaryProvider interface {
+	Dictionary(tenant string) *compression.Dictionary
+	Sample(tenant string, entries []logproto.Entry)
+}
+
 type Wrapper interface {
 	Wrap(wrapped Interface) Interface
 }
@@ -1587,6 +1603,14 @@ func (i *Ingester) tail(req *logproto.TailRequest, queryServer logproto.Querier_
 	if !ok {
 		return fmt.Errorf("unsupported query expression: want (LogSelectorExpr), got (%T)", req.Plan.AST)
 	}
+	expr, err = syntax.BindLookupTables(queryServer.Context(), expr, i.cfg.LookupTables)
+	if err != nil {
+		return err
+	}
+	expr, err = syntax.BindRedaction(queryServer.Context(), expr, i.cfg.RedactionLimits)
+	if err != nil {
+		return err
+	}
 
 	tailer, err := newTailer(instanceID, expr, queryServer, i.cfg.MaxDroppedStreams)
 	if err != nil {
@@ -28,6 +28,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/analytics"
 	"github.com/grafana/loki/v3/pkg/chunkenc"
 	"github.com/grafana/loki/v3/pkg/compactor/retention"
+	"github.com/grafana/loki/v3/pkg/compression"
 	"github.com/grafana/loki/v3/pkg/distributor/writefailures"
 	"github.com/grafana/loki/v3/pkg/ingester/index"
 	"github.com/grafana/loki/v3/pkg/ingester/wal"
@@ -259,6 +260,10 @@ func (i *instance) Push(ctx context.Context, req *logproto.PushRequest) error {
 
 		_, appendErr = s.Push(ctx, reqStream.Entries, record, 0, false, rateLimitWholeStream, i.customStreamsTracker, req.Format)
 		s.chunkMtx.Unlock()
+
+		if appendErr == nil && i.cfg.parsedEncoding == compression.ZstdDict && i.cfg.Dictionaries != nil {
+			i.cfg.Dictionaries.Sample(i.instanceID, reqStream.Entries)
+		}
 	}
 
 	if !record.IsEmpty() {
@@ -487,6 +492,14 @@ func (i *instance) query(ctx context.Context, req logql.SelectLogParams) (iter.E
 	if err != nil {
 		return nil, err
 	}
+	expr, err = syntax.BindLookupTables(ctx, expr, i.cfg.LookupTables)
+	if err != nil {
+		return nil, err
+	}
+	expr, err = syntax.BindRedaction(ctx, expr, i.cfg.RedactionLimits)
+	if err != nil {
+		return nil, err
+	}
 
 	pipeline, err := expr.Pipeline()
 	if err != nil {
@@ -510,7 +523,11 @@ func (i *instance) query(ctx context.Context, req logql.SelectLogParams) (iter.E
 	stats := stats.FromContext(ctx)
 	var iters []iter.EntryIterator
 
-	shard, err := parseShardFromRequest(req.Shards)
+	shards, keep, err := logql.DedupShards(req.Shards, expr)
+	if err != nil {
+		return nil, err
+	}
+	shard, err := parseShardFromRequest(shards)
 	if err != nil {
 		return nil, err
 	}
@@ -521,6 +538,9 @@ func (i *instance) query(ctx context.Context, req logql.SelectLogParams) (iter.E
 		expr.Matchers(),
 		shard,
 		func(stream *stream) error {
+			if keep != nil && !keep(stream.labels) {
+				return nil
+			}
 			iter, err := stream.Iterator(ctx, stats, req.Start, req.End, req.Direction, pipeline.ForStream(stream.labels))
 			if err != nil {
 				return err
@@ -547,6 +567,14 @@ func (i *instance) querySample(ctx context.Context, req logql.SelectSampleParams
 	if err != nil {
 		return nil, err
 	}
+	expr, err = syntax.BindLookupTables(ctx, expr, i.cfg.LookupTables)
+	if err != nil {
+		return nil, err
+	}
+	expr, err = syntax.BindRedaction(ctx, expr, i.cfg.RedactionLimits)
+	if err != nil {
+		return nil, err
+	}
 
 	extractors, err := expr.Extractors()
 	if err != nil {
@@ -575,7 +603,11 @@ func (i *instance) querySample(ctx context.Context, req logql.SelectSampleParams
 	stats := stats.FromContext(ctx)
 	var iters []iter.SampleIterator
 
-	shard, err := parseShardFromRequest(req.Shards)
+	shards, keep, err := logql.DedupShards(req.Shards, expr)
+	if err != nil {
+		return nil, err
+	}
+	shard, err := parseShardFromRequest(shards)
 	if err != nil {
 		return nil, err
 	}
@@ -589,6 +621,9 @@ func (i *instance) querySample(ctx context.Context, req logql.SelectSampleParams
 		selector.Matchers(),
 		shard,
 		func(stream *stream) error {
+			if keep != nil && !keep(stream.labels) {
+				return nil
+			}
 			streamExtractors := make([]log.StreamSampleExtractor, 0, len(extractors))
 			for _, extractor := range extractors {
 				streamExtractors = append(streamExtractors, extractor.ForStream(stream.labels))
@@ -0,0 +1,82 @@
+package iter
+
+import (
+	"context"
+	"slices"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
+)
+
+type dedupItem[T logprotoType] struct {
+	item   T
+	labels string
+}
+
+// dedupIterator skips the items identical to a previous item with the same
+// timestamp and labels, whatever their original stream.
+type dedupIterator[T logprotoType] struct {
+	StreamIterator[T]
+
+	timestamp func(T) int64
+	equal     func(a, b T) bool
+	stats     *stats.Context
+
+	// seen holds the items returned with the timestamp of the current item.
+	seen []dedupItem[T]
+}
+
+func (i *dedupIterator[T]) Next() bool {
+	for i.StreamIterator.Next() {
+		item, labels := i.StreamIterator.At(), i.StreamIterator.Labels()
+		if len(i.seen) > 0 && i.timestamp(i.seen[0].item) != i.timestamp(item) {
+			i.seen = i.seen[:0]
+		}
+		if i.isDuplicate(item, labels) {
+			i.stats.AddDuplicates(1)
+			continue
+		}
+		i.seen = append(i.seen, dedupItem[T]{item: item, labels: labels})
+		return true
+	}
+	return false
+}
+
+func (i *dedupIterator[T]) isDuplicate(item T, labels string) bool {
+	for _, s := range i.seen {
+		if s.labels == labels && i.equal(s.item, item) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewDedupEntryIterator returns an iterator over the entries of it without
+// the entries identical to a previous one: same timestamp, line, structured
+// metadata and labels. Unlike NewMergeEntryIterator, entries of different
+// original streams are deduplicated, which removes the entries of replicated
+// streams once the labels telling the replicas apart are dropped. Entries
+// must be ordered by timestamp.
+func NewDedupEntryIterator(ctx context.Context, it EntryIterator) EntryIterator {
+	return &dedupIterator[logproto.Entry]{
+		StreamIterator: it,
+		timestamp:      func(e logproto.Entry) int64 { return e.Timestamp.UnixNano() },
+		equal: func(a, b logproto.Entry) bool {
+			return a.Line == b.Line && slices.Equal(a.StructuredMetadata, b.StructuredMetadata)
+		},
+		stats: stats.FromContext(ctx),
+	}
+}
+
+// NewDedupSampleIterator is NewDedupEntryIterator for samples, which are
+// identical if they have the same timestamp, value, line hash and labels.
+func NewDedupSampleIterator(ctx context.Context, it SampleIterator) SampleIterator {
+	return &dedupIterator[logproto.Sample]{
+		StreamIterator: it,
+		timestamp:      func(s logproto.Sample) int64 { return s.Timestamp },
+		equal: func(a, b logproto.Sample) bool {
+			return a.Hash == b.Hash && a.Value == b.Value
+		},
+		stats: stats.FromContext(ctx),
+	}
+}
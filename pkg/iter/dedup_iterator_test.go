@@ -0,0 +1,83 @@
+package iter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
+)
+
+func TestDedupEntryIterator(t *testing.T) {
+	entry := func(ts int64, line string, metadata ...string) logproto.Entry {
+		e := logproto.Entry{Timestamp: time.Unix(ts, 0), Line: line}
+		for i := 0; i < len(metadata); i += 2 {
+			e.StructuredMetadata = append(e.StructuredMetadata, logproto.LabelAdapter{Name: metadata[i], Value: metadata[i+1]})
+		}
+		return e
+	}
+	// The replica label was dropped by the dedup stage, the streams of the
+	// replicas only differ by their hash.
+	it := NewSortEntryIterator([]EntryIterator{
+		NewStreamIterator(logproto.Stream{Labels: `{app="foo"}`, Hash: 1, Entries: []logproto.Entry{
+			entry(1, "a"), entry(2, "b"), entry(2, "c"), entry(3, "d", "trace_id", "1"),
+		}}),
+		NewStreamIterator(logproto.Stream{Labels: `{app="foo"}`, Hash: 2, Entries: []logproto.Entry{
+			entry(1, "a"), entry(2, "c"), entry(3, "d", "trace_id", "2"), entry(4, "e"),
+		}}),
+		NewStreamIterator(logproto.Stream{Labels: `{app="bar"}`, Hash: 3, Entries: []logproto.Entry{
+			entry(1, "a"),
+		}}),
+	}, logproto.FORWARD)
+
+	st, ctx := stats.NewContext(context.Background())
+	it = NewDedupEntryIterator(ctx, it)
+	defer it.Close()
+
+	var actual []logproto.Stream
+	for it.Next() {
+		actual = append(actual, logproto.Stream{Labels: it.Labels(), Entries: []logproto.Entry{it.At()}})
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []logproto.Stream{
+		{Labels: `{app="foo"}`, Entries: []logproto.Entry{entry(1, "a")}},
+		{Labels: `{app="bar"}`, Entries: []logproto.Entry{entry(1, "a")}},
+		{Labels: `{app="foo"}`, Entries: []logproto.Entry{entry(2, "b")}},
+		{Labels: `{app="foo"}`, Entries: []logproto.Entry{entry(2, "c")}},
+		{Labels: `{app="foo"}`, Entries: []logproto.Entry{entry(3, "d", "trace_id", "1")}},
+		{Labels: `{app="foo"}`, Entries: []logproto.Entry{entry(3, "d", "trace_id", "2")}},
+		{Labels: `{app="foo"}`, Entries: []logproto.Entry{entry(4, "e")}},
+	}, actual)
+	require.Equal(t, int64(2), st.Result(0, 0, 0).Querier.Store.Chunk.TotalDuplicates)
+}
+
+func TestDedupSampleIterator(t *testing.T) {
+	it := NewSortSampleIterator([]SampleIterator{
+		NewSeriesIterator(logproto.Series{Labels: `{app="foo"}`, StreamHash: 1, Samples: []logproto.Sample{
+			{Timestamp: 1, Hash: 1, Value: 1}, {Timestamp: 2, Hash: 2, Value: 1},
+		}}),
+		NewSeriesIterator(logproto.Series{Labels: `{app="foo"}`, StreamHash: 2, Samples: []logproto.Sample{
+			{Timestamp: 1, Hash: 1, Value: 1}, {Timestamp: 2, Hash: 3, Value: 1}, {Timestamp: 3, Hash: 4, Value: 2},
+		}}),
+	})
+
+	st, ctx := stats.NewContext(context.Background())
+	it = NewDedupSampleIterator(ctx, it)
+	defer it.Close()
+
+	var actual []logproto.Sample
+	for it.Next() {
+		actual = append(actual, it.At())
+	}
+	require.NoError(t, it.Err())
+	require.Equal(t, []logproto.Sample{
+		{Timestamp: 1, Hash: 1, Value: 1},
+		{Timestamp: 2, Hash: 2, Value: 1},
+		{Timestamp: 2, Hash: 3, Value: 1},
+		{Timestamp: 3, Hash: 4, Value: 2},
+	}, actual)
+	require.Equal(t, int64(1), st.Result(0, 0, 0).Querier.Store.Chunk.TotalDuplicates)
+}
@@ -59,6 +59,7 @@ type Client interface {
 	ListLabelValues(name string, quiet bool, start, end time.Time) (*loghttp.LabelResponse, error)
 	Series(matchers []string, start, end time.Time, quiet bool) (*loghttp.SeriesResponse, error)
 	LiveTailQueryConn(queryStr string, delayFor time.Duration, limit int, start time.Time, quiet bool) (*websocket.Conn, error)
+	TailQueryConn(queryStr string, delayFor time.Duration, limit int, start time.Time, quiet bool) (TailConn, error)
 	GetOrgID() string
 	GetStats(queryStr string, start, end time.Time, quiet bool) (*logproto.IndexStatsResponse, error)
 	GetVolume(query *volume.Query) (*loghttp.QueryResponse, error)
@@ -94,6 +95,8 @@ type DefaultClient struct {
 	BackoffConfig    BackoffConfig
 	Compression      bool
 	EnvironmentProxy bool
+	TailTransport    string
+	GRPCAddress      string
 }
 
 // Query uses the /api/v1/query endpoint to execute an instant query
@@ -174,15 +177,7 @@ func (c *DefaultClient) Series(matchers []string, start, end time.Time, quiet bo
 
 // LiveTailQueryConn uses /api/prom/tail to set up a websocket connection and returns it
 func (c *DefaultClient) LiveTailQueryConn(queryStr string, delayFor time.Duration, limit int, start time.Time, quiet bool) (*websocket.Conn, error) {
-	params := util.NewQueryStringBuilder()
-	params.SetString("query", queryStr)
-	if delayFor != 0 {
-		params.SetInt("delay_for", int64(delayFor.Seconds()))
-	}
-	params.SetInt("limit", int64(limit))
-	params.SetInt("start", start.UnixNano())
-
-	return c.wsConnect(tailPath, params.Encode(), quiet)
+	return c.wsConnect(tailPath, tailQueryParams(queryStr, delayFor, limit, start), quiet)
 }
 
 func (c *DefaultClient) GetOrgID() string {
@@ -340,41 +335,10 @@ func (c *DefaultClient) doRequest(path, query string, quiet bool, out interface{
 	}
 	req.Header = h
 
-	// Parse the URL to extract the host
-	clientConfig := config.HTTPClientConfig{
-		TLSConfig: c.TLSConfig,
-	}
-
-	if c.EnvironmentProxy {
-		clientConfig.ProxyFromEnvironment = true
-	}
-
-	if c.ProxyURL != "" {
-		prox, err := url.Parse(c.ProxyURL)
-		if err != nil {
-			return err
-		}
-		clientConfig.ProxyURL = config.URL{URL: prox}
-	}
-
-	client, err := config.NewClientFromConfig(clientConfig, "promtail", config.WithHTTP2Disabled())
-	client.Timeout = 0
+	client, err := c.httpClient()
 	if err != nil {
 		return err
 	}
-	if c.Tripperware != nil {
-		client.Transport = c.Tripperware(client.Transport)
-	}
-	if c.Compression {
-		// NewClientFromConfig() above returns an http.Client that uses a transport which
-		// has compression explicitly disabled. Here we re-enable it. If the caller
-		// defines a custom Tripperware that isn't an http.Transport then this won't work,
-		// but in that case they control the transport anyway and can configure
-		// compression that way.
-		if transport, ok := client.Transport.(*http.Transport); ok {
-			transport.DisableCompression = false
-		}
-	}
 
 	var resp *http.Response
 
@@ -442,41 +406,10 @@ func (c *DefaultClient) doPostRequest(path, query string, quiet bool) error {
 	}
 	req.Header = h
 
-	// Parse the URL to extract the host
-	clientConfig := config.HTTPClientConfig{
-		TLSConfig: c.TLSConfig,
-	}
-
-	if c.EnvironmentProxy {
-		clientConfig.ProxyFromEnvironment = true
-	}
-
-	if c.ProxyURL != "" {
-		prox, err := url.Parse(c.ProxyURL)
-		if err != nil {
-			return err
-		}
-		clientConfig.ProxyURL = config.URL{URL: prox}
-	}
-
-	client, err := config.NewClientFromConfig(clientConfig, "promtail", config.WithHTTP2Disabled())
-	client.Timeout = 0
+	client, err := c.httpClient()
 	if err != nil {
 		return err
 	}
-	if c.Tripperware != nil {
-		client.Transport = c.Tripperware(client.Transport)
-	}
-	if c.Compression {
-		// NewClientFromConfig() above returns an http.Client that uses a transport which
-		// has compression explicitly disabled. Here we re-enable it. If the caller
-		// defines a custom Tripperware that isn't an http.Transport then this won't work,
-		// but in that case they control the transport anyway and can configure
-		// compression that way.
-		if transport, ok := client.Transport.(*http.Transport); ok {
-			transport.DisableCompression = false
-		}
-	}
 
 	var resp *http.Response
 	success := false
@@ -541,41 +474,10 @@ func (c *DefaultClient) doDeleteRequest(path, query string, quiet bool) error {
 	}
 	req.Header = h
 
-	// Parse the URL to extract the host
-	clientConfig := config.HTTPClientConfig{
-		TLSConfig: c.TLSConfig,
-	}
-
-	if c.EnvironmentProxy {
-		clientConfig.ProxyFromEnvironment = true
-	}
-
-	if c.ProxyURL != "" {
-		prox, err := url.Parse(c.ProxyURL)
-		if err != nil {
-			return err
-		}
-		clientConfig.ProxyURL = config.URL{URL: prox}
-	}
-
-	client, err := config.NewClientFromConfig(clientConfig, "promtail", config.WithHTTP2Disabled())
-	client.Timeout = 0
+	client, err := c.httpClient()
 	if err != nil {
 		return err
 	}
-	if c.Tripperware != nil {
-		client.Transport = c.Tripperware(client.Transport)
-	}
-	if c.Compression {
-		// NewClientFromConfig() above returns an http.Client that uses a transport which
-		// has compression explicitly disabled. Here we re-enable it. If the caller
-		// defines a custom Tripperware that isn't an http.Transport then this won't work,
-		// but in that case they control the transport anyway and can configure
-		// compression that way.
-		if transport, ok := client.Transport.(*http.Transport); ok {
-			transport.DisableCompression = false
-		}
-	}
 
 	var resp *http.Response
 	success := false
@@ -620,6 +522,47 @@ func (c *DefaultClient) doDeleteRequest(path, query string, quiet bool) error {
 	return nil
 }
 
+// httpClient returns a client for the API requests, configured with the TLS,
+// proxy and compression settings.
+func (c *DefaultClient) httpClient() (*http.Client, error) {
+	clientConfig := config.HTTPClientConfig{
+		TLSConfig: c.TLSConfig,
+	}
+
+	if c.EnvironmentProxy {
+		clientConfig.ProxyFromEnvironment = true
+	}
+
+	if c.ProxyURL != "" {
+		prox, err := url.Parse(c.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+		clientConfig.ProxyURL = config.URL{URL: prox}
+	}
+
+	client, err := config.NewClientFromConfig(clientConfig, "promtail", config.WithHTTP2Disabled())
+	if err != nil {
+		return nil, err
+	}
+	client.Timeout = 0
+	if c.Tripperware != nil {
+		client.Transport = c.Tripperware(client.Transport)
+	}
+	if c.Compression {
+		// NewClientFromConfig() above returns an http.Client that uses a transport which
+		// has compression explicitly disabled. Here we re-enable it. If the caller
+		// defines a custom Tripperware that isn't an http.Transport then this won't work,
+		// but in that case they control the transport anyway and can configure
+		// compression that way.
+		if transport, ok := client.Transport.(*http.Transport); ok {
+			transport.DisableCompression = false
+		}
+	}
+
+	return client, nil
+}
+
 // nolint:goconst
 func (c *DefaultClient) getHTTPRequestHeader() (http.Header, error) {
 	h := make(http.Header)
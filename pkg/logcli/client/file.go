@@ -184,6 +184,10 @@ func (f *FileClient) LiveTailQueryConn(_ string, _ time.Duration, _ int, _ time.
 	return nil, fmt.Errorf("LiveTailQuery: %w", ErrNotSupported)
 }
 
+func (f *FileClient) TailQueryConn(_ string, _ time.Duration, _ int, _ time.Time, _ bool) (TailConn, error) {
+	return nil, fmt.Errorf("TailQuery: %w", ErrNotSupported)
+}
+
 func (f *FileClient) GetOrgID() string {
 	return f.orgID
 }
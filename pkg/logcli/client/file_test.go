@@ -192,6 +192,11 @@ func TestFileClient_LiveTail(t *testing.T) {
 	require.Error(t, err)
 	require.Nil(t, x)
 	assert.True(t, errors.Is(err, ErrNotSupported))
+
+	conn, err := c.TailQueryConn("", time.Second, 0, time.Now(), true)
+	require.Error(t, err)
+	require.Nil(t, conn)
+	assert.True(t, errors.Is(err, ErrNotSupported))
 }
 
 func TestFileClient_GetOrgID(t *testing.T) {
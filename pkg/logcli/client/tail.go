@@ -0,0 +1,296 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	json "github.com/json-iterator/go"
+	"github.com/prometheus/common/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/loki/v3/pkg/loghttp"
+	legacy "github.com/grafana/loki/v3/pkg/loghttp/legacy"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/util"
+	"github.com/grafana/loki/v3/pkg/util/marshal"
+	"github.com/grafana/loki/v3/pkg/util/unmarshal"
+)
+
+// Transports supported to tail logs.
+const (
+	TailTransportWebsocket = "websocket"
+	TailTransportSSE       = "sse"
+	TailTransportGRPC      = "grpc"
+
+	defaultGRPCPort = "9095"
+)
+
+// TailTransports lists the supported tail transports.
+var TailTransports = []string{TailTransportWebsocket, TailTransportSSE, TailTransportGRPC}
+
+// ErrTailDisconnected is returned by TailConn.Read when the connection was
+// closed unexpectedly, for example because the querier handling the request
+// stopped. The tail request can be sent again.
+var ErrTailDisconnected = errors.New("tail connection closed unexpectedly")
+
+// TailConn is a connection streaming the responses of a tail query.
+type TailConn interface {
+	// Read reads the next response into r.
+	Read(r *loghttp.TailResponse) error
+	Close() error
+}
+
+// TailQueryConn starts a tail query with the configured transport. The
+// websocket transport is used by default.
+func (c *DefaultClient) TailQueryConn(queryStr string, delayFor time.Duration, limit int, start time.Time, quiet bool) (TailConn, error) {
+	switch c.TailTransport {
+	case "", TailTransportWebsocket:
+		conn, err := c.LiveTailQueryConn(queryStr, delayFor, limit, start, quiet)
+		if err != nil {
+			return nil, err
+		}
+		return &websocketTailConn{conn: conn}, nil
+	case TailTransportSSE:
+		return c.sseTailConn(queryStr, delayFor, limit, start, quiet)
+	case TailTransportGRPC:
+		return c.grpcTailConn(queryStr, delayFor, limit, start, quiet)
+	default:
+		return nil, fmt.Errorf("unknown tail transport %q, expected one of %s", c.TailTransport, strings.Join(TailTransports, ", "))
+	}
+}
+
+type websocketTailConn struct {
+	conn *websocket.Conn
+}
+
+func (w *websocketTailConn) Read(r *loghttp.TailResponse) error {
+	err := unmarshal.ReadTailResponseJSON(r, w.conn)
+	// The connection closes abnormally when the querier handling the tail
+	// request stops running: "websocket: close 1006 (abnormal closure): unexpected EOF"
+	if websocket.IsCloseError(err, websocket.CloseAbnormalClosure) {
+		return fmt.Errorf("%w: %w", ErrTailDisconnected, err)
+	}
+	return err
+}
+
+func (w *websocketTailConn) Close() error {
+	return w.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+}
+
+func (c *DefaultClient) sseTailConn(queryStr string, delayFor time.Duration, limit int, start time.Time, quiet bool) (TailConn, error) {
+	us, err := buildURL(c.Address, tailPath, tailQueryParams(queryStr, delayFor, limit, start))
+	if err != nil {
+		return nil, err
+	}
+	if !quiet {
+		log.Println(us)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, us, nil)
+	if err != nil {
+		return nil, err
+	}
+	h, err := c.getHTTPRequestHeader()
+	if err != nil {
+		return nil, err
+	}
+	req.Header = h
+	req.Header.Set("Accept", "text/event-stream")
+
+	client, err := c.httpClient()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		buf, _ := io.ReadAll(resp.Body) // nolint
+		return nil, fmt.Errorf("error response from server: %s (%v)", string(buf), resp.Status)
+	}
+	return &sseTailConn{body: resp.Body, reader: bufio.NewReader(resp.Body)}, nil
+}
+
+type sseTailConn struct {
+	body   io.ReadCloser
+	reader *bufio.Reader
+}
+
+// Read reads events until the next message or error event. Comments, sent
+// to keep the connection alive, are ignored.
+func (s *sseTailConn) Read(r *loghttp.TailResponse) error {
+	var (
+		event string
+		data  bytes.Buffer
+	)
+	for {
+		line, err := s.reader.ReadBytes('\n')
+		if err != nil {
+			// The server only ends the stream on errors, which are sent as
+			// events first.
+			return fmt.Errorf("%w: %w", ErrTailDisconnected, err)
+		}
+		line = bytes.TrimRight(line, "\r\n")
+
+		switch {
+		case len(line) == 0:
+			if data.Len() == 0 {
+				continue
+			}
+			if event == "error" {
+				return fmt.Errorf("error from server: %s", data.String())
+			}
+			return json.Unmarshal(data.Bytes(), r)
+		case line[0] == ':':
+			continue
+		}
+
+		field, value, _ := bytes.Cut(line, []byte(":"))
+		value = bytes.TrimPrefix(value, []byte(" "))
+		switch string(field) {
+		case "event":
+			event = string(value)
+		case "data":
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.Write(value)
+		}
+	}
+}
+
+func (s *sseTailConn) Close() error {
+	return s.body.Close()
+}
+
+func (c *DefaultClient) grpcTailConn(queryStr string, delayFor time.Duration, limit int, start time.Time, quiet bool) (TailConn, error) {
+	addr, useTLS, err := c.grpcAddress()
+	if err != nil {
+		return nil, err
+	}
+	if !quiet {
+		log.Println("grpc://" + addr)
+	}
+
+	creds := insecure.NewCredentials()
+	if useTLS {
+		tlsConfig, err := config.NewTLSConfig(&c.TLSConfig)
+		if err != nil {
+			return nil, err
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(creds), grpc.WithUserAgent(userAgent))
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := c.getHTTPRequestHeader()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	md := metadata.MD{}
+	for k, v := range h {
+		if k == "User-Agent" {
+			continue
+		}
+		md.Set(k, v...)
+	}
+
+	ctx, cancel := context.WithCancel(metadata.NewOutgoingContext(context.Background(), md))
+	stream, err := logproto.NewLogTailClient(conn).Tail(ctx, &logproto.TailRequest{
+		Query:    queryStr,
+		DelayFor: uint32(delayFor.Seconds()),
+		Limit:    uint32(limit),
+		Start:    start,
+	})
+	if err != nil {
+		cancel()
+		conn.Close()
+		return nil, err
+	}
+	return &grpcTailConn{conn: conn, stream: stream, cancel: cancel}, nil
+}
+
+// grpcAddress returns the gRPC address to tail from, by default the host of
+// the server address on the default gRPC port, and whether to use TLS.
+func (c *DefaultClient) grpcAddress() (string, bool, error) {
+	u, err := url.Parse(c.Address)
+	if err != nil {
+		return "", false, err
+	}
+	addr := c.GRPCAddress
+	if addr == "" {
+		addr = net.JoinHostPort(u.Hostname(), defaultGRPCPort)
+	}
+	return addr, u.Scheme == "https", nil
+}
+
+type grpcTailConn struct {
+	conn   *grpc.ClientConn
+	stream logproto.LogTail_TailClient
+	cancel context.CancelFunc
+}
+
+func (g *grpcTailConn) Read(r *loghttp.TailResponse) error {
+	resp, err := g.stream.Recv()
+	if err != nil {
+		if err == io.EOF || status.Code(err) == codes.Unavailable {
+			return fmt.Errorf("%w: %w", ErrTailDisconnected, err)
+		}
+		return err
+	}
+
+	r.Streams = r.Streams[:0]
+	for _, s := range resp.Streams {
+		stream, err := marshal.NewStream(s)
+		if err != nil {
+			return err
+		}
+		r.Streams = append(r.Streams, stream)
+	}
+	r.DroppedStreams = r.DroppedStreams[:0]
+	for _, d := range resp.DroppedEntries {
+		dropped, err := marshal.NewDroppedStream(&legacy.DroppedEntry{Timestamp: d.Timestamp, Labels: d.Labels})
+		if err != nil {
+			return err
+		}
+		r.DroppedStreams = append(r.DroppedStreams, dropped)
+	}
+	return nil
+}
+
+func (g *grpcTailConn) Close() error {
+	g.cancel()
+	return g.conn.Close()
+}
+
+func tailQueryParams(queryStr string, delayFor time.Duration, limit int, start time.Time) string {
+	params := util.NewQueryStringBuilder()
+	params.SetString("query", queryStr)
+	if delayFor != 0 {
+		params.SetInt("delay_for", int64(delayFor.Seconds()))
+	}
+	params.SetInt("limit", int64(limit))
+	params.SetInt("start", start.UnixNano())
+	return params.Encode()
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/loghttp"
+)
+
+func TestSSETailConn_Read(t *testing.T) {
+	body := ": ping\n\n" +
+		"data: {\"streams\":[{\"stream\":{\"app\":\"loki\"},\"values\":[[\"1\",\"line 1\"]]}]}\n\n" +
+		": ping\n\n" +
+		"data: {\"streams\":[{\"stream\":{\"app\":\"loki\"},\n" +
+		"data: \"values\":[[\"2\",\"line 2\"]]}]}\n\n" +
+		"event: error\ndata: ingester stopped\n\n"
+	conn := &sseTailConn{body: io.NopCloser(strings.NewReader(body)), reader: bufio.NewReader(strings.NewReader(body))}
+
+	var resp loghttp.TailResponse
+	require.NoError(t, conn.Read(&resp))
+	require.Equal(t, loghttp.LabelSet{"app": "loki"}, resp.Streams[0].Labels)
+	require.Equal(t, "line 1", resp.Streams[0].Entries[0].Line)
+
+	resp = loghttp.TailResponse{}
+	require.NoError(t, conn.Read(&resp))
+	require.Equal(t, "line 2", resp.Streams[0].Entries[0].Line)
+
+	err := conn.Read(&resp)
+	require.EqualError(t, err, "error from server: ingester stopped")
+	require.False(t, errors.Is(err, ErrTailDisconnected))
+
+	require.ErrorIs(t, conn.Read(&resp), ErrTailDisconnected)
+}
+
+func TestDefaultClient_TailQueryConn_UnknownTransport(t *testing.T) {
+	c := &DefaultClient{Address: "http://localhost:3100", TailTransport: "carrier-pigeon"}
+	_, err := c.TailQueryConn(`{app="loki"}`, 0, 10, time.Now(), true)
+	require.ErrorContains(t, err, `unknown tail transport "carrier-pigeon"`)
+}
+
+func TestDefaultClient_grpcAddress(t *testing.T) {
+	addr, useTLS, err := (&DefaultClient{Address: "https://loki.example.com"}).grpcAddress()
+	require.NoError(t, err)
+	require.Equal(t, "loki.example.com:9095", addr)
+	require.True(t, useTLS)
+
+	addr, useTLS, err = (&DefaultClient{Address: "http://localhost:3100", GRPCAddress: "querier:9096"}).grpcAddress()
+	require.NoError(t, err)
+	require.Equal(t, "querier:9096", addr)
+	require.False(t, useTLS)
+}
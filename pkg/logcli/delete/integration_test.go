@@ -320,6 +320,10 @@ func (m *workflowMockClient) LiveTailQueryConn(string, time.Duration, int, time.
 	panic("not implemented")
 }
 
+func (m *workflowMockClient) TailQueryConn(string, time.Duration, int, time.Time, bool) (client.TailConn, error) {
+	panic("not implemented")
+}
+
 func (m *workflowMockClient) GetOrgID() string {
 	return "test-org"
 }
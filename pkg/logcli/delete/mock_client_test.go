@@ -114,6 +114,10 @@ func (m *mockDeleteClient) LiveTailQueryConn(_ string, _ time.Duration, _ int, _
 	panic("not implemented")
 }
 
+func (m *mockDeleteClient) TailQueryConn(_ string, _ time.Duration, _ int, _ time.Time, _ bool) (client.TailConn, error) {
+	panic("not implemented")
+}
+
 func (m *mockDeleteClient) GetOrgID() string {
 	return "test-org"
 }
@@ -470,6 +470,10 @@ func (t *testQueryClient) LiveTailQueryConn(_ string, _ time.Duration, _ int, _
 	panic("implement me")
 }
 
+func (t *testQueryClient) TailQueryConn(_ string, _ time.Duration, _ int, _ time.Time, _ bool) (logcli_client.TailConn, error) {
+	panic("implement me")
+}
+
 func (t *testQueryClient) GetOrgID() string {
 	panic("implement me")
 }
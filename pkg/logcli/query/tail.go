@@ -2,6 +2,7 @@ package query
 
 import (
 	"context"
+	"errors"
 	"log"
 	"os"
 	"os/signal"
@@ -10,19 +11,18 @@ import (
 	"time"
 
 	"github.com/fatih/color"
-	"github.com/gorilla/websocket"
 	"github.com/grafana/dskit/backoff"
 
 	"github.com/grafana/loki/v3/pkg/logcli/client"
 	"github.com/grafana/loki/v3/pkg/logcli/output"
 	"github.com/grafana/loki/v3/pkg/logcli/util"
 	"github.com/grafana/loki/v3/pkg/loghttp"
-	"github.com/grafana/loki/v3/pkg/util/unmarshal"
 )
 
-// TailQuery connects to the Loki websocket endpoint and tails logs
+// TailQuery connects to the Loki tail endpoint, with the transport configured
+// in the client, and tails logs
 func (q *Query) TailQuery(delayFor time.Duration, c client.Client, out output.LogOutput) {
-	conn, err := c.LiveTailQueryConn(q.QueryString, delayFor, q.Limit, q.Start, q.Quiet)
+	conn, err := c.TailQueryConn(q.QueryString, delayFor, q.Limit, q.Start, q.Quiet)
 	if err != nil {
 		log.Fatalf("Tailing logs failed: %+v", err)
 	}
@@ -31,8 +31,8 @@ func (q *Query) TailQuery(delayFor time.Duration, c client.Client, out output.Lo
 		stopChan := make(chan os.Signal, 1)
 		signal.Notify(stopChan, os.Interrupt, syscall.SIGTERM)
 		<-stopChan
-		if err := conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")); err != nil {
-			log.Println("Error closing websocket:", err)
+		if err := conn.Close(); err != nil {
+			log.Println("Error closing tail connection:", err)
 		}
 		os.Exit(0)
 	}()
@@ -49,18 +49,17 @@ func (q *Query) TailQuery(delayFor time.Duration, c client.Client, out output.Lo
 
 	for {
 		tailResponse := new(loghttp.TailResponse)
-		err := unmarshal.ReadTailResponseJSON(tailResponse, conn)
+		err := conn.Read(tailResponse)
 		if err != nil {
-			// Check if the websocket connection closed unexpectedly. If so, retry.
+			// Check if the connection closed unexpectedly. If so, retry.
 			// The connection might close unexpectedly if the querier handling the tail request
-			// in Loki stops running. The following error would be printed:
-			// "websocket: close 1006 (abnormal closure): unexpected EOF"
-			if websocket.IsCloseError(err, websocket.CloseAbnormalClosure) {
-				log.Printf("Remote websocket connection closed unexpectedly (%+v). Connecting again.", err)
+			// in Loki stops running.
+			if errors.Is(err, client.ErrTailDisconnected) {
+				log.Printf("Remote tail connection closed unexpectedly (%+v). Connecting again.", err)
 
 				// Close previous connection. If it fails to close the connection it should be fine as it is already broken.
-				if err = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")); err != nil {
-					log.Printf("Error closing websocket: %+v", err)
+				if err = conn.Close(); err != nil {
+					log.Printf("Error closing tail connection: %+v", err)
 				}
 
 				// Try to re-establish the connection up to 5 times.
@@ -71,7 +70,7 @@ func (q *Query) TailQuery(delayFor time.Duration, c client.Client, out output.Lo
 				})
 
 				for backoff.Ongoing() {
-					conn, err = c.LiveTailQueryConn(q.QueryString, delayFor, q.Limit, lastReceivedTimestamp, q.Quiet)
+					conn, err = c.TailQueryConn(q.QueryString, delayFor, q.Limit, lastReceivedTimestamp, q.Quiet)
 					if err == nil {
 						break
 					}
@@ -33,6 +33,8 @@ import (
 
 const (
 	pbContentType       = "application/x-protobuf"
+	grpcContentType     = "application/grpc"
+	grpcLogsExportPath  = "/opentelemetry.proto.collector.logs.v1.LogsService/Export"
 	gzipContentEncoding = "gzip"
 	zstdContentEncoding = "zstd"
 	lz4ContentEncoding  = "lz4"
@@ -55,6 +57,23 @@ func ParseOTLPRequest(userID string, r *http.Request, limits Limits, tenantConfi
 	return req, stats, err
 }
 
+// ParseOTLPExportRequest converts an OTLP export request received over gRPC into a Loki push request.
+// It applies the same attribute mapping as the HTTP endpoint and records the same ingestion stats as ParseRequest.
+func ParseOTLPExportRequest(ctx context.Context, logger log.Logger, userID string, exportReq plogotlp.ExportRequest, limits Limits, tenantConfigs *runtime.TenantConfigs, tracker UsageTracker, streamResolver StreamResolver, userAgent string) (*logproto.PushRequest, *Stats, error) {
+	stats := NewPushStats()
+	stats.ContentType = grpcContentType
+	otlpLogs := exportReq.Logs()
+	stats.BodySize = int64((&plog.ProtoMarshaler{}).LogsSize(otlpLogs))
+
+	req, err := otlpToLokiPushRequest(ctx, otlpLogs, userID, limits.OTLPConfig(userID), tenantConfigs, limits.DiscoverServiceName(userID), tracker, stats, logger, streamResolver, constants.OTLP)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recordPushStats(logger, userID, grpcLogsExportPath, userAgent, "", req, stats, tenantConfigs, constants.OTLP)
+	return req, stats, nil
+}
+
 func extractLogs(r *http.Request, maxRecvMsgSize int, pushStats *Stats) (plog.Logs, error) {
 	pushStats.ContentEncoding = r.Header.Get(contentEnc)
 	// bodySize should always reflect the compressed size of the request body
@@ -222,6 +241,9 @@ func otlpToLokiPushRequest(ctx context.Context, ld plog.Logs, userID string, otl
 
 		if err := streamLabels.Validate(); err != nil {
 			stats.Errs = append(stats.Errs, fmt.Errorf("invalid labels: %w", err))
+			for j := 0; j < sls.Len(); j++ {
+				stats.InvalidLabelsRecords += int64(sls.At(j).LogRecords().Len())
+			}
 			continue
 		}
 		labelsStr := streamLabels.String()
@@ -350,6 +372,7 @@ func otlpToLokiPushRequest(ctx context.Context, ld plog.Logs, userID string, otl
 
 					if err := combinedLabels.Validate(); err != nil {
 						stats.Errs = append(stats.Errs, fmt.Errorf("invalid labels with log attributes: %w", err))
+						stats.InvalidLabelsRecords++
 						continue
 					}
 
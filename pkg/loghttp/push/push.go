@@ -25,6 +25,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/prometheus/model/labels"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
 
 	"github.com/grafana/loki/v3/pkg/analytics"
 	"github.com/grafana/loki/v3/pkg/loghttp"
@@ -164,6 +165,10 @@ type Stats struct {
 	ContentEncoding  string
 
 	BodySize int64
+
+	// InvalidLabelsRecords holds the number of records dropped while parsing because they resulted in invalid stream labels.
+	InvalidLabelsRecords int64
+
 	// Extra is a place for a wrapped parser to record any interesting stats as key-value pairs to be logged
 	Extra []any
 
@@ -179,6 +184,14 @@ func ParseRequest(logger log.Logger, userID string, maxRecvMsgSize int, r *http.
 		return nil, nil, err
 	}
 
+	recordPushStats(logger, userID, r.URL.Path, r.Header.Get("User-Agent"), presumedAgentIP, req, pushStats, tenantConfigs, format)
+
+	return req, pushStats, err
+}
+
+// recordPushStats updates the ingestion metrics for a parsed push request and
+// logs a summary of it. It is shared by all push protocols.
+func recordPushStats(logger log.Logger, userID, path, userAgent, presumedAgentIP string, req *logproto.PushRequest, pushStats *Stats, tenantConfigs *runtime.TenantConfigs, format string) {
 	var (
 		entriesSize            int64
 		structuredMetadataSize int64
@@ -244,7 +257,7 @@ func ParseRequest(logger log.Logger, userID string, maxRecvMsgSize int, r *http.
 
 	logValues := []interface{}{
 		"msg", "push request parsed",
-		"path", r.URL.Path,
+		"path", path,
 		"contentType", pushStats.ContentType,
 		"contentEncoding", pushStats.ContentEncoding,
 		"bodySize", humanize.Bytes(uint64(pushStats.BodySize)),
@@ -261,7 +274,6 @@ func ParseRequest(logger log.Logger, userID string, maxRecvMsgSize int, r *http.
 		logValues = append(logValues, "presumedAgentIp", presumedAgentIP)
 	}
 
-	userAgent := r.Header.Get("User-Agent")
 	if userAgent != "" {
 		logValues = append(logValues, "userAgent", strings.TrimSpace(userAgent))
 	}
@@ -297,8 +309,6 @@ func ParseRequest(logger log.Logger, userID string, maxRecvMsgSize int, r *http.
 
 	logValues = append(logValues, pushStats.Extra...)
 	level.Debug(logger).Log(logValues...)
-
-	return req, pushStats, err
 }
 
 // parsePushRequestBody returns logproto.PushRequest from http.Request body, deserialized according to specified content type.
@@ -571,6 +581,37 @@ func OTLPError(w http.ResponseWriter, errorStr string, code int, logger log.Logg
 
 var _ ErrorWriter = OTLPError
 
+// OTLPPartialSuccess writes a successful OTLP export response that reports the rejected log records.
+// The response is encoded with the content type of the request, as required by the OTLP specification.
+func OTLPPartialSuccess(w http.ResponseWriter, r *http.Request, rejected int64, errorStr string, logger log.Logger) {
+	resp := plogotlp.NewExportResponse()
+	resp.PartialSuccess().SetRejectedLogRecords(rejected)
+	resp.PartialSuccess().SetErrorMessage(errorStr)
+
+	var (
+		respBytes []byte
+		err       error
+	)
+	respContentType := r.Header.Get(contentType)
+	if respContentType == applicationJSON {
+		respBytes, err = resp.MarshalJSON()
+	} else {
+		respContentType = pbContentType
+		respBytes, err = resp.MarshalProto()
+	}
+	if err != nil {
+		level.Error(logger).Log("msg", "failed to marshal partial success response", "error", err)
+		OTLPError(w, fmt.Sprintf("failed to marshal partial success response: %s", err.Error()), http.StatusInternalServerError, logger)
+		return
+	}
+
+	w.Header().Set(contentType, respContentType)
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(respBytes); err != nil {
+		level.Error(logger).Log("msg", "failed to write partial success response", "error", err)
+	}
+}
+
 func HTTPError(w http.ResponseWriter, errorStr string, code int, _ log.Logger) {
 	http.Error(w, errorStr, code)
 }
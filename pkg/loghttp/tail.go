@@ -16,7 +16,8 @@ import (
 )
 
 const (
-	maxDelayForInTailing = 5
+	// MaxDelayForInTailing is the maximum delay_for of a tail request, in seconds.
+	MaxDelayForInTailing = 5
 )
 
 // TailResponse represents the http json response to a tail query
@@ -99,8 +100,8 @@ func ParseTailQuery(r *http.Request) (*logproto.TailRequest, error) {
 	if err != nil {
 		return nil, err
 	}
-	if req.DelayFor > maxDelayForInTailing {
-		return nil, fmt.Errorf("delay_for can't be greater than %d", maxDelayForInTailing)
+	if req.DelayFor > MaxDelayForInTailing {
+		return nil, fmt.Errorf("delay_for can't be greater than %d", MaxDelayForInTailing)
 	}
 	return &req, nil
 }
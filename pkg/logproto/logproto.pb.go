@@ -1102,6 +1102,104 @@ func (m *TailResponse) GetDroppedStreams() []*DroppedStream {
 	return nil
 }
 
+// LiveTailResponse is a batch of tailed entries, equivalent to a message of the
+// /loki/api/v1/tail endpoint.
+type LiveTailResponse struct {
+	Streams        []github_com_grafana_loki_pkg_push.Stream `protobuf:"bytes,1,rep,name=streams,proto3,customtype=github.com/grafana/loki/pkg/push.Stream" json:"streams"`
+	DroppedEntries []DroppedEntry                            `protobuf:"bytes,2,rep,name=droppedEntries,proto3" json:"droppedEntries"`
+}
+
+func (m *LiveTailResponse) Reset()      { *m = LiveTailResponse{} }
+func (*LiveTailResponse) ProtoMessage() {}
+func (*LiveTailResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c28a5f14f1f4c79a, []int{18}
+}
+func (m *LiveTailResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *LiveTailResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_LiveTailResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *LiveTailResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LiveTailResponse.Merge(m, src)
+}
+func (m *LiveTailResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *LiveTailResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_LiveTailResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LiveTailResponse proto.InternalMessageInfo
+
+func (m *LiveTailResponse) GetDroppedEntries() []DroppedEntry {
+	if m != nil {
+		return m.DroppedEntries
+	}
+	return nil
+}
+
+// DroppedEntry is an entry that was not sent to a slow tail client.
+type DroppedEntry struct {
+	Timestamp time.Time `protobuf:"bytes,1,opt,name=timestamp,proto3,stdtime" json:"timestamp"`
+	Labels    string    `protobuf:"bytes,2,opt,name=labels,proto3" json:"labels,omitempty"`
+}
+
+func (m *DroppedEntry) Reset()      { *m = DroppedEntry{} }
+func (*DroppedEntry) ProtoMessage() {}
+func (*DroppedEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c28a5f14f1f4c79a, []int{19}
+}
+func (m *DroppedEntry) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DroppedEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DroppedEntry.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *DroppedEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DroppedEntry.Merge(m, src)
+}
+func (m *DroppedEntry) XXX_Size() int {
+	return m.Size()
+}
+func (m *DroppedEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_DroppedEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DroppedEntry proto.InternalMessageInfo
+
+func (m *DroppedEntry) GetTimestamp() time.Time {
+	if m != nil {
+		return m.Timestamp
+	}
+	return time.Time{}
+}
+
+func (m *DroppedEntry) GetLabels() string {
+	if m != nil {
+		return m.Labels
+	}
+	return ""
+}
+
 type SeriesRequest struct {
 	Start  time.Time `protobuf:"bytes,1,opt,name=start,proto3,stdtime" json:"start"`
 	End    time.Time `protobuf:"bytes,2,opt,name=end,proto3,stdtime" json:"end"`
@@ -1112,7 +1210,7 @@ type SeriesRequest struct {
 func (m *SeriesRequest) Reset()      { *m = SeriesRequest{} }
 func (*SeriesRequest) ProtoMessage() {}
 func (*SeriesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{18}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{20}
 }
 func (m *SeriesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1176,7 +1274,7 @@ type SeriesResponse struct {
 func (m *SeriesResponse) Reset()      { *m = SeriesResponse{} }
 func (*SeriesResponse) ProtoMessage() {}
 func (*SeriesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{19}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{21}
 }
 func (m *SeriesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1219,7 +1317,7 @@ type SeriesIdentifier struct {
 func (m *SeriesIdentifier) Reset()      { *m = SeriesIdentifier{} }
 func (*SeriesIdentifier) ProtoMessage() {}
 func (*SeriesIdentifier) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{20}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{22}
 }
 func (m *SeriesIdentifier) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1263,7 +1361,7 @@ type SeriesIdentifier_LabelsEntry struct {
 func (m *SeriesIdentifier_LabelsEntry) Reset()      { *m = SeriesIdentifier_LabelsEntry{} }
 func (*SeriesIdentifier_LabelsEntry) ProtoMessage() {}
 func (*SeriesIdentifier_LabelsEntry) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{20, 0}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{22, 0}
 }
 func (m *SeriesIdentifier_LabelsEntry) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1315,7 +1413,7 @@ type DroppedStream struct {
 func (m *DroppedStream) Reset()      { *m = DroppedStream{} }
 func (*DroppedStream) ProtoMessage() {}
 func (*DroppedStream) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{21}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{23}
 }
 func (m *DroppedStream) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1373,7 +1471,7 @@ type LabelPair struct {
 func (m *LabelPair) Reset()      { *m = LabelPair{} }
 func (*LabelPair) ProtoMessage() {}
 func (*LabelPair) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{22}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{24}
 }
 func (m *LabelPair) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1426,7 +1524,7 @@ type LegacyLabelPair struct {
 func (m *LegacyLabelPair) Reset()      { *m = LegacyLabelPair{} }
 func (*LegacyLabelPair) ProtoMessage() {}
 func (*LegacyLabelPair) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{23}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{25}
 }
 func (m *LegacyLabelPair) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1476,7 +1574,7 @@ type Chunk struct {
 func (m *Chunk) Reset()      { *m = Chunk{} }
 func (*Chunk) ProtoMessage() {}
 func (*Chunk) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{24}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{26}
 }
 func (m *Chunk) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1518,7 +1616,7 @@ type TailersCountRequest struct {
 func (m *TailersCountRequest) Reset()      { *m = TailersCountRequest{} }
 func (*TailersCountRequest) ProtoMessage() {}
 func (*TailersCountRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{25}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{27}
 }
 func (m *TailersCountRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1554,7 +1652,7 @@ type TailersCountResponse struct {
 func (m *TailersCountResponse) Reset()      { *m = TailersCountResponse{} }
 func (*TailersCountResponse) ProtoMessage() {}
 func (*TailersCountResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{26}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{28}
 }
 func (m *TailersCountResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1599,7 +1697,7 @@ type GetChunkIDsRequest struct {
 func (m *GetChunkIDsRequest) Reset()      { *m = GetChunkIDsRequest{} }
 func (*GetChunkIDsRequest) ProtoMessage() {}
 func (*GetChunkIDsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{27}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{29}
 }
 func (m *GetChunkIDsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1656,7 +1754,7 @@ type GetChunkIDsResponse struct {
 func (m *GetChunkIDsResponse) Reset()      { *m = GetChunkIDsResponse{} }
 func (*GetChunkIDsResponse) ProtoMessage() {}
 func (*GetChunkIDsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{28}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{30}
 }
 func (m *GetChunkIDsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1709,7 +1807,7 @@ type ChunkRef struct {
 func (m *ChunkRef) Reset()      { *m = ChunkRef{} }
 func (*ChunkRef) ProtoMessage() {}
 func (*ChunkRef) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{29}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{31}
 }
 func (m *ChunkRef) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1766,7 +1864,7 @@ type ChunkRefGroup struct {
 func (m *ChunkRefGroup) Reset()      { *m = ChunkRefGroup{} }
 func (*ChunkRefGroup) ProtoMessage() {}
 func (*ChunkRefGroup) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{30}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{32}
 }
 func (m *ChunkRefGroup) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1813,7 +1911,7 @@ type LabelValuesForMetricNameRequest struct {
 func (m *LabelValuesForMetricNameRequest) Reset()      { *m = LabelValuesForMetricNameRequest{} }
 func (*LabelValuesForMetricNameRequest) ProtoMessage() {}
 func (*LabelValuesForMetricNameRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{31}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{33}
 }
 func (m *LabelValuesForMetricNameRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1873,7 +1971,7 @@ type LabelNamesForMetricNameRequest struct {
 func (m *LabelNamesForMetricNameRequest) Reset()      { *m = LabelNamesForMetricNameRequest{} }
 func (*LabelNamesForMetricNameRequest) ProtoMessage() {}
 func (*LabelNamesForMetricNameRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{32}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{34}
 }
 func (m *LabelNamesForMetricNameRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1924,7 +2022,7 @@ type LineFilter struct {
 func (m *LineFilter) Reset()      { *m = LineFilter{} }
 func (*LineFilter) ProtoMessage() {}
 func (*LineFilter) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{33}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{35}
 }
 func (m *LineFilter) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1972,7 +2070,7 @@ type GetChunkRefRequest struct {
 func (m *GetChunkRefRequest) Reset()      { *m = GetChunkRefRequest{} }
 func (*GetChunkRefRequest) ProtoMessage() {}
 func (*GetChunkRefRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{34}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{36}
 }
 func (m *GetChunkRefRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2016,7 +2114,7 @@ type GetChunkRefResponse struct {
 func (m *GetChunkRefResponse) Reset()      { *m = GetChunkRefResponse{} }
 func (*GetChunkRefResponse) ProtoMessage() {}
 func (*GetChunkRefResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{35}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{37}
 }
 func (m *GetChunkRefResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2068,7 +2166,7 @@ type GetSeriesRequest struct {
 func (m *GetSeriesRequest) Reset()      { *m = GetSeriesRequest{} }
 func (*GetSeriesRequest) ProtoMessage() {}
 func (*GetSeriesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{36}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{38}
 }
 func (m *GetSeriesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2111,7 +2209,7 @@ type GetSeriesResponse struct {
 func (m *GetSeriesResponse) Reset()      { *m = GetSeriesResponse{} }
 func (*GetSeriesResponse) ProtoMessage() {}
 func (*GetSeriesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{37}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{39}
 }
 func (m *GetSeriesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2155,7 +2253,7 @@ type IndexSeries struct {
 func (m *IndexSeries) Reset()      { *m = IndexSeries{} }
 func (*IndexSeries) ProtoMessage() {}
 func (*IndexSeries) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{38}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{40}
 }
 func (m *IndexSeries) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2192,7 +2290,7 @@ type QueryIndexResponse struct {
 func (m *QueryIndexResponse) Reset()      { *m = QueryIndexResponse{} }
 func (*QueryIndexResponse) ProtoMessage() {}
 func (*QueryIndexResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{39}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{41}
 }
 func (m *QueryIndexResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2243,7 +2341,7 @@ type Row struct {
 func (m *Row) Reset()      { *m = Row{} }
 func (*Row) ProtoMessage() {}
 func (*Row) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{40}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{42}
 }
 func (m *Row) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2293,7 +2391,7 @@ type QueryIndexRequest struct {
 func (m *QueryIndexRequest) Reset()      { *m = QueryIndexRequest{} }
 func (*QueryIndexRequest) ProtoMessage() {}
 func (*QueryIndexRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{41}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{43}
 }
 func (m *QueryIndexRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2340,7 +2438,7 @@ type IndexQuery struct {
 func (m *IndexQuery) Reset()      { *m = IndexQuery{} }
 func (*IndexQuery) ProtoMessage() {}
 func (*IndexQuery) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{42}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{44}
 }
 func (m *IndexQuery) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2413,7 +2511,7 @@ type IndexStatsRequest struct {
 func (m *IndexStatsRequest) Reset()      { *m = IndexStatsRequest{} }
 func (*IndexStatsRequest) ProtoMessage() {}
 func (*IndexStatsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{43}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{45}
 }
 func (m *IndexStatsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2459,7 +2557,7 @@ type IndexStatsResponse struct {
 func (m *IndexStatsResponse) Reset()      { *m = IndexStatsResponse{} }
 func (*IndexStatsResponse) ProtoMessage() {}
 func (*IndexStatsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{44}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{46}
 }
 func (m *IndexStatsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2530,7 +2628,7 @@ type VolumeRequest struct {
 func (m *VolumeRequest) Reset()      { *m = VolumeRequest{} }
 func (*VolumeRequest) ProtoMessage() {}
 func (*VolumeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{45}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{47}
 }
 func (m *VolumeRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2609,7 +2707,7 @@ type VolumeResponse struct {
 func (m *VolumeResponse) Reset()      { *m = VolumeResponse{} }
 func (*VolumeResponse) ProtoMessage() {}
 func (*VolumeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{46}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{48}
 }
 func (m *VolumeResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2660,7 +2758,7 @@ type Volume struct {
 func (m *Volume) Reset()      { *m = Volume{} }
 func (*Volume) ProtoMessage() {}
 func (*Volume) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{47}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{49}
 }
 func (m *Volume) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2717,7 +2815,7 @@ type DetectedFieldsRequest struct {
 func (m *DetectedFieldsRequest) Reset()      { *m = DetectedFieldsRequest{} }
 func (*DetectedFieldsRequest) ProtoMessage() {}
 func (*DetectedFieldsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{48}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{50}
 }
 func (m *DetectedFieldsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2811,7 +2909,7 @@ type DetectedFieldsResponse struct {
 func (m *DetectedFieldsResponse) Reset()      { *m = DetectedFieldsResponse{} }
 func (*DetectedFieldsResponse) ProtoMessage() {}
 func (*DetectedFieldsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{49}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{51}
 }
 func (m *DetectedFieldsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2875,7 +2973,7 @@ type DetectedField struct {
 func (m *DetectedField) Reset()      { *m = DetectedField{} }
 func (*DetectedField) ProtoMessage() {}
 func (*DetectedField) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{50}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{52}
 }
 func (m *DetectedField) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2955,7 +3053,7 @@ type DetectedLabelsRequest struct {
 func (m *DetectedLabelsRequest) Reset()      { *m = DetectedLabelsRequest{} }
 func (*DetectedLabelsRequest) ProtoMessage() {}
 func (*DetectedLabelsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{51}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{53}
 }
 func (m *DetectedLabelsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3012,7 +3110,7 @@ type DetectedLabelsResponse struct {
 func (m *DetectedLabelsResponse) Reset()      { *m = DetectedLabelsResponse{} }
 func (*DetectedLabelsResponse) ProtoMessage() {}
 func (*DetectedLabelsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{52}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{54}
 }
 func (m *DetectedLabelsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3057,7 +3155,7 @@ type DetectedLabel struct {
 func (m *DetectedLabel) Reset()      { *m = DetectedLabel{} }
 func (*DetectedLabel) ProtoMessage() {}
 func (*DetectedLabel) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{53}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{55}
 }
 func (m *DetectedLabel) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3128,6 +3226,8 @@ func init() {
 	proto.RegisterType((*Series)(nil), "logproto.Series")
 	proto.RegisterType((*TailRequest)(nil), "logproto.TailRequest")
 	proto.RegisterType((*TailResponse)(nil), "logproto.TailResponse")
+	proto.RegisterType((*LiveTailResponse)(nil), "logproto.LiveTailResponse")
+	proto.RegisterType((*DroppedEntry)(nil), "logproto.DroppedEntry")
 	proto.RegisterType((*SeriesRequest)(nil), "logproto.SeriesRequest")
 	proto.RegisterType((*SeriesResponse)(nil), "logproto.SeriesResponse")
 	proto.RegisterType((*SeriesIdentifier)(nil), "logproto.SeriesIdentifier")
@@ -3170,182 +3270,186 @@ func init() {
 func init() { proto.RegisterFile("pkg/logproto/logproto.proto", fileDescriptor_c28a5f14f1f4c79a) }
 
 var fileDescriptor_c28a5f14f1f4c79a = []byte{
-	// 2796 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x1a, 0xcf, 0x8f, 0x5b, 0x47,
-	0xd9, 0xcf, 0xbf, 0xd6, 0xfe, 0xec, 0xdd, 0x6c, 0x66, 0x9d, 0x8d, 0xb5, 0x49, 0xfd, 0xb6, 0x23,
-	0x68, 0xb7, 0x4d, 0xba, 0x4e, 0xb6, 0xb4, 0xb4, 0x29, 0xa5, 0xc4, 0xbb, 0x4d, 0x9a, 0x74, 0x9b,
-	0xa4, 0xb3, 0x69, 0x5a, 0x10, 0x55, 0xf5, 0x62, 0xcf, 0xda, 0x8f, 0xd8, 0xef, 0x39, 0xef, 0x8d,
-	0x9b, 0xfa, 0xc6, 0x3f, 0x80, 0xa8, 0x84, 0x10, 0x70, 0x41, 0x42, 0x42, 0x02, 0x21, 0xf5, 0x82,
-	0x38, 0x70, 0x40, 0x70, 0xe1, 0x50, 0x6e, 0x3d, 0x56, 0x3d, 0x18, 0xba, 0xbd, 0xa0, 0x95, 0x90,
-	0x7a, 0x02, 0xa9, 0x5c, 0xd0, 0xfc, 0x7a, 0x6f, 0xde, 0x5b, 0x2f, 0xa9, 0x43, 0x50, 0x9b, 0x8b,
-	0x3d, 0xf3, 0xcd, 0x37, 0xdf, 0xcc, 0xf7, 0x63, 0xbe, 0x5f, 0x36, 0x9c, 0x18, 0xde, 0xea, 0x36,
-	0xfb, 0x7e, 0x77, 0x18, 0xf8, 0xcc, 0x8f, 0x06, 0xeb, 0xe2, 0x13, 0x95, 0xf4, 0x7c, 0xa5, 0xd6,
-	0xf5, 0xbb, 0xbe, 0xc4, 0xe1, 0x23, 0xb9, 0xbe, 0x62, 0x77, 0x7d, 0xbf, 0xdb, 0xa7, 0x4d, 0x31,
-	0xbb, 0x39, 0xda, 0x6d, 0x32, 0x77, 0x40, 0x43, 0xe6, 0x0c, 0x86, 0x0a, 0x61, 0x55, 0x51, 0xbf,
-	0xdd, 0x1f, 0xf8, 0x1d, 0xda, 0x6f, 0x86, 0xcc, 0x61, 0xa1, 0xfc, 0x54, 0x18, 0x4b, 0x1c, 0x63,
-	0x38, 0x0a, 0x7b, 0xe2, 0x43, 0x01, 0xcf, 0x70, 0x60, 0xc8, 0xfc, 0xc0, 0xe9, 0xd2, 0x66, 0xbb,
-	0x37, 0xf2, 0x6e, 0x35, 0xdb, 0x4e, 0xbb, 0x47, 0x9b, 0x01, 0x0d, 0x47, 0x7d, 0x16, 0xca, 0x09,
-	0x1b, 0x0f, 0xa9, 0x22, 0x83, 0x7f, 0x67, 0xc1, 0xb1, 0x6d, 0xe7, 0x26, 0xed, 0x5f, 0xf7, 0x6f,
-	0x38, 0xfd, 0x11, 0x0d, 0x09, 0x0d, 0x87, 0xbe, 0x17, 0x52, 0xb4, 0x09, 0xc5, 0x3e, 0x5f, 0x08,
-	0xeb, 0xd6, 0x6a, 0x6e, 0xad, 0xb2, 0x71, 0x6a, 0x3d, 0x62, 0x72, 0xea, 0x06, 0x09, 0x0d, 0x5f,
-	0xf4, 0x58, 0x30, 0x26, 0x6a, 0xeb, 0xca, 0x0d, 0xa8, 0x18, 0x60, 0xb4, 0x08, 0xb9, 0x5b, 0x74,
-	0x5c, 0xb7, 0x56, 0xad, 0xb5, 0x32, 0xe1, 0x43, 0x74, 0x16, 0x0a, 0x6f, 0x73, 0x32, 0xf5, 0xec,
-	0xaa, 0xb5, 0x56, 0xd9, 0x38, 0x11, 0x1f, 0xf2, 0x9a, 0xe7, 0xde, 0x1e, 0x51, 0xb1, 0x5b, 0x1d,
-	0x24, 0x31, 0xcf, 0x65, 0x9f, 0xb1, 0xf0, 0x29, 0x38, 0x7a, 0x60, 0x1d, 0x2d, 0x43, 0x51, 0x60,
-	0xc8, 0x1b, 0x97, 0x89, 0x9a, 0xe1, 0x1a, 0xa0, 0x1d, 0x16, 0x50, 0x67, 0x40, 0x1c, 0xc6, 0xef,
-	0x7b, 0x7b, 0x44, 0x43, 0x86, 0x5f, 0x81, 0xa5, 0x04, 0x54, 0xb1, 0xfd, 0x34, 0x54, 0xc2, 0x18,
-	0xac, 0x78, 0xaf, 0xc5, 0xd7, 0x8a, 0xf7, 0x10, 0x13, 0x11, 0xff, 0xdc, 0x02, 0x88, 0xd7, 0x50,
-	0x03, 0x40, 0xae, 0xbe, 0xe4, 0x84, 0x3d, 0xc1, 0x70, 0x9e, 0x18, 0x10, 0x74, 0x1a, 0x8e, 0xc6,
-	0xb3, 0x2b, 0xfe, 0x4e, 0xcf, 0x09, 0x3a, 0x42, 0x06, 0x79, 0x72, 0x70, 0x01, 0x21, 0xc8, 0x07,
-	0x0e, 0xa3, 0xf5, 0xdc, 0xaa, 0xb5, 0x96, 0x23, 0x62, 0xcc, 0xb9, 0x65, 0xd4, 0x73, 0x3c, 0x56,
-	0xcf, 0x0b, 0x71, 0xaa, 0x19, 0x87, 0x73, 0x8b, 0xa0, 0x61, 0xbd, 0xb0, 0x6a, 0xad, 0xcd, 0x13,
-	0x35, 0xc3, 0xff, 0xcc, 0x41, 0xf5, 0xd5, 0x11, 0x0d, 0xc6, 0x4a, 0x00, 0xa8, 0x01, 0xa5, 0x90,
-	0xf6, 0x69, 0x9b, 0xf9, 0x81, 0xd4, 0x48, 0x2b, 0x5b, 0xb7, 0x48, 0x04, 0x43, 0x35, 0x28, 0xf4,
-	0xdd, 0x81, 0xcb, 0xc4, 0xb5, 0xe6, 0x89, 0x9c, 0xa0, 0x73, 0x50, 0x08, 0x99, 0x13, 0x30, 0x71,
-	0x97, 0xca, 0xc6, 0xca, 0xba, 0x34, 0xe5, 0x75, 0x6d, 0xca, 0xeb, 0xd7, 0xb5, 0x29, 0xb7, 0x4a,
-	0xef, 0x4f, 0xec, 0xcc, 0xbb, 0x7f, 0xb5, 0x2d, 0x22, 0xb7, 0xa0, 0xa7, 0x21, 0x47, 0xbd, 0x8e,
-	0xb8, 0xef, 0xe7, 0xdd, 0xc9, 0x37, 0xa0, 0xb3, 0x50, 0xee, 0xb8, 0x01, 0x6d, 0x33, 0xd7, 0xf7,
-	0x04, 0x57, 0x0b, 0x1b, 0x4b, 0xb1, 0x46, 0xb6, 0xf4, 0x12, 0x89, 0xb1, 0xd0, 0x69, 0x28, 0x86,
-	0x5c, 0x74, 0x61, 0x7d, 0x8e, 0xdb, 0x42, 0xab, 0xb6, 0x3f, 0xb1, 0x17, 0x25, 0xe4, 0xb4, 0x3f,
-	0x70, 0x19, 0x1d, 0x0c, 0xd9, 0x98, 0x28, 0x1c, 0xf4, 0x38, 0xcc, 0x75, 0x68, 0x9f, 0x72, 0x85,
-	0x97, 0x84, 0xc2, 0x17, 0x0d, 0xf2, 0x62, 0x81, 0x68, 0x04, 0xf4, 0x26, 0xe4, 0x87, 0x7d, 0xc7,
-	0xab, 0x97, 0x05, 0x17, 0x0b, 0x31, 0xe2, 0xb5, 0xbe, 0xe3, 0xb5, 0x9e, 0xfd, 0x68, 0x62, 0x3f,
-	0xd5, 0x75, 0x59, 0x6f, 0x74, 0x73, 0xbd, 0xed, 0x0f, 0x9a, 0xdd, 0xc0, 0xd9, 0x75, 0x3c, 0xa7,
-	0xd9, 0xf7, 0x6f, 0xb9, 0xcd, 0xb7, 0x9f, 0x6c, 0xf2, 0x07, 0x7a, 0x7b, 0x44, 0x03, 0x97, 0x06,
-	0x4d, 0x4e, 0x66, 0x5d, 0xa8, 0x84, 0x6f, 0x25, 0x82, 0x2c, 0xba, 0xcc, 0xed, 0xcf, 0x0f, 0xe8,
-	0x26, 0x7f, 0xbd, 0x61, 0x1d, 0xc4, 0x29, 0xc7, 0xe3, 0x53, 0x04, 0x9c, 0xd0, 0xdd, 0x8b, 0x81,
-	0x3f, 0x1a, 0xb6, 0x8e, 0xec, 0x4f, 0x6c, 0x13, 0x9f, 0x98, 0x93, 0xcb, 0xf9, 0x52, 0x71, 0x71,
-	0x0e, 0xbf, 0x97, 0x03, 0xb4, 0xe3, 0x0c, 0x86, 0x7d, 0x3a, 0x93, 0xfa, 0x23, 0x45, 0x67, 0xef,
-	0x59, 0xd1, 0xb9, 0x59, 0x15, 0x1d, 0x6b, 0x2d, 0x3f, 0x9b, 0xd6, 0x0a, 0x9f, 0x57, 0x6b, 0xc5,
-	0x2f, 0xbd, 0xd6, 0x70, 0x1d, 0xf2, 0x9c, 0x32, 0x77, 0x96, 0x81, 0x73, 0x47, 0xe8, 0xa6, 0x4a,
-	0xf8, 0x10, 0x6f, 0x43, 0x51, 0xf2, 0x85, 0x56, 0xd2, 0xca, 0x4b, 0xbe, 0xdb, 0x58, 0x71, 0x39,
-	0xad, 0x92, 0xc5, 0x58, 0x25, 0x39, 0x21, 0x6c, 0xfc, 0x07, 0x0b, 0xe6, 0x95, 0x45, 0x28, 0xdf,
-	0x77, 0x13, 0xe6, 0xa4, 0xef, 0xd1, 0x7e, 0xef, 0x78, 0xda, 0xef, 0x9d, 0xef, 0x38, 0x43, 0x46,
-	0x83, 0x56, 0xf3, 0xfd, 0x89, 0x6d, 0x7d, 0x34, 0xb1, 0x1f, 0x3d, 0x4c, 0x68, 0x3a, 0x3a, 0x69,
-	0x7f, 0xa9, 0x09, 0xa3, 0x53, 0xe2, 0x76, 0x2c, 0x54, 0x66, 0x75, 0x64, 0x5d, 0x06, 0xb5, 0x4b,
-	0x5e, 0x97, 0x86, 0x9c, 0x72, 0x9e, 0x5b, 0x04, 0x91, 0x38, 0x9c, 0xcd, 0x3b, 0x4e, 0xe0, 0xb9,
-	0x5e, 0x37, 0xac, 0xe7, 0x84, 0x4f, 0x8f, 0xe6, 0xf8, 0xa7, 0x16, 0x2c, 0x25, 0xcc, 0x5a, 0x31,
-	0xf1, 0x0c, 0x14, 0x43, 0xae, 0x29, 0xcd, 0x83, 0x61, 0x14, 0x3b, 0x02, 0xde, 0x5a, 0x50, 0x97,
-	0x2f, 0xca, 0x39, 0x51, 0xf8, 0xf7, 0xef, 0x6a, 0x7f, 0xb6, 0xa0, 0x2a, 0x02, 0x93, 0x7e, 0x6b,
-	0x08, 0xf2, 0x9e, 0x33, 0xa0, 0x4a, 0x55, 0x62, 0x6c, 0x44, 0x2b, 0x7e, 0x5c, 0x49, 0x47, 0xab,
-	0x59, 0x1d, 0xac, 0x75, 0xcf, 0x0e, 0xd6, 0x8a, 0xdf, 0x5d, 0x0d, 0x0a, 0xdc, 0xbc, 0xc7, 0xc2,
-	0xb9, 0x96, 0x89, 0x9c, 0xe0, 0x47, 0x61, 0x5e, 0x71, 0xa1, 0x44, 0x7b, 0x58, 0x80, 0x1d, 0x40,
-	0x51, 0x6a, 0x02, 0x7d, 0x05, 0xca, 0x51, 0x2a, 0x23, 0xb8, 0xcd, 0xb5, 0x8a, 0xfb, 0x13, 0x3b,
-	0xcb, 0x42, 0x12, 0x2f, 0x20, 0xdb, 0x0c, 0xfa, 0x56, 0xab, 0xbc, 0x3f, 0xb1, 0x25, 0x40, 0x85,
-	0x78, 0x74, 0x12, 0xf2, 0x3d, 0x1e, 0x37, 0xb9, 0x08, 0xf2, 0xad, 0xd2, 0xfe, 0xc4, 0x16, 0x73,
-	0x22, 0x3e, 0xf1, 0x45, 0xa8, 0x6e, 0xd3, 0xae, 0xd3, 0x1e, 0xab, 0x43, 0x6b, 0x9a, 0x1c, 0x3f,
-	0xd0, 0xd2, 0x34, 0x1e, 0x86, 0x6a, 0x74, 0xe2, 0x5b, 0x83, 0x50, 0xbd, 0x86, 0x4a, 0x04, 0x7b,
-	0x25, 0xc4, 0x3f, 0xb3, 0x40, 0xd9, 0x00, 0xc2, 0x46, 0xb6, 0xc3, 0x7d, 0x21, 0xec, 0x4f, 0x6c,
-	0x05, 0xd1, 0xc9, 0x0c, 0x7a, 0x0e, 0xe6, 0x42, 0x71, 0x22, 0x27, 0x96, 0x36, 0x2d, 0xb1, 0xd0,
-	0x3a, 0xc2, 0x4d, 0x64, 0x7f, 0x62, 0x6b, 0x44, 0xa2, 0x07, 0x68, 0x3d, 0x91, 0x10, 0x48, 0xc6,
-	0x16, 0xf6, 0x27, 0xb6, 0x01, 0x35, 0x13, 0x04, 0xfc, 0x99, 0x05, 0x95, 0xeb, 0x8e, 0x1b, 0x99,
-	0x50, 0x5d, 0xab, 0x28, 0xf6, 0xd5, 0x12, 0xc0, 0x2d, 0xb1, 0x43, 0xfb, 0xce, 0xf8, 0x82, 0x1f,
-	0x08, 0xba, 0xf3, 0x24, 0x9a, 0xc7, 0x31, 0x3c, 0x3f, 0x35, 0x86, 0x17, 0x66, 0x77, 0xed, 0xff,
-	0x5f, 0x47, 0x7a, 0x39, 0x5f, 0xca, 0x2e, 0xe6, 0xf0, 0x7b, 0x16, 0x54, 0x25, 0xf3, 0xca, 0xf2,
-	0xbe, 0x0b, 0x45, 0x29, 0x1b, 0xc1, 0xfe, 0x7f, 0x71, 0x4c, 0xa7, 0x66, 0x71, 0x4a, 0x8a, 0x26,
-	0x7a, 0x01, 0x16, 0x3a, 0x81, 0x3f, 0x1c, 0xd2, 0xce, 0x8e, 0x72, 0x7f, 0xd9, 0xb4, 0xfb, 0xdb,
-	0x32, 0xd7, 0x49, 0x0a, 0x1d, 0xff, 0xc5, 0x82, 0x79, 0xe5, 0x4c, 0x94, 0xba, 0x22, 0x11, 0x5b,
-	0xf7, 0x1c, 0x3d, 0xb3, 0xb3, 0x46, 0xcf, 0x65, 0x28, 0x76, 0x79, 0x7c, 0xd1, 0x0e, 0x49, 0xcd,
-	0x66, 0x8b, 0xaa, 0xf8, 0x32, 0x2c, 0x68, 0x56, 0x0e, 0xf1, 0xa8, 0x2b, 0x69, 0x8f, 0x7a, 0xa9,
-	0x43, 0x3d, 0xe6, 0xee, 0xba, 0x91, 0x8f, 0x54, 0xf8, 0xf8, 0x87, 0x16, 0x2c, 0xa6, 0x51, 0xd0,
-	0x56, 0xaa, 0xb0, 0x78, 0xe4, 0x70, 0x72, 0x66, 0x4d, 0xa1, 0x49, 0xab, 0xca, 0xe2, 0xa9, 0xbb,
-	0x55, 0x16, 0x35, 0xd3, 0xc9, 0x94, 0x95, 0x57, 0xc0, 0x3f, 0xb1, 0x60, 0x3e, 0xa1, 0x4b, 0xf4,
-	0x0c, 0xe4, 0x77, 0x03, 0x7f, 0x30, 0x93, 0xa2, 0xc4, 0x0e, 0xf4, 0x35, 0xc8, 0x32, 0x7f, 0x26,
-	0x35, 0x65, 0x99, 0xcf, 0xb5, 0xa4, 0xd8, 0xcf, 0xc9, 0xbc, 0x5d, 0xce, 0xf0, 0x53, 0x50, 0x16,
-	0x0c, 0x5d, 0x73, 0xdc, 0x60, 0x6a, 0xc0, 0x98, 0xce, 0xd0, 0x73, 0x70, 0x44, 0x3a, 0xc3, 0xe9,
-	0x9b, 0xab, 0xd3, 0x36, 0x57, 0xf5, 0xe6, 0x13, 0x50, 0x10, 0x49, 0x07, 0xdf, 0xd2, 0x71, 0x98,
-	0xa3, 0xb7, 0xf0, 0x31, 0x3e, 0x06, 0x4b, 0xfc, 0x0d, 0xd2, 0x20, 0xdc, 0xf4, 0x47, 0x1e, 0xd3,
-	0x75, 0xd3, 0x69, 0xa8, 0x25, 0xc1, 0xca, 0x4a, 0x6a, 0x50, 0x68, 0x73, 0x80, 0xa0, 0x31, 0x4f,
-	0xe4, 0x04, 0xff, 0xd2, 0x02, 0x74, 0x91, 0x32, 0x71, 0xca, 0xa5, 0xad, 0xe8, 0x79, 0xac, 0x40,
-	0x69, 0xe0, 0xb0, 0x76, 0x8f, 0x06, 0xa1, 0xce, 0x5f, 0xf4, 0xfc, 0x8b, 0x48, 0x3c, 0xf1, 0x59,
-	0x58, 0x4a, 0xdc, 0x52, 0xf1, 0xb4, 0x02, 0xa5, 0xb6, 0x82, 0xa9, 0x90, 0x17, 0xcd, 0xf1, 0x6f,
-	0xb3, 0x50, 0xd2, 0x69, 0x1d, 0x3a, 0x0b, 0x95, 0x5d, 0xd7, 0xeb, 0xd2, 0x60, 0x18, 0xb8, 0x4a,
-	0x04, 0x79, 0x99, 0xe6, 0x19, 0x60, 0x62, 0x4e, 0xd0, 0x13, 0x30, 0x37, 0x0a, 0x69, 0xf0, 0x96,
-	0x2b, 0x5f, 0x7a, 0xb9, 0x55, 0xdb, 0x9b, 0xd8, 0xc5, 0xd7, 0x42, 0x1a, 0x5c, 0xda, 0xe2, 0xc1,
-	0x67, 0x24, 0x46, 0x44, 0x7e, 0x77, 0xd0, 0xcb, 0xca, 0x4c, 0x45, 0x02, 0xd7, 0xfa, 0x3a, 0xbf,
-	0x7e, 0xca, 0xd5, 0x0d, 0x03, 0x7f, 0x40, 0x59, 0x8f, 0x8e, 0xc2, 0x66, 0xdb, 0x1f, 0x0c, 0x7c,
-	0xaf, 0x29, 0x7a, 0x07, 0x82, 0x69, 0x1e, 0x41, 0xf9, 0x76, 0x65, 0xb9, 0xd7, 0x61, 0x8e, 0xf5,
-	0x02, 0x7f, 0xd4, 0xed, 0x89, 0xc0, 0x90, 0x6b, 0x9d, 0x9b, 0x9d, 0x9e, 0xa6, 0x40, 0xf4, 0x00,
-	0x3d, 0xcc, 0xa5, 0x45, 0xdb, 0xb7, 0xc2, 0xd1, 0x40, 0xd6, 0x9e, 0xad, 0xc2, 0xfe, 0xc4, 0xb6,
-	0x9e, 0x20, 0x11, 0x18, 0x9f, 0x87, 0xf9, 0x44, 0x2a, 0x8c, 0xce, 0x40, 0x3e, 0xa0, 0xbb, 0xda,
-	0x15, 0xa0, 0x83, 0x19, 0xb3, 0x8c, 0xfe, 0x1c, 0x87, 0x88, 0x4f, 0xfc, 0x83, 0x2c, 0xd8, 0x46,
-	0xd5, 0x7f, 0xc1, 0x0f, 0x5e, 0xa1, 0x2c, 0x70, 0xdb, 0x57, 0x9c, 0x01, 0xd5, 0xe6, 0x65, 0x43,
-	0x65, 0x20, 0x80, 0x6f, 0x19, 0xaf, 0x08, 0x06, 0x11, 0x1e, 0x7a, 0x08, 0x40, 0x3c, 0x3b, 0xb9,
-	0x2e, 0x1f, 0x54, 0x59, 0x40, 0xc4, 0xf2, 0x66, 0x42, 0xd8, 0xcd, 0x19, 0x85, 0xa3, 0x84, 0x7c,
-	0x29, 0x2d, 0xe4, 0x99, 0xe9, 0x44, 0x92, 0x35, 0x9f, 0x4b, 0x21, 0xf9, 0x5c, 0xf0, 0x3f, 0x2c,
-	0x68, 0x6c, 0xeb, 0x9b, 0xdf, 0xa3, 0x38, 0x34, 0xbf, 0xd9, 0xfb, 0xc4, 0x6f, 0xee, 0x3e, 0xf2,
-	0x9b, 0x4f, 0xf1, 0xdb, 0x00, 0xd8, 0x76, 0x3d, 0x7a, 0xc1, 0xed, 0x33, 0x1a, 0x4c, 0x29, 0x92,
-	0x7e, 0x94, 0x8b, 0x3d, 0x0e, 0xa1, 0xbb, 0x5a, 0x06, 0x9b, 0x86, 0x9b, 0xbf, 0x1f, 0x2c, 0x66,
-	0xef, 0x23, 0x8b, 0xb9, 0x94, 0x07, 0xf4, 0x60, 0x6e, 0x57, 0xb0, 0x27, 0x23, 0x76, 0xa2, 0xff,
-	0x14, 0xf3, 0xde, 0xfa, 0xa6, 0x3a, 0xfc, 0xe9, 0xbb, 0x24, 0x5c, 0xa2, 0x8f, 0xd8, 0x0c, 0xc7,
-	0x1e, 0x73, 0xde, 0x31, 0xf6, 0x13, 0x7d, 0x08, 0x72, 0x54, 0x4e, 0x57, 0x98, 0x9a, 0xd3, 0x3d,
-	0xaf, 0x8e, 0xf9, 0x5f, 0xf2, 0x3a, 0xdc, 0x8d, 0x1d, 0xac, 0x50, 0x8a, 0x72, 0xb0, 0x8f, 0xdc,
-	0xed, 0xf9, 0xcb, 0x47, 0x8f, 0xd6, 0x92, 0xa5, 0x59, 0x35, 0x2a, 0xcd, 0x3a, 0xf4, 0x9d, 0x44,
-	0x5d, 0x86, 0xff, 0x68, 0xc1, 0xe2, 0x45, 0xca, 0x92, 0xd9, 0xd8, 0x03, 0xa4, 0x7c, 0xfc, 0x12,
-	0x1c, 0x35, 0xee, 0xaf, 0xe4, 0xf4, 0x64, 0x2a, 0x05, 0x3b, 0x16, 0x4b, 0x4a, 0xc8, 0x40, 0x55,
-	0xb6, 0xc9, 0xec, 0xeb, 0x1a, 0x54, 0x8c, 0x45, 0x74, 0x3e, 0x95, 0x77, 0x2d, 0xa5, 0x1a, 0xba,
-	0x3c, 0x77, 0x68, 0xd5, 0x14, 0x4f, 0xb2, 0x7e, 0x55, 0x59, 0x75, 0x94, 0xa3, 0xec, 0x00, 0x12,
-	0x8a, 0x15, 0x64, 0xcd, 0x28, 0x29, 0xa0, 0x2f, 0x47, 0x09, 0x58, 0x34, 0x47, 0x0f, 0x43, 0x3e,
-	0xf0, 0xef, 0xe8, 0x84, 0x7a, 0x3e, 0x3e, 0x92, 0xf8, 0x77, 0x88, 0x58, 0xc2, 0xcf, 0x41, 0x8e,
-	0xf8, 0x77, 0x50, 0x03, 0x20, 0x70, 0xbc, 0x2e, 0xbd, 0x11, 0x95, 0x72, 0x55, 0x62, 0x40, 0x0e,
-	0xc9, 0x60, 0x36, 0xe1, 0xa8, 0x79, 0x23, 0xa9, 0xee, 0x75, 0x98, 0x7b, 0x75, 0x64, 0x8a, 0xab,
-	0x96, 0x12, 0x97, 0xec, 0x18, 0x68, 0x24, 0x6e, 0x33, 0x10, 0xc3, 0xd1, 0x49, 0x28, 0x33, 0xe7,
-	0x66, 0x9f, 0x5e, 0x89, 0x9d, 0x65, 0x0c, 0xe0, 0xab, 0xbc, 0x0a, 0xbd, 0x61, 0xa4, 0x62, 0x31,
-	0x00, 0x3d, 0x0e, 0x8b, 0xf1, 0x9d, 0xaf, 0x05, 0x74, 0xd7, 0x7d, 0x47, 0x68, 0xb8, 0x4a, 0x0e,
-	0xc0, 0xd1, 0x1a, 0x1c, 0x89, 0x61, 0x3b, 0x22, 0xe5, 0xc9, 0x0b, 0xd4, 0x34, 0x98, 0xcb, 0x46,
-	0xb0, 0xfb, 0xe2, 0xed, 0x91, 0xd3, 0x17, 0xcf, 0xb4, 0x4a, 0x0c, 0x08, 0xfe, 0x93, 0x05, 0x47,
-	0xa5, 0xaa, 0xf9, 0x1b, 0x78, 0x10, 0xad, 0xfe, 0x57, 0x16, 0x20, 0x93, 0x03, 0x65, 0x5a, 0x5f,
-	0x35, 0x3b, 0x52, 0x3c, 0xa7, 0xaa, 0x88, 0xe2, 0x5a, 0x82, 0xe2, 0xa6, 0x12, 0x86, 0x62, 0x5b,
-	0x76, 0xde, 0x44, 0x0b, 0x5d, 0x56, 0xef, 0x12, 0x42, 0xd4, 0x37, 0xb2, 0xa1, 0x70, 0x73, 0xcc,
-	0x68, 0xa8, 0x6a, 0x6f, 0xd1, 0x74, 0x10, 0x00, 0x22, 0xbf, 0xf8, 0x59, 0xd4, 0x63, 0xc2, 0x6a,
-	0xf2, 0xf1, 0x59, 0x0a, 0x44, 0xf4, 0x00, 0xff, 0x2b, 0x0b, 0xf3, 0x37, 0xfc, 0xfe, 0x28, 0x0e,
-	0xaf, 0x0f, 0x52, 0x68, 0x49, 0x34, 0x04, 0x0a, 0xba, 0x21, 0x80, 0x20, 0x1f, 0x32, 0x3a, 0x14,
-	0x96, 0x95, 0x23, 0x62, 0x8c, 0x30, 0x54, 0x99, 0x13, 0x74, 0x29, 0x93, 0x65, 0x56, 0xbd, 0x28,
-	0xf2, 0xdf, 0x04, 0x0c, 0xad, 0x42, 0xc5, 0xe9, 0x76, 0x03, 0xda, 0x75, 0x18, 0x6d, 0x8d, 0xeb,
-	0x73, 0xe2, 0x30, 0x13, 0x84, 0x2e, 0xc3, 0x42, 0xdb, 0x69, 0xf7, 0x5c, 0xaf, 0x7b, 0x75, 0xc8,
-	0x5c, 0xdf, 0x0b, 0xeb, 0x25, 0xe1, 0xc1, 0x4f, 0xae, 0x9b, 0x3f, 0x49, 0xad, 0x6f, 0x26, 0x70,
-	0x94, 0x1f, 0x4b, 0xed, 0xc4, 0x6f, 0xc0, 0x82, 0x16, 0xbc, 0x32, 0x8f, 0x33, 0x30, 0xf7, 0xb6,
-	0x80, 0x4c, 0x69, 0xf6, 0x49, 0x54, 0x45, 0x4a, 0xa3, 0x25, 0x7f, 0xd4, 0xd0, 0xfc, 0xe3, 0xcb,
-	0x50, 0x94, 0xe8, 0xe8, 0xa4, 0x59, 0x78, 0xc9, 0xdc, 0x93, 0xcf, 0x55, 0x15, 0x85, 0xa1, 0x28,
-	0x09, 0x29, 0x23, 0x12, 0x76, 0x26, 0x21, 0x44, 0x7d, 0xe3, 0x1f, 0x67, 0xe1, 0xd8, 0x16, 0x65,
-	0xb4, 0xcd, 0x68, 0xe7, 0x82, 0x4b, 0xfb, 0x9d, 0x2f, 0xb4, 0x27, 0x10, 0x75, 0xf6, 0x72, 0x46,
-	0x67, 0x8f, 0xfb, 0xb0, 0xbe, 0xeb, 0xd1, 0x6d, 0xa3, 0x35, 0x14, 0x03, 0x62, 0x19, 0x15, 0xcc,
-	0xa6, 0x91, 0xb6, 0x91, 0xa2, 0x61, 0x23, 0x71, 0x43, 0x70, 0x2e, 0xd1, 0xc3, 0xd4, 0x15, 0x68,
-	0x29, 0x2e, 0x5f, 0xf1, 0xef, 0x2d, 0x58, 0x4e, 0xcb, 0x45, 0xa9, 0xf1, 0x45, 0x28, 0xee, 0x0a,
-	0xc8, 0xc1, 0xb6, 0x73, 0x62, 0x87, 0xec, 0x5c, 0x48, 0x54, 0xb3, 0x73, 0x21, 0x21, 0xe8, 0xb1,
-	0xc4, 0x0f, 0x56, 0xad, 0xa5, 0xfd, 0x89, 0x7d, 0x44, 0x00, 0x0c, 0x5c, 0xc5, 0xcc, 0xe9, 0xe8,
-	0xe2, 0xb9, 0xb8, 0x25, 0x22, 0x21, 0x26, 0x61, 0xd5, 0xdf, 0xfc, 0xb7, 0x05, 0xf3, 0x89, 0x8b,
-	0x08, 0x11, 0xf1, 0x27, 0xa0, 0xc2, 0x83, 0x9c, 0xa0, 0xc7, 0x20, 0xcf, 0xc6, 0x43, 0x15, 0x15,
-	0x5a, 0xc7, 0x3e, 0x9b, 0xd8, 0x47, 0x13, 0xdb, 0xae, 0x8f, 0x87, 0x94, 0x08, 0x14, 0xfe, 0x72,
-	0xda, 0x4e, 0xd0, 0x71, 0x3d, 0xa7, 0xef, 0x32, 0xa9, 0x9d, 0x3c, 0x31, 0x41, 0xdc, 0x1d, 0x0d,
-	0x9d, 0x20, 0xd4, 0x49, 0x60, 0x59, 0xba, 0x23, 0x05, 0x22, 0x7a, 0x20, 0x9a, 0x3b, 0xb7, 0x28,
-	0x6b, 0xf7, 0x64, 0x58, 0x50, 0xcd, 0x1d, 0x01, 0x49, 0x34, 0x77, 0x04, 0x04, 0x6d, 0x40, 0xe9,
-	0x7b, 0xa1, 0xef, 0x5d, 0x73, 0x58, 0x4f, 0x3e, 0xe8, 0xd6, 0xf2, 0xfe, 0xc4, 0x46, 0x1a, 0x66,
-	0xec, 0x88, 0xf0, 0xf0, 0x2f, 0xac, 0xd8, 0xa0, 0xe5, 0xbb, 0xff, 0xd2, 0x19, 0x34, 0xfe, 0x76,
-	0x6c, 0x5b, 0xfa, 0x8a, 0xca, 0xb6, 0x5e, 0x80, 0x85, 0x4e, 0x62, 0xe5, 0x70, 0x1b, 0x93, 0xcd,
-	0xee, 0x14, 0x3a, 0x1e, 0xc5, 0xba, 0x17, 0x90, 0x43, 0x74, 0x9f, 0x52, 0x68, 0xf6, 0xa0, 0x42,
-	0x63, 0x4d, 0xe5, 0xee, 0xae, 0xa9, 0xc7, 0x1f, 0x81, 0x72, 0xf4, 0xc3, 0x26, 0xaa, 0xc0, 0xdc,
-	0x85, 0xab, 0xe4, 0xf5, 0xf3, 0x64, 0x6b, 0x31, 0x83, 0xaa, 0x50, 0x6a, 0x9d, 0xdf, 0x7c, 0x59,
-	0xcc, 0xac, 0x8d, 0xdf, 0x14, 0x75, 0xb2, 0x13, 0xa0, 0x6f, 0x40, 0x41, 0x66, 0x30, 0xcb, 0x31,
-	0x73, 0xe6, 0x6f, 0x7e, 0x2b, 0xc7, 0x0f, 0xc0, 0xa5, 0x94, 0x70, 0xe6, 0x8c, 0x85, 0xae, 0x40,
-	0x45, 0x00, 0x55, 0x57, 0xfd, 0x64, 0xba, 0xb9, 0x9d, 0xa0, 0xf4, 0xd0, 0x21, 0xab, 0x06, 0xbd,
-	0x73, 0x50, 0x90, 0x02, 0x5b, 0x4e, 0x25, 0x9a, 0x53, 0x6e, 0x93, 0xf8, 0x9d, 0x01, 0x67, 0xd0,
-	0xb3, 0x90, 0xbf, 0xee, 0xb8, 0x7d, 0x64, 0xe4, 0xb9, 0x46, 0x33, 0x7c, 0x65, 0x39, 0x0d, 0x36,
-	0x8e, 0x7d, 0x3e, 0xea, 0xe9, 0x1f, 0x4f, 0x37, 0x16, 0xf5, 0xf6, 0xfa, 0xc1, 0x85, 0xe8, 0xe4,
-	0xab, 0xb2, 0xf3, 0xac, 0xdb, 0x5b, 0xe8, 0xa1, 0xe4, 0x51, 0xa9, 0x6e, 0xd8, 0x4a, 0xe3, 0xb0,
-	0xe5, 0x88, 0xe0, 0x36, 0x54, 0x8c, 0xd6, 0x92, 0x29, 0xd6, 0x83, 0x7d, 0x31, 0x53, 0xac, 0x53,
-	0xfa, 0x51, 0x38, 0x83, 0x2e, 0x42, 0x89, 0x57, 0x07, 0xe2, 0x27, 0xa8, 0x13, 0xe9, 0x22, 0xc0,
-	0x48, 0xfe, 0x56, 0x4e, 0x4e, 0x5f, 0x8c, 0x08, 0x7d, 0x0b, 0xca, 0x17, 0x29, 0x53, 0x51, 0xef,
-	0x78, 0x3a, 0x6c, 0x4e, 0x91, 0x54, 0x32, 0xf4, 0xe2, 0x0c, 0x7a, 0x43, 0x14, 0x2a, 0x49, 0x97,
-	0x8e, 0xec, 0x43, 0x5c, 0x77, 0x74, 0xaf, 0xd5, 0xc3, 0x11, 0x22, 0xca, 0xaf, 0x27, 0x28, 0xab,
-	0x5c, 0xc3, 0x3e, 0xe4, 0xc1, 0x46, 0x94, 0xed, 0xbb, 0xfc, 0x41, 0x05, 0x67, 0x36, 0xde, 0xd4,
-	0xff, 0xd1, 0xd8, 0x72, 0x98, 0x83, 0xae, 0xc2, 0x82, 0x90, 0x65, 0xf4, 0x27, 0x8e, 0x84, 0xcd,
-	0x1f, 0xf8, 0xc7, 0x48, 0xc2, 0xe6, 0x0f, 0xfe, 0x73, 0x04, 0x67, 0x5a, 0x6f, 0x7e, 0xf0, 0x71,
-	0x23, 0xf3, 0xe1, 0xc7, 0x8d, 0xcc, 0xa7, 0x1f, 0x37, 0xac, 0xef, 0xef, 0x35, 0xac, 0x5f, 0xef,
-	0x35, 0xac, 0xf7, 0xf7, 0x1a, 0xd6, 0x07, 0x7b, 0x0d, 0xeb, 0x6f, 0x7b, 0x0d, 0xeb, 0xef, 0x7b,
-	0x8d, 0xcc, 0xa7, 0x7b, 0x0d, 0xeb, 0xdd, 0x4f, 0x1a, 0x99, 0x0f, 0x3e, 0x69, 0x64, 0x3e, 0xfc,
-	0xa4, 0x91, 0xf9, 0xce, 0xa3, 0x77, 0x2f, 0xdf, 0xa5, 0x5b, 0x2c, 0x8a, 0xaf, 0x27, 0xff, 0x13,
-	0x00, 0x00, 0xff, 0xff, 0x6c, 0x7a, 0xae, 0xe7, 0x7b, 0x24, 0x00, 0x00,
+	// 2858 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x5a, 0xcf, 0x6f, 0x1b, 0xc7,
+	0xf5, 0xe7, 0x92, 0xcb, 0x5f, 0x8f, 0x94, 0x2c, 0x8f, 0x68, 0x99, 0xa0, 0x6d, 0x52, 0x59, 0x7c,
+	0xbf, 0x89, 0x12, 0x3b, 0xa4, 0xad, 0x34, 0x69, 0xe2, 0x34, 0x6d, 0x4d, 0x29, 0x76, 0xec, 0x28,
+	0xb6, 0x33, 0x72, 0x9c, 0xb4, 0x68, 0x10, 0xac, 0xc9, 0x11, 0xb9, 0x31, 0xb9, 0x4b, 0xef, 0x0e,
+	0xed, 0xe8, 0xd6, 0x7f, 0xa0, 0x68, 0x80, 0xa2, 0x68, 0x7b, 0x29, 0x50, 0xa0, 0x40, 0x8b, 0x02,
+	0xb9, 0x14, 0x3d, 0xf4, 0x50, 0xb4, 0x97, 0x1e, 0xd2, 0x5b, 0x8e, 0x41, 0x0e, 0x6c, 0xa3, 0x5c,
+	0x0a, 0x01, 0x05, 0x72, 0x6a, 0x81, 0xf4, 0x52, 0xcc, 0xaf, 0xdd, 0xd9, 0x15, 0x19, 0x85, 0xae,
+	0x8b, 0xc4, 0x17, 0x71, 0xe7, 0x33, 0x6f, 0xde, 0xcc, 0xfb, 0x31, 0x6f, 0xde, 0xbc, 0x11, 0x9c,
+	0x18, 0xdd, 0xee, 0xb5, 0x06, 0x5e, 0x6f, 0xe4, 0x7b, 0xd4, 0x0b, 0x3f, 0x9a, 0xfc, 0x2f, 0x2a,
+	0xa8, 0x76, 0xad, 0xd2, 0xf3, 0x7a, 0x9e, 0xa0, 0x61, 0x5f, 0xa2, 0xbf, 0xd6, 0xe8, 0x79, 0x5e,
+	0x6f, 0x40, 0x5a, 0xbc, 0x75, 0x6b, 0xbc, 0xd3, 0xa2, 0xce, 0x90, 0x04, 0xd4, 0x1e, 0x8e, 0x24,
+	0xc1, 0xaa, 0xe4, 0x7e, 0x67, 0x30, 0xf4, 0xba, 0x64, 0xd0, 0x0a, 0xa8, 0x4d, 0x03, 0xf1, 0x57,
+	0x52, 0x2c, 0x33, 0x8a, 0xd1, 0x38, 0xe8, 0xf3, 0x3f, 0x12, 0x3c, 0xcb, 0xc0, 0x80, 0x7a, 0xbe,
+	0xdd, 0x23, 0xad, 0x4e, 0x7f, 0xec, 0xde, 0x6e, 0x75, 0xec, 0x4e, 0x9f, 0xb4, 0x7c, 0x12, 0x8c,
+	0x07, 0x34, 0x10, 0x0d, 0xba, 0x3b, 0x22, 0x92, 0x8d, 0xf5, 0x3b, 0x03, 0x8e, 0x6d, 0xd9, 0xb7,
+	0xc8, 0xe0, 0x86, 0x77, 0xd3, 0x1e, 0x8c, 0x49, 0x80, 0x49, 0x30, 0xf2, 0xdc, 0x80, 0xa0, 0x0d,
+	0xc8, 0x0d, 0x58, 0x47, 0x50, 0x35, 0x56, 0x33, 0x6b, 0xa5, 0xf5, 0xd3, 0xcd, 0x50, 0xc8, 0xa9,
+	0x03, 0x04, 0x1a, 0xbc, 0xe8, 0x52, 0x7f, 0x17, 0xcb, 0xa1, 0xb5, 0x9b, 0x50, 0xd2, 0x60, 0xb4,
+	0x04, 0x99, 0xdb, 0x64, 0xb7, 0x6a, 0xac, 0x1a, 0x6b, 0x45, 0xcc, 0x3e, 0xd1, 0x39, 0xc8, 0xde,
+	0x65, 0x6c, 0xaa, 0xe9, 0x55, 0x63, 0xad, 0xb4, 0x7e, 0x22, 0x9a, 0xe4, 0x35, 0xd7, 0xb9, 0x33,
+	0x26, 0x7c, 0xb4, 0x9c, 0x48, 0x50, 0x9e, 0x4f, 0x3f, 0x6b, 0x58, 0xa7, 0xe1, 0xe8, 0x81, 0x7e,
+	0xb4, 0x02, 0x39, 0x4e, 0x21, 0x56, 0x5c, 0xc4, 0xb2, 0x65, 0x55, 0x00, 0x6d, 0x53, 0x9f, 0xd8,
+	0x43, 0x6c, 0x53, 0xb6, 0xde, 0x3b, 0x63, 0x12, 0x50, 0xeb, 0x15, 0x58, 0x8e, 0xa1, 0x52, 0xec,
+	0x67, 0xa0, 0x14, 0x44, 0xb0, 0x94, 0xbd, 0x12, 0x2d, 0x2b, 0x1a, 0x83, 0x75, 0x42, 0xeb, 0xe7,
+	0x06, 0x40, 0xd4, 0x87, 0xea, 0x00, 0xa2, 0xf7, 0x25, 0x3b, 0xe8, 0x73, 0x81, 0x4d, 0xac, 0x21,
+	0xe8, 0x0c, 0x1c, 0x8d, 0x5a, 0x57, 0xbd, 0xed, 0xbe, 0xed, 0x77, 0xb9, 0x0e, 0x4c, 0x7c, 0xb0,
+	0x03, 0x21, 0x30, 0x7d, 0x9b, 0x92, 0x6a, 0x66, 0xd5, 0x58, 0xcb, 0x60, 0xfe, 0xcd, 0xa4, 0xa5,
+	0xc4, 0xb5, 0x5d, 0x5a, 0x35, 0xb9, 0x3a, 0x65, 0x8b, 0xe1, 0xcc, 0x23, 0x48, 0x50, 0xcd, 0xae,
+	0x1a, 0x6b, 0x0b, 0x58, 0xb6, 0xac, 0x7f, 0x66, 0xa0, 0xfc, 0xea, 0x98, 0xf8, 0xbb, 0x52, 0x01,
+	0xa8, 0x0e, 0x85, 0x80, 0x0c, 0x48, 0x87, 0x7a, 0xbe, 0xb0, 0x48, 0x3b, 0x5d, 0x35, 0x70, 0x88,
+	0xa1, 0x0a, 0x64, 0x07, 0xce, 0xd0, 0xa1, 0x7c, 0x59, 0x0b, 0x58, 0x34, 0xd0, 0x79, 0xc8, 0x06,
+	0xd4, 0xf6, 0x29, 0x5f, 0x4b, 0x69, 0xbd, 0xd6, 0x14, 0xae, 0xdc, 0x54, 0xae, 0xdc, 0xbc, 0xa1,
+	0x5c, 0xb9, 0x5d, 0x78, 0x7f, 0xd2, 0x48, 0xbd, 0xfb, 0xd7, 0x86, 0x81, 0xc5, 0x10, 0xf4, 0x0c,
+	0x64, 0x88, 0xdb, 0xad, 0x9a, 0x73, 0x8c, 0x64, 0x03, 0xd0, 0x39, 0x28, 0x76, 0x1d, 0x9f, 0x74,
+	0xa8, 0xe3, 0xb9, 0x5c, 0xaa, 0xc5, 0xf5, 0xe5, 0xc8, 0x22, 0x9b, 0xaa, 0x0b, 0x47, 0x54, 0xe8,
+	0x0c, 0xe4, 0x02, 0xa6, 0xba, 0xa0, 0x9a, 0x67, 0xbe, 0xd0, 0xae, 0xec, 0x4f, 0x1a, 0x4b, 0x02,
+	0x39, 0xe3, 0x0d, 0x1d, 0x4a, 0x86, 0x23, 0xba, 0x8b, 0x25, 0x0d, 0x7a, 0x02, 0xf2, 0x5d, 0x32,
+	0x20, 0xcc, 0xe0, 0x05, 0x6e, 0xf0, 0x25, 0x8d, 0x3d, 0xef, 0xc0, 0x8a, 0x00, 0xbd, 0x09, 0xe6,
+	0x68, 0x60, 0xbb, 0xd5, 0x22, 0x97, 0x62, 0x31, 0x22, 0xbc, 0x3e, 0xb0, 0xdd, 0xf6, 0x73, 0x1f,
+	0x4d, 0x1a, 0x4f, 0xf7, 0x1c, 0xda, 0x1f, 0xdf, 0x6a, 0x76, 0xbc, 0x61, 0xab, 0xe7, 0xdb, 0x3b,
+	0xb6, 0x6b, 0xb7, 0x06, 0xde, 0x6d, 0xa7, 0x75, 0xf7, 0xa9, 0x16, 0xdb, 0xa0, 0x77, 0xc6, 0xc4,
+	0x77, 0x88, 0xdf, 0x62, 0x6c, 0x9a, 0xdc, 0x24, 0x6c, 0x28, 0xe6, 0x6c, 0xd1, 0x15, 0xe6, 0x7f,
+	0x9e, 0x4f, 0x36, 0xd8, 0xee, 0x0d, 0xaa, 0xc0, 0x67, 0x39, 0x1e, 0xcd, 0xc2, 0x71, 0x4c, 0x76,
+	0x2e, 0xf9, 0xde, 0x78, 0xd4, 0x3e, 0xb2, 0x3f, 0x69, 0xe8, 0xf4, 0x58, 0x6f, 0x5c, 0x31, 0x0b,
+	0xb9, 0xa5, 0xbc, 0xf5, 0x5e, 0x06, 0xd0, 0xb6, 0x3d, 0x1c, 0x0d, 0xc8, 0x5c, 0xe6, 0x0f, 0x0d,
+	0x9d, 0xbe, 0x6f, 0x43, 0x67, 0xe6, 0x35, 0x74, 0x64, 0x35, 0x73, 0x3e, 0xab, 0x65, 0xbf, 0xa8,
+	0xd5, 0x72, 0x5f, 0x79, 0xab, 0x59, 0x55, 0x30, 0x19, 0x67, 0x16, 0x2c, 0x7d, 0xfb, 0x1e, 0xb7,
+	0x4d, 0x19, 0xb3, 0x4f, 0x6b, 0x0b, 0x72, 0x42, 0x2e, 0x54, 0x4b, 0x1a, 0x2f, 0xbe, 0x6f, 0x23,
+	0xc3, 0x65, 0x94, 0x49, 0x96, 0x22, 0x93, 0x64, 0xb8, 0xb2, 0xad, 0x3f, 0x18, 0xb0, 0x20, 0x3d,
+	0x42, 0xc6, 0xbe, 0x5b, 0x90, 0x17, 0xb1, 0x47, 0xc5, 0xbd, 0xe3, 0xc9, 0xb8, 0x77, 0xa1, 0x6b,
+	0x8f, 0x28, 0xf1, 0xdb, 0xad, 0xf7, 0x27, 0x0d, 0xe3, 0xa3, 0x49, 0xe3, 0xb1, 0x59, 0x4a, 0x53,
+	0xa7, 0x93, 0x1c, 0x87, 0x15, 0x63, 0x74, 0x9a, 0xaf, 0x8e, 0x06, 0xd2, 0xad, 0x8e, 0x34, 0x79,
+	0xab, 0x79, 0xd9, 0xed, 0x91, 0x80, 0x71, 0x36, 0x99, 0x47, 0x60, 0x41, 0xc3, 0xc4, 0xbc, 0x67,
+	0xfb, 0xae, 0xe3, 0xf6, 0x82, 0x6a, 0x86, 0xc7, 0xf4, 0xb0, 0x6d, 0xfd, 0xd4, 0x80, 0xe5, 0x98,
+	0x5b, 0x4b, 0x21, 0x9e, 0x85, 0x5c, 0xc0, 0x2c, 0xa5, 0x64, 0xd0, 0x9c, 0x62, 0x9b, 0xe3, 0xed,
+	0x45, 0xb9, 0xf8, 0x9c, 0x68, 0x63, 0x49, 0xff, 0xe0, 0x96, 0xf6, 0x67, 0x03, 0xca, 0xfc, 0x60,
+	0x52, 0x7b, 0x0d, 0x81, 0xe9, 0xda, 0x43, 0x22, 0x4d, 0xc5, 0xbf, 0xb5, 0xd3, 0x8a, 0x4d, 0x57,
+	0x50, 0xa7, 0xd5, 0xbc, 0x01, 0xd6, 0xb8, 0xef, 0x00, 0x6b, 0x44, 0xfb, 0xae, 0x02, 0x59, 0xe6,
+	0xde, 0xbb, 0x3c, 0xb8, 0x16, 0xb1, 0x68, 0x58, 0x8f, 0xc1, 0x82, 0x94, 0x42, 0xaa, 0x76, 0xd6,
+	0x01, 0x3b, 0x84, 0x9c, 0xb0, 0x04, 0xfa, 0x3f, 0x28, 0x86, 0xa9, 0x0c, 0x97, 0x36, 0xd3, 0xce,
+	0xed, 0x4f, 0x1a, 0x69, 0x1a, 0xe0, 0xa8, 0x03, 0x35, 0xf4, 0x43, 0xdf, 0x68, 0x17, 0xf7, 0x27,
+	0x0d, 0x01, 0xc8, 0x23, 0x1e, 0x9d, 0x04, 0xb3, 0xcf, 0xce, 0x4d, 0xa6, 0x02, 0xb3, 0x5d, 0xd8,
+	0x9f, 0x34, 0x78, 0x1b, 0xf3, 0xbf, 0xd6, 0x25, 0x28, 0x6f, 0x91, 0x9e, 0xdd, 0xd9, 0x95, 0x93,
+	0x56, 0x14, 0x3b, 0x36, 0xa1, 0xa1, 0x78, 0x3c, 0x02, 0xe5, 0x70, 0xc6, 0xb7, 0x86, 0x81, 0xdc,
+	0x0d, 0xa5, 0x10, 0x7b, 0x25, 0xb0, 0x7e, 0x66, 0x80, 0xf4, 0x01, 0x64, 0x69, 0xd9, 0x0e, 0x8b,
+	0x85, 0xb0, 0x3f, 0x69, 0x48, 0x44, 0x25, 0x33, 0xe8, 0x79, 0xc8, 0x07, 0x7c, 0x46, 0xc6, 0x2c,
+	0xe9, 0x5a, 0xbc, 0xa3, 0x7d, 0x84, 0xb9, 0xc8, 0xfe, 0xa4, 0xa1, 0x08, 0xb1, 0xfa, 0x40, 0xcd,
+	0x58, 0x42, 0x20, 0x04, 0x5b, 0xdc, 0x9f, 0x34, 0x34, 0x54, 0x4f, 0x10, 0xac, 0xcf, 0x0c, 0x28,
+	0xdd, 0xb0, 0x9d, 0xd0, 0x85, 0xaa, 0xca, 0x44, 0x51, 0xac, 0x16, 0x00, 0xf3, 0xc4, 0x2e, 0x19,
+	0xd8, 0xbb, 0x17, 0x3d, 0x9f, 0xf3, 0x5d, 0xc0, 0x61, 0x3b, 0x3a, 0xc3, 0xcd, 0xa9, 0x67, 0x78,
+	0x76, 0xfe, 0xd0, 0xfe, 0xbf, 0x0d, 0xa4, 0x57, 0xcc, 0x42, 0x7a, 0x29, 0x63, 0xbd, 0x67, 0x40,
+	0x59, 0x08, 0x2f, 0x3d, 0xef, 0x7b, 0x90, 0x13, 0xba, 0xe1, 0xe2, 0x7f, 0x4e, 0x60, 0x3a, 0x3d,
+	0x4f, 0x50, 0x92, 0x3c, 0xd1, 0xb7, 0x60, 0xb1, 0xeb, 0x7b, 0xa3, 0x11, 0xe9, 0x6e, 0xcb, 0xf0,
+	0x97, 0x4e, 0x86, 0xbf, 0x4d, 0xbd, 0x1f, 0x27, 0xc8, 0x59, 0x28, 0x5d, 0xda, 0x72, 0xee, 0x92,
+	0xd8, 0x9a, 0xe7, 0x8b, 0xa6, 0xa9, 0xfb, 0x8a, 0xa6, 0x9b, 0xe1, 0xca, 0x59, 0x82, 0xed, 0x84,
+	0x9e, 0xb9, 0x72, 0x60, 0xe5, 0x3c, 0x01, 0x97, 0x21, 0x2c, 0x31, 0xc6, 0x7a, 0x1b, 0xca, 0x3a,
+	0x15, 0x6a, 0x27, 0x77, 0xf1, 0x17, 0xf5, 0x11, 0x6d, 0x8f, 0xaf, 0x84, 0x1b, 0x2a, 0x2d, 0xd2,
+	0x53, 0xd1, 0xb2, 0xfe, 0x62, 0xc0, 0x82, 0x8c, 0xbb, 0xd2, 0xb3, 0x43, 0x6f, 0x34, 0xee, 0x3b,
+	0xd1, 0x48, 0xcf, 0x9b, 0x68, 0xac, 0x40, 0xae, 0xc7, 0x8e, 0x62, 0x15, 0xbb, 0x65, 0x6b, 0xbe,
+	0x04, 0xc4, 0xba, 0x02, 0x8b, 0x4a, 0x94, 0x19, 0x87, 0x4f, 0x2d, 0x79, 0xf8, 0x5c, 0xee, 0x12,
+	0x97, 0x3a, 0x3b, 0x4e, 0x78, 0x9c, 0x48, 0x7a, 0xeb, 0x87, 0x06, 0x2c, 0x25, 0x49, 0xd0, 0x66,
+	0xe2, 0x0e, 0xf6, 0xe8, 0x6c, 0x76, 0xfa, 0xf5, 0x4b, 0xb1, 0x96, 0x97, 0xb0, 0xa7, 0x0f, 0xbb,
+	0x84, 0x55, 0xf4, 0x78, 0x5c, 0x94, 0x01, 0xd4, 0xfa, 0x89, 0x01, 0x0b, 0x31, 0xb7, 0x47, 0xcf,
+	0x82, 0xb9, 0xe3, 0x7b, 0xc3, 0xb9, 0x0c, 0xc5, 0x47, 0xa0, 0xaf, 0x41, 0x9a, 0x7a, 0x73, 0x99,
+	0x29, 0x4d, 0x3d, 0xcd, 0x87, 0x32, 0x31, 0x1f, 0x7a, 0x1a, 0x8a, 0x5c, 0xa0, 0xeb, 0xb6, 0xe3,
+	0x4f, 0x3d, 0x5b, 0xa7, 0x0b, 0xf4, 0x3c, 0x1c, 0x11, 0xe7, 0xc6, 0xf4, 0xc1, 0xe5, 0x69, 0x83,
+	0xcb, 0x6a, 0xf0, 0x09, 0xc8, 0xf2, 0xfc, 0x8c, 0x0d, 0xe9, 0xda, 0xd4, 0x56, 0x43, 0xd8, 0xb7,
+	0x75, 0x0c, 0x96, 0xd9, 0xd6, 0x27, 0x7e, 0xb0, 0xe1, 0x8d, 0x5d, 0xaa, 0xae, 0x98, 0x67, 0xa0,
+	0x12, 0x87, 0xa5, 0x97, 0x54, 0x20, 0xdb, 0x61, 0x00, 0xe7, 0xb1, 0x80, 0x45, 0xc3, 0xfa, 0xa5,
+	0x01, 0xe8, 0x12, 0xa1, 0x7c, 0x96, 0xcb, 0x9b, 0xe1, 0xf6, 0xa8, 0x41, 0x61, 0x68, 0xd3, 0x4e,
+	0x9f, 0xf8, 0x81, 0x4a, 0xf5, 0x54, 0xfb, 0xcb, 0xc8, 0xd1, 0xad, 0x73, 0xb0, 0x1c, 0x5b, 0xa5,
+	0x94, 0xa9, 0x06, 0x85, 0x8e, 0xc4, 0x64, 0x76, 0x10, 0xb6, 0xad, 0xdf, 0xa6, 0xa1, 0xa0, 0x32,
+	0x60, 0x74, 0x0e, 0x4a, 0x3b, 0x8e, 0xdb, 0x23, 0xfe, 0xc8, 0x77, 0xa4, 0x0a, 0x4c, 0x91, 0x11,
+	0x6b, 0x30, 0xd6, 0x1b, 0xe8, 0x49, 0xc8, 0x8f, 0x03, 0xe2, 0xbf, 0xe5, 0x88, 0x9d, 0x5e, 0x6c,
+	0x57, 0xf6, 0x26, 0x8d, 0xdc, 0x6b, 0x01, 0xf1, 0x2f, 0x6f, 0xb2, 0x73, 0x7a, 0xcc, 0xbf, 0xb0,
+	0xf8, 0xed, 0xa2, 0x97, 0xa5, 0x9b, 0xf2, 0x5c, 0xb7, 0xfd, 0xf5, 0x29, 0xc1, 0x75, 0xe4, 0x7b,
+	0x43, 0x42, 0xfb, 0x64, 0x1c, 0xb4, 0x3a, 0xde, 0x70, 0xe8, 0xb9, 0x2d, 0x5e, 0x66, 0xe1, 0x42,
+	0xb3, 0x64, 0x83, 0x0d, 0x97, 0x9e, 0x7b, 0x03, 0xf2, 0xb4, 0xef, 0x7b, 0xe3, 0x5e, 0x9f, 0x9f,
+	0xa1, 0x99, 0xf6, 0xf9, 0xf9, 0xf9, 0x29, 0x0e, 0x58, 0x7d, 0xa0, 0x47, 0x98, 0xb6, 0x48, 0xe7,
+	0x76, 0x30, 0x1e, 0x8a, 0x6b, 0x7a, 0x3b, 0xbb, 0x3f, 0x69, 0x18, 0x4f, 0xe2, 0x10, 0xb6, 0x2e,
+	0xc0, 0x42, 0xec, 0xd6, 0x80, 0xce, 0x82, 0xe9, 0x93, 0x1d, 0x15, 0x0a, 0xd0, 0xc1, 0xcb, 0x85,
+	0x48, 0x94, 0x18, 0x0d, 0xe6, 0x7f, 0xad, 0x1f, 0xa4, 0xa1, 0xa1, 0x15, 0x48, 0x2e, 0x7a, 0xfe,
+	0x2b, 0x84, 0xfa, 0x4e, 0xe7, 0xaa, 0x3d, 0x24, 0xca, 0xbd, 0x1a, 0x50, 0x1a, 0x72, 0xf0, 0x2d,
+	0x6d, 0x17, 0xc1, 0x30, 0xa4, 0x43, 0xa7, 0x00, 0xf8, 0xb6, 0x13, 0xfd, 0x62, 0x43, 0x15, 0x39,
+	0xc2, 0xbb, 0x37, 0x62, 0xca, 0x6e, 0xcd, 0xa9, 0x1c, 0xa9, 0xe4, 0xcb, 0x49, 0x25, 0xcf, 0xcd,
+	0x27, 0xd4, 0xac, 0xbe, 0x5d, 0xb2, 0xf1, 0xed, 0x62, 0xfd, 0xc3, 0x80, 0xfa, 0x96, 0x5a, 0xf9,
+	0x7d, 0xaa, 0x43, 0xc9, 0x9b, 0x7e, 0x40, 0xf2, 0x66, 0x1e, 0xa0, 0xbc, 0x66, 0x42, 0xde, 0x3a,
+	0xc0, 0x96, 0xe3, 0x92, 0x8b, 0xce, 0x80, 0x12, 0x7f, 0xca, 0x7d, 0xf2, 0x47, 0x99, 0x28, 0xe2,
+	0x60, 0xb2, 0xa3, 0x74, 0xb0, 0xa1, 0x85, 0xf9, 0x07, 0x21, 0x62, 0xfa, 0x01, 0x8a, 0x98, 0x49,
+	0x44, 0x40, 0x17, 0xf2, 0x3b, 0x5c, 0x3c, 0x71, 0x62, 0xc7, 0x4a, 0x75, 0x91, 0xec, 0xed, 0x6f,
+	0xca, 0xc9, 0x9f, 0x39, 0x24, 0x37, 0xe5, 0x25, 0xd7, 0x56, 0xb0, 0xeb, 0x52, 0xfb, 0x1d, 0x6d,
+	0x3c, 0x56, 0x93, 0x20, 0x5b, 0xa6, 0xbf, 0xd9, 0xa9, 0xe9, 0xef, 0x0b, 0x72, 0x9a, 0xff, 0x26,
+	0x05, 0xb6, 0x7a, 0xb0, 0x1c, 0x33, 0x8a, 0x0c, 0xb0, 0x8f, 0x1e, 0xb6, 0xfd, 0xc5, 0xa6, 0x47,
+	0x6b, 0xf1, 0x5b, 0x6c, 0x39, 0xbc, 0xc5, 0x76, 0xc9, 0x3b, 0xb1, 0x2b, 0xac, 0xf5, 0x47, 0x03,
+	0x96, 0x2e, 0x11, 0x1a, 0xcf, 0xc6, 0x1e, 0x22, 0xe3, 0x5b, 0x2f, 0xc1, 0x51, 0x6d, 0xfd, 0x52,
+	0x4f, 0x4f, 0x25, 0x52, 0xb0, 0x63, 0x91, 0xa6, 0xb8, 0x0e, 0x04, 0x79, 0x22, 0xfb, 0xba, 0x0e,
+	0x25, 0xad, 0x13, 0x5d, 0x48, 0xe4, 0x5d, 0xcb, 0x89, 0xda, 0x37, 0xcb, 0x1d, 0xda, 0x15, 0x29,
+	0x93, 0xb8, 0xea, 0xcb, 0x5c, 0x3e, 0xcc, 0x51, 0xb6, 0x01, 0x71, 0xc3, 0x72, 0xb6, 0xfa, 0x29,
+	0xc9, 0xd1, 0x97, 0xc3, 0x04, 0x2c, 0x6c, 0xa3, 0x47, 0xc0, 0xf4, 0xbd, 0x7b, 0x2a, 0x83, 0x5f,
+	0x88, 0xa6, 0xc4, 0xde, 0x3d, 0xcc, 0xbb, 0xac, 0xe7, 0x21, 0x83, 0xbd, 0x7b, 0xac, 0xb8, 0xec,
+	0xdb, 0x6e, 0x8f, 0xdc, 0x0c, 0x6f, 0xbd, 0x65, 0xac, 0x21, 0x33, 0x32, 0x98, 0x0d, 0x38, 0xaa,
+	0xaf, 0x48, 0x98, 0xbb, 0x09, 0xf9, 0x57, 0xc7, 0xba, 0xba, 0x2a, 0x09, 0x75, 0xf1, 0x21, 0x58,
+	0x11, 0x31, 0x9f, 0x81, 0x08, 0x47, 0x27, 0xa1, 0x48, 0xed, 0x5b, 0x03, 0x72, 0x35, 0x0a, 0x96,
+	0x11, 0xc0, 0x7a, 0xd9, 0x85, 0xfd, 0xa6, 0x96, 0x8a, 0x45, 0x00, 0x7a, 0x02, 0x96, 0xa2, 0x35,
+	0x5f, 0xf7, 0xc9, 0x8e, 0xf3, 0x0e, 0xb7, 0x70, 0x19, 0x1f, 0xc0, 0xd1, 0x1a, 0x1c, 0x89, 0xb0,
+	0x6d, 0x9e, 0xf2, 0x98, 0x9c, 0x34, 0x09, 0x33, 0xdd, 0x70, 0x71, 0x5f, 0xbc, 0x33, 0xb6, 0x07,
+	0x7c, 0x9b, 0x96, 0xb1, 0x86, 0x58, 0x7f, 0x32, 0xe0, 0xa8, 0x30, 0x35, 0xdb, 0x03, 0x0f, 0xa3,
+	0xd7, 0xff, 0xca, 0x00, 0xa4, 0x4b, 0x20, 0x5d, 0xeb, 0xff, 0xf5, 0xeb, 0x26, 0xcb, 0xa9, 0x4a,
+	0xbc, 0x0e, 0x21, 0xa0, 0xe8, 0xc6, 0x68, 0x41, 0xae, 0x23, 0x8a, 0x94, 0xfc, 0xb5, 0x41, 0x14,
+	0x3a, 0x04, 0x82, 0xe5, 0x2f, 0xab, 0xcf, 0xdc, 0xda, 0xa5, 0x24, 0x90, 0x65, 0x0a, 0x5e, 0x9f,
+	0xe1, 0x00, 0x16, 0x3f, 0x6c, 0x2e, 0x22, 0xef, 0x9b, 0x66, 0x34, 0x97, 0x84, 0xb0, 0xfa, 0xb0,
+	0xfe, 0x95, 0x86, 0x85, 0x9b, 0xde, 0x60, 0x3c, 0x24, 0x0f, 0xa1, 0x9e, 0xe3, 0xb5, 0x93, 0xac,
+	0xaa, 0x9d, 0x20, 0x30, 0x03, 0x4a, 0x46, 0xdc, 0xb3, 0x32, 0x98, 0x7f, 0x23, 0x0b, 0xca, 0xd4,
+	0xf6, 0x7b, 0x84, 0x8a, 0x6b, 0x56, 0x35, 0xc7, 0xf3, 0xdf, 0x18, 0x86, 0x56, 0xa1, 0x64, 0xf7,
+	0x7a, 0x3e, 0xe9, 0xd9, 0x94, 0xb4, 0x77, 0xab, 0x79, 0x3e, 0x99, 0x0e, 0xa1, 0x2b, 0xb0, 0xc8,
+	0xde, 0xe7, 0x1c, 0xb7, 0x77, 0x6d, 0xc4, 0xde, 0x30, 0xd8, 0x5b, 0x04, 0x8b, 0xe0, 0x27, 0x9b,
+	0xfa, 0xeb, 0x5d, 0x73, 0x23, 0x46, 0xa3, 0x6e, 0xf4, 0xf1, 0x91, 0xd6, 0x1b, 0xb0, 0xa8, 0x14,
+	0x2f, 0xdd, 0xe3, 0x2c, 0xe4, 0xef, 0x72, 0x64, 0x4a, 0x5d, 0x54, 0x90, 0x4a, 0x56, 0x8a, 0x2c,
+	0xfe, 0xfe, 0xa3, 0xe4, 0xb7, 0xae, 0x40, 0x4e, 0x90, 0xb3, 0x22, 0x5d, 0x94, 0x23, 0x89, 0xdc,
+	0x93, 0xb5, 0xe5, 0x2d, 0xca, 0x82, 0x9c, 0x60, 0x54, 0xcd, 0x44, 0x7e, 0x26, 0x10, 0x2c, 0x7f,
+	0xad, 0x1f, 0xa7, 0xe1, 0xd8, 0x26, 0xa1, 0xa4, 0x43, 0x49, 0xf7, 0xa2, 0x43, 0x06, 0xdd, 0x2f,
+	0xb5, 0x26, 0x10, 0x16, 0x41, 0x33, 0x5a, 0x11, 0x94, 0xc5, 0xb0, 0x81, 0xe3, 0x92, 0x2d, 0xad,
+	0x8a, 0x16, 0x01, 0x91, 0x8e, 0xb2, 0x7a, 0x7d, 0x4d, 0xf9, 0x48, 0x4e, 0xf3, 0x91, 0xa8, 0x76,
+	0x9a, 0x8f, 0x95, 0x7b, 0xd5, 0x0d, 0xb4, 0x10, 0x5d, 0x5f, 0xad, 0xdf, 0x1b, 0xb0, 0x92, 0xd4,
+	0x8b, 0x34, 0xe3, 0x8b, 0x90, 0xdb, 0xe1, 0xc8, 0xc1, 0x9a, 0x52, 0x6c, 0x84, 0xa8, 0x5c, 0x08,
+	0x52, 0xbd, 0x72, 0x21, 0x10, 0xf4, 0x78, 0xec, 0x6d, 0xaf, 0xbd, 0xbc, 0x3f, 0x69, 0x1c, 0xe1,
+	0x80, 0x46, 0x2b, 0x85, 0x39, 0x13, 0x2e, 0x3c, 0x13, 0x95, 0x44, 0x04, 0xa2, 0x33, 0x16, 0x88,
+	0xf5, 0x6f, 0x56, 0x34, 0xd0, 0x17, 0xc2, 0x55, 0xc4, 0xb6, 0x80, 0x3c, 0x1e, 0x44, 0x03, 0x3d,
+	0x0e, 0x26, 0x7b, 0x86, 0x96, 0xf7, 0xb9, 0x63, 0x9f, 0x4d, 0x1a, 0x47, 0x63, 0xc3, 0x6e, 0xec,
+	0x8e, 0x08, 0xe6, 0x24, 0x6c, 0xe7, 0x74, 0x6c, 0xbf, 0xeb, 0xb8, 0xf6, 0xc0, 0xa1, 0xc2, 0x3a,
+	0x26, 0xd6, 0x21, 0x16, 0x8e, 0x46, 0xb6, 0x1f, 0xa8, 0x24, 0xb0, 0x28, 0xc2, 0x91, 0x84, 0xb0,
+	0xfa, 0x60, 0x92, 0x04, 0xb7, 0x09, 0xed, 0xf4, 0xc5, 0xb1, 0x20, 0x24, 0x11, 0x88, 0x2e, 0x89,
+	0x40, 0xd0, 0x3a, 0x14, 0xde, 0x0e, 0x3c, 0xf7, 0xba, 0x4d, 0xfb, 0x62, 0x43, 0xb7, 0x57, 0xf6,
+	0x27, 0x0d, 0xa4, 0x30, 0x6d, 0x44, 0x48, 0x67, 0xfd, 0xc2, 0x88, 0x1c, 0x5a, 0xec, 0xfb, 0xaf,
+	0x9c, 0x43, 0x5b, 0xdf, 0x81, 0x95, 0xe4, 0x12, 0xa5, 0x6f, 0xb1, 0x32, 0x68, 0xac, 0x67, 0xb6,
+	0x8f, 0xf1, 0x7e, 0x9c, 0x20, 0xb7, 0xc6, 0x91, 0xed, 0x39, 0x32, 0xc3, 0xf6, 0x09, 0x83, 0xa6,
+	0x0f, 0x1a, 0x34, 0xb2, 0x54, 0xe6, 0x70, 0x4b, 0x3d, 0xf1, 0x28, 0x14, 0xc3, 0x37, 0x60, 0x54,
+	0x82, 0xfc, 0xc5, 0x6b, 0xf8, 0xf5, 0x0b, 0x78, 0x73, 0x29, 0x85, 0xca, 0x50, 0x68, 0x5f, 0xd8,
+	0x78, 0x99, 0xb7, 0x8c, 0xf5, 0xdf, 0xe4, 0x54, 0xb2, 0xe3, 0xa3, 0x6f, 0x40, 0x56, 0x64, 0x30,
+	0x5a, 0xa5, 0x54, 0x7f, 0x1e, 0xad, 0x1d, 0x3f, 0x80, 0x0b, 0x2d, 0x59, 0xa9, 0xb3, 0x06, 0xba,
+	0x0a, 0x25, 0x0e, 0xca, 0x07, 0x88, 0x93, 0xc9, 0x77, 0x80, 0x18, 0xa7, 0x53, 0x33, 0x7a, 0x35,
+	0x7e, 0xe7, 0x21, 0x2b, 0x14, 0xb6, 0x92, 0x48, 0x34, 0xa7, 0xac, 0x26, 0xf6, 0x24, 0x63, 0xa5,
+	0xd0, 0x73, 0x60, 0xb2, 0x22, 0x13, 0xd2, 0xf2, 0x5c, 0xed, 0xdd, 0xa0, 0xb6, 0x92, 0x84, 0xb5,
+	0x69, 0x5f, 0x08, 0x9f, 0x3f, 0x8e, 0x27, 0x0b, 0x8b, 0x6a, 0x78, 0xf5, 0x60, 0x47, 0x38, 0xf3,
+	0x35, 0x28, 0xeb, 0xe5, 0x2d, 0x74, 0x2a, 0x3e, 0x55, 0xa2, 0x1a, 0x56, 0xab, 0xcf, 0xea, 0x0e,
+	0x19, 0x6e, 0x41, 0x49, 0x2b, 0x2d, 0xe9, 0x6a, 0x3d, 0x58, 0x17, 0xab, 0x9d, 0x9a, 0xd1, 0x1b,
+	0x72, 0xbb, 0x04, 0x05, 0x76, 0x3b, 0xe0, 0xaf, 0x75, 0x27, 0x92, 0x97, 0x00, 0x2d, 0xf9, 0xab,
+	0x9d, 0x9c, 0xde, 0x19, 0x32, 0xfa, 0x36, 0x14, 0x2f, 0x11, 0x2a, 0x4f, 0xbd, 0xe3, 0xc9, 0x63,
+	0x73, 0x8a, 0xa6, 0xe2, 0x47, 0xaf, 0x95, 0x42, 0x6f, 0xf0, 0x8b, 0x4a, 0x3c, 0xa4, 0xa3, 0xc6,
+	0x8c, 0xd0, 0x1d, 0xae, 0x6b, 0x75, 0x36, 0x41, 0xc8, 0xf9, 0xf5, 0x18, 0x67, 0x99, 0x6b, 0x34,
+	0x66, 0x6c, 0xd8, 0x90, 0x73, 0xe3, 0x90, 0xff, 0xe5, 0xb1, 0x52, 0xeb, 0x2f, 0x41, 0x7e, 0xcb,
+	0xeb, 0x71, 0xcf, 0x7a, 0xe1, 0xf3, 0x3d, 0x4c, 0xab, 0x71, 0x27, 0xdf, 0x40, 0x98, 0x97, 0xad,
+	0xbf, 0xa9, 0xfe, 0x31, 0x66, 0xd3, 0xa6, 0x36, 0xba, 0x06, 0x8b, 0xdc, 0x2a, 0xe1, 0x7f, 0xce,
+	0xc4, 0x76, 0xcf, 0x81, 0x7f, 0xd3, 0xa9, 0x9d, 0x9a, 0xd1, 0xab, 0x26, 0x68, 0xbf, 0xf9, 0xc1,
+	0xc7, 0xf5, 0xd4, 0x87, 0x1f, 0xd7, 0x53, 0x9f, 0x7e, 0x5c, 0x37, 0xbe, 0xbf, 0x57, 0x37, 0x7e,
+	0xbd, 0x57, 0x37, 0xde, 0xdf, 0xab, 0x1b, 0x1f, 0xec, 0xd5, 0x8d, 0xbf, 0xed, 0xd5, 0x8d, 0xbf,
+	0xef, 0xd5, 0x53, 0x9f, 0xee, 0xd5, 0x8d, 0x77, 0x3f, 0xa9, 0xa7, 0x3e, 0xf8, 0xa4, 0x9e, 0xfa,
+	0xf0, 0x93, 0x7a, 0xea, 0xbb, 0x8f, 0x1d, 0x5e, 0x08, 0x10, 0x01, 0x36, 0xc7, 0x7f, 0x9e, 0xfa,
+	0xcf, 0x00, 0x29, 0x54, 0xca, 0xf1, 0xf0, 0x25, 0x00, 0x00,
 }
 
 func (x Direction) String() string {
@@ -3992,6 +4096,70 @@ func (this *TailResponse) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *LiveTailResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*LiveTailResponse)
+	if !ok {
+		that2, ok := that.(LiveTailResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Streams) != len(that1.Streams) {
+		return false
+	}
+	for i := range this.Streams {
+		if !this.Streams[i].Equal(that1.Streams[i]) {
+			return false
+		}
+	}
+	if len(this.DroppedEntries) != len(that1.DroppedEntries) {
+		return false
+	}
+	for i := range this.DroppedEntries {
+		if !this.DroppedEntries[i].Equal(&that1.DroppedEntries[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *DroppedEntry) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*DroppedEntry)
+	if !ok {
+		that2, ok := that.(DroppedEntry)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.Timestamp.Equal(that1.Timestamp) {
+		return false
+	}
+	if this.Labels != that1.Labels {
+		return false
+	}
+	return true
+}
 func (this *SeriesRequest) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
@@ -5380,9 +5548,9 @@ func (this *Series) GoString() string {
 	s = append(s, "&logproto.Series{")
 	s = append(s, "Labels: "+fmt.Sprintf("%#v", this.Labels)+",\n")
 	if this.Samples != nil {
-		vs := make([]*Sample, len(this.Samples))
+		vs := make([]Sample, len(this.Samples))
 		for i := range vs {
-			vs[i] = &this.Samples[i]
+			vs[i] = this.Samples[i]
 		}
 		s = append(s, "Samples: "+fmt.Sprintf("%#v", vs)+",\n")
 	}
@@ -5417,6 +5585,34 @@ func (this *TailResponse) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
+func (this *LiveTailResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&logproto.LiveTailResponse{")
+	s = append(s, "Streams: "+fmt.Sprintf("%#v", this.Streams)+",\n")
+	if this.DroppedEntries != nil {
+		vs := make([]DroppedEntry, len(this.DroppedEntries))
+		for i := range vs {
+			vs[i] = this.DroppedEntries[i]
+		}
+		s = append(s, "DroppedEntries: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *DroppedEntry) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&logproto.DroppedEntry{")
+	s = append(s, "Timestamp: "+fmt.Sprintf("%#v", this.Timestamp)+",\n")
+	s = append(s, "Labels: "+fmt.Sprintf("%#v", this.Labels)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func (this *SeriesRequest) GoString() string {
 	if this == nil {
 		return "nil"
@@ -5437,9 +5633,9 @@ func (this *SeriesResponse) GoString() string {
 	s := make([]string, 0, 5)
 	s = append(s, "&logproto.SeriesResponse{")
 	if this.Series != nil {
-		vs := make([]*SeriesIdentifier, len(this.Series))
+		vs := make([]SeriesIdentifier, len(this.Series))
 		for i := range vs {
-			vs[i] = &this.Series[i]
+			vs[i] = this.Series[i]
 		}
 		s = append(s, "Series: "+fmt.Sprintf("%#v", vs)+",\n")
 	}
@@ -5453,9 +5649,9 @@ func (this *SeriesIdentifier) GoString() string {
 	s := make([]string, 0, 5)
 	s = append(s, "&logproto.SeriesIdentifier{")
 	if this.Labels != nil {
-		vs := make([]*SeriesIdentifier_LabelsEntry, len(this.Labels))
+		vs := make([]SeriesIdentifier_LabelsEntry, len(this.Labels))
 		for i := range vs {
-			vs[i] = &this.Labels[i]
+			vs[i] = this.Labels[i]
 		}
 		s = append(s, "Labels: "+fmt.Sprintf("%#v", vs)+",\n")
 	}
@@ -5667,9 +5863,9 @@ func (this *GetSeriesResponse) GoString() string {
 	s := make([]string, 0, 5)
 	s = append(s, "&logproto.GetSeriesResponse{")
 	if this.Series != nil {
-		vs := make([]*IndexSeries, len(this.Series))
+		vs := make([]IndexSeries, len(this.Series))
 		for i := range vs {
-			vs[i] = &this.Series[i]
+			vs[i] = this.Series[i]
 		}
 		s = append(s, "Series: "+fmt.Sprintf("%#v", vs)+",\n")
 	}
@@ -5785,9 +5981,9 @@ func (this *VolumeResponse) GoString() string {
 	s := make([]string, 0, 6)
 	s = append(s, "&logproto.VolumeResponse{")
 	if this.Volumes != nil {
-		vs := make([]*Volume, len(this.Volumes))
+		vs := make([]Volume, len(this.Volumes))
 		for i := range vs {
-			vs[i] = &this.Volumes[i]
+			vs[i] = this.Volumes[i]
 		}
 		s = append(s, "Volumes: "+fmt.Sprintf("%#v", vs)+",\n")
 	}
@@ -6427,68 +6623,167 @@ var _Querier_serviceDesc = grpc.ServiceDesc{
 	Metadata: "pkg/logproto/logproto.proto",
 }
 
-// StreamDataClient is the client API for StreamData service.
+// LogTailClient is the client API for LogTail service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type StreamDataClient interface {
-	GetStreamRates(ctx context.Context, in *StreamRatesRequest, opts ...grpc.CallOption) (*StreamRatesResponse, error)
+type LogTailClient interface {
+	Tail(ctx context.Context, in *TailRequest, opts ...grpc.CallOption) (LogTail_TailClient, error)
 }
 
-type streamDataClient struct {
+type logTailClient struct {
 	cc *grpc.ClientConn
 }
 
-func NewStreamDataClient(cc *grpc.ClientConn) StreamDataClient {
-	return &streamDataClient{cc}
+func NewLogTailClient(cc *grpc.ClientConn) LogTailClient {
+	return &logTailClient{cc}
 }
 
-func (c *streamDataClient) GetStreamRates(ctx context.Context, in *StreamRatesRequest, opts ...grpc.CallOption) (*StreamRatesResponse, error) {
-	out := new(StreamRatesResponse)
-	err := c.cc.Invoke(ctx, "/logproto.StreamData/GetStreamRates", in, out, opts...)
+func (c *logTailClient) Tail(ctx context.Context, in *TailRequest, opts ...grpc.CallOption) (LogTail_TailClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_LogTail_serviceDesc.Streams[0], "/logproto.LogTail/Tail", opts...)
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	x := &logTailTailClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
 }
 
-// StreamDataServer is the server API for StreamData service.
-type StreamDataServer interface {
-	GetStreamRates(context.Context, *StreamRatesRequest) (*StreamRatesResponse, error)
+type LogTail_TailClient interface {
+	Recv() (*LiveTailResponse, error)
+	grpc.ClientStream
 }
 
-// UnimplementedStreamDataServer can be embedded to have forward compatible implementations.
-type UnimplementedStreamDataServer struct {
+type logTailTailClient struct {
+	grpc.ClientStream
 }
 
-func (*UnimplementedStreamDataServer) GetStreamRates(ctx context.Context, req *StreamRatesRequest) (*StreamRatesResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetStreamRates not implemented")
+func (x *logTailTailClient) Recv() (*LiveTailResponse, error) {
+	m := new(LiveTailResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
 }
 
-func RegisterStreamDataServer(s *grpc.Server, srv StreamDataServer) {
-	s.RegisterService(&_StreamData_serviceDesc, srv)
+// LogTailServer is the server API for LogTail service.
+type LogTailServer interface {
+	Tail(*TailRequest, LogTail_TailServer) error
 }
 
-func _StreamData_GetStreamRates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StreamRatesRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(StreamDataServer).GetStreamRates(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/logproto.StreamData/GetStreamRates",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(StreamDataServer).GetStreamRates(ctx, req.(*StreamRatesRequest))
-	}
-	return interceptor(ctx, in, info, handler)
+// UnimplementedLogTailServer can be embedded to have forward compatible implementations.
+type UnimplementedLogTailServer struct {
 }
 
-var _StreamData_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "logproto.StreamData",
-	HandlerType: (*StreamDataServer)(nil),
+func (*UnimplementedLogTailServer) Tail(req *TailRequest, srv LogTail_TailServer) error {
+	return status.Errorf(codes.Unimplemented, "method Tail not implemented")
+}
+
+func RegisterLogTailServer(s *grpc.Server, srv LogTailServer) {
+	s.RegisterService(&_LogTail_serviceDesc, srv)
+}
+
+func _LogTail_Tail_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TailRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LogTailServer).Tail(m, &logTailTailServer{stream})
+}
+
+type LogTail_TailServer interface {
+	Send(*LiveTailResponse) error
+	grpc.ServerStream
+}
+
+type logTailTailServer struct {
+	grpc.ServerStream
+}
+
+func (x *logTailTailServer) Send(m *LiveTailResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _LogTail_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "logproto.LogTail",
+	HandlerType: (*LogTailServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Tail",
+			Handler:       _LogTail_Tail_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "pkg/logproto/logproto.proto",
+}
+
+// StreamDataClient is the client API for StreamData service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type StreamDataClient interface {
+	GetStreamRates(ctx context.Context, in *StreamRatesRequest, opts ...grpc.CallOption) (*StreamRatesResponse, error)
+}
+
+type streamDataClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewStreamDataClient(cc *grpc.ClientConn) StreamDataClient {
+	return &streamDataClient{cc}
+}
+
+func (c *streamDataClient) GetStreamRates(ctx context.Context, in *StreamRatesRequest, opts ...grpc.CallOption) (*StreamRatesResponse, error) {
+	out := new(StreamRatesResponse)
+	err := c.cc.Invoke(ctx, "/logproto.StreamData/GetStreamRates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StreamDataServer is the server API for StreamData service.
+type StreamDataServer interface {
+	GetStreamRates(context.Context, *StreamRatesRequest) (*StreamRatesResponse, error)
+}
+
+// UnimplementedStreamDataServer can be embedded to have forward compatible implementations.
+type UnimplementedStreamDataServer struct {
+}
+
+func (*UnimplementedStreamDataServer) GetStreamRates(ctx context.Context, req *StreamRatesRequest) (*StreamRatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStreamRates not implemented")
+}
+
+func RegisterStreamDataServer(s *grpc.Server, srv StreamDataServer) {
+	s.RegisterService(&_StreamData_serviceDesc, srv)
+}
+
+func _StreamData_GetStreamRates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StreamRatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StreamDataServer).GetStreamRates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/logproto.StreamData/GetStreamRates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StreamDataServer).GetStreamRates(ctx, req.(*StreamRatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _StreamData_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "logproto.StreamData",
+	HandlerType: (*StreamDataServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
 			MethodName: "GetStreamRates",
@@ -7398,6 +7693,95 @@ func (m *TailResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *LiveTailResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LiveTailResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *LiveTailResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.DroppedEntries) > 0 {
+		for iNdEx := len(m.DroppedEntries) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.DroppedEntries[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintLogproto(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Streams) > 0 {
+		for iNdEx := len(m.Streams) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size := m.Streams[iNdEx].Size()
+				i -= size
+				if _, err := m.Streams[iNdEx].MarshalTo(dAtA[i:]); err != nil {
+					return 0, err
+				}
+				i = encodeVarintLogproto(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DroppedEntry) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DroppedEntry) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DroppedEntry) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Labels) > 0 {
+		i -= len(m.Labels)
+		copy(dAtA[i:], m.Labels)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Labels)))
+		i--
+		dAtA[i] = 0x12
+	}
+	n17, err17 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Timestamp, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Timestamp):])
+	if err17 != nil {
+		return 0, err17
+	}
+	i -= n17
+	i = encodeVarintLogproto(dAtA, i, uint64(n17))
+	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
 func (m *SeriesRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -7436,21 +7820,21 @@ func (m *SeriesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 			dAtA[i] = 0x1a
 		}
 	}
-	n17, err17 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.End, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.End):])
-	if err17 != nil {
-		return 0, err17
-	}
-	i -= n17
-	i = encodeVarintLogproto(dAtA, i, uint64(n17))
-	i--
-	dAtA[i] = 0x12
-	n18, err18 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Start, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Start):])
+	n18, err18 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.End, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.End):])
 	if err18 != nil {
 		return 0, err18
 	}
 	i -= n18
 	i = encodeVarintLogproto(dAtA, i, uint64(n18))
 	i--
+	dAtA[i] = 0x12
+	n19, err19 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Start, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Start):])
+	if err19 != nil {
+		return 0, err19
+	}
+	i -= n19
+	i = encodeVarintLogproto(dAtA, i, uint64(n19))
+	i--
 	dAtA[i] = 0xa
 	return len(dAtA) - i, nil
 }
@@ -7593,21 +7977,21 @@ func (m *DroppedStream) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i--
 		dAtA[i] = 0x1a
 	}
-	n19, err19 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.To, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.To):])
-	if err19 != nil {
-		return 0, err19
-	}
-	i -= n19
-	i = encodeVarintLogproto(dAtA, i, uint64(n19))
-	i--
-	dAtA[i] = 0x12
-	n20, err20 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.From, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.From):])
+	n20, err20 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.To, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.To):])
 	if err20 != nil {
 		return 0, err20
 	}
 	i -= n20
 	i = encodeVarintLogproto(dAtA, i, uint64(n20))
 	i--
+	dAtA[i] = 0x12
+	n21, err21 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.From, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.From):])
+	if err21 != nil {
+		return 0, err21
+	}
+	i -= n21
+	i = encodeVarintLogproto(dAtA, i, uint64(n21))
+	i--
 	dAtA[i] = 0xa
 	return len(dAtA) - i, nil
 }
@@ -7787,21 +8171,21 @@ func (m *GetChunkIDsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
-	n21, err21 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.End, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.End):])
-	if err21 != nil {
-		return 0, err21
-	}
-	i -= n21
-	i = encodeVarintLogproto(dAtA, i, uint64(n21))
-	i--
-	dAtA[i] = 0x1a
-	n22, err22 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Start, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Start):])
+	n22, err22 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.End, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.End):])
 	if err22 != nil {
 		return 0, err22
 	}
 	i -= n22
 	i = encodeVarintLogproto(dAtA, i, uint64(n22))
 	i--
+	dAtA[i] = 0x1a
+	n23, err23 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Start, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Start):])
+	if err23 != nil {
+		return 0, err23
+	}
+	i -= n23
+	i = encodeVarintLogproto(dAtA, i, uint64(n23))
+	i--
 	dAtA[i] = 0x12
 	if len(m.Matchers) > 0 {
 		i -= len(m.Matchers)
@@ -8759,21 +9143,21 @@ func (m *DetectedFieldsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i--
 		dAtA[i] = 0x1a
 	}
-	n26, err26 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.End, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.End):])
-	if err26 != nil {
-		return 0, err26
-	}
-	i -= n26
-	i = encodeVarintLogproto(dAtA, i, uint64(n26))
-	i--
-	dAtA[i] = 0x12
-	n27, err27 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Start, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Start):])
+	n27, err27 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.End, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.End):])
 	if err27 != nil {
 		return 0, err27
 	}
 	i -= n27
 	i = encodeVarintLogproto(dAtA, i, uint64(n27))
 	i--
+	dAtA[i] = 0x12
+	n28, err28 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Start, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Start):])
+	if err28 != nil {
+		return 0, err28
+	}
+	i -= n28
+	i = encodeVarintLogproto(dAtA, i, uint64(n28))
+	i--
 	dAtA[i] = 0xa
 	return len(dAtA) - i, nil
 }
@@ -8923,21 +9307,21 @@ func (m *DetectedLabelsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i--
 		dAtA[i] = 0x1a
 	}
-	n28, err28 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.End, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.End):])
-	if err28 != nil {
-		return 0, err28
-	}
-	i -= n28
-	i = encodeVarintLogproto(dAtA, i, uint64(n28))
-	i--
-	dAtA[i] = 0x12
-	n29, err29 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Start, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Start):])
+	n29, err29 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.End, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.End):])
 	if err29 != nil {
 		return 0, err29
 	}
 	i -= n29
 	i = encodeVarintLogproto(dAtA, i, uint64(n29))
 	i--
+	dAtA[i] = 0x12
+	n30, err30 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Start, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Start):])
+	if err30 != nil {
+		return 0, err30
+	}
+	i -= n30
+	i = encodeVarintLogproto(dAtA, i, uint64(n30))
+	i--
 	dAtA[i] = 0xa
 	return len(dAtA) - i, nil
 }
@@ -9418,53 +9802,89 @@ func (m *TailResponse) Size() (n int) {
 	return n
 }
 
-func (m *SeriesRequest) Size() (n int) {
+func (m *LiveTailResponse) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Start)
-	n += 1 + l + sovLogproto(uint64(l))
-	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.End)
-	n += 1 + l + sovLogproto(uint64(l))
-	if len(m.Groups) > 0 {
-		for _, s := range m.Groups {
-			l = len(s)
+	if len(m.Streams) > 0 {
+		for _, e := range m.Streams {
+			l = e.Size()
 			n += 1 + l + sovLogproto(uint64(l))
 		}
 	}
-	if len(m.Shards) > 0 {
-		for _, s := range m.Shards {
-			l = len(s)
+	if len(m.DroppedEntries) > 0 {
+		for _, e := range m.DroppedEntries {
+			l = e.Size()
 			n += 1 + l + sovLogproto(uint64(l))
 		}
 	}
 	return n
 }
 
-func (m *SeriesResponse) Size() (n int) {
+func (m *DroppedEntry) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	if len(m.Series) > 0 {
-		for _, e := range m.Series {
-			l = e.Size()
-			n += 1 + l + sovLogproto(uint64(l))
-		}
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Timestamp)
+	n += 1 + l + sovLogproto(uint64(l))
+	l = len(m.Labels)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
 	}
 	return n
 }
 
-func (m *SeriesIdentifier) Size() (n int) {
+func (m *SeriesRequest) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	if len(m.Labels) > 0 {
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Start)
+	n += 1 + l + sovLogproto(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.End)
+	n += 1 + l + sovLogproto(uint64(l))
+	if len(m.Groups) > 0 {
+		for _, s := range m.Groups {
+			l = len(s)
+			n += 1 + l + sovLogproto(uint64(l))
+		}
+	}
+	if len(m.Shards) > 0 {
+		for _, s := range m.Shards {
+			l = len(s)
+			n += 1 + l + sovLogproto(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SeriesResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Series) > 0 {
+		for _, e := range m.Series {
+			l = e.Size()
+			n += 1 + l + sovLogproto(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SeriesIdentifier) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Labels) > 0 {
 		for _, e := range m.Labels {
 			l = e.Size()
 			n += 1 + l + sovLogproto(uint64(l))
@@ -10397,6 +10817,33 @@ func (this *TailResponse) String() string {
 	}, "")
 	return s
 }
+func (this *LiveTailResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForDroppedEntries := "[]DroppedEntry{"
+	for _, f := range this.DroppedEntries {
+		repeatedStringForDroppedEntries += strings.Replace(strings.Replace(f.String(), "DroppedEntry", "DroppedEntry", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForDroppedEntries += "}"
+	s := strings.Join([]string{`&LiveTailResponse{`,
+		`Streams:` + fmt.Sprintf("%v", this.Streams) + `,`,
+		`DroppedEntries:` + repeatedStringForDroppedEntries + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *DroppedEntry) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&DroppedEntry{`,
+		`Timestamp:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Timestamp), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
+		`Labels:` + fmt.Sprintf("%v", this.Labels) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *SeriesRequest) String() string {
 	if this == nil {
 		return "nil"
@@ -11037,7 +11484,7 @@ func (m *LabelToValuesResponse) Unmarshal(dAtA []byte) error {
 					if err != nil {
 						return err
 					}
-					if skippy < 0 {
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
 						return ErrInvalidLengthLogproto
 					}
 					if (iNdEx + skippy) > postIndex {
@@ -11054,10 +11501,7 @@ func (m *LabelToValuesResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -11139,10 +11583,7 @@ func (m *UniqueLabelValues) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -11192,10 +11633,7 @@ func (m *StreamRatesRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -11279,10 +11717,7 @@ func (m *StreamRatesResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -11440,10 +11875,7 @@ func (m *StreamRate) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -11767,10 +12199,7 @@ func (m *QueryRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -12056,10 +12485,7 @@ func (m *SampleQueryRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -12143,10 +12569,7 @@ func (m *Plan) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -12266,10 +12689,7 @@ func (m *Delete) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -12418,10 +12838,7 @@ func (m *QueryResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -12570,10 +12987,7 @@ func (m *SampleQueryResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -12779,10 +13193,7 @@ func (m *LabelRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -12864,10 +13275,7 @@ func (m *LabelResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -12966,10 +13374,7 @@ func (m *Sample) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -13049,10 +13454,7 @@ func (m *LegacySample) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -13130,7 +13532,299 @@ func (m *Series) Unmarshal(dAtA []byte) error {
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Samples", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Samples", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Samples = append(m.Samples, Sample{})
+			if err := m.Samples[len(m.Samples)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StreamHash", wireType)
+			}
+			m.StreamHash = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StreamHash |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipLogproto(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TailRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowLogproto
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TailRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TailRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Query = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DelayFor", wireType)
+			}
+			m.DelayFor = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DelayFor |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+			}
+			m.Limit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Limit |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Start, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Plan", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Plan == nil {
+				m.Plan = &github_com_grafana_loki_v3_pkg_querier_plan.QueryPlan{}
+			}
+			if err := m.Plan.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipLogproto(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TailResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowLogproto
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TailResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TailResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stream", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -13157,16 +13851,18 @@ func (m *Series) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Samples = append(m.Samples, Sample{})
-			if err := m.Samples[len(m.Samples)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.Stream == nil {
+				m.Stream = &github_com_grafana_loki_pkg_push.Stream{}
+			}
+			if err := m.Stream.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field StreamHash", wireType)
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DroppedStreams", wireType)
 			}
-			m.StreamHash = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowLogproto
@@ -13176,21 +13872,33 @@ func (m *Series) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.StreamHash |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if msglen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DroppedStreams = append(m.DroppedStreams, &DroppedStream{})
+			if err := m.DroppedStreams[len(m.DroppedStreams)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipLogproto(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -13205,7 +13913,7 @@ func (m *Series) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TailRequest) Unmarshal(dAtA []byte) error {
+func (m *LiveTailResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -13228,85 +13936,15 @@ func (m *TailRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TailRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: LiveTailResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TailRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: LiveTailResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowLogproto
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Query = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DelayFor", wireType)
-			}
-			m.DelayFor = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowLogproto
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.DelayFor |= uint32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
-			}
-			m.Limit = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowLogproto
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Limit |= uint32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 5:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Streams", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -13333,13 +13971,14 @@ func (m *TailRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Start, dAtA[iNdEx:postIndex]); err != nil {
+			m.Streams = append(m.Streams, github_com_grafana_loki_pkg_push.Stream{})
+			if err := m.Streams[len(m.Streams)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 6:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Plan", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field DroppedEntries", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -13366,10 +14005,8 @@ func (m *TailRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Plan == nil {
-				m.Plan = &github_com_grafana_loki_v3_pkg_querier_plan.QueryPlan{}
-			}
-			if err := m.Plan.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.DroppedEntries = append(m.DroppedEntries, DroppedEntry{})
+			if err := m.DroppedEntries[len(m.DroppedEntries)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -13379,10 +14016,7 @@ func (m *TailRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -13397,7 +14031,7 @@ func (m *TailRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TailResponse) Unmarshal(dAtA []byte) error {
+func (m *DroppedEntry) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -13420,15 +14054,15 @@ func (m *TailResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TailResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: DroppedEntry: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TailResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: DroppedEntry: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Stream", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -13455,18 +14089,15 @@ func (m *TailResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Stream == nil {
-				m.Stream = &github_com_grafana_loki_pkg_push.Stream{}
-			}
-			if err := m.Stream.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Timestamp, dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DroppedStreams", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowLogproto
@@ -13476,25 +14107,23 @@ func (m *TailResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthLogproto
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.DroppedStreams = append(m.DroppedStreams, &DroppedStream{})
-			if err := m.DroppedStreams[len(m.DroppedStreams)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Labels = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -13502,10 +14131,7 @@ func (m *TailResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -13685,10 +14311,7 @@ func (m *SeriesRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -13772,10 +14395,7 @@ func (m *SeriesResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -13859,10 +14479,7 @@ func (m *SeriesIdentifier) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -13976,10 +14593,7 @@ func (m *SeriesIdentifier_LabelsEntry) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -14127,10 +14741,7 @@ func (m *DroppedStream) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -14244,10 +14855,7 @@ func (m *LabelPair) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -14365,10 +14973,7 @@ func (m *LegacyLabelPair) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -14452,10 +15057,7 @@ func (m *Chunk) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -14505,10 +15107,7 @@ func (m *TailersCountRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -14577,10 +15176,7 @@ func (m *TailersCountResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -14728,10 +15324,7 @@ func (m *GetChunkIDsRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -14813,10 +15406,7 @@ func (m *GetChunkIDsResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -14974,10 +15564,7 @@ func (m *ChunkRef) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -15061,10 +15648,7 @@ func (m *ChunkRefGroup) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -15248,10 +15832,7 @@ func (m *LabelValuesForMetricNameRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -15403,10 +15984,7 @@ func (m *LabelNamesForMetricNameRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -15490,10 +16068,7 @@ func (m *LineFilter) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -15680,10 +16255,7 @@ func (m *GetChunkRefRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -15800,10 +16372,7 @@ func (m *GetChunkRefResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -15923,10 +16492,7 @@ func (m *GetSeriesRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -16010,10 +16576,7 @@ func (m *GetSeriesResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -16097,10 +16660,7 @@ func (m *IndexSeries) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -16216,10 +16776,7 @@ func (m *QueryIndexResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -16337,10 +16894,7 @@ func (m *Row) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -16424,10 +16978,7 @@ func (m *QueryIndexRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -16643,10 +17194,7 @@ func (m *IndexQuery) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -16766,10 +17314,7 @@ func (m *IndexStatsRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -16895,10 +17440,7 @@ func (m *IndexStatsResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -17153,10 +17695,7 @@ func (m *VolumeRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -17259,10 +17798,7 @@ func (m *VolumeResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -17363,10 +17899,7 @@ func (m *Volume) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -17623,10 +18156,7 @@ func (m *DetectedFieldsRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -17761,10 +18291,7 @@ func (m *DetectedFieldsResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -17995,10 +18522,7 @@ func (m *DetectedField) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -18146,10 +18670,7 @@ func (m *DetectedLabelsRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -18233,10 +18754,7 @@ func (m *DetectedLabelsResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -18371,10 +18889,7 @@ func (m *DetectedLabel) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -18392,6 +18907,7 @@ func (m *DetectedLabel) Unmarshal(dAtA []byte) error {
 func skipLogproto(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
+	depth := 0
 	for iNdEx < l {
 		var wire uint64
 		for shift := uint(0); ; shift += 7 {
@@ -18423,10 +18939,8 @@ func skipLogproto(dAtA []byte) (n int, err error) {
 					break
 				}
 			}
-			return iNdEx, nil
 		case 1:
 			iNdEx += 8
-			return iNdEx, nil
 		case 2:
 			var length int
 			for shift := uint(0); ; shift += 7 {
@@ -18447,55 +18961,30 @@ func skipLogproto(dAtA []byte) (n int, err error) {
 				return 0, ErrInvalidLengthLogproto
 			}
 			iNdEx += length
-			if iNdEx < 0 {
-				return 0, ErrInvalidLengthLogproto
-			}
-			return iNdEx, nil
 		case 3:
-			for {
-				var innerWire uint64
-				var start int = iNdEx
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return 0, ErrIntOverflowLogproto
-					}
-					if iNdEx >= l {
-						return 0, io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					innerWire |= (uint64(b) & 0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				innerWireType := int(innerWire & 0x7)
-				if innerWireType == 4 {
-					break
-				}
-				next, err := skipLogproto(dAtA[start:])
-				if err != nil {
-					return 0, err
-				}
-				iNdEx = start + next
-				if iNdEx < 0 {
-					return 0, ErrInvalidLengthLogproto
-				}
-			}
-			return iNdEx, nil
+			depth++
 		case 4:
-			return iNdEx, nil
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupLogproto
+			}
+			depth--
 		case 5:
 			iNdEx += 4
-			return iNdEx, nil
 		default:
 			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
 		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthLogproto
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
 	}
-	panic("unreachable")
+	return 0, io.ErrUnexpectedEOF
 }
 
 var (
-	ErrInvalidLengthLogproto = fmt.Errorf("proto: negative length found during unmarshaling")
-	ErrIntOverflowLogproto   = fmt.Errorf("proto: integer overflow")
+	ErrInvalidLengthLogproto        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowLogproto          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupLogproto = fmt.Errorf("proto: unexpected end of group")
 )
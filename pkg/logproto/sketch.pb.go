@@ -545,6 +545,152 @@ func (m *Labels) GetMetric() []*LabelPair {
 	return nil
 }
 
+type HyperLogLogMatrix struct {
+	Values []*HyperLogLogVector `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+}
+
+func (m *HyperLogLogMatrix) Reset()      { *m = HyperLogLogMatrix{} }
+func (*HyperLogLogMatrix) ProtoMessage() {}
+func (*HyperLogLogMatrix) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f9fd40e59b87ff3, []int{8}
+}
+func (m *HyperLogLogMatrix) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *HyperLogLogMatrix) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_HyperLogLogMatrix.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *HyperLogLogMatrix) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HyperLogLogMatrix.Merge(m, src)
+}
+func (m *HyperLogLogMatrix) XXX_Size() int {
+	return m.Size()
+}
+func (m *HyperLogLogMatrix) XXX_DiscardUnknown() {
+	xxx_messageInfo_HyperLogLogMatrix.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HyperLogLogMatrix proto.InternalMessageInfo
+
+func (m *HyperLogLogMatrix) GetValues() []*HyperLogLogVector {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+type HyperLogLogVector struct {
+	Samples []*HyperLogLogSample `protobuf:"bytes,1,rep,name=samples,proto3" json:"samples,omitempty"`
+}
+
+func (m *HyperLogLogVector) Reset()      { *m = HyperLogLogVector{} }
+func (*HyperLogLogVector) ProtoMessage() {}
+func (*HyperLogLogVector) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f9fd40e59b87ff3, []int{9}
+}
+func (m *HyperLogLogVector) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *HyperLogLogVector) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_HyperLogLogVector.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *HyperLogLogVector) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HyperLogLogVector.Merge(m, src)
+}
+func (m *HyperLogLogVector) XXX_Size() int {
+	return m.Size()
+}
+func (m *HyperLogLogVector) XXX_DiscardUnknown() {
+	xxx_messageInfo_HyperLogLogVector.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HyperLogLogVector proto.InternalMessageInfo
+
+func (m *HyperLogLogVector) GetSamples() []*HyperLogLogSample {
+	if m != nil {
+		return m.Samples
+	}
+	return nil
+}
+
+type HyperLogLogSample struct {
+	// sketch is the binary encoding of a HyperLogLog sketch.
+	Sketch      []byte       `protobuf:"bytes,1,opt,name=sketch,proto3" json:"sketch,omitempty"`
+	TimestampMs int64        `protobuf:"varint,2,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"`
+	Metric      []*LabelPair `protobuf:"bytes,3,rep,name=metric,proto3" json:"metric,omitempty"`
+}
+
+func (m *HyperLogLogSample) Reset()      { *m = HyperLogLogSample{} }
+func (*HyperLogLogSample) ProtoMessage() {}
+func (*HyperLogLogSample) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7f9fd40e59b87ff3, []int{10}
+}
+func (m *HyperLogLogSample) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *HyperLogLogSample) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_HyperLogLogSample.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *HyperLogLogSample) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HyperLogLogSample.Merge(m, src)
+}
+func (m *HyperLogLogSample) XXX_Size() int {
+	return m.Size()
+}
+func (m *HyperLogLogSample) XXX_DiscardUnknown() {
+	xxx_messageInfo_HyperLogLogSample.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HyperLogLogSample proto.InternalMessageInfo
+
+func (m *HyperLogLogSample) GetSketch() []byte {
+	if m != nil {
+		return m.Sketch
+	}
+	return nil
+}
+
+func (m *HyperLogLogSample) GetTimestampMs() int64 {
+	if m != nil {
+		return m.TimestampMs
+	}
+	return 0
+}
+
+func (m *HyperLogLogSample) GetMetric() []*LabelPair {
+	if m != nil {
+		return m.Metric
+	}
+	return nil
+}
+
 type TopK struct {
 	Cms         *CountMinSketch `protobuf:"bytes,1,opt,name=cms,proto3" json:"cms,omitempty"`
 	List        []*TopK_Pair    `protobuf:"bytes,2,rep,name=list,proto3" json:"list,omitempty"`
@@ -554,7 +700,7 @@ type TopK struct {
 func (m *TopK) Reset()      { *m = TopK{} }
 func (*TopK) ProtoMessage() {}
 func (*TopK) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7f9fd40e59b87ff3, []int{8}
+	return fileDescriptor_7f9fd40e59b87ff3, []int{11}
 }
 func (m *TopK) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -612,7 +758,7 @@ type TopK_Pair struct {
 func (m *TopK_Pair) Reset()      { *m = TopK_Pair{} }
 func (*TopK_Pair) ProtoMessage() {}
 func (*TopK_Pair) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7f9fd40e59b87ff3, []int{8, 0}
+	return fileDescriptor_7f9fd40e59b87ff3, []int{11, 0}
 }
 func (m *TopK_Pair) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -662,7 +808,7 @@ type TopKMatrix struct {
 func (m *TopKMatrix) Reset()      { *m = TopKMatrix{} }
 func (*TopKMatrix) ProtoMessage() {}
 func (*TopKMatrix) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7f9fd40e59b87ff3, []int{9}
+	return fileDescriptor_7f9fd40e59b87ff3, []int{12}
 }
 func (m *TopKMatrix) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -706,7 +852,7 @@ type TopKMatrix_Vector struct {
 func (m *TopKMatrix_Vector) Reset()      { *m = TopKMatrix_Vector{} }
 func (*TopKMatrix_Vector) ProtoMessage() {}
 func (*TopKMatrix_Vector) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7f9fd40e59b87ff3, []int{9, 0}
+	return fileDescriptor_7f9fd40e59b87ff3, []int{12, 0}
 }
 func (m *TopKMatrix_Vector) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -759,6 +905,9 @@ func init() {
 	proto.RegisterType((*CountMinSketch)(nil), "logproto.CountMinSketch")
 	proto.RegisterType((*CountMinSketchVector)(nil), "logproto.CountMinSketchVector")
 	proto.RegisterType((*Labels)(nil), "logproto.Labels")
+	proto.RegisterType((*HyperLogLogMatrix)(nil), "logproto.HyperLogLogMatrix")
+	proto.RegisterType((*HyperLogLogVector)(nil), "logproto.HyperLogLogVector")
+	proto.RegisterType((*HyperLogLogSample)(nil), "logproto.HyperLogLogSample")
 	proto.RegisterType((*TopK)(nil), "logproto.TopK")
 	proto.RegisterType((*TopK_Pair)(nil), "logproto.TopK.Pair")
 	proto.RegisterType((*TopKMatrix)(nil), "logproto.TopKMatrix")
@@ -768,50 +917,53 @@ func init() {
 func init() { proto.RegisterFile("pkg/logproto/sketch.proto", fileDescriptor_7f9fd40e59b87ff3) }
 
 var fileDescriptor_7f9fd40e59b87ff3 = []byte{
-	// 681 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0x41, 0x4f, 0x13, 0x41,
-	0x18, 0xdd, 0xb1, 0xb5, 0x2d, 0x5f, 0x81, 0xe0, 0x48, 0xcc, 0x5a, 0xcc, 0xa6, 0xee, 0x41, 0x08,
-	0xc6, 0xd6, 0x40, 0x24, 0x9c, 0xc1, 0x03, 0x89, 0xa2, 0x38, 0x10, 0x0f, 0x26, 0xc6, 0x2c, 0xdb,
-	0x61, 0x3b, 0xe9, 0xee, 0xce, 0x66, 0x67, 0x0a, 0xe8, 0xc9, 0x5f, 0x60, 0x8c, 0x17, 0xff, 0x82,
-	0x57, 0x7f, 0x82, 0x37, 0x8f, 0x1c, 0x39, 0x4a, 0xb9, 0x78, 0xe4, 0x27, 0x98, 0x9d, 0x99, 0x2d,
-	0xdd, 0x25, 0x8a, 0xa7, 0xce, 0xf7, 0xe6, 0x7d, 0x6f, 0xdf, 0xcc, 0xf7, 0x3a, 0x70, 0x37, 0x19,
-	0x04, 0xdd, 0x90, 0x07, 0x49, 0xca, 0x25, 0xef, 0x8a, 0x01, 0x95, 0x7e, 0xbf, 0xa3, 0x0a, 0xdc,
-	0xc8, 0xe1, 0xd6, 0x42, 0x81, 0x94, 0x2f, 0x34, 0xcd, 0x7d, 0x01, 0xf3, 0xaf, 0x86, 0x5e, 0x2c,
-	0x59, 0x48, 0x77, 0x55, 0xfb, 0xb6, 0x27, 0x53, 0x76, 0x8c, 0xd7, 0xa0, 0x76, 0xe8, 0x85, 0x43,
-	0x2a, 0x6c, 0xd4, 0xae, 0x2c, 0x35, 0x57, 0x9c, 0xce, 0xb8, 0xb1, 0xc8, 0x7f, 0x4d, 0x7d, 0xc9,
-	0x53, 0x62, 0xd8, 0xee, 0x4e, 0x59, 0x4f, 0xef, 0xe3, 0x75, 0xa8, 0x0b, 0x2f, 0x4a, 0xc2, 0xeb,
-	0x05, 0x77, 0x15, 0x8d, 0xe4, 0x74, 0xf7, 0x13, 0x2a, 0x4b, 0x6a, 0x06, 0x7e, 0x00, 0xe8, 0xc0,
-	0x46, 0x6d, 0xb4, 0xd4, 0x5c, 0xb1, 0xff, 0x26, 0x46, 0xd0, 0x01, 0xbe, 0x0f, 0xd3, 0x92, 0x45,
-	0x54, 0x48, 0x2f, 0x4a, 0xde, 0x45, 0xc2, 0xbe, 0xd1, 0x46, 0x4b, 0x15, 0xd2, 0x1c, 0x63, 0xdb,
-	0x02, 0x3f, 0x84, 0x5a, 0x44, 0x65, 0xca, 0x7c, 0xbb, 0xa2, 0xcc, 0xdd, 0xbe, 0xd4, 0x7b, 0xee,
-	0xed, 0xd3, 0x70, 0xc7, 0x63, 0x29, 0x31, 0x14, 0x37, 0x80, 0xd9, 0xe2, 0x47, 0xf0, 0x23, 0xa8,
-	0xcb, 0x1e, 0x0b, 0xa8, 0x90, 0xc6, 0xcf, 0xad, 0xcb, 0xfe, 0xbd, 0xa7, 0x6a, 0x63, 0xcb, 0x22,
-	0x39, 0x07, 0xdf, 0x83, 0x46, 0xaf, 0xa7, 0x87, 0xa5, 0xcc, 0x4c, 0x6f, 0x59, 0x64, 0x8c, 0x6c,
-	0x34, 0xa0, 0xa6, 0x57, 0xee, 0x0f, 0x04, 0x75, 0xd3, 0x8e, 0xe7, 0xa0, 0x12, 0xb1, 0x58, 0xc9,
-	0x23, 0x92, 0x2d, 0x15, 0xe2, 0x1d, 0x2b, 0x81, 0x0c, 0xf1, 0x8e, 0x71, 0x1b, 0x9a, 0x3e, 0x8f,
-	0x92, 0x94, 0x0a, 0xc1, 0x78, 0x6c, 0x57, 0xd4, 0xce, 0x24, 0x84, 0xd7, 0x61, 0x2a, 0x49, 0xb9,
-	0x4f, 0x85, 0xa0, 0x3d, 0xbb, 0xaa, 0x8e, 0xda, 0xba, 0x62, 0xb5, 0xb3, 0x49, 0x63, 0x99, 0x72,
-	0xd6, 0x23, 0x97, 0xe4, 0xd6, 0x1a, 0x34, 0x72, 0x18, 0x63, 0xa8, 0x46, 0xd4, 0xcb, 0xcd, 0xa8,
-	0x35, 0xbe, 0x03, 0xb5, 0x23, 0xca, 0x82, 0xbe, 0x34, 0x86, 0x4c, 0xe5, 0x7e, 0x80, 0xd9, 0x4d,
-	0x3e, 0x8c, 0xe5, 0x36, 0x8b, 0xcd, 0x65, 0xcd, 0xc3, 0xcd, 0x1e, 0x4d, 0x64, 0x5f, 0xb5, 0xcf,
-	0x10, 0x5d, 0x64, 0xe8, 0x11, 0xeb, 0x49, 0x7d, 0x21, 0x33, 0x44, 0x17, 0xb8, 0x05, 0x0d, 0x3f,
-	0xeb, 0xa6, 0xa9, 0x50, 0x93, 0x41, 0x64, 0x5c, 0x67, 0xa7, 0xed, 0xbf, 0x4f, 0x68, 0x1a, 0xf2,
-	0x20, 0xe4, 0x81, 0x5d, 0xcd, 0x2e, 0x92, 0x4c, 0x42, 0xee, 0x57, 0x04, 0xf3, 0xc5, 0x8f, 0x9b,
-	0x30, 0x96, 0x13, 0x81, 0xae, 0x26, 0xe2, 0x71, 0x3e, 0x05, 0x65, 0xa8, 0x90, 0xb0, 0xa2, 0x24,
-	0x31, 0x3c, 0xbc, 0x0c, 0x75, 0x1d, 0x10, 0x61, 0x42, 0x34, 0x57, 0x0a, 0x91, 0x20, 0x39, 0xc1,
-	0x7d, 0x02, 0x35, 0x0d, 0x4d, 0x24, 0x0f, 0x5d, 0x9f, 0xbc, 0xef, 0x08, 0xaa, 0x7b, 0x3c, 0x79,
-	0x86, 0x97, 0xa1, 0xe2, 0x1b, 0xdf, 0xff, 0xb2, 0x96, 0x91, 0xf0, 0x22, 0x54, 0x43, 0x26, 0xb2,
-	0xb9, 0x94, 0xf4, 0x33, 0xa5, 0x8e, 0xd2, 0x57, 0x84, 0xf2, 0x85, 0x56, 0xae, 0x5c, 0x68, 0x6b,
-	0x05, 0xaa, 0x19, 0x3f, 0x1b, 0x16, 0x3d, 0xa4, 0xb1, 0x4e, 0xfb, 0x14, 0xd1, 0x45, 0x86, 0xaa,
-	0xe1, 0x98, 0x04, 0xe8, 0xc2, 0xfd, 0x82, 0x00, 0xb2, 0x2f, 0x99, 0x77, 0x65, 0xb5, 0xf4, 0xae,
-	0x2c, 0x14, 0xfd, 0x68, 0x56, 0xa7, 0xf8, 0xa8, 0xb4, 0x5e, 0x42, 0xcd, 0x4c, 0xce, 0x85, 0xaa,
-	0xe4, 0xc9, 0xc0, 0x9c, 0x7c, 0xb6, 0xd8, 0x4c, 0xd4, 0xde, 0x7f, 0xfc, 0xdf, 0x37, 0xde, 0x9e,
-	0x9c, 0x39, 0xd6, 0xe9, 0x99, 0x63, 0x5d, 0x9c, 0x39, 0xe8, 0xe3, 0xc8, 0x41, 0xdf, 0x46, 0x0e,
-	0xfa, 0x39, 0x72, 0xd0, 0xc9, 0xc8, 0x41, 0xbf, 0x46, 0x0e, 0xfa, 0x3d, 0x72, 0xac, 0x8b, 0x91,
-	0x83, 0x3e, 0x9f, 0x3b, 0xd6, 0xc9, 0xb9, 0x63, 0x9d, 0x9e, 0x3b, 0xd6, 0x9b, 0xc5, 0x80, 0xc9,
-	0xfe, 0x70, 0xbf, 0xe3, 0xf3, 0xa8, 0x1b, 0xa4, 0xde, 0x81, 0x17, 0x7b, 0xdd, 0x90, 0x0f, 0x58,
-	0xf7, 0x70, 0xb5, 0x3b, 0xf9, 0xc6, 0xee, 0xd7, 0xd4, 0xcf, 0xea, 0x9f, 0x00, 0x00, 0x00, 0xff,
-	0xff, 0xab, 0x2f, 0x32, 0x74, 0x9f, 0x05, 0x00, 0x00,
+	// 733 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x55, 0x4f, 0x4f, 0xdb, 0x48,
+	0x14, 0xf7, 0x90, 0x6c, 0x12, 0x5e, 0x00, 0x91, 0xd9, 0x68, 0xe5, 0x0d, 0x2b, 0x2b, 0xeb, 0xc3,
+	0x82, 0x58, 0x6d, 0xb2, 0x02, 0x81, 0x38, 0xc3, 0x1e, 0xa2, 0x5d, 0xd8, 0xd2, 0x01, 0xf5, 0x50,
+	0xa9, 0xaa, 0x8c, 0x33, 0x38, 0xa3, 0xd8, 0x1e, 0xcb, 0x33, 0x01, 0xda, 0x5e, 0xfa, 0x09, 0xaa,
+	0xaa, 0x97, 0x7e, 0x85, 0x5e, 0xfb, 0x11, 0x7a, 0xeb, 0x91, 0x23, 0xc7, 0x12, 0x2e, 0x3d, 0xf2,
+	0x11, 0x2a, 0x8f, 0xc7, 0xf9, 0xe3, 0xd0, 0xd2, 0x43, 0x4f, 0x99, 0xf7, 0x7b, 0xbf, 0xf7, 0xe6,
+	0xcd, 0x7b, 0xbf, 0x17, 0xc3, 0xaf, 0x51, 0xdf, 0x6b, 0xfb, 0xdc, 0x8b, 0x62, 0x2e, 0x79, 0x5b,
+	0xf4, 0xa9, 0x74, 0x7b, 0x2d, 0x65, 0xe0, 0x4a, 0x06, 0x37, 0x56, 0xa6, 0x48, 0xd9, 0x21, 0xa5,
+	0xd9, 0xff, 0x43, 0xfd, 0xe1, 0xc0, 0x09, 0x25, 0xf3, 0xe9, 0x91, 0x0a, 0x3f, 0x70, 0x64, 0xcc,
+	0x2e, 0xf0, 0x36, 0x94, 0xce, 0x1c, 0x7f, 0x40, 0x85, 0x89, 0x9a, 0x85, 0xb5, 0xea, 0x86, 0xd5,
+	0x1a, 0x05, 0x4e, 0xf3, 0x1f, 0x51, 0x57, 0xf2, 0x98, 0x68, 0xb6, 0x7d, 0x08, 0xf5, 0xbb, 0xfc,
+	0x78, 0x07, 0xca, 0xc2, 0x09, 0x22, 0xff, 0xfe, 0x84, 0x47, 0x8a, 0x46, 0x32, 0xba, 0xfd, 0x0a,
+	0x41, 0xfd, 0x2e, 0x06, 0xfe, 0x03, 0xd0, 0xa9, 0x89, 0x9a, 0x68, 0xad, 0xba, 0x61, 0x7e, 0x2d,
+	0x19, 0x41, 0xa7, 0xf8, 0x77, 0x58, 0x90, 0x2c, 0xa0, 0x42, 0x3a, 0x41, 0xf4, 0x34, 0x10, 0xe6,
+	0x5c, 0x13, 0xad, 0x15, 0x48, 0x75, 0x84, 0x1d, 0x08, 0xfc, 0x27, 0x94, 0x02, 0x2a, 0x63, 0xe6,
+	0x9a, 0x05, 0x55, 0xdc, 0xcf, 0xe3, 0x7c, 0xfb, 0xce, 0x09, 0xf5, 0x0f, 0x1d, 0x16, 0x13, 0x4d,
+	0xb1, 0x3d, 0x58, 0x9a, 0xbe, 0x04, 0xff, 0x05, 0x65, 0xd9, 0x65, 0x1e, 0x15, 0x52, 0xd7, 0x53,
+	0x1b, 0xc7, 0x1f, 0xff, 0xa3, 0x1c, 0x1d, 0x83, 0x64, 0x1c, 0xfc, 0x1b, 0x54, 0xba, 0xdd, 0x74,
+	0x58, 0xaa, 0x98, 0x85, 0x8e, 0x41, 0x46, 0xc8, 0x6e, 0x05, 0x4a, 0xe9, 0xc9, 0xfe, 0x80, 0xa0,
+	0xac, 0xc3, 0xf1, 0x32, 0x14, 0x02, 0x16, 0xaa, 0xf4, 0x88, 0x24, 0x47, 0x85, 0x38, 0x17, 0xe6,
+	0x9c, 0x46, 0x9c, 0x0b, 0xdc, 0x84, 0xaa, 0xcb, 0x83, 0x28, 0xa6, 0x42, 0x30, 0x1e, 0x9a, 0x05,
+	0xe5, 0x99, 0x84, 0xf0, 0x0e, 0xcc, 0x47, 0x31, 0x77, 0xa9, 0x10, 0xb4, 0x6b, 0x16, 0xd5, 0x53,
+	0x1b, 0x33, 0xa5, 0xb6, 0xf6, 0x68, 0x28, 0x63, 0xce, 0xba, 0x64, 0x4c, 0x6e, 0x6c, 0x43, 0x25,
+	0x83, 0x31, 0x86, 0x62, 0x40, 0x9d, 0xac, 0x18, 0x75, 0xc6, 0xbf, 0x40, 0xe9, 0x9c, 0x32, 0xaf,
+	0x27, 0x75, 0x41, 0xda, 0xb2, 0x9f, 0xc3, 0xd2, 0x1e, 0x1f, 0x84, 0xf2, 0x80, 0x85, 0xba, 0x59,
+	0x75, 0xf8, 0xa9, 0x4b, 0x23, 0xd9, 0x53, 0xe1, 0x8b, 0x24, 0x35, 0x12, 0xf4, 0x9c, 0x75, 0x65,
+	0xda, 0x90, 0x45, 0x92, 0x1a, 0xb8, 0x01, 0x15, 0x37, 0x89, 0xa6, 0xb1, 0x50, 0x93, 0x41, 0x64,
+	0x64, 0x27, 0xaf, 0xed, 0x3d, 0x8b, 0x68, 0xec, 0x73, 0xcf, 0xe7, 0x9e, 0x59, 0x4c, 0x1a, 0x49,
+	0x26, 0x21, 0xfb, 0x2d, 0x82, 0xfa, 0xf4, 0xe5, 0x5a, 0x8c, 0x79, 0x45, 0xa0, 0x59, 0x45, 0xfc,
+	0x9d, 0x4d, 0xc1, 0x9c, 0xcb, 0x2b, 0x6c, 0x3a, 0x25, 0xd1, 0x3c, 0xbc, 0x0e, 0xe5, 0x54, 0x20,
+	0x42, 0x8b, 0x68, 0x39, 0x27, 0x22, 0x41, 0x32, 0x82, 0xbd, 0x05, 0xa5, 0x14, 0x9a, 0x50, 0x1e,
+	0xba, 0x5f, 0x79, 0x1d, 0xa8, 0x75, 0x92, 0xf7, 0xed, 0x73, 0x6f, 0x9f, 0x7b, 0x7a, 0x53, 0x37,
+	0x73, 0x9b, 0xba, 0x32, 0xce, 0x30, 0x41, 0xce, 0xad, 0xe9, 0xbf, 0x50, 0x9b, 0x71, 0xe2, 0xad,
+	0xfc, 0x8e, 0xde, 0x9d, 0x2a, 0xbf, 0xa0, 0x2f, 0xa0, 0x36, 0xe3, 0x4d, 0xf4, 0xa0, 0xfb, 0x87,
+	0xd4, 0x60, 0xb4, 0xf5, 0xc3, 0x97, 0xf1, 0x3d, 0x82, 0xe2, 0x31, 0x8f, 0xfe, 0xc3, 0xeb, 0x50,
+	0x70, 0xf5, 0x28, 0xbf, 0x35, 0xad, 0x84, 0x84, 0x57, 0xa1, 0xe8, 0x33, 0x91, 0x48, 0x35, 0x97,
+	0x3f, 0xc9, 0xd4, 0x52, 0xf9, 0x15, 0x21, 0xaf, 0xb1, 0xc2, 0x8c, 0xc6, 0x1a, 0x1b, 0x50, 0x4c,
+	0xf8, 0x89, 0x7e, 0xe9, 0x19, 0x0d, 0xd3, 0x3f, 0x80, 0x79, 0x92, 0x1a, 0x09, 0xaa, 0xf4, 0xaa,
+	0x97, 0x22, 0x35, 0xec, 0x37, 0x08, 0x20, 0xb9, 0xe9, 0xfe, 0x01, 0x8e, 0x59, 0xad, 0xe9, 0x01,
+	0x36, 0x1e, 0x40, 0x49, 0x4f, 0xcd, 0x86, 0xa2, 0xe4, 0x51, 0x5f, 0xbf, 0x7c, 0x69, 0x3a, 0x98,
+	0x28, 0xdf, 0x77, 0x74, 0x7d, 0xf7, 0xc9, 0xe5, 0xb5, 0x65, 0x5c, 0x5d, 0x5b, 0xc6, 0xed, 0xb5,
+	0x85, 0x5e, 0x0e, 0x2d, 0xf4, 0x6e, 0x68, 0xa1, 0x8f, 0x43, 0x0b, 0x5d, 0x0e, 0x2d, 0xf4, 0x69,
+	0x68, 0xa1, 0xcf, 0x43, 0xcb, 0xb8, 0x1d, 0x5a, 0xe8, 0xf5, 0x8d, 0x65, 0x5c, 0xde, 0x58, 0xc6,
+	0xd5, 0x8d, 0x65, 0x3c, 0x5e, 0xf5, 0x98, 0xec, 0x0d, 0x4e, 0x5a, 0x2e, 0x0f, 0xda, 0x5e, 0xec,
+	0x9c, 0x3a, 0xa1, 0xd3, 0xf6, 0x79, 0x9f, 0xb5, 0xcf, 0x36, 0xdb, 0x93, 0x9f, 0x9d, 0x93, 0x92,
+	0xfa, 0xd9, 0xfc, 0x32, 0x00, 0x25, 0x76, 0xbe, 0x8d, 0xb2, 0x06, 0x00, 0x00,
 }
 
 func (this *QuantileSketchMatrix) Equal(that interface{}) bool {
@@ -1152,6 +1304,99 @@ func (this *Labels) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *HyperLogLogMatrix) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*HyperLogLogMatrix)
+	if !ok {
+		that2, ok := that.(HyperLogLogMatrix)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Values) != len(that1.Values) {
+		return false
+	}
+	for i := range this.Values {
+		if !this.Values[i].Equal(that1.Values[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *HyperLogLogVector) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*HyperLogLogVector)
+	if !ok {
+		that2, ok := that.(HyperLogLogVector)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Samples) != len(that1.Samples) {
+		return false
+	}
+	for i := range this.Samples {
+		if !this.Samples[i].Equal(that1.Samples[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *HyperLogLogSample) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*HyperLogLogSample)
+	if !ok {
+		that2, ok := that.(HyperLogLogSample)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !bytes.Equal(this.Sketch, that1.Sketch) {
+		return false
+	}
+	if this.TimestampMs != that1.TimestampMs {
+		return false
+	}
+	if len(this.Metric) != len(that1.Metric) {
+		return false
+	}
+	for i := range this.Metric {
+		if !this.Metric[i].Equal(that1.Metric[i]) {
+			return false
+		}
+	}
+	return true
+}
 func (this *TopK) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
@@ -1405,6 +1650,44 @@ func (this *Labels) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
+func (this *HyperLogLogMatrix) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&logproto.HyperLogLogMatrix{")
+	if this.Values != nil {
+		s = append(s, "Values: "+fmt.Sprintf("%#v", this.Values)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *HyperLogLogVector) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&logproto.HyperLogLogVector{")
+	if this.Samples != nil {
+		s = append(s, "Samples: "+fmt.Sprintf("%#v", this.Samples)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *HyperLogLogSample) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&logproto.HyperLogLogSample{")
+	s = append(s, "Sketch: "+fmt.Sprintf("%#v", this.Sketch)+",\n")
+	s = append(s, "TimestampMs: "+fmt.Sprintf("%#v", this.TimestampMs)+",\n")
+	if this.Metric != nil {
+		s = append(s, "Metric: "+fmt.Sprintf("%#v", this.Metric)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func (this *TopK) GoString() string {
 	if this == nil {
 		return "nil"
@@ -1891,7 +2174,7 @@ func (m *Labels) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *TopK) Marshal() (dAtA []byte, err error) {
+func (m *HyperLogLogMatrix) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1901,27 +2184,20 @@ func (m *TopK) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *TopK) MarshalTo(dAtA []byte) (int, error) {
+func (m *HyperLogLogMatrix) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *TopK) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *HyperLogLogMatrix) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Hyperloglog) > 0 {
-		i -= len(m.Hyperloglog)
-		copy(dAtA[i:], m.Hyperloglog)
-		i = encodeVarintSketch(dAtA, i, uint64(len(m.Hyperloglog)))
-		i--
-		dAtA[i] = 0x1a
-	}
-	if len(m.List) > 0 {
-		for iNdEx := len(m.List) - 1; iNdEx >= 0; iNdEx-- {
+	if len(m.Values) > 0 {
+		for iNdEx := len(m.Values) - 1; iNdEx >= 0; iNdEx-- {
 			{
-				size, err := m.List[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				size, err := m.Values[iNdEx].MarshalToSizedBuffer(dAtA[:i])
 				if err != nil {
 					return 0, err
 				}
@@ -1929,25 +2205,13 @@ func (m *TopK) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 				i = encodeVarintSketch(dAtA, i, uint64(size))
 			}
 			i--
-			dAtA[i] = 0x12
-		}
-	}
-	if m.Cms != nil {
-		{
-			size, err := m.Cms.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintSketch(dAtA, i, uint64(size))
+			dAtA[i] = 0xa
 		}
-		i--
-		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *TopK_Pair) Marshal() (dAtA []byte, err error) {
+func (m *HyperLogLogVector) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1957,28 +2221,170 @@ func (m *TopK_Pair) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *TopK_Pair) MarshalTo(dAtA []byte) (int, error) {
+func (m *HyperLogLogVector) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *TopK_Pair) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *HyperLogLogVector) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.Count != 0 {
-		i -= 8
-		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Count))))
-		i--
-		dAtA[i] = 0x11
-	}
-	if len(m.Event) > 0 {
-		i -= len(m.Event)
-		copy(dAtA[i:], m.Event)
-		i = encodeVarintSketch(dAtA, i, uint64(len(m.Event)))
-		i--
-		dAtA[i] = 0xa
+	if len(m.Samples) > 0 {
+		for iNdEx := len(m.Samples) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Samples[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSketch(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *HyperLogLogSample) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *HyperLogLogSample) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *HyperLogLogSample) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Metric) > 0 {
+		for iNdEx := len(m.Metric) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Metric[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSketch(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.TimestampMs != 0 {
+		i = encodeVarintSketch(dAtA, i, uint64(m.TimestampMs))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Sketch) > 0 {
+		i -= len(m.Sketch)
+		copy(dAtA[i:], m.Sketch)
+		i = encodeVarintSketch(dAtA, i, uint64(len(m.Sketch)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TopK) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TopK) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TopK) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Hyperloglog) > 0 {
+		i -= len(m.Hyperloglog)
+		copy(dAtA[i:], m.Hyperloglog)
+		i = encodeVarintSketch(dAtA, i, uint64(len(m.Hyperloglog)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.List) > 0 {
+		for iNdEx := len(m.List) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.List[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSketch(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Cms != nil {
+		{
+			size, err := m.Cms.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintSketch(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TopK_Pair) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TopK_Pair) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TopK_Pair) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Count != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Count))))
+		i--
+		dAtA[i] = 0x11
+	}
+	if len(m.Event) > 0 {
+		i -= len(m.Event)
+		copy(dAtA[i:], m.Event)
+		i = encodeVarintSketch(dAtA, i, uint64(len(m.Event)))
+		i--
+		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
@@ -2257,6 +2663,58 @@ func (m *Labels) Size() (n int) {
 	return n
 }
 
+func (m *HyperLogLogMatrix) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Values) > 0 {
+		for _, e := range m.Values {
+			l = e.Size()
+			n += 1 + l + sovSketch(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *HyperLogLogVector) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Samples) > 0 {
+		for _, e := range m.Samples {
+			l = e.Size()
+			n += 1 + l + sovSketch(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *HyperLogLogSample) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Sketch)
+	if l > 0 {
+		n += 1 + l + sovSketch(uint64(l))
+	}
+	if m.TimestampMs != 0 {
+		n += 1 + sovSketch(uint64(m.TimestampMs))
+	}
+	if len(m.Metric) > 0 {
+		for _, e := range m.Metric {
+			l = e.Size()
+			n += 1 + l + sovSketch(uint64(l))
+		}
+	}
+	return n
+}
+
 func (m *TopK) Size() (n int) {
 	if m == nil {
 		return 0
@@ -2484,6 +2942,53 @@ func (this *Labels) String() string {
 	}, "")
 	return s
 }
+func (this *HyperLogLogMatrix) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForValues := "[]*HyperLogLogVector{"
+	for _, f := range this.Values {
+		repeatedStringForValues += strings.Replace(f.String(), "HyperLogLogVector", "HyperLogLogVector", 1) + ","
+	}
+	repeatedStringForValues += "}"
+	s := strings.Join([]string{`&HyperLogLogMatrix{`,
+		`Values:` + repeatedStringForValues + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *HyperLogLogVector) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForSamples := "[]*HyperLogLogSample{"
+	for _, f := range this.Samples {
+		repeatedStringForSamples += strings.Replace(f.String(), "HyperLogLogSample", "HyperLogLogSample", 1) + ","
+	}
+	repeatedStringForSamples += "}"
+	s := strings.Join([]string{`&HyperLogLogVector{`,
+		`Samples:` + repeatedStringForSamples + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *HyperLogLogSample) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForMetric := "[]*LabelPair{"
+	for _, f := range this.Metric {
+		repeatedStringForMetric += strings.Replace(fmt.Sprintf("%v", f), "LabelPair", "LabelPair", 1) + ","
+	}
+	repeatedStringForMetric += "}"
+	s := strings.Join([]string{`&HyperLogLogSample{`,
+		`Sketch:` + fmt.Sprintf("%v", this.Sketch) + `,`,
+		`TimestampMs:` + fmt.Sprintf("%v", this.TimestampMs) + `,`,
+		`Metric:` + repeatedStringForMetric + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *TopK) String() string {
 	if this == nil {
 		return "nil"
@@ -3586,6 +4091,320 @@ func (m *Labels) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *HyperLogLogMatrix) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSketch
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HyperLogLogMatrix: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HyperLogLogMatrix: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Values", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSketch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSketch
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSketch
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Values = append(m.Values, &HyperLogLogVector{})
+			if err := m.Values[len(m.Values)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSketch(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSketch
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSketch
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *HyperLogLogVector) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSketch
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HyperLogLogVector: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HyperLogLogVector: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Samples", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSketch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSketch
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSketch
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Samples = append(m.Samples, &HyperLogLogSample{})
+			if err := m.Samples[len(m.Samples)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSketch(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSketch
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSketch
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *HyperLogLogSample) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSketch
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HyperLogLogSample: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HyperLogLogSample: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sketch", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSketch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthSketch
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSketch
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sketch = append(m.Sketch[:0], dAtA[iNdEx:postIndex]...)
+			if m.Sketch == nil {
+				m.Sketch = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimestampMs", wireType)
+			}
+			m.TimestampMs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSketch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TimestampMs |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metric", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSketch
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSketch
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSketch
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Metric = append(m.Metric, &LabelPair{})
+			if err := m.Metric[len(m.Metric)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSketch(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthSketch
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthSketch
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *TopK) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
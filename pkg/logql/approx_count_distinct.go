@@ -0,0 +1,463 @@
+package logql
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/axiomhq/hyperloglog"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	promql_parser "github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/loki/v3/pkg/iter"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
+)
+
+const (
+	HyperLogLogMatrixType = "HyperLogLogMatrix"
+)
+
+// HyperLogLogSample holds the HyperLogLog sketch of the distinct values of a
+// series at a given step. Sketches of the same series computed on different
+// shards are merged to count the distinct values of the whole series.
+type HyperLogLogSample struct {
+	T int64
+	F *hyperloglog.Sketch
+
+	Metric labels.Labels
+}
+
+type (
+	HyperLogLogVector []HyperLogLogSample
+	HyperLogLogMatrix []HyperLogLogVector
+)
+
+func (s HyperLogLogSample) ToProto() (*logproto.HyperLogLogSample, error) {
+	sketch, err := s.F.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	metric := make([]*logproto.LabelPair, 0, s.Metric.Len())
+	s.Metric.Range(func(l labels.Label) {
+		metric = append(metric, &logproto.LabelPair{Name: l.Name, Value: l.Value})
+	})
+
+	return &logproto.HyperLogLogSample{
+		Sketch:      sketch,
+		TimestampMs: s.T,
+		Metric:      metric,
+	}, nil
+}
+
+func hyperLogLogSampleFromProto(p *logproto.HyperLogLogSample) (HyperLogLogSample, error) {
+	sketch := hyperloglog.New()
+	if err := sketch.UnmarshalBinary(p.Sketch); err != nil {
+		return HyperLogLogSample{}, err
+	}
+
+	b := labels.NewScratchBuilder(len(p.Metric))
+	for _, l := range p.Metric {
+		b.Add(l.Name, l.Value)
+	}
+
+	return HyperLogLogSample{
+		T:      p.TimestampMs,
+		F:      sketch,
+		Metric: b.Labels(),
+	}, nil
+}
+
+func (v HyperLogLogVector) Merge(right HyperLogLogVector) (HyperLogLogVector, error) {
+	// labels hash to vector index map
+	groups := streamHashPool.Get().(map[uint64]int)
+	defer func() {
+		clear(groups)
+		streamHashPool.Put(groups)
+	}()
+	for i, sample := range v {
+		groups[labels.StableHash(sample.Metric)] = i
+	}
+
+	for _, sample := range right {
+		i, ok := groups[labels.StableHash(sample.Metric)]
+		if !ok {
+			v = append(v, sample)
+			continue
+		}
+
+		if err := v[i].F.Merge(sample.F); err != nil {
+			return v, err
+		}
+	}
+
+	return v, nil
+}
+
+func (HyperLogLogVector) SampleVector() promql.Vector {
+	return promql.Vector{}
+}
+
+func (HyperLogLogVector) QuantileSketchVec() ProbabilisticQuantileVector {
+	return ProbabilisticQuantileVector{}
+}
+
+func (HyperLogLogVector) CountMinSketchVec() CountMinSketchVector {
+	return CountMinSketchVector{}
+}
+
+func (v HyperLogLogVector) HyperLogLogVec() HyperLogLogVector {
+	return v
+}
+
+func (v HyperLogLogVector) ToProto() (*logproto.HyperLogLogVector, error) {
+	samples := make([]*logproto.HyperLogLogSample, len(v))
+	for i, sample := range v {
+		s, err := sample.ToProto()
+		if err != nil {
+			return nil, err
+		}
+		samples[i] = s
+	}
+	return &logproto.HyperLogLogVector{Samples: samples}, nil
+}
+
+func HyperLogLogVectorFromProto(p *logproto.HyperLogLogVector) (HyperLogLogVector, error) {
+	out := make(HyperLogLogVector, len(p.Samples))
+	for i, sample := range p.Samples {
+		s, err := hyperLogLogSampleFromProto(sample)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = s
+	}
+	return out, nil
+}
+
+func (HyperLogLogMatrix) String() string {
+	return "HyperLogLogMatrix()"
+}
+
+func (HyperLogLogMatrix) Type() promql_parser.ValueType { return HyperLogLogMatrixType }
+
+func (m HyperLogLogMatrix) Merge(right HyperLogLogMatrix) (HyperLogLogMatrix, error) {
+	if len(m) != len(right) {
+		return nil, fmt.Errorf("failed to merge hyperloglog matrix: lengths differ %d!=%d", len(m), len(right))
+	}
+	var err error
+	for i, vec := range m {
+		m[i], err = vec.Merge(right[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge hyperloglog matrix: %w", err)
+		}
+	}
+	return m, nil
+}
+
+func (m HyperLogLogMatrix) ToProto() (*logproto.HyperLogLogMatrix, error) {
+	values := make([]*logproto.HyperLogLogVector, len(m))
+	for i, vec := range m {
+		v, err := vec.ToProto()
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return &logproto.HyperLogLogMatrix{Values: values}, nil
+}
+
+func HyperLogLogMatrixFromProto(p *logproto.HyperLogLogMatrix) (HyperLogLogMatrix, error) {
+	out := make(HyperLogLogMatrix, len(p.Values))
+	for i, v := range p.Values {
+		vec, err := HyperLogLogVectorFromProto(v)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = vec
+	}
+	return out, nil
+}
+
+// insertValue adds a sample value to a sketch. Values of distinct counts are
+// either hashes of label values or numbers converted by the unwrap function.
+func insertValue(sketch *hyperloglog.Sketch, buf []byte, v float64) {
+	binary.LittleEndian.PutUint64(buf, math.Float64bits(v))
+	sketch.Insert(buf)
+}
+
+func newHyperLogLogIterator(
+	it iter.PeekingSampleIterator,
+	selRange, step, start, end, offset int64,
+) RangeVectorIterator {
+	// forces at least one step.
+	if step == 0 {
+		step = 1
+	}
+	if offset != 0 {
+		start = start - offset
+		end = end - offset
+	}
+
+	inner := &batchRangeVectorIterator{
+		iter:     it,
+		step:     step,
+		end:      end,
+		selRange: selRange,
+		metrics:  map[string]labels.Labels{},
+		window:   map[string]*promql.Series{},
+		agg:      nil,
+		current:  start - step, // first loop iteration will set it to start
+		offset:   offset,
+	}
+	return &hyperLogLogBatchRangeVectorIterator{
+		batchRangeVectorIterator: inner,
+		buf:                      make([]byte, 8),
+	}
+}
+
+type hyperLogLogBatchRangeVectorIterator struct {
+	*batchRangeVectorIterator
+	buf []byte
+}
+
+func (r *hyperLogLogBatchRangeVectorIterator) At() (int64, StepResult) {
+	at := make(HyperLogLogVector, 0, len(r.window))
+	// convert ts from nano to milli seconds as the iterator work with nanoseconds
+	ts := r.current/1e+6 + r.offset/1e+6
+	for _, series := range r.window {
+		sketch := hyperloglog.New()
+		for _, p := range series.Floats {
+			insertValue(sketch, r.buf, p.F)
+		}
+		at = append(at, HyperLogLogSample{
+			T:      ts,
+			F:      sketch,
+			Metric: series.Metric,
+		})
+	}
+	return ts, at
+}
+
+// HyperLogLogStepEvaluator evaluates the sketches of the distinct values of
+// each series in a range.
+type HyperLogLogStepEvaluator struct {
+	iter RangeVectorIterator
+
+	err error
+}
+
+func (e *HyperLogLogStepEvaluator) Next() (bool, int64, StepResult) {
+	next := e.iter.Next()
+	if !next {
+		return false, 0, HyperLogLogVector{}
+	}
+	ts, r := e.iter.At()
+	vec := r.HyperLogLogVec()
+	for _, s := range vec {
+		// Errors are not allowed in metrics unless they've been specifically requested.
+		if s.Metric.Has(logqlmodel.ErrorLabel) && s.Metric.Get(logqlmodel.PreserveErrorLabel) != "true" {
+			e.err = logqlmodel.NewPipelineErr(s.Metric)
+			return false, 0, HyperLogLogVector{}
+		}
+	}
+	return true, ts, vec
+}
+
+func (e *HyperLogLogStepEvaluator) Close() error { return e.iter.Close() }
+
+func (e *HyperLogLogStepEvaluator) Error() error {
+	if e.err != nil {
+		return e.err
+	}
+	return e.iter.Error()
+}
+
+func (e *HyperLogLogStepEvaluator) Explain(parent Node) {
+	parent.Child("HyperLogLog")
+}
+
+// hyperLogLogVectorAggEvaluator counts the distinct series of each group of
+// a vector in a HyperLogLog sketch.
+type hyperLogLogVectorAggEvaluator struct {
+	nextEvaluator StepEvaluator
+	expr          *syntax.VectorAggregationExpr
+	buf           []byte
+	lb            *labels.Builder
+}
+
+func newHyperLogLogVectorAggEvaluator(nextEvaluator StepEvaluator, expr *syntax.VectorAggregationExpr) *hyperLogLogVectorAggEvaluator {
+	return &hyperLogLogVectorAggEvaluator{
+		nextEvaluator: nextEvaluator,
+		expr:          expr,
+		buf:           make([]byte, 0, 1024),
+		lb:            labels.NewBuilder(labels.EmptyLabels()),
+	}
+}
+
+func (e *hyperLogLogVectorAggEvaluator) Next() (bool, int64, StepResult) {
+	next, ts, r := e.nextEvaluator.Next()
+	if !next {
+		return false, 0, HyperLogLogVector{}
+	}
+
+	groups := map[uint64]int{}
+	result := HyperLogLogVector{}
+	for _, s := range r.SampleVector() {
+		var groupingKey uint64
+		if e.expr.Grouping.Without {
+			groupingKey, e.buf = s.Metric.HashWithoutLabels(e.buf, e.expr.Grouping.Groups...)
+		} else {
+			groupingKey, e.buf = s.Metric.HashForLabels(e.buf, e.expr.Grouping.Groups...)
+		}
+		i, ok := groups[groupingKey]
+		if !ok {
+			if e.expr.Grouping.Without {
+				e.lb.Reset(s.Metric)
+				e.lb.Del(e.expr.Grouping.Groups...)
+				e.lb.Del(labels.MetricName)
+			} else {
+				e.lb.Reset(labels.EmptyLabels())
+				for _, n := range e.expr.Grouping.Groups {
+					if v := s.Metric.Get(n); v != "" {
+						e.lb.Set(n, v)
+					}
+				}
+			}
+			i = len(result)
+			groups[groupingKey] = i
+			result = append(result, HyperLogLogSample{
+				T:      ts,
+				F:      hyperloglog.New(),
+				Metric: e.lb.Labels(),
+			})
+		}
+		e.buf = stableBytes(e.buf, s.Metric)
+		result[i].F.Insert(e.buf)
+	}
+	return next, ts, result
+}
+
+func (e *hyperLogLogVectorAggEvaluator) Close() error {
+	return e.nextEvaluator.Close()
+}
+
+func (e *hyperLogLogVectorAggEvaluator) Error() error {
+	return e.nextEvaluator.Error()
+}
+
+func (e *hyperLogLogVectorAggEvaluator) Explain(parent Node) {
+	b := parent.Child("HyperLogLogVectorAgg")
+	e.nextEvaluator.Explain(b)
+}
+
+// JoinHyperLogLogVector joins the results from stepEvaluator into a HyperLogLogMatrix.
+func JoinHyperLogLogVector(next bool, r StepResult, stepEvaluator StepEvaluator, params Params) (promql_parser.Value, error) {
+	vec := r.HyperLogLogVec()
+	if stepEvaluator.Error() != nil {
+		return nil, stepEvaluator.Error()
+	}
+
+	if GetRangeType(params) == InstantType {
+		return HyperLogLogMatrix{vec}, nil
+	}
+
+	stepCount := int(math.Ceil(float64(params.End().Sub(params.Start()).Nanoseconds()) / float64(params.Step().Nanoseconds())))
+	if stepCount <= 0 {
+		stepCount = 1
+	}
+
+	result := make(HyperLogLogMatrix, 0, stepCount)
+
+	for next {
+		result = append(result, vec)
+		next, _, r = stepEvaluator.Next()
+		vec = r.HyperLogLogVec()
+		if stepEvaluator.Error() != nil {
+			return nil, stepEvaluator.Error()
+		}
+	}
+
+	return result, stepEvaluator.Error()
+}
+
+// HyperLogLogMatrixStepEvaluator steps through a matrix of HyperLogLog sketch
+// vectors.
+type HyperLogLogMatrixStepEvaluator struct {
+	end, ts time.Time
+	step    time.Duration
+	m       HyperLogLogMatrix
+}
+
+func NewHyperLogLogMatrixStepEvaluator(m HyperLogLogMatrix, params Params) *HyperLogLogMatrixStepEvaluator {
+	step := params.Step()
+	return &HyperLogLogMatrixStepEvaluator{
+		end:  params.End(),
+		ts:   params.Start().Add(-step), // will be corrected on first Next() call
+		step: step,
+		m:    m,
+	}
+}
+
+func (m *HyperLogLogMatrixStepEvaluator) Next() (bool, int64, StepResult) {
+	m.ts = m.ts.Add(m.step)
+	if m.ts.After(m.end) || len(m.m) == 0 {
+		return false, 0, nil
+	}
+
+	vec := m.m[0]
+	m.m = m.m[1:]
+
+	return true, m.ts.UnixNano() / int64(time.Millisecond), vec
+}
+
+func (*HyperLogLogMatrixStepEvaluator) Close() error { return nil }
+
+func (*HyperLogLogMatrixStepEvaluator) Error() error { return nil }
+
+func (*HyperLogLogMatrixStepEvaluator) Explain(parent Node) {
+	parent.Child("HyperLogLogMatrix")
+}
+
+// HyperLogLogVectorStepEvaluator evaluates HyperLogLog sketches into the
+// estimated number of distinct values.
+type HyperLogLogVectorStepEvaluator struct {
+	inner StepEvaluator
+}
+
+var _ StepEvaluator = NewHyperLogLogVectorStepEvaluator(nil)
+
+func NewHyperLogLogVectorStepEvaluator(inner StepEvaluator) *HyperLogLogVectorStepEvaluator {
+	return &HyperLogLogVectorStepEvaluator{inner: inner}
+}
+
+func (e *HyperLogLogVectorStepEvaluator) Next() (bool, int64, StepResult) {
+	ok, ts, r := e.inner.Next()
+	if !ok {
+		return false, 0, SampleVector{}
+	}
+	hllVec := r.HyperLogLogVec()
+
+	vec := make(promql.Vector, len(hllVec))
+	for i, s := range hllVec {
+		vec[i] = promql.Sample{
+			T:      s.T,
+			F:      float64(s.F.Estimate()),
+			Metric: s.Metric,
+		}
+	}
+
+	return ok, ts, SampleVector(vec)
+}
+
+func (e *HyperLogLogVectorStepEvaluator) Close() error { return e.inner.Close() }
+
+func (e *HyperLogLogVectorStepEvaluator) Error() error { return e.inner.Error() }
+
+func (e *HyperLogLogVectorStepEvaluator) Explain(parent Node) {
+	b := parent.Child("HyperLogLogVector")
+	e.inner.Explain(b)
+}
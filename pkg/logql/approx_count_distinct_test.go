@@ -0,0 +1,61 @@
+package logql
+
+import (
+	"testing"
+
+	"github.com/axiomhq/hyperloglog"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHyperLogLogMatrixSerialization(t *testing.T) {
+	sketch := hyperloglog.New()
+	buf := make([]byte, 8)
+	for i := 0; i < 100; i++ {
+		insertValue(sketch, buf, float64(i))
+	}
+
+	matrix := HyperLogLogMatrix{
+		HyperLogLogVector{
+			{T: 0, F: sketch, Metric: labels.FromStrings("foo", "bar")},
+		},
+	}
+
+	proto, err := matrix.ToProto()
+	require.NoError(t, err)
+	require.Len(t, proto.Values, 1)
+	require.Len(t, proto.Values[0].Samples, 1)
+
+	actual, err := HyperLogLogMatrixFromProto(proto)
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	require.Len(t, actual[0], 1)
+	require.Equal(t, matrix[0][0].Metric, actual[0][0].Metric)
+	require.Equal(t, sketch.Estimate(), actual[0][0].F.Estimate())
+}
+
+func TestHyperLogLogVectorMerge(t *testing.T) {
+	buf := make([]byte, 8)
+	newSample := func(from, to int, metric labels.Labels) HyperLogLogSample {
+		sketch := hyperloglog.New()
+		for i := from; i < to; i++ {
+			insertValue(sketch, buf, float64(i))
+		}
+		return HyperLogLogSample{F: sketch, Metric: metric}
+	}
+
+	foo, bar := labels.FromStrings("app", "foo"), labels.FromStrings("app", "bar")
+	left := HyperLogLogVector{newSample(0, 100, foo)}
+	right := HyperLogLogVector{newSample(50, 150, foo), newSample(0, 10, bar)}
+
+	merged, err := left.Merge(right)
+	require.NoError(t, err)
+	require.Len(t, merged, 2)
+	// Values seen on both sides are only counted once.
+	require.InEpsilon(t, 150, float64(merged[0].F.Estimate()), 0.02)
+	require.Equal(t, bar, merged[1].Metric)
+	require.Equal(t, uint64(10), merged[1].F.Estimate())
+
+	_, err = HyperLogLogMatrix{left}.Merge(HyperLogLogMatrix{left, right})
+	require.Error(t, err)
+}
@@ -432,6 +432,65 @@ func (e *CountMinSketchEvalExpr) Walk(f syntax.WalkFn) {
 	}
 }
 
+// HyperLogLogEvalExpr merges the HyperLogLog sketches of its downstreams
+// and evaluates them to the estimated number of distinct values.
+type HyperLogLogEvalExpr struct {
+	syntax.SampleExpr
+	downstreams []DownstreamSampleExpr
+}
+
+func (e HyperLogLogEvalExpr) String() string {
+	var sb strings.Builder
+	for i, d := range e.downstreams {
+		if i >= defaultMaxDepth {
+			break
+		}
+
+		if i > 0 {
+			sb.WriteString(" ++ ")
+		}
+
+		sb.WriteString(d.String())
+	}
+	return fmt.Sprintf("HyperLogLogEval<%s>", sb.String())
+}
+
+func (e *HyperLogLogEvalExpr) Walk(f syntax.WalkFn) {
+	if !f(e) {
+		return
+	}
+	if e.SampleExpr != nil {
+		e.SampleExpr.Walk(f)
+	}
+	for _, d := range e.downstreams {
+		d.Walk(f)
+	}
+}
+
+// HistogramCumulateExpr merges the histogram buckets of its downstreams,
+// returned by __histogram_buckets_over_time__ for each shard or split range,
+// and makes them cumulative.
+type HistogramCumulateExpr struct {
+	syntax.SampleExpr
+	buckets syntax.SampleExpr
+}
+
+func (e HistogramCumulateExpr) String() string {
+	return fmt.Sprintf("HistogramCumulate<%s>", e.buckets.String())
+}
+
+func (e *HistogramCumulateExpr) Walk(f syntax.WalkFn) {
+	if !f(e) {
+		return
+	}
+	if e.SampleExpr != nil {
+		e.SampleExpr.Walk(f)
+	}
+	if e.buckets != nil {
+		e.buckets.Walk(f)
+	}
+}
+
 type Downstreamable interface {
 	Downstreamer(context.Context) Downstreamer
 }
@@ -510,7 +569,7 @@ func (errorQuerier) SelectSamples(_ context.Context, _ SelectSampleParams) (iter
 func NewDownstreamEvaluator(downstreamer Downstreamer) *DownstreamEvaluator {
 	return &DownstreamEvaluator{
 		Downstreamer:     downstreamer,
-		defaultEvaluator: NewDefaultEvaluator(&errorQuerier{}, 0, 0),
+		defaultEvaluator: NewDefaultEvaluator(&errorQuerier{}, 0, 0, 0),
 	}
 }
 
@@ -707,6 +766,38 @@ func (ev *DownstreamEvaluator) NewStepEvaluator(
 			return nil, fmt.Errorf("unexpected matrix type: got (%T), want (CountMinSketchVector)", results[0].Data)
 		}
 		return NewCountMinSketchVectorStepEvaluator(vector), nil
+	case *HyperLogLogEvalExpr:
+		queries := make([]DownstreamQuery, len(e.downstreams))
+		for i, d := range e.downstreams {
+			queries[i] = DownstreamQuery{
+				Params: ParamsWithExpressionOverride{
+					Params:             ParamOverridesFromShard(params, d.shard),
+					ExpressionOverride: d.SampleExpr,
+				},
+			}
+		}
+
+		acc := newHyperLogLogAccumulator()
+		results, err := ev.Downstream(ctx, queries, acc)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(results) != 1 {
+			return nil, fmt.Errorf("unexpected results length for sharded distinct count: got (%d), want (1)", len(results))
+		}
+
+		matrix, ok := results[0].Data.(HyperLogLogMatrix)
+		if !ok {
+			return nil, fmt.Errorf("unexpected matrix type: got (%T), want (HyperLogLogMatrix)", results[0].Data)
+		}
+		return NewHyperLogLogVectorStepEvaluator(NewHyperLogLogMatrixStepEvaluator(matrix, params)), nil
+	case *HistogramCumulateExpr:
+		buckets, err := nextEvFactory.NewStepEvaluator(ctx, nextEvFactory, e.buckets, params)
+		if err != nil {
+			return nil, err
+		}
+		return newHistogramCumulateEvaluator(buckets), nil
 	default:
 		return ev.defaultEvaluator.NewStepEvaluator(ctx, nextEvFactory, e, params)
 	}
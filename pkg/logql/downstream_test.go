@@ -46,6 +46,8 @@ func TestMappingEquivalence(t *testing.T) {
 		{`1 + 1`, false, nil},
 		{`{a="1"}`, false, nil},
 		{`{a="1"} |= "number: 10"`, false, nil},
+		{`{a="1"} |= "line=10 " | context before=2 after=1`, false, nil},
+		{`{a="1"} | dedup index`, false, nil},
 		{`rate({a=~".+"}[1s])`, false, nil},
 		{`sum by (a) (rate({a=~".+"}[1s]))`, false, nil},
 		{`sum(rate({a=~".+"}[1s]))`, false, nil},
@@ -82,6 +84,21 @@ func TestMappingEquivalence(t *testing.T) {
 		{`last_over_time({a=~".+"} | logfmt | unwrap value [1s]) by (a)`, false, []string{ShardLastOverTime}},
 		{`last_over_time({a=~".+"} | logfmt | unwrap value [1s] offset 2s) by (a)`, false, []string{ShardLastOverTime}},
 		{`last_over_time({a=~".+"} | logfmt | unwrap value [1s] offset -2s) by (a)`, false, []string{ShardLastOverTime}},
+		{`histogram_over_time({a=~".+"} | logfmt | unwrap value [5s])`, false, nil},
+		{`histogram_over_time({a=~".+"} | logfmt | unwrap value [5s]) by (a)`, false, nil},
+		{`sum by (a, le) (histogram_over_time({a=~".+"} | logfmt | unwrap value [5s]))`, false, nil},
+		{`histogram_quantile(0.9, histogram_over_time({a=~".+"} | logfmt | unwrap value [5s]) by (a))`, false, nil},
+		{`sum(abs(rate({a=~".+"}[1s])))`, false, nil},
+		{`round(sum by (a) (rate({a=~".+"}[1s])), 0.5)`, false, nil},
+		{`clamp_max(count_over_time({a=~".+"}[1s]), 5)`, false, nil},
+		{`label_join(rate({a=~".+"}[1s]), "b", "-", "a", "a")`, false, nil},
+		{`sum(rate({a=~".+"}[1s])) > day_of_week()`, false, nil},
+		{`count_values("value", count_over_time({a=~".+"}[1s]))`, false, nil},
+		{`count_values("value", rate({a=~".+"}[1s])) by (a)`, false, nil},
+		{`group by (a) (rate({a=~".+"}[1s]))`, false, nil},
+		{`quantile(0.9, rate({a=~".+"}[1s])) by (a)`, false, nil},
+		{`count(limitk(2, rate({a=~".+"}[1s])))`, false, nil},
+		{`limit_ratio(0.5, rate({a=~".+"}[1s]))`, false, nil},
 		// topk prefers already-seen values in tiebreakers. Since the test data generates
 		// the same log lines for each series & the resulting promql.Vectors aren't deterministically
 		// sorted by labels, we don't expect this to pass.
@@ -176,6 +193,58 @@ func TestMappingEquivalence(t *testing.T) {
 	}
 }
 
+func TestMappingEquivalenceDedup(t *testing.T) {
+	var (
+		shards   = 3
+		rounds   = 20
+		start    = time.Unix(0, 0)
+		end      = time.Unix(0, int64(time.Second*time.Duration(rounds)))
+		step     = time.Second
+		interval = time.Duration(0)
+		limit    = 1000
+	)
+
+	// every stream is sent by two replicas, which are on different shards
+	// unless they are sharded without their replica label
+	var streams []logproto.Stream
+	for _, s := range randomStreams(20, rounds+1, shards, []string{"a", "b"}, true) {
+		for _, replica := range []string{"x", "y"} {
+			ls := labels.NewBuilder(mustParseLabels(s.Labels)).Set("replica", replica).Labels()
+			streams = append(streams, logproto.Stream{Labels: ls.String(), Hash: labels.StableHash(ls), Entries: s.Entries})
+		}
+	}
+
+	for _, query := range []string{
+		`{a=~".+"} | dedup replica`,
+		`count_over_time({a=~".+"} | dedup replica [1s])`,
+		`sum by (a) (count_over_time({a=~".+"} | dedup replica [1s]))`,
+		`sum(rate({a=~".+"} | dedup replica [1s]))`,
+		`max by (b) (sum_over_time({a=~".+"} | logfmt | dedup replica | unwrap value [1s]))`,
+	} {
+		t.Run(query, func(t *testing.T) {
+			q := NewMockQuerier(shards, streams)
+			opts := EngineOpts{}
+			regular := NewEngine(opts, q, NoLimits, log.NewNopLogger())
+			sharded := NewDownstreamEngine(opts, MockDownstreamer{regular}, NoLimits, log.NewNopLogger())
+
+			params, err := NewLiteralParams(query, start, end, step, interval, logproto.FORWARD, uint32(limit), nil, nil)
+			require.NoError(t, err)
+			ctx := user.InjectOrgID(context.Background(), "fake")
+
+			mapper := NewShardMapper(NewPowerOfTwoStrategy(ConstantShards(shards)), nilShardMetrics, nil)
+			_, _, mapped, err := mapper.Parse(params.GetExpression())
+			require.NoError(t, err)
+			require.Contains(t, mapped.String(), "shard=")
+
+			res, err := regular.Query(params).Exec(ctx)
+			require.NoError(t, err)
+			shardedRes, err := sharded.Query(ctx, ParamsWithExpressionOverride{Params: params, ExpressionOverride: mapped}).Exec(ctx)
+			require.NoError(t, err)
+			require.Equal(t, res.Data, shardedRes.Data)
+		})
+	}
+}
+
 func TestMappingEquivalenceSketches(t *testing.T) {
 	var (
 		shards   = 3
@@ -196,6 +265,9 @@ func TestMappingEquivalenceSketches(t *testing.T) {
 		{`quantile_over_time(0.70, {a=~".+"} | logfmt | unwrap value [1s]) by (a)`, 0.05},
 		{`quantile_over_time(0.99, {a=~".+"} | logfmt | unwrap value [1s]) by (a)`, 0.02},
 		{`quantile_over_time(0.99, {a=~".+"} | logfmt | unwrap value [1s] offset 2s) by (a)`, 0.02},
+		{`approx_count_distinct_over_time({a=~".+"} | logfmt | unwrap line [5s]) by (a)`, 0.02},
+		{`approx_count_distinct_over_time({a=~".+"} | logfmt | unwrap value [1s]) by (a)`, 0.02},
+		{`approx_count_distinct by (a) (count_over_time({a=~".+"} | logfmt | keep a, index [1s]))`, 0.02},
 	} {
 		q := NewMockQuerier(
 			shards,
@@ -225,7 +297,7 @@ func TestMappingEquivalenceSketches(t *testing.T) {
 			ctx := user.InjectOrgID(context.Background(), "fake")
 
 			strategy := NewPowerOfTwoStrategy(ConstantShards(shards))
-			mapper := NewShardMapper(strategy, nilShardMetrics, []string{ShardQuantileOverTime})
+			mapper := NewShardMapper(strategy, nilShardMetrics, []string{ShardQuantileOverTime, ShardApproxCountDistinct})
 			_, _, mapped, err := mapper.Parse(params.GetExpression())
 			require.NoError(t, err)
 
@@ -261,7 +333,7 @@ func TestMappingEquivalenceSketches(t *testing.T) {
 			ctx := user.InjectOrgID(context.Background(), "fake")
 
 			strategy := NewPowerOfTwoStrategy(ConstantShards(shards))
-			mapper := NewShardMapper(strategy, nilShardMetrics, []string{ShardQuantileOverTime, SupportApproxTopk})
+			mapper := NewShardMapper(strategy, nilShardMetrics, []string{ShardQuantileOverTime, ShardApproxCountDistinct, SupportApproxTopk})
 
 			_, _, mapped, err := mapper.Parse(params.GetExpression())
 			require.NoError(t, err)
@@ -502,6 +574,11 @@ func TestRangeMappingEquivalence(t *testing.T) {
 		{`rate({a=~".+"}[2s])`, time.Second},
 		{`rate({a=~".+"} | unwrap b [2s])`, time.Second},
 		{`bytes_rate({a=~".+"}[2s])`, time.Second},
+		{`histogram_over_time({a=~".+"} | logfmt | unwrap value [2s]) by (a)`, time.Second},
+
+		// histogram_quantile
+		{`histogram_quantile(0.9, histogram_over_time({a=~".+"} | logfmt | unwrap value [2s]) by (a))`, time.Second},
+		{`histogram_quantile(0.5, sum by (le) (histogram_over_time({a=~".+"} | logfmt | unwrap value [2s]) by (a)))`, time.Second},
 
 		// sum
 		{`sum(bytes_over_time({a=~".+"}[2s]))`, time.Second},
@@ -160,11 +160,16 @@ type EngineOpts struct {
 	// MaxCountMinSketchHeapSize is the maximum number of labels the heap for a topk query using a count min sketch
 	// can track. This impacts the memory usage and accuracy of a sharded probabilistic topk query.
 	MaxCountMinSketchHeapSize int `yaml:"max_count_min_sketch_heap_size"`
+
+	// MaxContextWindow is the maximum amount of time before and after a log
+	// line matched by a query with a context stage to look for its context.
+	MaxContextWindow time.Duration `yaml:"max_context_window"`
 }
 
 func (opts *EngineOpts) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
 	f.DurationVar(&opts.MaxLookBackPeriod, prefix+"max-lookback-period", 30*time.Second, "The maximum amount of time to look back for log lines. Used only for instant log queries.")
 	f.IntVar(&opts.MaxCountMinSketchHeapSize, prefix+"max-count-min-sketch-heap-size", 10_000, "The maximum number of labels the heap of a topk query using a count min sketch can track.")
+	f.DurationVar(&opts.MaxContextWindow, prefix+"max-context-window", time.Hour, "The maximum amount of time before and after a log line matched by a query with a context stage to look for the lines of its context.")
 
 	// Log executing query by default
 	opts.LogExecutingQuery = true
@@ -174,6 +179,9 @@ func (opts *EngineOpts) applyDefault() {
 	if opts.MaxLookBackPeriod == 0 {
 		opts.MaxLookBackPeriod = 30 * time.Second
 	}
+	if opts.MaxContextWindow == 0 {
+		opts.MaxContextWindow = time.Hour
+	}
 }
 
 // QueryEngine is the LogQL engine.
@@ -192,7 +200,7 @@ func NewEngine(opts EngineOpts, q Querier, l Limits, logger log.Logger) *QueryEn
 	}
 	return &QueryEngine{
 		logger:           logger,
-		evaluatorFactory: NewDefaultEvaluator(q, opts.MaxLookBackPeriod, opts.MaxCountMinSketchHeapSize),
+		evaluatorFactory: NewDefaultEvaluator(q, opts.MaxLookBackPeriod, opts.MaxCountMinSketchHeapSize, opts.MaxContextWindow),
 		limits:           l,
 		opts:             opts,
 	}
@@ -235,6 +243,8 @@ func (q *query) resultLength(res promql_parser.Value) int {
 		return int(r.Lines())
 	case ProbabilisticQuantileMatrix:
 		return len(r)
+	case HyperLogLogMatrix:
+		return len(r)
 	default:
 		// for `scalar` or `string` or any other return type, we just return `0` as result length.
 		return 0
@@ -342,6 +352,9 @@ func (q *query) Eval(ctx context.Context) (promql_parser.Value, error) {
 		if err != nil {
 			return nil, err
 		}
+		if syntax.HasDedupStage(e) {
+			itr = iter.NewDedupEntryIterator(ctx, itr)
+		}
 
 		encodingFlags := httpreq.ExtractEncodingFlagsFromCtx(ctx)
 		if encodingFlags.Has(httpreq.FlagCategorizeLabels) {
@@ -424,6 +437,8 @@ func (q *query) evalSample(ctx context.Context, expr syntax.SampleExpr) (promql_
 			return JoinCountMinSketchVector(next, vec, stepEvaluator, q.params)
 		case HeapCountMinSketchVector:
 			return JoinCountMinSketchVector(next, vec.CountMinSketchVector, stepEvaluator, q.params)
+		case HyperLogLogVector:
+			return JoinHyperLogLogVector(next, vec, stepEvaluator, q.params)
 		default:
 			return nil, fmt.Errorf("unsupported result type: %T", r)
 		}
@@ -2745,6 +2745,10 @@ func (s *storeSampleResult) CountMinSketchVec() CountMinSketchVector {
 	return CountMinSketchVector{}
 }
 
+func (s *storeSampleResult) HyperLogLogVec() HyperLogLogVector {
+	return HyperLogLogVector{}
+}
+
 func TestEngine_Variants_RangeQuery(t *testing.T) {
 	t.Parallel()
 
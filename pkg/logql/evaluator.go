@@ -19,7 +19,9 @@ import (
 	"github.com/grafana/loki/v3/pkg/iter"
 	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/logql/vector"
 	"github.com/grafana/loki/v3/pkg/logqlmodel"
+	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
 	"github.com/grafana/loki/v3/pkg/querier/plan"
 	"github.com/grafana/loki/v3/pkg/storage/chunk/cache/resultscache"
 	"github.com/grafana/loki/v3/pkg/util"
@@ -290,19 +292,26 @@ func EvaluatorUnsupportedType(expr syntax.Expr, ev EvaluatorFactory) error {
 type DefaultEvaluator struct {
 	maxLookBackPeriod         time.Duration
 	maxCountMinSketchHeapSize int
+	maxContextWindow          time.Duration
 	querier                   Querier
 }
 
 // NewDefaultEvaluator constructs a DefaultEvaluator
-func NewDefaultEvaluator(querier Querier, maxLookBackPeriod time.Duration, maxCountMinSketchHeapSize int) *DefaultEvaluator {
+func NewDefaultEvaluator(querier Querier, maxLookBackPeriod time.Duration, maxCountMinSketchHeapSize int, maxContextWindow time.Duration) *DefaultEvaluator {
 	return &DefaultEvaluator{
 		querier:                   querier,
 		maxLookBackPeriod:         maxLookBackPeriod,
 		maxCountMinSketchHeapSize: maxCountMinSketchHeapSize,
+		maxContextWindow:          maxContextWindow,
 	}
 }
 
 func (ev *DefaultEvaluator) NewIterator(ctx context.Context, expr syntax.LogSelectorExpr, q Params) (iter.EntryIterator, error) {
+	contextStage, match := syntax.SplitContextStage(expr)
+	if contextStage != nil {
+		return ev.newContextIterator(ctx, expr, contextStage, match, q)
+	}
+
 	params := SelectLogParams{
 		QueryRequest: &logproto.QueryRequest{
 			Start:     q.Start(),
@@ -310,7 +319,7 @@ func (ev *DefaultEvaluator) NewIterator(ctx context.Context, expr syntax.LogSele
 			Limit:     q.Limit(),
 			Direction: q.Direction(),
 			Selector:  expr.String(),
-			Shards:    q.Shards(),
+			Shards:    sampledShards(expr, q.Shards()),
 			Plan: &plan.QueryPlan{
 				AST: expr,
 			},
@@ -322,9 +331,117 @@ func (ev *DefaultEvaluator) NewIterator(ctx context.Context, expr syntax.LogSele
 		params.Start = params.Start.Add(-ev.maxLookBackPeriod)
 	}
 
+	if syntax.HasDedupStage(expr) && params.Limit > 0 {
+		return ev.selectDedupLogs(ctx, params)
+	}
 	return ev.querier.SelectLogs(ctx, params)
 }
 
+// dedupFetchFactor is the number of entries selected for each entry to return
+// by a log query with a dedup stage, as the duplicates of the replicas of a
+// stream only count once towards the limit.
+const dedupFetchFactor = 3
+
+// selectDedupLogs selects the deduplicated entries of a log query with a dedup
+// stage, up to the limit of params. It selects dedupFetchFactor times as many
+// entries, and twice as many again until the limit is reached or all the
+// entries were selected.
+func (ev *DefaultEvaluator) selectDedupLogs(ctx context.Context, params SelectLogParams) (iter.EntryIterator, error) {
+	limit, fetch := params.Limit, params.Limit*dedupFetchFactor
+	for {
+		// Every page is selected with its own request, as queriers may keep it.
+		req := *params.QueryRequest
+		req.Limit = fetch
+		it, err := ev.querier.SelectLogs(ctx, SelectLogParams{QueryRequest: &req})
+		if err != nil {
+			return nil, err
+		}
+
+		// The duplicates are only recorded for the entries returned.
+		counted := &countingEntryIterator{EntryIterator: it}
+		deduped := iter.NewDedupEntryIterator(context.Background(), counted)
+		var (
+			streams = map[string]*logproto.Stream{}
+			order   []string
+			n       uint32
+		)
+		for n < limit && deduped.Next() {
+			n++
+			lbs := deduped.Labels()
+			s, ok := streams[lbs]
+			if !ok {
+				s = &logproto.Stream{Labels: lbs, Hash: deduped.StreamHash()}
+				streams[lbs] = s
+				order = append(order, lbs)
+			}
+			s.Entries = append(s.Entries, deduped.At())
+		}
+		err = deduped.Err()
+		if closeErr := deduped.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if n == limit || counted.read < fetch {
+			stats.FromContext(ctx).AddDuplicates(int64(counted.read - n))
+			res := make([]logproto.Stream, 0, len(order))
+			for _, lbs := range order {
+				res = append(res, *streams[lbs])
+			}
+			return iter.NewStreamsIterator(res, params.Direction), nil
+		}
+		fetch *= 2
+	}
+}
+
+// countingEntryIterator counts the entries read from an iterator.
+type countingEntryIterator struct {
+	iter.EntryIterator
+	read uint32
+}
+
+func (i *countingEntryIterator) Next() bool {
+	if !i.EntryIterator.Next() {
+		return false
+	}
+	i.read++
+	return true
+}
+
+// newContextIterator returns the iterator of a log query with a context stage.
+// Only the entries passing the leading line filters of the query are read, a
+// page of the limit of the query at a time, and matched in the querier. The
+// entries surrounding the matches are then read from their streams, within
+// the maximum context window.
+func (ev *DefaultEvaluator) newContextIterator(ctx context.Context, expr syntax.LogSelectorExpr, contextStage *syntax.ContextExpr, match syntax.LogSelectorExpr, q Params) (iter.EntryIterator, error) {
+	pipeline, err := match.Pipeline()
+	if err != nil {
+		return nil, err
+	}
+
+	selector := syntax.LeadingLineFilters(match)
+	req := logproto.QueryRequest{
+		Start:     q.Start(),
+		End:       q.End(),
+		Direction: q.Direction(),
+		Selector:  selector.String(),
+		Shards:    sampledShards(expr, q.Shards()),
+		Plan: &plan.QueryPlan{
+			AST: selector,
+		},
+		StoreChunks: q.GetStoreChunks(),
+	}
+
+	if GetRangeType(q) == InstantType {
+		req.Start = req.Start.Add(-ev.maxLookBackPeriod)
+	}
+
+	candidates := newPagedEntryIterator(ctx, ev.querier, req, q.Limit())
+	return newContextIterator(ctx, ev.querier, candidates, pipeline, contextStage, ev.maxContextWindow, q.Direction(), q.Limit()), nil
+}
+
 func (ev *DefaultEvaluator) NewStepEvaluator(
 	ctx context.Context,
 	nextEvFactory SampleEvaluatorFactory,
@@ -333,7 +450,9 @@ func (ev *DefaultEvaluator) NewStepEvaluator(
 ) (StepEvaluator, error) {
 	switch e := expr.(type) {
 	case *syntax.VectorAggregationExpr:
-		if rangExpr, ok := e.Left.(*syntax.RangeAggregationExpr); ok && e.Operation == syntax.OpTypeSum {
+		// The labels of the samples are not reduced at the source with a dedup
+		// stage, they tell apart the samples of different streams.
+		if rangExpr, ok := e.Left.(*syntax.RangeAggregationExpr); ok && e.Operation == syntax.OpTypeSum && !syntax.HasDedupStage(rangExpr) {
 			// if range expression is wrapped with a vector expression
 			// we should send the vector expression for allowing reducing labels at the source.
 			nextEvFactory = SampleEvaluatorFunc(func(ctx context.Context, _ SampleEvaluatorFactory, _ syntax.SampleExpr, _ Params) (StepEvaluator, error) {
@@ -345,7 +464,7 @@ func (ev *DefaultEvaluator) NewStepEvaluator(
 						End: q.End().Add(-rangExpr.Left.Offset).Add(time.Nanosecond),
 						// intentionally send the vector for reducing labels.
 						Selector: e.String(),
-						Shards:   q.Shards(),
+						Shards:   sampledShards(expr, q.Shards()),
 						Plan: &plan.QueryPlan{
 							AST: expr,
 						},
@@ -361,6 +480,8 @@ func (ev *DefaultEvaluator) NewStepEvaluator(
 		return newVectorAggEvaluator(ctx, nextEvFactory, e, q, ev.maxCountMinSketchHeapSize)
 	case *CountMinSketchEvalExpr:
 		return NewCountMinSketchEvalStepEvaluator(ctx, nextEvFactory, e, q)
+	case *syntax.SubqueryAggregationExpr:
+		return newSubqueryAggEvaluator(ctx, nextEvFactory, e, q)
 	case *syntax.RangeAggregationExpr:
 		it, err := ev.querier.SelectSamples(ctx, SelectSampleParams{
 			&logproto.SampleQueryRequest{
@@ -370,7 +491,7 @@ func (ev *DefaultEvaluator) NewStepEvaluator(
 				End: q.End().Add(-e.Left.Offset).Add(time.Nanosecond),
 				// intentionally send the vector for reducing labels.
 				Selector: e.String(),
-				Shards:   q.Shards(),
+				Shards:   sampledShards(expr, q.Shards()),
 				Plan: &plan.QueryPlan{
 					AST: expr,
 				},
@@ -380,11 +501,18 @@ func (ev *DefaultEvaluator) NewStepEvaluator(
 		if err != nil {
 			return nil, err
 		}
+		if syntax.HasDedupStage(e) {
+			it = iter.NewDedupSampleIterator(ctx, it)
+		}
 		return newRangeAggEvaluator(iter.NewPeekingSampleIterator(it), e, q, e.Left.Offset)
 	case *syntax.BinOpExpr:
 		return newBinOpStepEvaluator(ctx, nextEvFactory, e, q)
 	case *syntax.LabelReplaceExpr:
 		return newLabelReplaceEvaluator(ctx, nextEvFactory, e, q)
+	case *syntax.LabelJoinExpr:
+		return newLabelJoinEvaluator(ctx, nextEvFactory, e, q)
+	case *syntax.FunctionExpr:
+		return newFunctionEvaluator(ctx, nextEvFactory, e, q)
 	case *syntax.VectorExpr:
 		val, err := e.Value()
 		if err != nil {
@@ -412,8 +540,28 @@ func newVectorAggEvaluator(
 	}
 	sort.Strings(expr.Grouping.Groups)
 
-	if expr.Operation == syntax.OpTypeCountMinSketch {
+	switch expr.Operation {
+	case syntax.OpTypeCountMinSketch:
 		return newCountMinSketchVectorAggEvaluator(nextEvaluator, expr, maxCountMinSketchHeapSize)
+	case syntax.OpTypeHyperLogLog:
+		return newHyperLogLogVectorAggEvaluator(nextEvaluator, expr), nil
+	case syntax.OpTypeApproxCountDistinct:
+		return NewHyperLogLogVectorStepEvaluator(newHyperLogLogVectorAggEvaluator(nextEvaluator, expr)), nil
+	case syntax.OpTypeHistogramQuantile:
+		return newHistogramQuantileEvaluator(nextEvaluator, expr), nil
+	case syntax.OpTypeCountValues:
+		// count_values("v", x) counts the series by value, like count by (v)
+		// over x with the values of the series stored in v.
+		return &VectorAggEvaluator{
+			nextEvaluator: newCountValuesEvaluator(nextEvaluator, expr.Label),
+			expr: &syntax.VectorAggregationExpr{
+				Left:      expr.Left,
+				Grouping:  countValuesGrouping(expr.Grouping, expr.Label),
+				Operation: syntax.OpTypeCount,
+			},
+			buf: make([]byte, 0, 1024),
+			lb:  labels.NewBuilder(labels.EmptyLabels()),
+		}, nil
 	}
 
 	return &VectorAggEvaluator{
@@ -439,11 +587,15 @@ func (e *VectorAggEvaluator) Next() (bool, int64, StepResult) {
 	}
 	vec := r.SampleVector()
 	result := map[uint64]*groupedAggregation{}
-	if e.expr.Operation == syntax.OpTypeTopK || e.expr.Operation == syntax.OpTypeBottomK {
+	if e.expr.Operation == syntax.OpTypeTopK || e.expr.Operation == syntax.OpTypeBottomK || e.expr.Operation == syntax.OpTypeLimitK {
 		if e.expr.Params < 1 {
 			return next, ts, SampleVector{}
 		}
 	}
+	if e.expr.Operation == syntax.OpTypeLimitRatio {
+		// The series are kept or dropped regardless of their group.
+		return next, ts, SampleVector(limitRatio(e.expr.Ratio, vec))
+	}
 	for _, s := range vec {
 		metric := s.Metric
 
@@ -514,6 +666,16 @@ func (e *VectorAggEvaluator) Next() (bool, int64, StepResult) {
 					F:      s.F,
 					Metric: s.Metric,
 				})
+			case syntax.OpTypeGroup:
+				result[groupingKey].value = 1
+			case syntax.OpTypeQuantile:
+				result[groupingKey].heap = vectorByValueHeap{{F: s.F}}
+			case syntax.OpTypeLimitK:
+				result[groupingKey].heap = make(vectorByValueHeap, 0, resultSize)
+				result[groupingKey].heap = limitK(e.expr.Params, result[groupingKey].heap, promql.Sample{
+					F:      s.F,
+					Metric: s.Metric,
+				})
 			}
 			continue
 		}
@@ -575,6 +737,15 @@ func (e *VectorAggEvaluator) Next() (bool, int64, StepResult) {
 				F:      s.F,
 				Metric: s.Metric,
 			})
+		case syntax.OpTypeGroup:
+			// the value of a group is always 1.
+		case syntax.OpTypeQuantile:
+			group.heap = append(group.heap, promql.Sample{F: s.F})
+		case syntax.OpTypeLimitK:
+			group.heap = limitK(e.expr.Params, group.heap, promql.Sample{
+				F:      s.F,
+				Metric: s.Metric,
+			})
 		default:
 			panic(errors.Errorf("expected aggregation operator but got %q", e.expr.Operation))
 		}
@@ -617,6 +788,19 @@ func (e *VectorAggEvaluator) Next() (bool, int64, StepResult) {
 				})
 			}
 			continue // Bypass default append.
+
+		case syntax.OpTypeQuantile:
+			aggr.value = Quantile(e.expr.Quantile, vector.HeapByMaxValue(aggr.heap))
+
+		case syntax.OpTypeLimitK:
+			for _, v := range aggr.heap {
+				vec = append(vec, promql.Sample{
+					Metric: v.Metric,
+					T:      ts,
+					F:      v.F,
+				})
+			}
+			continue // Bypass default append.
 		default:
 		}
 		vec = append(vec, promql.Sample{
@@ -673,6 +857,32 @@ func newRangeAggEvaluator(
 		return &QuantileSketchStepEvaluator{
 			iter: iter,
 		}, nil
+	case syntax.OpRangeTypeHyperLogLog, syntax.OpRangeTypeApproxCountDistinct:
+		ev := &HyperLogLogStepEvaluator{
+			iter: newHyperLogLogIterator(
+				it,
+				expr.Left.Interval.Nanoseconds(),
+				q.Step().Nanoseconds(),
+				q.Start().UnixNano(), q.End().UnixNano(), o.Nanoseconds(),
+			),
+		}
+		if expr.Operation == syntax.OpRangeTypeApproxCountDistinct {
+			return NewHyperLogLogVectorStepEvaluator(ev), nil
+		}
+		return ev, nil
+	case syntax.OpRangeTypeHistogram, syntax.OpRangeTypeHistogramBuckets:
+		ev := &RangeVectorEvaluator{
+			iter: newHistogramBucketsIterator(
+				it, expr,
+				expr.Left.Interval.Nanoseconds(),
+				q.Step().Nanoseconds(),
+				q.Start().UnixNano(), q.End().UnixNano(), o.Nanoseconds(),
+			),
+		}
+		if expr.Operation == syntax.OpRangeTypeHistogram {
+			return newHistogramCumulateEvaluator(ev), nil
+		}
+		return ev, nil
 	case syntax.OpRangeTypeFirstWithTimestamp:
 		iter := newFirstWithTimestampIterator(
 			it,
@@ -1382,7 +1592,7 @@ func (ev *DefaultEvaluator) NewVariantsStepEvaluator(
 				// add leap nanosecond to endTs to include lines exactly at endTs. range iterators work on start exclusive, end inclusive ranges
 				End:      q.End().Add(-logRange.Offset).Add(time.Nanosecond),
 				Selector: expr.String(),
-				Shards:   q.Shards(),
+				Shards:   sampledShards(expr, q.Shards()),
 				Plan: &plan.QueryPlan{
 					AST: expr,
 				},
@@ -1392,6 +1602,9 @@ func (ev *DefaultEvaluator) NewVariantsStepEvaluator(
 		if err != nil {
 			return nil, err
 		}
+		if syntax.HasDedupStage(e) {
+			it = iter.NewDedupSampleIterator(ctx, it)
+		}
 		return ev.newVariantsEvaluator(ctx, iter.NewPeekingSampleIterator(it), e, q)
 	default:
 		return nil, EvaluatorUnsupportedType(e, ev)
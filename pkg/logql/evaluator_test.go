@@ -1,16 +1,24 @@
 package logql
 
 import (
+	"context"
+	"fmt"
 	"math"
+	"slices"
 	"testing"
 	"time"
 
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/promql"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/loki/v3/pkg/iter"
+	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/logqlmodel"
 )
 
 func TestDefaultEvaluator_DivideByZero(t *testing.T) {
@@ -386,3 +394,255 @@ func newReturnVectorEvaluator(vec []float64) *returnVectorEvaluator {
 		vec: pvec,
 	}
 }
+
+type selectLogsRecorder struct {
+	MockQuerier
+	params []SelectLogParams
+	// limited truncates the entries selected to the limit of the request,
+	// once ordered by timestamp.
+	limited bool
+}
+
+func (q *selectLogsRecorder) SelectLogs(ctx context.Context, params SelectLogParams) (iter.EntryIterator, error) {
+	q.params = append(q.params, params)
+	it, err := q.MockQuerier.SelectLogs(ctx, params)
+	if err != nil || !q.limited || params.Limit == 0 {
+		return it, err
+	}
+	defer it.Close()
+	// Every entry is a stream, so that they are returned in order.
+	var streams []logproto.Stream
+	for it.Next() {
+		streams = append(streams, logproto.Stream{Labels: it.Labels(), Entries: []logproto.Entry{it.At()}})
+	}
+	slices.SortStableFunc(streams, func(a, b logproto.Stream) int {
+		if params.Direction == logproto.BACKWARD {
+			return b.Entries[0].Timestamp.Compare(a.Entries[0].Timestamp)
+		}
+		return a.Entries[0].Timestamp.Compare(b.Entries[0].Timestamp)
+	})
+	return iter.NewStreamsIterator(streams[:min(int(params.Limit), len(streams))], params.Direction), it.Err()
+}
+
+func TestDefaultEvaluator_ContextStage(t *testing.T) {
+	entries := func(lines ...string) []logproto.Entry {
+		res := make([]logproto.Entry, 0, len(lines))
+		for i, line := range lines {
+			res = append(res, logproto.Entry{Timestamp: time.Unix(int64(i), 0), Line: line})
+		}
+		return res
+	}
+	querier := &selectLogsRecorder{
+		MockQuerier: NewMockQuerier(1, []logproto.Stream{
+			{Labels: `{app="foo"}`, Entries: entries("start", "level=info", "level=error panic", "level=info", "level=info", "stop")},
+			{Labels: `{app="bar"}`, Entries: entries("level=error panic", "level=info")},
+			// The labels of this stream are a superset of the ones of foo.
+			{Labels: `{app="foo", pod="a"}`, Entries: entries("0", "1", "2", "3", "4", "5")},
+		}),
+	}
+	eng := NewEngine(EngineOpts{}, querier, NoLimits, log.NewNopLogger())
+
+	params, err := NewLiteralParams(`{app=~"foo|bar"} |= "panic" | logfmt | level="error" | context before=1 after=2`, time.Unix(0, 0), time.Unix(10, 0), 0, 0, logproto.FORWARD, 100, nil, nil)
+	require.NoError(t, err)
+	res, err := eng.Query(params).Exec(user.InjectOrgID(context.Background(), "fake"))
+	require.NoError(t, err)
+
+	require.Equal(t, logqlmodel.Streams{
+		{Labels: `{app="bar"}`, Entries: entries("level=error panic", "level=info")},
+		{Labels: `{app="foo"}`, Entries: entries("start", "level=info", "level=error panic", "level=info", "level=info")[1:]},
+	}, res.Data)
+
+	// The line filters are pushed down, and the candidates are read a page
+	// of the limit at a time.
+	require.Equal(t, `{app=~"foo|bar"} |= "panic"`, querier.params[0].Selector)
+	require.Equal(t, uint32(100), querier.params[0].Limit)
+	// The context of the matches is read once from each stream, within the
+	// context window.
+	var selectors []string
+	for _, p := range querier.params[1:] {
+		selectors = append(selectors, p.Selector)
+		require.Equal(t, 2*time.Hour+time.Nanosecond, p.End.Sub(p.Start))
+		require.Equal(t, uint32(100), p.Limit)
+	}
+	require.Equal(t, []string{`{app="bar"}`, `{app="foo"}`}, selectors)
+
+	paged := &selectLogsRecorder{
+		MockQuerier: NewMockQuerier(1, []logproto.Stream{
+			{Labels: `{app="foo"}`, Entries: entries("panic level=info", "panic level=info", "panic level=error", "a", "panic level=error", "b")},
+		}),
+		limited: true,
+	}
+	eng = NewEngine(EngineOpts{}, paged, NoLimits, log.NewNopLogger())
+	for _, tc := range []struct {
+		limit     uint32
+		expected  []logproto.Entry
+		selectors []string
+	}{
+		{
+			// The candidates are read until enough entries are returned, and
+			// the windows are read a page of the limit at a time too.
+			limit:     3,
+			expected:  entries("panic level=info", "panic level=info", "panic level=error", "a", "panic level=error")[2:],
+			selectors: []string{`{app="foo"} |= "panic"`, `{app="foo"}`, `{app="foo"}`, `{app="foo"} |= "panic"`, `{app="foo"}`, `{app="foo"}`, `{app="foo"}`},
+		},
+		{
+			// The windows of the matches of a page overlapping in a stream are
+			// merged and read once.
+			limit:     10,
+			expected:  entries("panic level=info", "panic level=info", "panic level=error", "a", "panic level=error", "b")[2:],
+			selectors: []string{`{app="foo"} |= "panic"`, `{app="foo"}`},
+		},
+	} {
+		paged.params = nil
+		params, err := NewLiteralParams(`{app="foo"} |= "panic" | logfmt | level="error" | context after=1`, time.Unix(0, 0), time.Unix(10, 0), 0, 0, logproto.FORWARD, tc.limit, nil, nil)
+		require.NoError(t, err)
+		res, err := eng.Query(params).Exec(user.InjectOrgID(context.Background(), "fake"))
+		require.NoError(t, err)
+		require.Equal(t, logqlmodel.Streams{{Labels: `{app="foo"}`, Entries: tc.expected}}, res.Data)
+
+		selectors = selectors[:0]
+		for _, p := range paged.params {
+			selectors = append(selectors, p.Selector)
+		}
+		require.Equal(t, tc.selectors, selectors)
+	}
+}
+
+func TestEngine_ContextStage(t *testing.T) {
+	// Lines are named after their position in the stream, the ones containing
+	// "panic" match.
+	stream := func(labels string, lines ...string) logproto.Stream {
+		s := logproto.Stream{Labels: labels}
+		for i, line := range lines {
+			s.Entries = append(s.Entries, logproto.Entry{Timestamp: time.Unix(int64(i), 0), Line: line})
+		}
+		return s
+	}
+	querier := NewMockQuerier(1, []logproto.Stream{
+		stream(`{app="a"}`, "0", "1", "2 panic", "3", "4", "5", "6", "7 panic", "8", "9"),
+		stream(`{app="b"}`, "0", "1", "2", "3", "4 panic", "5 panic", "6", "7", "8", "9"),
+	})
+	eng := NewEngine(EngineOpts{}, querier, NoLimits, log.NewNopLogger())
+
+	for _, tc := range []struct {
+		before, after int
+		start, end    int64
+		expected      map[string][]string
+	}{
+		{
+			before: 0, after: 0, end: 10,
+			expected: map[string][]string{
+				`{app="a"}`: {"2 panic", "7 panic"},
+				`{app="b"}`: {"4 panic", "5 panic"},
+			},
+		},
+		{
+			before: 1, after: 1, end: 10,
+			expected: map[string][]string{
+				`{app="a"}`: {"1", "2 panic", "3", "6", "7 panic", "8"},
+				`{app="b"}`: {"3", "4 panic", "5 panic", "6"},
+			},
+		},
+		{
+			before: 3, after: 0, end: 10,
+			expected: map[string][]string{
+				`{app="a"}`: {"0", "1", "2 panic", "4", "5", "6", "7 panic"},
+				`{app="b"}`: {"1", "2", "3", "4 panic", "5 panic"},
+			},
+		},
+		{
+			// Contexts of close matches overlap and entries are only returned once.
+			before: 0, after: 5, end: 10,
+			expected: map[string][]string{
+				`{app="a"}`: {"2 panic", "3", "4", "5", "6", "7 panic", "8", "9"},
+				`{app="b"}`: {"4 panic", "5 panic", "6", "7", "8", "9"},
+			},
+		},
+		{
+			// The context of a match may be outside of the query range.
+			before: 2, after: 2, start: 4, end: 5,
+			expected: map[string][]string{
+				`{app="b"}`: {"2", "3", "4 panic", "5 panic", "6"},
+			},
+		},
+	} {
+		for _, direction := range []logproto.Direction{logproto.FORWARD, logproto.BACKWARD} {
+			qs := fmt.Sprintf(`{app=~"a|b"} |= "panic" | context before=%d after=%d`, tc.before, tc.after)
+			t.Run(fmt.Sprintf("%s %d-%d %s", qs, tc.start, tc.end, direction), func(t *testing.T) {
+				params, err := NewLiteralParams(qs, time.Unix(tc.start, 0), time.Unix(tc.end, 0), 0, 0, direction, 100, nil, nil)
+				require.NoError(t, err)
+				res, err := eng.Query(params).Exec(user.InjectOrgID(context.Background(), "fake"))
+				require.NoError(t, err)
+
+				actual := map[string][]string{}
+				for _, s := range res.Data.(logqlmodel.Streams) {
+					for _, e := range s.Entries {
+						actual[s.Labels] = append(actual[s.Labels], e.Line)
+					}
+					if direction == logproto.BACKWARD {
+						slices.Reverse(actual[s.Labels])
+					}
+				}
+				require.Equal(t, tc.expected, actual)
+			})
+		}
+	}
+}
+
+func TestDefaultEvaluator_DedupStage(t *testing.T) {
+	entries := func(lines ...string) []logproto.Entry {
+		res := make([]logproto.Entry, 0, len(lines))
+		for i, line := range lines {
+			res = append(res, logproto.Entry{Timestamp: time.Unix(int64(i), 0), Line: line})
+		}
+		return res
+	}
+	querier := &selectLogsRecorder{
+		MockQuerier: NewMockQuerier(1, []logproto.Stream{
+			{Labels: `{app="foo", replica="a"}`, Entries: entries("0", "1", "2")},
+			{Labels: `{app="foo", replica="b"}`, Entries: entries("0", "1", "2 only on b")},
+			{Labels: `{app="bar", replica="a"}`, Entries: entries("0", "1")},
+		}),
+	}
+	eng := NewEngine(EngineOpts{}, querier, NoLimits, log.NewNopLogger())
+	ctx := user.InjectOrgID(context.Background(), "fake")
+
+	params, err := NewLiteralParams(`{app=~".+"} | dedup replica`, time.Unix(0, 0), time.Unix(10, 0), 0, 0, logproto.FORWARD, 4, nil, nil)
+	require.NoError(t, err)
+	res, err := eng.Query(params).Exec(ctx)
+	require.NoError(t, err)
+	require.Equal(t, logqlmodel.Streams{
+		{Labels: `{app="bar"}`, Entries: entries("0", "1")},
+		{Labels: `{app="foo"}`, Entries: entries("0", "1")},
+	}, res.Data)
+	// The duplicates must not count towards the limit.
+	require.Len(t, querier.params, 1)
+	require.Equal(t, uint32(4*dedupFetchFactor), querier.params[0].Limit)
+
+	// More entries are selected while the duplicates fill the selected pages.
+	replicated := &selectLogsRecorder{limited: true}
+	for _, replica := range []string{"a", "b", "c", "d", "e"} {
+		replicated.streams = append(replicated.streams, logproto.Stream{Labels: `{app="foo", replica="` + replica + `"}`, Entries: entries("0", "1", "2", "3", "4")})
+	}
+	params, err = NewLiteralParams(`{app="foo"} | dedup replica`, time.Unix(0, 0), time.Unix(10, 0), 0, 0, logproto.FORWARD, 4, nil, nil)
+	require.NoError(t, err)
+	res, err = NewEngine(EngineOpts{}, replicated, NoLimits, log.NewNopLogger()).Query(params).Exec(ctx)
+	require.NoError(t, err)
+	require.Equal(t, logqlmodel.Streams{
+		{Labels: `{app="foo"}`, Entries: entries("0", "1", "2", "3")},
+	}, res.Data)
+	require.Len(t, replicated.params, 2)
+	require.Equal(t, uint32(4*dedupFetchFactor), replicated.params[0].Limit)
+	require.Equal(t, uint32(8*dedupFetchFactor), replicated.params[1].Limit)
+	require.Equal(t, int64(12), res.Statistics.TotalDuplicates())
+
+	params, err = NewLiteralParams(`sum by (app) (count_over_time({app=~".+"} | dedup replica [10s]))`, time.Unix(10, 0), time.Unix(10, 0), 0, 0, logproto.FORWARD, 0, nil, nil)
+	require.NoError(t, err)
+	res, err = eng.Query(params).Exec(ctx)
+	require.NoError(t, err)
+	// The entries at the start of the range are excluded.
+	require.Equal(t, promql.Vector{
+		{T: 10000, F: 1, Metric: labels.FromStrings("app", "bar")},
+		{T: 10000, F: 3, Metric: labels.FromStrings("app", "foo")},
+	}, res.Data)
+}
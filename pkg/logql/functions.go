@@ -0,0 +1,197 @@
+package logql
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+// functionValue returns how the function of expr computes the value of a
+// sample from its timestamp in milliseconds and its value, with the same
+// semantics as PromQL.
+func functionValue(expr *syntax.FunctionExpr) func(t int64, v float64) float64 {
+	switch expr.Function {
+	case syntax.OpFuncAbs:
+		return func(_ int64, v float64) float64 { return math.Abs(v) }
+	case syntax.OpFuncCeil:
+		return func(_ int64, v float64) float64 { return math.Ceil(v) }
+	case syntax.OpFuncFloor:
+		return func(_ int64, v float64) float64 { return math.Floor(v) }
+	case syntax.OpFuncRound:
+		toNearest := 1.0
+		if expr.Param != nil {
+			toNearest = *expr.Param
+		}
+		// As in PromQL, dividing by the inverse of toNearest avoids floating
+		// point errors, e.g. for round(x, 0.1).
+		toNearestInverse := 1.0 / toNearest
+		return func(_ int64, v float64) float64 {
+			return math.Floor(v*toNearestInverse+0.5) / toNearestInverse
+		}
+	case syntax.OpFuncClampMin:
+		minVal := *expr.Param
+		return func(_ int64, v float64) float64 { return math.Max(minVal, v) }
+	case syntax.OpFuncClampMax:
+		maxVal := *expr.Param
+		return func(_ int64, v float64) float64 { return math.Min(maxVal, v) }
+	case syntax.OpFuncSqrt:
+		return func(_ int64, v float64) float64 { return math.Sqrt(v) }
+	case syntax.OpFuncLn:
+		return func(_ int64, v float64) float64 { return math.Log(v) }
+	case syntax.OpFuncExp:
+		return func(_ int64, v float64) float64 { return math.Exp(v) }
+	case syntax.OpFuncTimestamp:
+		return func(t int64, _ float64) float64 { return float64(t) / 1000 }
+	case syntax.OpFuncHour:
+		return dateFunction(expr, func(t time.Time) float64 { return float64(t.Hour()) })
+	case syntax.OpFuncDayOfWeek:
+		return dateFunction(expr, func(t time.Time) float64 { return float64(t.Weekday()) })
+	default:
+		return nil
+	}
+}
+
+// dateFunction returns a function computing f from the values of the samples
+// as Unix timestamps in seconds, or from the time of the step without argument.
+func dateFunction(expr *syntax.FunctionExpr, f func(time.Time) float64) func(t int64, v float64) float64 {
+	if expr.Left == nil {
+		return func(t int64, _ float64) float64 { return f(time.UnixMilli(t).UTC()) }
+	}
+	return func(_ int64, v float64) float64 { return f(time.Unix(int64(v), 0).UTC()) }
+}
+
+func newFunctionEvaluator(
+	ctx context.Context,
+	evFactory SampleEvaluatorFactory,
+	expr *syntax.FunctionExpr,
+	q Params,
+) (StepEvaluator, error) {
+	fn := functionValue(expr)
+	if fn == nil {
+		return nil, fmt.Errorf("unsupported function: %s", expr.Function)
+	}
+
+	var nextEvaluator StepEvaluator
+	if expr.Left == nil {
+		// hour() and day_of_week() return a single series without labels.
+		nextEvaluator = newVectorIterator(0, q.Step().Milliseconds(), q.Start().UnixMilli(), q.End().UnixMilli())
+	} else {
+		var err error
+		nextEvaluator, err = evFactory.NewStepEvaluator(ctx, evFactory, expr.Left, q)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &FunctionEvaluator{
+		nextEvaluator: nextEvaluator,
+		expr:          expr,
+		fn:            fn,
+	}, nil
+}
+
+// FunctionEvaluator applies a function to the value of each sample.
+type FunctionEvaluator struct {
+	nextEvaluator StepEvaluator
+	expr          *syntax.FunctionExpr
+	fn            func(t int64, v float64) float64
+}
+
+func (e *FunctionEvaluator) Next() (bool, int64, StepResult) {
+	next, ts, r := e.nextEvaluator.Next()
+	if !next {
+		return false, 0, SampleVector{}
+	}
+	vec := r.SampleVector()
+	for i := range vec {
+		vec[i].F = e.fn(vec[i].T, vec[i].F)
+	}
+	return next, ts, SampleVector(vec)
+}
+
+func (e *FunctionEvaluator) Close() error {
+	return e.nextEvaluator.Close()
+}
+
+func (e *FunctionEvaluator) Error() error {
+	return e.nextEvaluator.Error()
+}
+
+func (e *FunctionEvaluator) Explain(parent Node) {
+	b := parent.Childf("%s Function", e.expr.Function)
+	e.nextEvaluator.Explain(b)
+}
+
+func newLabelJoinEvaluator(
+	ctx context.Context,
+	evFactory SampleEvaluatorFactory,
+	expr *syntax.LabelJoinExpr,
+	q Params,
+) (*LabelJoinEvaluator, error) {
+	nextEvaluator, err := evFactory.NewStepEvaluator(ctx, evFactory, expr.Left, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LabelJoinEvaluator{
+		nextEvaluator: nextEvaluator,
+		expr:          expr,
+		buf:           make([]byte, 0, 1024),
+	}, nil
+}
+
+// LabelJoinEvaluator sets the destination label of each series to the values
+// of the source labels joined by the separator.
+type LabelJoinEvaluator struct {
+	nextEvaluator StepEvaluator
+	labelCache    map[uint64]labels.Labels
+	expr          *syntax.LabelJoinExpr
+	buf           []byte
+}
+
+func (e *LabelJoinEvaluator) Next() (bool, int64, StepResult) {
+	next, ts, r := e.nextEvaluator.Next()
+	if !next {
+		return false, 0, SampleVector{}
+	}
+	vec := r.SampleVector()
+	if e.labelCache == nil {
+		e.labelCache = make(map[uint64]labels.Labels, len(vec))
+	}
+	var hash uint64
+	values := make([]string, len(e.expr.Src))
+	for i, s := range vec {
+		hash, e.buf = s.Metric.HashWithoutLabels(e.buf)
+		if lbs, ok := e.labelCache[hash]; ok {
+			vec[i].Metric = lbs
+			continue
+		}
+		for j, src := range e.expr.Src {
+			values[j] = s.Metric.Get(src)
+		}
+		// An empty value removes the label.
+		outLbs := labels.NewBuilder(s.Metric).Set(e.expr.Dst, strings.Join(values, e.expr.Separator)).Labels()
+		e.labelCache[hash] = outLbs
+		vec[i].Metric = outLbs
+	}
+	return next, ts, SampleVector(vec)
+}
+
+func (e *LabelJoinEvaluator) Close() error {
+	return e.nextEvaluator.Close()
+}
+
+func (e *LabelJoinEvaluator) Error() error {
+	return e.nextEvaluator.Error()
+}
+
+func (e *LabelJoinEvaluator) Explain(parent Node) {
+	b := parent.Childf("%s LabelJoin", e.expr.Dst)
+	e.nextEvaluator.Explain(b)
+}
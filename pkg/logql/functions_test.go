@@ -0,0 +1,91 @@
+package logql
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/iter"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+func TestFunctionValue(t *testing.T) {
+	// Monday, 2 January 2006 15:04:05 UTC
+	ts := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+
+	for _, tc := range []struct {
+		query    string
+		value    float64
+		expected float64
+	}{
+		{`abs(vector(0))`, -1.5, 1.5},
+		{`ceil(vector(0))`, 1.2, 2},
+		{`floor(vector(0))`, -1.2, -2},
+		{`round(vector(0))`, 2.5, 3},
+		{`round(vector(0))`, -2.5, -2},
+		{`round(vector(0), 0.1)`, 1.234, 1.2},
+		{`round(vector(0), 5)`, 13, 15},
+		{`clamp_min(vector(0), 2)`, 1, 2},
+		{`clamp_min(vector(0), -2)`, 1, 1},
+		{`clamp_max(vector(0), 2)`, 3, 2},
+		{`sqrt(vector(0))`, 16, 4},
+		{`ln(vector(0))`, math.E, 1},
+		{`exp(vector(0))`, 0, 1},
+		{`timestamp(vector(0))`, 42, float64(ts.Unix())},
+		{`hour(vector(0))`, float64(ts.Add(-3 * time.Hour).Unix()), 12},
+		{`hour()`, 0, 15},
+		{`day_of_week(vector(0))`, float64(ts.Add(-48 * time.Hour).Unix()), 6},
+		{`day_of_week()`, 0, 1},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			expr := syntax.MustParseExpr(tc.query).(*syntax.FunctionExpr)
+			require.InDelta(t, tc.expected, functionValue(expr)(ts.UnixMilli(), tc.value), 1e-9)
+		})
+	}
+}
+
+func TestEngine_Functions(t *testing.T) {
+	querier := errorIteratorQuerier{
+		samples: func() []iter.SampleIterator {
+			return []iter.SampleIterator{
+				iter.NewSeriesIterator(newSeries(10, identity, `{app="foo", pod="a"}`)),
+			}
+		},
+	}
+	eng := NewEngine(EngineOpts{}, querier, NoLimits, log.NewNopLogger())
+
+	for _, tc := range []struct {
+		qs       string
+		expected promql.Vector
+	}{
+		{
+			`clamp_max(sum by (app) (count_over_time({app="foo"}[10s])), 4)`,
+			promql.Vector{{T: 10000, F: 4, Metric: labels.FromStrings("app", "foo")}},
+		},
+		{
+			`label_join(count_over_time({app="foo"}[10s]), "dst", "-", "app", "pod")`,
+			promql.Vector{{T: 10000, F: 9, Metric: labels.FromStrings("app", "foo", "dst", "foo-a", "pod", "a")}},
+		},
+		{
+			`timestamp(count_over_time({app="foo"}[10s]))`,
+			promql.Vector{{T: 10000, F: 10, Metric: labels.FromStrings("app", "foo", "pod", "a")}},
+		},
+	} {
+		t.Run(tc.qs, func(t *testing.T) {
+			params, err := NewLiteralParams(tc.qs, time.Unix(10, 0), time.Unix(10, 0), 0, 0, logproto.FORWARD, 0, nil, nil)
+			require.NoError(t, err)
+
+			res, err := eng.Query(params).Exec(user.InjectOrgID(context.Background(), "fake"))
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, res.Data)
+		})
+	}
+}
@@ -0,0 +1,378 @@
+package logql
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+
+	"github.com/grafana/loki/v3/pkg/iter"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+// Histograms computed by histogram_over_time use the exponential buckets of
+// Prometheus native histograms. Like the buckets of classic Prometheus
+// histograms, each bucket is returned as a series with its upper boundary in
+// the `le` label, counting all values less than or equal to it, and the last
+// bucket is `+Inf`. At every step, all series have the same buckets, so that
+// they can be aggregated by `le`.
+//
+// Histograms are computed in two steps. __histogram_buckets_over_time__ counts
+// the values of each series into the buckets holding them, returning only the
+// buckets with values and without making them cumulative. Bucket boundaries
+// don't depend on the values, so the buckets of shards and split ranges are
+// merged by summing them, and are only made cumulative once merged.
+
+// histogramSchema is the native histogram schema of the buckets. Bucket
+// boundaries are the powers of 2^(2^-histogramSchema).
+const histogramSchema = 3
+
+var (
+	// histogramBounds are the fractions of the bucket boundaries within a
+	// power of two, in the form returned by math.Frexp.
+	histogramBounds = func() []float64 {
+		bounds := make([]float64, 1<<histogramSchema)
+		for i := range bounds {
+			bounds[i] = math.Exp2(float64(i)/float64(len(bounds)) - 1)
+		}
+		return bounds
+	}()
+	// histogramGrowth is the ratio between the boundaries of two consecutive buckets.
+	histogramGrowth = math.Exp2(math.Exp2(-histogramSchema))
+)
+
+// histogramBucket returns the index of the bucket holding the positive value
+// v. The bucket i holds the values in (histogramUpperBound(i-1), histogramUpperBound(i)].
+func histogramBucket(v float64) int {
+	frac, exp := math.Frexp(v)
+	return sort.SearchFloat64s(histogramBounds, frac) + (exp-1)*len(histogramBounds)
+}
+
+// histogramUpperBound returns the upper boundary of the bucket i.
+func histogramUpperBound(i int) float64 {
+	n := len(histogramBounds)
+	exp := i / n
+	if i%n < 0 {
+		exp--
+	}
+	return math.Ldexp(histogramBounds[i-exp*n]*2, exp)
+}
+
+// histogramLowerBound returns the lower boundary of the bucket with the upper
+// boundary upper.
+func histogramLowerBound(upper float64) float64 {
+	switch {
+	case upper > 0:
+		return upper / histogramGrowth
+	case upper < 0:
+		return upper * histogramGrowth
+	default:
+		return 0
+	}
+}
+
+// histogramBucketUpperBound returns the upper boundary of the bucket holding
+// v. Negative values are held by the buckets mirroring the ones of their
+// absolute value, and zero by its own bucket.
+func histogramBucketUpperBound(v float64) float64 {
+	switch {
+	case v > 0:
+		return histogramUpperBound(histogramBucket(v))
+	case v < 0:
+		return -histogramUpperBound(histogramBucket(-v) - 1)
+	default:
+		return 0
+	}
+}
+
+// newHistogramBucketsIterator returns the non-cumulative buckets of each
+// series, computed by __histogram_buckets_over_time__.
+func newHistogramBucketsIterator(
+	samples iter.PeekingSampleIterator,
+	expr *syntax.RangeAggregationExpr,
+	selRange, step, start, end, offset int64,
+) RangeVectorIterator {
+	// forces at least one step.
+	if step == 0 {
+		step = 1
+	}
+	if offset != 0 {
+		start = start - offset
+		end = end - offset
+	}
+
+	var it RangeVectorIterator = &histogramBucketsRangeVectorIterator{
+		batchRangeVectorIterator: &batchRangeVectorIterator{
+			iter:     samples,
+			step:     step,
+			end:      end,
+			selRange: selRange,
+			metrics:  map[string]labels.Labels{},
+			window:   map[string]*promql.Series{},
+			agg:      nil,
+			current:  start - step, // first loop iteration will set it to start
+			offset:   offset,
+		},
+		counts: map[float64]float64{},
+	}
+	if scale := samplingScale(expr); scale != 1 {
+		return &scaledRangeVectorIterator{RangeVectorIterator: it, scale: scale}
+	}
+	return it
+}
+
+type histogramBucketsRangeVectorIterator struct {
+	*batchRangeVectorIterator
+	counts map[float64]float64
+	sorted []float64
+}
+
+func (r *histogramBucketsRangeVectorIterator) At() (int64, StepResult) {
+	if r.at == nil {
+		r.at = make([]promql.Sample, 0, len(r.window))
+	}
+	r.at = r.at[:0]
+	// convert ts from nano to milli seconds as the iterator work with nanoseconds
+	ts := r.current/1e+6 + r.offset/1e+6
+
+	for _, series := range r.window {
+		clear(r.counts)
+		for _, p := range series.Floats {
+			// NaN and infinite values don't belong to any bucket.
+			if math.IsNaN(p.F) || math.IsInf(p.F, 0) {
+				continue
+			}
+			r.counts[histogramBucketUpperBound(p.F)]++
+		}
+		r.sorted = r.sorted[:0]
+		for upper := range r.counts {
+			r.sorted = append(r.sorted, upper)
+		}
+		sort.Float64s(r.sorted)
+
+		lb := labels.NewBuilder(series.Metric)
+		for _, upper := range r.sorted {
+			lb.Set(labels.BucketLabel, strconv.FormatFloat(upper, 'g', -1, 64))
+			r.at = append(r.at, promql.Sample{F: r.counts[upper], T: ts, Metric: lb.Labels()})
+		}
+	}
+	return ts, SampleVector(r.at)
+}
+
+// histogramCumulateEvaluator merges the buckets returned by
+// __histogram_buckets_over_time__ and makes them cumulative. At every step,
+// each histogram gets the buckets of all histograms, and a `+Inf` bucket.
+type histogramCumulateEvaluator struct {
+	nextEvaluator StepEvaluator
+	buf           []byte
+	lb            *labels.Builder
+}
+
+func newHistogramCumulateEvaluator(nextEvaluator StepEvaluator) *histogramCumulateEvaluator {
+	return &histogramCumulateEvaluator{
+		nextEvaluator: nextEvaluator,
+		buf:           make([]byte, 0, 1024),
+		lb:            labels.NewBuilder(labels.EmptyLabels()),
+	}
+}
+
+func (e *histogramCumulateEvaluator) Next() (bool, int64, StepResult) {
+	next, ts, r := e.nextEvaluator.Next()
+	if !next {
+		return false, 0, SampleVector{}
+	}
+	vec := r.SampleVector()
+
+	groups := map[uint64]*histogramGroup{}
+	order := make([]*histogramGroup, 0)
+	bounds := map[float64]struct{}{}
+	for _, s := range vec {
+		upper, err := strconv.ParseFloat(s.Metric.Get(labels.BucketLabel), 64)
+		if err != nil {
+			// series without a bucket label are ignored.
+			continue
+		}
+		bounds[upper] = struct{}{}
+
+		var key uint64
+		key, e.buf = s.Metric.HashWithoutLabels(e.buf, labels.BucketLabel)
+		group, ok := groups[key]
+		if !ok {
+			e.lb.Reset(s.Metric)
+			e.lb.Del(labels.BucketLabel)
+			group = &histogramGroup{labels: e.lb.Labels()}
+			groups[key] = group
+			order = append(order, group)
+		}
+		group.buckets = append(group.buckets, histogramBucketCount{upper: upper, count: s.F})
+	}
+
+	sorted := make([]float64, 0, len(bounds))
+	for upper := range bounds {
+		sorted = append(sorted, upper)
+	}
+	sort.Float64s(sorted)
+
+	res := make(SampleVector, 0, len(order)*(len(sorted)+1))
+	counts := make([]float64, len(sorted))
+	for _, group := range order {
+		clear(counts)
+		// The buckets of the same histogram returned by different shards or
+		// split ranges are summed.
+		for _, b := range group.buckets {
+			counts[sort.SearchFloat64s(sorted, b.upper)] += b.count
+		}
+
+		e.lb.Reset(group.labels)
+		var cumulative float64
+		for i, upper := range sorted {
+			cumulative += counts[i]
+			e.lb.Set(labels.BucketLabel, strconv.FormatFloat(upper, 'g', -1, 64))
+			res = append(res, promql.Sample{F: cumulative, T: ts, Metric: e.lb.Labels()})
+		}
+		e.lb.Set(labels.BucketLabel, "+Inf")
+		res = append(res, promql.Sample{F: cumulative, T: ts, Metric: e.lb.Labels()})
+	}
+	return next, ts, res
+}
+
+func (e *histogramCumulateEvaluator) Close() error {
+	return e.nextEvaluator.Close()
+}
+
+func (e *histogramCumulateEvaluator) Error() error {
+	return e.nextEvaluator.Error()
+}
+
+func (e *histogramCumulateEvaluator) Explain(parent Node) {
+	b := parent.Child("HistogramCumulate")
+	e.nextEvaluator.Explain(b)
+}
+
+// histogramQuantileEvaluator evaluates histogram_quantile over the buckets
+// returned by histogram_over_time.
+type histogramQuantileEvaluator struct {
+	nextEvaluator StepEvaluator
+	expr          *syntax.VectorAggregationExpr
+	buf           []byte
+	lb            *labels.Builder
+}
+
+func newHistogramQuantileEvaluator(nextEvaluator StepEvaluator, expr *syntax.VectorAggregationExpr) *histogramQuantileEvaluator {
+	return &histogramQuantileEvaluator{
+		nextEvaluator: nextEvaluator,
+		expr:          expr,
+		buf:           make([]byte, 0, 1024),
+		lb:            labels.NewBuilder(labels.EmptyLabels()),
+	}
+}
+
+type histogramBucketCount struct {
+	upper, count float64
+}
+
+type histogramGroup struct {
+	labels  labels.Labels
+	buckets []histogramBucketCount
+}
+
+func (e *histogramQuantileEvaluator) Next() (bool, int64, StepResult) {
+	next, ts, r := e.nextEvaluator.Next()
+	if !next {
+		return false, 0, SampleVector{}
+	}
+	vec := r.SampleVector()
+
+	groups := map[uint64]*histogramGroup{}
+	order := make([]*histogramGroup, 0)
+	for _, s := range vec {
+		upper, err := strconv.ParseFloat(s.Metric.Get(labels.BucketLabel), 64)
+		if err != nil {
+			// series without a bucket label are ignored.
+			continue
+		}
+
+		var key uint64
+		key, e.buf = s.Metric.HashWithoutLabels(e.buf, labels.BucketLabel)
+		group, ok := groups[key]
+		if !ok {
+			e.lb.Reset(s.Metric)
+			e.lb.Del(labels.BucketLabel)
+			group = &histogramGroup{labels: e.lb.Labels()}
+			groups[key] = group
+			order = append(order, group)
+		}
+		group.buckets = append(group.buckets, histogramBucketCount{upper: upper, count: s.F})
+	}
+
+	res := make(SampleVector, 0, len(order))
+	for _, group := range order {
+		res = append(res, promql.Sample{
+			Metric: group.labels,
+			T:      ts,
+			F:      histogramQuantile(e.expr.Quantile, group.buckets),
+		})
+	}
+	return next, ts, res
+}
+
+func (e *histogramQuantileEvaluator) Close() error {
+	return e.nextEvaluator.Close()
+}
+
+func (e *histogramQuantileEvaluator) Error() error {
+	return e.nextEvaluator.Error()
+}
+
+func (e *histogramQuantileEvaluator) Explain(parent Node) {
+	b := parent.Childf("%v HistogramQuantile", e.expr.Quantile)
+	e.nextEvaluator.Explain(b)
+}
+
+// histogramQuantile returns the φ-quantile of the values counted by the
+// cumulative buckets, interpolating linearly within the bucket holding it.
+// Like in Prometheus, the result is NaN without a `+Inf` bucket, and the
+// upper boundary of the last finite bucket if the quantile is in the `+Inf`
+// bucket.
+func histogramQuantile(q float64, buckets []histogramBucketCount) float64 {
+	switch {
+	case math.IsNaN(q):
+		return math.NaN()
+	case q < 0:
+		return math.Inf(-1)
+	case q > 1:
+		return math.Inf(1)
+	}
+
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].upper < buckets[j].upper })
+	if len(buckets) < 2 || !math.IsInf(buckets[len(buckets)-1].upper, 1) {
+		return math.NaN()
+	}
+	// Counts must not decrease, which may happen when aggregating buckets
+	// evaluated separately.
+	for i := 1; i < len(buckets); i++ {
+		buckets[i].count = max(buckets[i].count, buckets[i-1].count)
+	}
+	total := buckets[len(buckets)-1].count
+	if total == 0 {
+		return math.NaN()
+	}
+
+	rank := q * total
+	i := sort.Search(len(buckets)-1, func(i int) bool { return buckets[i].count >= rank })
+	if i == len(buckets)-1 {
+		return buckets[len(buckets)-2].upper
+	}
+
+	upper, lower, below := buckets[i].upper, histogramLowerBound(buckets[i].upper), 0.0
+	if i > 0 {
+		lower = max(lower, buckets[i-1].upper)
+		below = buckets[i-1].count
+	}
+	if buckets[i].count == below {
+		return upper
+	}
+	return lower + (upper-lower)*(rank-below)/(buckets[i].count-below)
+}
@@ -0,0 +1,194 @@
+package logql
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/iter"
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+func TestHistogramBucketUpperBound(t *testing.T) {
+	for _, tc := range []struct {
+		value    float64
+		expected float64
+	}{
+		{1, 1},
+		{1.05, math.Exp2(1.0 / 8)},
+		{0.95, 1},
+		{0.5, 0.5},
+		{2, 2},
+		{3, math.Exp2(13.0 / 8)},
+		{0, 0},
+		{-1, -math.Exp2(-1.0 / 8)},
+		{-2, -math.Exp2(7.0 / 8)},
+	} {
+		le := histogramBucketUpperBound(tc.value)
+		if tc.value == 0 {
+			require.Equal(t, tc.expected, le)
+			continue
+		}
+		require.InEpsilon(t, tc.expected, le, 1e-9, "value %v", tc.value)
+		// The value is within the bucket, whose boundary closest to zero is exclusive.
+		if tc.value > 0 {
+			require.Less(t, histogramLowerBound(le), tc.value, "value %v", tc.value)
+			require.GreaterOrEqual(t, le, tc.value, "value %v", tc.value)
+		} else {
+			require.LessOrEqual(t, histogramLowerBound(le), tc.value, "value %v", tc.value)
+			require.Greater(t, le, tc.value, "value %v", tc.value)
+		}
+	}
+}
+
+func TestHistogramQuantile(t *testing.T) {
+	buckets := func() []histogramBucketCount {
+		return []histogramBucketCount{
+			{upper: 4, count: 4},
+			{upper: math.Inf(1), count: 5},
+			{upper: 1, count: 1},
+			{upper: 2, count: 2},
+		}
+	}
+
+	require.Equal(t, 1.0, histogramQuantile(0.2, buckets()))
+	require.Equal(t, 2.0, histogramQuantile(0.4, buckets()))
+	// The lower boundary of a bucket is the one of its native histogram bucket.
+	require.InDelta(t, 4/histogramGrowth+(4-4/histogramGrowth)/2, histogramQuantile(0.6, buckets()), 1e-9)
+	require.Equal(t, 4.0, histogramQuantile(0.8, buckets()))
+	// The quantile is in the +Inf bucket.
+	require.Equal(t, 4.0, histogramQuantile(1, buckets()))
+	require.Equal(t, math.Inf(-1), histogramQuantile(-1, buckets()))
+	require.Equal(t, math.Inf(1), histogramQuantile(2, buckets()))
+	require.True(t, math.IsNaN(histogramQuantile(0.5, nil)))
+	// The +Inf bucket is required.
+	require.True(t, math.IsNaN(histogramQuantile(0.5, buckets()[:1])))
+	require.True(t, math.IsNaN(histogramQuantile(0.5, []histogramBucketCount{{upper: 1}, {upper: math.Inf(1)}})))
+}
+
+func TestHistogramCumulateEvaluator(t *testing.T) {
+	bucket := func(app, le string, count float64) promql.Sample {
+		return promql.Sample{Metric: labels.FromStrings("app", app, labels.BucketLabel, le), T: 1000, F: count}
+	}
+	ev := newHistogramCumulateEvaluator(NewVectorStepEvaluator(time.Unix(1, 0), promql.Vector{
+		// The buckets of foo were returned by two shards.
+		bucket("foo", "1", 2),
+		bucket("foo", "4", 1),
+		bucket("foo", "1", 3),
+		bucket("bar", "2", 4),
+		// Series without a bucket label are ignored.
+		{Metric: labels.FromStrings("app", "baz"), F: 1},
+	}))
+
+	next, _, r := ev.Next()
+	require.True(t, next)
+	// Every histogram gets the buckets of all histograms.
+	require.ElementsMatch(t, promql.Vector{
+		bucket("foo", "1", 5),
+		bucket("foo", "2", 5),
+		bucket("foo", "4", 6),
+		bucket("foo", "+Inf", 6),
+		bucket("bar", "1", 0),
+		bucket("bar", "2", 4),
+		bucket("bar", "4", 4),
+		bucket("bar", "+Inf", 4),
+	}, []promql.Sample(r.SampleVector()))
+	next, _, _ = ev.Next()
+	require.False(t, next)
+}
+
+func TestEngine_Histogram(t *testing.T) {
+	// one sample per second from 1s to 299s for each series, whose value is
+	// its timestamp for foo and twice its timestamp for bar.
+	foo, bar := logproto.Series{Labels: `{app="foo"}`}, logproto.Series{Labels: `{app="bar"}`}
+	var values []float64
+	for i := int64(1); i < 300; i++ {
+		foo.Samples = append(foo.Samples, logproto.Sample{Timestamp: time.Unix(i, 0).UnixNano(), Hash: uint64(i), Value: float64(i)})
+		bar.Samples = append(bar.Samples, logproto.Sample{Timestamp: time.Unix(i, 0).UnixNano(), Hash: uint64(i), Value: float64(2 * i)})
+		values = append(values, float64(i), float64(2*i))
+	}
+	sort.Float64s(values)
+	querier := errorIteratorQuerier{
+		samples: func() []iter.SampleIterator {
+			return []iter.SampleIterator{iter.NewSeriesIterator(foo), iter.NewSeriesIterator(bar)}
+		},
+	}
+	eng := NewEngine(EngineOpts{}, querier, NoLimits, log.NewNopLogger())
+	exec := func(t *testing.T, qs string) promql.Vector {
+		params, err := NewLiteralParams(qs, time.Unix(300, 0), time.Unix(300, 0), 0, 0, logproto.FORWARD, 0, nil, nil)
+		require.NoError(t, err)
+		res, err := eng.Query(params).Exec(user.InjectOrgID(context.Background(), "fake"))
+		require.NoError(t, err)
+		vec, ok := res.Data.(promql.Vector)
+		require.True(t, ok)
+		return vec
+	}
+
+	t.Run("buckets", func(t *testing.T) {
+		buckets := map[string][]histogramBucketCount{}
+		for _, s := range exec(t, `histogram_over_time({app=~"foo|bar"} | unwrap v [5m])`) {
+			le, err := strconv.ParseFloat(s.Metric.Get(labels.BucketLabel), 64)
+			require.NoError(t, err)
+			app := s.Metric.Get("app")
+			buckets[app] = append(buckets[app], histogramBucketCount{upper: le, count: s.F})
+		}
+		require.Len(t, buckets, 2)
+		require.Equal(t, len(buckets["foo"]), len(buckets["bar"]))
+
+		for app, counts := range buckets {
+			sort.Slice(counts, func(i, j int) bool { return counts[i].upper < counts[j].upper })
+			// The buckets are cumulative and end with +Inf, which counts all values.
+			last := counts[len(counts)-1]
+			require.True(t, math.IsInf(last.upper, 1), app)
+			require.Equal(t, 299.0, last.count, app)
+			for i := 1; i < len(counts); i++ {
+				require.GreaterOrEqual(t, counts[i].count, counts[i-1].count, app)
+			}
+		}
+		// No value of foo is greater than 299, while half of the values of bar are.
+		require.Equal(t, 299.0, buckets["foo"][len(buckets["foo"])-2].count)
+		for _, b := range buckets["bar"] {
+			if b.upper >= 299 && b.upper < 2*histogramGrowth*150 {
+				require.InDelta(t, 149, b.count, 20, "le=%v", b.upper)
+			}
+		}
+	})
+
+	for _, tc := range []struct {
+		qs       string
+		expected map[string]float64
+	}{
+		{
+			`histogram_quantile(0.5, sum by (le) (histogram_over_time({app=~"foo|bar"} | unwrap v [5m])))`,
+			map[string]float64{"": values[len(values)/2]},
+		},
+		{
+			`histogram_quantile(0.99, sum by (le) (histogram_over_time({app=~"foo|bar"} | unwrap v [5m])))`,
+			map[string]float64{"": values[len(values)*99/100]},
+		},
+		{
+			`histogram_quantile(0.9, histogram_over_time({app=~"foo|bar"} | unwrap v [5m]) by (app))`,
+			map[string]float64{"foo": 269, "bar": 538},
+		},
+	} {
+		t.Run(tc.qs, func(t *testing.T) {
+			vec := exec(t, tc.qs)
+			require.Len(t, vec, len(tc.expected))
+			for _, s := range vec {
+				expected, ok := tc.expected[s.Metric.Get("app")]
+				require.True(t, ok, s.Metric.String())
+				// The error is bounded by the width of the buckets.
+				require.InEpsilon(t, expected, s.F, histogramGrowth-1, s.Metric.String())
+			}
+		})
+	}
+}
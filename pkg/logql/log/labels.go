@@ -138,6 +138,10 @@ type LabelsBuilder struct {
 	currentResult LabelsResult
 	groupedResult LabelsResult
 
+	// streamHash is the stable hash of base, computed on first use.
+	streamHash    uint64
+	hasStreamHash bool
+
 	*BaseLabelsBuilder
 }
 
@@ -191,6 +195,16 @@ func (b *BaseLabelsBuilder) ForLabels(lbs labels.Labels, hash uint64) *LabelsBui
 	return res
 }
 
+// StreamHash returns a hash of the stream labels which is stable across
+// processes.
+func (b *LabelsBuilder) StreamHash() uint64 {
+	if !b.hasStreamHash {
+		b.streamHash = labels.StableHash(b.base)
+		b.hasStreamHash = true
+	}
+	return b.streamHash
+}
+
 // Reset clears all current state for the builder.
 func (b *BaseLabelsBuilder) Reset() {
 	b.del = b.del[:0]
@@ -0,0 +1,137 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// LookupTables provides the lookup tables of the tenant of a request.
+type LookupTables interface {
+	// LookupTable returns the given version of a lookup table. An empty
+	// version returns the latest one.
+	LookupTable(ctx context.Context, name, version string) (*LookupTable, error)
+}
+
+// LookupVersionLength is the number of hexadecimal characters of the version
+// of a lookup table.
+const LookupVersionLength = 16
+
+// ValidLookupVersion returns whether v is a well-formed lookup table version.
+func ValidLookupVersion(v string) bool {
+	if len(v) != LookupVersionLength {
+		return false
+	}
+	for _, c := range v {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// LookupTable is an immutable version of a static table used to enrich log
+// lines with the columns of the row matching one of their labels.
+type LookupTable struct {
+	Name    string
+	Version string
+	Columns []string
+	Rows    [][]string
+
+	mtx     sync.Mutex
+	indexes map[string]map[string][]string
+}
+
+// NewLookupTable creates a lookup table. Every row must have a value for each
+// column.
+func NewLookupTable(name, version string, columns []string, rows [][]string) *LookupTable {
+	return &LookupTable{
+		Name:    name,
+		Version: version,
+		Columns: columns,
+		Rows:    rows,
+		indexes: map[string]map[string][]string{},
+	}
+}
+
+// index returns the rows of the table by the value of column. When several
+// rows have the same value, the first one wins.
+func (t *LookupTable) index(column string) (map[string][]string, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if idx, ok := t.indexes[column]; ok {
+		return idx, nil
+	}
+	pos := -1
+	for i, c := range t.Columns {
+		if c == column {
+			pos = i
+			break
+		}
+	}
+	if pos < 0 {
+		return nil, fmt.Errorf("lookup table %s has no column %s", t.Name, column)
+	}
+
+	idx := make(map[string][]string, len(t.Rows))
+	for _, row := range t.Rows {
+		if _, ok := idx[row[pos]]; !ok {
+			idx[row[pos]] = row
+		}
+	}
+	t.indexes[column] = idx
+	return idx, nil
+}
+
+// Lookup adds the columns of the row of a lookup table whose key column equals
+// the value of the label of the same name. Lines without such a row are left
+// unchanged.
+type Lookup struct {
+	key     string
+	columns []string
+	rows    map[string][]string
+}
+
+// NewLookup creates a stage looking up the label key in the column key of
+// table.
+func NewLookup(table *LookupTable, key string) (*Lookup, error) {
+	rows, err := table.index(key)
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]string, len(table.Columns))
+	for i, c := range table.Columns {
+		if c != key {
+			columns[i] = c
+		}
+	}
+	return &Lookup{
+		key:     key,
+		columns: columns,
+		rows:    rows,
+	}, nil
+}
+
+func (l *Lookup) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	value, ok := lbs.Get(l.key)
+	if !ok {
+		return line, true
+	}
+	row, ok := l.rows[value]
+	if !ok {
+		return line, true
+	}
+	for i, column := range l.columns {
+		if column == "" || row[i] == "" {
+			continue
+		}
+		if lbs.BaseHas(column) {
+			column = column + duplicateSuffix
+		}
+		lbs.Set(ParsedLabel, column, row[i])
+	}
+	return line, true
+}
+
+func (l *Lookup) RequiredLabelNames() []string { return []string{l.key} }
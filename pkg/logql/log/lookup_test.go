@@ -0,0 +1,64 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Lookup(t *testing.T) {
+	table := NewLookupTable("customers", "0f3a0f3a0f3a0f3a", []string{"customer_id", "tier", "region"}, [][]string{
+		{"1", "gold", "eu"},
+		{"2", "silver", ""},
+		{"1", "bronze", "us"},
+	})
+
+	for _, tc := range []struct {
+		name string
+		lbs  labels.Labels
+		want labels.Labels
+	}{
+		{
+			"match",
+			labels.FromStrings("app", "foo", "customer_id", "2"),
+			labels.FromStrings("app", "foo", "customer_id", "2", "tier", "silver"),
+		},
+		{
+			"first row wins",
+			labels.FromStrings("app", "foo", "customer_id", "1"),
+			labels.FromStrings("app", "foo", "customer_id", "1", "region", "eu", "tier", "gold"),
+		},
+		{
+			"duplicate label",
+			labels.FromStrings("app", "foo", "customer_id", "1", "region", "eu-west-1"),
+			labels.FromStrings("app", "foo", "customer_id", "1", "region", "eu-west-1", "region_extracted", "eu", "tier", "gold"),
+		},
+		{
+			"no row",
+			labels.FromStrings("app", "foo", "customer_id", "3"),
+			labels.FromStrings("app", "foo", "customer_id", "3"),
+		},
+		{
+			"no key",
+			labels.FromStrings("app", "foo"),
+			labels.FromStrings("app", "foo"),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			lookup, err := NewLookup(table, "customer_id")
+			require.NoError(t, err)
+			require.Equal(t, []string{"customer_id"}, lookup.RequiredLabelNames())
+
+			lbls := NewBaseLabelsBuilder().ForLabels(tc.lbs, labels.StableHash(tc.lbs))
+			lbls.Reset()
+			line, ok := lookup.Process(0, []byte("line"), lbls)
+			require.True(t, ok)
+			require.Equal(t, "line", string(line))
+			require.Equal(t, tc.want, lbls.LabelsResult().Labels())
+		})
+	}
+
+	_, err := NewLookup(table, "team")
+	require.EqualError(t, err, "lookup table customers has no column team")
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/cespare/xxhash/v2"
 	"github.com/pkg/errors"
 	"github.com/prometheus/prometheus/model/labels"
 
@@ -16,6 +17,9 @@ const (
 	ConvertBytes    = "bytes"
 	ConvertDuration = "duration"
 	ConvertFloat    = "float"
+	// ConvertHash converts label values to a hash of the value, so that
+	// distinct values can be counted whatever their format.
+	ConvertHash = "hash"
 )
 
 // LineExtractor extracts a float64 from a log line.
@@ -156,6 +160,8 @@ func LabelExtractorWithStages(
 		convFn = convertDuration
 	case ConvertFloat:
 		convFn = convertFloat
+	case ConvertHash:
+		convFn = convertHash
 	default:
 		return nil, errors.Errorf("unsupported conversion operation %s", conversion)
 	}
@@ -313,10 +319,50 @@ func (sp *filteringStreamExtractor) ProcessString(ts int64, line string, structu
 	return sp.extractor.ProcessString(ts, line, structuredMetadata)
 }
 
+// NewStreamFilteringSampleExtractor creates a sample extractor dropping every
+// log line of the streams for which keep returns false.
+func NewStreamFilteringSampleExtractor(keep func(labels.Labels) bool, e SampleExtractor) SampleExtractor {
+	return &streamFilteringSampleExtractor{
+		keep:      keep,
+		extractor: e,
+	}
+}
+
+type streamFilteringSampleExtractor struct {
+	keep      func(labels.Labels) bool
+	extractor SampleExtractor
+}
+
+func (p *streamFilteringSampleExtractor) ForStream(labels labels.Labels) StreamSampleExtractor {
+	se := p.extractor.ForStream(labels)
+	if p.keep(labels) {
+		return se
+	}
+	return droppedStreamExtractor{se}
+}
+
+type droppedStreamExtractor struct {
+	StreamSampleExtractor
+}
+
+func (droppedStreamExtractor) Process(_ int64, _ []byte, _ labels.Labels) ([]ExtractedSample, bool) {
+	return nil, false
+}
+
+func (droppedStreamExtractor) ProcessString(_ int64, _ string, _ labels.Labels) ([]ExtractedSample, bool) {
+	return nil, false
+}
+
 func convertFloat(v string) (float64, error) {
 	return strconv.ParseFloat(v, 64)
 }
 
+// convertHash returns the 53 most significant bits of the hash of v, which are
+// exactly representable by a float64.
+func convertHash(v string) (float64, error) {
+	return float64(xxhash.Sum64String(v) >> 11), nil
+}
+
 func convertDuration(v string) (float64, error) {
 	d, err := time.ParseDuration(v)
 	if err != nil {
@@ -2,9 +2,14 @@ package log
 
 import (
 	"bytes"
+	"encoding/csv"
+	"encoding/xml"
 	"errors"
 	"fmt"
+	"io"
+	"strconv"
 	"strings"
+	"unicode"
 	"unicode/utf8"
 	"unsafe"
 
@@ -34,6 +39,9 @@ var (
 	_ Stage = &JSONParser{}
 	_ Stage = &RegexpParser{}
 	_ Stage = &LogfmtParser{}
+	_ Stage = &XMLParser{}
+	_ Stage = &XMLExpressionParser{}
+	_ Stage = &CSVParser{}
 
 	trueBytes = []byte("true")
 
@@ -41,6 +49,7 @@ var (
 	errMissingCapture       = errors.New("at least one named capture must be supplied")
 	errFoundAllLabels       = errors.New("found all required labels")
 	errLabelDoesNotMatch    = errors.New("found a label with a matcher that didn't match")
+	errNotXML               = errors.New("expecting xml element, but it is not")
 
 	// the rune error replacement is rejected by Prometheus hence replacing them with space.
 	removeInvalidUtf = func(r rune) rune {
@@ -831,3 +840,500 @@ func (u *UnpackParser) unpack(entry []byte, lbs *LabelsBuilder) ([]byte, error)
 	}
 	return entry, nil
 }
+
+// XMLParser extracts the text of every element and the value of every
+// attribute of an xml log line as labels. Like for json, the names of nested
+// elements are joined with an underscore starting from the root element, and
+// attributes are appended to the name of their element, e.g.
+// `<event id="1"><level>info</level></event>` yields event_id="1" and
+// event_level="info". Namespace prefixes are ignored.
+type XMLParser struct {
+	prefixBuffer          [][]byte
+	sanitizedPrefixBuffer []byte
+	text                  []byte
+	keys                  internedStringSet
+}
+
+// NewXMLParser creates a log stage that can parse a xml log line and add elements and attributes as labels.
+func NewXMLParser() *XMLParser {
+	return &XMLParser{
+		keys:                  internedStringSet{},
+		sanitizedPrefixBuffer: make([]byte, 0, 64),
+	}
+}
+
+func (x *XMLParser) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	parserHints := lbs.ParserLabelHints()
+	if parserHints.NoLabels() {
+		return line, true
+	}
+	if !isValidXMLStart(line) {
+		addErrLabel(errXML, errNotXML, lbs)
+		return line, true
+	}
+
+	// reset the state.
+	x.prefixBuffer = x.prefixBuffer[:0]
+
+	dec := xml.NewDecoder(bytes.NewReader(line))
+	leaf, hasAttrs := false, false
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return line, true
+		}
+		if err != nil {
+			addErrLabel(errXML, err, lbs)
+			return line, true
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			x.prefixBuffer = append(x.prefixBuffer, []byte(t.Name.Local))
+			if !parserHints.ShouldExtractPrefix(string(x.buildSanitizedPrefixFromBuffer())) {
+				if err := dec.Skip(); err != nil {
+					addErrLabel(errXML, err, lbs)
+					return line, true
+				}
+				x.prefixBuffer = x.prefixBuffer[:len(x.prefixBuffer)-1]
+				leaf = false
+				continue
+			}
+			leaf, hasAttrs = true, false
+			x.text = x.text[:0]
+
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns") {
+					continue
+				}
+				hasAttrs = true
+				if !x.setLabel([]byte(attr.Name.Local), attr.Value, lbs) {
+					return line, false
+				}
+			}
+		case xml.CharData:
+			if leaf {
+				x.text = append(x.text, t...)
+			}
+		case xml.EndElement:
+			// elements holding only attributes are not extracted.
+			if text := bytes.TrimSpace(x.text); leaf && (len(text) > 0 || !hasAttrs) {
+				if !x.setLabel(nil, string(text), lbs) {
+					return line, false
+				}
+			}
+			leaf = false
+			x.prefixBuffer = x.prefixBuffer[:len(x.prefixBuffer)-1]
+		}
+
+		if parserHints.AllRequiredExtracted() {
+			return line, true
+		}
+	}
+}
+
+// setLabel sets the label named after the current element, and the given
+// attribute if any. It returns false if the line can be dropped because the
+// label does not match the filters of the query.
+func (x *XMLParser) setLabel(attr []byte, value string, lbs *LabelsBuilder) bool {
+	prefixLen := len(x.prefixBuffer)
+	if attr != nil {
+		x.prefixBuffer = append(x.prefixBuffer, attr)
+	}
+	sanitized := x.buildSanitizedPrefixFromBuffer()
+	x.prefixBuffer = x.prefixBuffer[:prefixLen]
+
+	key, ok := x.keys.Get(sanitized, func() (string, bool) {
+		field := string(sanitized)
+		if lbs.BaseHas(field) {
+			field = field + duplicateSuffix
+		}
+		if !lbs.ParserLabelHints().ShouldExtract(field) {
+			return "", false
+		}
+		return field, true
+	})
+	if !ok || lbs.ParserLabelHints().Extracted(key) {
+		return true
+	}
+
+	if bytes.ContainsRune(unsafeGetBytes(value), utf8.RuneError) {
+		value = strings.Map(removeInvalidUtf, value)
+	}
+	lbs.Set(ParsedLabel, key, value)
+	return lbs.ParserLabelHints().ShouldContinueParsingLine(key, lbs)
+}
+
+func (x *XMLParser) buildSanitizedPrefixFromBuffer() []byte {
+	x.sanitizedPrefixBuffer = x.sanitizedPrefixBuffer[:0]
+
+	for _, part := range x.prefixBuffer {
+		if len(x.sanitizedPrefixBuffer) > 0 {
+			x.sanitizedPrefixBuffer = append(x.sanitizedPrefixBuffer, byte(jsonSpacer))
+		}
+		x.sanitizedPrefixBuffer = appendSanitized(x.sanitizedPrefixBuffer, part)
+	}
+
+	return x.sanitizedPrefixBuffer
+}
+
+func (x *XMLParser) RequiredLabelNames() []string { return []string{} }
+
+func isValidXMLStart(data []byte) bool {
+	data = bytes.TrimLeftFunc(data, unicode.IsSpace)
+	return len(data) > 0 && data[0] == '<'
+}
+
+// xmlPath is a parsed xml expression such as `event/data[2]/@name`.
+type xmlPath struct {
+	steps []xmlStep
+	attr  string
+}
+
+// xmlStep matches an element by name, and by position amongst its siblings of
+// the same name when index is positive.
+type xmlStep struct {
+	name  string
+	index int
+}
+
+// parseXMLPath parses a subset of XPath: a list of element names separated by
+// slashes, optionally followed by a 1-based position between brackets, and
+// optionally ending with an attribute prefixed by @. The name `*` matches any
+// element.
+func parseXMLPath(expr string) (xmlPath, error) {
+	var path xmlPath
+	parts := strings.Split(strings.TrimPrefix(expr, "/"), "/")
+	for i, part := range parts {
+		if part == "" {
+			return xmlPath{}, errors.New("empty element name")
+		}
+
+		if strings.HasPrefix(part, "@") {
+			if i == 0 || i != len(parts)-1 {
+				return xmlPath{}, fmt.Errorf("unexpected attribute %s", part)
+			}
+			path.attr = localXMLName(part[1:])
+			if path.attr == "" {
+				return xmlPath{}, errors.New("empty attribute name")
+			}
+			continue
+		}
+
+		step := xmlStep{name: part}
+		if open := strings.IndexByte(part, '['); open >= 0 {
+			if !strings.HasSuffix(part, "]") {
+				return xmlPath{}, fmt.Errorf("unterminated position in %s", part)
+			}
+			index, err := strconv.Atoi(part[open+1 : len(part)-1])
+			if err != nil || index < 1 {
+				return xmlPath{}, fmt.Errorf("invalid position in %s", part)
+			}
+			step = xmlStep{name: part[:open], index: index}
+		}
+		step.name = localXMLName(step.name)
+		if step.name == "" {
+			return xmlPath{}, errors.New("empty element name")
+		}
+		path.steps = append(path.steps, step)
+	}
+	return path, nil
+}
+
+func localXMLName(name string) string {
+	if i := strings.LastIndexByte(name, ':'); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// xmlElement is an element of the current branch of a xml document.
+type xmlElement struct {
+	name     string
+	index    int
+	children map[string]int
+}
+
+func (p xmlPath) matches(branch []xmlElement) bool {
+	if len(p.steps) != len(branch) {
+		return false
+	}
+	for i, step := range p.steps {
+		if step.name != "*" && step.name != branch[i].name {
+			return false
+		}
+		if step.index > 0 && step.index != branch[i].index {
+			return false
+		}
+	}
+	return true
+}
+
+type XMLExpressionParser struct {
+	ids   []string
+	paths []xmlPath
+	keys  internedStringSet
+
+	branch  []xmlElement
+	values  []string
+	found   []bool
+	texts   [][]byte
+	reading []bool
+}
+
+// NewXMLExpressionParser creates a parser that extracts the elements and
+// attributes selected by the given XPath-like expressions.
+func NewXMLExpressionParser(expressions []LabelExtractionExpr) (*XMLExpressionParser, error) {
+	if len(expressions) == 0 {
+		return nil, fmt.Errorf("no xml expression provided")
+	}
+
+	var ids []string
+	var paths []xmlPath
+	for _, exp := range expressions {
+		path, err := parseXMLPath(exp.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse expression [%s]: %w", exp.Expression, err)
+		}
+
+		if !model.LabelName(exp.Identifier).IsValid() {
+			return nil, fmt.Errorf("invalid extracted label name '%s'", exp.Identifier)
+		}
+
+		ids = append(ids, exp.Identifier)
+		paths = append(paths, path)
+	}
+
+	return &XMLExpressionParser{
+		ids:     ids,
+		paths:   paths,
+		keys:    internedStringSet{},
+		values:  make([]string, len(ids)),
+		found:   make([]bool, len(ids)),
+		texts:   make([][]byte, len(ids)),
+		reading: make([]bool, len(ids)),
+	}, nil
+}
+
+func (x *XMLExpressionParser) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	if len(line) == 0 || lbs.ParserLabelHints().NoLabels() {
+		return line, true
+	}
+
+	if !isValidXMLStart(line) {
+		addErrLabel(errXML, errNotXML, lbs)
+		return line, true
+	}
+
+	if err := x.extract(line); err != nil {
+		addErrLabel(errXML, err, lbs)
+	}
+
+	// Ensure there's a label for every value
+	for i, identifier := range x.ids {
+		key, _ := x.keys.Get(unsafeGetBytes(identifier), func() (string, bool) {
+			if lbs.BaseHas(identifier) {
+				identifier = identifier + duplicateSuffix
+			}
+			return identifier, true
+		})
+		lbs.Set(ParsedLabel, key, x.values[i])
+	}
+
+	return line, true
+}
+
+// extract stores the value of every expression in values, stopping as soon as
+// all the expressions have been found.
+func (x *XMLExpressionParser) extract(line []byte) error {
+	x.branch = x.branch[:0]
+	for i := range x.ids {
+		x.values[i] = ""
+		x.found[i] = false
+		x.reading[i] = false
+		x.texts[i] = x.texts[i][:0]
+	}
+	remaining := len(x.ids)
+
+	dec := xml.NewDecoder(bytes.NewReader(line))
+	for remaining > 0 {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			element := xmlElement{name: t.Name.Local, index: 1}
+			if len(x.branch) > 0 {
+				parent := &x.branch[len(x.branch)-1]
+				if parent.children == nil {
+					parent.children = map[string]int{}
+				}
+				parent.children[element.name]++
+				element.index = parent.children[element.name]
+			}
+			x.branch = append(x.branch, element)
+
+			for i, path := range x.paths {
+				if x.found[i] || !path.matches(x.branch) {
+					continue
+				}
+				if path.attr == "" {
+					x.reading[i] = true
+					continue
+				}
+				for _, attr := range t.Attr {
+					if attr.Name.Local == path.attr {
+						x.values[i] = attr.Value
+						x.found[i] = true
+						remaining--
+						break
+					}
+				}
+			}
+		case xml.CharData:
+			for i := range x.paths {
+				if x.reading[i] && len(x.branch) == len(x.paths[i].steps) {
+					x.texts[i] = append(x.texts[i], t...)
+				}
+			}
+		case xml.EndElement:
+			for i := range x.paths {
+				if x.reading[i] && len(x.branch) == len(x.paths[i].steps) {
+					x.values[i] = string(bytes.TrimSpace(x.texts[i]))
+					x.reading[i] = false
+					x.found[i] = true
+					remaining--
+				}
+			}
+			x.branch = x.branch[:len(x.branch)-1]
+		}
+	}
+	return nil
+}
+
+func (x *XMLExpressionParser) RequiredLabelNames() []string { return []string{} }
+
+// CSVParser extracts the fields of a csv log line as labels named after the
+// given columns. A column with an empty name is skipped. Without columns, the
+// fields are named after their position: column_1, column_2, etc.
+type CSVParser struct {
+	columns []string
+	keys    internedStringSet
+}
+
+// NewCSVParser creates a parser that can extract labels from a csv log line.
+func NewCSVParser(columns []string) (*CSVParser, error) {
+	for _, c := range columns {
+		if c != "" && !model.LabelName(c).IsValid() {
+			return nil, fmt.Errorf("invalid column name '%s'", c)
+		}
+	}
+	return &CSVParser{
+		columns: columns,
+		keys:    internedStringSet{},
+	}, nil
+}
+
+func (c *CSVParser) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	parserHints := lbs.ParserLabelHints()
+	if parserHints.NoLabels() || len(line) == 0 {
+		return line, true
+	}
+
+	r := csv.NewReader(bytes.NewReader(line))
+	r.FieldsPerRecord = -1
+	record, err := r.Read()
+	if err != nil {
+		addErrLabel(errCSV, err, lbs)
+		return line, true
+	}
+
+	for i, value := range record {
+		column := c.column(i)
+		if column == "" {
+			if len(c.columns) > 0 && i >= len(c.columns) {
+				break
+			}
+			continue
+		}
+
+		key, ok := c.keys.Get(unsafeGetBytes(column), func() (string, bool) {
+			if lbs.BaseHas(column) {
+				column = column + duplicateSuffix
+			}
+			if !parserHints.ShouldExtract(column) {
+				return "", false
+			}
+			return column, true
+		})
+		if !ok || parserHints.Extracted(key) {
+			continue
+		}
+
+		if strings.ContainsRune(value, utf8.RuneError) {
+			value = strings.Map(removeInvalidUtf, value)
+		}
+
+		lbs.Set(ParsedLabel, key, value)
+		if !parserHints.ShouldContinueParsingLine(key, lbs) {
+			return line, false
+		}
+
+		if parserHints.AllRequiredExtracted() {
+			break
+		}
+	}
+
+	return line, true
+}
+
+// column returns the name of the i-th column, or an empty string if the
+// column must be skipped.
+func (c *CSVParser) column(i int) string {
+	if len(c.columns) == 0 {
+		return "column_" + strconv.Itoa(i+1)
+	}
+	if i < len(c.columns) {
+		return c.columns[i]
+	}
+	return ""
+}
+
+func (c *CSVParser) RequiredLabelNames() []string { return []string{} }
+
+// DetectParser processes the line with the first of the json, xml, logfmt and
+// csv parsers able to parse it, and returns the name of this parser.
+func DetectParser(line []byte, lbls *LabelsBuilder) (string, bool) {
+	if _, ok := NewJSONParser(true).Process(0, line, lbls); ok && !lbls.HasErr() {
+		return "json", true
+	}
+	lbls.Reset()
+
+	if _, ok := NewXMLParser().Process(0, line, lbls); ok && !lbls.HasErr() {
+		return "xml", true
+	}
+	lbls.Reset()
+
+	if _, ok := NewLogfmtParser(false, false).Process(0, line, lbls); !ok || lbls.HasErr() {
+		return "", false
+	}
+	// csv lines are parsed as logfmt lines without any key-value pair.
+	if len(lbls.UnsortedLabels(nil, ParsedLabel)) > 0 || !bytes.ContainsRune(line, ',') {
+		return "logfmt", true
+	}
+	lbls.Reset()
+
+	csvParser, err := NewCSVParser(nil)
+	if err != nil {
+		return "", false
+	}
+	if _, ok := csvParser.Process(0, line, lbls); !ok || lbls.HasErr() {
+		return "", false
+	}
+	return "csv", true
+}
@@ -43,6 +43,10 @@ var (
 	}`)
 
 	logfmtLine = []byte(`ts=2021-02-02T14:35:05.983992774Z caller=spanlogger.go:79 org_id=3677 traceID=2e5c7234b8640997 Ingester.TotalReached=15 Ingester.TotalChunksMatched=0 Ingester.TotalBatches=0`)
+
+	xmlLine = []byte(`<request protocol="HTTP/2.0"><remote_user>foo</remote_user><response><status>204</status><latency_seconds>30.001</latency_seconds></response></request>`)
+
+	csvLine = []byte(`foo,10.0.0.1:80,HTTP/2.0,204,30.001`)
 )
 
 func Test_ParserHints(t *testing.T) {
@@ -224,6 +228,34 @@ func Test_ParserHints(t *testing.T) {
 			[]float64{1.0},
 			[]string{"{app=\"nginx\", message_message=\"foo\"}"},
 		},
+		{
+			`sum by (request_protocol) (count_over_time({app="nginx"} | xml | request_response_status = 204 [1m]))`,
+			xmlLine,
+			true,
+			[]float64{1.0},
+			[]string{`{request_protocol="HTTP/2.0"}`},
+		},
+		{
+			`sum(count_over_time({app="nginx"} | xml | request_response_status = 200 [1m]))`,
+			xmlLine,
+			false,
+			[]float64{0},
+			[]string{""},
+		},
+		{
+			`sum by (remote_user) (count_over_time({app="nginx"} | csv "remote_user", "upstream_addr", "protocol", "status" | status = 204 [1m]))`,
+			csvLine,
+			true,
+			[]float64{1.0},
+			[]string{`{remote_user="foo"}`},
+		},
+		{
+			`sum by (column_1) (count_over_time({app="nginx"} | csv | column_4 = 500 [1m]))`,
+			csvLine,
+			false,
+			[]float64{0},
+			[]string{""},
+		},
 	} {
 		t.Run(tt.expr, func(t *testing.T) {
 			t.Parallel()
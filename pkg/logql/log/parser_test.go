@@ -1575,6 +1575,324 @@ func TestXExpressionParserFailures(t *testing.T) {
 	}
 }
 
+func Test_xmlParser_Parse(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  []byte
+		lbs   labels.Labels
+		want  labels.Labels
+		hints ParserHint
+	}{
+		{
+			"elements and attributes",
+			[]byte(`<?xml version="1.0"?><event id="42" xmlns="urn:foo"><level>error</level><source host="web-1"><name> nginx </name></source></event>`),
+			labels.EmptyLabels(),
+			labels.FromStrings("event_id", "42",
+				"event_level", "error",
+				"event_source_host", "web-1",
+				"event_source_name", "nginx",
+			),
+			NoParserHints(),
+		},
+		{
+			"namespaces and sanitized names",
+			[]byte(`<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope"><soap:Body><m:status-code xmlns:m="urn:m">500</m:status-code></soap:Body></soap:Envelope>`),
+			labels.EmptyLabels(),
+			labels.FromStrings("Envelope_Body_status_code", "500"),
+			NoParserHints(),
+		},
+		{
+			"duplicates",
+			[]byte(`<app>bar</app>`),
+			labels.FromStrings("app", "foo"),
+			labels.FromStrings("app", "foo",
+				"app_extracted", "bar",
+			),
+			NoParserHints(),
+		},
+		{
+			"not xml",
+			[]byte(`level=info`),
+			labels.FromStrings("foo", "bar"),
+			labels.FromStrings("foo", "bar",
+				"__error__", "XMLParserErr",
+				"__error_details__", "expecting xml element, but it is not",
+			),
+			NoParserHints(),
+		},
+		{
+			"invalid xml",
+			[]byte(`<event><level>info</event>`),
+			labels.FromStrings("foo", "bar"),
+			labels.FromStrings("foo", "bar",
+				"__error__", "XMLParserErr",
+				"__error_details__", "XML syntax error on line 1: element <level> closed by </event>",
+			),
+			NoParserHints(),
+		},
+		{
+			"hints",
+			[]byte(`<event id="42"><level>error</level><source host="web-1"><name>nginx</name></source></event>`),
+			labels.EmptyLabels(),
+			labels.FromStrings("event_source_name", "nginx"),
+			NewParserHint([]string{"event_source_name"}, []string{"event_source_name"}, false, false, "", nil),
+		},
+	}
+	for _, tt := range tests {
+		x := NewXMLParser()
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBaseLabelsBuilderWithGrouping(nil, tt.hints, false, false).ForLabels(tt.lbs, labels.StableHash(tt.lbs))
+			b.Reset()
+			_, _ = x.Process(0, tt.line, b)
+			require.Equal(t, tt.want, b.LabelsResult().Labels())
+		})
+	}
+}
+
+func TestXMLExpressionParser(t *testing.T) {
+	testLine := []byte(`<Event xmlns="http://schemas.microsoft.com/win/2004/08/events/event">
+  <System>
+    <Provider Name="Service Control Manager"/>
+    <EventID>7036</EventID>
+    <Level>4</Level>
+  </System>
+  <EventData>
+    <Data Name="param1">Windows Update</Data>
+    <Data Name="param2">stopped</Data>
+  </EventData>
+</Event>`)
+
+	tests := []struct {
+		name        string
+		line        []byte
+		expressions []LabelExtractionExpr
+		lbs         labels.Labels
+		want        labels.Labels
+	}{
+		{
+			"single element",
+			testLine,
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("id", "/Event/System/EventID"),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("id", "7036"),
+		},
+		{
+			"elements and attributes",
+			testLine,
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("level", "Event/System/Level"),
+				NewLabelExtractionExpr("provider", "Event/System/Provider/@Name"),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("level", "4",
+				"provider", "Service Control Manager",
+			),
+		},
+		{
+			"positions",
+			testLine,
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("first", "Event/EventData/Data"),
+				NewLabelExtractionExpr("second", "Event/EventData/Data[2]"),
+				NewLabelExtractionExpr("second_name", "Event/*/Data[2]/@Name"),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("first", "Windows Update",
+				"second", "stopped",
+				"second_name", "param2",
+			),
+		},
+		{
+			"expression matching nothing",
+			testLine,
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("nope", "Event/System/Nope"),
+				NewLabelExtractionExpr("missing_attr", "Event/System/Level/@Name"),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("missing_attr", "",
+				"nope", "",
+			),
+		},
+		{
+			"label override",
+			testLine,
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("level", "Event/System/Level"),
+			},
+			labels.FromStrings("level", "info"),
+			labels.FromStrings("level", "info",
+				"level_extracted", "4",
+			),
+		},
+		{
+			"not xml",
+			[]byte(`{"level":"info"}`),
+			[]LabelExtractionExpr{
+				NewLabelExtractionExpr("level", "Event/System/Level"),
+			},
+			labels.EmptyLabels(),
+			labels.FromStrings("__error__", "XMLParserErr",
+				"__error_details__", "expecting xml element, but it is not",
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, err := NewXMLExpressionParser(tt.expressions)
+			require.NoError(t, err)
+			b := NewBaseLabelsBuilder().ForLabels(tt.lbs, labels.StableHash(tt.lbs))
+			b.Reset()
+			_, _ = x.Process(0, tt.line, b)
+			require.Equal(t, tt.want, b.LabelsResult().Labels())
+		})
+	}
+}
+
+func TestXMLExpressionParserFailures(t *testing.T) {
+	tests := []struct {
+		expression string
+		error      string
+	}{
+		{"", "empty element name"},
+		{"a//b", "empty element name"},
+		{"@id", "unexpected attribute @id"},
+		{"a/@id/b", "unexpected attribute @id"},
+		{"a/@", "empty attribute name"},
+		{"a[1", "unterminated position in a[1"},
+		{"a[0]", "invalid position in a[0]"},
+		{"a[b]", "invalid position in a[b]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			_, err := NewXMLExpressionParser([]LabelExtractionExpr{NewLabelExtractionExpr("foo", tt.expression)})
+			require.EqualError(t, err, fmt.Sprintf("cannot parse expression [%s]: %s", tt.expression, tt.error))
+		})
+	}
+}
+
+func Test_csvParser_Parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		columns []string
+		line    []byte
+		lbs     labels.Labels
+		want    labels.Labels
+		hints   ParserHint
+	}{
+		{
+			"columns",
+			[]string{"ts", "job", "status", "message"},
+			[]byte(`2024-01-01T00:00:00Z,backup,failed,"disk full, aborting"`),
+			labels.EmptyLabels(),
+			labels.FromStrings("ts", "2024-01-01T00:00:00Z",
+				"job", "backup",
+				"status", "failed",
+				"message", "disk full, aborting",
+			),
+			NoParserHints(),
+		},
+		{
+			"skipped and missing columns",
+			[]string{"", "job", "status", "message"},
+			[]byte(`2024-01-01T00:00:00Z,backup,failed`),
+			labels.EmptyLabels(),
+			labels.FromStrings("job", "backup",
+				"status", "failed",
+			),
+			NoParserHints(),
+		},
+		{
+			"extra fields",
+			[]string{"ts", "job"},
+			[]byte(`2024-01-01T00:00:00Z,backup,failed`),
+			labels.EmptyLabels(),
+			labels.FromStrings("ts", "2024-01-01T00:00:00Z",
+				"job", "backup",
+			),
+			NoParserHints(),
+		},
+		{
+			"positional columns",
+			nil,
+			[]byte(`backup,failed`),
+			labels.EmptyLabels(),
+			labels.FromStrings("column_1", "backup",
+				"column_2", "failed",
+			),
+			NoParserHints(),
+		},
+		{
+			"duplicates",
+			[]string{"job"},
+			[]byte(`backup`),
+			labels.FromStrings("job", "csv"),
+			labels.FromStrings("job", "csv",
+				"job_extracted", "backup",
+			),
+			NoParserHints(),
+		},
+		{
+			"invalid csv",
+			[]string{"job"},
+			[]byte(`"backup`),
+			labels.FromStrings("foo", "bar"),
+			labels.FromStrings("foo", "bar",
+				"__error__", "CSVParserErr",
+				"__error_details__", "parse error on line 1, column 8: extraneous or missing \" in quoted-field",
+			),
+			NoParserHints(),
+		},
+		{
+			"hints",
+			[]string{"ts", "job", "status"},
+			[]byte(`2024-01-01T00:00:00Z,backup,failed`),
+			labels.EmptyLabels(),
+			labels.FromStrings("status", "failed"),
+			NewParserHint([]string{"status"}, []string{"status"}, false, false, "", nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewCSVParser(tt.columns)
+			require.NoError(t, err)
+			b := NewBaseLabelsBuilderWithGrouping(nil, tt.hints, false, false).ForLabels(tt.lbs, labels.StableHash(tt.lbs))
+			b.Reset()
+			_, _ = c.Process(0, tt.line, b)
+			require.Equal(t, tt.want, b.LabelsResult().Labels())
+		})
+	}
+}
+
+func TestDetectParser(t *testing.T) {
+	for _, tc := range []struct {
+		line     string
+		parser   string
+		detected bool
+		want     labels.Labels
+	}{
+		{`{"job":"backup"}`, "json", true, labels.FromStrings("job", "backup")},
+		{`<job name="backup"/>`, "xml", true, labels.FromStrings("job_name", "backup")},
+		{`job=backup status=failed`, "logfmt", true, labels.FromStrings("job", "backup", "status", "failed")},
+		{`backup,failed`, "csv", true, labels.FromStrings("column_1", "backup", "column_2", "failed")},
+		{`backup failed`, "logfmt", true, labels.EmptyLabels()},
+		{`"backup,failed`, "", false, labels.EmptyLabels()},
+	} {
+		t.Run(tc.line, func(t *testing.T) {
+			b := NewBaseLabelsBuilder().ForLabels(labels.EmptyLabels(), 0)
+			b.Reset()
+			parser, ok := DetectParser([]byte(tc.line), b)
+			require.Equal(t, tc.detected, ok)
+			require.Equal(t, tc.parser, parser)
+			if ok {
+				require.Equal(t, tc.want, b.LabelsResult().Labels())
+			}
+		})
+	}
+}
+
 func Test_unpackParser_Parse(t *testing.T) {
 	tests := []struct {
 		name string
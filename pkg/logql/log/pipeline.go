@@ -241,6 +241,25 @@ func (p *streamPipeline) ProcessString(ts int64, line string, structuredMetadata
 
 func (p *streamPipeline) BaseLabels() LabelsResult { return p.builder.currentResult }
 
+// LabelFiltersBeforeParser returns the label filters which are applied before
+// any stage that can add, remove or modify labels. Lines are only returned by
+// the pipeline if they pass all of them, so they can be used to skip data
+// which can't match, like chunk blocks with structured metadata statistics.
+func (p *streamPipeline) LabelFiltersBeforeParser() []LabelFilterer {
+	var filters []LabelFilterer
+	for _, s := range p.stages {
+		switch s := s.(type) {
+		case LabelFilterer:
+			filters = append(filters, s)
+		case StageFunc, *noopStage:
+			// Line filters don't modify labels.
+		default:
+			return filters
+		}
+	}
+	return filters
+}
+
 // PipelineFilter contains a set of matchers and a pipeline that, when matched,
 // causes an entry from a log stream to be skipped. Matching entries must also
 // fall between 'start' and 'end', inclusive
@@ -317,6 +336,15 @@ func (sp *filteringStreamPipeline) BaseLabels() LabelsResult {
 	return sp.pipeline.BaseLabels()
 }
 
+// LabelFiltersBeforeParser returns the label filters of the wrapped pipeline,
+// if it has any.
+func (sp *filteringStreamPipeline) LabelFiltersBeforeParser() []LabelFilterer {
+	if p, ok := sp.pipeline.(interface{ LabelFiltersBeforeParser() []LabelFilterer }); ok {
+		return p.LabelFiltersBeforeParser()
+	}
+	return nil
+}
+
 func (sp *filteringStreamPipeline) Process(ts int64, line []byte, structuredMetadata labels.Labels) ([]byte, LabelsResult, bool) {
 	for _, filter := range sp.filters {
 		if ts < filter.start || ts > filter.end {
@@ -347,6 +375,44 @@ func (sp *filteringStreamPipeline) ProcessString(ts int64, line string, structur
 	return sp.pipeline.ProcessString(ts, line, structuredMetadata)
 }
 
+// NewStreamFilteringPipeline creates a pipeline dropping every log line of the
+// streams for which keep returns false.
+func NewStreamFilteringPipeline(keep func(labels.Labels) bool, p Pipeline) Pipeline {
+	return &streamFilteringPipeline{
+		keep:     keep,
+		pipeline: p,
+	}
+}
+
+type streamFilteringPipeline struct {
+	keep     func(labels.Labels) bool
+	pipeline Pipeline
+}
+
+func (p *streamFilteringPipeline) ForStream(labels labels.Labels) StreamPipeline {
+	sp := p.pipeline.ForStream(labels)
+	if p.keep(labels) {
+		return sp
+	}
+	return droppedStreamPipeline{sp}
+}
+
+func (p *streamFilteringPipeline) Reset() {
+	p.pipeline.Reset()
+}
+
+type droppedStreamPipeline struct {
+	StreamPipeline
+}
+
+func (droppedStreamPipeline) Process(_ int64, _ []byte, _ labels.Labels) ([]byte, LabelsResult, bool) {
+	return nil, nil, false
+}
+
+func (droppedStreamPipeline) ProcessString(_ int64, _ string, _ labels.Labels) (string, LabelsResult, bool) {
+	return "", nil, false
+}
+
 // ReduceStages reduces multiple stages into one.
 func ReduceStages(stages []Stage) Stage {
 	if len(stages) == 0 {
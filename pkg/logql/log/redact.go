@@ -0,0 +1,176 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// Names of the built-in redaction detectors.
+const (
+	RedactCreditCard = "credit_card"
+	RedactEmail      = "email"
+	RedactJWT        = "jwt"
+	RedactAWSKey     = "aws_key"
+)
+
+type redactDetector struct {
+	name string
+	re   *regexp.Regexp
+	// hint is a substring every match contains, used to skip the regexp for most lines.
+	hint []byte
+	// valid reports whether a match must be redacted, for detectors whose regexp
+	// is not precise enough on its own.
+	valid       func(match []byte) bool
+	replacement []byte
+}
+
+var builtinRedactDetectors = map[string]*redactDetector{
+	RedactCreditCard: newRedactDetector(RedactCreditCard, regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`), "", luhnValid),
+	RedactEmail:      newRedactDetector(RedactEmail, regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`), "@", nil),
+	RedactJWT:        newRedactDetector(RedactJWT, regexp.MustCompile(`\beyJ[A-Za-z0-9_\-]+\.eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]*`), "eyJ", nil),
+	RedactAWSKey:     newRedactDetector(RedactAWSKey, regexp.MustCompile(`\b(?:AKIA|ASIA|ABIA|ACCA)[A-Z0-9]{16}\b`), "A", nil),
+}
+
+func newRedactDetector(name string, re *regexp.Regexp, hint string, valid func([]byte) bool) *redactDetector {
+	d := &redactDetector{
+		name:        name,
+		re:          re,
+		valid:       valid,
+		replacement: []byte(fmt.Sprintf("<redacted:%s>", name)),
+	}
+	if hint != "" {
+		d.hint = []byte(hint)
+	}
+	return d
+}
+
+// RedactDetectors returns the names of the built-in redaction detectors.
+func RedactDetectors() []string {
+	names := make([]string, 0, len(builtinRedactDetectors))
+	for name := range builtinRedactDetectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RedactionLimits provides the redaction config of tenants, so that queries
+// apply the same detectors as the ingestion.
+type RedactionLimits interface {
+	// QueryRedaction returns the redactor applied to the entries of the tenant
+	// at ingestion, nil if redaction is disabled, and the custom patterns of
+	// the tenant. It returns an error if the redaction config of the tenant is
+	// invalid, so that its entries are never returned unredacted.
+	QueryRedaction(tenantID string) (*Redactor, map[string]string, error)
+}
+
+// Redactor replaces sensitive data detected in log lines with `<redacted:detector>`.
+// It is safe for concurrent use.
+type Redactor struct {
+	detectors []*redactDetector
+}
+
+// NewRedactor creates a redactor from built-in detector names and custom
+// regexps keyed by the name used in their replacement.
+// Without detectors and patterns, all built-in detectors are used.
+func NewRedactor(detectors []string, patterns map[string]string) (*Redactor, error) {
+	if len(detectors) == 0 && len(patterns) == 0 {
+		detectors = RedactDetectors()
+	}
+
+	r := &Redactor{detectors: make([]*redactDetector, 0, len(detectors)+len(patterns))}
+	for _, name := range detectors {
+		d, ok := builtinRedactDetectors[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown redaction detector %q, supported: %v", name, RedactDetectors())
+		}
+		r.detectors = append(r.detectors, d)
+	}
+
+	// Sort the custom patterns so they are always applied in the same order.
+	names := make([]string, 0, len(patterns))
+	for name := range patterns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		re, err := regexp.Compile(patterns[name])
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", name, err)
+		}
+		r.detectors = append(r.detectors, newRedactDetector(name, re, "", nil))
+	}
+	return r, nil
+}
+
+// Redact returns the line with all detected sensitive data replaced.
+// matched, if not nil, is called with the name of the detector for every replacement.
+// The line is returned as is when nothing was detected.
+func (r *Redactor) Redact(line []byte, matched func(detector string)) []byte {
+	for _, d := range r.detectors {
+		if d.hint != nil && !bytes.Contains(line, d.hint) {
+			continue
+		}
+		if !d.re.Match(line) {
+			continue
+		}
+		line = d.re.ReplaceAllFunc(line, func(match []byte) []byte {
+			if d.valid != nil && !d.valid(match) {
+				return match
+			}
+			if matched != nil {
+				matched(d.name)
+			}
+			return d.replacement
+		})
+	}
+	return line
+}
+
+// RedactString is like Redact for strings.
+func (r *Redactor) RedactString(s string, matched func(detector string)) string {
+	redacted := r.Redact([]byte(s), matched)
+	if string(redacted) == s {
+		return s
+	}
+	return string(redacted)
+}
+
+// Process implements Stage. The values of the labels added by the query, such
+// as structured metadata and the labels extracted by parsers, are redacted
+// too. Stream labels are not.
+func (r *Redactor) Process(_ int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	if lbs != nil {
+		for _, category := range allCategories {
+			for i, l := range lbs.add[category] {
+				lbs.add[category][i].Value = r.RedactString(l.Value, nil)
+			}
+		}
+	}
+	return r.Redact(line, nil), true
+}
+
+func (r *Redactor) RequiredLabelNames() []string { return []string{} }
+
+// luhnValid reports whether the digits of the match pass the Luhn checksum used by payment card numbers.
+func luhnValid(match []byte) bool {
+	var sum, n int
+	for i := len(match) - 1; i >= 0; i-- {
+		c := match[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		digit := int(c - '0')
+		if n%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		n++
+	}
+	return n >= 13 && n <= 19 && sum%10 == 0
+}
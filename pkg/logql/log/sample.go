@@ -0,0 +1,85 @@
+package log
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// Sampling modes of the sample stage.
+const (
+	// SampleEntries samples individual log entries.
+	SampleEntries = "entry"
+	// SampleStreams samples whole streams. Streams are not sampled by the
+	// stage itself but when selecting the data to read, so that chunks of
+	// streams which are not sampled are never fetched.
+	SampleStreams = "stream"
+)
+
+// ValidateSampleRatio returns an error if ratio is not a valid sampling ratio.
+func ValidateSampleRatio(ratio float64) error {
+	if math.IsNaN(ratio) || ratio <= 0 || ratio > 1 {
+		return fmt.Errorf("invalid sampling ratio %v, must be in (0, 1]", ratio)
+	}
+	return nil
+}
+
+// SampleEntry deterministically decides whether the log entry with the given
+// timestamp and line, of the stream with the given labels hash, is part of a
+// sample of the given ratio. Entries are sampled by a hash of all of them, so
+// that entries of different streams at the same timestamp are sampled
+// independently, and every querier makes the same decision for an entry.
+// Samples of a smaller ratio are subsets of samples of a larger one.
+func SampleEntry(streamHash uint64, ts int64, line []byte, ratio float64) bool {
+	if ratio >= 1 {
+		return true
+	}
+	var buf [16]byte
+	binary.LittleEndian.PutUint64(buf[:8], streamHash)
+	binary.LittleEndian.PutUint64(buf[8:], uint64(ts))
+	d := xxhash.New()
+	_, _ = d.Write(buf[:])
+	_, _ = d.Write(line)
+	return d.Sum64() < uint64(ratio*math.MaxUint64)
+}
+
+// maxStreamSampleBits bounds stream samples to shards of a 32 bits shard factor.
+const maxStreamSampleBits = 31
+
+// StreamSampleBits returns the number of leading fingerprint bits that must be
+// zero for a stream to be part of a sample of the given ratio. Stream sampling
+// selects a power of two fraction of the fingerprint space, so the ratio is
+// rounded down to the nearest power of two, see EffectiveStreamSampleRatio.
+func StreamSampleBits(ratio float64) uint32 {
+	if ratio >= 1 {
+		return 0
+	}
+	return min(uint32(math.Ceil(-math.Log2(ratio))), maxStreamSampleBits)
+}
+
+// EffectiveStreamSampleRatio returns the fraction of streams actually sampled
+// for a requested ratio.
+func EffectiveStreamSampleRatio(ratio float64) float64 {
+	return math.Ldexp(1, -int(StreamSampleBits(ratio)))
+}
+
+// Sampler is a stage keeping a deterministic sample of log entries.
+type Sampler struct {
+	ratio float64
+}
+
+// NewSampler creates a sampling stage keeping the given ratio of entries.
+func NewSampler(ratio float64) (*Sampler, error) {
+	if err := ValidateSampleRatio(ratio); err != nil {
+		return nil, err
+	}
+	return &Sampler{ratio: ratio}, nil
+}
+
+func (s *Sampler) Process(ts int64, line []byte, lbs *LabelsBuilder) ([]byte, bool) {
+	return line, SampleEntry(lbs.StreamHash(), ts, line, s.ratio)
+}
+
+func (s *Sampler) RequiredLabelNames() []string { return []string{} }
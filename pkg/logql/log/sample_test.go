@@ -0,0 +1,77 @@
+package log
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampleEntry(t *testing.T) {
+	const n = 100000
+	streamHash := labels.StableHash(labels.FromStrings("app", "foo"))
+	line := []byte("line")
+
+	for _, ratio := range []float64{0.001, 0.01, 0.1, 0.5} {
+		kept := 0
+		for ts := int64(0); ts < n; ts++ {
+			// Use realistic, evenly spaced timestamps.
+			sampled := SampleEntry(streamHash, 1700000000000000000+ts*1000, line, ratio)
+			require.Equal(t, sampled, SampleEntry(streamHash, 1700000000000000000+ts*1000, line, ratio), "sampling must be deterministic")
+			if sampled {
+				kept++
+				// Samples of a smaller ratio are subsets of samples of a larger one.
+				require.True(t, SampleEntry(streamHash, 1700000000000000000+ts*1000, line, min(1, ratio*2)))
+			}
+		}
+		require.InDelta(t, ratio, float64(kept)/n, ratio*0.2, "ratio %v", ratio)
+	}
+
+	// Entries at the same timestamp are sampled independently of each other.
+	kept := 0
+	for i := 0; i < n; i++ {
+		streamHash := labels.StableHash(labels.FromStrings("app", strconv.Itoa(i%100)))
+		if SampleEntry(streamHash, 1700000000000000000, []byte(strconv.Itoa(i)), 0.1) {
+			kept++
+		}
+	}
+	require.InDelta(t, 0.1, float64(kept)/n, 0.02)
+
+	require.True(t, SampleEntry(streamHash, 42, line, 1))
+}
+
+func TestStreamSampleBits(t *testing.T) {
+	for _, tc := range []struct {
+		ratio     float64
+		bits      uint32
+		effective float64
+	}{
+		{1, 0, 1},
+		{0.5, 1, 0.5},
+		{0.3, 2, 0.25},
+		{0.25, 2, 0.25},
+		{0.01, 7, 1.0 / 128},
+		{1e-12, maxStreamSampleBits, 1.0 / (1 << maxStreamSampleBits)},
+	} {
+		require.Equal(t, tc.bits, StreamSampleBits(tc.ratio), "ratio %v", tc.ratio)
+		require.Equal(t, tc.effective, EffectiveStreamSampleRatio(tc.ratio), "ratio %v", tc.ratio)
+	}
+}
+
+func TestSampler(t *testing.T) {
+	_, err := NewSampler(0)
+	require.Error(t, err)
+	_, err = NewSampler(1.5)
+	require.Error(t, err)
+
+	s, err := NewSampler(0.1)
+	require.NoError(t, err)
+	lbs := labels.FromStrings("app", "foo")
+	builder := NewBaseLabelsBuilder().ForLabels(lbs, labels.StableHash(lbs))
+	for ts := int64(0); ts < 1000; ts++ {
+		line, ok := s.Process(ts, []byte("line"), builder)
+		require.Equal(t, "line", string(line))
+		require.Equal(t, SampleEntry(labels.StableHash(lbs), ts, []byte("line"), 0.1), ok)
+	}
+}
@@ -0,0 +1,440 @@
+package logql
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/v3/pkg/iter"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/log"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/querier/plan"
+	"github.com/grafana/loki/v3/pkg/util"
+)
+
+// contextEntry is an entry returned by a contextIterator.
+type contextEntry struct {
+	logproto.Entry
+	labels     string
+	streamHash uint64
+}
+
+// contextStream is the state of a stream of a contextIterator.
+type contextStream struct {
+	labels   labels.Labels
+	pipeline log.StreamPipeline
+	// last is the timestamp of the last entry returned, and lines the lines
+	// returned at this timestamp.
+	last  int64
+	lines map[string]struct{}
+}
+
+// contextKey identifies an entry of a stream.
+type contextKey struct {
+	ts   int64
+	line string
+}
+
+// contextWindow is a time range of a stream holding the context of matches.
+type contextWindow struct {
+	start, end time.Time
+	matches    map[contextKey]struct{}
+}
+
+// contextIterator returns the entries matched by the stages preceding a
+// context stage, together with the entries surrounding every match in its
+// stream. The candidate matches are read a page at a time with the line
+// filters of the query. The windows of the matches of a page, spanning the
+// maximum context window before and after them, are merged when they overlap
+// in a stream, and every merged window is read once. The entries read before
+// a match are buffered until it is read.
+type contextIterator struct {
+	ctx        context.Context
+	querier    Querier
+	candidates iter.EntryIterator
+	pipeline   log.Pipeline
+	// leading and trailing are the number of entries returned before and
+	// after a match, in the direction of the query.
+	leading, trailing int
+	window            time.Duration
+	direction         logproto.Direction
+	pageSize          uint32
+
+	streams map[string]*contextStream
+	buf     []contextEntry
+	cur     contextEntry
+	err     error
+}
+
+func newContextIterator(ctx context.Context, querier Querier, candidates iter.EntryIterator, pipeline log.Pipeline, contextStage *syntax.ContextExpr, window time.Duration, direction logproto.Direction, pageSize uint32) *contextIterator {
+	leading, trailing := contextStage.Before, contextStage.After
+	if direction == logproto.BACKWARD {
+		// Iterating backward, the entries preceding a match in time are read
+		// after it.
+		leading, trailing = trailing, leading
+	}
+	return &contextIterator{
+		ctx:        ctx,
+		querier:    querier,
+		candidates: candidates,
+		pipeline:   pipeline,
+		leading:    leading,
+		trailing:   trailing,
+		window:     window,
+		direction:  direction,
+		pageSize:   max(pageSize, 1),
+		streams:    map[string]*contextStream{},
+	}
+}
+
+func (i *contextIterator) Next() bool {
+	for len(i.buf) == 0 {
+		more, err := i.nextPage()
+		if err != nil {
+			i.err = err
+			return false
+		}
+		if !more {
+			return false
+		}
+	}
+	i.cur, i.buf = i.buf[0], i.buf[1:]
+	return true
+}
+
+// nextPage reads a page of candidates and buffers the context of its
+// matches. It returns false once all the candidates were read.
+func (i *contextIterator) nextPage() (bool, error) {
+	matches := map[string][]contextEntry{}
+	var order []string
+	var read uint32
+	for read < i.pageSize && i.candidates.Next() {
+		read++
+		e := contextEntry{Entry: i.candidates.At(), labels: i.candidates.Labels(), streamHash: i.candidates.StreamHash()}
+		lbs, err := streamLabels(e.labels, e.Entry)
+		if err != nil {
+			return false, err
+		}
+		key := lbs.String()
+		s, ok := i.streams[key]
+		if !ok {
+			s = &contextStream{labels: lbs, pipeline: i.pipeline.ForStream(lbs)}
+			i.streams[key] = s
+		}
+		if _, _, ok := s.pipeline.Process(e.Timestamp.UnixNano(), util.YoloBuf(e.Line), logproto.FromLabelAdaptersToLabels(e.StructuredMetadata)); !ok {
+			continue
+		}
+		if _, ok := matches[key]; !ok {
+			order = append(order, key)
+		}
+		matches[key] = append(matches[key], e)
+	}
+	if err := i.candidates.Err(); err != nil {
+		return false, err
+	}
+
+	for _, key := range order {
+		for _, w := range i.windows(matches[key]) {
+			if err := i.readWindow(i.streams[key], w); err != nil {
+				return false, err
+			}
+		}
+	}
+	return read > 0, nil
+}
+
+// windows returns the windows of the matches of a stream, merged when they
+// overlap, in the order they are read.
+func (i *contextIterator) windows(matches []contextEntry) []contextWindow {
+	slices.SortStableFunc(matches, func(a, b contextEntry) int { return a.Timestamp.Compare(b.Timestamp) })
+	var windows []contextWindow
+	for _, m := range matches {
+		start, end := m.Timestamp.Add(-i.window), m.Timestamp.Add(i.window)
+		if n := len(windows); n == 0 || start.After(windows[n-1].end) {
+			windows = append(windows, contextWindow{start: start, matches: map[contextKey]struct{}{}})
+		}
+		w := &windows[len(windows)-1]
+		w.end = end
+		w.matches[contextKey{ts: m.Timestamp.UnixNano(), line: m.Line}] = struct{}{}
+	}
+	if i.direction == logproto.BACKWARD {
+		slices.Reverse(windows)
+	}
+	return windows
+}
+
+// readWindow buffers the context of the matches of the stream s within w.
+// Entries read before a match are kept until it is read, and the window is
+// read until the context of its last match is complete.
+func (i *contextIterator) readWindow(s *contextStream, w contextWindow) error {
+	it := i.selectStream(s.labels, w.start, w.end.Add(1))
+	defer it.Close()
+
+	var (
+		leading  []contextEntry
+		trailing int
+		last     time.Time
+	)
+	for (len(w.matches) > 0 || trailing > 0) && it.Next() {
+		e := contextEntry{Entry: it.At(), labels: it.Labels(), streamHash: it.StreamHash()}
+		stream, err := streamLabels(e.labels, e.Entry)
+		if err != nil {
+			return err
+		}
+		// Streams whose labels are a superset of the ones of s are selected
+		// too, and skipped.
+		if !labels.Equal(stream, s.labels) {
+			continue
+		}
+
+		key := contextKey{ts: e.Timestamp.UnixNano(), line: e.Line}
+		if _, ok := w.matches[key]; ok {
+			delete(w.matches, key)
+			for _, l := range leading {
+				if i.within(l.Timestamp, e.Timestamp) {
+					i.add(s, l)
+				}
+			}
+			leading = leading[:0]
+			i.add(s, e)
+			trailing, last = i.trailing, e.Timestamp
+			continue
+		}
+		if trailing > 0 && i.within(e.Timestamp, last) {
+			i.add(s, e)
+			trailing--
+			continue
+		}
+		trailing = 0
+		if i.leading > 0 {
+			if len(leading) == i.leading {
+				leading = leading[1:]
+			}
+			leading = append(leading, e)
+		}
+	}
+	return it.Err()
+}
+
+// within returns whether a and b are within the context window of each other.
+func (i *contextIterator) within(a, b time.Time) bool {
+	d := a.Sub(b)
+	return d <= i.window && d >= -i.window
+}
+
+// add buffers e unless it was already returned. Entries of a stream are
+// returned in order, so the contexts of the next matches only overlap with
+// the entries returned last.
+func (i *contextIterator) add(s *contextStream, e contextEntry) {
+	ts := e.Timestamp.UnixNano()
+	if s.lines != nil {
+		if (i.direction == logproto.FORWARD && ts < s.last) || (i.direction == logproto.BACKWARD && ts > s.last) {
+			return
+		}
+		if _, ok := s.lines[e.Line]; ok && ts == s.last {
+			return
+		}
+	}
+	if s.lines == nil || ts != s.last {
+		s.last = ts
+		s.lines = map[string]struct{}{}
+	}
+	s.lines[e.Line] = struct{}{}
+	i.buf = append(i.buf, e)
+}
+
+// selectStream returns the entries of the stream with the labels lbs between
+// start and end, in the direction of the query. Streams whose labels are a
+// superset of lbs are selected too.
+func (i *contextIterator) selectStream(lbs labels.Labels, start, end time.Time) *pagedEntryIterator {
+	matchers := make([]*labels.Matcher, 0, lbs.Len())
+	lbs.Range(func(l labels.Label) {
+		matchers = append(matchers, labels.MustNewMatcher(labels.MatchEqual, l.Name, l.Value))
+	})
+	selector := &syntax.MatchersExpr{Mts: matchers}
+	return newPagedEntryIterator(i.ctx, i.querier, logproto.QueryRequest{
+		Start:     start,
+		End:       end,
+		Direction: i.direction,
+		Selector:  selector.String(),
+		Plan: &plan.QueryPlan{
+			AST: selector,
+		},
+	}, i.pageSize)
+}
+
+// pagedEntryKey identifies an entry of a page.
+type pagedEntryKey struct {
+	labels, line string
+}
+
+// pagedEntryIterator selects the entries of a request a page of pageSize
+// entries at a time, only selecting the next page once the previous one was
+// read. Every page starts at the timestamp of the last entry of the previous
+// one, and the entries at this timestamp which were already returned are
+// skipped.
+type pagedEntryIterator struct {
+	ctx      context.Context
+	querier  Querier
+	req      logproto.QueryRequest
+	pageSize uint32
+
+	page iter.EntryIterator
+	// limit is the limit of the current page, read the number of entries
+	// read from it and returned the number of those which were returned.
+	limit, read, returned uint32
+	done                  bool
+	// last is the timestamp of the last entry returned, atLast counts the
+	// entries returned at this timestamp and skip the ones still to skip in
+	// the current page.
+	last         time.Time
+	atLast, skip map[pagedEntryKey]int
+	cur          contextEntry
+	err          error
+}
+
+func newPagedEntryIterator(ctx context.Context, querier Querier, req logproto.QueryRequest, pageSize uint32) *pagedEntryIterator {
+	return &pagedEntryIterator{
+		ctx:      ctx,
+		querier:  querier,
+		req:      req,
+		pageSize: max(pageSize, 1),
+		limit:    max(pageSize, 1),
+		atLast:   map[pagedEntryKey]int{},
+	}
+}
+
+func (i *pagedEntryIterator) Next() bool {
+	for i.err == nil {
+		if i.page == nil {
+			if i.done {
+				return false
+			}
+			i.nextPage()
+			continue
+		}
+		if !i.page.Next() {
+			i.endPage()
+			continue
+		}
+		i.read++
+		e := contextEntry{Entry: i.page.At(), labels: i.page.Labels(), streamHash: i.page.StreamHash()}
+		key := pagedEntryKey{labels: e.labels, line: e.Line}
+		if !e.Timestamp.Equal(i.last) {
+			i.last = e.Timestamp
+			clear(i.atLast)
+			i.skip = nil
+		} else if i.skip[key] > 0 {
+			i.skip[key]--
+			continue
+		}
+		i.atLast[key]++
+		i.returned++
+		i.cur = e
+		return true
+	}
+	return false
+}
+
+func (i *pagedEntryIterator) nextPage() {
+	req := i.req
+	req.Limit = i.limit
+	if !i.last.IsZero() {
+		if req.Direction == logproto.FORWARD {
+			req.Start = i.last
+		} else {
+			req.End = i.last.Add(1)
+		}
+		i.skip = maps.Clone(i.atLast)
+	}
+	i.page, i.err = i.querier.SelectLogs(i.ctx, SelectLogParams{QueryRequest: &req})
+	i.read, i.returned = 0, 0
+}
+
+func (i *pagedEntryIterator) endPage() {
+	i.err = i.page.Err()
+	if err := i.page.Close(); i.err == nil {
+		i.err = err
+	}
+	i.page = nil
+	switch {
+	case i.read < i.limit:
+		i.done = true
+	case i.returned == 0:
+		// The page only holds entries at the timestamp of the last entry,
+		// which are read again with a larger limit until they are all read.
+		i.limit *= 2
+	default:
+		i.limit = i.pageSize
+	}
+}
+
+func (i *pagedEntryIterator) At() logproto.Entry {
+	return i.cur.Entry
+}
+
+func (i *pagedEntryIterator) Labels() string {
+	return i.cur.labels
+}
+
+func (i *pagedEntryIterator) StreamHash() uint64 {
+	return i.cur.streamHash
+}
+
+func (i *pagedEntryIterator) Err() error {
+	return i.err
+}
+
+func (i *pagedEntryIterator) Close() error {
+	if i.page == nil {
+		return nil
+	}
+	err := i.page.Close()
+	i.page = nil
+	return err
+}
+
+// streamLabels returns the labels of the stream of an entry with the labels
+// lbs, which include its structured metadata.
+func streamLabels(lbs string, e logproto.Entry) (labels.Labels, error) {
+	ls, err := syntax.ParseLabels(lbs)
+	if err != nil {
+		return labels.EmptyLabels(), fmt.Errorf("failed to parse stream labels: %w", err)
+	}
+	if len(e.StructuredMetadata) == 0 {
+		return ls, nil
+	}
+	b := labels.NewBuilder(ls)
+	for _, l := range e.StructuredMetadata {
+		b.Del(l.Name)
+	}
+	return b.Labels(), nil
+}
+
+func (i *contextIterator) At() logproto.Entry {
+	return i.cur.Entry
+}
+
+func (i *contextIterator) Labels() string {
+	return i.cur.labels
+}
+
+func (i *contextIterator) StreamHash() uint64 {
+	return i.cur.streamHash
+}
+
+func (i *contextIterator) Err() error {
+	if i.err != nil {
+		return i.err
+	}
+	return i.candidates.Err()
+}
+
+func (i *contextIterator) Close() error {
+	return i.candidates.Close()
+}
@@ -171,6 +171,7 @@ func RecordRangeAndInstantQueryMetrics(
 		"queue_time", logql_stats.ConvertSecondsToNanoseconds(stats.Summary.QueueTime),
 		"splits", stats.Summary.Splits,
 		"shards", stats.Summary.Shards,
+		"estimated_cost", util.HumanizeBytes(uint64(stats.Summary.EstimatedCost)),
 		"query_referenced_structured_metadata", stats.QueryReferencedStructuredMetadata(),
 		"pipeline_wrapper_filtered_lines", stats.PipelineWrapperFilteredLines(),
 		"chunk_refs_fetch_time", stats.ChunkRefsFetchTime(),
@@ -68,6 +68,10 @@ func (ProbabilisticQuantileVector) CountMinSketchVec() CountMinSketchVector {
 	return CountMinSketchVector{}
 }
 
+func (ProbabilisticQuantileVector) HyperLogLogVec() HyperLogLogVector {
+	return HyperLogLogVector{}
+}
+
 func (q ProbabilisticQuantileVector) ToProto() *logproto.QuantileSketchVector {
 	samples := make([]*logproto.QuantileSketchSample, len(q))
 	for i, sample := range q {
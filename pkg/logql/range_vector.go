@@ -40,6 +40,20 @@ type RangeVectorIterator interface {
 }
 
 func newRangeVectorIterator(
+	it iter.PeekingSampleIterator,
+	expr *syntax.RangeAggregationExpr,
+	selRange, step, start, end, offset int64) (RangeVectorIterator, error) {
+	rangeIt, err := newUnscaledRangeVectorIterator(it, expr, selRange, step, start, end, offset)
+	if err != nil {
+		return nil, err
+	}
+	if scale := samplingScale(expr); scale != 1 {
+		return &scaledRangeVectorIterator{RangeVectorIterator: rangeIt, scale: scale}, nil
+	}
+	return rangeIt, nil
+}
+
+func newUnscaledRangeVectorIterator(
 	it iter.PeekingSampleIterator,
 	expr *syntax.RangeAggregationExpr,
 	selRange, step, start, end, offset int64) (RangeVectorIterator, error) {
@@ -51,6 +65,23 @@ func newRangeVectorIterator(
 		start = start - offset
 		end = end - offset
 	}
+	if expr.Operation == syntax.OpRangeTypePredictLinear {
+		// The prediction is relative to the time of the step, which only
+		// the iterator knows.
+		return &predictLinearRangeVectorIterator{
+			batchRangeVectorIterator: &batchRangeVectorIterator{
+				iter:     it,
+				step:     step,
+				end:      end,
+				selRange: selRange,
+				metrics:  map[string]labels.Labels{},
+				window:   map[string]*promql.Series{},
+				current:  start - step, // first loop iteration will set it to start
+				offset:   offset,
+			},
+			duration: *expr.Params,
+		}, nil
+	}
 	var overlap bool
 	if selRange >= step && start != end {
 		overlap = true
@@ -88,6 +119,38 @@ func newRangeVectorIterator(
 	}, nil
 }
 
+// samplingScale returns the factor the results of a range aggregation are
+// multiplied by to estimate them over the data before sampling. Only additive
+// aggregations are scaled.
+func samplingScale(expr *syntax.RangeAggregationExpr) float64 {
+	if expr.Left == nil {
+		return 1
+	}
+	switch expr.Operation {
+	case syntax.OpRangeTypeCount, syntax.OpRangeTypeRate, syntax.OpRangeTypeBytes,
+		syntax.OpRangeTypeBytesRate, syntax.OpRangeTypeSum, syntax.OpRangeTypeHistogram,
+		syntax.OpRangeTypeHistogramBuckets:
+		return syntax.SamplingScale(expr.Left)
+	default:
+		return 1
+	}
+}
+
+// scaledRangeVectorIterator scales the results of sampled range aggregations.
+type scaledRangeVectorIterator struct {
+	RangeVectorIterator
+	scale float64
+}
+
+func (r *scaledRangeVectorIterator) At() (int64, StepResult) {
+	ts, res := r.RangeVectorIterator.At()
+	vec := res.SampleVector()
+	for i := range vec {
+		vec[i].F *= r.scale
+	}
+	return ts, res
+}
+
 //batch
 
 type batchRangeVectorIterator struct {
@@ -250,6 +313,12 @@ func aggregator(r *syntax.RangeAggregationExpr) (BatchRangeVectorAggregator, err
 		return last, nil
 	case syntax.OpRangeTypeAbsent:
 		return one, nil
+	case syntax.OpRangeTypeChanges:
+		return changes, nil
+	case syntax.OpRangeTypeDeriv:
+		return deriv, nil
+	case syntax.OpRangeTypeHoltWinters:
+		return holtWinters(*r.Params, *r.SecondParam), nil
 	default:
 		return nil, fmt.Errorf(syntax.UnsupportedErr, r.Operation)
 	}
@@ -504,6 +573,112 @@ func one(_ []promql.FPoint) float64 {
 	return 1.0
 }
 
+// changes returns the number of times the value changed within the range.
+func changes(samples []promql.FPoint) float64 {
+	if len(samples) == 0 {
+		return math.NaN()
+	}
+	var changes float64
+	prev := samples[0].F
+	for _, sample := range samples[1:] {
+		if sample.F != prev && !(math.IsNaN(sample.F) && math.IsNaN(prev)) {
+			changes++
+		}
+		prev = sample.F
+	}
+	return changes
+}
+
+// deriv returns the per-second derivative of the samples, using simple
+// linear regression.
+func deriv(samples []promql.FPoint) float64 {
+	if len(samples) < 2 {
+		return math.NaN()
+	}
+	// The intercept time is the first sample to avoid floating point
+	// errors with large timestamps, as in Prometheus.
+	slope, _ := linearRegression(samples, samples[0].T)
+	return slope
+}
+
+// predictLinear predicts the value of the samples duration seconds after ts,
+// using simple linear regression.
+func predictLinear(samples []promql.FPoint, ts int64, duration float64) float64 {
+	if len(samples) < 2 {
+		return math.NaN()
+	}
+	slope, intercept := linearRegression(samples, ts)
+	return slope*duration + intercept
+}
+
+// linearRegression function is taken from prometheus code promql/functions.go.
+// It returns the slope per second and the intercept at interceptTime of the
+// least squares fit of the samples, whose timestamps are in nanoseconds.
+func linearRegression(samples []promql.FPoint, interceptTime int64) (slope, intercept float64) {
+	var (
+		n                        float64
+		sumX, sumY, sumXY, sumX2 float64
+		initY                    = samples[0].F
+		constY                   = true
+	)
+	for i, sample := range samples {
+		// Set constY to false if any new y values are encountered.
+		if constY && i > 0 && sample.F != initY {
+			constY = false
+		}
+		n++
+		x := float64(sample.T-interceptTime) / float64(time.Second)
+		sumX += x
+		sumY += sample.F
+		sumXY += x * sample.F
+		sumX2 += x * x
+	}
+	if constY {
+		if math.IsInf(initY, 0) {
+			return math.NaN(), math.NaN()
+		}
+		return 0, initY
+	}
+	covXY := sumXY - sumX*sumY/n
+	varX := sumX2 - sumX*sumX/n
+
+	slope = covXY / varX
+	intercept = sumY/n - slope*sumX/n
+	return slope, intercept
+}
+
+// holtWinters function is taken from prometheus code promql/functions.go.
+// It smooths the samples with double exponential smoothing, with the
+// smoothing factor sf and the trend factor tf.
+func holtWinters(sf, tf float64) func(samples []promql.FPoint) float64 {
+	return func(samples []promql.FPoint) float64 {
+		l := len(samples)
+		// Can't do the smoothing operation with less than two points.
+		if l < 2 {
+			return math.NaN()
+		}
+
+		var s0, s1, b float64
+		// Set initial values.
+		s1 = samples[0].F
+		b = samples[1].F - samples[0].F
+
+		// Run the smoothing operation.
+		for i := 1; i < l; i++ {
+			// Scale the raw value against the smoothing factor.
+			x := sf * samples[i].F
+			// Scale the last smoothed value with the trend at this point.
+			if i > 1 {
+				b = tf*(s1-s0) + (1-tf)*b
+			}
+			y := (1 - sf) * (s1 + b)
+
+			s0, s1 = s1, x+y
+		}
+		return s1
+	}
+}
+
 // streaming range agg
 type streamRangeVectorIterator struct {
 	iter                                 iter.PeekingSampleIterator
@@ -627,6 +802,12 @@ func streamingAggregator(r *syntax.RangeAggregationExpr) (RangeStreamingAgg, err
 		return &LastOverTime{}, nil
 	case syntax.OpRangeTypeAbsent:
 		return &OneOverTime{}, nil
+	case syntax.OpRangeTypeChanges:
+		return &ChangesOverTime{}, nil
+	case syntax.OpRangeTypeDeriv:
+		return &DerivOverTime{samples: make([]promql.FPoint, 0)}, nil
+	case syntax.OpRangeTypeHoltWinters:
+		return &HoltWintersOverTime{sf: *r.Params, tf: *r.SecondParam, samples: make([]promql.FPoint, 0)}, nil
 	default:
 		return nil, fmt.Errorf(syntax.UnsupportedErr, r.Operation)
 	}
@@ -843,6 +1024,49 @@ func (a *LastOverTime) at() float64 {
 	return a.v
 }
 
+type ChangesOverTime struct {
+	prev    float64
+	changes float64
+	hasData bool
+}
+
+func (a *ChangesOverTime) agg(sample promql.FPoint) {
+	if a.hasData && sample.F != a.prev && !(math.IsNaN(sample.F) && math.IsNaN(a.prev)) {
+		a.changes++
+	}
+	a.prev = sample.F
+	a.hasData = true
+}
+
+func (a *ChangesOverTime) at() float64 {
+	return a.changes
+}
+
+type DerivOverTime struct {
+	samples []promql.FPoint
+}
+
+func (a *DerivOverTime) agg(sample promql.FPoint) {
+	a.samples = append(a.samples, sample)
+}
+
+func (a *DerivOverTime) at() float64 {
+	return deriv(a.samples)
+}
+
+type HoltWintersOverTime struct {
+	sf, tf  float64
+	samples []promql.FPoint
+}
+
+func (a *HoltWintersOverTime) agg(sample promql.FPoint) {
+	a.samples = append(a.samples, sample)
+}
+
+func (a *HoltWintersOverTime) at() float64 {
+	return holtWinters(a.sf, a.tf)(a.samples)
+}
+
 type OneOverTime struct {
 }
 
@@ -851,3 +1075,27 @@ func (a *OneOverTime) agg(_ promql.FPoint) {
 func (a *OneOverTime) at() float64 {
 	return 1.0
 }
+
+// predictLinearRangeVectorIterator predicts the value of each series of the
+// window at the time of the step plus a duration in seconds.
+type predictLinearRangeVectorIterator struct {
+	*batchRangeVectorIterator
+	duration float64
+}
+
+func (r *predictLinearRangeVectorIterator) At() (int64, StepResult) {
+	if r.at == nil {
+		r.at = make([]promql.Sample, 0, len(r.window))
+	}
+	r.at = r.at[:0]
+	// convert ts from nano to milli seconds as the iterator work with nanoseconds
+	ts := r.current/1e+6 + r.offset/1e+6
+	for _, series := range r.window {
+		r.at = append(r.at, promql.Sample{
+			F:      predictLinear(series.Floats, r.current, r.duration),
+			T:      ts,
+			Metric: series.Metric,
+		})
+	}
+	return ts, SampleVector(r.at)
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"slices"
 	"sort"
 	"testing"
 	"time"
 
+	"github.com/go-kit/log"
 	"github.com/gogo/protobuf/proto"
+	"github.com/grafana/dskit/user"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/promql"
 	"github.com/stretchr/testify/require"
@@ -488,6 +491,48 @@ func Test_RangeVectorIterator(t *testing.T) {
 	}
 }
 
+func Test_RangeVectorIterator_Sampling(t *testing.T) {
+	for _, tc := range []struct {
+		query string
+		scale float64
+	}{
+		{`count_over_time({app="foo"} | sample 0.5 [5s])`, 2},
+		{`count_over_time({app="foo"} | sample 0.25 by stream [5s])`, 4},
+		{`sum_over_time({app="foo"} | sample 0.1 | unwrap foo [5s])`, 10},
+		{`max_over_time({app="foo"} | sample 0.5 | unwrap foo [5s])`, 1},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			expr := syntax.MustParseExpr(tc.query).(*syntax.RangeAggregationExpr)
+			unsampled := *expr
+			unsampled.Left = &syntax.LogRangeExpr{Left: &syntax.MatchersExpr{}, Interval: expr.Left.Interval}
+
+			results := func(expr *syntax.RangeAggregationExpr) []promql.Vector {
+				it, err := newRangeVectorIterator(newfakePeekingSampleIterator(samples), expr,
+					(5 * time.Second).Nanoseconds(), (30 * time.Second).Nanoseconds(),
+					time.Unix(10, 0).UnixNano(), time.Unix(100, 0).UnixNano(), 0)
+				require.NoError(t, err)
+
+				var res []promql.Vector
+				for it.Next() {
+					_, v := it.At()
+					res = append(res, slices.Clone(v.SampleVector()))
+				}
+				return res
+			}
+
+			expected, actual := results(&unsampled), results(expr)
+			require.NotEmpty(t, expected)
+			require.Len(t, actual, len(expected))
+			for i, vec := range expected {
+				for j := range vec {
+					vec[j].F *= tc.scale
+				}
+				require.ElementsMatch(t, vec, actual[i])
+			}
+		})
+	}
+}
+
 func Test_RangeVectorIteratorBadLabels(t *testing.T) {
 	badIterator := iter.NewPeekingSampleIterator(
 		iter.NewSeriesIterator(logproto.Series{
@@ -535,13 +580,16 @@ func Test_InstantQueryRangeVectorAggregations(t *testing.T) {
 		{"first", 1., syntax.OpRangeTypeFirst, false},
 		{"last", 3., syntax.OpRangeTypeLast, false},
 		{"absent", 1., syntax.OpRangeTypeAbsent, false},
+		{"changes", 2., syntax.OpRangeTypeChanges, false},
+		{"deriv", 1.0000000000000001e+09, syntax.OpRangeTypeDeriv, false},
+		{"holt winters", 3., syntax.OpRangeTypeHoltWinters, false},
 	}
 
 	var start, end int64 = 4, 4 // Instant query
 	for _, tt := range tests {
 		t.Run(fmt.Sprintf("testing aggregation %s", tt.name), func(t *testing.T) {
 			it, err := newRangeVectorIterator(sampleIter(tt.negative),
-				&syntax.RangeAggregationExpr{Left: &syntax.LogRangeExpr{Interval: 2}, Params: proto.Float64(0.99), Operation: tt.op},
+				&syntax.RangeAggregationExpr{Left: &syntax.LogRangeExpr{Interval: 2}, Params: proto.Float64(0.99), SecondParam: proto.Float64(0.5), Operation: tt.op},
 				3, 1, start, end, 0)
 			require.NoError(t, err)
 
@@ -554,6 +602,70 @@ func Test_InstantQueryRangeVectorAggregations(t *testing.T) {
 	}
 }
 
+func Test_PredictLinearRangeVectorIterator(t *testing.T) {
+	// The values grow by 1 per second, and are predicted 10s after each step.
+	series := logproto.Series{Labels: labelFoo.String(), StreamHash: labels.StableHash(labelFoo)}
+	for i := int64(1); i <= 60; i++ {
+		series.Samples = append(series.Samples, logproto.Sample{Timestamp: time.Unix(i, 0).UnixNano(), Hash: uint64(i), Value: float64(i)})
+	}
+	it, err := newRangeVectorIterator(
+		iter.NewPeekingSampleIterator(iter.NewSeriesIterator(series)),
+		&syntax.RangeAggregationExpr{Left: &syntax.LogRangeExpr{Interval: 10 * time.Second}, Params: proto.Float64(10), Operation: syntax.OpRangeTypePredictLinear},
+		(10 * time.Second).Nanoseconds(), (20 * time.Second).Nanoseconds(), time.Unix(20, 0).UnixNano(), time.Unix(60, 0).UnixNano(), 0)
+	require.NoError(t, err)
+
+	var res []promql.Sample
+	for it.Next() {
+		_, r := it.At()
+		res = append(res, r.SampleVector()...)
+	}
+	require.Len(t, res, 3)
+	for i, ts := range []int64{20, 40, 60} {
+		require.Equal(t, ts*1000, res[i].T)
+		require.InDelta(t, float64(ts+10), res[i].F, 1e-9)
+	}
+}
+
+func TestEngine_TrendFunctions(t *testing.T) {
+	// one sample per second from 1s to 299s, whose value is its timestamp
+	series := logproto.Series{Labels: `{app="foo"}`}
+	for i := int64(1); i < 300; i++ {
+		series.Samples = append(series.Samples, logproto.Sample{Timestamp: time.Unix(i, 0).UnixNano(), Hash: uint64(i), Value: float64(i)})
+	}
+	querier := errorIteratorQuerier{
+		samples: func() []iter.SampleIterator {
+			return []iter.SampleIterator{iter.NewSeriesIterator(series)}
+		},
+	}
+	eng := NewEngine(EngineOpts{}, querier, NoLimits, log.NewNopLogger())
+
+	for _, tc := range []struct {
+		qs       string
+		expected float64
+	}{
+		{`changes({app="foo"} | unwrap v [1m])`, 59},
+		{`deriv({app="foo"} | unwrap v [1m])`, 1},
+		{`predict_linear({app="foo"} | unwrap v [1m], 60)`, 300},
+		{`holt_winters({app="foo"} | unwrap v [1m], 0.5, 0.5)`, 240},
+		// The inner expression is evaluated at 60s, 120s, 180s and 240s.
+		{`deriv(max_over_time({app="foo"} | unwrap v [1m])[4m:1m])`, 1},
+		{`predict_linear(max_over_time({app="foo"} | unwrap v [1m])[4m:1m], 60)`, 300},
+	} {
+		t.Run(tc.qs, func(t *testing.T) {
+			params, err := NewLiteralParams(tc.qs, time.Unix(240, 0), time.Unix(240, 0), 0, 0, logproto.FORWARD, 0, nil, nil)
+			require.NoError(t, err)
+
+			res, err := eng.Query(params).Exec(user.InjectOrgID(context.Background(), "fake"))
+			require.NoError(t, err)
+
+			vec, ok := res.Data.(promql.Vector)
+			require.True(t, ok)
+			require.Len(t, vec, 1)
+			require.InDelta(t, tc.expected, vec[0].F, 1e-9)
+		})
+	}
+}
+
 func sampleIter(negative bool) iter.PeekingSampleIterator {
 	return iter.NewPeekingSampleIterator(
 		iter.NewSortSampleIterator([]iter.SampleIterator{
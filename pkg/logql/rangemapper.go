@@ -22,6 +22,14 @@ var splittableVectorOp = map[string]struct{}{
 	syntax.OpTypeTopK:     {},
 	syntax.OpTypeSort:     {},
 	syntax.OpTypeSortDesc: {},
+
+	syntax.OpTypeHistogramQuantile: {},
+
+	syntax.OpTypeCountValues: {},
+	syntax.OpTypeQuantile:    {},
+	syntax.OpTypeGroup:       {},
+	syntax.OpTypeLimitK:      {},
+	syntax.OpTypeLimitRatio:  {},
 }
 
 var splittableRangeVectorOp = map[string]struct{}{
@@ -32,6 +40,7 @@ var splittableRangeVectorOp = map[string]struct{}{
 	syntax.OpRangeTypeSum:       {},
 	syntax.OpRangeTypeMax:       {},
 	syntax.OpRangeTypeMin:       {},
+	syntax.OpRangeTypeHistogram: {},
 }
 
 // RangeMapper is used to rewrite LogQL sample expressions into multiple
@@ -140,6 +149,15 @@ func (m RangeMapper) Map(expr syntax.SampleExpr, vectorAggrPushdown *syntax.Vect
 		return m.mapVectorAggregationExpr(e, recorder)
 	case *syntax.RangeAggregationExpr:
 		return m.mapRangeAggregationExpr(e, vectorAggrPushdown, recorder), nil
+	case *syntax.SubqueryAggregationExpr:
+		// The inner expression is split at each step of the subquery. The
+		// vector aggregation cannot be pushed down past the subquery.
+		innerMapped, err := m.Map(e.Left.Left, nil, recorder)
+		if err != nil {
+			return nil, err
+		}
+		e.Left.Left = innerMapped
+		return e, nil
 	case *syntax.BinOpExpr:
 		lhsMapped, err := m.Map(e.SampleExpr, vectorAggrPushdown, recorder)
 		if err != nil {
@@ -175,6 +193,25 @@ func (m RangeMapper) Map(expr syntax.SampleExpr, vectorAggrPushdown *syntax.Vect
 		}
 		e.Left = lhsMapped
 		return e, nil
+	case *syntax.LabelJoinExpr:
+		lhsMapped, err := m.Map(e.Left, nil, recorder)
+		if err != nil {
+			return nil, err
+		}
+		e.Left = lhsMapped
+		return e, nil
+	case *syntax.FunctionExpr:
+		if e.Left == nil {
+			return e, nil
+		}
+		// The vector aggregation cannot be pushed down past the function,
+		// which does not commute with it.
+		lhsMapped, err := m.Map(e.Left, nil, recorder)
+		if err != nil {
+			return nil, err
+		}
+		e.Left = lhsMapped
+		return e, nil
 	case *syntax.LiteralExpr:
 		return e, nil
 	case *syntax.VectorExpr:
@@ -207,7 +244,7 @@ func getRangeInterval(expr syntax.SampleExpr) time.Duration {
 }
 
 // hasLabelExtractionStage returns true if an expression contains a stage for label extraction,
-// such as `| json` or `| logfmt`, that would result in an exploding amount of series in downstream queries.
+// such as `| json`, `| logfmt` or `| xml`, that would result in an exploding amount of series in downstream queries.
 func hasLabelExtractionStage(expr syntax.SampleExpr) bool {
 	found := false
 	expr.Walk(func(e syntax.Expr) bool {
@@ -217,7 +254,7 @@ func hasLabelExtractionStage(expr syntax.SampleExpr) bool {
 		case *syntax.LineParserExpr:
 			// It will **not** return true for `regexp`, `unpack` and `pattern`, since these label extraction
 			// stages can control how many labels, and therefore the resulting amount of series, are extracted.
-			if concrete.Op == syntax.OpParserTypeJSON {
+			if concrete.Op == syntax.OpParserTypeJSON || concrete.Op == syntax.OpParserTypeXML {
 				found = true
 			}
 		}
@@ -462,10 +499,11 @@ func (m RangeMapper) mapVectorAggregationExpr(expr *syntax.VectorAggregationExpr
 
 	// In order to minimize the amount of streams on the downstream query,
 	// we can push down the outer vector aggregation to the downstream query.
-	// This does not work for `count()` and `topk()`, though.
+	// This does not work for `count()`, `topk()`, `histogram_quantile()` and
+	// the aggregations which do not sum the series of their groups, though.
 	// We also do not want to push down, if the inner expression is a binary operation.
 	var vectorAggrPushdown *syntax.VectorAggregationExpr
-	if _, ok := expr.Left.(*syntax.BinOpExpr); !ok && expr.Operation != syntax.OpTypeCount && expr.Operation != syntax.OpTypeTopK && expr.Operation != syntax.OpTypeSort && expr.Operation != syntax.OpTypeSortDesc {
+	if _, ok := expr.Left.(*syntax.BinOpExpr); !ok && pushdownVectorOp(expr.Operation) {
 		vectorAggrPushdown = expr
 	}
 
@@ -480,9 +518,25 @@ func (m RangeMapper) mapVectorAggregationExpr(expr *syntax.VectorAggregationExpr
 		Grouping:  expr.Grouping,
 		Params:    expr.Params,
 		Operation: expr.Operation,
+		Quantile:  expr.Quantile,
+		Ratio:     expr.Ratio,
+		Label:     expr.Label,
 	}, nil
 }
 
+// pushdownVectorOp returns whether a vector aggregation can be pushed down
+// to the downstream queries of its inner range aggregation.
+func pushdownVectorOp(op string) bool {
+	switch op {
+	case syntax.OpTypeCount, syntax.OpTypeTopK, syntax.OpTypeSort, syntax.OpTypeSortDesc,
+		syntax.OpTypeHistogramQuantile, syntax.OpTypeCountValues, syntax.OpTypeQuantile,
+		syntax.OpTypeGroup, syntax.OpTypeLimitK, syntax.OpTypeLimitRatio:
+		return false
+	default:
+		return true
+	}
+}
+
 // mapRangeAggregationExpr maps expr into a new SampleExpr with multiple downstream subqueries split by range interval
 // Optimization: in order to reduce the returned stream from the inner downstream functions, in case a range aggregation
 // expression is aggregated by a vector aggregation expression with a label grouping, the downstream expression can be
@@ -499,6 +553,13 @@ func (m RangeMapper) mapRangeAggregationExpr(expr *syntax.RangeAggregationExpr,
 		return expr
 	}
 
+	if expr.Operation == syntax.OpRangeTypeHistogram {
+		// The buckets of each split would be made cumulative with different
+		// boundaries, so vector aggregations are only applied to the merged
+		// histograms.
+		vectorAggrPushdown = nil
+	}
+
 	labelExtractor := hasLabelExtractionStage(expr)
 
 	// Downstream queries with label extractors can potentially produce a huge amount of series
@@ -544,6 +605,12 @@ func (m RangeMapper) mapRangeAggregationExpr(expr *syntax.RangeAggregationExpr,
 			return expr
 		}
 		return m.sumOverFullRange(expr, vectorAggrPushdown, syntax.OpRangeTypeBytes, rangeInterval, recorder)
+	case syntax.OpRangeTypeHistogram:
+		// histogram_over_time({app="foo"} | unwrap bar [2m]) =>
+		// HistogramCumulate<__histogram_buckets_over_time__({app="foo"} | unwrap bar [1m]) ++ __histogram_buckets_over_time__({app="foo"} | unwrap bar [1m] offset 1m)>
+		buckets := *expr
+		buckets.Operation = syntax.OpRangeTypeHistogramBuckets
+		return &HistogramCumulateExpr{buckets: m.mapConcatSampleExpr(&buckets, rangeInterval, recorder)}
 	default:
 		// this should not be reachable.
 		// If an operation is splittable it should have an optimization listed.
@@ -563,6 +630,8 @@ func (m RangeMapper) mapRangeAggregationExpr(expr *syntax.RangeAggregationExpr,
 // supported.
 // A binary expression is splittable, if both the left and the right-hand side
 // are splittable.
+// A subquery aggregation is splittable, if its inner expression is splittable.
+// A function is splittable, if its argument is splittable.
 func isSplittableByRange(expr syntax.SampleExpr) bool {
 	switch e := expr.(type) {
 	case *syntax.VectorAggregationExpr:
@@ -579,6 +648,12 @@ func isSplittableByRange(expr syntax.SampleExpr) bool {
 		return isSplittableByRange(e.SampleExpr) || literalLHS && isSplittableByRange(e.RHS) || literalRHS
 	case *syntax.LabelReplaceExpr:
 		return isSplittableByRange(e.Left)
+	case *syntax.SubqueryAggregationExpr:
+		return isSplittableByRange(e.Left.Left)
+	case *syntax.LabelJoinExpr:
+		return isSplittableByRange(e.Left)
+	case *syntax.FunctionExpr:
+		return e.Left != nil && isSplittableByRange(e.Left)
 	case *syntax.VectorExpr:
 		return false
 	default:
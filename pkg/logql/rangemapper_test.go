@@ -195,6 +195,51 @@ func Test_SplitRangeVectorMapping(t *testing.T) {
 			)`,
 			3,
 		},
+		{
+			`histogram_over_time({app="foo"} | unwrap bar [3m]) by (baz)`,
+			`HistogramCumulate<
+				downstream<__histogram_buckets_over_time__({app="foo"} | unwrap bar [1m] offset 2m0s) by (baz), shard=<nil>>
+				++ downstream<__histogram_buckets_over_time__({app="foo"} | unwrap bar [1m] offset 1m0s) by (baz), shard=<nil>>
+				++ downstream<__histogram_buckets_over_time__({app="foo"} | unwrap bar [1m]) by (baz), shard=<nil>>
+			>`,
+			3,
+		},
+		{
+			// the vector aggregation is not pushed down
+			`histogram_quantile(0.99, sum by (le) (histogram_over_time({app="foo"} | unwrap bar [3m])))`,
+			`histogram_quantile(0.99,
+				sum by (le) (
+					HistogramCumulate<
+						downstream<__histogram_buckets_over_time__({app="foo"} | unwrap bar [1m] offset 2m0s), shard=<nil>>
+						++ downstream<__histogram_buckets_over_time__({app="foo"} | unwrap bar [1m] offset 1m0s), shard=<nil>>
+						++ downstream<__histogram_buckets_over_time__({app="foo"} | unwrap bar [1m]), shard=<nil>>
+					>
+				)
+			)`,
+			3,
+		},
+		{
+			`group by (a) (count_over_time({app="foo"}[3m]))`,
+			`group by (a) (
+				sum without () (
+					downstream<count_over_time({app="foo"}[1m] offset 2m0s), shard=<nil>>
+					++ downstream<count_over_time({app="foo"}[1m] offset 1m0s), shard=<nil>>
+					++ downstream<count_over_time({app="foo"}[1m]), shard=<nil>>
+				)
+			)`,
+			3,
+		},
+		{
+			`quantile(0.9, count_over_time({app="foo"}[3m])) by (a)`,
+			`quantile by (a) (0.9,
+				sum without () (
+					downstream<count_over_time({app="foo"}[1m] offset 2m0s), shard=<nil>>
+					++ downstream<count_over_time({app="foo"}[1m] offset 1m0s), shard=<nil>>
+					++ downstream<count_over_time({app="foo"}[1m]), shard=<nil>>
+				)
+			)`,
+			3,
+		},
 		{
 			`sum_over_time({app="foo"} | unwrap bar [3m])`,
 			`sum without () (
@@ -1780,6 +1825,63 @@ func Test_SplitRangeVectorMapping(t *testing.T) {
 			3,
 		},
 
+		// functions
+		{
+			`abs(sum by (baz) (sum_over_time({app="foo"} | unwrap bar [3m])))`,
+			`abs(
+				sum by (baz) (
+					sum without () (
+						downstream<sum by (baz) (sum_over_time({app="foo"} | unwrap bar [1m] offset 2m0s)), shard=<nil>>
+						++ downstream<sum by (baz) (sum_over_time({app="foo"} | unwrap bar [1m] offset 1m0s)), shard=<nil>>
+						++ downstream<sum by (baz) (sum_over_time({app="foo"} | unwrap bar [1m])), shard=<nil>>
+					)
+				)
+			)`,
+			3,
+		},
+		{
+			`sum(abs(sum_over_time({app="foo"} | unwrap bar [3m])))`,
+			`sum(
+				abs(
+					sum without () (
+						downstream<sum_over_time({app="foo"} | unwrap bar [1m] offset 2m0s), shard=<nil>>
+						++ downstream<sum_over_time({app="foo"} | unwrap bar [1m] offset 1m0s), shard=<nil>>
+						++ downstream<sum_over_time({app="foo"} | unwrap bar [1m]), shard=<nil>>
+					)
+				)
+			)`,
+			3,
+		},
+
+		// subqueries
+		{
+			`max_over_time(sum by (baz) (count_over_time({app="foo"}[3m]))[1h:1m])`,
+			`max_over_time(
+				sum by (baz) (
+					sum without () (
+						downstream<sum by (baz) (count_over_time({app="foo"} [1m] offset 2m0s)), shard=<nil>>
+						++ downstream<sum by (baz) (count_over_time({app="foo"} [1m] offset 1m0s)), shard=<nil>>
+						++ downstream<sum by (baz) (count_over_time({app="foo"} [1m])), shard=<nil>>
+					)
+				)[1h:1m]
+			)`,
+			3,
+		},
+		{
+			`predict_linear(sum by (baz) (count_over_time({app="foo"}[3m]))[1h:1m], 3600)`,
+			`predict_linear(
+				sum by (baz) (
+					sum without () (
+						downstream<sum by (baz) (count_over_time({app="foo"} [1m] offset 2m0s)), shard=<nil>>
+						++ downstream<sum by (baz) (count_over_time({app="foo"} [1m] offset 1m0s)), shard=<nil>>
+						++ downstream<sum by (baz) (count_over_time({app="foo"} [1m])), shard=<nil>>
+					)
+				)[1h:1m],
+				3600
+			)`,
+			3,
+		},
+
 		// label_replace
 		{
 			`label_replace(sum by (baz) (count_over_time({app="foo"}[3m])), "x", "$1", "a", "(.*)")`,
@@ -1841,6 +1943,22 @@ func Test_SplitRangeVectorMapping_Noop(t *testing.T) {
 			`sum(avg_over_time({app="foo"} | unwrap bar[3m]))`,
 			`sum(avg_over_time({app="foo"} | unwrap bar[3m]))`,
 		},
+		{
+			`deriv({app="foo"} | unwrap bar[3m])`,
+			`deriv({app="foo"} | unwrap bar[3m])`,
+		},
+		{
+			`sum(changes({app="foo"} | unwrap bar[3m]))`,
+			`sum(changes({app="foo"} | unwrap bar[3m]))`,
+		},
+		{
+			`predict_linear({app="foo"} | unwrap bar[3m], 3600)`,
+			`predict_linear({app="foo"} | unwrap bar[3m],3600)`,
+		},
+		{
+			`holt_winters({app="foo"} | unwrap bar[3m], 0.5, 0.1) by (pod)`,
+			`holt_winters({app="foo"} | unwrap bar[3m],0.5,0.1) by (pod)`,
+		},
 
 		// should be noop if range interval is lower or equal to split interval (1m)
 		{
@@ -1858,6 +1976,10 @@ func Test_SplitRangeVectorMapping_Noop(t *testing.T) {
 			`count_over_time({app="foo"} | json [3m])`,
 			`count_over_time({app="foo"} | json [3m])`,
 		},
+		{
+			`count_over_time({app="foo"} | xml [3m])`,
+			`count_over_time({app="foo"} | xml [3m])`,
+		},
 		{
 			`sum_over_time({app="foo"} | logfmt | unwrap bar [3m])`,
 			`sum_over_time({app="foo"} | logfmt | unwrap bar [3m])`,
@@ -0,0 +1,91 @@
+package logql
+
+import (
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/log"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/storage/stores/shipper/indexshipper/tsdb/index"
+)
+
+// Streams sampled by `| sample <ratio> by stream` are the streams whose
+// fingerprint falls in the first power of two fraction of the fingerprint
+// space. Selecting them is done by reading a single shard, so the chunks of
+// other streams are never fetched.
+
+// streamSampleShard returns the shard holding the streams sampled by the
+// stream sample stages of expr, if any.
+func streamSampleShard(expr syntax.Expr) (index.ShardAnnotation, bool) {
+	_, ratio := syntax.SamplingRatios(expr)
+	if ratio >= 1 {
+		return index.ShardAnnotation{}, false
+	}
+	return index.NewShard(0, 1<<log.StreamSampleBits(ratio)), true
+}
+
+// sampledShards returns the shards to query for expr. Unsharded queries with
+// stream sample stages only read the sampled streams. Sharded queries already
+// are restricted to the sampled streams by the ShardMapper.
+func sampledShards(expr syntax.Expr, shards []string) []string {
+	if len(shards) > 0 {
+		return shards
+	}
+	shard, ok := streamSampleShard(expr)
+	if !ok {
+		return shards
+	}
+	return []string{NewPowerOfTwoShard(shard).String()}
+}
+
+// sampleShards drops the shards holding none of the streams sampled by the
+// stream sample stages of expr and narrows the shards holding some of them.
+func sampleShards(expr syntax.Expr, shards []ShardWithChunkRefs) []ShardWithChunkRefs {
+	sample, ok := streamSampleShard(expr)
+	if !ok || len(shards) == 0 {
+		return shards
+	}
+	_, end := sample.GetFromThrough()
+
+	res := make([]ShardWithChunkRefs, 0, len(shards))
+	for _, shard := range shards {
+		from, through := shard.GetFromThrough()
+		switch {
+		case from >= end:
+			continue
+		case through <= end:
+			res = append(res, shard)
+			continue
+		}
+
+		// The shard holds both sampled streams and streams which are not.
+		if shard.Bounded == nil {
+			// Only the first power of two shard of a smaller factor than the
+			// sample overlaps it, and it is a superset of the sample.
+			res = append(res, ShardWithChunkRefs{Shard: NewPowerOfTwoShard(sample)})
+			continue
+		}
+
+		narrowed := *shard.Bounded
+		narrowed.Bounds.Max = end - 1
+		res = append(res, ShardWithChunkRefs{
+			Shard:  NewBoundedShard(narrowed),
+			chunks: sampleChunkRefs(shard.chunks, end),
+		})
+	}
+	return res
+}
+
+// sampleChunkRefs returns the chunk refs of streams with a fingerprint lower than end.
+func sampleChunkRefs(chunks *logproto.ChunkRefGroup, end model.Fingerprint) *logproto.ChunkRefGroup {
+	if chunks == nil {
+		return nil
+	}
+	res := &logproto.ChunkRefGroup{Refs: make([]*logproto.ChunkRef, 0, len(chunks.Refs))}
+	for _, ref := range chunks.Refs {
+		if model.Fingerprint(ref.Fingerprint) < end {
+			res.Refs = append(res.Refs, ref)
+		}
+	}
+	return res
+}
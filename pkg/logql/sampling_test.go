@@ -0,0 +1,74 @@
+package logql
+
+import (
+	"math"
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/storage/stores/shipper/indexshipper/tsdb/index"
+)
+
+func TestSampledShards(t *testing.T) {
+	expr := syntax.MustParseExpr(`count_over_time({app="foo"} | sample 0.25 by stream [1m])`)
+	require.Equal(t, []string{"0_of_4"}, sampledShards(expr, nil))
+	require.Equal(t, []string{"1_of_16"}, sampledShards(expr, []string{"1_of_16"}))
+
+	expr = syntax.MustParseExpr(`count_over_time({app="foo"} | sample 0.25 [1m])`)
+	require.Empty(t, sampledShards(expr, nil))
+}
+
+func TestSampleShards(t *testing.T) {
+	expr := syntax.MustParseExpr(`count_over_time({app="foo"} | sample 0.25 by stream [1m])`)
+	end := model.Fingerprint(1 << 62)
+
+	t.Run("power of two", func(t *testing.T) {
+		for _, tc := range []struct {
+			of       uint32
+			expected []string
+		}{
+			{of: 1, expected: []string{"0_of_4"}},
+			{of: 2, expected: []string{"0_of_4"}},
+			{of: 4, expected: []string{"0_of_4"}},
+			{of: 8, expected: []string{"0_of_8", "1_of_8"}},
+		} {
+			var shards []ShardWithChunkRefs
+			for i := uint32(0); i < tc.of; i++ {
+				shards = append(shards, ShardWithChunkRefs{Shard: NewPowerOfTwoShard(index.NewShard(i, tc.of))})
+			}
+
+			var res []string
+			for _, s := range sampleShards(expr, shards) {
+				res = append(res, s.String())
+			}
+			require.Equal(t, tc.expected, res, "factor %d", tc.of)
+		}
+	})
+
+	t.Run("bounded", func(t *testing.T) {
+		chunks := &logproto.ChunkRefGroup{Refs: []*logproto.ChunkRef{
+			{Fingerprint: uint64(end) - 10},
+			{Fingerprint: uint64(end)},
+			{Fingerprint: uint64(end) + 10},
+		}}
+		shards := []ShardWithChunkRefs{
+			*NewBoundedShard(logproto.Shard{Bounds: logproto.FPBounds{Min: 0, Max: 100}}).Bind(nil),
+			*NewBoundedShard(logproto.Shard{Bounds: logproto.FPBounds{Min: 101, Max: end + 100}}).Bind(chunks),
+			*NewBoundedShard(logproto.Shard{Bounds: logproto.FPBounds{Min: end + 101, Max: math.MaxUint64}}).Bind(nil),
+		}
+
+		res := sampleShards(expr, shards)
+		require.Len(t, res, 2)
+		require.Equal(t, shards[0], res[0])
+		require.Equal(t, logproto.FPBounds{Min: 101, Max: end - 1}, res[1].Bounded.Bounds)
+		require.Equal(t, []*logproto.ChunkRef{{Fingerprint: uint64(end) - 10}}, res[1].chunks.Refs)
+	})
+
+	t.Run("no stream sampling", func(t *testing.T) {
+		shards := []ShardWithChunkRefs{{Shard: NewPowerOfTwoShard(index.NewShard(1, 2))}}
+		require.Equal(t, shards, sampleShards(syntax.MustParseExpr(`count_over_time({app="foo"} | sample 0.25 [1m])`), shards))
+	})
+}
@@ -13,19 +13,21 @@ import (
 )
 
 const (
-	ShardLastOverTime     = "last_over_time"
-	ShardFirstOverTime    = "first_over_time"
-	ShardQuantileOverTime = "quantile_over_time"
-	SupportApproxTopk     = "approx_topk"
+	ShardLastOverTime        = "last_over_time"
+	ShardFirstOverTime       = "first_over_time"
+	ShardQuantileOverTime    = "quantile_over_time"
+	ShardApproxCountDistinct = "approx_count_distinct"
+	SupportApproxTopk        = "approx_topk"
 )
 
 type ShardMapper struct {
-	shards                   ShardingStrategy
-	metrics                  *MapperMetrics
-	quantileOverTimeSharding bool
-	lastOverTimeSharding     bool
-	firstOverTimeSharding    bool
-	approxTopkSupport        bool
+	shards                      ShardingStrategy
+	metrics                     *MapperMetrics
+	quantileOverTimeSharding    bool
+	lastOverTimeSharding        bool
+	firstOverTimeSharding       bool
+	approxCountDistinctSharding bool
+	approxTopkSupport           bool
 }
 
 func NewShardMapper(strategy ShardingStrategy, metrics *MapperMetrics, shardAggregation []string) ShardMapper {
@@ -45,6 +47,8 @@ func NewShardMapper(strategy ShardingStrategy, metrics *MapperMetrics, shardAggr
 			mapper.lastOverTimeSharding = true
 		case ShardFirstOverTime:
 			mapper.firstOverTimeSharding = true
+		case ShardApproxCountDistinct:
+			mapper.approxCountDistinctSharding = true
 		case SupportApproxTopk:
 			mapper.approxTopkSupport = true
 		}
@@ -78,6 +82,17 @@ func (m ShardMapper) Parse(parsed syntax.Expr) (noop bool, bytesPerShard uint64,
 	return noop, bytesPerShard, mapped, err
 }
 
+// shardsFor returns the shards to query for expr. When expr samples streams,
+// only the shards holding sampled streams are returned, so whole chunks are
+// skipped rather than filtered line by line.
+func (m ShardMapper) shardsFor(expr syntax.Expr) ([]ShardWithChunkRefs, uint64, error) {
+	shards, bytesPerShard, err := m.shards.Shards(expr)
+	if err != nil {
+		return nil, 0, err
+	}
+	return sampleShards(expr, shards), bytesPerShard, nil
+}
+
 func (m ShardMapper) Map(expr syntax.Expr, r *downstreamRecorder, topLevel bool) (syntax.Expr, uint64, error) {
 	// immediately clone the passed expr to avoid mutating the original
 	expr, err := syntax.Clone(expr)
@@ -99,8 +114,14 @@ func (m ShardMapper) Map(expr syntax.Expr, r *downstreamRecorder, topLevel bool)
 		return m.mapVectorAggregationExpr(e, r, topLevel)
 	case *syntax.LabelReplaceExpr:
 		return m.mapLabelReplaceExpr(e, r, topLevel)
+	case *syntax.LabelJoinExpr:
+		return m.mapLabelJoinExpr(e, r, topLevel)
+	case *syntax.FunctionExpr:
+		return m.mapFunctionExpr(e, r, topLevel)
 	case *syntax.RangeAggregationExpr:
 		return m.mapRangeAggregationExpr(e, r, topLevel)
+	case *syntax.SubqueryAggregationExpr:
+		return m.mapSubqueryAggregationExpr(e, r)
 	case *syntax.BinOpExpr:
 		return m.mapBinOpExpr(e, r, topLevel)
 	default:
@@ -162,7 +183,7 @@ func (m ShardMapper) mapBinOpExpr(e *syntax.BinOpExpr, r *downstreamRecorder, to
 
 func (m ShardMapper) mapLogSelectorExpr(expr syntax.LogSelectorExpr, r *downstreamRecorder) (syntax.LogSelectorExpr, uint64, error) {
 	var head *ConcatLogSelectorExpr
-	shards, maxBytesPerShard, err := m.shards.Shards(expr)
+	shards, maxBytesPerShard, err := m.shardsFor(expr)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -191,7 +212,7 @@ func (m ShardMapper) mapLogSelectorExpr(expr syntax.LogSelectorExpr, r *downstre
 
 func (m ShardMapper) mapSampleExpr(expr syntax.SampleExpr, r *downstreamRecorder) (syntax.SampleExpr, uint64, error) {
 	var head *ConcatSampleExpr
-	shards, maxBytesPerShard, err := m.shards.Shards(expr)
+	shards, maxBytesPerShard, err := m.shardsFor(expr)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -232,6 +253,9 @@ func (m ShardMapper) wrappedShardedVectorAggr(expr *syntax.VectorAggregationExpr
 		Grouping:  expr.Grouping,
 		Params:    expr.Params,
 		Operation: expr.Operation,
+		Quantile:  expr.Quantile,
+		Ratio:     expr.Ratio,
+		Label:     expr.Label,
 	}, bytesPerShard, nil
 }
 
@@ -291,6 +315,22 @@ func (m ShardMapper) mapVectorAggregationExpr(expr *syntax.VectorAggregationExpr
 				Grouping:  expr.Grouping,
 				Operation: syntax.OpTypeSum,
 			}, bytesPerShard, nil
+		case syntax.OpTypeGroup, syntax.OpTypeLimitK, syntax.OpTypeLimitRatio:
+			// group(x) -> group(group(x, shard=1) ++ group(x, shard=2)...)
+			// limitk(k, x) -> limitk(k, limitk(k, x, shard=1) ++ limitk(k, x, shard=2)...)
+			// limit_ratio(r, x) -> limit_ratio(r, limit_ratio(r, x, shard=1) ++ limit_ratio(r, x, shard=2)...)
+			return m.wrappedShardedVectorAggr(expr, r)
+		case syntax.OpTypeCountValues:
+			// count_values("v", x) by (a) -> sum by (a, v) (count_values("v", x, shard=1) by (a) ++ count_values("v", x, shard=2) by (a)...)
+			sharded, bytesPerShard, err := m.mapSampleExpr(expr, r)
+			if err != nil {
+				return nil, 0, err
+			}
+			return &syntax.VectorAggregationExpr{
+				Left:      sharded,
+				Grouping:  countValuesGrouping(expr.Grouping, expr.Label),
+				Operation: syntax.OpTypeSum,
+			}, bytesPerShard, nil
 		case syntax.OpTypeApproxTopK:
 			if !m.approxTopkSupport {
 				return nil, 0, fmt.Errorf("approx_topk is not enabled. See -limits.shard_aggregations")
@@ -320,6 +360,10 @@ func (m ShardMapper) mapVectorAggregationExpr(expr *syntax.VectorAggregationExpr
 				"operation", expr.Operation,
 			)
 			return m.mapApproxTopk(expr, true)
+		case syntax.OpTypeApproxCountDistinct:
+			if m.approxCountDistinctSharding && seriesExistOnShards(expr.Left) {
+				return m.mapApproxCountDistinct(expr)
+			}
 		}
 	}
 
@@ -339,6 +383,9 @@ func (m ShardMapper) mapVectorAggregationExpr(expr *syntax.VectorAggregationExpr
 		Grouping:  expr.Grouping,
 		Params:    expr.Params,
 		Operation: expr.Operation,
+		Quantile:  expr.Quantile,
+		Ratio:     expr.Ratio,
+		Label:     expr.Label,
 	}, bytesPerShard, nil
 }
 
@@ -405,6 +452,63 @@ func (m ShardMapper) mapApproxTopk(expr *syntax.VectorAggregationExpr, forceNoSh
 	}, bytesPerShard, nil
 }
 
+// mapApproxCountDistinct shards the count of distinct series in each group.
+// Each shard counts the series it holds in HyperLogLog sketches, which are
+// merged by the frontend. Merging sketches counts a series once even if it
+// exists on several shards.
+func (m ShardMapper) mapApproxCountDistinct(expr *syntax.VectorAggregationExpr) (syntax.SampleExpr, uint64, error) {
+	shards, bytesPerShard, err := m.shardsFor(expr)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(shards) == 0 {
+		return noOp(expr, m.shards.Resolver())
+	}
+
+	// approx_count_distinct by (foo) (inner) ->
+	// hyperloglog_eval(
+	//   __hyperloglog__ by (foo) (inner, shard=1) ++ __hyperloglog__ by (foo) (inner, shard=2)...
+	// )
+	expr.Operation = syntax.OpTypeHyperLogLog
+	downstreams := make([]DownstreamSampleExpr, 0, len(shards))
+	for i := range shards {
+		downstreams = append(downstreams, DownstreamSampleExpr{
+			shard:      &shards[i],
+			SampleExpr: expr,
+		})
+	}
+
+	return &HyperLogLogEvalExpr{
+		downstreams: downstreams,
+	}, bytesPerShard, nil
+}
+
+// seriesExistOnShards returns whether the series of expr are the union of the
+// series of expr evaluated on each shard. Their values may differ, which does
+// not matter when counting series.
+func seriesExistOnShards(expr syntax.SampleExpr) bool {
+	ok := true
+	expr.Walk(func(e syntax.Expr) bool {
+		switch e := e.(type) {
+		case *syntax.RangeAggregationExpr:
+			// absent_over_time returns a series for shards without any data.
+			ok = ok && e.Operation != syntax.OpRangeTypeAbsent
+		case *syntax.VectorAggregationExpr:
+			switch e.Operation {
+			case syntax.OpTypeTopK, syntax.OpTypeBottomK, syntax.OpTypeApproxTopK:
+				// The series kept depend on their values.
+				ok = false
+			}
+		case *syntax.BinOpExpr, *syntax.VectorExpr, *syntax.LiteralExpr:
+			// Filters and set operations depend on values, and literals
+			// are not part of any shard.
+			ok = false
+		}
+		return ok
+	})
+	return ok
+}
+
 func (m ShardMapper) mapLabelReplaceExpr(expr *syntax.LabelReplaceExpr, r *downstreamRecorder, topLevel bool) (syntax.SampleExpr, uint64, error) {
 	subMapped, bytesPerShard, err := m.Map(expr.Left, r, topLevel)
 	if err != nil {
@@ -415,6 +519,53 @@ func (m ShardMapper) mapLabelReplaceExpr(expr *syntax.LabelReplaceExpr, r *downs
 	return &cpy, bytesPerShard, nil
 }
 
+func (m ShardMapper) mapLabelJoinExpr(expr *syntax.LabelJoinExpr, r *downstreamRecorder, topLevel bool) (syntax.SampleExpr, uint64, error) {
+	subMapped, bytesPerShard, err := m.Map(expr.Left, r, topLevel)
+	if err != nil {
+		return nil, 0, err
+	}
+	cpy := *expr
+	cpy.Left = subMapped.(syntax.SampleExpr)
+	return &cpy, bytesPerShard, nil
+}
+
+// mapFunctionExpr shards the argument of a function, which is then applied by
+// the frontend to each sample of the merged results.
+func (m ShardMapper) mapFunctionExpr(expr *syntax.FunctionExpr, r *downstreamRecorder, topLevel bool) (syntax.SampleExpr, uint64, error) {
+	if expr.Left == nil {
+		return expr, 0, nil
+	}
+	subMapped, bytesPerShard, err := m.Map(expr.Left, r, topLevel)
+	if err != nil {
+		return nil, 0, err
+	}
+	cpy := *expr
+	cpy.Left = subMapped.(syntax.SampleExpr)
+	return &cpy, bytesPerShard, nil
+}
+
+// mapSubqueryAggregationExpr shards the inner expression of a subquery, whose
+// steps are then aggregated by the frontend.
+func (m ShardMapper) mapSubqueryAggregationExpr(expr *syntax.SubqueryAggregationExpr, r *downstreamRecorder) (syntax.SampleExpr, uint64, error) {
+	subMapped, bytesPerShard, err := m.Map(expr.Left.Left, r, false)
+	if err != nil {
+		return nil, 0, err
+	}
+	if isNoOp(expr.Left.Left, subMapped) {
+		return noOp(expr, m.shards.Resolver())
+	}
+	sampleExpr, ok := subMapped.(syntax.SampleExpr)
+	if !ok {
+		return nil, 0, badASTMapping(subMapped)
+	}
+
+	subquery := *expr.Left
+	subquery.Left = sampleExpr
+	cpy := *expr
+	cpy.Left = &subquery
+	return &cpy, bytesPerShard, nil
+}
+
 // These functions require a different merge strategy than the default
 // concatenation.
 // This is because the same label sets may exist on multiple shards when label-reducing parsing is applied or when
@@ -434,6 +585,11 @@ var rangeMergeMap = map[string]string{
 }
 
 func (m ShardMapper) mapRangeAggregationExpr(expr *syntax.RangeAggregationExpr, r *downstreamRecorder, topLevel bool) (syntax.SampleExpr, uint64, error) {
+	if expr.Operation == syntax.OpRangeTypeApproxCountDistinct {
+		// Sketches are merged by the frontend, so unlike other aggregations
+		// this one is sharded wherever it is nested in the query.
+		return m.mapApproxCountDistinctOverTime(expr, r)
+	}
 	if !expr.Shardable(topLevel) {
 		return noOp(expr, m.shards.Resolver())
 	}
@@ -473,6 +629,19 @@ func (m ShardMapper) mapRangeAggregationExpr(expr *syntax.RangeAggregationExpr,
 			Operation: merger,
 		}, bytes, err
 
+	case syntax.OpRangeTypeHistogram:
+		// Every histogram gets the buckets of all histograms, so the buckets
+		// are made cumulative once the shards are merged, even if each series
+		// only exists on one shard.
+		// histogram_over_time(_) by (foo) -> HistogramCumulate<__histogram_buckets_over_time__(_) by (foo) ++ ...>
+		buckets := *expr
+		buckets.Operation = syntax.OpRangeTypeHistogramBuckets
+		mapped, bytes, err := m.mapSampleExpr(&buckets, r)
+		if err != nil {
+			return nil, 0, err
+		}
+		return &HistogramCumulateExpr{buckets: mapped}, bytes, nil
+
 	case syntax.OpRangeTypeAvg:
 		potentialConflict := syntax.ReducesLabels(expr)
 		if !potentialConflict && (expr.Grouping == nil || expr.Grouping.Noop()) {
@@ -590,7 +759,7 @@ func (m ShardMapper) mapRangeAggregationExpr(expr *syntax.RangeAggregationExpr,
 			return m.mapSampleExpr(expr, r)
 		}
 
-		shards, bytesPerShard, err := m.shards.Shards(expr)
+		shards, bytesPerShard, err := m.shardsFor(expr)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -622,7 +791,7 @@ func (m ShardMapper) mapRangeAggregationExpr(expr *syntax.RangeAggregationExpr,
 			return m.mapSampleExpr(expr, r)
 		}
 
-		shards, bytesPerShard, err := m.shards.Shards(expr)
+		shards, bytesPerShard, err := m.shardsFor(expr)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -649,6 +818,44 @@ func (m ShardMapper) mapRangeAggregationExpr(expr *syntax.RangeAggregationExpr,
 	}
 }
 
+func (m ShardMapper) mapApproxCountDistinctOverTime(expr *syntax.RangeAggregationExpr, r *downstreamRecorder) (syntax.SampleExpr, uint64, error) {
+	if !m.approxCountDistinctSharding || !expr.Left.Shardable(false) {
+		return noOp(expr, m.shards.Resolver())
+	}
+
+	// Without label reducing stages or grouping, a series only exists on a
+	// single shard and is counted exactly there.
+	potentialConflict := syntax.ReducesLabels(expr)
+	if !potentialConflict && (expr.Grouping == nil || expr.Grouping.Noop()) {
+		return m.mapSampleExpr(expr, r)
+	}
+
+	shards, bytesPerShard, err := m.shardsFor(expr)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(shards) == 0 {
+		return noOp(expr, m.shards.Resolver())
+	}
+
+	// approx_count_distinct_over_time() by (foo) ->
+	// hyperloglog_eval(
+	//   __hyperloglog_over_time__() by (foo, shard=1) ++ __hyperloglog_over_time__() by (foo, shard=2)...
+	// )
+	expr.Operation = syntax.OpRangeTypeHyperLogLog
+	downstreams := make([]DownstreamSampleExpr, 0, len(shards))
+	for i := range shards {
+		downstreams = append(downstreams, DownstreamSampleExpr{
+			shard:      &shards[i],
+			SampleExpr: expr,
+		})
+	}
+
+	return &HyperLogLogEvalExpr{
+		downstreams: downstreams,
+	}, bytesPerShard, nil
+}
+
 func noOp[E syntax.Expr](expr E, shards ShardResolver) (E, uint64, error) {
 	exprStats, err := shards.GetStats(expr)
 	if err != nil {
@@ -663,9 +870,12 @@ func isNoOp(left syntax.Expr, right syntax.Expr) bool {
 }
 
 func isLiteralOrVector(e syntax.Expr) bool {
-	switch e.(type) {
+	switch e := e.(type) {
 	case *syntax.VectorExpr, *syntax.LiteralExpr:
 		return true
+	case *syntax.FunctionExpr:
+		// hour() and day_of_week() only depend on the time of each step.
+		return e.Left == nil
 	default:
 		return false
 	}
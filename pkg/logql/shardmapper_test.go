@@ -116,7 +116,7 @@ func TestMapSampleExpr(t *testing.T) {
 
 func TestMappingStrings(t *testing.T) {
 	strategy := NewPowerOfTwoStrategy(ConstantShards(2))
-	m := NewShardMapper(strategy, nilShardMetrics, []string{ShardQuantileOverTime, SupportApproxTopk})
+	m := NewShardMapper(strategy, nilShardMetrics, []string{ShardQuantileOverTime, ShardApproxCountDistinct, SupportApproxTopk})
 	for _, tc := range []struct {
 		in  string
 		out string
@@ -139,6 +139,57 @@ func TestMappingStrings(t *testing.T) {
 			out: `downstream<{foo="bar"}, shard=0_of_2>
 					++ downstream<{foo="bar"}, shard=1_of_2>`,
 		},
+		{
+			in: `sum(abs(rate({foo="bar"}[1m])))`,
+			out: `sum(
+				downstream<sum(abs(rate({foo="bar"}[1m]))), shard=0_of_2>
+				++ downstream<sum(abs(rate({foo="bar"}[1m]))), shard=1_of_2>
+			)`,
+		},
+		{
+			in: `round(sum(rate({foo="bar"}[1m])), 0.5) > hour()`,
+			out: `(round(
+				sum(
+					downstream<sum(rate({foo="bar"}[1m])), shard=0_of_2>
+					++ downstream<sum(rate({foo="bar"}[1m])), shard=1_of_2>
+				), 0.5
+			) > hour())`,
+		},
+		{
+			in: `label_join(sum by (a, b) (rate({foo="bar"}[1m])), "c", "-", "a", "b")`,
+			out: `label_join(
+				sum by (a, b) (
+					downstream<sum by (a, b) (rate({foo="bar"}[1m])), shard=0_of_2>
+					++ downstream<sum by (a, b) (rate({foo="bar"}[1m])), shard=1_of_2>
+				), "c", "-", "a", "b"
+			)`,
+		},
+		{
+			in: `max_over_time(sum(rate({foo="bar"}[1m]))[1h:1m])`,
+			out: `max_over_time(
+				sum(
+					downstream<sum(rate({foo="bar"}[1m])), shard=0_of_2>
+					++ downstream<sum(rate({foo="bar"}[1m])), shard=1_of_2>
+				)[1h:1m]
+			)`,
+		},
+		{
+			in: `deriv(sum by (a) (rate({foo="bar"}[1m]))[1h:1m])`,
+			out: `deriv(
+				sum by (a) (
+					downstream<sum by (a) (rate({foo="bar"}[1m])), shard=0_of_2>
+					++ downstream<sum by (a) (rate({foo="bar"}[1m])), shard=1_of_2>
+				)[1h:1m]
+			)`,
+		},
+		{
+			in:  `sum by (a) (predict_linear({foo="bar"} | logfmt | unwrap value [5m], 3600))`,
+			out: `sum by (a) (predict_linear({foo="bar"} | logfmt | unwrap value [5m], 3600))`,
+		},
+		{
+			in:  `max_over_time(quantile_over_time(0.70, {a=~".+"} | logfmt | unwrap value [1s])[1h:])`,
+			out: `max_over_time(quantile_over_time(0.7,{a=~".+"}|logfmt|unwrapvalue[1s])[1h:])`,
+		},
 		{
 			in: `{foo="bar"} |= "foo" |~ "bar" | json | latency >= 10s or foo<5 and bar="t" | line_format "b{{.blip}}"`,
 			out: `downstream<{foo="bar"} |="foo" |~"bar" | json | (latency>=10s or (foo<5,bar="t")) | line_format "b{{.blip}}", shard=0_of_2>
@@ -473,6 +524,79 @@ func TestMappingStrings(t *testing.T) {
 			in:  `count by (foo) (sum by (foo, bar) (rate({job="bar"}[1m])))`,
 			out: `countby(foo)(sumby(foo,bar)(downstream<sumby(foo,bar)(rate({job="bar"}[1m])),shard=0_of_2>++downstream<sumby(foo,bar)(rate({job="bar"}[1m])),shard=1_of_2>))`,
 		},
+		{
+			in:  `group by (a) (rate({job="bar"}[1m]))`,
+			out: `groupby(a)(downstream<groupby(a)(rate({job="bar"}[1m])),shard=0_of_2>++downstream<groupby(a)(rate({job="bar"}[1m])),shard=1_of_2>)`,
+		},
+		{
+			// count_values is merged by summing the per-shard counts of each value
+			in:  `count_values("v", rate({job="bar"}[1m])) by (a)`,
+			out: `sumby(a,v)(downstream<count_valuesby(a)("v",rate({job="bar"}[1m])),shard=0_of_2>++downstream<count_valuesby(a)("v",rate({job="bar"}[1m])),shard=1_of_2>)`,
+		},
+		{
+			in:  `count_values("v", rate({job="bar"}[1m])) without (a)`,
+			out: `sumwithout(a)(downstream<count_valueswithout(a)("v",rate({job="bar"}[1m])),shard=0_of_2>++downstream<count_valueswithout(a)("v",rate({job="bar"}[1m])),shard=1_of_2>)`,
+		},
+		{
+			// don't shard the count_values since there is label reduction in children
+			in:  `count_values("v", sum by (a) (rate({job="bar"}[1m])))`,
+			out: `count_values("v",sumby(a)(downstream<sumby(a)(rate({job="bar"}[1m])),shard=0_of_2>++downstream<sumby(a)(rate({job="bar"}[1m])),shard=1_of_2>))`,
+		},
+		{
+			in:  `limitk(2, rate({job="bar"}[1m]))`,
+			out: `limitk(2,downstream<limitk(2,rate({job="bar"}[1m])),shard=0_of_2>++downstream<limitk(2,rate({job="bar"}[1m])),shard=1_of_2>)`,
+		},
+		{
+			// don't shard aggregations over limitk since it selects series on every shard
+			in:  `sum(limitk(2, rate({job="bar"}[1m])))`,
+			out: `sum(limitk(2,downstream<limitk(2,rate({job="bar"}[1m])),shard=0_of_2>++downstream<limitk(2,rate({job="bar"}[1m])),shard=1_of_2>))`,
+		},
+		{
+			in:  `limit_ratio(0.5, rate({job="bar"}[1m]))`,
+			out: `limit_ratio(0.5,downstream<limit_ratio(0.5,rate({job="bar"}[1m])),shard=0_of_2>++downstream<limit_ratio(0.5,rate({job="bar"}[1m])),shard=1_of_2>)`,
+		},
+		{
+			// quantile can't be merged across shards
+			in:  `quantile(0.9, rate({job="bar"}[1m])) by (a)`,
+			out: `quantileby(a)(0.9,downstream<rate({job="bar"}[1m]),shard=0_of_2>++downstream<rate({job="bar"}[1m]),shard=1_of_2>)`,
+		},
+		{
+			in:  `approx_count_distinct_over_time({a=~".+"} | logfmt | unwrap user [1m]) by (a)`,
+			out: `HyperLogLogEval<downstream<__hyperloglog_over_time__({a=~".+"}|logfmt|unwrapuser[1m])by(a),shard=0_of_2>++downstream<__hyperloglog_over_time__({a=~".+"}|logfmt|unwrapuser[1m])by(a),shard=1_of_2>>`,
+		},
+		{
+			in:  `histogram_over_time({a=~".+"} | logfmt | unwrap latency [1m]) by (a)`,
+			out: `HistogramCumulate<downstream<__histogram_buckets_over_time__({a=~".+"}|logfmt|unwraplatency[1m])by(a),shard=0_of_2>++downstream<__histogram_buckets_over_time__({a=~".+"}|logfmt|unwraplatency[1m])by(a),shard=1_of_2>>`,
+		},
+		{
+			// the buckets are only aggregated once they are cumulative
+			in:  `histogram_quantile(0.99, sum by (le) (histogram_over_time({a=~".+"} | logfmt | unwrap latency [1m])))`,
+			out: `histogram_quantile(0.99,sumby(le)(HistogramCumulate<downstream<__histogram_buckets_over_time__({a=~".+"}|logfmt|unwraplatency[1m]),shard=0_of_2>++downstream<__histogram_buckets_over_time__({a=~".+"}|logfmt|unwraplatency[1m]),shard=1_of_2>>))`,
+		},
+		{
+			// the sketches are merged wherever the aggregation is nested
+			in:  `sum by (a) (approx_count_distinct_over_time({a=~".+"} | logfmt | unwrap user [1m]) by (a, b))`,
+			out: `sumby(a)(HyperLogLogEval<downstream<__hyperloglog_over_time__({a=~".+"}|logfmt|unwrapuser[1m])by(a,b),shard=0_of_2>++downstream<__hyperloglog_over_time__({a=~".+"}|logfmt|unwrapuser[1m])by(a,b),shard=1_of_2>>)`,
+		},
+		{
+			// series without grouping exist on a single shard
+			in:  `approx_count_distinct_over_time({a=~".+"} | logfmt | unwrap user [1m])`,
+			out: `downstream<approx_count_distinct_over_time({a=~".+"}|logfmt|unwrapuser[1m]),shard=0_of_2>++downstream<approx_count_distinct_over_time({a=~".+"}|logfmt|unwrapuser[1m]),shard=1_of_2>`,
+		},
+		{
+			in:  `approx_count_distinct by (a) (count_over_time({a=~".+"} | logfmt | keep a, user [1m]))`,
+			out: `HyperLogLogEval<downstream<__hyperloglog__by(a)(count_over_time({a=~".+"}|logfmt|keepa,user[1m])),shard=0_of_2>++downstream<__hyperloglog__by(a)(count_over_time({a=~".+"}|logfmt|keepa,user[1m])),shard=1_of_2>>`,
+		},
+		{
+			// the replicas of a stream are on the same shard
+			in:  `sum by (a) (count_over_time({a=~".+"} | dedup replica [1m]))`,
+			out: `sumby(a)(downstream<sumby(a)(count_over_time({a=~".+"}|dedupreplica[1m])),shard=0_of_2>++downstream<sumby(a)(count_over_time({a=~".+"}|dedupreplica[1m])),shard=1_of_2>)`,
+		},
+		{
+			// the series of a filter depend on the values of the whole query
+			in:  `approx_count_distinct by (a) (count_over_time({a=~".+"}[1m]) > 1)`,
+			out: `approx_count_distinctby(a)((downstream<count_over_time({a=~".+"}[1m]),shard=0_of_2>++downstream<count_over_time({a=~".+"}[1m]),shard=1_of_2>>1))`,
+		},
 	} {
 		t.Run(tc.in, func(t *testing.T) {
 			ast, err := syntax.ParseExpr(tc.in)
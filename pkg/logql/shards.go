@@ -2,10 +2,12 @@ package logql
 
 import (
 	"encoding/json"
+	"slices"
 
 	"github.com/grafana/dskit/multierror"
 	"github.com/pkg/errors"
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
 
 	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/grafana/loki/v3/pkg/logql/syntax"
@@ -262,6 +264,35 @@ func ParseShards(strs []string) (Shards, ShardVersion, error) {
 	return shards, prevVersion, nil
 }
 
+// DedupShards returns the shards of a request to select in the index and, for
+// queries with a dedup stage, a function returning whether a stream belongs to
+// the shards of the request. The replicas of a stream only differ by their
+// dedup labels, so such queries shard the streams on their labels without the
+// dedup labels, which puts the replicas of a stream on the same shard. Their
+// shards cannot be selected in the index and the returned shards are nil.
+func DedupShards(shards []string, expr syntax.Expr) ([]string, func(labels.Labels) bool, error) {
+	names := syntax.DedupLabels(expr)
+	if len(shards) == 0 || len(names) == 0 {
+		return shards, nil, nil
+	}
+	parsed, _, err := ParseShards(shards)
+	if err != nil {
+		return nil, nil, err
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	return nil, func(lbs labels.Labels) bool {
+		hash, _ := lbs.HashWithoutLabels(nil, names...)
+		for _, s := range parsed {
+			if s.Match(model.Fingerprint(hash)) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
 func ParseShard(s string) (Shard, ShardVersion, error) {
 
 	var bounded logproto.Shard
@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
 	"github.com/grafana/loki/v3/pkg/storage/stores/shipper/indexshipper/tsdb/index"
 )
 
@@ -186,3 +188,42 @@ func TestParseShards(t *testing.T) {
 		})
 	}
 }
+
+func TestDedupShards(t *testing.T) {
+	encoded := Shards{
+		NewPowerOfTwoShard(index.ShardAnnotation{Shard: 0, Of: 2}),
+		NewPowerOfTwoShard(index.ShardAnnotation{Shard: 1, Of: 2}),
+	}.Encode()
+
+	// queries without a dedup stage are sharded in the index
+	shards, keep, err := DedupShards(encoded[:1], syntax.MustParseExpr(`{app="foo"} | drop replica`))
+	require.NoError(t, err)
+	require.Equal(t, encoded[:1], shards)
+	require.Nil(t, keep)
+
+	expr := syntax.MustParseExpr(`count_over_time({app="foo"} | dedup replica, agent [1m])`)
+	shards, keep, err = DedupShards(nil, expr)
+	require.NoError(t, err)
+	require.Nil(t, shards)
+	require.Nil(t, keep)
+
+	// the replicas of a stream are on the same shard, whatever the shard
+	replicas := []labels.Labels{
+		labels.FromStrings("app", "foo", "pod", "a", "replica", "1", "agent", "x"),
+		labels.FromStrings("app", "foo", "pod", "a", "replica", "2", "agent", "y"),
+		labels.FromStrings("app", "foo", "pod", "a"),
+	}
+	var kept int
+	for i := range encoded {
+		shards, keep, err = DedupShards(encoded[i:i+1], expr)
+		require.NoError(t, err)
+		require.Nil(t, shards)
+		for _, ls := range replicas {
+			require.Equal(t, keep(replicas[0]), keep(ls))
+		}
+		if keep(replicas[0]) {
+			kept++
+		}
+	}
+	require.Equal(t, 1, kept)
+}
@@ -0,0 +1,187 @@
+package logql
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+
+	"github.com/grafana/loki/v3/pkg/iter"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+// defaultSubqueryStep is the step of subqueries without an explicit step
+// within instant queries, which have no step of their own.
+const defaultSubqueryStep = time.Minute
+
+// subqueryParams are the params of the inner expression of a subquery, which is
+// evaluated as a range query over the range of the subquery.
+type subqueryParams struct {
+	Params
+	expr       syntax.Expr
+	start, end time.Time
+	step       time.Duration
+}
+
+func (p subqueryParams) QueryString() string           { return p.expr.String() }
+func (p subqueryParams) GetExpression() syntax.Expr    { return p.expr }
+func (p subqueryParams) Start() time.Time              { return p.start }
+func (p subqueryParams) End() time.Time                { return p.end }
+func (p subqueryParams) Step() time.Duration           { return p.step }
+func (p subqueryParams) Interval() time.Duration       { return 0 }
+func (p subqueryParams) Direction() logproto.Direction { return logproto.FORWARD }
+
+// newSubqueryParams returns the params of the inner expression of the
+// subquery expr evaluated within a query with the params q.
+func newSubqueryParams(expr *syntax.SubqueryExpr, q Params) subqueryParams {
+	step := expr.Step
+	if step == 0 {
+		step = q.Step()
+	}
+	if step == 0 {
+		step = defaultSubqueryStep
+	}
+
+	// As in PromQL, the steps of the inner expression are aligned to multiples
+	// of the step of the subquery, so that its samples don't depend on the
+	// start of the query.
+	start := q.Start().Add(-expr.Offset - expr.Range).UnixNano()
+	if rem := start % step.Nanoseconds(); rem != 0 {
+		start -= rem
+		if start < q.Start().Add(-expr.Offset-expr.Range).UnixNano() {
+			start += step.Nanoseconds()
+		}
+	}
+
+	return subqueryParams{
+		Params: q,
+		expr:   expr.Left,
+		start:  time.Unix(0, start),
+		end:    q.End().Add(-expr.Offset),
+		step:   step,
+	}
+}
+
+// newSubqueryAggEvaluator evaluates the inner expression of a subquery at each
+// of its steps, and aggregates the resulting samples over the range of the
+// subquery the same way unwrapped range aggregations aggregate the samples
+// extracted from log lines.
+func newSubqueryAggEvaluator(
+	ctx context.Context,
+	nextEvFactory SampleEvaluatorFactory,
+	expr *syntax.SubqueryAggregationExpr,
+	q Params,
+) (StepEvaluator, error) {
+	inner, err := nextEvFactory.NewStepEvaluator(ctx, nextEvFactory, expr.Left.Left, newSubqueryParams(expr.Left, q))
+	if err != nil {
+		return nil, err
+	}
+
+	// The range iterators only read the operation, its parameter and the
+	// range of the aggregation, which are the same for subqueries.
+	rangeExpr := &syntax.RangeAggregationExpr{
+		Left: &syntax.LogRangeExpr{
+			Interval: expr.Left.Range,
+			Offset:   expr.Left.Offset,
+		},
+		Operation:   expr.Operation,
+		Params:      expr.Params,
+		SecondParam: expr.SecondParam,
+	}
+	// The samples of the inner expression are already scaled if it samples
+	// log lines, so the range aggregation must not scale them again.
+	it, err := newUnscaledRangeVectorIterator(
+		iter.NewPeekingSampleIterator(newSubqueryIterator(inner, expr.Grouping)), rangeExpr,
+		expr.Left.Range.Nanoseconds(),
+		q.Step().Nanoseconds(),
+		q.Start().UnixNano(), q.End().UnixNano(), expr.Left.Offset.Nanoseconds(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SubqueryAggEvaluator{
+		RangeVectorEvaluator: RangeVectorEvaluator{iter: it},
+		inner:                inner,
+		expr:                 expr,
+	}, nil
+}
+
+// SubqueryAggEvaluator aggregates the samples of the inner expression of a
+// subquery over the range of the subquery.
+type SubqueryAggEvaluator struct {
+	RangeVectorEvaluator
+	inner StepEvaluator
+	expr  *syntax.SubqueryAggregationExpr
+}
+
+func (e *SubqueryAggEvaluator) Explain(parent Node) {
+	b := parent.Childf("[%s, %s] SubqueryAgg", e.expr.Operation, e.expr.Left.String())
+	e.inner.Explain(b)
+}
+
+// subqueryIterator iterates in time order over the samples of the steps of the
+// inner expression of a subquery. The labels of the samples are reduced to the
+// grouping of the subquery aggregation, if any.
+type subqueryIterator struct {
+	ev       StepEvaluator
+	grouping *syntax.Grouping
+	lb       *labels.Builder
+
+	vec    promql.Vector
+	idx    int
+	sample logproto.Sample
+	labels string
+}
+
+func newSubqueryIterator(ev StepEvaluator, grouping *syntax.Grouping) *subqueryIterator {
+	return &subqueryIterator{
+		ev:       ev,
+		grouping: grouping,
+		lb:       labels.NewBuilder(labels.EmptyLabels()),
+	}
+}
+
+func (it *subqueryIterator) Next() bool {
+	it.idx++
+	for it.idx >= len(it.vec) {
+		next, _, r := it.ev.Next()
+		if !next {
+			return false
+		}
+		it.vec = r.SampleVector()
+		it.idx = 0
+	}
+
+	s := it.vec[it.idx]
+	metric := it.group(s.Metric)
+	it.labels = metric.String()
+	it.sample = logproto.Sample{
+		// steps are in milliseconds while samples are in nanoseconds.
+		Timestamp: s.T * int64(time.Millisecond),
+		Value:     s.F,
+		Hash:      metric.Hash(),
+	}
+	return true
+}
+
+func (it *subqueryIterator) group(metric labels.Labels) labels.Labels {
+	if it.grouping == nil || it.grouping.Noop() {
+		return metric
+	}
+	it.lb.Reset(metric)
+	if it.grouping.Without {
+		it.lb.Del(it.grouping.Groups...)
+	} else {
+		it.lb.Keep(it.grouping.Groups...)
+	}
+	return it.lb.Labels()
+}
+
+func (it *subqueryIterator) At() logproto.Sample { return it.sample }
+func (it *subqueryIterator) Labels() string      { return it.labels }
+func (it *subqueryIterator) StreamHash() uint64  { return it.sample.Hash }
+func (it *subqueryIterator) Err() error          { return it.ev.Error() }
+func (it *subqueryIterator) Close() error        { return it.ev.Close() }
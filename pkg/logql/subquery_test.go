@@ -0,0 +1,81 @@
+package logql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/iter"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+func TestSubqueryParams(t *testing.T) {
+	expr := syntax.MustParseExpr(`max_over_time(sum(rate({app="foo"}[1m]))[1h:1m] offset 10m)`).(*syntax.SubqueryAggregationExpr)
+	q, err := NewLiteralParams(expr.String(), time.Unix(4230, 0), time.Unix(4290, 0), 0, 0, logproto.FORWARD, 0, nil, nil)
+	require.NoError(t, err)
+
+	p := newSubqueryParams(expr.Left, q)
+	// 4230s - 10m - 1h = 30s, aligned to the next minute.
+	require.Equal(t, time.Unix(60, 0), p.Start())
+	require.Equal(t, time.Unix(3690, 0), p.End())
+	require.Equal(t, time.Minute, p.Step())
+	require.Equal(t, `sum(rate({app="foo"}[1m]))`, p.QueryString())
+
+	// Subqueries without step use the step of the query.
+	expr = syntax.MustParseExpr(`max_over_time(sum(rate({app="foo"}[1m]))[1h:])`).(*syntax.SubqueryAggregationExpr)
+	q, err = NewLiteralParams(expr.String(), time.Unix(7200, 0), time.Unix(7200, 0), 30*time.Second, 0, logproto.FORWARD, 0, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Second, newSubqueryParams(expr.Left, q).Step())
+}
+
+func TestEngine_Subquery(t *testing.T) {
+	// one sample per second from 1s to 299s
+	querier := errorIteratorQuerier{
+		samples: func() []iter.SampleIterator {
+			return []iter.SampleIterator{
+				iter.NewSeriesIterator(offsetSeries(newSeries(299, identity, `{app="foo", pod="a"}`))),
+			}
+		},
+	}
+	eng := NewEngine(EngineOpts{}, querier, NoLimits, log.NewNopLogger())
+
+	for _, tc := range []struct {
+		qs       string
+		expected float64
+	}{
+		// The inner expression is evaluated at 60s, 120s, 180s, 240s and 300s.
+		{`count_over_time(sum(count_over_time({app="foo"}[1m]))[5m:1m])`, 5},
+		{`sum_over_time(sum(count_over_time({app="foo"}[1m]))[5m:1m])`, 299},
+		{`min_over_time(sum(count_over_time({app="foo"}[1m]))[5m:1m])`, 59},
+		{`max_over_time(count_over_time({app="foo"}[1m])[5m:1m]) by (app)`, 60},
+		{`changes(sum(count_over_time({app="foo"}[1m]))[5m:1m])`, 1},
+	} {
+		t.Run(tc.qs, func(t *testing.T) {
+			params, err := NewLiteralParams(tc.qs, time.Unix(300, 0), time.Unix(300, 0), 0, 0, logproto.FORWARD, 0, nil, nil)
+			require.NoError(t, err)
+
+			res, err := eng.Query(params).Exec(user.InjectOrgID(context.Background(), "fake"))
+			require.NoError(t, err)
+
+			vec, ok := res.Data.(promql.Vector)
+			require.True(t, ok)
+			require.Len(t, vec, 1)
+			require.Equal(t, tc.expected, vec[0].F)
+		})
+	}
+}
+
+// offsetSeries shifts the samples of the series by one second, so that the
+// first sample is at 1s.
+func offsetSeries(s logproto.Series) logproto.Series {
+	for i := range s.Samples {
+		s.Samples[i].Timestamp += time.Second.Nanoseconds()
+	}
+	return s
+}
@@ -1,7 +1,9 @@
 package syntax
 
 import (
+	"context"
 	"fmt"
+	"maps"
 	"math"
 	"regexp"
 	"strconv"
@@ -10,6 +12,7 @@ import (
 
 	"github.com/grafana/loki/v3/pkg/util"
 
+	"github.com/grafana/dskit/tenant"
 	"github.com/pkg/errors"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
@@ -48,23 +51,34 @@ type Expr interface {
 func (MatchersExpr) isExpr()               {}
 func (PipelineExpr) isExpr()               {}
 func (RangeAggregationExpr) isExpr()       {}
+func (SubqueryAggregationExpr) isExpr()    {}
 func (VectorAggregationExpr) isExpr()      {}
 func (LiteralExpr) isExpr()                {}
 func (VectorExpr) isExpr()                 {}
 func (LabelReplaceExpr) isExpr()           {}
+func (LabelJoinExpr) isExpr()              {}
+func (FunctionExpr) isExpr()               {}
 func (LineParserExpr) isExpr()             {}
 func (LogfmtParserExpr) isExpr()           {}
 func (LineFilterExpr) isExpr()             {}
 func (LabelFilterExpr) isExpr()            {}
 func (DecolorizeExpr) isExpr()             {}
+func (RedactExpr) isExpr()                 {}
+func (SamplingExpr) isExpr()               {}
+func (ContextExpr) isExpr()                {}
+func (LookupExpr) isExpr()                 {}
+func (DedupExpr) isExpr()                  {}
 func (DropLabelsExpr) isExpr()             {}
 func (KeepLabelsExpr) isExpr()             {}
 func (LineFmtExpr) isExpr()                {}
 func (LabelFmtExpr) isExpr()               {}
 func (JSONExpressionParserExpr) isExpr()   {}
 func (LogfmtExpressionParserExpr) isExpr() {}
+func (XMLExpressionParserExpr) isExpr()    {}
+func (CSVParserExpr) isExpr()              {}
 func (LogRangeExpr) isExpr()               {}
 func (OffsetExpr) isExpr()                 {}
+func (SubqueryExpr) isExpr()               {}
 func (UnwrapExpr) isExpr()                 {}
 func (MultiVariantExpr) isExpr()           {}
 
@@ -96,12 +110,15 @@ type SampleExpr interface {
 	isSampleExpr()
 }
 
-func (RangeAggregationExpr) isSampleExpr()  {}
-func (VectorAggregationExpr) isSampleExpr() {}
-func (LiteralExpr) isSampleExpr()           {}
-func (VectorExpr) isSampleExpr()            {}
-func (LabelReplaceExpr) isSampleExpr()      {}
-func (MultiVariantExpr) isSampleExpr()      {}
+func (RangeAggregationExpr) isSampleExpr()    {}
+func (SubqueryAggregationExpr) isSampleExpr() {}
+func (VectorAggregationExpr) isSampleExpr()   {}
+func (LiteralExpr) isSampleExpr()             {}
+func (VectorExpr) isSampleExpr()              {}
+func (LabelReplaceExpr) isSampleExpr()        {}
+func (LabelJoinExpr) isSampleExpr()           {}
+func (FunctionExpr) isSampleExpr()            {}
+func (MultiVariantExpr) isSampleExpr()        {}
 
 // StageExpr is an expression defining a single step into a log pipeline
 type StageExpr interface {
@@ -117,12 +134,19 @@ func (LogfmtParserExpr) isStageExpr()           {}
 func (LineFilterExpr) isStageExpr()             {}
 func (LabelFilterExpr) isStageExpr()            {}
 func (DecolorizeExpr) isStageExpr()             {}
+func (RedactExpr) isStageExpr()                 {}
+func (SamplingExpr) isStageExpr()               {}
+func (ContextExpr) isStageExpr()                {}
+func (LookupExpr) isStageExpr()                 {}
+func (DedupExpr) isStageExpr()                  {}
 func (DropLabelsExpr) isStageExpr()             {}
 func (KeepLabelsExpr) isStageExpr()             {}
 func (LineFmtExpr) isStageExpr()                {}
 func (LabelFmtExpr) isStageExpr()               {}
 func (JSONExpressionParserExpr) isStageExpr()   {}
 func (LogfmtExpressionParserExpr) isStageExpr() {}
+func (XMLExpressionParserExpr) isStageExpr()    {}
+func (CSVParserExpr) isStageExpr()              {}
 
 func Clone[T Expr](e T) (T, error) {
 	var empty T
@@ -261,10 +285,9 @@ func (m MultiStageExpr) reorderStages() []StageExpr {
 			result = append(result, f)
 		case *LineFilterExpr:
 			lineFilters = append(lineFilters, f)
-		case *LineFmtExpr:
-			// line_format modifies the contents of the line so any line filter
-			// originally after a line_format must still be after the same
-			// line_format.
+		case *LineFmtExpr, *RedactExpr:
+			// line_format and redact modify the contents of the line so any line
+			// filter originally after them must still be after the same stage.
 
 			notLineFilters = append(notLineFilters, f)
 
@@ -422,7 +445,7 @@ func (e *PipelineExpr) Pipeline() (log.Pipeline, error) {
 func (e *PipelineExpr) HasFilter() bool {
 	for _, p := range e.MultiStages {
 		switch v := p.(type) {
-		case *LabelFilterExpr:
+		case *LabelFilterExpr, *SamplingExpr:
 			return true
 		case *LineFilterExpr:
 			// ignore empty matchers as they match everything
@@ -762,6 +785,8 @@ func (e *LineParserExpr) Stage() (log.Stage, error) {
 		return log.NewUnpackParser(), nil
 	case OpParserTypePattern:
 		return log.NewPatternParser(e.Param)
+	case OpParserTypeXML:
+		return log.NewXMLParser(), nil
 	default:
 		return nil, fmt.Errorf("unknown parser operator: %s", e.Op)
 	}
@@ -842,6 +867,468 @@ func (e *DecolorizeExpr) Walk(f WalkFn) { f(e) }
 
 func (e *DecolorizeExpr) Accept(v RootVisitor) { v.VisitDecolorize(e) }
 
+// RedactExpr replaces sensitive data detected in the line, using all built-in
+// detectors when none are named, and the custom patterns of the tenant once
+// bound with BindRedaction.
+type RedactExpr struct {
+	Detectors []string
+
+	redactor *log.Redactor
+}
+
+func newRedactExpr(detectors []string) *RedactExpr {
+	if _, err := log.NewRedactor(detectors, nil); err != nil {
+		panic(logqlmodel.NewParseError(err.Error(), 0, 0))
+	}
+	return &RedactExpr{Detectors: detectors}
+}
+
+func (e *RedactExpr) Shardable(_ bool) bool { return true }
+
+func (e *RedactExpr) Stage() (log.Stage, error) {
+	if e.redactor != nil {
+		return e.redactor, nil
+	}
+	return log.NewRedactor(e.Detectors, nil)
+}
+
+func (e *RedactExpr) String() string {
+	if len(e.Detectors) == 0 {
+		return fmt.Sprintf("%s %s", OpPipe, OpRedact)
+	}
+	return fmt.Sprintf("%s %s %s", OpPipe, OpRedact, strings.Join(e.Detectors, ","))
+}
+
+func (e *RedactExpr) Walk(f WalkFn) { f(e) }
+
+func (e *RedactExpr) Accept(v RootVisitor) { v.VisitRedact(e) }
+
+// SamplingExpr keeps a deterministic sample of the log entries or streams.
+// Metric queries scale additive range aggregations by the inverse of the
+// sampling ratio.
+type SamplingExpr struct {
+	Ratio float64
+	// Mode is either log.SampleEntries or log.SampleStreams.
+	Mode string
+}
+
+func newSamplingExpr(ratio string, mode string) *SamplingExpr {
+	r, err := strconv.ParseFloat(ratio, 64)
+	if err != nil {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid sampling ratio %s", ratio), 0, 0))
+	}
+	if err := log.ValidateSampleRatio(r); err != nil {
+		panic(logqlmodel.NewParseError(err.Error(), 0, 0))
+	}
+	switch mode {
+	case "":
+		mode = log.SampleEntries
+	case log.SampleEntries, log.SampleStreams:
+	default:
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid sampling mode %q, expected %q or %q", mode, log.SampleEntries, log.SampleStreams), 0, 0))
+	}
+	return &SamplingExpr{Ratio: r, Mode: mode}
+}
+
+func (e *SamplingExpr) Shardable(_ bool) bool { return true }
+
+func (e *SamplingExpr) Stage() (log.Stage, error) {
+	if e.Mode == log.SampleStreams {
+		// Streams are sampled when selecting the data to read, see SamplingRatios.
+		return log.NoopStage, nil
+	}
+	return log.NewSampler(e.Ratio)
+}
+
+func (e *SamplingExpr) String() string {
+	ratio := strconv.FormatFloat(e.Ratio, 'f', -1, 64)
+	if e.Mode == log.SampleStreams {
+		return fmt.Sprintf("%s %s %s by %s", OpPipe, OpSample, ratio, log.SampleStreams)
+	}
+	return fmt.Sprintf("%s %s %s", OpPipe, OpSample, ratio)
+}
+
+func (e *SamplingExpr) Walk(f WalkFn) { f(e) }
+
+func (e *SamplingExpr) Accept(v RootVisitor) { v.VisitSampling(e) }
+
+// SamplingRatios returns the fraction of entries and the fraction of streams
+// kept by the sample stages of expr, 1 if there are none. Samples of the same
+// mode are nested, so only the smallest ratio of each mode applies. The stream
+// ratio is the effective ratio, see log.EffectiveStreamSampleRatio.
+func SamplingRatios(expr Expr) (entries, streams float64) {
+	entries, streams = 1, 1
+	expr.Walk(func(e Expr) bool {
+		s, ok := e.(*SamplingExpr)
+		if !ok {
+			return true
+		}
+		if s.Mode == log.SampleStreams {
+			streams = min(streams, log.EffectiveStreamSampleRatio(s.Ratio))
+		} else {
+			entries = min(entries, s.Ratio)
+		}
+		return true
+	})
+	return entries, streams
+}
+
+// SamplingScale returns the factor additive aggregations over expr must be
+// multiplied by to estimate their value over the data before sampling.
+func SamplingScale(expr Expr) float64 {
+	entries, streams := SamplingRatios(expr)
+	return 1 / (entries * streams)
+}
+
+// ContextExpr shows the entries surrounding the entries matched by the
+// stages preceding it: up to Before entries before and After entries after
+// every match, from the same stream. It must be the last stage of a log query.
+type ContextExpr struct {
+	Before int
+	After  int
+}
+
+// newContextExpr returns the context stage for the given options, a list of
+// name and value pairs.
+func newContextExpr(options []string) *ContextExpr {
+	e := &ContextExpr{}
+	seen := map[string]struct{}{}
+	for i := 0; i+1 < len(options); i += 2 {
+		name, value := options[i], options[i+1]
+		if _, ok := seen[name]; ok {
+			panic(logqlmodel.NewParseError(fmt.Sprintf("duplicate context option %s", name), 0, 0))
+		}
+		seen[name] = struct{}{}
+
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			panic(logqlmodel.NewParseError(fmt.Sprintf("invalid context option %s=%s, expected a non-negative number of lines", name, value), 0, 0))
+		}
+		switch name {
+		case OpContextBefore:
+			e.Before = n
+		case OpContextAfter:
+			e.After = n
+		default:
+			panic(logqlmodel.NewParseError(fmt.Sprintf("invalid context option %s, expected %s or %s", name, OpContextBefore, OpContextAfter), 0, 0))
+		}
+	}
+	return e
+}
+
+func (e *ContextExpr) Shardable(_ bool) bool { return true }
+
+// Stage returns an error: the context of an entry depends on the entries
+// around it, so it is evaluated by the engine over the entries of the whole
+// stream rather than line by line, see SplitContextStage.
+func (e *ContextExpr) Stage() (log.Stage, error) {
+	return nil, fmt.Errorf("%s stage cannot be evaluated line by line", OpContext)
+}
+
+func (e *ContextExpr) String() string {
+	return fmt.Sprintf("%s %s %s=%d %s=%d", OpPipe, OpContext, OpContextBefore, e.Before, OpContextAfter, e.After)
+}
+
+func (e *ContextExpr) Walk(f WalkFn) { f(e) }
+
+func (e *ContextExpr) Accept(v RootVisitor) { v.VisitContext(e) }
+
+// HasContextStage returns whether expr contains a context stage.
+func HasContextStage(expr Expr) bool {
+	var found bool
+	expr.Walk(func(e Expr) bool {
+		if _, ok := e.(*ContextExpr); ok {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// SplitContextStage returns the context stage of expr and the log selector
+// matching the entries to show the context of, which is expr without its
+// context stage. It returns nil if the last stage of expr is not a context
+// stage.
+func SplitContextStage(expr LogSelectorExpr) (*ContextExpr, LogSelectorExpr) {
+	p, ok := expr.(*PipelineExpr)
+	if !ok || len(p.MultiStages) == 0 {
+		return nil, expr
+	}
+	last := len(p.MultiStages) - 1
+	c, ok := p.MultiStages[last].(*ContextExpr)
+	if !ok {
+		return nil, expr
+	}
+	if last == 0 {
+		return c, p.Left
+	}
+	return c, newPipelineExpr(p.Left, p.MultiStages[:last])
+}
+
+// LeadingLineFilters returns the stream selector of expr with the line filters
+// preceding any other stage, which select entries without modifying them.
+func LeadingLineFilters(expr LogSelectorExpr) LogSelectorExpr {
+	p, ok := expr.(*PipelineExpr)
+	if !ok {
+		return expr
+	}
+	n := 0
+	for n < len(p.MultiStages) {
+		if _, ok := p.MultiStages[n].(*LineFilterExpr); !ok {
+			break
+		}
+		n++
+	}
+	if n == 0 {
+		return p.Left
+	}
+	return newPipelineExpr(p.Left, p.MultiStages[:n])
+}
+
+// LookupExpr adds the columns of the row of the lookup table Table whose
+// column Key equals the value of the label Key. Version pins the version of the
+// table, the latest one is used when empty. Tables are stored per tenant and
+// must be bound with BindLookupTables before building a pipeline.
+type LookupExpr struct {
+	Table   string
+	Key     string
+	Version string
+
+	table *log.LookupTable
+}
+
+func newLookupExpr(table, key, option, version string) *LookupExpr {
+	if option != "" && option != OpLookupVersion {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid lookup option %s, expected %s", option, OpLookupVersion), 0, 0))
+	}
+	if option != "" && !log.ValidLookupVersion(version) {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid lookup table version %q, expected %d lowercase hexadecimal characters", version, log.LookupVersionLength), 0, 0))
+	}
+	return &LookupExpr{Table: table, Key: key, Version: version}
+}
+
+func (e *LookupExpr) Shardable(_ bool) bool { return true }
+
+func (e *LookupExpr) Stage() (log.Stage, error) {
+	if e.table == nil {
+		return nil, fmt.Errorf("lookup table %s is not loaded", e.Table)
+	}
+	return log.NewLookup(e.table, e.Key)
+}
+
+func (e *LookupExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s %s %s %s", OpPipe, OpLookup, e.Table, OpOn, e.Key))
+	if e.Version != "" {
+		sb.WriteString(fmt.Sprintf(" %s %s", OpLookupVersion, strconv.Quote(e.Version)))
+	}
+	return sb.String()
+}
+
+func (e *LookupExpr) Walk(f WalkFn) { f(e) }
+
+func (e *LookupExpr) Accept(v RootVisitor) { v.VisitLookup(e) }
+
+// HasLookupStage returns whether expr contains a lookup stage.
+func HasLookupStage(expr Expr) bool {
+	var found bool
+	expr.Walk(func(e Expr) bool {
+		if _, ok := e.(*LookupExpr); ok {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// BindLookupTables returns a copy of expr whose lookup stages use the tables
+// returned by tables, or expr itself if it has no lookup stage.
+func BindLookupTables[T Expr](ctx context.Context, expr T, tables log.LookupTables) (T, error) {
+	if !HasLookupStage(expr) {
+		return expr, nil
+	}
+	if tables == nil {
+		return expr, errors.New("lookup tables are not enabled")
+	}
+	cloned, err := Clone(expr)
+	if err != nil {
+		return expr, err
+	}
+	cloned.Walk(func(e Expr) bool {
+		l, ok := e.(*LookupExpr)
+		if !ok {
+			return true
+		}
+		l.table, err = tables.LookupTable(ctx, l.Table, l.Version)
+		return err == nil
+	})
+	if err != nil {
+		return expr, err
+	}
+	return cloned, nil
+}
+
+// BindRedaction returns a copy of expr whose redact stages also apply the
+// custom patterns of the tenants of the request. The queries of tenants with
+// redaction enabled are redacted with their ingestion redactor at the end of
+// every pipeline, so that entries written before redaction was enabled, the
+// labels extracted from them and the series of metric queries are redacted
+// too. expr itself is returned if there is nothing to bind.
+func BindRedaction[T Expr](ctx context.Context, expr T, limits log.RedactionLimits) (T, error) {
+	if limits == nil {
+		return expr, nil
+	}
+	tenants, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return expr, err
+	}
+
+	var (
+		redactors []*log.Redactor
+		patterns  = map[string]string{}
+	)
+	for _, id := range tenants {
+		redactor, tenantPatterns, err := limits.QueryRedaction(id)
+		if err != nil {
+			return expr, err
+		}
+		if redactor != nil {
+			redactors = append(redactors, redactor)
+		}
+		maps.Copy(patterns, tenantPatterns)
+	}
+	hasRedactStage := HasRedactStage(expr)
+	if !hasRedactStage && len(redactors) == 0 {
+		return expr, nil
+	}
+
+	cloned, err := Clone(expr)
+	if err != nil {
+		return expr, err
+	}
+	if hasRedactStage && len(patterns) > 0 {
+		cloned.Walk(func(e Expr) bool {
+			r, ok := e.(*RedactExpr)
+			if !ok {
+				return true
+			}
+			detectors := r.Detectors
+			if len(detectors) == 0 {
+				detectors = log.RedactDetectors()
+			}
+			r.redactor, err = log.NewRedactor(detectors, patterns)
+			return err == nil
+		})
+		if err != nil {
+			return expr, err
+		}
+	}
+	if len(redactors) == 0 {
+		return cloned, nil
+	}
+
+	stages := make(MultiStageExpr, 0, len(redactors))
+	for _, r := range redactors {
+		stages = append(stages, &RedactExpr{redactor: r})
+	}
+	if selector, ok := any(cloned).(LogSelectorExpr); ok {
+		// Fail closed if the redacted selector cannot be returned as T.
+		bound, ok := withRedactStages(selector, stages).(T)
+		if !ok {
+			return expr, fmt.Errorf("cannot redact the query %s", expr)
+		}
+		return bound, nil
+	}
+	cloned.Walk(func(e Expr) bool {
+		if r, ok := e.(*LogRangeExpr); ok {
+			r.Left = withRedactStages(r.Left, stages)
+		}
+		return true
+	})
+	return cloned, nil
+}
+
+// withRedactStages returns expr with the redact stages appended to its pipeline.
+func withRedactStages(expr LogSelectorExpr, stages MultiStageExpr) LogSelectorExpr {
+	switch e := expr.(type) {
+	case *MatchersExpr:
+		return newPipelineExpr(e, stages)
+	case *PipelineExpr:
+		e.MultiStages = append(e.MultiStages, stages...)
+		return e
+	}
+	return expr
+}
+
+// HasRedactStage returns whether expr contains a redact stage.
+func HasRedactStage(expr Expr) bool {
+	var found bool
+	expr.Walk(func(e Expr) bool {
+		if _, ok := e.(*RedactExpr); ok {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// DedupExpr drops the labels telling replicated streams apart, so that the
+// identical entries of the replicas can be removed by the engine, see
+// HasDedupStage.
+type DedupExpr struct {
+	Labels []string
+}
+
+func newDedupExpr(labels []string) *DedupExpr {
+	return &DedupExpr{Labels: labels}
+}
+
+// Shardable returns true: the streams of a query with a dedup stage are sharded
+// on their labels without the dedup labels, which puts the replicas of a
+// stream on the same shard, see logql.DedupShards.
+func (e *DedupExpr) Shardable(_ bool) bool { return true }
+
+func (e *DedupExpr) Stage() (log.Stage, error) {
+	labels := make([]log.NamedLabelMatcher, 0, len(e.Labels))
+	for _, name := range e.Labels {
+		labels = append(labels, log.NewNamedLabelMatcher(nil, name))
+	}
+	return log.NewDropLabels(labels), nil
+}
+
+func (e *DedupExpr) String() string {
+	return fmt.Sprintf("%s %s %s", OpPipe, OpDedup, strings.Join(e.Labels, ", "))
+}
+
+func (e *DedupExpr) Walk(f WalkFn) { f(e) }
+
+func (e *DedupExpr) Accept(v RootVisitor) { v.VisitDedup(e) }
+
+// HasDedupStage returns whether expr contains a dedup stage, in which case the
+// engine must remove the identical entries or samples of different streams.
+func HasDedupStage(expr Expr) bool {
+	var found bool
+	expr.Walk(func(e Expr) bool {
+		if _, ok := e.(*DedupExpr); ok {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// DedupLabels returns the labels dropped by the dedup stages of expr.
+func DedupLabels(expr Expr) []string {
+	var names []string
+	expr.Walk(func(e Expr) bool {
+		if d, ok := e.(*DedupExpr); ok {
+			names = append(names, d.Labels...)
+		}
+		return true
+	})
+	return names
+}
+
 type DropLabelsExpr struct {
 	dropLabels []log.NamedLabelMatcher
 }
@@ -1016,6 +1503,83 @@ func (j *JSONExpressionParserExpr) String() string {
 	return sb.String()
 }
 
+type XMLExpressionParserExpr struct {
+	Expressions []log.LabelExtractionExpr
+}
+
+func newXMLExpressionParser(expressions []log.LabelExtractionExpr) *XMLExpressionParserExpr {
+	for _, exp := range expressions {
+		if _, err := log.NewXMLExpressionParser([]log.LabelExtractionExpr{exp}); err != nil {
+			panic(logqlmodel.NewParseError(fmt.Sprintf("invalid xml expression: %s", err.Error()), 0, 0))
+		}
+	}
+	return &XMLExpressionParserExpr{
+		Expressions: expressions,
+	}
+}
+
+func (x *XMLExpressionParserExpr) Shardable(_ bool) bool { return true }
+
+func (x *XMLExpressionParserExpr) Walk(f WalkFn) { f(x) }
+
+func (x *XMLExpressionParserExpr) Accept(v RootVisitor) { v.VisitXMLExpressionParser(x) }
+
+func (x *XMLExpressionParserExpr) Stage() (log.Stage, error) {
+	return log.NewXMLExpressionParser(x.Expressions)
+}
+
+func (x *XMLExpressionParserExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s ", OpPipe, OpParserTypeXML))
+	for i, exp := range x.Expressions {
+		sb.WriteString(exp.Identifier)
+		sb.WriteString("=")
+		sb.WriteString(strconv.Quote(exp.Expression))
+
+		if i+1 != len(x.Expressions) {
+			sb.WriteString(",")
+		}
+	}
+	return sb.String()
+}
+
+type CSVParserExpr struct {
+	Columns []string
+}
+
+func newCSVParserExpr(columns []string) *CSVParserExpr {
+	if _, err := log.NewCSVParser(columns); err != nil {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid csv parser: %s", err.Error()), 0, 0))
+	}
+	return &CSVParserExpr{
+		Columns: columns,
+	}
+}
+
+func (c *CSVParserExpr) Shardable(_ bool) bool { return true }
+
+func (c *CSVParserExpr) Walk(f WalkFn) { f(c) }
+
+func (c *CSVParserExpr) Accept(v RootVisitor) { v.VisitCSVParser(c) }
+
+func (c *CSVParserExpr) Stage() (log.Stage, error) {
+	return log.NewCSVParser(c.Columns)
+}
+
+func (c *CSVParserExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s", OpPipe, OpParserTypeCSV))
+	for i, column := range c.Columns {
+		if i == 0 {
+			sb.WriteString(" ")
+		} else {
+			sb.WriteString(",")
+		}
+		sb.WriteString(strconv.Quote(column))
+	}
+	return sb.String()
+}
+
 type internedStringSet map[string]struct {
 	s  string
 	ok bool
@@ -1232,6 +1796,12 @@ const (
 	OpTypeSort     = "sort"
 	OpTypeSortDesc = "sort_desc"
 
+	OpTypeCountValues = "count_values"
+	OpTypeQuantile    = "quantile"
+	OpTypeGroup       = "group"
+	OpTypeLimitK      = "limitk"
+	OpTypeLimitRatio  = "limit_ratio"
+
 	// range vector ops
 	OpRangeTypeCount       = "count_over_time"
 	OpRangeTypeRate        = "rate"
@@ -1249,6 +1819,12 @@ const (
 	OpRangeTypeLast        = "last_over_time"
 	OpRangeTypeAbsent      = "absent_over_time"
 
+	// trend range aggregations, whose parameters follow the range
+	OpRangeTypeChanges       = "changes"
+	OpRangeTypeDeriv         = "deriv"
+	OpRangeTypePredictLinear = "predict_linear"
+	OpRangeTypeHoltWinters   = "holt_winters"
+
 	// vector
 	OpTypeVector = "vector"
 
@@ -1279,11 +1855,31 @@ const (
 	OpParserTypeRegexp  = "regexp"
 	OpParserTypeUnpack  = "unpack"
 	OpParserTypePattern = "pattern"
+	OpParserTypeXML     = "xml"
+	OpParserTypeCSV     = "csv"
 
 	OpFmtLine    = "line_format"
 	OpFmtLabel   = "label_format"
 	OpDecolorize = "decolorize"
 
+	// redact
+	OpRedact = "redact"
+
+	// sample
+	OpSample = "sample"
+
+	// context
+	OpContext       = "context"
+	OpContextBefore = "before"
+	OpContextAfter  = "after"
+
+	// lookup
+	OpLookup        = "lookup"
+	OpLookupVersion = "version"
+
+	// dedup
+	OpDedup = "dedup"
+
 	OpPipe   = "|"
 	OpUnwrap = "unwrap"
 	OpOffset = "offset"
@@ -1300,6 +1896,21 @@ const (
 	OpConvDurationSeconds = "duration_seconds"
 
 	OpLabelReplace = "label_replace"
+	OpLabelJoin    = "label_join"
+
+	// functions
+	OpFuncAbs       = "abs"
+	OpFuncCeil      = "ceil"
+	OpFuncFloor     = "floor"
+	OpFuncRound     = "round"
+	OpFuncClampMin  = "clamp_min"
+	OpFuncClampMax  = "clamp_max"
+	OpFuncSqrt      = "sqrt"
+	OpFuncLn        = "ln"
+	OpFuncExp       = "exp"
+	OpFuncTimestamp = "timestamp"
+	OpFuncHour      = "hour"
+	OpFuncDayOfWeek = "day_of_week"
 
 	// function filters
 	OpFilterIP = "ip"
@@ -1320,11 +1931,20 @@ const (
 	OpRangeTypeQuantileSketch     = "__quantile_sketch_over_time__"
 	OpRangeTypeFirstWithTimestamp = "__first_over_time_ts__"
 	OpRangeTypeLastWithTimestamp  = "__last_over_time_ts__"
+	OpRangeTypeHyperLogLog        = "__hyperloglog_over_time__"
+	OpRangeTypeHistogramBuckets   = "__histogram_buckets_over_time__"
 
 	OpTypeCountMinSketch = "__count_min_sketch__"
+	OpTypeHyperLogLog    = "__hyperloglog__"
 
 	// probabilistic aggregations
-	OpTypeApproxTopK = "approx_topk"
+	OpTypeApproxTopK               = "approx_topk"
+	OpTypeApproxCountDistinct      = "approx_count_distinct"
+	OpRangeTypeApproxCountDistinct = "approx_count_distinct_over_time"
+
+	// histograms
+	OpRangeTypeHistogram    = "histogram_over_time"
+	OpTypeHistogramQuantile = "histogram_quantile"
 
 	// variants
 	OpVariants = "variants"
@@ -1356,9 +1976,11 @@ type RangeAggregationExpr struct {
 	Left      *LogRangeExpr
 	Operation string
 
-	Params   *float64
-	Grouping *Grouping
-	err      error
+	Params *float64
+	// SecondParam is the trend factor of holt_winters.
+	SecondParam *float64
+	Grouping    *Grouping
+	err         error
 }
 
 func newRangeAggregationExpr(left *LogRangeExpr, operation string, gr *Grouping, stringParams *string) SampleExpr {
@@ -1375,89 +1997,412 @@ func newRangeAggregationExpr(left *LogRangeExpr, operation string, gr *Grouping,
 		}
 
 	} else {
-		if operation == OpRangeTypeQuantile {
+		if operation == OpRangeTypeQuantile || hasTrailingParams(operation) {
 			return &RangeAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("parameter required for operation %s", operation), 0, 0)}
 		}
 	}
-	e := &RangeAggregationExpr{
-		Left:      left,
-		Operation: operation,
-		Grouping:  gr,
-		Params:    params,
+	e := &RangeAggregationExpr{
+		Left:      left,
+		Operation: operation,
+		Grouping:  gr,
+		Params:    params,
+	}
+	if err := e.validate(); err != nil {
+		return &RangeAggregationExpr{err: logqlmodel.NewParseError(err.Error(), 0, 0)}
+	}
+	return e
+}
+
+// newTrailingParamsRangeAggregationExpr creates a range aggregation whose
+// parameters follow the range, such as `predict_linear(<range>, 3600)`.
+func newTrailingParamsRangeAggregationExpr(left *LogRangeExpr, operation string, gr *Grouping, stringParams []string) SampleExpr {
+	params, secondParam, err := parseTrailingParams(operation, stringParams)
+	if err != nil {
+		return &RangeAggregationExpr{err: logqlmodel.NewParseError(err.Error(), 0, 0)}
+	}
+	e := &RangeAggregationExpr{
+		Left:        left,
+		Operation:   operation,
+		Grouping:    gr,
+		Params:      params,
+		SecondParam: secondParam,
+	}
+	if err := e.validate(); err != nil {
+		return &RangeAggregationExpr{err: logqlmodel.NewParseError(err.Error(), 0, 0)}
+	}
+	return e
+}
+
+// hasTrailingParams returns whether the parameters of a range aggregation
+// follow its range, as in PromQL.
+func hasTrailingParams(operation string) bool {
+	return operation == OpRangeTypePredictLinear || operation == OpRangeTypeHoltWinters
+}
+
+// parseTrailingParams parses the parameters following the range of
+// predict_linear and holt_winters.
+func parseTrailingParams(operation string, stringParams []string) (*float64, *float64, error) {
+	var expected int
+	switch operation {
+	case OpRangeTypePredictLinear:
+		expected = 1
+	case OpRangeTypeHoltWinters:
+		expected = 2
+	default:
+		return nil, nil, fmt.Errorf("parameter %s not supported for operation %s", stringParams[0], operation)
+	}
+	if len(stringParams) != expected {
+		return nil, nil, fmt.Errorf("expected %d parameters for operation %s, got %d", expected, operation, len(stringParams))
+	}
+	params := make([]*float64, 2)
+	for i, sp := range stringParams {
+		v, err := strconv.ParseFloat(sp, 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid parameter for operation %s: %s", operation, err)
+		}
+		params[i] = &v
+	}
+	return params[0], params[1], nil
+}
+
+// validateTrailingParams validates the parameters of holt_winters, whose
+// smoothing and trend factors must be between 0 and 1.
+func validateTrailingParams(operation string, params, secondParam *float64) error {
+	if operation != OpRangeTypeHoltWinters {
+		if secondParam != nil {
+			return fmt.Errorf("invalid second parameter for operation %s", operation)
+		}
+		return nil
+	}
+	if params == nil || secondParam == nil {
+		return fmt.Errorf("parameter required for operation %s", operation)
+	}
+	if *params <= 0 || *params >= 1 {
+		return fmt.Errorf("invalid smoothing factor for operation %s, expected 0 < sf < 1: %v", operation, *params)
+	}
+	if *secondParam <= 0 || *secondParam >= 1 {
+		return fmt.Errorf("invalid trend factor for operation %s, expected 0 < tf < 1: %v", operation, *secondParam)
+	}
+	return nil
+}
+
+func (e *RangeAggregationExpr) Selector() (LogSelectorExpr, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.Left, nil
+}
+
+func (e *RangeAggregationExpr) MatcherGroups() ([]MatcherRange, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	xs := e.Left.Left.Matchers()
+	if len(xs) > 0 {
+		return []MatcherRange{
+			{
+				Matchers: xs,
+				Interval: e.Left.Interval,
+				Offset:   e.Left.Offset,
+			},
+		}, nil
+	}
+	return nil, nil
+}
+
+func (e RangeAggregationExpr) validate() error {
+	if e.Grouping != nil {
+		switch e.Operation {
+		case OpRangeTypeAvg, OpRangeTypeStddev, OpRangeTypeStdvar, OpRangeTypeQuantile,
+			OpRangeTypeQuantileSketch, OpRangeTypeMax, OpRangeTypeMin, OpRangeTypeFirst,
+			OpRangeTypeLast, OpRangeTypeFirstWithTimestamp, OpRangeTypeLastWithTimestamp,
+			OpRangeTypeApproxCountDistinct, OpRangeTypeHyperLogLog, OpRangeTypeHistogram,
+			OpRangeTypeHistogramBuckets, OpRangeTypeChanges, OpRangeTypeDeriv, OpRangeTypePredictLinear,
+			OpRangeTypeHoltWinters:
+		default:
+			return fmt.Errorf("grouping not allowed for %s aggregation", e.Operation)
+		}
+	}
+	if err := validateTrailingParams(e.Operation, e.Params, e.SecondParam); err != nil {
+		return err
+	}
+	if e.Left.Unwrap != nil {
+		switch e.Operation {
+		case OpRangeTypeAvg, OpRangeTypeSum, OpRangeTypeMax, OpRangeTypeMin, OpRangeTypeStddev,
+			OpRangeTypeStdvar, OpRangeTypeQuantile, OpRangeTypeRate, OpRangeTypeRateCounter,
+			OpRangeTypeAbsent, OpRangeTypeFirst, OpRangeTypeLast, OpRangeTypeQuantileSketch,
+			OpRangeTypeFirstWithTimestamp, OpRangeTypeLastWithTimestamp,
+			OpRangeTypeApproxCountDistinct, OpRangeTypeHyperLogLog, OpRangeTypeHistogram,
+			OpRangeTypeHistogramBuckets, OpRangeTypeChanges, OpRangeTypeDeriv, OpRangeTypePredictLinear,
+			OpRangeTypeHoltWinters:
+			return nil
+		default:
+			return fmt.Errorf("invalid aggregation %s with unwrap", e.Operation)
+		}
+	}
+	switch e.Operation {
+	case OpRangeTypeBytes, OpRangeTypeBytesRate, OpRangeTypeCount, OpRangeTypeRate, OpRangeTypeAbsent:
+		return nil
+	default:
+		return fmt.Errorf("invalid aggregation %s without unwrap", e.Operation)
+	}
+}
+
+func (e RangeAggregationExpr) Validate() error {
+	return e.validate()
+}
+
+// impls Stringer
+func (e *RangeAggregationExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(e.Operation)
+	sb.WriteString("(")
+	if hasTrailingParams(e.Operation) {
+		sb.WriteString(e.Left.String())
+		writeTrailingParams(&sb, e.Params, e.SecondParam)
+	} else {
+		if e.Params != nil {
+			sb.WriteString(strconv.FormatFloat(*e.Params, 'f', -1, 64))
+			sb.WriteString(",")
+		}
+		sb.WriteString(e.Left.String())
+	}
+	sb.WriteString(")")
+	if e.Grouping != nil {
+		sb.WriteString(e.Grouping.String())
+	}
+	return sb.String()
+}
+
+// impl SampleExpr
+func (e *RangeAggregationExpr) Shardable(topLevel bool) bool {
+	// Here we are blocking sharding of quantile operations if they are not
+	// the top level aggregation in a query, such as max(quantile_over_time(...)).
+	// The sharding here will be blocked even if the feature flag in the shardmapper
+	// to enable sharding of quantile queries is enabled.
+	if e.Operation == OpRangeTypeQuantile && !topLevel {
+		return false
+	}
+	return shardableOps[e.Operation] && e.Left.Shardable(topLevel)
+}
+
+// writeTrailingParams writes the parameters following the range of
+// predict_linear and holt_winters.
+func writeTrailingParams(sb *strings.Builder, params ...*float64) {
+	for _, p := range params {
+		if p == nil {
+			continue
+		}
+		sb.WriteString(",")
+		sb.WriteString(strconv.FormatFloat(*p, 'f', -1, 64))
+	}
+}
+
+func (e *RangeAggregationExpr) Walk(f WalkFn) {
+	if !f(e) {
+		return
+	}
+	if e.Left != nil {
+		e.Left.Walk(f)
+	}
+}
+
+func (e *RangeAggregationExpr) Accept(v RootVisitor) { v.VisitRangeAggregation(e) }
+
+// SubqueryExpr is a sample expression evaluated at each step of a range, such
+// as `sum(rate({app="foo"}[1m]))[1h:1m]`. A zero Step evaluates the inner
+// expression at the step of the query.
+type SubqueryExpr struct {
+	Left   SampleExpr
+	Range  time.Duration
+	Step   time.Duration
+	Offset time.Duration
+}
+
+// subqueryRange is the `[range:step]` token of a subquery.
+type subqueryRange struct {
+	Range, Step time.Duration
+}
+
+func newSubqueryExpr(left SampleExpr, r subqueryRange, o *OffsetExpr) *SubqueryExpr {
+	var offset time.Duration
+	if o != nil {
+		offset = o.Offset
+	}
+	return &SubqueryExpr{
+		Left:   left,
+		Range:  r.Range,
+		Step:   r.Step,
+		Offset: offset,
+	}
+}
+
+// impls Stringer
+func (e SubqueryExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(e.Left.String())
+	sb.WriteString(fmt.Sprintf("[%v:", model.Duration(e.Range)))
+	if e.Step != 0 {
+		sb.WriteString(model.Duration(e.Step).String())
+	}
+	sb.WriteString("]")
+	if e.Offset != 0 {
+		offsetExpr := OffsetExpr{Offset: e.Offset}
+		sb.WriteString(offsetExpr.String())
+	}
+	return sb.String()
+}
+
+// Shardable returns false, as the inner expression of a subquery is sharded
+// on its own.
+func (e *SubqueryExpr) Shardable(_ bool) bool { return false }
+
+func (e *SubqueryExpr) Walk(f WalkFn) {
+	if !f(e) {
+		return
+	}
+	if e.Left != nil {
+		e.Left.Walk(f)
+	}
+}
+
+func (e *SubqueryExpr) Accept(v RootVisitor) { v.VisitSubquery(e) }
+
+// SubqueryAggregationExpr is a range aggregation over the samples of a subquery,
+// such as `max_over_time(sum(rate({app="foo"}[1m]))[1h:1m])`.
+type SubqueryAggregationExpr struct {
+	Left      *SubqueryExpr
+	Operation string
+
+	Params *float64
+	// SecondParam is the trend factor of holt_winters.
+	SecondParam *float64
+	Grouping    *Grouping
+	err         error
+}
+
+func newSubqueryAggregationExpr(left *SubqueryExpr, operation string, gr *Grouping, stringParams *string) SampleExpr {
+	var params *float64
+	if stringParams != nil {
+		if operation != OpRangeTypeQuantile {
+			return &SubqueryAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("parameter %s not supported for operation %s", *stringParams, operation), 0, 0)}
+		}
+		var err error
+		params = new(float64)
+		*params, err = strconv.ParseFloat(*stringParams, 64)
+		if err != nil {
+			return &SubqueryAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("invalid parameter for operation %s: %s", operation, err), 0, 0)}
+		}
+	} else if operation == OpRangeTypeQuantile || hasTrailingParams(operation) {
+		return &SubqueryAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("parameter required for operation %s", operation), 0, 0)}
+	}
+	e := &SubqueryAggregationExpr{
+		Left:      left,
+		Operation: operation,
+		Grouping:  gr,
+		Params:    params,
+	}
+	if err := e.validate(); err != nil {
+		return &SubqueryAggregationExpr{err: logqlmodel.NewParseError(err.Error(), 0, 0)}
+	}
+	return e
+}
+
+// newTrailingParamsSubqueryAggregationExpr creates a range aggregation over a
+// subquery whose parameters follow the subquery, such as
+// `predict_linear(<subquery>, 3600)`.
+func newTrailingParamsSubqueryAggregationExpr(left *SubqueryExpr, operation string, gr *Grouping, stringParams []string) SampleExpr {
+	params, secondParam, err := parseTrailingParams(operation, stringParams)
+	if err != nil {
+		return &SubqueryAggregationExpr{err: logqlmodel.NewParseError(err.Error(), 0, 0)}
+	}
+	e := &SubqueryAggregationExpr{
+		Left:        left,
+		Operation:   operation,
+		Grouping:    gr,
+		Params:      params,
+		SecondParam: secondParam,
 	}
 	if err := e.validate(); err != nil {
-		return &RangeAggregationExpr{err: logqlmodel.NewParseError(err.Error(), 0, 0)}
+		return &SubqueryAggregationExpr{err: logqlmodel.NewParseError(err.Error(), 0, 0)}
 	}
 	return e
 }
 
-func (e *RangeAggregationExpr) Selector() (LogSelectorExpr, error) {
-	if e.err != nil {
-		return nil, e.err
+func (e SubqueryAggregationExpr) validate() error {
+	switch e.Operation {
+	case OpRangeTypeAvg, OpRangeTypeStddev, OpRangeTypeStdvar, OpRangeTypeQuantile,
+		OpRangeTypeMax, OpRangeTypeMin, OpRangeTypeFirst, OpRangeTypeLast,
+		OpRangeTypeChanges, OpRangeTypeDeriv, OpRangeTypePredictLinear, OpRangeTypeHoltWinters:
+	case OpRangeTypeCount, OpRangeTypeSum:
+		if e.Grouping != nil {
+			return fmt.Errorf("grouping not allowed for %s aggregation", e.Operation)
+		}
+	default:
+		return fmt.Errorf("invalid aggregation %s over subquery", e.Operation)
 	}
-	return e.Left.Left, nil
+	if err := validateTrailingParams(e.Operation, e.Params, e.SecondParam); err != nil {
+		return err
+	}
+	if e.Left.Range <= 0 {
+		return fmt.Errorf("invalid subquery range %s", model.Duration(e.Left.Range))
+	}
+	if e.Left.Step < 0 {
+		return fmt.Errorf("invalid subquery step %s", e.Left.Step)
+	}
+	return nil
 }
 
-func (e *RangeAggregationExpr) MatcherGroups() ([]MatcherRange, error) {
+func (e SubqueryAggregationExpr) Validate() error {
+	return e.validate()
+}
+
+func (e *SubqueryAggregationExpr) Selector() (LogSelectorExpr, error) {
 	if e.err != nil {
 		return nil, e.err
 	}
-	xs := e.Left.Left.Matchers()
-	if len(xs) > 0 {
-		return []MatcherRange{
-			{
-				Matchers: xs,
-				Interval: e.Left.Interval,
-				Offset:   e.Left.Offset,
-			},
-		}, nil
-	}
-	return nil, nil
+	return e.Left.Left.Selector()
 }
 
-func (e RangeAggregationExpr) validate() error {
-	if e.Grouping != nil {
-		switch e.Operation {
-		case OpRangeTypeAvg, OpRangeTypeStddev, OpRangeTypeStdvar, OpRangeTypeQuantile,
-			OpRangeTypeQuantileSketch, OpRangeTypeMax, OpRangeTypeMin, OpRangeTypeFirst,
-			OpRangeTypeLast, OpRangeTypeFirstWithTimestamp, OpRangeTypeLastWithTimestamp:
-		default:
-			return fmt.Errorf("grouping not allowed for %s aggregation", e.Operation)
-		}
+// MatcherGroups returns the matcher groups of the inner expression, with
+// their intervals extended by the range of the subquery.
+func (e *SubqueryAggregationExpr) MatcherGroups() ([]MatcherRange, error) {
+	if e.err != nil {
+		return nil, e.err
 	}
-	if e.Left.Unwrap != nil {
-		switch e.Operation {
-		case OpRangeTypeAvg, OpRangeTypeSum, OpRangeTypeMax, OpRangeTypeMin, OpRangeTypeStddev,
-			OpRangeTypeStdvar, OpRangeTypeQuantile, OpRangeTypeRate, OpRangeTypeRateCounter,
-			OpRangeTypeAbsent, OpRangeTypeFirst, OpRangeTypeLast, OpRangeTypeQuantileSketch,
-			OpRangeTypeFirstWithTimestamp, OpRangeTypeLastWithTimestamp:
-			return nil
-		default:
-			return fmt.Errorf("invalid aggregation %s with unwrap", e.Operation)
-		}
+	groups, err := e.Left.Left.MatcherGroups()
+	if err != nil {
+		return nil, err
 	}
-	switch e.Operation {
-	case OpRangeTypeBytes, OpRangeTypeBytesRate, OpRangeTypeCount, OpRangeTypeRate, OpRangeTypeAbsent:
-		return nil
-	default:
-		return fmt.Errorf("invalid aggregation %s without unwrap", e.Operation)
+	for i := range groups {
+		groups[i].Interval += e.Left.Range
+		groups[i].Offset += e.Left.Offset
 	}
+	return groups, nil
 }
 
-func (e RangeAggregationExpr) Validate() error {
-	return e.validate()
+func (e *SubqueryAggregationExpr) Extractors() ([]SampleExtractor, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.Left.Extractors()
 }
 
 // impls Stringer
-func (e *RangeAggregationExpr) String() string {
+func (e *SubqueryAggregationExpr) String() string {
 	var sb strings.Builder
 	sb.WriteString(e.Operation)
 	sb.WriteString("(")
-	if e.Params != nil {
-		sb.WriteString(strconv.FormatFloat(*e.Params, 'f', -1, 64))
-		sb.WriteString(",")
+	if hasTrailingParams(e.Operation) {
+		sb.WriteString(e.Left.String())
+		writeTrailingParams(&sb, e.Params, e.SecondParam)
+	} else {
+		if e.Params != nil {
+			sb.WriteString(strconv.FormatFloat(*e.Params, 'f', -1, 64))
+			sb.WriteString(",")
+		}
+		sb.WriteString(e.Left.String())
 	}
-	sb.WriteString(e.Left.String())
 	sb.WriteString(")")
 	if e.Grouping != nil {
 		sb.WriteString(e.Grouping.String())
@@ -1465,19 +2410,11 @@ func (e *RangeAggregationExpr) String() string {
 	return sb.String()
 }
 
-// impl SampleExpr
-func (e *RangeAggregationExpr) Shardable(topLevel bool) bool {
-	// Here we are blocking sharding of quantile operations if they are not
-	// the top level aggregation in a query, such as max(quantile_over_time(...)).
-	// The sharding here will be blocked even if the feature flag in the shardmapper
-	// to enable sharding of quantile queries is enabled.
-	if e.Operation == OpRangeTypeQuantile && !topLevel {
-		return false
-	}
-	return shardableOps[e.Operation] && e.Left.Shardable(topLevel)
-}
+// Shardable returns false, as the samples of a subquery are only known once
+// its inner expression has been evaluated over all shards.
+func (e *SubqueryAggregationExpr) Shardable(_ bool) bool { return false }
 
-func (e *RangeAggregationExpr) Walk(f WalkFn) {
+func (e *SubqueryAggregationExpr) Walk(f WalkFn) {
 	if !f(e) {
 		return
 	}
@@ -1486,7 +2423,7 @@ func (e *RangeAggregationExpr) Walk(f WalkFn) {
 	}
 }
 
-func (e *RangeAggregationExpr) Accept(v RootVisitor) { v.VisitRangeAggregation(e) }
+func (e *SubqueryAggregationExpr) Accept(v RootVisitor) { v.VisitSubqueryAggregation(e) }
 
 // Grouping struct represents the grouping by/without label(s) for vector aggregators and range vector aggregators.
 // The representation is as follows:
@@ -1536,14 +2473,21 @@ type VectorAggregationExpr struct {
 	Grouping  *Grouping `json:"grouping,omitempty"`
 	Params    int       `json:"params"`
 	Operation string    `json:"operation"`
-	err       error
+	// Quantile is the φ parameter of quantile and histogram_quantile.
+	Quantile float64 `json:"quantile,omitempty"`
+	// Ratio is the ratio of series kept by limit_ratio.
+	Ratio float64 `json:"ratio,omitempty"`
+	// Label is the label holding the counted values of count_values.
+	Label string `json:"label,omitempty"`
+	err   error
 }
 
 func mustNewVectorAggregationExpr(left SampleExpr, operation string, gr *Grouping, params *string) SampleExpr {
 	var p int
+	var q, ratio float64
 	var err error
 	switch operation {
-	case OpTypeBottomK, OpTypeTopK, OpTypeApproxTopK:
+	case OpTypeBottomK, OpTypeTopK, OpTypeApproxTopK, OpTypeLimitK:
 		if params == nil {
 			return &VectorAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("parameter required for operation %s", operation), 0, 0)}
 		}
@@ -1558,6 +2502,37 @@ func mustNewVectorAggregationExpr(left SampleExpr, operation string, gr *Groupin
 			return &VectorAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("grouping not allowed for %s aggregation", operation), 0, 0)}
 		}
 
+	case OpTypeHistogramQuantile, OpTypeQuantile:
+		if params == nil {
+			return &VectorAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("parameter required for operation %s", operation), 0, 0)}
+		}
+		q, err = strconv.ParseFloat(*params, 64)
+		if err != nil {
+			return &VectorAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("invalid parameter %s(%s,", operation, *params), 0, 0)}
+		}
+		// the buckets of a histogram are the series which only differ by their bucket label.
+		if operation == OpTypeHistogramQuantile && gr != nil {
+			return &VectorAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("grouping not allowed for %s aggregation", operation), 0, 0)}
+		}
+
+	case OpTypeLimitRatio:
+		if params == nil {
+			return &VectorAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("parameter required for operation %s", operation), 0, 0)}
+		}
+		ratio, err = strconv.ParseFloat(*params, 64)
+		if err != nil {
+			return &VectorAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("invalid parameter %s(%s,", operation, *params), 0, 0)}
+		}
+		if ratio < -1 || ratio > 1 {
+			return &VectorAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("invalid parameter (must be between -1 and 1) %s(%s,", operation, *params), 0, 0)}
+		}
+
+	case OpTypeCountValues:
+		if params == nil {
+			return &VectorAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("parameter required for operation %s", operation), 0, 0)}
+		}
+		return &VectorAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("invalid parameter %s(%s, expected a label name", operation, *params), 0, 0)}
+
 	default:
 		if params != nil {
 			return &VectorAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("unsupported parameter for operation %s(%s,", operation, *params), 0, 0)}
@@ -1571,6 +2546,28 @@ func mustNewVectorAggregationExpr(left SampleExpr, operation string, gr *Groupin
 		Operation: operation,
 		Grouping:  gr,
 		Params:    p,
+		Quantile:  q,
+		Ratio:     ratio,
+	}
+}
+
+// mustNewCountValuesExpr creates a count_values aggregation, which counts the
+// series with the same value and stores the value in the label.
+func mustNewCountValuesExpr(left SampleExpr, operation string, gr *Grouping, label string) SampleExpr {
+	if operation != OpTypeCountValues {
+		return &VectorAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("unsupported parameter for operation %s(%q,", operation, label), 0, 0)}
+	}
+	if !model.LabelName(label).IsValid() {
+		return &VectorAggregationExpr{err: logqlmodel.NewParseError(fmt.Sprintf("invalid label name in %s: %s", operation, label), 0, 0)}
+	}
+	if gr == nil {
+		gr = &Grouping{}
+	}
+	return &VectorAggregationExpr{
+		Left:      left,
+		Operation: operation,
+		Grouping:  gr,
+		Label:     label,
 	}
 }
 
@@ -1624,8 +2621,14 @@ func (e *VectorAggregationExpr) String() string {
 	var params []string
 	switch e.Operation {
 	// bottomK and topk can have first parameter as 0
-	case OpTypeBottomK, OpTypeTopK, OpTypeApproxTopK:
+	case OpTypeBottomK, OpTypeTopK, OpTypeApproxTopK, OpTypeLimitK:
 		params = []string{fmt.Sprintf("%d", e.Params), e.Left.String()}
+	case OpTypeHistogramQuantile, OpTypeQuantile:
+		params = []string{strconv.FormatFloat(e.Quantile, 'f', -1, 64), e.Left.String()}
+	case OpTypeLimitRatio:
+		params = []string{strconv.FormatFloat(e.Ratio, 'f', -1, 64), e.Left.String()}
+	case OpTypeCountValues:
+		params = []string{strconv.Quote(e.Label), e.Left.String()}
 	default:
 		if e.Params != 0 {
 			params = []string{fmt.Sprintf("%d", e.Params), e.Left.String()}
@@ -1641,14 +2644,22 @@ func (e *VectorAggregationExpr) Shardable(topLevel bool) bool {
 	if !shardableOps[e.Operation] || !e.Left.Shardable(topLevel) {
 		return false
 	}
+	// limitk keeps up to k series on every shard, and every shard would
+	// have its own histogram buckets, so aggregations above them can only be
+	// computed once the shards are merged.
+	if limitsSeries(e.Left) || hasHistogram(e.Left) {
+		return false
+	}
 
 	switch e.Operation {
 
-	case OpTypeCount, OpTypeAvg:
+	case OpTypeCount, OpTypeAvg, OpTypeCountValues, OpTypeLimitK, OpTypeLimitRatio:
 		// count is shardable if labels are not mutated
 		// otherwise distinct values can be present in multiple shards and
 		// counted twice.
 		// avg is similar since it's remapped to sum/count.
+		// count_values is also remapped to sum/count_values, and limitk and
+		// limit_ratio would select partial series.
 		// TODO(owen-d): this is hard to figure out; we should refactor to
 		// make these relationships clearer, safer, and more extensible.
 		shardable := !ReducesLabels(e.Left)
@@ -2271,6 +3282,203 @@ func (e *LabelReplaceExpr) String() string {
 	return sb.String()
 }
 
+type LabelJoinExpr struct {
+	Left      SampleExpr
+	Dst       string
+	Separator string
+	Src       []string
+	err       error
+}
+
+func mustNewLabelJoinExpr(left SampleExpr, dst, separator string, src []string) *LabelJoinExpr {
+	if !model.LabelName(dst).IsValid() {
+		return &LabelJoinExpr{
+			err: logqlmodel.NewParseError(fmt.Sprintf("invalid destination label name in label_join: %s", dst), 0, 0),
+		}
+	}
+	for _, name := range src {
+		if !model.LabelName(name).IsValid() {
+			return &LabelJoinExpr{
+				err: logqlmodel.NewParseError(fmt.Sprintf("invalid source label name in label_join: %s", name), 0, 0),
+			}
+		}
+	}
+	return &LabelJoinExpr{
+		Left:      left,
+		Dst:       dst,
+		Separator: separator,
+		Src:       src,
+	}
+}
+
+func (e *LabelJoinExpr) Selector() (LogSelectorExpr, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.Selector()
+}
+
+func (e *LabelJoinExpr) MatcherGroups() ([]MatcherRange, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	return e.Left.MatcherGroups()
+}
+
+func (e *LabelJoinExpr) Extractors() ([]SampleExtractor, error) {
+	if e.err != nil {
+		return []SampleExtractor{}, e.err
+	}
+	return e.Left.Extractors()
+}
+
+func (e *LabelJoinExpr) Shardable(_ bool) bool {
+	return false
+}
+
+func (e *LabelJoinExpr) Walk(f WalkFn) {
+	if !f(e) {
+		return
+	}
+	if e.Left != nil {
+		e.Left.Walk(f)
+	}
+}
+
+func (e *LabelJoinExpr) Accept(v RootVisitor) { v.VisitLabelJoin(e) }
+
+func (e *LabelJoinExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(OpLabelJoin)
+	sb.WriteString("(")
+	sb.WriteString(e.Left.String())
+	sb.WriteString(",")
+	sb.WriteString(strconv.Quote(e.Dst))
+	sb.WriteString(",")
+	sb.WriteString(strconv.Quote(e.Separator))
+	for _, src := range e.Src {
+		sb.WriteString(",")
+		sb.WriteString(strconv.Quote(src))
+	}
+	sb.WriteString(")")
+	return sb.String()
+}
+
+// FunctionExpr applies a PromQL function, such as `abs` or `hour`, to each
+// sample of a vector. Left is nil for `hour()` and `day_of_week()` without
+// argument, which apply to the time of each step instead.
+type FunctionExpr struct {
+	Left     SampleExpr
+	Function string
+	Param    *float64
+	err      error
+}
+
+func mustNewFunctionExpr(left SampleExpr, function string, param *LiteralExpr) *FunctionExpr {
+	e := &FunctionExpr{
+		Left:     left,
+		Function: function,
+	}
+	if param != nil {
+		if param.err != nil {
+			return &FunctionExpr{err: param.err}
+		}
+		e.Param = &param.Val
+	}
+	if err := e.validate(); err != nil {
+		return &FunctionExpr{err: logqlmodel.NewParseError(err.Error(), 0, 0)}
+	}
+	return e
+}
+
+func (e *FunctionExpr) validate() error {
+	if _, ok := e.Left.(*LiteralExpr); ok {
+		return fmt.Errorf("expected type instant vector in call to function %s, got scalar", e.Function)
+	}
+	switch e.Function {
+	case OpFuncHour, OpFuncDayOfWeek:
+		if e.Param != nil {
+			return fmt.Errorf("invalid parameter for function %s", e.Function)
+		}
+		return nil
+	case OpFuncClampMin, OpFuncClampMax:
+		if e.Param == nil {
+			return fmt.Errorf("parameter required for function %s", e.Function)
+		}
+	case OpFuncRound:
+	default:
+		if e.Param != nil {
+			return fmt.Errorf("invalid parameter for function %s", e.Function)
+		}
+	}
+	if e.Left == nil {
+		return fmt.Errorf("argument required for function %s", e.Function)
+	}
+	return nil
+}
+
+func (e *FunctionExpr) Selector() (LogSelectorExpr, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	if e.Left == nil {
+		return &VectorExpr{}, nil
+	}
+	return e.Left.Selector()
+}
+
+func (e *FunctionExpr) MatcherGroups() ([]MatcherRange, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	if e.Left == nil {
+		return nil, nil
+	}
+	return e.Left.MatcherGroups()
+}
+
+func (e *FunctionExpr) Extractors() ([]SampleExtractor, error) {
+	if e.err != nil {
+		return []SampleExtractor{}, e.err
+	}
+	if e.Left == nil {
+		return []SampleExtractor{}, nil
+	}
+	return e.Left.Extractors()
+}
+
+// Shardable returns whether the argument of the function is shardable, since
+// functions apply to each sample independently.
+func (e *FunctionExpr) Shardable(topLevel bool) bool {
+	return e.Left != nil && e.Left.Shardable(topLevel)
+}
+
+func (e *FunctionExpr) Walk(f WalkFn) {
+	if !f(e) {
+		return
+	}
+	if e.Left != nil {
+		e.Left.Walk(f)
+	}
+}
+
+func (e *FunctionExpr) Accept(v RootVisitor) { v.VisitFunction(e) }
+
+func (e *FunctionExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(e.Function)
+	sb.WriteString("(")
+	if e.Left != nil {
+		sb.WriteString(e.Left.String())
+	}
+	if e.Param != nil {
+		sb.WriteString(",")
+		sb.WriteString(strconv.FormatFloat(*e.Param, 'f', -1, 64))
+	}
+	sb.WriteString(")")
+	return sb.String()
+}
+
 // shardableOps lists the operations which may be sharded, but are not
 // guaranteed to be. See the `Shardable()` implementations
 // on the respective expr types for more details.
@@ -2301,6 +3509,11 @@ var shardableOps = map[string]bool{
 
 	OpTypeApproxTopK: true,
 
+	OpTypeCountValues: true,
+	OpTypeGroup:       true,
+	OpTypeLimitK:      true,
+	OpTypeLimitRatio:  true,
+
 	// range vector ops
 	OpRangeTypeAvg:       true,
 	OpRangeTypeCount:     true,
@@ -2313,6 +3526,7 @@ var shardableOps = map[string]bool{
 	OpRangeTypeMax:       true,
 	OpRangeTypeMin:       true,
 	OpRangeTypeQuantile:  true,
+	OpRangeTypeHistogram: true,
 
 	// binops - arith
 	OpTypeAdd: true,
@@ -2426,6 +3640,27 @@ func ReducesLabels(e Expr) (conflict bool) {
 	return
 }
 
+// limitsSeries reports whether the expression contains a limitk aggregation.
+func limitsSeries(e Expr) (limited bool) {
+	e.Walk(func(e Expr) bool {
+		if expr, ok := e.(*VectorAggregationExpr); ok && expr.Operation == OpTypeLimitK {
+			limited = true
+		}
+		return !limited
+	})
+	return
+}
+
+func hasHistogram(e Expr) (found bool) {
+	e.Walk(func(e Expr) bool {
+		if expr, ok := e.(*RangeAggregationExpr); ok && expr.Operation == OpRangeTypeHistogram {
+			found = true
+		}
+		return !found
+	})
+	return
+}
+
 func groupingReducesLabels(grp *Grouping) bool {
 	if grp == nil {
 		return false
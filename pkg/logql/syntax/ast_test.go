@@ -1,11 +1,14 @@
 package syntax
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/grafana/dskit/user"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/promql"
 	"github.com/stretchr/testify/assert"
@@ -51,6 +54,8 @@ func Test_logSelectorExpr_String(t *testing.T) {
 		{`{foo="bar"} |= "baz" |~ "blip" != "flip" !~ "flap" | regexp "(?P<foo>foo|bar)"`, true},
 		{`{foo="bar"} |= "baz" |~ "blip" != "flip" !~ "flap" | regexp "(?P<foo>foo|bar)" | ( ( foo<5.01 , bar>20ms ) or foo="bar" ) | line_format "blip{{.boop}}bap" | label_format foo=bar,bar="blip{{.blop}}"`, true},
 		{`{foo="bar"} | logfmt | counter>-1 | counter>=-1 | counter<-1 | counter<=-1 | counter!=-1 | counter==-1`, true},
+		{`{foo="bar"} |= "baz" | sample 0.01 | logfmt`, true},
+		{`{foo="bar"} | sample 0.125 by stream | sample 0.5`, true},
 	}
 
 	for _, tt := range tests {
@@ -1051,6 +1056,16 @@ func TestFilterReordering(t *testing.T) {
 		require.Len(t, stages, 2)
 		require.Equal(t, `| json | bar="next"`, MultiStageExpr(stages).String())
 	})
+
+	t.Run("it makes sure line filters after redact stay after it", func(t *testing.T) {
+		logExpr := `{container_name="app"} |= "foo" | redact email |= "<redacted:email>"`
+		l, err := ParseExpr(logExpr)
+		require.NoError(t, err)
+
+		stages := l.(*PipelineExpr).MultiStages.reorderStages()
+		require.Len(t, stages, 3)
+		require.Equal(t, `|= "foo" | redact email |= "<redacted:email>"`, MultiStageExpr(stages).String())
+	})
 }
 
 var result bool
@@ -1222,3 +1237,206 @@ variants(
 		})
 	}
 }
+
+func TestSamplingScale(t *testing.T) {
+	for _, tc := range []struct {
+		query string
+		scale float64
+	}{
+		{`count_over_time({foo="bar"}[1m])`, 1},
+		{`count_over_time({foo="bar"} | sample 0.1 [1m])`, 10},
+		{`count_over_time({foo="bar"} | sample 0.5 | json | sample 0.1 [1m])`, 10},
+		{`count_over_time({foo="bar"} | sample 0.25 by stream [1m])`, 4},
+		// stream sampling ratios are rounded down to a power of two
+		{`count_over_time({foo="bar"} | sample 0.1 by stream [1m])`, 16},
+		{`count_over_time({foo="bar"} | sample 0.5 by stream | sample 0.5 [1m])`, 4},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			expr, err := ParseExpr(tc.query)
+			require.NoError(t, err)
+			require.Equal(t, tc.scale, SamplingScale(expr))
+		})
+	}
+}
+
+func TestSplitContextStage(t *testing.T) {
+	for _, tc := range []struct {
+		query   string
+		context *ContextExpr
+		match   string
+	}{
+		{`{foo="bar"} |= "panic"`, nil, `{foo="bar"} |= "panic"`},
+		{`{foo="bar"} | context before=2`, &ContextExpr{Before: 2}, `{foo="bar"}`},
+		{`{foo="bar"} |= "panic" | json | level="error" | context before=2 after=1`, &ContextExpr{Before: 2, After: 1}, `{foo="bar"} |= "panic" | json | level="error"`},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			expr, err := ParseLogSelector(tc.query, true)
+			require.NoError(t, err)
+			c, match := SplitContextStage(expr)
+			require.Equal(t, tc.context, c)
+			require.Equal(t, tc.match, match.String())
+			require.Equal(t, tc.context != nil, HasContextStage(expr))
+		})
+	}
+}
+
+func TestLeadingLineFilters(t *testing.T) {
+	for _, tc := range []struct {
+		query    string
+		selector string
+	}{
+		{`{foo="bar"}`, `{foo="bar"}`},
+		{`{foo="bar"} |= "panic" != "test"`, `{foo="bar"} |= "panic" != "test"`},
+		{`{foo="bar"} |= "panic" | json | level="error" |= "db"`, `{foo="bar"} |= "panic"`},
+		{`{foo="bar"} | json |= "panic"`, `{foo="bar"}`},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			expr, err := ParseLogSelector(tc.query, true)
+			require.NoError(t, err)
+			require.Equal(t, tc.selector, LeadingLineFilters(expr).String())
+		})
+	}
+}
+
+type lookupTablesFunc func(ctx context.Context, name, version string) (*log.LookupTable, error)
+
+func (f lookupTablesFunc) LookupTable(ctx context.Context, name, version string) (*log.LookupTable, error) {
+	return f(ctx, name, version)
+}
+
+func TestBindLookupTables(t *testing.T) {
+	ctx := context.Background()
+	customers := log.NewLookupTable("customers", "0f3a0f3a0f3a0f3a", []string{"customer_id", "tier"}, [][]string{{"1", "gold"}, {"2", "silver"}})
+	var versions []string
+	tables := lookupTablesFunc(func(_ context.Context, name, version string) (*log.LookupTable, error) {
+		if name != customers.Name {
+			return nil, fmt.Errorf("lookup table %s not found", name)
+		}
+		versions = append(versions, version)
+		return customers, nil
+	})
+
+	// Queries without lookup stages are returned as is.
+	expr, err := ParseLogSelector(`{app="foo"} | json`, true)
+	require.NoError(t, err)
+	bound, err := BindLookupTables(ctx, expr, nil)
+	require.NoError(t, err)
+	require.Same(t, expr, bound)
+
+	expr, err = ParseLogSelector(`{app="foo"} | logfmt | lookup customers on customer_id version "0f3a0f3a0f3a0f3a"`, true)
+	require.NoError(t, err)
+	_, err = BindLookupTables(ctx, expr, nil)
+	require.EqualError(t, err, "lookup tables are not enabled")
+	_, err = expr.Pipeline()
+	require.ErrorContains(t, err, "lookup table customers is not loaded")
+
+	bound, err = BindLookupTables(ctx, expr, tables)
+	require.NoError(t, err)
+	require.Equal(t, []string{"0f3a0f3a0f3a0f3a"}, versions)
+	require.Equal(t, expr.String(), bound.String())
+	_, err = expr.Pipeline()
+	require.Error(t, err, "the original expression is not modified")
+
+	p, err := bound.Pipeline()
+	require.NoError(t, err)
+	_, lbs, matches := p.ForStream(labelBar).Process(0, []byte("customer_id=2 msg=hello"), labels.EmptyLabels())
+	require.True(t, matches)
+	require.Equal(t, labels.FromStrings("app", "bar", "customer_id", "2", "msg", "hello", "tier", "silver"), lbs.Labels())
+
+	expr, err = ParseLogSelector(`{app="foo"} | lookup teams on team`, true)
+	require.NoError(t, err)
+	_, err = BindLookupTables(ctx, expr, tables)
+	require.EqualError(t, err, "lookup table teams not found")
+}
+
+type redactionLimitsFunc func(tenantID string) (*log.Redactor, map[string]string, error)
+
+func (f redactionLimitsFunc) QueryRedaction(tenantID string) (*log.Redactor, map[string]string, error) {
+	return f(tenantID)
+}
+
+func TestBindRedaction(t *testing.T) {
+	patterns := map[string]string{"ticket": `TICKET-\d+`}
+	ingestRedactor, err := log.NewRedactor([]string{"email"}, patterns)
+	require.NoError(t, err)
+	limits := redactionLimitsFunc(func(tenantID string) (*log.Redactor, map[string]string, error) {
+		switch tenantID {
+		case "redacted":
+			return ingestRedactor, patterns, nil
+		case "patterns-only":
+			return nil, patterns, nil
+		case "invalid":
+			return nil, nil, errors.New("invalid redaction config")
+		}
+		return nil, nil, nil
+	})
+	const line = "user=foo@bar.com ticket=TICKET-42"
+
+	process := func(t *testing.T, expr LogSelectorExpr) string {
+		p, err := expr.Pipeline()
+		require.NoError(t, err)
+		out, _, matches := p.ForStream(labelBar).Process(0, []byte(line), labels.EmptyLabels())
+		require.True(t, matches)
+		return string(out)
+	}
+
+	for _, tc := range []struct {
+		name, tenant, query string
+		unchanged           bool
+		expected            string
+	}{
+		{name: "no redaction", tenant: "other", query: `{app="foo"} | json`, unchanged: true, expected: line},
+		{name: "builtin detectors", tenant: "other", query: `{app="foo"} | redact`, expected: "user=<redacted:email> ticket=TICKET-42"},
+		{name: "tenant patterns", tenant: "patterns-only", query: `{app="foo"} | redact`, expected: "user=<redacted:email> ticket=<redacted:ticket>"},
+		{name: "tenant patterns with detectors", tenant: "patterns-only", query: `{app="foo"} | redact credit_card`, expected: "user=foo@bar.com ticket=<redacted:ticket>"},
+		{name: "enforced on matchers", tenant: "redacted", query: `{app="foo"}`, expected: "user=<redacted:email> ticket=<redacted:ticket>"},
+		{name: "enforced on pipelines", tenant: "redacted", query: `{app="foo"} | line_format "{{__line__}}"`, expected: "user=<redacted:email> ticket=<redacted:ticket>"},
+		{name: "enforced for one of the tenants", tenant: "other|redacted", query: `{app="foo"}`, expected: "user=<redacted:email> ticket=<redacted:ticket>"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := user.InjectOrgID(context.Background(), tc.tenant)
+			expr, err := ParseLogSelector(tc.query, true)
+			require.NoError(t, err)
+
+			bound, err := BindRedaction(ctx, expr, limits)
+			require.NoError(t, err)
+			if tc.unchanged {
+				require.Same(t, expr, bound)
+			}
+			require.Equal(t, tc.expected, process(t, bound))
+		})
+	}
+
+	// Labels extracted from the line and structured metadata are redacted too.
+	ctx := user.InjectOrgID(context.Background(), "redacted")
+	expr, err := ParseLogSelector(`{app="foo"} | logfmt`, true)
+	require.NoError(t, err)
+	bound, err := BindRedaction(ctx, expr, limits)
+	require.NoError(t, err)
+	p, err := bound.Pipeline()
+	require.NoError(t, err)
+	_, lbs, matches := p.ForStream(labelBar).Process(0, []byte(line), labels.FromStrings("contact", "bar@foo.com"))
+	require.True(t, matches)
+	require.Equal(t, labels.FromStrings("app", "bar", "contact", "<redacted:email>", "ticket", "<redacted:ticket>", "user", "<redacted:email>"), lbs.Labels())
+
+	// The series of metric queries are redacted.
+	sample, err := ParseSampleExpr(`sum by (user) (count_over_time({app="foo"} | logfmt [1m]))`)
+	require.NoError(t, err)
+	boundSample, err := BindRedaction(ctx, sample, limits)
+	require.NoError(t, err)
+	require.NotSame(t, sample, boundSample)
+	extractors, err := boundSample.Extractors()
+	require.NoError(t, err)
+	require.Len(t, extractors, 1)
+	samples, ok := extractors[0].ForStream(labelBar).Process(0, []byte(line), labels.EmptyLabels())
+	require.True(t, ok)
+	require.Len(t, samples, 1)
+	require.Equal(t, labels.FromStrings("user", "<redacted:email>"), samples[0].Labels.Labels())
+
+	_, err = BindRedaction(context.Background(), sample, limits)
+	require.Error(t, err, "the tenant is required")
+
+	// Invalid redaction configs fail the queries of the tenant.
+	_, err = BindRedaction(user.InjectOrgID(context.Background(), "other|invalid"), sample, limits)
+	require.EqualError(t, err, "invalid redaction config")
+}
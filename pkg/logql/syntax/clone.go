@@ -1,6 +1,8 @@
 package syntax
 
 import (
+	"slices"
+
 	"github.com/prometheus/prometheus/model/labels"
 
 	"github.com/grafana/loki/v3/pkg/logql/log"
@@ -55,6 +57,9 @@ func (v *cloneVisitor) VisitVectorAggregation(e *VectorAggregationExpr) {
 		Left:      MustClone[SampleExpr](e.Left),
 		Params:    e.Params,
 		Operation: e.Operation,
+		Quantile:  e.Quantile,
+		Ratio:     e.Ratio,
+		Label:     e.Label,
 	}
 
 	if e.Grouping != nil {
@@ -79,6 +84,34 @@ func (v *cloneVisitor) VisitRangeAggregation(e *RangeAggregationExpr) {
 		copied.Params = &tmp
 	}
 
+	if e.SecondParam != nil {
+		tmp := *e.SecondParam
+		copied.SecondParam = &tmp
+	}
+
+	v.cloned = copied
+}
+
+func (v *cloneVisitor) VisitSubqueryAggregation(e *SubqueryAggregationExpr) {
+	copied := &SubqueryAggregationExpr{
+		Left:      MustClone[*SubqueryExpr](e.Left),
+		Operation: e.Operation,
+	}
+
+	if e.Grouping != nil {
+		copied.Grouping = cloneGrouping(e.Grouping)
+	}
+
+	if e.Params != nil {
+		tmp := *e.Params
+		copied.Params = &tmp
+	}
+
+	if e.SecondParam != nil {
+		tmp := *e.SecondParam
+		copied.SecondParam = &tmp
+	}
+
 	v.cloned = copied
 }
 
@@ -87,6 +120,25 @@ func (v *cloneVisitor) VisitLabelReplace(e *LabelReplaceExpr) {
 	v.cloned = mustNewLabelReplaceExpr(left, e.Dst, e.Replacement, e.Src, e.Regex)
 }
 
+func (v *cloneVisitor) VisitLabelJoin(e *LabelJoinExpr) {
+	left := MustClone[SampleExpr](e.Left)
+	v.cloned = mustNewLabelJoinExpr(left, e.Dst, e.Separator, slices.Clone(e.Src))
+}
+
+func (v *cloneVisitor) VisitFunction(e *FunctionExpr) {
+	copied := &FunctionExpr{
+		Function: e.Function,
+	}
+	if e.Left != nil {
+		copied.Left = MustClone[SampleExpr](e.Left)
+	}
+	if e.Param != nil {
+		tmp := *e.Param
+		copied.Param = &tmp
+	}
+	v.cloned = copied
+}
+
 func (v *cloneVisitor) VisitLiteral(e *LiteralExpr) {
 	v.cloned = &LiteralExpr{Val: e.Val}
 }
@@ -117,6 +169,15 @@ func (v *cloneVisitor) VisitLogRange(e *LogRangeExpr) {
 	v.cloned = copied
 }
 
+func (v *cloneVisitor) VisitSubquery(e *SubqueryExpr) {
+	v.cloned = &SubqueryExpr{
+		Left:   MustClone[SampleExpr](e.Left),
+		Range:  e.Range,
+		Step:   e.Step,
+		Offset: e.Offset,
+	}
+}
+
 func (v *cloneVisitor) VisitMatchers(e *MatchersExpr) {
 	copied := &MatchersExpr{
 		Mts: make([]*labels.Matcher, len(e.Mts)),
@@ -144,6 +205,26 @@ func (v *cloneVisitor) VisitDecolorize(*DecolorizeExpr) {
 	v.cloned = &DecolorizeExpr{}
 }
 
+func (v *cloneVisitor) VisitRedact(e *RedactExpr) {
+	v.cloned = &RedactExpr{Detectors: slices.Clone(e.Detectors), redactor: e.redactor}
+}
+
+func (v *cloneVisitor) VisitSampling(e *SamplingExpr) {
+	v.cloned = &SamplingExpr{Ratio: e.Ratio, Mode: e.Mode}
+}
+
+func (v *cloneVisitor) VisitContext(e *ContextExpr) {
+	v.cloned = &ContextExpr{Before: e.Before, After: e.After}
+}
+
+func (v *cloneVisitor) VisitLookup(e *LookupExpr) {
+	v.cloned = &LookupExpr{Table: e.Table, Key: e.Key, Version: e.Version, table: e.table}
+}
+
+func (v *cloneVisitor) VisitDedup(e *DedupExpr) {
+	v.cloned = &DedupExpr{Labels: slices.Clone(e.Labels)}
+}
+
 func (v *cloneVisitor) VisitDropLabels(e *DropLabelsExpr) {
 	copied := &DropLabelsExpr{
 		dropLabels: make([]log.NamedLabelMatcher, len(e.dropLabels)),
@@ -294,6 +375,19 @@ func (v *cloneVisitor) VisitLogfmtExpressionParser(e *LogfmtExpressionParserExpr
 	v.cloned = copied
 }
 
+func (v *cloneVisitor) VisitXMLExpressionParser(e *XMLExpressionParserExpr) {
+	copied := &XMLExpressionParserExpr{
+		Expressions: make([]log.LabelExtractionExpr, len(e.Expressions)),
+	}
+	copy(copied.Expressions, e.Expressions)
+
+	v.cloned = copied
+}
+
+func (v *cloneVisitor) VisitCSVParser(e *CSVParserExpr) {
+	v.cloned = &CSVParserExpr{Columns: slices.Clone(e.Columns)}
+}
+
 func (v *cloneVisitor) VisitLogfmtParser(e *LogfmtParserExpr) {
 	v.cloned = &LogfmtParserExpr{
 		Strict:    e.Strict,
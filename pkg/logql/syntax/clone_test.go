@@ -23,6 +23,36 @@ func TestClone(t *testing.T) {
 		"simple aggregation with unwrap": {
 			query: `sum_over_time({env="prod", app=~"loki.*"} | unwrap bytes[5m])`,
 		},
+		"subquery": {
+			query: `max_over_time(sum by (app) (rate({env="prod"}[1m]))[1h:5m] offset 10m) by (app)`,
+		},
+		"context stage": {
+			query: `{env="prod"} |= "panic" | context before=20 after=5`,
+		},
+		"dedup stage": {
+			query: `sum by (app) (count_over_time({env="prod"} | dedup agent_replica, pod [5m]))`,
+		},
+		"lookup stage": {
+			query: `sum by (tier) (count_over_time({env="prod"} | json | lookup customers on customer_id version "0f3a0f3a0f3a0f3a" [5m]))`,
+		},
+		"xml and csv parsers": {
+			query: `{env="prod"} | xml | xml level="Event/System/Level", id="Event/@id" | csv | csv "ts", "", "status"`,
+		},
+		"count values": {
+			query: `count_values("value", rate({env="prod"}[5m])) by (app)`,
+		},
+		"limit ratio": {
+			query: `limit_ratio(0.5, quantile(0.9, rate({env="prod"}[5m])) by (app))`,
+		},
+		"trend functions": {
+			query: `holt_winters({env="prod"} | unwrap bar [30m], 0.5, 0.1) by (app)`,
+		},
+		"functions": {
+			query: `round(hour(timestamp(rate({env="prod"}[1m]))), 0.5) < hour()`,
+		},
+		"label join": {
+			query: `label_join(rate({env="prod"}[1m]), "dst", "-", "env", "pod")`,
+		},
 		"bin op": {
 			query: `(count_over_time({env="prod", app=~"loki.*"}[5m]) >= 0)`,
 		},
@@ -60,19 +60,9 @@ func (r RangeAggregationExpr) extractor(override *Grouping) (log.SampleExtractor
 	}
 	// unwrap...means we want to extract metrics from labels.
 	if r.Left.Unwrap != nil {
-		var convOp string
-		switch r.Left.Unwrap.Operation {
-		case OpConvBytes:
-			convOp = log.ConvertBytes
-		case OpConvDuration, OpConvDurationSeconds:
-			convOp = log.ConvertDuration
-		default:
-			convOp = log.ConvertFloat
-		}
-
 		return log.LabelExtractorWithStages(
 			r.Left.Unwrap.Identifier,
-			convOp, groups, without, noLabels, stages,
+			unwrapConversion(r.Operation, r.Left.Unwrap), groups, without, noLabels, stages,
 			log.ReduceAndLabelFilter(r.Left.Unwrap.PostFilters),
 		)
 	}
@@ -87,6 +77,24 @@ func (r RangeAggregationExpr) extractor(override *Grouping) (log.SampleExtractor
 	}
 }
 
+// unwrapConversion returns the conversion of the values of an unwrapped label.
+// Distinct counts hash values without a conversion function, so that any
+// value can be counted, not only numbers.
+func unwrapConversion(operation string, unwrap *UnwrapExpr) string {
+	switch unwrap.Operation {
+	case OpConvBytes:
+		return log.ConvertBytes
+	case OpConvDuration, OpConvDurationSeconds:
+		return log.ConvertDuration
+	}
+	switch operation {
+	case OpRangeTypeApproxCountDistinct, OpRangeTypeHyperLogLog:
+		return log.ConvertHash
+	default:
+		return log.ConvertFloat
+	}
+}
+
 func (m *MultiVariantExpr) Extractors() ([]log.SampleExtractor, error) {
 	if m.err != nil {
 		return nil, m.err
@@ -164,22 +172,11 @@ func variantRangeAggExprExtractor(rangeAgg *RangeAggregationExpr) (log.SampleExt
 
 		sort.Strings(groups)
 
-		// Determine conversion operation
-		var convOp string
-		switch rangeAgg.Left.Unwrap.Operation {
-		case OpConvBytes:
-			convOp = log.ConvertBytes
-		case OpConvDuration, OpConvDurationSeconds:
-			convOp = log.ConvertDuration
-		default:
-			convOp = log.ConvertFloat
-		}
-
 		// Create label extractor without the common pipeline stages
 		// The common pipeline will be applied separately
 		return log.LabelExtractorWithStages(
 			rangeAgg.Left.Unwrap.Identifier,
-			convOp,
+			unwrapConversion(rangeAgg.Operation, rangeAgg.Left.Unwrap),
 			groups,
 			without,
 			noLabels,
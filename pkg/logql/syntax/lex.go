@@ -66,6 +66,8 @@ var tokens = map[string]int{
 	OpParserTypeLogfmt:  LOGFMT,
 	OpParserTypeUnpack:  UNPACK,
 	OpParserTypePattern: PATTERN,
+	OpParserTypeXML:     XML,
+	OpParserTypeCSV:     CSV,
 
 	// fmt
 	OpFmtLabel: LABEL_FMT,
@@ -81,6 +83,21 @@ var tokens = map[string]int{
 	// keep labels
 	OpKeep: KEEP,
 
+	// redact
+	OpRedact: REDACT,
+
+	// sample
+	OpSample: SAMPLE,
+
+	// context
+	OpContext: CONTEXT,
+
+	// lookup
+	OpLookup: LOOKUP,
+
+	// dedup
+	OpDedup: DEDUP,
+
 	// variants
 	OpVariants: VARIANTS,
 	VariantsOf: OF,
@@ -111,6 +128,11 @@ var functionTokens = map[string]int{
 	OpRangeTypeAbsent:      ABSENT_OVER_TIME,
 	OpTypeVector:           VECTOR,
 
+	OpRangeTypeChanges:       CHANGES,
+	OpRangeTypeDeriv:         DERIV,
+	OpRangeTypePredictLinear: PREDICT_LINEAR,
+	OpRangeTypeHoltWinters:   HOLT_WINTERS,
+
 	// vec ops
 	OpTypeSum:      SUM,
 	OpTypeAvg:      AVG,
@@ -123,9 +145,36 @@ var functionTokens = map[string]int{
 	OpTypeTopK:     TOPK,
 	OpTypeSort:     SORT,
 	OpTypeSortDesc: SORT_DESC,
-	OpLabelReplace: LABEL_REPLACE,
 
-	OpTypeApproxTopK: APPROX_TOPK,
+	OpTypeCountValues: COUNT_VALUES,
+	OpTypeQuantile:    QUANTILE,
+	OpTypeGroup:       GROUP,
+	OpTypeLimitK:      LIMITK,
+	OpTypeLimitRatio:  LIMIT_RATIO,
+	OpLabelReplace:    LABEL_REPLACE,
+
+	OpTypeApproxTopK:               APPROX_TOPK,
+	OpTypeApproxCountDistinct:      APPROX_COUNT_DISTINCT,
+	OpRangeTypeApproxCountDistinct: APPROX_COUNT_DISTINCT_OVER_TIME,
+
+	OpRangeTypeHistogram:    HISTOGRAM_OVER_TIME,
+	OpTypeHistogramQuantile: HISTOGRAM_QUANTILE,
+
+	OpLabelJoin: LABEL_JOIN,
+
+	// functions
+	OpFuncAbs:       ABS,
+	OpFuncCeil:      CEIL,
+	OpFuncFloor:     FLOOR,
+	OpFuncRound:     ROUND,
+	OpFuncClampMin:  CLAMP_MIN,
+	OpFuncClampMax:  CLAMP_MAX,
+	OpFuncSqrt:      SQRT,
+	OpFuncLn:        LN,
+	OpFuncExp:       EXP,
+	OpFuncTimestamp: TIMESTAMP,
+	OpFuncHour:      HOUR,
+	OpFuncDayOfWeek: DAY_OF_WEEK,
 
 	// conversion Op
 	OpConvBytes:           BYTES_CONV,
@@ -208,6 +257,10 @@ func (l *lexer) Lex(lval *syntaxSymType) int {
 		l.builder.Reset()
 		for r := l.Next(); r != scanner.EOF; r = l.Next() {
 			if r == ']' {
+				// subqueries have a step after the range, such as `[1h:1m]`.
+				if rng, step, ok := strings.Cut(l.builder.String(), ":"); ok {
+					return l.subqueryRange(lval, rng, step)
+				}
 				i, err := model.ParseDuration(l.builder.String())
 				if err != nil {
 					l.Error(err.Error())
@@ -257,6 +310,25 @@ func (l *lexer) Lex(lval *syntaxSymType) int {
 	return IDENTIFIER
 }
 
+// subqueryRange scans the range and the optional step of a subquery.
+func (l *lexer) subqueryRange(lval *syntaxSymType, rng, step string) int {
+	r, err := model.ParseDuration(rng)
+	if err != nil {
+		l.Error(err.Error())
+		return 0
+	}
+	lval.subqueryRange = subqueryRange{Range: time.Duration(r)}
+	if step != "" {
+		s, err := model.ParseDuration(step)
+		if err != nil {
+			l.Error(err.Error())
+			return 0
+		}
+		lval.subqueryRange.Step = time.Duration(s)
+	}
+	return SUBQUERY_RANGE
+}
+
 func (l *lexer) Error(msg string) {
 	l.errs = append(l.errs, logqlmodel.NewParseError(msg, l.Line, l.Column))
 }
@@ -117,6 +117,9 @@ func validateExpr(expr Expr) error {
 	case SampleExpr:
 		return validateSampleExpr(e)
 	case LogSelectorExpr:
+		if err := validateContextStage(e); err != nil {
+			return err
+		}
 		return validateLogSelectorExpression(e)
 	case VariantsExpr:
 		return validateVariantsExpr(e)
@@ -126,6 +129,9 @@ func validateExpr(expr Expr) error {
 }
 
 func validateVariantsExpr(e VariantsExpr) error {
+	if HasContextStage(e.LogRange().Left) {
+		return logqlmodel.NewParseError("context stage is only allowed in log queries", 0, 0)
+	}
 	err := validateLogSelectorExpression(e.LogRange().Left)
 	if err != nil {
 		return err
@@ -227,15 +233,52 @@ func validateSampleExpr(expr SampleExpr) error {
 			return e.err
 		}
 		return validateSampleExpr(e.Left)
+	case *SubqueryAggregationExpr:
+		if e.err != nil {
+			return e.err
+		}
+		return validateSampleExpr(e.Left.Left)
+	case *LabelJoinExpr:
+		if e.err != nil {
+			return e.err
+		}
+		return validateSampleExpr(e.Left)
+	case *FunctionExpr:
+		if e.err != nil {
+			return e.err
+		}
+		if e.Left == nil {
+			return nil
+		}
+		return validateSampleExpr(e.Left)
 	default:
 		selector, err := e.Selector()
 		if err != nil {
 			return err
 		}
+		if HasContextStage(selector) {
+			return logqlmodel.NewParseError("context stage is only allowed in log queries", 0, 0)
+		}
 		return validateLogSelectorExpression(selector)
 	}
 }
 
+// validateContextStage checks that the context stage, if any, is the last stage
+// of the pipeline, as the stages before it select the entries to show the
+// context of.
+func validateContextStage(expr LogSelectorExpr) error {
+	p, ok := expr.(*PipelineExpr)
+	if !ok {
+		return nil
+	}
+	for i, s := range p.MultiStages {
+		if _, ok := s.(*ContextExpr); ok && i != len(p.MultiStages)-1 {
+			return logqlmodel.NewParseError("context stage must be the last stage of the pipeline", 0, 0)
+		}
+	}
+	return nil
+}
+
 func validateLogSelectorExpression(expr LogSelectorExpr) error {
 	switch e := expr.(type) {
 	case *VectorExpr:
@@ -50,6 +50,153 @@ var ParseTestCases = []struct {
 			},
 		),
 	},
+	{
+		in: `{ foo = "bar" } | redact`,
+		exp: newPipelineExpr(
+			newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}),
+			MultiStageExpr{
+				newRedactExpr(nil),
+			},
+		),
+	},
+	{
+		in: `{ foo = "bar" } | redact email, credit_card | json`,
+		exp: newPipelineExpr(
+			newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}),
+			MultiStageExpr{
+				newRedactExpr([]string{"email", "credit_card"}),
+				newLabelParserExpr(OpParserTypeJSON, ""),
+			},
+		),
+	},
+	{
+		in:  `{ foo = "bar" } | redact phone_number`,
+		err: logqlmodel.NewParseError(`unknown redaction detector "phone_number", supported: [aws_key credit_card email jwt]`, 0, 0),
+	},
+	{
+		in: `{ foo = "bar" } | sample 0.01 | json`,
+		exp: newPipelineExpr(
+			newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}),
+			MultiStageExpr{
+				&SamplingExpr{Ratio: 0.01, Mode: log.SampleEntries},
+				newLabelParserExpr(OpParserTypeJSON, ""),
+			},
+		),
+	},
+	{
+		in: `{ foo = "bar" } | sample 0.25 by stream`,
+		exp: newPipelineExpr(
+			newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}),
+			MultiStageExpr{
+				&SamplingExpr{Ratio: 0.25, Mode: log.SampleStreams},
+			},
+		),
+	},
+	{
+		in:  `{ foo = "bar" } | sample 2`,
+		err: logqlmodel.NewParseError(`invalid sampling ratio 2, must be in (0, 1]`, 0, 0),
+	},
+	{
+		in:  `{ foo = "bar" } | sample 0.1 by chunk`,
+		err: logqlmodel.NewParseError(`invalid sampling mode "chunk", expected "entry" or "stream"`, 0, 0),
+	},
+	{
+		in: `{ foo = "bar" } |= "panic" | context before=20 after=5`,
+		exp: newPipelineExpr(
+			newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}),
+			MultiStageExpr{
+				newLineFilterExpr(log.LineMatchEqual, "", "panic"),
+				&ContextExpr{Before: 20, After: 5},
+			},
+		),
+	},
+	{
+		in: `{ foo = "bar" } | json | level="error" | context after=3`,
+		exp: newPipelineExpr(
+			newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}),
+			MultiStageExpr{
+				newLabelParserExpr(OpParserTypeJSON, ""),
+				&LabelFilterExpr{LabelFilterer: log.NewStringLabelFilter(mustNewMatcher(labels.MatchEqual, "level", "error"))},
+				&ContextExpr{After: 3},
+			},
+		),
+	},
+	{
+		in:  `{ foo = "bar" } | context around=3`,
+		err: logqlmodel.NewParseError(`invalid context option around, expected before or after`, 0, 0),
+	},
+	{
+		in:  `{ foo = "bar" } | context before=3 before=4`,
+		err: logqlmodel.NewParseError(`duplicate context option before`, 0, 0),
+	},
+	{
+		in:  `{ foo = "bar" } | context before=1.5`,
+		err: logqlmodel.NewParseError(`invalid context option before=1.5, expected a non-negative number of lines`, 0, 0),
+	},
+	{
+		in:  `{ foo = "bar" } |= "panic" | context before=3 | json`,
+		err: logqlmodel.NewParseError(`context stage must be the last stage of the pipeline`, 0, 0),
+	},
+	{
+		in:  `count_over_time({ foo = "bar" } |= "panic" | context before=3 [5m])`,
+		err: logqlmodel.NewParseError(`context stage is only allowed in log queries`, 0, 0),
+	},
+	{
+		in: `{ foo = "bar" } | json | lookup customers on customer_id`,
+		exp: newPipelineExpr(
+			newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}),
+			MultiStageExpr{
+				newLabelParserExpr(OpParserTypeJSON, ""),
+				&LookupExpr{Table: "customers", Key: "customer_id"},
+			},
+		),
+	},
+	{
+		in: `{ foo = "bar" } | lookup customers on customer_id version "0f3a0f3a0f3a0f3a" | tier="gold"`,
+		exp: newPipelineExpr(
+			newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}),
+			MultiStageExpr{
+				&LookupExpr{Table: "customers", Key: "customer_id", Version: "0f3a0f3a0f3a0f3a"},
+				&LabelFilterExpr{LabelFilterer: log.NewStringLabelFilter(mustNewMatcher(labels.MatchEqual, "tier", "gold"))},
+			},
+		),
+	},
+	{
+		in:  `{ foo = "bar" } | lookup customers on customer_id revision "0f3a0f3a0f3a0f3a"`,
+		err: logqlmodel.NewParseError(`invalid lookup option revision, expected version`, 0, 0),
+	},
+	{
+		in:  `{ foo = "bar" } | lookup customers on customer_id version ""`,
+		err: logqlmodel.NewParseError(`invalid lookup table version "", expected 16 lowercase hexadecimal characters`, 0, 0),
+	},
+	{
+		in:  `{ foo = "bar" } | lookup customers on customer_id version "../../tenant-b/customers/0f3a0f3a0f3a0f3a"`,
+		err: logqlmodel.NewParseError(`invalid lookup table version "../../tenant-b/customers/0f3a0f3a0f3a0f3a", expected 16 lowercase hexadecimal characters`, 0, 0),
+	},
+	{
+		in: `{ foo = "bar" } | dedup agent_replica`,
+		exp: newPipelineExpr(
+			newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}),
+			MultiStageExpr{&DedupExpr{Labels: []string{"agent_replica"}}},
+		),
+	},
+	{
+		in: `count_over_time({ foo = "bar" } |= "error" | dedup agent_replica, pod [5m])`,
+		exp: newRangeAggregationExpr(
+			newLogRange(newPipelineExpr(
+				newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}),
+				MultiStageExpr{
+					newLineFilterExpr(log.LineMatchEqual, "", "error"),
+					&DedupExpr{Labels: []string{"agent_replica", "pod"}},
+				},
+			), 5*time.Minute, nil, nil),
+			OpRangeTypeCount, nil, nil,
+		),
+	},
+	{
+		in:  `{ foo = "bar" } | dedup`,
+		err: logqlmodel.NewParseError("syntax error: unexpected $end, expecting IDENTIFIER", 1, 24),
+	},
 	{
 		// test [12h] before filter expr
 		in: `count_over_time({foo="bar"}[12h] |= "error")`,
@@ -235,6 +382,62 @@ var ParseTestCases = []struct {
 			Operation: "rate",
 		}, "sum", nil, nil),
 	},
+	{
+		in: `count_values("value", count_over_time({ foo = "bar" }[5h])) by (bar)`,
+		exp: mustNewCountValuesExpr(&RangeAggregationExpr{
+			Left: &LogRangeExpr{
+				Left:     &MatchersExpr{Mts: []*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}},
+				Interval: 5 * time.Hour,
+			},
+			Operation: "count_over_time",
+		}, "count_values", &Grouping{
+			Groups: []string{"bar"},
+		}, "value"),
+	},
+	{
+		in: `quantile(0.9, count_over_time({ foo = "bar" }[5h])) by (bar)`,
+		exp: mustNewVectorAggregationExpr(&RangeAggregationExpr{
+			Left: &LogRangeExpr{
+				Left:     &MatchersExpr{Mts: []*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}},
+				Interval: 5 * time.Hour,
+			},
+			Operation: "count_over_time",
+		}, "quantile", &Grouping{
+			Groups: []string{"bar"},
+		}, NewStringLabelFilter("0.9")),
+	},
+	{
+		in: `group by (bar) (count_over_time({ foo = "bar" }[5h]))`,
+		exp: mustNewVectorAggregationExpr(&RangeAggregationExpr{
+			Left: &LogRangeExpr{
+				Left:     &MatchersExpr{Mts: []*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}},
+				Interval: 5 * time.Hour,
+			},
+			Operation: "count_over_time",
+		}, "group", &Grouping{
+			Groups: []string{"bar"},
+		}, nil),
+	},
+	{
+		in: `limitk(2, count_over_time({ foo = "bar" }[5h]))`,
+		exp: mustNewVectorAggregationExpr(&RangeAggregationExpr{
+			Left: &LogRangeExpr{
+				Left:     &MatchersExpr{Mts: []*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}},
+				Interval: 5 * time.Hour,
+			},
+			Operation: "count_over_time",
+		}, "limitk", nil, NewStringLabelFilter("2")),
+	},
+	{
+		in: `limit_ratio(-0.5, count_over_time({ foo = "bar" }[5h]))`,
+		exp: mustNewVectorAggregationExpr(&RangeAggregationExpr{
+			Left: &LogRangeExpr{
+				Left:     &MatchersExpr{Mts: []*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}},
+				Interval: 5 * time.Hour,
+			},
+			Operation: "count_over_time",
+		}, "limit_ratio", nil, NewStringLabelFilter("-0.5")),
+	},
 	{
 		in: `avg(count_over_time({ foo = "bar" }[5h])) by (bar,foo)`,
 		exp: mustNewVectorAggregationExpr(&RangeAggregationExpr{
@@ -368,6 +571,50 @@ var ParseTestCases = []struct {
 		in:  `approx_topk(2, count_over_time({ foo = "bar" }[5h])) by (foo)`,
 		err: logqlmodel.NewParseError("grouping not allowed for approx_topk aggregation", 0, 0),
 	},
+	{
+		in:  `histogram_quantile(0.99, histogram_over_time({ foo = "bar" } | unwrap latency [5h])) by (foo)`,
+		err: logqlmodel.NewParseError("grouping not allowed for histogram_quantile aggregation", 0, 0),
+	},
+	{
+		in:  `histogram_quantile(histogram_over_time({ foo = "bar" } | unwrap latency [5h]))`,
+		err: logqlmodel.NewParseError("parameter required for operation histogram_quantile", 0, 0),
+	},
+	{
+		in:  `quantile(count_over_time({ foo = "bar" }[5h]))`,
+		err: logqlmodel.NewParseError("parameter required for operation quantile", 0, 0),
+	},
+	{
+		in:  `limit_ratio(2, count_over_time({ foo = "bar" }[5h]))`,
+		err: logqlmodel.NewParseError("invalid parameter (must be between -1 and 1) limit_ratio(2,", 0, 0),
+	},
+	{
+		in:  `count_values(5, count_over_time({ foo = "bar" }[5h]))`,
+		err: logqlmodel.NewParseError("invalid parameter count_values(5, expected a label name", 0, 0),
+	},
+	{
+		in:  `count_values("", count_over_time({ foo = "bar" }[5h]))`,
+		err: logqlmodel.NewParseError("invalid label name in count_values: ", 0, 0),
+	},
+	{
+		in:  `sum("foo", count_over_time({ foo = "bar" }[5h]))`,
+		err: logqlmodel.NewParseError("unsupported parameter for operation sum(\"foo\",", 0, 0),
+	},
+	{
+		in:  `approx_count_distinct_over_time({ foo = "bar" }[5h]) by (foo)`,
+		err: logqlmodel.NewParseError("invalid aggregation approx_count_distinct_over_time without unwrap", 0, 0),
+	},
+	{
+		in:  `rate(sum(rate({ foo = "bar" }[5m]))[1h:1m])`,
+		err: logqlmodel.NewParseError("invalid aggregation rate over subquery", 0, 0),
+	},
+	{
+		in:  `sum_over_time(sum(rate({ foo = "bar" }[5m]))[1h:1m]) by (foo)`,
+		err: logqlmodel.NewParseError("grouping not allowed for sum_over_time aggregation", 0, 0),
+	},
+	{
+		in:  `quantile_over_time(sum(rate({ foo = "bar" }[5m]))[1h:1m])`,
+		err: logqlmodel.NewParseError("parameter required for operation quantile_over_time", 0, 0),
+	},
 	{
 		in:  `rate({ foo = "bar" }[5minutes])`,
 		err: logqlmodel.NewParseError(`unknown unit "minutes" in duration "5minutes"`, 0, 21),
@@ -384,6 +631,50 @@ var ParseTestCases = []struct {
 		in:  `rate({ foo = "bar" }[5)`,
 		err: logqlmodel.NewParseError("missing closing ']' in duration", 0, 21),
 	},
+	{
+		in:  `deriv({ foo = "bar" }[5m])`,
+		err: logqlmodel.NewParseError("invalid aggregation deriv without unwrap", 0, 0),
+	},
+	{
+		in:  `predict_linear({ foo = "bar" } | unwrap bar [5m])`,
+		err: logqlmodel.NewParseError("parameter required for operation predict_linear", 0, 0),
+	},
+	{
+		in:  `predict_linear(3600, { foo = "bar" } | unwrap bar [5m])`,
+		err: logqlmodel.NewParseError("parameter 3600 not supported for operation predict_linear", 0, 0),
+	},
+	{
+		in:  `changes({ foo = "bar" } | unwrap bar [5m], 2)`,
+		err: logqlmodel.NewParseError("parameter 2 not supported for operation changes", 0, 0),
+	},
+	{
+		in:  `holt_winters({ foo = "bar" } | unwrap bar [5m], 0.5)`,
+		err: logqlmodel.NewParseError("expected 2 parameters for operation holt_winters, got 1", 0, 0),
+	},
+	{
+		in:  `holt_winters({ foo = "bar" } | unwrap bar [5m], 1, 0.5)`,
+		err: logqlmodel.NewParseError("invalid smoothing factor for operation holt_winters, expected 0 < sf < 1: 1", 0, 0),
+	},
+	{
+		in:  `abs(5)`,
+		err: logqlmodel.NewParseError("expected type instant vector in call to function abs, got scalar", 0, 0),
+	},
+	{
+		in:  `clamp_min(rate({ foo = "bar" }[5m]))`,
+		err: logqlmodel.NewParseError("parameter required for function clamp_min", 0, 0),
+	},
+	{
+		in:  `ceil(rate({ foo = "bar" }[5m]), 2)`,
+		err: logqlmodel.NewParseError("invalid parameter for function ceil", 0, 0),
+	},
+	{
+		in:  `timestamp()`,
+		err: logqlmodel.NewParseError("argument required for function timestamp", 0, 0),
+	},
+	{
+		in:  `label_join(rate({ foo = "bar" }[5m]), "", ",", "a")`,
+		err: logqlmodel.NewParseError("invalid destination label name in label_join: ", 0, 0),
+	},
 	{
 		in:  `min({ foo = "bar" }[5m])`,
 		err: logqlmodel.NewParseError("syntax error: unexpected RANGE", 0, 20),
@@ -1866,6 +2157,148 @@ var ParseTestCases = []struct {
 			OpRangeTypeMin, &Grouping{}, nil,
 		),
 	},
+	{
+		in: `approx_count_distinct_over_time({app="foo"} | unwrap bar [5m]) by (foo)`,
+		exp: newRangeAggregationExpr(
+			newLogRange(
+				newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+				5*time.Minute,
+				newUnwrapExpr("bar", ""),
+				nil),
+			OpRangeTypeApproxCountDistinct, &Grouping{Groups: []string{"foo"}}, nil,
+		),
+	},
+	{
+		in: `histogram_quantile(0.99, histogram_over_time({app="foo"} | unwrap bar [5m]) by (foo))`,
+		exp: mustNewVectorAggregationExpr(
+			newRangeAggregationExpr(
+				newLogRange(
+					newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+					5*time.Minute,
+					newUnwrapExpr("bar", ""),
+					nil),
+				OpRangeTypeHistogram, &Grouping{Groups: []string{"foo"}}, nil,
+			),
+			OpTypeHistogramQuantile, nil, NewStringLabelFilter("0.99"),
+		),
+	},
+	{
+		in: `max_over_time(sum by (foo) (rate({app="foo"}[5m]))[1h:1m])`,
+		exp: newSubqueryAggregationExpr(
+			newSubqueryExpr(
+				mustNewVectorAggregationExpr(
+					newRangeAggregationExpr(
+						newLogRange(newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}), 5*time.Minute, nil, nil),
+						OpRangeTypeRate, nil, nil,
+					),
+					OpTypeSum, &Grouping{Groups: []string{"foo"}}, nil,
+				),
+				subqueryRange{Range: time.Hour, Step: time.Minute},
+				nil,
+			),
+			OpRangeTypeMax, nil, nil,
+		),
+	},
+	{
+		in: `predict_linear({app="foo"} | unwrap bar [30m], 3600) by (pod)`,
+		exp: newTrailingParamsRangeAggregationExpr(
+			newLogRange(newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}), 30*time.Minute, newUnwrapExpr("bar", ""), nil),
+			OpRangeTypePredictLinear, &Grouping{Groups: []string{"pod"}}, []string{"3600"},
+		),
+	},
+	{
+		in: `holt_winters(sum(rate({app="foo"}[5m]))[1h:], 0.5, 0.1)`,
+		exp: newTrailingParamsSubqueryAggregationExpr(
+			newSubqueryExpr(
+				mustNewVectorAggregationExpr(
+					newRangeAggregationExpr(
+						newLogRange(newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}), 5*time.Minute, nil, nil),
+						OpRangeTypeRate, nil, nil,
+					),
+					OpTypeSum, nil, nil,
+				),
+				subqueryRange{Range: time.Hour}, nil,
+			),
+			OpRangeTypeHoltWinters, nil, []string{"0.5", "0.1"},
+		),
+	},
+	{
+		in: `round(sum(rate({app="foo"}[5m])), -0.5)`,
+		exp: mustNewFunctionExpr(
+			mustNewVectorAggregationExpr(
+				newRangeAggregationExpr(
+					newLogRange(newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}), 5*time.Minute, nil, nil),
+					OpRangeTypeRate, nil, nil,
+				),
+				OpTypeSum, nil, nil,
+			),
+			OpFuncRound, mustNewLiteralExpr("0.5", true),
+		),
+	},
+	{
+		in: `abs(rate({app="foo"}[5m])) > hour()`,
+		exp: mustNewBinOpExpr(
+			OpTypeGT,
+			&BinOpOptions{VectorMatching: &VectorMatching{Card: CardOneToOne}},
+			mustNewFunctionExpr(
+				newRangeAggregationExpr(
+					newLogRange(newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}), 5*time.Minute, nil, nil),
+					OpRangeTypeRate, nil, nil,
+				),
+				OpFuncAbs, nil,
+			),
+			mustNewFunctionExpr(nil, OpFuncHour, nil),
+		),
+	},
+	{
+		in: `label_join(rate({app="foo"}[5m]), "foo", ",", "a", "b")`,
+		exp: mustNewLabelJoinExpr(
+			newRangeAggregationExpr(
+				newLogRange(newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}), 5*time.Minute, nil, nil),
+				OpRangeTypeRate, nil, nil,
+			),
+			"foo", ",", []string{"a", "b"},
+		),
+	},
+	{
+		in: `{app="foo"} | json | hour > 5 | timestamp="x"`,
+		exp: newPipelineExpr(
+			newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStageExpr{
+				newLabelParserExpr(OpParserTypeJSON, ""),
+				&LabelFilterExpr{LabelFilterer: log.NewNumericLabelFilter(log.LabelFilterGreaterThan, "hour", 5)},
+				&LabelFilterExpr{LabelFilterer: log.NewStringLabelFilter(mustNewMatcher(labels.MatchEqual, "timestamp", "x"))},
+			},
+		),
+	},
+	{
+		in: `quantile_over_time(0.99, rate({app="foo"}[5m])[1h:] offset 10m) by (foo)`,
+		exp: newSubqueryAggregationExpr(
+			newSubqueryExpr(
+				newRangeAggregationExpr(
+					newLogRange(newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}), 5*time.Minute, nil, nil),
+					OpRangeTypeRate, nil, nil,
+				),
+				subqueryRange{Range: time.Hour},
+				newOffsetExpr(10*time.Minute),
+			),
+			OpRangeTypeQuantile, &Grouping{Groups: []string{"foo"}}, NewStringLabelFilter("0.99"),
+		),
+	},
+	{
+		in: `approx_count_distinct by (foo) (count_over_time({app="foo"}[5m]))`,
+		exp: mustNewVectorAggregationExpr(
+			newRangeAggregationExpr(
+				newLogRange(
+					newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+					5*time.Minute,
+					nil,
+					nil),
+				OpRangeTypeCount, nil, nil,
+			),
+			OpTypeApproxCountDistinct, &Grouping{Groups: []string{"foo"}}, nil,
+		),
+	},
 	{
 		in: `max_over_time({app="foo"} | unwrap bar [5m]) without ()`,
 		exp: newRangeAggregationExpr(
@@ -2917,7 +3350,7 @@ var ParseTestCases = []struct {
 	},
 	{
 		in:  `quantile_over_time(foo,{namespace="tns"} |= "level=error" | json |foo>=5,bar<25ms| unwrap latency [5m])`,
-		err: logqlmodel.NewParseError("syntax error: unexpected IDENTIFIER, expecting NUMBER or { or (", 1, 20),
+		err: logqlmodel.NewParseError("syntax error: unexpected IDENTIFIER", 1, 20),
 	},
 	{
 		in:  `vector(abc)`,
@@ -3016,6 +3449,68 @@ var ParseTestCases = []struct {
 			},
 		},
 	},
+	{
+		in: `{app="foo"} | xml | Event_System_Level="2"`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				newLabelParserExpr(OpParserTypeXML, ""),
+				&LabelFilterExpr{
+					LabelFilterer: log.NewStringLabelFilter(mustNewMatcher(labels.MatchEqual, "Event_System_Level", "2")),
+				},
+			},
+		},
+	},
+	{
+		in: `{app="foo"} | xml level="/Event/System/Level", name="Event/EventData/Data[2]/@Name"`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				newXMLExpressionParser([]log.LabelExtractionExpr{
+					log.NewLabelExtractionExpr("level", `/Event/System/Level`),
+					log.NewLabelExtractionExpr("name", `Event/EventData/Data[2]/@Name`),
+				}),
+			},
+		},
+	},
+	{
+		in: `{app="foo"} | csv | column_2="failed"`,
+		exp: &PipelineExpr{
+			Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+			MultiStages: MultiStageExpr{
+				newCSVParserExpr(nil),
+				&LabelFilterExpr{
+					LabelFilterer: log.NewStringLabelFilter(mustNewMatcher(labels.MatchEqual, "column_2", "failed")),
+				},
+			},
+		},
+	},
+	{
+		in: `sum by (job) (count_over_time({app="foo"} | csv "ts", "", "job", "status" | status="failed" [5m]))`,
+		exp: mustNewVectorAggregationExpr(
+			newRangeAggregationExpr(
+				newLogRange(&PipelineExpr{
+					Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
+					MultiStages: MultiStageExpr{
+						newCSVParserExpr([]string{"ts", "", "job", "status"}),
+						&LabelFilterExpr{
+							LabelFilterer: log.NewStringLabelFilter(mustNewMatcher(labels.MatchEqual, "status", "failed")),
+						},
+					},
+				}, 5*time.Minute, nil, nil),
+				OpRangeTypeCount, nil, nil,
+			),
+			OpTypeSum, &Grouping{Groups: []string{"job"}}, nil,
+		),
+	},
+	{
+		in:  `{app="foo"} | csv "ts", "\xff"`,
+		err: logqlmodel.NewParseError("invalid csv parser: invalid column name '\xff'", 0, 0),
+	},
+	{
+		in:  `{app="foo"} | xml level="Event//Level"`,
+		err: logqlmodel.NewParseError("invalid xml expression: cannot parse expression [Event//Level]: empty element name", 0, 0),
+	},
 	{
 		in: `{app="foo"} | json bob="top.params[0]"`,
 		exp: &PipelineExpr{
@@ -136,6 +136,31 @@ func (e *DecolorizeExpr) Pretty(_ int) string {
 	return e.String()
 }
 
+// e.g: | redact email,jwt
+func (e *RedactExpr) Pretty(_ int) string {
+	return e.String()
+}
+
+// e.g: | sample 0.01 by stream
+func (e *SamplingExpr) Pretty(_ int) string {
+	return e.String()
+}
+
+// e.g: | context before=10 after=5
+func (e *ContextExpr) Pretty(_ int) string {
+	return e.String()
+}
+
+// e.g: | lookup customers on customer_id
+func (e *LookupExpr) Pretty(_ int) string {
+	return e.String()
+}
+
+// e.g: | dedup agent_replica
+func (e *DedupExpr) Pretty(_ int) string {
+	return e.String()
+}
+
 // e.g: | label_format dst="{{ .src }}"
 func (e *LabelFmtExpr) Pretty(level int) string {
 	return commonPrefixIndent(level, e)
@@ -151,6 +176,16 @@ func (e *LogfmtExpressionParserExpr) Pretty(level int) string {
 	return commonPrefixIndent(level, e)
 }
 
+// e.g: | xml label="expression", another="expression"
+func (e *XMLExpressionParserExpr) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
+// e.g: | csv "column", "another"
+func (e *CSVParserExpr) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
 // e.g: sum_over_time({foo="bar"} | logfmt | unwrap bytes_processed [5m])
 func (e *UnwrapExpr) Pretty(level int) string {
 	s := Indent(level)
@@ -210,12 +245,80 @@ func (e *RangeAggregationExpr) Pretty(level int) string {
 	s += "(\n"
 
 	// print args to the function.
-	if e.Params != nil {
-		s = fmt.Sprintf("%s%s%s,", s, Indent(level+1), fmt.Sprint(*e.Params))
-		s += "\n"
+	if hasTrailingParams(e.Operation) {
+		s += e.Left.Pretty(level + 1)
+		s += prettyTrailingParams(level+1, e.Params, e.SecondParam)
+	} else {
+		if e.Params != nil {
+			s = fmt.Sprintf("%s%s%s,", s, Indent(level+1), fmt.Sprint(*e.Params))
+			s += "\n"
+		}
+
+		s += e.Left.Pretty(level + 1)
 	}
 
-	s += e.Left.Pretty(level + 1)
+	s += "\n" + Indent(level) + ")"
+
+	if e.Grouping != nil {
+		s += e.Grouping.Pretty(level)
+	}
+
+	return s
+}
+
+// prettyTrailingParams prints the parameters following the range of
+// predict_linear and holt_winters, one per line.
+func prettyTrailingParams(level int, params ...*float64) string {
+	var s string
+	for _, p := range params {
+		if p == nil {
+			continue
+		}
+		s += fmt.Sprintf(",\n%s%s", Indent(level), fmt.Sprint(*p))
+	}
+	return s
+}
+
+// e.g: sum(rate({foo="bar"}[1m]))[1h:1m]
+func (e *SubqueryExpr) Pretty(level int) string {
+	s := e.Left.Pretty(level)
+
+	step := ""
+	if e.Step != 0 {
+		step = model.Duration(e.Step).String()
+	}
+	s = fmt.Sprintf("%s [%s:%s]", s, model.Duration(e.Range), step)
+
+	if e.Offset != 0 {
+		oe := OffsetExpr{Offset: e.Offset}
+		s += oe.Pretty(level)
+	}
+
+	return s
+}
+
+// e.g: max_over_time(sum(rate({foo="bar"}[1m]))[1h:1m])
+func (e *SubqueryAggregationExpr) Pretty(level int) string {
+	s := Indent(level)
+	if !NeedSplit(e) {
+		return s + e.String()
+	}
+
+	s += e.Operation
+
+	s += "(\n"
+
+	if hasTrailingParams(e.Operation) {
+		s += e.Left.Pretty(level + 1)
+		s += prettyTrailingParams(level+1, e.Params, e.SecondParam)
+	} else {
+		if e.Params != nil {
+			s = fmt.Sprintf("%s%s%s,", s, Indent(level+1), fmt.Sprint(*e.Params))
+			s += "\n"
+		}
+
+		s += e.Left.Pretty(level + 1)
+	}
 
 	s += "\n" + Indent(level) + ")"
 
@@ -248,9 +351,18 @@ func (e *VectorAggregationExpr) Pretty(level int) string {
 	left := e.Left.Pretty(level + 1)
 	switch e.Operation {
 	// e.Params default value (0) can mean a legit param for topk and bottomk
-	case OpTypeBottomK, OpTypeTopK:
+	case OpTypeBottomK, OpTypeTopK, OpTypeLimitK:
 		params = []string{fmt.Sprintf("%s%d", Indent(level+1), e.Params), left}
 
+	case OpTypeHistogramQuantile, OpTypeQuantile:
+		params = []string{fmt.Sprintf("%s%s", Indent(level+1), strconv.FormatFloat(e.Quantile, 'f', -1, 64)), left}
+
+	case OpTypeLimitRatio:
+		params = []string{fmt.Sprintf("%s%s", Indent(level+1), strconv.FormatFloat(e.Ratio, 'f', -1, 64)), left}
+
+	case OpTypeCountValues:
+		params = []string{fmt.Sprintf("%s%s", Indent(level+1), strconv.Quote(e.Label)), left}
+
 	default:
 		if e.Params != 0 {
 			params = []string{fmt.Sprintf("%s%d", Indent(level+1), e.Params), left}
@@ -338,6 +450,59 @@ func (e *LabelReplaceExpr) Pretty(level int) string {
 	return s
 }
 
+// e.g: label_join(rate({job="api-server",service="a:c"}[5m]), "foo", ",", "job", "service")
+func (e *LabelJoinExpr) Pretty(level int) string {
+	s := Indent(level)
+
+	if !NeedSplit(e) {
+		return s + e.String()
+	}
+
+	s += OpLabelJoin
+
+	s += "(\n"
+
+	params := []string{
+		e.Left.Pretty(level + 1),
+		Indent(level+1) + strconv.Quote(e.Dst),
+		Indent(level+1) + strconv.Quote(e.Separator),
+	}
+	for _, src := range e.Src {
+		params = append(params, Indent(level+1)+strconv.Quote(src))
+	}
+
+	for i, v := range params {
+		s += v
+		// LogQL doesn't allow `,` at the end of last argument.
+		if i < len(params)-1 {
+			s += ","
+		}
+		s += "\n"
+	}
+
+	s += Indent(level) + ")"
+
+	return s
+}
+
+// e.g: round(rate({job="api-server"}[5m]), 0.5)
+func (e *FunctionExpr) Pretty(level int) string {
+	s := Indent(level)
+
+	if e.Left == nil || !NeedSplit(e) {
+		return s + e.String()
+	}
+
+	s += e.Function + "(\n"
+	s += e.Left.Pretty(level + 1)
+	if e.Param != nil {
+		s += ",\n" + Indent(level+1) + strconv.FormatFloat(*e.Param, 'f', -1, 64)
+	}
+	s += "\n" + Indent(level) + ")"
+
+	return s
+}
+
 // e.g: vector(5)
 func (e *VectorExpr) Pretty(level int) string {
 	return commonPrefixIndent(level, e)
@@ -132,6 +132,26 @@ func TestFormat_VectorAggregation(t *testing.T) {
   count_over_time(
     {foo="bar", namespace="loki", instance="localhost"} [5m]
   )
+)`,
+		},
+		{
+			name: "count_values",
+			in:   `count_values("value", count_over_time({foo="bar",namespace="loki",instance="localhost"}[5m])) by (container)`,
+			exp: `count_values by (container)(
+  "value",
+  count_over_time(
+    {foo="bar", namespace="loki", instance="localhost"} [5m]
+  )
+)`,
+		},
+		{
+			name: "limit_ratio",
+			in:   `limit_ratio(0.5, count_over_time({foo="bar",namespace="loki",instance="localhost"}[5m]))`,
+			exp: `limit_ratio(
+  0.5,
+  count_over_time(
+    {foo="bar", namespace="loki", instance="localhost"} [5m]
+  )
 )`,
 		},
 	}
@@ -186,6 +206,42 @@ func TestFormat_LabelReplace(t *testing.T) {
   "$1",
   "service",
   "(.*):.*"
+)`,
+		},
+		{
+			name: "label_join",
+			in:   `label_join(rate({job="api-server",service="a:c"}|= "err" [5m]), "foo", "-", "job", "service")`,
+			exp: `label_join(
+  rate(
+    {job="api-server", service="a:c"}
+      |= "err" [5m]
+  ),
+  "foo",
+  "-",
+  "job",
+  "service"
+)`,
+		},
+		{
+			name: "holt_winters",
+			in:   `holt_winters({job="api-server",service="a:c"}|= "err" | unwrap bar [5m], 0.5, 0.1)`,
+			exp: `holt_winters(
+  {job="api-server", service="a:c"}
+    |= "err"
+    | unwrap bar [5m],
+  0.5,
+  0.1
+)`,
+		},
+		{
+			name: "function",
+			in:   `round(rate({job="api-server",service="a:c"}|= "err" [5m]), 0.5)`,
+			exp: `round(
+  rate(
+    {job="api-server", service="a:c"}
+      |= "err" [5m]
+  ),
+  0.5
 )`,
 		},
 	}
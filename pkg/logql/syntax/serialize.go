@@ -38,6 +38,7 @@ const (
 	Card                = "cardinality"
 	Dst                 = "dst"
 	Duration            = "duration"
+	Function            = "function"
 	Groups              = "groups"
 	GroupingField       = "grouping"
 	Include             = "include"
@@ -46,6 +47,7 @@ const (
 	IntervalNanos       = "interval_nanos"
 	IPField             = "ip"
 	Label               = "label"
+	LabelJoin           = "label_join"
 	LabelReplace        = "label_replace"
 	LHS                 = "lhs"
 	Literal             = "literal"
@@ -60,15 +62,22 @@ const (
 	Params              = "params"
 	Pattern             = "pattern"
 	PostFilterers       = "post_filterers"
+	Quantile            = "quantile"
+	SecondParam         = "second_param"
 	Range               = "range"
 	RangeAgg            = "range_agg"
+	Ratio               = "ratio"
 	Raw                 = "raw"
 	RegexField          = "regex"
 	Replacement         = "replacement"
 	ReturnBool          = "return_bool"
 	RHS                 = "rhs"
+	Separator           = "separator"
 	Src                 = "src"
+	StepNanos           = "step_nanos"
 	StringField         = "string"
+	Subquery            = "subquery"
+	SubqueryAgg         = "subquery_agg"
 	NoopField           = "noop"
 	Type                = "type"
 	Unwrap              = "unwrap"
@@ -92,12 +101,18 @@ func DecodeJSON(raw string) (Expr, error) {
 		return decodeVectorAgg(iter)
 	case RangeAgg:
 		return decodeRangeAgg(iter)
+	case SubqueryAgg:
+		return decodeSubqueryAgg(iter)
 	case Literal:
 		return decodeLiteral(iter)
 	case Vector:
 		return decodeVector(iter)
 	case LabelReplace:
 		return decodeLabelReplace(iter)
+	case LabelJoin:
+		return decodeLabelJoin(iter)
+	case Function:
+		return decodeFunction(iter)
 	case LogSelector:
 		return decodeLogSelector(iter)
 	case Variants:
@@ -159,6 +174,21 @@ func (v *JSONSerializer) VisitVectorAggregation(e *VectorAggregationExpr) {
 	v.WriteObjectField(Params)
 	v.WriteInt(e.Params)
 
+	switch e.Operation {
+	case OpTypeHistogramQuantile, OpTypeQuantile:
+		v.WriteMore()
+		v.WriteObjectField(Quantile)
+		v.WriteFloat64(e.Quantile)
+	case OpTypeLimitRatio:
+		v.WriteMore()
+		v.WriteObjectField(Ratio)
+		v.WriteFloat64(e.Ratio)
+	case OpTypeCountValues:
+		v.WriteMore()
+		v.WriteObjectField(Label)
+		v.WriteString(e.Label)
+	}
+
 	v.WriteMore()
 	v.WriteObjectField(Op)
 	v.WriteString(e.Operation)
@@ -199,6 +229,12 @@ func (v *JSONSerializer) VisitRangeAggregation(e *RangeAggregationExpr) {
 		v.WriteFloat64(*e.Params)
 	}
 
+	if e.SecondParam != nil {
+		v.WriteMore()
+		v.WriteObjectField(SecondParam)
+		v.WriteFloat64(*e.SecondParam)
+	}
+
 	v.WriteMore()
 	v.WriteObjectField(Range)
 	v.VisitLogRange(e.Left)
@@ -232,6 +268,62 @@ func (v *JSONSerializer) VisitLogRange(e *LogRangeExpr) {
 	v.Flush()
 }
 
+func (v *JSONSerializer) VisitSubqueryAggregation(e *SubqueryAggregationExpr) {
+	v.WriteObjectStart()
+
+	v.WriteObjectField(SubqueryAgg)
+	v.WriteObjectStart()
+
+	v.WriteObjectField(Op)
+	v.WriteString(e.Operation)
+
+	if e.Grouping != nil {
+		v.WriteMore()
+		v.WriteObjectField(GroupingField)
+		encodeGrouping(v.Stream, e.Grouping)
+	}
+
+	if e.Params != nil {
+		v.WriteMore()
+		v.WriteObjectField(Params)
+		v.WriteFloat64(*e.Params)
+	}
+
+	if e.SecondParam != nil {
+		v.WriteMore()
+		v.WriteObjectField(SecondParam)
+		v.WriteFloat64(*e.SecondParam)
+	}
+
+	v.WriteMore()
+	v.WriteObjectField(Subquery)
+	v.VisitSubquery(e.Left)
+	v.WriteObjectEnd()
+
+	v.WriteObjectEnd()
+	v.Flush()
+}
+
+func (v *JSONSerializer) VisitSubquery(e *SubqueryExpr) {
+	v.WriteObjectStart()
+
+	v.WriteObjectField(IntervalNanos)
+	v.WriteInt64(int64(e.Range))
+	v.WriteMore()
+	v.WriteObjectField(StepNanos)
+	v.WriteInt64(int64(e.Step))
+	v.WriteMore()
+	v.WriteObjectField(OffsetNanos)
+	v.WriteInt64(int64(e.Offset))
+
+	v.WriteMore()
+	v.WriteObjectField(Inner)
+	e.Left.Accept(v)
+
+	v.WriteObjectEnd()
+	v.Flush()
+}
+
 func (v *JSONSerializer) VisitLabelReplace(e *LabelReplaceExpr) {
 	v.WriteObjectStart()
 
@@ -262,6 +354,65 @@ func (v *JSONSerializer) VisitLabelReplace(e *LabelReplaceExpr) {
 	v.Flush()
 }
 
+func (v *JSONSerializer) VisitLabelJoin(e *LabelJoinExpr) {
+	v.WriteObjectStart()
+
+	v.WriteObjectField(LabelJoin)
+	v.WriteObjectStart()
+
+	v.WriteObjectField(Inner)
+	e.Left.Accept(v)
+
+	v.WriteMore()
+	v.WriteObjectField(Dst)
+	v.WriteString(e.Dst)
+
+	v.WriteMore()
+	v.WriteObjectField(Separator)
+	v.WriteString(e.Separator)
+
+	v.WriteMore()
+	v.WriteObjectField(Src)
+	v.WriteArrayStart()
+	for i, src := range e.Src {
+		if i > 0 {
+			v.WriteMore()
+		}
+		v.WriteString(src)
+	}
+	v.WriteArrayEnd()
+
+	v.WriteObjectEnd()
+	v.WriteObjectEnd()
+	v.Flush()
+}
+
+func (v *JSONSerializer) VisitFunction(e *FunctionExpr) {
+	v.WriteObjectStart()
+
+	v.WriteObjectField(Function)
+	v.WriteObjectStart()
+
+	v.WriteObjectField(Op)
+	v.WriteString(e.Function)
+
+	if e.Left != nil {
+		v.WriteMore()
+		v.WriteObjectField(Inner)
+		e.Left.Accept(v)
+	}
+
+	if e.Param != nil {
+		v.WriteMore()
+		v.WriteObjectField(Params)
+		v.WriteFloat64(*e.Param)
+	}
+
+	v.WriteObjectEnd()
+	v.WriteObjectEnd()
+	v.Flush()
+}
+
 func (v *JSONSerializer) VisitLiteral(e *LiteralExpr) {
 	v.WriteObjectStart()
 
@@ -338,6 +489,11 @@ func (v *JSONSerializer) VisitVariants(e *MultiVariantExpr) {
 // Below are StageExpr visitors that we are skipping since a pipeline is
 // serialized as a string.
 func (*JSONSerializer) VisitDecolorize(*DecolorizeExpr)                         {}
+func (*JSONSerializer) VisitRedact(*RedactExpr)                                 {}
+func (*JSONSerializer) VisitSampling(*SamplingExpr)                             {}
+func (*JSONSerializer) VisitContext(*ContextExpr)                               {}
+func (*JSONSerializer) VisitLookup(*LookupExpr)                                 {}
+func (*JSONSerializer) VisitDedup(*DedupExpr)                                   {}
 func (*JSONSerializer) VisitDropLabels(*DropLabelsExpr)                         {}
 func (*JSONSerializer) VisitJSONExpressionParser(*JSONExpressionParserExpr)     {}
 func (*JSONSerializer) VisitKeepLabel(*KeepLabelsExpr)                          {}
@@ -348,6 +504,8 @@ func (*JSONSerializer) VisitLineFilter(*LineFilterExpr)
 func (*JSONSerializer) VisitLineFmt(*LineFmtExpr)                               {}
 func (*JSONSerializer) VisitLogfmtExpressionParser(*LogfmtExpressionParserExpr) {}
 func (*JSONSerializer) VisitLogfmtParser(*LogfmtParserExpr)                     {}
+func (*JSONSerializer) VisitXMLExpressionParser(*XMLExpressionParserExpr)       {}
+func (*JSONSerializer) VisitCSVParser(*CSVParserExpr)                           {}
 
 func encodeGrouping(s *jsoniter.Stream, g *Grouping) {
 	s.WriteObjectStart()
@@ -742,12 +900,18 @@ func decodeSample(iter *jsoniter.Iterator) (SampleExpr, error) {
 			expr, err = decodeVectorAgg(iter)
 		case RangeAgg:
 			expr, err = decodeRangeAgg(iter)
+		case SubqueryAgg:
+			expr, err = decodeSubqueryAgg(iter)
 		case Literal:
 			expr, err = decodeLiteral(iter)
 		case Vector:
 			expr, err = decodeVector(iter)
 		case LabelReplace:
 			expr, err = decodeLabelReplace(iter)
+		case LabelJoin:
+			expr, err = decodeLabelJoin(iter)
+		case Function:
+			expr, err = decodeFunction(iter)
 		default:
 			return nil, fmt.Errorf("unknown sample expression type: %s", key)
 		}
@@ -858,6 +1022,12 @@ func decodeVectorAgg(iter *jsoniter.Iterator) (*VectorAggregationExpr, error) {
 			expr.Operation = iter.ReadString()
 		case Params:
 			expr.Params = iter.ReadInt()
+		case Quantile:
+			expr.Quantile = iter.ReadFloat64()
+		case Ratio:
+			expr.Ratio = iter.ReadFloat64()
+		case Label:
+			expr.Label = iter.ReadString()
 		case GroupingField:
 			expr.Grouping, err = decodeGrouping(iter)
 		case Inner:
@@ -879,6 +1049,9 @@ func decodeRangeAgg(iter *jsoniter.Iterator) (*RangeAggregationExpr, error) {
 		case Params:
 			tmp := iter.ReadFloat64()
 			expr.Params = &tmp
+		case SecondParam:
+			tmp := iter.ReadFloat64()
+			expr.SecondParam = &tmp
 		case Range:
 			expr.Left, err = decodeLogRange(iter)
 		case GroupingField:
@@ -909,6 +1082,50 @@ func decodeLogRange(iter *jsoniter.Iterator) (*LogRangeExpr, error) {
 	return expr, err
 }
 
+func decodeSubqueryAgg(iter *jsoniter.Iterator) (*SubqueryAggregationExpr, error) {
+	expr := &SubqueryAggregationExpr{}
+	var err error
+
+	for f := iter.ReadObject(); f != ""; f = iter.ReadObject() {
+		switch f {
+		case Op:
+			expr.Operation = iter.ReadString()
+		case Params:
+			tmp := iter.ReadFloat64()
+			expr.Params = &tmp
+		case SecondParam:
+			tmp := iter.ReadFloat64()
+			expr.SecondParam = &tmp
+		case Subquery:
+			expr.Left, err = decodeSubquery(iter)
+		case GroupingField:
+			expr.Grouping, err = decodeGrouping(iter)
+		}
+	}
+
+	return expr, err
+}
+
+func decodeSubquery(iter *jsoniter.Iterator) (*SubqueryExpr, error) {
+	expr := &SubqueryExpr{}
+	var err error
+
+	for f := iter.ReadObject(); f != ""; f = iter.ReadObject() {
+		switch f {
+		case Inner:
+			expr.Left, err = decodeSample(iter)
+		case IntervalNanos:
+			expr.Range = time.Duration(iter.ReadInt64())
+		case StepNanos:
+			expr.Step = time.Duration(iter.ReadInt64())
+		case OffsetNanos:
+			expr.Offset = time.Duration(iter.ReadInt64())
+		}
+	}
+
+	return expr, err
+}
+
 func decodeLabelReplace(iter *jsoniter.Iterator) (*LabelReplaceExpr, error) {
 	var err error
 	var left SampleExpr
@@ -935,6 +1152,52 @@ func decodeLabelReplace(iter *jsoniter.Iterator) (*LabelReplaceExpr, error) {
 	return mustNewLabelReplaceExpr(left, dst, replacement, src, regex), nil
 }
 
+func decodeLabelJoin(iter *jsoniter.Iterator) (*LabelJoinExpr, error) {
+	var err error
+	var left SampleExpr
+	var dst, separator string
+	var src []string
+
+	for f := iter.ReadObject(); f != ""; f = iter.ReadObject() {
+		switch f {
+		case Inner:
+			left, err = decodeSample(iter)
+			if err != nil {
+				return nil, err
+			}
+		case Dst:
+			dst = iter.ReadString()
+		case Separator:
+			separator = iter.ReadString()
+		case Src:
+			for iter.ReadArray() {
+				src = append(src, iter.ReadString())
+			}
+		}
+	}
+
+	return mustNewLabelJoinExpr(left, dst, separator, src), nil
+}
+
+func decodeFunction(iter *jsoniter.Iterator) (*FunctionExpr, error) {
+	expr := &FunctionExpr{}
+	var err error
+
+	for f := iter.ReadObject(); f != ""; f = iter.ReadObject() {
+		switch f {
+		case Op:
+			expr.Function = iter.ReadString()
+		case Inner:
+			expr.Left, err = decodeSample(iter)
+		case Params:
+			tmp := iter.ReadFloat64()
+			expr.Param = &tmp
+		}
+	}
+
+	return expr, err
+}
+
 func decodeLiteral(iter *jsoniter.Iterator) (*LiteralExpr, error) {
 	expr := &LiteralExpr{}
 
@@ -39,6 +39,27 @@ func TestJSONSerializationRoundTrip(t *testing.T) {
 		"sum over or vector": {
 			query: `(sum(count_over_time({foo="bar"}[5m])) or vector(1.000000))`,
 		},
+		"histogram quantile": {
+			query: `histogram_quantile(0.99, sum by (le) (histogram_over_time({app="foo"} | json | unwrap latency [5m])))`,
+		},
+		"vector aggregations with parameters": {
+			query: `limit_ratio(-0.5, limitk(2, count_values("value", quantile(0.9, group by (app) (rate({app="foo"}[1m]))) by (app))))`,
+		},
+		"xml and csv parsers": {
+			query: `sum by (status) (count_over_time({app="foo"} | xml level="Event/System/Level" | csv "ts", "status" | level="2" [5m]))`,
+		},
+		"subquery": {
+			query: `quantile_over_time(0.99, sum by (app) (rate({app="foo"} | json [1m]))[1h:5m] offset 10m) by (app)`,
+		},
+		"trend functions": {
+			query: `holt_winters(sum by (app) (rate({app="foo"}[1m]))[1h:], 0.5, 0.1) > predict_linear({app="foo"} | unwrap bar [30m], 3600) by (app)`,
+		},
+		"functions": {
+			query: `clamp_max(abs(sum by (app) (rate({app="foo"}[1m]))), 5) > day_of_week()`,
+		},
+		"label join": {
+			query: `label_join(rate({app="foo"}[1m]), "dst", "-", "app", "pod")`,
+		},
 		"label replace": {
 			query: `label_replace(vector(0.000000),"foo","bar","","")`,
 		},
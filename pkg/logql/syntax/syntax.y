@@ -43,24 +43,26 @@ import (
   labelExtractionExpressionList []log.LabelExtractionExpr
   unwrapExpr *UnwrapExpr
   offsetExpr *OffsetExpr
+  subqueryRange subqueryRange
+  subqueryExpr *SubqueryExpr
 }
 
 %start root
 
 %type <expr> expr
 %type <logExpr> logExpr
-%type <metricExpr> metricExpr rangeAggregationExpr vectorAggregationExpr binOpExpr labelReplaceExpr vectorExpr
+%type <metricExpr> metricExpr rangeAggregationExpr vectorAggregationExpr binOpExpr labelReplaceExpr labelJoinExpr functionExpr vectorExpr
 %type <variantsExpr> variantsExpr
-%type <stage> pipelineStage logfmtParser labelParser jsonExpressionParser logfmtExpressionParser lineFormatExpr decolorizeExpr labelFormatExpr dropLabelsExpr keepLabelsExpr
+%type <stage> pipelineStage logfmtParser labelParser jsonExpressionParser logfmtExpressionParser xmlExpressionParser csvParser lineFormatExpr decolorizeExpr labelFormatExpr dropLabelsExpr keepLabelsExpr redactExpr samplingExpr contextExpr lookupExpr dedupExpr
 %type <stages> pipelineExpr
 %type <lineFilterExpr> lineFilter lineFilters orFilter
-%type <op> rangeOp convOp vectorOp filterOp
+%type <op> rangeOp convOp vectorOp filterOp functionOp
 %type <filterer> bytesFilter numberFilter durationFilter labelFilter unitFilter ipLabelFilter
 %type <filter> filter
 %type <matcher> matcher
 %type <matchers> matchers selector
 %type <str> vector
-%type <strs> labels parserFlags
+%type <strs> labels parserFlags stringList rangeParams contextOption contextOptions
 %type <binOpts> binOpModifier boolModifier onOrIgnoringModifier
 %type <namedMatcher> namedMatcher
 %type <namedMatchers> namedMatchers
@@ -73,18 +75,22 @@ import (
 %type <labelExtractionExpressionList> labelExtractionExpressionList
 %type <unwrapExpr> unwrapExpr
 %type <offsetExpr> offsetExpr
+%type <subqueryExpr> subqueryExpr
 %type <metricExprs> metricExprs
 
 %token <bytes> BYTES
 %token <str> IDENTIFIER STRING NUMBER FUNCTION_FLAG
 %token <dur> DURATION RANGE
+%token <subqueryRange> SUBQUERY_RANGE
 %token <val> MATCHERS LABELS EQ RE NRE NPA OPEN_BRACE CLOSE_BRACE OPEN_BRACKET CLOSE_BRACKET COMMA DOT PIPE_MATCH PIPE_EXACT PIPE_PATTERN
              OPEN_PARENTHESIS CLOSE_PARENTHESIS BY WITHOUT COUNT_OVER_TIME RATE RATE_COUNTER SUM SORT SORT_DESC AVG
-             MAX MIN COUNT STDDEV STDVAR BOTTOMK TOPK APPROX_TOPK
+             MAX MIN COUNT STDDEV STDVAR BOTTOMK TOPK APPROX_TOPK APPROX_COUNT_DISTINCT HISTOGRAM_QUANTILE
              BYTES_OVER_TIME BYTES_RATE BOOL JSON REGEXP LOGFMT PIPE LINE_FMT LABEL_FMT UNWRAP AVG_OVER_TIME SUM_OVER_TIME MIN_OVER_TIME
              MAX_OVER_TIME STDVAR_OVER_TIME STDDEV_OVER_TIME QUANTILE_OVER_TIME BYTES_CONV DURATION_CONV DURATION_SECONDS_CONV
-             FIRST_OVER_TIME LAST_OVER_TIME ABSENT_OVER_TIME VECTOR LABEL_REPLACE UNPACK OFFSET PATTERN IP ON IGNORING GROUP_LEFT GROUP_RIGHT
-             DECOLORIZE DROP KEEP VARIANTS OF
+             FIRST_OVER_TIME LAST_OVER_TIME ABSENT_OVER_TIME APPROX_COUNT_DISTINCT_OVER_TIME HISTOGRAM_OVER_TIME VECTOR LABEL_REPLACE UNPACK OFFSET PATTERN IP ON IGNORING GROUP_LEFT GROUP_RIGHT
+             DECOLORIZE DROP KEEP VARIANTS OF REDACT SAMPLE LABEL_JOIN
+             ABS CEIL FLOOR ROUND CLAMP_MIN CLAMP_MAX SQRT LN EXP TIMESTAMP HOUR DAY_OF_WEEK
+             CHANGES DERIV PREDICT_LINEAR HOLT_WINTERS COUNT_VALUES QUANTILE GROUP LIMITK LIMIT_RATIO XML CSV CONTEXT LOOKUP DEDUP
 
 // Operators are listed with increasing precedence.
 %left <binOp> OR
@@ -117,6 +123,8 @@ metricExpr:
     | binOpExpr                                     { $$ = $1 }
     | literalExpr                                   { $$ = $1 }
     | labelReplaceExpr                              { $$ = $1 }
+    | labelJoinExpr                                 { $$ = $1 }
+    | functionExpr                                  { $$ = $1 }
     | vectorExpr                                    { $$ = $1 }
     | OPEN_PARENTHESIS metricExpr CLOSE_PARENTHESIS { $$ = $2 }
     ;
@@ -154,6 +162,11 @@ logRangeExpr:
     | logRangeExpr error
     ;
 
+subqueryExpr:
+      metricExpr SUBQUERY_RANGE              { $$ = newSubqueryExpr($1, $2, nil) }
+    | metricExpr SUBQUERY_RANGE offsetExpr   { $$ = newSubqueryExpr($1, $2, $3) }
+    ;
+
 unwrapExpr:
     PIPE UNWRAP IDENTIFIER                                                   { $$ = newUnwrapExpr($3, "")}
   | PIPE UNWRAP convOp OPEN_PARENTHESIS IDENTIFIER CLOSE_PARENTHESIS         { $$ = newUnwrapExpr($5, $3)}
@@ -171,6 +184,19 @@ rangeAggregationExpr:
     | rangeOp OPEN_PARENTHESIS NUMBER COMMA logRangeExpr CLOSE_PARENTHESIS           { $$ = newRangeAggregationExpr($5, $1, nil, &$3) }
     | rangeOp OPEN_PARENTHESIS logRangeExpr CLOSE_PARENTHESIS grouping               { $$ = newRangeAggregationExpr($3, $1, $5, nil) }
     | rangeOp OPEN_PARENTHESIS NUMBER COMMA logRangeExpr CLOSE_PARENTHESIS grouping  { $$ = newRangeAggregationExpr($5, $1, $7, &$3) }
+    | rangeOp OPEN_PARENTHESIS subqueryExpr CLOSE_PARENTHESIS                        { $$ = newSubqueryAggregationExpr($3, $1, nil, nil) }
+    | rangeOp OPEN_PARENTHESIS NUMBER COMMA subqueryExpr CLOSE_PARENTHESIS           { $$ = newSubqueryAggregationExpr($5, $1, nil, &$3) }
+    | rangeOp OPEN_PARENTHESIS subqueryExpr CLOSE_PARENTHESIS grouping               { $$ = newSubqueryAggregationExpr($3, $1, $5, nil) }
+    | rangeOp OPEN_PARENTHESIS NUMBER COMMA subqueryExpr CLOSE_PARENTHESIS grouping  { $$ = newSubqueryAggregationExpr($5, $1, $7, &$3) }
+    | rangeOp OPEN_PARENTHESIS logRangeExpr rangeParams CLOSE_PARENTHESIS            { $$ = newTrailingParamsRangeAggregationExpr($3, $1, nil, $4) }
+    | rangeOp OPEN_PARENTHESIS logRangeExpr rangeParams CLOSE_PARENTHESIS grouping   { $$ = newTrailingParamsRangeAggregationExpr($3, $1, $6, $4) }
+    | rangeOp OPEN_PARENTHESIS subqueryExpr rangeParams CLOSE_PARENTHESIS            { $$ = newTrailingParamsSubqueryAggregationExpr($3, $1, nil, $4) }
+    | rangeOp OPEN_PARENTHESIS subqueryExpr rangeParams CLOSE_PARENTHESIS grouping   { $$ = newTrailingParamsSubqueryAggregationExpr($3, $1, $6, $4) }
+    ;
+
+rangeParams:
+      COMMA NUMBER              { $$ = []string{$2} }
+    | rangeParams COMMA NUMBER  { $$ = append($1, $3) }
     ;
 
 vectorAggregationExpr:
@@ -182,6 +208,12 @@ vectorAggregationExpr:
     | vectorOp OPEN_PARENTHESIS NUMBER COMMA metricExpr CLOSE_PARENTHESIS                 { $$ = mustNewVectorAggregationExpr($5, $1, nil, &$3) }
     | vectorOp OPEN_PARENTHESIS NUMBER COMMA metricExpr CLOSE_PARENTHESIS grouping        { $$ = mustNewVectorAggregationExpr($5, $1, $7, &$3) }
     | vectorOp grouping OPEN_PARENTHESIS NUMBER COMMA metricExpr CLOSE_PARENTHESIS        { $$ = mustNewVectorAggregationExpr($6, $1, $2, &$4) }
+    | vectorOp OPEN_PARENTHESIS SUB NUMBER COMMA metricExpr CLOSE_PARENTHESIS             { n := "-" + $4; $$ = mustNewVectorAggregationExpr($6, $1, nil, &n) }
+    | vectorOp OPEN_PARENTHESIS SUB NUMBER COMMA metricExpr CLOSE_PARENTHESIS grouping    { n := "-" + $4; $$ = mustNewVectorAggregationExpr($6, $1, $8, &n) }
+    | vectorOp grouping OPEN_PARENTHESIS SUB NUMBER COMMA metricExpr CLOSE_PARENTHESIS    { n := "-" + $5; $$ = mustNewVectorAggregationExpr($7, $1, $2, &n) }
+    | vectorOp OPEN_PARENTHESIS STRING COMMA metricExpr CLOSE_PARENTHESIS                 { $$ = mustNewCountValuesExpr($5, $1, nil, $3) }
+    | vectorOp OPEN_PARENTHESIS STRING COMMA metricExpr CLOSE_PARENTHESIS grouping        { $$ = mustNewCountValuesExpr($5, $1, $7, $3) }
+    | vectorOp grouping OPEN_PARENTHESIS STRING COMMA metricExpr CLOSE_PARENTHESIS        { $$ = mustNewCountValuesExpr($6, $1, $2, $4) }
     ;
 
 labelReplaceExpr:
@@ -189,6 +221,37 @@ labelReplaceExpr:
       { $$ = mustNewLabelReplaceExpr($3, $5, $7, $9, $11)}
     ;
 
+labelJoinExpr:
+    LABEL_JOIN OPEN_PARENTHESIS metricExpr COMMA STRING COMMA STRING COMMA stringList CLOSE_PARENTHESIS
+      { $$ = mustNewLabelJoinExpr($3, $5, $7, $9)}
+    ;
+
+stringList:
+      STRING                  { $$ = []string{ $1 } }
+    | stringList COMMA STRING { $$ = append($1, $3) }
+    ;
+
+functionExpr:
+      functionOp OPEN_PARENTHESIS metricExpr CLOSE_PARENTHESIS                   { $$ = mustNewFunctionExpr($3, $1, nil) }
+    | functionOp OPEN_PARENTHESIS metricExpr COMMA literalExpr CLOSE_PARENTHESIS { $$ = mustNewFunctionExpr($3, $1, $5) }
+    | functionOp OPEN_PARENTHESIS CLOSE_PARENTHESIS                              { $$ = mustNewFunctionExpr(nil, $1, nil) }
+    ;
+
+functionOp:
+      ABS         { $$ = OpFuncAbs }
+    | CEIL        { $$ = OpFuncCeil }
+    | FLOOR       { $$ = OpFuncFloor }
+    | ROUND       { $$ = OpFuncRound }
+    | CLAMP_MIN   { $$ = OpFuncClampMin }
+    | CLAMP_MAX   { $$ = OpFuncClampMax }
+    | SQRT        { $$ = OpFuncSqrt }
+    | LN          { $$ = OpFuncLn }
+    | EXP         { $$ = OpFuncExp }
+    | TIMESTAMP   { $$ = OpFuncTimestamp }
+    | HOUR        { $$ = OpFuncHour }
+    | DAY_OF_WEEK { $$ = OpFuncDayOfWeek }
+    ;
+
 selector:
       OPEN_BRACE matchers CLOSE_BRACE  { $$ = $2 }
     | OPEN_BRACE matchers error        { $$ = $2 }
@@ -218,12 +281,19 @@ pipelineStage:
   | PIPE labelParser             { $$ = $2 }
   | PIPE jsonExpressionParser    { $$ = $2 }
   | PIPE logfmtExpressionParser  { $$ = $2 }
+  | PIPE xmlExpressionParser     { $$ = $2 }
+  | PIPE csvParser               { $$ = $2 }
   | PIPE labelFilter             { $$ = &LabelFilterExpr{LabelFilterer: $2 }}
   | PIPE lineFormatExpr          { $$ = $2 }
   | PIPE decolorizeExpr          { $$ = $2 }
   | PIPE labelFormatExpr         { $$ = $2 }
   | PIPE dropLabelsExpr          { $$ = $2 }
   | PIPE keepLabelsExpr          { $$ = $2 }
+  | PIPE redactExpr              { $$ = $2 }
+  | PIPE samplingExpr            { $$ = $2 }
+  | PIPE contextExpr             { $$ = $2 }
+  | PIPE lookupExpr              { $$ = $2 }
+  | PIPE dedupExpr               { $$ = $2 }
   ;
 
 filter:
@@ -271,6 +341,7 @@ labelParser:
   | REGEXP STRING       { $$ = newLabelParserExpr(OpParserTypeRegexp, $2) }
   | UNPACK              { $$ = newLabelParserExpr(OpParserTypeUnpack, "") }
   | PATTERN STRING      { $$ = newLabelParserExpr(OpParserTypePattern, $2) }
+  | XML                 { $$ = newLabelParserExpr(OpParserTypeXML, "") }
   ;
 
 jsonExpressionParser:
@@ -281,10 +352,44 @@ logfmtExpressionParser:
   | LOGFMT labelExtractionExpressionList              { $$ = newLogfmtExpressionParser($2, nil)}
   ;
 
+xmlExpressionParser:
+    XML labelExtractionExpressionList { $$ = newXMLExpressionParser($2) }
+
+csvParser:
+    CSV             { $$ = newCSVParserExpr(nil) }
+  | CSV stringList  { $$ = newCSVParserExpr($2) }
+  ;
+
 lineFormatExpr: LINE_FMT STRING { $$ = newLineFmtExpr($2) };
 
 decolorizeExpr: DECOLORIZE { $$ = newDecolorizeExpr() };
 
+redactExpr:
+    REDACT         { $$ = newRedactExpr(nil) }
+  | REDACT labels  { $$ = newRedactExpr($2) }
+  ;
+
+samplingExpr:
+    SAMPLE NUMBER                  { $$ = newSamplingExpr($2, "") }
+  | SAMPLE NUMBER BY IDENTIFIER    { $$ = newSamplingExpr($2, $4) }
+  ;
+
+contextExpr: CONTEXT contextOptions { $$ = newContextExpr($2) };
+
+contextOptions:
+    contextOption                 { $$ = $1 }
+  | contextOptions contextOption  { $$ = append($1, $2...) }
+  ;
+
+contextOption: IDENTIFIER EQ NUMBER { $$ = []string{ $1, $3 } };
+
+lookupExpr:
+    LOOKUP IDENTIFIER ON IDENTIFIER                    { $$ = newLookupExpr($2, $4, "", "") }
+  | LOOKUP IDENTIFIER ON IDENTIFIER IDENTIFIER STRING  { $$ = newLookupExpr($2, $4, $5, $6) }
+  ;
+
+dedupExpr: DEDUP labels { $$ = newDedupExpr($2) };
+
 labelFormat:
      IDENTIFIER EQ IDENTIFIER { $$ = log.NewRenameLabelFmt($1, $3)}
   |  IDENTIFIER EQ STRING     { $$ = log.NewTemplateLabelFmt($1, $3)}
@@ -487,6 +592,13 @@ vectorOp:
       | SORT    { $$ = OpTypeSort }
       | SORT_DESC    { $$ = OpTypeSortDesc }
       | APPROX_TOPK  { $$ = OpTypeApproxTopK }
+      | APPROX_COUNT_DISTINCT  { $$ = OpTypeApproxCountDistinct }
+      | HISTOGRAM_QUANTILE     { $$ = OpTypeHistogramQuantile }
+      | COUNT_VALUES           { $$ = OpTypeCountValues }
+      | QUANTILE               { $$ = OpTypeQuantile }
+      | GROUP                  { $$ = OpTypeGroup }
+      | LIMITK                 { $$ = OpTypeLimitK }
+      | LIMIT_RATIO            { $$ = OpTypeLimitRatio }
       ;
 
 rangeOp:
@@ -505,6 +617,12 @@ rangeOp:
     | FIRST_OVER_TIME    { $$ = OpRangeTypeFirst }
     | LAST_OVER_TIME     { $$ = OpRangeTypeLast }
     | ABSENT_OVER_TIME   { $$ = OpRangeTypeAbsent }
+    | APPROX_COUNT_DISTINCT_OVER_TIME { $$ = OpRangeTypeApproxCountDistinct }
+    | HISTOGRAM_OVER_TIME             { $$ = OpRangeTypeHistogram }
+    | CHANGES                         { $$ = OpRangeTypeChanges }
+    | DERIV                           { $$ = OpRangeTypeDeriv }
+    | PREDICT_LINEAR                  { $$ = OpRangeTypePredictLinear }
+    | HOLT_WINTERS                    { $$ = OpRangeTypeHoltWinters }
     ;
 
 offsetExpr:
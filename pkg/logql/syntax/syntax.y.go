@@ -45,6 +45,8 @@ type syntaxSymType struct {
 	labelExtractionExpressionList []log.LabelExtractionExpr
 	unwrapExpr                    *UnwrapExpr
 	offsetExpr                    *OffsetExpr
+	subqueryRange                 subqueryRange
+	subqueryExpr                  *SubqueryExpr
 }
 
 const BYTES = 57346
@@ -54,93 +56,127 @@ const NUMBER = 57349
 const FUNCTION_FLAG = 57350
 const DURATION = 57351
 const RANGE = 57352
-const MATCHERS = 57353
-const LABELS = 57354
-const EQ = 57355
-const RE = 57356
-const NRE = 57357
-const NPA = 57358
-const OPEN_BRACE = 57359
-const CLOSE_BRACE = 57360
-const OPEN_BRACKET = 57361
-const CLOSE_BRACKET = 57362
-const COMMA = 57363
-const DOT = 57364
-const PIPE_MATCH = 57365
-const PIPE_EXACT = 57366
-const PIPE_PATTERN = 57367
-const OPEN_PARENTHESIS = 57368
-const CLOSE_PARENTHESIS = 57369
-const BY = 57370
-const WITHOUT = 57371
-const COUNT_OVER_TIME = 57372
-const RATE = 57373
-const RATE_COUNTER = 57374
-const SUM = 57375
-const SORT = 57376
-const SORT_DESC = 57377
-const AVG = 57378
-const MAX = 57379
-const MIN = 57380
-const COUNT = 57381
-const STDDEV = 57382
-const STDVAR = 57383
-const BOTTOMK = 57384
-const TOPK = 57385
-const APPROX_TOPK = 57386
-const BYTES_OVER_TIME = 57387
-const BYTES_RATE = 57388
-const BOOL = 57389
-const JSON = 57390
-const REGEXP = 57391
-const LOGFMT = 57392
-const PIPE = 57393
-const LINE_FMT = 57394
-const LABEL_FMT = 57395
-const UNWRAP = 57396
-const AVG_OVER_TIME = 57397
-const SUM_OVER_TIME = 57398
-const MIN_OVER_TIME = 57399
-const MAX_OVER_TIME = 57400
-const STDVAR_OVER_TIME = 57401
-const STDDEV_OVER_TIME = 57402
-const QUANTILE_OVER_TIME = 57403
-const BYTES_CONV = 57404
-const DURATION_CONV = 57405
-const DURATION_SECONDS_CONV = 57406
-const FIRST_OVER_TIME = 57407
-const LAST_OVER_TIME = 57408
-const ABSENT_OVER_TIME = 57409
-const VECTOR = 57410
-const LABEL_REPLACE = 57411
-const UNPACK = 57412
-const OFFSET = 57413
-const PATTERN = 57414
-const IP = 57415
-const ON = 57416
-const IGNORING = 57417
-const GROUP_LEFT = 57418
-const GROUP_RIGHT = 57419
-const DECOLORIZE = 57420
-const DROP = 57421
-const KEEP = 57422
-const VARIANTS = 57423
-const OF = 57424
-const OR = 57425
-const AND = 57426
-const UNLESS = 57427
-const CMP_EQ = 57428
-const NEQ = 57429
-const LT = 57430
-const LTE = 57431
-const GT = 57432
-const GTE = 57433
-const ADD = 57434
-const SUB = 57435
-const MUL = 57436
-const DIV = 57437
-const MOD = 57438
-const POW = 57439
+const SUBQUERY_RANGE = 57353
+const MATCHERS = 57354
+const LABELS = 57355
+const EQ = 57356
+const RE = 57357
+const NRE = 57358
+const NPA = 57359
+const OPEN_BRACE = 57360
+const CLOSE_BRACE = 57361
+const OPEN_BRACKET = 57362
+const CLOSE_BRACKET = 57363
+const COMMA = 57364
+const DOT = 57365
+const PIPE_MATCH = 57366
+const PIPE_EXACT = 57367
+const PIPE_PATTERN = 57368
+const OPEN_PARENTHESIS = 57369
+const CLOSE_PARENTHESIS = 57370
+const BY = 57371
+const WITHOUT = 57372
+const COUNT_OVER_TIME = 57373
+const RATE = 57374
+const RATE_COUNTER = 57375
+const SUM = 57376
+const SORT = 57377
+const SORT_DESC = 57378
+const AVG = 57379
+const MAX = 57380
+const MIN = 57381
+const COUNT = 57382
+const STDDEV = 57383
+const STDVAR = 57384
+const BOTTOMK = 57385
+const TOPK = 57386
+const APPROX_TOPK = 57387
+const APPROX_COUNT_DISTINCT = 57388
+const HISTOGRAM_QUANTILE = 57389
+const BYTES_OVER_TIME = 57390
+const BYTES_RATE = 57391
+const BOOL = 57392
+const JSON = 57393
+const REGEXP = 57394
+const LOGFMT = 57395
+const PIPE = 57396
+const LINE_FMT = 57397
+const LABEL_FMT = 57398
+const UNWRAP = 57399
+const AVG_OVER_TIME = 57400
+const SUM_OVER_TIME = 57401
+const MIN_OVER_TIME = 57402
+const MAX_OVER_TIME = 57403
+const STDVAR_OVER_TIME = 57404
+const STDDEV_OVER_TIME = 57405
+const QUANTILE_OVER_TIME = 57406
+const BYTES_CONV = 57407
+const DURATION_CONV = 57408
+const DURATION_SECONDS_CONV = 57409
+const FIRST_OVER_TIME = 57410
+const LAST_OVER_TIME = 57411
+const ABSENT_OVER_TIME = 57412
+const APPROX_COUNT_DISTINCT_OVER_TIME = 57413
+const HISTOGRAM_OVER_TIME = 57414
+const VECTOR = 57415
+const LABEL_REPLACE = 57416
+const UNPACK = 57417
+const OFFSET = 57418
+const PATTERN = 57419
+const IP = 57420
+const ON = 57421
+const IGNORING = 57422
+const GROUP_LEFT = 57423
+const GROUP_RIGHT = 57424
+const DECOLORIZE = 57425
+const DROP = 57426
+const KEEP = 57427
+const VARIANTS = 57428
+const OF = 57429
+const REDACT = 57430
+const SAMPLE = 57431
+const LABEL_JOIN = 57432
+const ABS = 57433
+const CEIL = 57434
+const FLOOR = 57435
+const ROUND = 57436
+const CLAMP_MIN = 57437
+const CLAMP_MAX = 57438
+const SQRT = 57439
+const LN = 57440
+const EXP = 57441
+const TIMESTAMP = 57442
+const HOUR = 57443
+const DAY_OF_WEEK = 57444
+const CHANGES = 57445
+const DERIV = 57446
+const PREDICT_LINEAR = 57447
+const HOLT_WINTERS = 57448
+const COUNT_VALUES = 57449
+const QUANTILE = 57450
+const GROUP = 57451
+const LIMITK = 57452
+const LIMIT_RATIO = 57453
+const XML = 57454
+const CSV = 57455
+const CONTEXT = 57456
+const LOOKUP = 57457
+const DEDUP = 57458
+const OR = 57459
+const AND = 57460
+const UNLESS = 57461
+const CMP_EQ = 57462
+const NEQ = 57463
+const LT = 57464
+const LTE = 57465
+const GT = 57466
+const GTE = 57467
+const ADD = 57468
+const SUB = 57469
+const MUL = 57470
+const DIV = 57471
+const MOD = 57472
+const POW = 57473
 
 var syntaxToknames = [...]string{
 	"$end",
@@ -153,6 +189,7 @@ var syntaxToknames = [...]string{
 	"FUNCTION_FLAG",
 	"DURATION",
 	"RANGE",
+	"SUBQUERY_RANGE",
 	"MATCHERS",
 	"LABELS",
 	"EQ",
@@ -187,6 +224,8 @@ var syntaxToknames = [...]string{
 	"BOTTOMK",
 	"TOPK",
 	"APPROX_TOPK",
+	"APPROX_COUNT_DISTINCT",
+	"HISTOGRAM_QUANTILE",
 	"BYTES_OVER_TIME",
 	"BYTES_RATE",
 	"BOOL",
@@ -210,6 +249,8 @@ var syntaxToknames = [...]string{
 	"FIRST_OVER_TIME",
 	"LAST_OVER_TIME",
 	"ABSENT_OVER_TIME",
+	"APPROX_COUNT_DISTINCT_OVER_TIME",
+	"HISTOGRAM_OVER_TIME",
 	"VECTOR",
 	"LABEL_REPLACE",
 	"UNPACK",
@@ -225,6 +266,35 @@ var syntaxToknames = [...]string{
 	"KEEP",
 	"VARIANTS",
 	"OF",
+	"REDACT",
+	"SAMPLE",
+	"LABEL_JOIN",
+	"ABS",
+	"CEIL",
+	"FLOOR",
+	"ROUND",
+	"CLAMP_MIN",
+	"CLAMP_MAX",
+	"SQRT",
+	"LN",
+	"EXP",
+	"TIMESTAMP",
+	"HOUR",
+	"DAY_OF_WEEK",
+	"CHANGES",
+	"DERIV",
+	"PREDICT_LINEAR",
+	"HOLT_WINTERS",
+	"COUNT_VALUES",
+	"QUANTILE",
+	"GROUP",
+	"LIMITK",
+	"LIMIT_RATIO",
+	"XML",
+	"CSV",
+	"CONTEXT",
+	"LOOKUP",
+	"DEDUP",
 	"OR",
 	"AND",
 	"UNLESS",
@@ -241,189 +311,271 @@ var syntaxToknames = [...]string{
 	"MOD",
 	"POW",
 }
+
 var syntaxStatenames = [...]string{}
 
 const syntaxEofCode = 1
 const syntaxErrCode = 2
 const syntaxInitialStackSize = 16
 
-var syntaxExca = [...]int{
+var syntaxExca = [...]int16{
 	-1, 1,
 	1, -1,
 	-2, 0,
-	-1, 150,
-	21, 227,
-	27, 227,
+	-1, 195,
+	22, 300,
+	28, 300,
 	-2, 3,
-	-1, 290,
-	21, 228,
-	27, 228,
+	-1, 370,
+	22, 301,
+	28, 301,
 	-2, 3,
 }
 
 const syntaxPrivate = 57344
 
-const syntaxLast = 642
-
-var syntaxAct = [...]int{
-
-	293, 232, 88, 4, 217, 67, 130, 6, 188, 206,
-	158, 79, 203, 195, 66, 241, 205, 193, 80, 2,
-	59, 286, 84, 51, 52, 53, 60, 61, 64, 65,
-	62, 63, 54, 55, 56, 57, 58, 59, 143, 218,
-	11, 52, 53, 60, 61, 64, 65, 62, 63, 54,
-	55, 56, 57, 58, 59, 60, 61, 64, 65, 62,
-	63, 54, 55, 56, 57, 58, 59, 56, 57, 58,
-	59, 289, 113, 144, 210, 156, 157, 296, 119, 54,
-	55, 56, 57, 58, 59, 150, 301, 154, 156, 157,
-	269, 162, 225, 18, 160, 268, 265, 167, 224, 18,
-	284, 264, 219, 18, 281, 283, 146, 18, 278, 280,
-	98, 18, 70, 277, 169, 172, 173, 298, 174, 175,
-	176, 177, 178, 179, 180, 181, 182, 183, 184, 185,
-	186, 187, 275, 170, 171, 18, 272, 274, 371, 18,
-	146, 271, 371, 200, 197, 208, 208, 216, 211, 214,
-	215, 212, 213, 343, 297, 140, 209, 392, 296, 267,
-	223, 155, 228, 343, 387, 263, 297, 310, 239, 235,
-	140, 190, 236, 360, 145, 233, 134, 228, 19, 20,
-	374, 114, 244, 350, 19, 20, 190, 380, 19, 20,
-	379, 134, 19, 20, 298, 298, 19, 20, 252, 253,
-	254, 344, 335, 299, 298, 75, 77, 298, 75, 77,
-	256, 89, 90, 72, 73, 74, 72, 73, 74, 378,
-	19, 20, 228, 243, 19, 20, 290, 87, 368, 89,
-	90, 376, 291, 294, 189, 300, 228, 303, 160, 113,
-	306, 292, 307, 119, 234, 320, 295, 305, 191, 189,
-	304, 266, 270, 273, 276, 279, 282, 285, 346, 347,
-	348, 229, 310, 363, 314, 316, 319, 321, 359, 208,
-	299, 322, 328, 324, 353, 75, 77, 76, 140, 333,
-	76, 243, 308, 72, 73, 74, 310, 351, 140, 247,
-	310, 331, 358, 243, 190, 336, 357, 338, 340, 134,
-	342, 113, 243, 318, 140, 243, 352, 341, 337, 134,
-	113, 234, 310, 354, 237, 317, 310, 222, 312, 243,
-	190, 148, 311, 221, 315, 134, 259, 245, 147, 334,
-	159, 126, 127, 125, 330, 135, 137, 301, 365, 366,
-	15, 242, 160, 113, 367, 364, 140, 76, 15, 161,
-	369, 370, 329, 128, 287, 129, 375, 161, 251, 250,
-	249, 136, 138, 139, 231, 18, 248, 134, 220, 75,
-	77, 382, 166, 383, 384, 15, 165, 72, 73, 74,
-	261, 302, 191, 189, 7, 164, 388, 94, 23, 24,
-	25, 38, 47, 48, 39, 41, 42, 40, 43, 44,
-	45, 46, 49, 26, 27, 234, 93, 86, 81, 390,
-	386, 356, 152, 28, 29, 30, 31, 32, 33, 34,
-	257, 309, 262, 35, 36, 37, 50, 21, 151, 231,
-	240, 153, 260, 246, 75, 77, 238, 75, 77, 14,
-	15, 76, 72, 73, 74, 72, 73, 74, 230, 7,
-	19, 20, 258, 23, 24, 25, 38, 47, 48, 39,
-	41, 42, 40, 43, 44, 45, 46, 49, 26, 27,
-	234, 385, 85, 234, 373, 372, 349, 339, 28, 29,
-	30, 31, 32, 33, 34, 83, 326, 327, 35, 36,
-	37, 50, 21, 296, 196, 163, 196, 255, 391, 194,
-	3, 168, 75, 77, 14, 15, 76, 92, 78, 76,
-	72, 73, 74, 91, 7, 19, 20, 389, 23, 24,
-	25, 38, 47, 48, 39, 41, 42, 40, 43, 44,
-	45, 46, 49, 26, 27, 377, 362, 361, 234, 332,
-	323, 149, 313, 28, 29, 30, 31, 32, 33, 34,
-	75, 77, 140, 35, 36, 37, 50, 21, 72, 73,
-	74, 325, 288, 227, 204, 202, 226, 225, 224, 14,
-	201, 199, 198, 134, 76, 95, 381, 355, 207, 196,
-	19, 20, 85, 204, 97, 96, 69, 192, 22, 82,
-	71, 131, 132, 141, 133, 126, 127, 125, 142, 135,
-	137, 17, 345, 16, 68, 124, 123, 122, 121, 120,
-	118, 117, 116, 115, 5, 13, 12, 128, 10, 129,
-	9, 8, 76, 1, 0, 136, 138, 139, 99, 100,
-	101, 102, 103, 104, 105, 106, 107, 108, 109, 110,
-	111, 112,
+const syntaxLast = 1190
+
+var syntaxAct = [...]int16{
+	300, 381, 96, 240, 253, 280, 6, 170, 4, 261,
+	258, 95, 247, 303, 265, 117, 108, 205, 269, 245,
+	3, 293, 260, 109, 2, 113, 88, 20, 107, 80,
+	81, 82, 89, 90, 93, 94, 91, 92, 83, 84,
+	85, 86, 87, 88, 85, 86, 87, 88, 11, 81,
+	82, 89, 90, 93, 94, 91, 92, 83, 84, 85,
+	86, 87, 88, 89, 90, 93, 94, 91, 92, 83,
+	84, 85, 86, 87, 88, 83, 84, 85, 86, 87,
+	88, 366, 188, 273, 201, 202, 364, 99, 185, 20,
+	361, 363, 369, 20, 282, 360, 224, 225, 144, 483,
+	342, 349, 152, 288, 20, 242, 348, 222, 223, 345,
+	174, 287, 20, 358, 344, 384, 20, 387, 357, 195,
+	386, 384, 206, 129, 208, 209, 199, 201, 202, 203,
+	483, 216, 217, 218, 355, 281, 445, 20, 352, 354,
+	339, 20, 116, 351, 118, 119, 21, 22, 189, 516,
+	221, 185, 185, 509, 226, 227, 228, 229, 230, 231,
+	232, 233, 234, 235, 236, 237, 238, 239, 242, 242,
+	333, 104, 106, 174, 174, 347, 514, 438, 255, 101,
+	102, 103, 385, 343, 249, 145, 263, 263, 252, 279,
+	274, 277, 278, 275, 276, 190, 447, 448, 449, 272,
+	243, 241, 264, 118, 119, 338, 286, 191, 21, 22,
+	505, 466, 21, 22, 302, 291, 108, 185, 298, 338,
+	191, 386, 309, 21, 22, 465, 386, 297, 107, 313,
+	315, 21, 22, 200, 242, 21, 22, 379, 495, 174,
+	334, 496, 338, 104, 106, 326, 327, 328, 464, 494,
+	266, 101, 102, 103, 438, 450, 21, 22, 104, 106,
+	21, 22, 291, 379, 330, 241, 101, 102, 103, 104,
+	106, 291, 480, 412, 338, 385, 105, 101, 102, 103,
+	463, 301, 294, 338, 338, 373, 373, 340, 292, 402,
+	401, 378, 372, 453, 370, 490, 301, 434, 386, 380,
+	382, 144, 206, 390, 208, 152, 392, 301, 371, 375,
+	266, 383, 377, 376, 388, 394, 488, 396, 384, 386,
+	486, 393, 346, 350, 353, 356, 359, 362, 365, 243,
+	241, 470, 266, 410, 266, 291, 299, 406, 408, 411,
+	413, 266, 104, 106, 414, 263, 417, 421, 105, 185,
+	101, 102, 103, 320, 389, 409, 266, 407, 462, 319,
+	294, 391, 285, 105, 316, 456, 296, 17, 284, 405,
+	454, 174, 428, 435, 105, 430, 473, 513, 397, 314,
+	301, 437, 439, 185, 441, 321, 144, 443, 432, 451,
+	444, 144, 305, 440, 436, 164, 165, 163, 193, 175,
+	177, 387, 192, 478, 455, 174, 431, 457, 427, 459,
+	426, 367, 325, 324, 323, 322, 283, 215, 214, 166,
+	213, 167, 125, 124, 123, 122, 115, 176, 178, 179,
+	110, 507, 180, 181, 506, 197, 461, 460, 472, 458,
+	476, 477, 331, 144, 400, 471, 398, 105, 395, 338,
+	474, 475, 196, 482, 481, 198, 168, 169, 182, 183,
+	184, 337, 299, 335, 333, 318, 317, 489, 104, 106,
+	485, 308, 487, 306, 114, 295, 101, 102, 103, 341,
+	498, 500, 502, 304, 497, 336, 503, 332, 112, 501,
+	484, 479, 312, 310, 452, 433, 442, 424, 380, 390,
+	144, 399, 504, 510, 17, 248, 301, 515, 329, 451,
+	508, 144, 512, 7, 419, 420, 254, 27, 28, 29,
+	48, 57, 58, 49, 51, 52, 50, 53, 54, 55,
+	56, 59, 60, 61, 30, 31, 248, 374, 511, 246,
+	307, 267, 220, 121, 32, 33, 34, 35, 36, 37,
+	38, 120, 493, 492, 39, 40, 41, 42, 43, 79,
+	23, 104, 106, 491, 469, 468, 429, 416, 415, 101,
+	102, 103, 16, 105, 404, 403, 24, 67, 68, 69,
+	70, 71, 72, 73, 74, 75, 76, 77, 78, 44,
+	45, 46, 47, 62, 63, 64, 65, 66, 20, 301,
+	418, 368, 290, 259, 194, 289, 104, 106, 288, 17,
+	287, 256, 21, 311, 101, 102, 103, 251, 7, 219,
+	250, 499, 27, 28, 29, 48, 57, 58, 49, 51,
+	52, 50, 53, 54, 55, 56, 59, 60, 61, 30,
+	31, 467, 425, 423, 98, 422, 262, 248, 270, 32,
+	33, 34, 35, 36, 37, 38, 114, 266, 271, 39,
+	40, 41, 42, 43, 79, 23, 105, 259, 257, 128,
+	127, 268, 244, 26, 111, 100, 171, 16, 172, 186,
+	173, 24, 67, 68, 69, 70, 71, 72, 73, 74,
+	75, 76, 77, 78, 44, 45, 46, 47, 62, 63,
+	64, 65, 66, 212, 210, 187, 25, 19, 446, 18,
+	97, 105, 162, 161, 160, 17, 159, 21, 22, 158,
+	157, 156, 155, 154, 7, 153, 151, 150, 27, 28,
+	29, 48, 57, 58, 49, 51, 52, 50, 53, 54,
+	55, 56, 59, 60, 61, 30, 31, 149, 148, 147,
+	146, 5, 15, 14, 13, 32, 33, 34, 35, 36,
+	37, 38, 12, 10, 9, 39, 40, 41, 42, 43,
+	79, 23, 8, 1, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 16, 0, 0, 0, 24, 67, 68,
+	69, 70, 71, 72, 73, 74, 75, 76, 77, 78,
+	44, 45, 46, 47, 62, 63, 64, 65, 66, 20,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	17, 0, 0, 21, 211, 0, 0, 0, 0, 7,
+	0, 0, 0, 27, 28, 29, 48, 57, 58, 49,
+	51, 52, 50, 53, 54, 55, 56, 59, 60, 61,
+	30, 31, 0, 0, 0, 0, 0, 0, 0, 0,
+	32, 33, 34, 35, 36, 37, 38, 0, 0, 0,
+	39, 40, 41, 42, 43, 79, 23, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 16, 0,
+	0, 0, 24, 67, 68, 69, 70, 71, 72, 73,
+	74, 75, 76, 77, 78, 44, 45, 46, 47, 62,
+	63, 64, 65, 66, 20, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 17, 0, 0, 21, 22,
+	0, 0, 0, 0, 207, 0, 0, 0, 27, 28,
+	29, 48, 57, 58, 49, 51, 52, 50, 53, 54,
+	55, 56, 59, 60, 61, 30, 31, 0, 0, 0,
+	0, 0, 0, 0, 0, 32, 33, 34, 35, 36,
+	37, 38, 0, 0, 0, 39, 40, 41, 42, 43,
+	79, 23, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 16, 0, 0, 0, 24, 67, 68,
+	69, 70, 71, 72, 73, 74, 75, 76, 77, 78,
+	44, 45, 46, 47, 62, 63, 64, 65, 66, 204,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	17, 0, 0, 21, 22, 0, 0, 0, 0, 207,
+	0, 0, 0, 27, 28, 29, 48, 57, 58, 49,
+	51, 52, 50, 53, 54, 55, 56, 59, 60, 61,
+	30, 31, 0, 0, 0, 0, 0, 0, 0, 0,
+	32, 33, 34, 35, 36, 37, 38, 0, 185, 0,
+	39, 40, 41, 42, 43, 79, 23, 126, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 16, 0,
+	174, 0, 24, 67, 68, 69, 70, 71, 72, 73,
+	74, 75, 76, 77, 78, 44, 45, 46, 47, 62,
+	63, 64, 65, 66, 164, 165, 163, 0, 175, 177,
+	0, 0, 0, 0, 0, 0, 0, 0, 21, 22,
+	0, 0, 0, 0, 0, 0, 0, 0, 166, 0,
+	167, 0, 0, 0, 0, 0, 176, 178, 179, 0,
+	0, 180, 181, 0, 0, 0, 0, 0, 0, 130,
+	131, 132, 133, 134, 135, 136, 137, 138, 139, 140,
+	141, 142, 143, 0, 0, 168, 169, 182, 183, 184,
 }
-var syntaxPact = [...]int{
 
-	358, -1000, -60, -1000, -1000, -1000, 535, 358, -1000, -1000,
-	-1000, -1000, -1000, -1000, 382, 467, 381, 201, -1000, 506,
-	500, 380, 361, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+var syntaxPact = [...]int16{
+	802, -1000, -88, -1000, -1000, -1000, 590, 802, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, 403, 469, 399, 115,
+	-1000, 544, 536, 398, 397, 396, 395, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, 63, 63, 63, 63, 63, 63, 63, 63, 63,
-	63, 63, 63, 63, 63, 63, 535, -1000, 190, 547,
-	-45, 67, -1000, -1000, -1000, -1000, -1000, -1000, 301, 294,
-	-60, 358, 410, -1000, -1000, 74, 323, 488, 359, 350,
-	346, -1000, -1000, 358, 494, 358, 59, 39, -1000, 358,
-	358, 358, 358, 358, 358, 358, 358, 358, 358, 358,
-	358, 358, 358, -1000, -45, -1000, -1000, -1000, -1000, 165,
-	-1000, -1000, -1000, -1000, -1000, 491, 574, 566, -1000, 565,
-	-1000, -1000, -1000, -1000, 341, 564, -1000, 578, 573, 573,
-	61, -1000, -1000, 33, -1000, 342, -1000, -1000, -1000, 296,
-	-1000, -1000, -1000, 577, 562, 561, 560, 557, 234, 427,
-	419, 331, 287, 415, 423, 314, 300, 412, 262, -43,
-	340, 334, 333, 332, -31, -31, -27, -27, -77, -77,
-	-77, -77, -13, -13, -13, -13, -13, -13, 165, 341,
-	341, 341, 489, 399, -1000, -1000, 439, 399, -1000, -1000,
-	299, -1000, 411, -1000, 367, 401, -1000, 74, -1000, 401,
-	92, 86, 132, 128, 104, 100, 96, -1000, -62, 328,
-	556, -11, 358, -1000, -1000, -1000, -1000, -1000, -1000, 183,
-	331, 422, 144, 193, 283, 354, 220, 183, 358, 255,
-	400, 295, -1000, -1000, 291, -1000, 536, -1000, 297, 288,
-	276, 218, 273, 165, 150, -1000, 399, 574, 534, -1000,
-	559, 481, 573, 326, -1000, -1000, -1000, 308, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, 33, 533, 252, 303,
-	-1000, -1000, 175, 487, 66, 487, 468, 6, 341, 6,
-	143, 196, 466, 156, 260, -1000, -1000, 247, -1000, 358,
-	572, -1000, -1000, 390, 269, -1000, 265, -1000, -1000, 241,
-	-1000, 146, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 531,
-	530, -1000, 236, -1000, 331, 183, 66, 487, 66, -1000,
-	-1000, 165, -1000, 6, -1000, 202, -1000, -1000, -1000, 87,
-	465, 464, 153, 183, 204, -1000, 529, -1000, -1000, -1000,
-	-1000, 192, 163, -1000, 160, -1000, 66, -1000, 571, 91,
-	66, 32, 6, 6, 461, -1000, -1000, 389, -1000, -1000,
-	-1000, 137, 66, -1000, -1000, 6, 511, -1000, -1000, 388,
-	492, 130, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	73, 73, 73, 73, 73, 73, 73, 73, 73, 73,
+	73, 73, 73, 73, 73, 590, -1000, 155, 1073, -35,
+	142, -1000, -1000, -1000, -1000, -1000, -1000, 374, 370, -88,
+	802, 433, -1000, -1000, 112, 1012, 697, 393, 391, 390,
+	-1000, -1000, 802, 802, 591, 535, 802, 28, 15, -1000,
+	802, 802, 802, 802, 802, 802, 802, 802, 802, 802,
+	802, 802, 802, 802, -1000, -35, -1000, -1000, -1000, -1000,
+	-1000, -1000, 83, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, 531, 642, 614, -1000, 611, 642, 510,
+	-1000, -1000, -1000, -1000, 378, 605, -1000, 662, 641, 641,
+	652, 534, 643, 653, 652, 69, -1000, -1000, 129, -1000,
+	389, -1000, -1000, -1000, 340, -1000, -1000, -1000, 651, 604,
+	602, 599, 596, 260, 453, 338, 452, 907, 472, 364,
+	451, 533, 449, 486, 351, 336, 444, 443, 331, -1000,
+	357, -69, 388, 387, 386, 385, -57, -57, -84, -84,
+	-105, -105, -105, -105, -51, -51, -51, -51, -51, -51,
+	83, 378, 378, 378, 500, 420, -1000, -1000, 473, 420,
+	-1000, -1000, 420, 442, -1000, 212, -1000, 441, -1000, 471,
+	439, -1000, 112, -1000, 439, 427, -1000, 111, 643, -1000,
+	465, 21, 427, 105, 97, 134, 130, 109, 86, 82,
+	-1000, -36, 384, 595, 5, 802, -1000, -1000, -1000, -1000,
+	-1000, -1000, 174, 264, 530, 907, 174, 263, 253, 242,
+	172, 344, 326, 333, 39, 174, 802, 426, 802, 350,
+	424, 494, 422, 262, -1000, 261, -1000, 569, 568, -1000,
+	20, -1000, 329, 327, 305, 245, 146, 83, 147, -1000,
+	420, 642, 562, 561, -1000, 598, 509, 641, 640, 638,
+	-1000, 490, 637, 383, -1000, -1000, -1000, 381, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, 129, 560, 347, 379,
+	-1000, -1000, 174, 488, -1000, 269, 345, -1000, 174, 39,
+	167, 545, 66, 545, 487, 39, 378, 131, 227, 484,
+	265, -1000, -1000, -1000, 342, 802, 337, -1000, 802, 417,
+	802, -1000, -1000, 415, 414, 330, 252, -1000, 220, -1000,
+	-1000, 197, -1000, 183, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, 636, 559, 558, -1000, 303,
+	-1000, 349, -1000, -1000, 174, 174, -1000, -1000, 39, 66,
+	545, 66, -1000, -1000, 83, -1000, 376, -1000, -1000, -1000,
+	481, 244, 45, 480, 174, 292, 174, 288, 802, 267,
+	557, 547, -1000, -1000, -1000, -1000, -1000, 546, 221, 210,
+	-1000, 213, 452, 349, -1000, -1000, -1000, 66, 616, 39,
+	479, 76, 66, 60, 39, -1000, 174, -1000, -1000, 182,
+	-1000, 412, 409, -1000, -1000, -1000, -1000, 253, 326, 125,
+	-1000, 39, 66, -1000, -1000, -1000, 532, 510, 227, -1000,
+	-1000, 355, 148, 501, -1000, 121, -1000,
 }
-var syntaxPgo = [...]int{
-
-	0, 623, 18, 500, 3, 621, 620, 618, 616, 615,
-	614, 5, 613, 612, 611, 610, 609, 608, 607, 606,
-	605, 14, 112, 604, 4, 603, 602, 601, 102, 598,
-	594, 593, 8, 592, 591, 590, 6, 589, 7, 588,
-	15, 587, 575, 585, 584, 9, 16, 12, 565, 2,
-	10, 40, 13, 17, 1, 0, 541,
+
+var syntaxPgo = [...]int16{
+	0, 773, 23, 20, 8, 772, 764, 763, 762, 754,
+	753, 752, 751, 2, 750, 749, 748, 747, 727, 726,
+	725, 723, 722, 721, 720, 719, 716, 714, 713, 712,
+	11, 87, 710, 5, 709, 708, 707, 94, 706, 705,
+	680, 679, 3, 678, 676, 675, 7, 674, 6, 673,
+	14, 672, 4, 21, 18, 671, 1087, 670, 669, 9,
+	22, 10, 668, 15, 13, 48, 12, 19, 0, 1,
+	17, 604,
 }
-var syntaxR1 = [...]int{
 
+var syntaxR1 = [...]int8{
 	0, 1, 2, 2, 2, 3, 3, 3, 4, 4,
-	4, 4, 4, 4, 4, 10, 50, 50, 50, 50,
-	50, 50, 50, 50, 50, 50, 50, 50, 50, 50,
-	50, 50, 50, 50, 50, 50, 50, 50, 50, 50,
-	50, 50, 54, 54, 54, 26, 26, 26, 5, 5,
-	5, 5, 6, 6, 6, 6, 6, 6, 8, 38,
-	38, 38, 37, 37, 36, 36, 36, 36, 21, 21,
-	11, 11, 11, 11, 11, 11, 11, 11, 11, 11,
-	11, 35, 35, 35, 35, 35, 35, 28, 24, 24,
-	24, 22, 22, 22, 23, 23, 41, 41, 12, 12,
-	13, 13, 13, 13, 14, 15, 15, 16, 17, 47,
-	47, 48, 48, 48, 18, 32, 32, 32, 32, 32,
-	32, 32, 32, 32, 52, 52, 53, 53, 34, 34,
-	33, 33, 31, 31, 31, 31, 31, 31, 31, 29,
-	29, 29, 29, 29, 29, 29, 30, 30, 30, 30,
-	30, 30, 30, 45, 45, 46, 46, 19, 20, 7,
+	4, 4, 4, 4, 4, 4, 4, 12, 64, 64,
+	64, 64, 64, 64, 64, 64, 64, 64, 64, 64,
+	64, 64, 64, 64, 64, 64, 64, 64, 64, 64,
+	64, 64, 64, 64, 70, 70, 68, 68, 68, 35,
+	35, 35, 5, 5, 5, 5, 5, 5, 5, 5,
+	5, 5, 5, 5, 53, 53, 6, 6, 6, 6,
+	6, 6, 6, 6, 6, 6, 6, 6, 8, 9,
+	52, 52, 10, 10, 10, 38, 38, 38, 38, 38,
+	38, 38, 38, 38, 38, 38, 38, 48, 48, 48,
+	47, 47, 46, 46, 46, 46, 30, 30, 13, 13,
+	13, 13, 13, 13, 13, 13, 13, 13, 13, 13,
+	13, 13, 13, 13, 13, 13, 45, 45, 45, 45,
+	45, 45, 37, 33, 33, 33, 31, 31, 31, 32,
+	32, 51, 51, 14, 14, 15, 15, 15, 15, 15,
+	16, 17, 17, 18, 19, 19, 20, 21, 25, 25,
+	26, 26, 27, 55, 55, 54, 28, 28, 29, 61,
+	61, 62, 62, 62, 22, 42, 42, 42, 42, 42,
+	42, 42, 42, 42, 66, 66, 67, 67, 44, 44,
+	43, 43, 41, 41, 41, 41, 41, 41, 41, 39,
+	39, 39, 39, 39, 39, 39, 40, 40, 40, 40,
+	40, 40, 40, 59, 59, 60, 60, 23, 24, 7,
 	7, 7, 7, 7, 7, 7, 7, 7, 7, 7,
-	7, 7, 7, 7, 43, 43, 44, 44, 44, 44,
-	42, 42, 42, 42, 42, 42, 42, 42, 51, 51,
-	51, 9, 39, 27, 27, 27, 27, 27, 27, 27,
-	27, 27, 27, 27, 27, 25, 25, 25, 25, 25,
-	25, 25, 25, 25, 25, 25, 25, 25, 25, 25,
-	55, 40, 40, 49, 49, 49, 49, 56, 56,
+	7, 7, 7, 7, 57, 57, 58, 58, 58, 58,
+	56, 56, 56, 56, 56, 56, 56, 56, 65, 65,
+	65, 11, 49, 36, 36, 36, 36, 36, 36, 36,
+	36, 36, 36, 36, 36, 36, 36, 36, 36, 36,
+	36, 36, 34, 34, 34, 34, 34, 34, 34, 34,
+	34, 34, 34, 34, 34, 34, 34, 34, 34, 34,
+	34, 34, 34, 69, 50, 50, 63, 63, 63, 63,
+	71, 71,
 }
-var syntaxR2 = [...]int{
 
+var syntaxR2 = [...]int8{
 	0, 1, 1, 1, 1, 1, 2, 3, 1, 1,
-	1, 1, 1, 1, 3, 8, 2, 3, 4, 5,
-	3, 4, 5, 6, 3, 4, 5, 6, 3, 4,
-	5, 6, 4, 5, 6, 7, 3, 4, 4, 5,
-	3, 2, 3, 6, 3, 1, 1, 1, 4, 6,
-	5, 7, 4, 5, 5, 6, 7, 7, 12, 3,
-	3, 2, 1, 3, 3, 3, 3, 3, 1, 2,
-	1, 2, 2, 2, 2, 2, 2, 2, 2, 2,
-	2, 1, 1, 1, 1, 1, 1, 1, 1, 3,
-	4, 2, 5, 3, 1, 2, 1, 2, 1, 2,
-	1, 2, 1, 2, 2, 3, 2, 2, 1, 3,
+	1, 1, 1, 1, 1, 1, 3, 8, 2, 3,
+	4, 5, 3, 4, 5, 6, 3, 4, 5, 6,
+	3, 4, 5, 6, 4, 5, 6, 7, 3, 4,
+	4, 5, 3, 2, 2, 3, 3, 6, 3, 1,
+	1, 1, 4, 6, 5, 7, 4, 6, 5, 7,
+	5, 6, 5, 6, 2, 3, 4, 5, 5, 6,
+	7, 7, 7, 8, 8, 6, 7, 7, 12, 10,
+	1, 3, 4, 6, 3, 1, 1, 1, 1, 1,
+	1, 1, 1, 1, 1, 1, 1, 3, 3, 2,
+	1, 3, 3, 3, 3, 3, 1, 2, 1, 2,
+	2, 2, 2, 2, 2, 2, 2, 2, 2, 2,
+	2, 2, 2, 2, 2, 2, 1, 1, 1, 1,
+	1, 1, 1, 1, 3, 4, 2, 5, 3, 1,
+	2, 1, 2, 1, 2, 1, 2, 1, 2, 1,
+	2, 3, 2, 2, 1, 2, 2, 1, 1, 2,
+	2, 4, 2, 1, 2, 3, 4, 6, 2, 3,
 	3, 1, 3, 3, 2, 1, 1, 1, 1, 3,
 	2, 3, 3, 3, 3, 1, 1, 3, 6, 6,
 	1, 1, 3, 3, 3, 3, 3, 3, 3, 3,
@@ -435,100 +587,126 @@ var syntaxR2 = [...]int{
 	2, 4, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	2, 1, 3, 4, 4, 3, 3, 1, 3,
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+	1, 1, 1, 2, 1, 3, 4, 4, 3, 3,
+	1, 3,
 }
-var syntaxChk = [...]int{
-
-	-1000, -1, -2, -3, -4, -10, -38, 26, -5, -6,
-	-7, -51, -8, -9, 81, 17, -25, -27, 7, 92,
-	93, 69, -39, 30, 31, 32, 45, 46, 55, 56,
-	57, 58, 59, 60, 61, 65, 66, 67, 33, 36,
-	39, 37, 38, 40, 41, 42, 43, 34, 35, 44,
-	68, 83, 84, 85, 92, 93, 94, 95, 96, 97,
-	86, 87, 90, 91, 88, 89, -21, -11, -23, 51,
-	-22, -35, 23, 24, 25, 15, 87, 16, -3, -4,
-	-2, 26, -37, 18, -36, 5, 26, 26, -49, 28,
-	29, 7, 7, 26, 26, -42, -43, -44, 47, -42,
-	-42, -42, -42, -42, -42, -42, -42, -42, -42, -42,
-	-42, -42, -42, -11, -22, -12, -13, -14, -15, -32,
-	-16, -17, -18, -19, -20, 50, 48, 49, 70, 72,
-	-36, -34, -33, -30, 26, 52, 78, 53, 79, 80,
-	5, -31, -29, 83, 6, -28, 73, 27, 27, -56,
-	-4, 18, 2, 21, 13, 87, 14, 15, -50, 7,
-	-38, 26, -4, 7, 26, 26, 26, -4, 7, -2,
-	74, 75, 76, 77, -2, -2, -2, -2, -2, -2,
-	-2, -2, -2, -2, -2, -2, -2, -2, -32, 84,
-	21, 83, -41, -53, 8, -52, 5, -53, 6, 6,
-	-32, 6, -48, -47, 5, -46, -45, 5, -36, -46,
-	13, 87, 90, 91, 88, 89, 86, -24, 6, -28,
-	26, 27, 21, -36, 6, 6, 6, 6, 2, 27,
-	21, 10, -54, -21, 51, -38, -50, 27, 21, -4,
-	7, -40, 27, 5, -40, 27, 21, 27, 26, 26,
-	26, 26, -32, -32, -32, 8, -53, 21, 13, 27,
-	21, 13, 21, 73, 9, 4, -51, 73, 9, 4,
-	-51, 9, 4, -51, 9, 4, -51, 9, 4, -51,
-	9, 4, -51, 9, 4, -51, 83, 26, 6, 82,
-	-4, -49, -50, -55, -54, -21, 71, 10, 51, 10,
-	-54, 54, 27, -54, -21, 27, -49, -4, 27, 21,
-	21, 27, 27, 6, -40, 27, -40, 27, 27, -40,
-	27, -40, -52, 6, -47, 2, 5, 6, -45, 26,
-	26, -24, 6, 27, 26, 27, -54, -21, -54, 9,
-	-55, -32, -55, 10, 5, -26, 62, 63, 64, 10,
-	27, 27, -54, 27, -4, 5, 21, 27, 27, 27,
-	27, 6, 6, 27, -50, -49, -54, -55, 26, -55,
-	-54, 51, 10, 10, 27, -49, 27, 6, 27, 27,
-	27, 5, -54, -55, -55, 10, 21, 27, -55, 6,
-	21, 6, 27,
+
+var syntaxChk = [...]int16{
+	-1000, -1, -2, -3, -4, -12, -48, 27, -5, -6,
+	-7, -65, -8, -9, -10, -11, 86, 18, -34, -36,
+	7, 126, 127, 74, 90, -38, -49, 31, 32, 33,
+	48, 49, 58, 59, 60, 61, 62, 63, 64, 68,
+	69, 70, 71, 72, 103, 104, 105, 106, 34, 37,
+	40, 38, 39, 41, 42, 43, 44, 35, 36, 45,
+	46, 47, 107, 108, 109, 110, 111, 91, 92, 93,
+	94, 95, 96, 97, 98, 99, 100, 101, 102, 73,
+	117, 118, 119, 126, 127, 128, 129, 130, 131, 120,
+	121, 124, 125, 122, 123, -30, -13, -32, 54, -31,
+	-45, 24, 25, 26, 16, 121, 17, -3, -4, -2,
+	27, -47, 19, -46, 5, 27, 27, -63, 29, 30,
+	7, 7, 27, 27, 27, 27, -56, -57, -58, 50,
+	-56, -56, -56, -56, -56, -56, -56, -56, -56, -56,
+	-56, -56, -56, -56, -13, -31, -14, -15, -16, -17,
+	-18, -19, -42, -20, -21, -22, -23, -24, -25, -26,
+	-27, -28, -29, 53, 51, 52, 75, 77, 112, 113,
+	-46, -44, -43, -40, 27, 55, 83, 56, 84, 85,
+	88, 89, 114, 115, 116, 5, -41, -39, 117, 6,
+	-37, 78, 28, 28, -71, -4, 19, 2, 22, 14,
+	121, 15, 16, -64, 7, -70, -48, 27, -4, -4,
+	7, 127, 6, 27, 27, 27, -4, -4, -4, 28,
+	7, -2, 79, 80, 81, 82, -2, -2, -2, -2,
+	-2, -2, -2, -2, -2, -2, -2, -2, -2, -2,
+	-42, 118, 22, 117, -51, -67, 8, -66, 5, -67,
+	6, 6, -67, -52, 6, -42, 6, -62, -61, 5,
+	-60, -59, 5, -46, -60, -50, 5, 7, -55, -54,
+	5, 5, -50, 14, 121, 124, 125, 122, 123, 120,
+	-33, 6, -37, 27, 28, 22, -46, 6, 6, 6,
+	6, 2, 28, -53, 22, 22, 28, -53, -30, 10,
+	-68, 54, -48, -64, 11, 28, 22, 7, 22, -4,
+	7, 127, 6, -50, 28, -50, 28, 22, 22, 28,
+	22, 28, 27, 27, 27, 27, -42, -42, -42, 8,
+	-67, 22, 14, 22, 28, 22, 14, 22, 22, 29,
+	-54, 14, 79, 78, 9, 4, -65, 78, 9, 4,
+	-65, 9, 4, -65, 9, 4, -65, 9, 4, -65,
+	9, 4, -65, 9, 4, -65, 117, 27, 6, 87,
+	-4, -63, 28, 22, 7, -64, -70, -63, 28, 10,
+	-68, -69, -68, -30, 76, 10, 54, 57, -30, 28,
+	-68, 28, -69, -63, -4, 22, -4, 28, 22, 7,
+	22, 28, 28, 6, 6, -65, -50, 28, -50, 28,
+	28, -50, 28, -50, -66, 6, 6, -61, 2, 5,
+	6, -59, 5, 5, 7, 5, 27, 27, -33, 6,
+	28, 27, -63, 7, 28, 28, -63, -69, 10, -68,
+	-30, -68, 9, -69, -42, 5, -35, 65, 66, 67,
+	28, -68, 10, 28, 28, -4, 28, -4, 22, -4,
+	22, 22, 28, 28, 28, 28, 28, 5, 6, 6,
+	28, -64, -48, 27, -63, -63, -69, -68, 27, 10,
+	28, -69, -68, 54, 10, -63, 28, -63, 28, -4,
+	28, 6, 6, 6, 28, 28, 28, -30, -48, 5,
+	-69, 10, -68, -69, -63, 28, 22, 22, -30, 28,
+	-69, 6, -52, 22, 28, 6, 28,
 }
-var syntaxDef = [...]int{
 
+var syntaxDef = [...]int16{
 	0, -2, 1, 2, 3, 4, 5, 0, 8, 9,
-	10, 11, 12, 13, 0, 0, 0, 0, 188, 0,
-	0, 0, 0, 205, 206, 207, 208, 209, 210, 211,
-	212, 213, 214, 215, 216, 217, 218, 219, 193, 194,
-	195, 196, 197, 198, 199, 200, 201, 202, 203, 204,
-	192, 174, 174, 174, 174, 174, 174, 174, 174, 174,
-	174, 174, 174, 174, 174, 174, 6, 68, 70, 0,
-	94, 0, 81, 82, 83, 84, 85, 86, 2, 3,
-	0, 0, 0, 61, 62, 0, 0, 0, 0, 0,
-	0, 189, 190, 0, 0, 0, 180, 181, 175, 0,
+	10, 11, 12, 13, 14, 15, 0, 0, 0, 0,
+	248, 0, 0, 0, 0, 0, 0, 272, 273, 274,
+	275, 276, 277, 278, 279, 280, 281, 282, 283, 284,
+	285, 286, 287, 288, 289, 290, 291, 292, 253, 254,
+	255, 256, 257, 258, 259, 260, 261, 262, 263, 264,
+	265, 266, 267, 268, 269, 270, 271, 85, 86, 87,
+	88, 89, 90, 91, 92, 93, 94, 95, 96, 252,
+	234, 234, 234, 234, 234, 234, 234, 234, 234, 234,
+	234, 234, 234, 234, 234, 6, 106, 108, 0, 139,
+	0, 126, 127, 128, 129, 130, 131, 2, 3, 0,
+	0, 0, 99, 100, 0, 0, 0, 0, 0, 0,
+	249, 250, 0, 0, 0, 0, 0, 240, 241, 235,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 69, 95, 71, 72, 73, 74, 75,
-	76, 77, 78, 79, 80, 98, 100, 0, 102, 0,
-	115, 116, 117, 118, 0, 0, 108, 0, 0, 0,
-	0, 130, 131, 0, 91, 0, 87, 7, 14, 0,
-	-2, 59, 60, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 3, 188, 0, 0, 0, 3, 0, 159,
-	0, 0, 182, 185, 160, 161, 162, 163, 164, 165,
-	166, 167, 168, 169, 170, 171, 172, 173, 120, 0,
-	0, 0, 99, 106, 96, 126, 125, 104, 101, 103,
-	0, 107, 114, 111, 0, 157, 155, 153, 154, 158,
-	0, 0, 0, 0, 0, 0, 0, 93, 88, 0,
-	0, 0, 0, 63, 64, 65, 66, 67, 41, 48,
-	0, 16, 0, 0, 0, 0, 0, 52, 0, 3,
-	188, 0, 225, 221, 0, 226, 0, 191, 0, 0,
-	0, 0, 121, 122, 123, 97, 105, 0, 0, 119,
-	0, 0, 0, 0, 137, 144, 151, 0, 136, 143,
-	150, 132, 139, 146, 133, 140, 147, 134, 141, 148,
-	135, 142, 149, 138, 145, 152, 0, 0, 0, 0,
-	-2, 50, 0, 17, 20, 36, 0, 24, 0, 28,
-	0, 0, 0, 0, 0, 40, 54, 3, 53, 0,
-	0, 223, 224, 0, 0, 177, 0, 179, 183, 0,
-	186, 0, 127, 124, 112, 113, 109, 110, 156, 0,
-	0, 89, 0, 92, 0, 49, 21, 37, 38, 220,
-	25, 44, 29, 32, 42, 0, 45, 46, 47, 18,
-	0, 0, 0, 55, 3, 222, 0, 176, 178, 184,
-	187, 0, 0, 90, 0, 51, 39, 33, 0, 19,
-	22, 0, 26, 30, 0, 56, 57, 0, 128, 129,
-	15, 0, 23, 27, 31, 34, 0, 43, 35, 0,
-	0, 0, 58,
+	0, 0, 0, 0, 107, 140, 109, 110, 111, 112,
+	113, 114, 115, 116, 117, 118, 119, 120, 121, 122,
+	123, 124, 125, 143, 145, 0, 147, 0, 149, 154,
+	175, 176, 177, 178, 0, 0, 157, 0, 0, 0,
+	158, 0, 0, 0, 0, 0, 190, 191, 0, 136,
+	0, 132, 7, 16, 0, -2, 97, 98, 0, 0,
+	0, 0, 0, 0, 248, 0, 5, 0, 3, 3,
+	248, 0, 0, 0, 0, 0, 3, 3, 3, 84,
+	0, 219, 0, 0, 242, 245, 220, 221, 222, 223,
+	224, 225, 226, 227, 228, 229, 230, 231, 232, 233,
+	180, 0, 0, 0, 144, 152, 141, 186, 185, 150,
+	146, 148, 153, 155, 80, 0, 156, 174, 171, 0,
+	217, 215, 213, 214, 218, 159, 294, 160, 162, 163,
+	0, 0, 168, 0, 0, 0, 0, 0, 0, 0,
+	138, 133, 0, 0, 0, 0, 101, 102, 103, 104,
+	105, 43, 52, 0, 0, 0, 56, 0, 6, 18,
+	0, 0, 5, 0, 44, 66, 0, 250, 0, 3,
+	248, 0, 0, 0, 298, 0, 299, 0, 0, 82,
+	0, 251, 0, 0, 0, 0, 181, 182, 183, 142,
+	151, 0, 0, 0, 179, 0, 0, 0, 0, 0,
+	164, 0, 0, 0, 197, 204, 211, 0, 196, 203,
+	210, 192, 199, 206, 193, 200, 207, 194, 201, 208,
+	195, 202, 209, 198, 205, 212, 0, 0, 0, 0,
+	-2, 54, 60, 0, 64, 0, 0, 58, 62, 30,
+	0, 19, 22, 38, 0, 26, 0, 0, 6, 0,
+	0, 42, 45, 68, 3, 0, 3, 67, 0, 250,
+	0, 296, 297, 0, 0, 0, 0, 237, 0, 239,
+	243, 0, 246, 0, 187, 184, 81, 172, 173, 169,
+	170, 216, 295, 161, 165, 166, 0, 0, 134, 0,
+	137, 0, 61, 65, 53, 57, 63, 31, 34, 23,
+	39, 40, 293, 27, 48, 46, 0, 49, 50, 51,
+	0, 0, 20, 0, 69, 3, 75, 3, 0, 3,
+	0, 0, 83, 236, 238, 244, 247, 0, 0, 0,
+	135, 0, 0, 0, 55, 59, 35, 41, 0, 32,
+	0, 21, 24, 0, 28, 70, 72, 76, 71, 3,
+	77, 0, 0, 167, 188, 189, 17, 0, 0, 0,
+	33, 36, 25, 29, 73, 74, 0, 0, 0, 47,
+	37, 0, 0, 0, 79, 0, 78,
 }
-var syntaxTok1 = [...]int{
 
+var syntaxTok1 = [...]int8{
 	1,
 }
-var syntaxTok2 = [...]int{
 
+var syntaxTok2 = [...]uint8{
 	2, 3, 4, 5, 6, 7, 8, 9, 10, 11,
 	12, 13, 14, 15, 16, 17, 18, 19, 20, 21,
 	22, 23, 24, 25, 26, 27, 28, 29, 30, 31,
@@ -538,9 +716,13 @@ var syntaxTok2 = [...]int{
 	62, 63, 64, 65, 66, 67, 68, 69, 70, 71,
 	72, 73, 74, 75, 76, 77, 78, 79, 80, 81,
 	82, 83, 84, 85, 86, 87, 88, 89, 90, 91,
-	92, 93, 94, 95, 96, 97,
+	92, 93, 94, 95, 96, 97, 98, 99, 100, 101,
+	102, 103, 104, 105, 106, 107, 108, 109, 110, 111,
+	112, 113, 114, 115, 116, 117, 118, 119, 120, 121,
+	122, 123, 124, 125, 126, 127, 128, 129, 130, 131,
 }
-var syntaxTok3 = [...]int{
+
+var syntaxTok3 = [...]int8{
 	0,
 }
 
@@ -620,9 +802,9 @@ func syntaxErrorMessage(state, lookAhead int) string {
 	expected := make([]int, 0, 4)
 
 	// Look for shiftable tokens.
-	base := syntaxPact[state]
+	base := int(syntaxPact[state])
 	for tok := TOKSTART; tok-1 < len(syntaxToknames); tok++ {
-		if n := base + tok; n >= 0 && n < syntaxLast && syntaxChk[syntaxAct[n]] == tok {
+		if n := base + tok; n >= 0 && n < syntaxLast && int(syntaxChk[int(syntaxAct[n])]) == tok {
 			if len(expected) == cap(expected) {
 				return res
 			}
@@ -632,13 +814,13 @@ func syntaxErrorMessage(state, lookAhead int) string {
 
 	if syntaxDef[state] == -2 {
 		i := 0
-		for syntaxExca[i] != -1 || syntaxExca[i+1] != state {
+		for syntaxExca[i] != -1 || int(syntaxExca[i+1]) != state {
 			i += 2
 		}
 
 		// Look for tokens that we accept or reduce.
 		for i += 2; syntaxExca[i] >= 0; i += 2 {
-			tok := syntaxExca[i]
+			tok := int(syntaxExca[i])
 			if tok < TOKSTART || syntaxExca[i+1] == 0 {
 				continue
 			}
@@ -669,30 +851,30 @@ func syntaxlex1(lex syntaxLexer, lval *syntaxSymType) (char, token int) {
 	token = 0
 	char = lex.Lex(lval)
 	if char <= 0 {
-		token = syntaxTok1[0]
+		token = int(syntaxTok1[0])
 		goto out
 	}
 	if char < len(syntaxTok1) {
-		token = syntaxTok1[char]
+		token = int(syntaxTok1[char])
 		goto out
 	}
 	if char >= syntaxPrivate {
 		if char < syntaxPrivate+len(syntaxTok2) {
-			token = syntaxTok2[char-syntaxPrivate]
+			token = int(syntaxTok2[char-syntaxPrivate])
 			goto out
 		}
 	}
 	for i := 0; i < len(syntaxTok3); i += 2 {
-		token = syntaxTok3[i+0]
+		token = int(syntaxTok3[i+0])
 		if token == char {
-			token = syntaxTok3[i+1]
+			token = int(syntaxTok3[i+1])
 			goto out
 		}
 	}
 
 out:
 	if token == 0 {
-		token = syntaxTok2[1] /* unknown char */
+		token = int(syntaxTok2[1]) /* unknown char */
 	}
 	if syntaxDebug >= 3 {
 		__yyfmt__.Printf("lex %s(%d)\n", syntaxTokname(token), uint(char))
@@ -747,7 +929,7 @@ syntaxstack:
 	syntaxS[syntaxp].yys = syntaxstate
 
 syntaxnewstate:
-	syntaxn = syntaxPact[syntaxstate]
+	syntaxn = int(syntaxPact[syntaxstate])
 	if syntaxn <= syntaxFlag {
 		goto syntaxdefault /* simple state */
 	}
@@ -758,8 +940,8 @@ syntaxnewstate:
 	if syntaxn < 0 || syntaxn >= syntaxLast {
 		goto syntaxdefault
 	}
-	syntaxn = syntaxAct[syntaxn]
-	if syntaxChk[syntaxn] == syntaxtoken { /* valid shift */
+	syntaxn = int(syntaxAct[syntaxn])
+	if int(syntaxChk[syntaxn]) == syntaxtoken { /* valid shift */
 		syntaxrcvr.char = -1
 		syntaxtoken = -1
 		syntaxVAL = syntaxrcvr.lval
@@ -772,7 +954,7 @@ syntaxnewstate:
 
 syntaxdefault:
 	/* default state action */
-	syntaxn = syntaxDef[syntaxstate]
+	syntaxn = int(syntaxDef[syntaxstate])
 	if syntaxn == -2 {
 		if syntaxrcvr.char < 0 {
 			syntaxrcvr.char, syntaxtoken = syntaxlex1(syntaxlex, &syntaxrcvr.lval)
@@ -781,18 +963,18 @@ syntaxdefault:
 		/* look through exception table */
 		xi := 0
 		for {
-			if syntaxExca[xi+0] == -1 && syntaxExca[xi+1] == syntaxstate {
+			if syntaxExca[xi+0] == -1 && int(syntaxExca[xi+1]) == syntaxstate {
 				break
 			}
 			xi += 2
 		}
 		for xi += 2; ; xi += 2 {
-			syntaxn = syntaxExca[xi+0]
+			syntaxn = int(syntaxExca[xi+0])
 			if syntaxn < 0 || syntaxn == syntaxtoken {
 				break
 			}
 		}
-		syntaxn = syntaxExca[xi+1]
+		syntaxn = int(syntaxExca[xi+1])
 		if syntaxn < 0 {
 			goto ret0
 		}
@@ -814,10 +996,10 @@ syntaxdefault:
 
 			/* find a state where "error" is a legal shift action */
 			for syntaxp >= 0 {
-				syntaxn = syntaxPact[syntaxS[syntaxp].yys] + syntaxErrCode
+				syntaxn = int(syntaxPact[syntaxS[syntaxp].yys]) + syntaxErrCode
 				if syntaxn >= 0 && syntaxn < syntaxLast {
-					syntaxstate = syntaxAct[syntaxn] /* simulate a shift of "error" */
-					if syntaxChk[syntaxstate] == syntaxErrCode {
+					syntaxstate = int(syntaxAct[syntaxn]) /* simulate a shift of "error" */
+					if int(syntaxChk[syntaxstate]) == syntaxErrCode {
 						goto syntaxstack
 					}
 				}
@@ -853,7 +1035,7 @@ syntaxdefault:
 	syntaxpt := syntaxp
 	_ = syntaxpt // guard against "declared and not used"
 
-	syntaxp -= syntaxR2[syntaxn]
+	syntaxp -= int(syntaxR2[syntaxn])
 	// syntaxp is now the index of $0. Perform the default action. Iff the
 	// reduced production is ε, $1 is possibly out of range.
 	if syntaxp+1 >= len(syntaxS) {
@@ -864,16 +1046,16 @@ syntaxdefault:
 	syntaxVAL = syntaxS[syntaxp+1]
 
 	/* consult goto table to find next state */
-	syntaxn = syntaxR1[syntaxn]
-	syntaxg := syntaxPgo[syntaxn]
+	syntaxn = int(syntaxR1[syntaxn])
+	syntaxg := int(syntaxPgo[syntaxn])
 	syntaxj := syntaxg + syntaxS[syntaxp].yys + 1
 
 	if syntaxj >= syntaxLast {
-		syntaxstate = syntaxAct[syntaxg]
+		syntaxstate = int(syntaxAct[syntaxg])
 	} else {
-		syntaxstate = syntaxAct[syntaxj]
-		if syntaxChk[syntaxstate] != -syntaxn {
-			syntaxstate = syntaxAct[syntaxg]
+		syntaxstate = int(syntaxAct[syntaxj])
+		if int(syntaxChk[syntaxstate]) != -syntaxn {
+			syntaxstate = int(syntaxAct[syntaxg])
 		}
 	}
 	// dummy call; replaced with literal code
@@ -945,1077 +1127,1445 @@ syntaxdefault:
 			syntaxVAL.metricExpr = syntaxDollar[1].metricExpr
 		}
 	case 14:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.metricExpr = syntaxDollar[1].metricExpr
+		}
+	case 15:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.metricExpr = syntaxDollar[1].metricExpr
+		}
+	case 16:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.metricExpr = syntaxDollar[2].metricExpr
 		}
-	case 15:
+	case 17:
 		syntaxDollar = syntaxS[syntaxpt-8 : syntaxpt+1]
 		{
 			syntaxVAL.variantsExpr = newVariantsExpr(syntaxDollar[3].metricExprs, syntaxDollar[7].logRangeExpr)
 		}
-	case 16:
+	case 18:
 		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
 		{
 			syntaxVAL.logRangeExpr = newLogRange(newMatcherExpr(syntaxDollar[1].matchers), syntaxDollar[2].dur, nil, nil)
 		}
-	case 17:
+	case 19:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.logRangeExpr = newLogRange(newMatcherExpr(syntaxDollar[1].matchers), syntaxDollar[2].dur, nil, syntaxDollar[3].offsetExpr)
 		}
-	case 18:
+	case 20:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.logRangeExpr = newLogRange(newMatcherExpr(syntaxDollar[2].matchers), syntaxDollar[4].dur, nil, nil)
 		}
-	case 19:
+	case 21:
 		syntaxDollar = syntaxS[syntaxpt-5 : syntaxpt+1]
 		{
 			syntaxVAL.logRangeExpr = newLogRange(newMatcherExpr(syntaxDollar[2].matchers), syntaxDollar[4].dur, nil, syntaxDollar[5].offsetExpr)
 		}
-	case 20:
+	case 22:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.logRangeExpr = newLogRange(newMatcherExpr(syntaxDollar[1].matchers), syntaxDollar[2].dur, syntaxDollar[3].unwrapExpr, nil)
 		}
-	case 21:
+	case 23:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.logRangeExpr = newLogRange(newMatcherExpr(syntaxDollar[1].matchers), syntaxDollar[2].dur, syntaxDollar[4].unwrapExpr, syntaxDollar[3].offsetExpr)
 		}
-	case 22:
+	case 24:
 		syntaxDollar = syntaxS[syntaxpt-5 : syntaxpt+1]
 		{
 			syntaxVAL.logRangeExpr = newLogRange(newMatcherExpr(syntaxDollar[2].matchers), syntaxDollar[4].dur, syntaxDollar[5].unwrapExpr, nil)
 		}
-	case 23:
+	case 25:
 		syntaxDollar = syntaxS[syntaxpt-6 : syntaxpt+1]
 		{
 			syntaxVAL.logRangeExpr = newLogRange(newMatcherExpr(syntaxDollar[2].matchers), syntaxDollar[4].dur, syntaxDollar[6].unwrapExpr, syntaxDollar[5].offsetExpr)
 		}
-	case 24:
+	case 26:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.logRangeExpr = newLogRange(newMatcherExpr(syntaxDollar[1].matchers), syntaxDollar[3].dur, syntaxDollar[2].unwrapExpr, nil)
 		}
-	case 25:
+	case 27:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.logRangeExpr = newLogRange(newMatcherExpr(syntaxDollar[1].matchers), syntaxDollar[3].dur, syntaxDollar[2].unwrapExpr, syntaxDollar[4].offsetExpr)
 		}
-	case 26:
+	case 28:
 		syntaxDollar = syntaxS[syntaxpt-5 : syntaxpt+1]
 		{
 			syntaxVAL.logRangeExpr = newLogRange(newMatcherExpr(syntaxDollar[2].matchers), syntaxDollar[5].dur, syntaxDollar[3].unwrapExpr, nil)
 		}
-	case 27:
+	case 29:
 		syntaxDollar = syntaxS[syntaxpt-6 : syntaxpt+1]
 		{
 			syntaxVAL.logRangeExpr = newLogRange(newMatcherExpr(syntaxDollar[2].matchers), syntaxDollar[5].dur, syntaxDollar[3].unwrapExpr, syntaxDollar[6].offsetExpr)
 		}
-	case 28:
+	case 30:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.logRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(syntaxDollar[1].matchers), syntaxDollar[2].stages), syntaxDollar[3].dur, nil, nil)
 		}
-	case 29:
+	case 31:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.logRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(syntaxDollar[1].matchers), syntaxDollar[2].stages), syntaxDollar[3].dur, nil, syntaxDollar[4].offsetExpr)
 		}
-	case 30:
+	case 32:
 		syntaxDollar = syntaxS[syntaxpt-5 : syntaxpt+1]
 		{
 			syntaxVAL.logRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(syntaxDollar[2].matchers), syntaxDollar[3].stages), syntaxDollar[5].dur, nil, nil)
 		}
-	case 31:
+	case 33:
 		syntaxDollar = syntaxS[syntaxpt-6 : syntaxpt+1]
 		{
 			syntaxVAL.logRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(syntaxDollar[2].matchers), syntaxDollar[3].stages), syntaxDollar[5].dur, nil, syntaxDollar[6].offsetExpr)
 		}
-	case 32:
+	case 34:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.logRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(syntaxDollar[1].matchers), syntaxDollar[2].stages), syntaxDollar[4].dur, syntaxDollar[3].unwrapExpr, nil)
 		}
-	case 33:
+	case 35:
 		syntaxDollar = syntaxS[syntaxpt-5 : syntaxpt+1]
 		{
 			syntaxVAL.logRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(syntaxDollar[1].matchers), syntaxDollar[2].stages), syntaxDollar[4].dur, syntaxDollar[3].unwrapExpr, syntaxDollar[5].offsetExpr)
 		}
-	case 34:
+	case 36:
 		syntaxDollar = syntaxS[syntaxpt-6 : syntaxpt+1]
 		{
 			syntaxVAL.logRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(syntaxDollar[2].matchers), syntaxDollar[3].stages), syntaxDollar[6].dur, syntaxDollar[4].unwrapExpr, nil)
 		}
-	case 35:
+	case 37:
 		syntaxDollar = syntaxS[syntaxpt-7 : syntaxpt+1]
 		{
 			syntaxVAL.logRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(syntaxDollar[2].matchers), syntaxDollar[3].stages), syntaxDollar[6].dur, syntaxDollar[4].unwrapExpr, syntaxDollar[7].offsetExpr)
 		}
-	case 36:
+	case 38:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.logRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(syntaxDollar[1].matchers), syntaxDollar[3].stages), syntaxDollar[2].dur, nil, nil)
 		}
-	case 37:
+	case 39:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.logRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(syntaxDollar[1].matchers), syntaxDollar[4].stages), syntaxDollar[2].dur, nil, syntaxDollar[3].offsetExpr)
 		}
-	case 38:
+	case 40:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.logRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(syntaxDollar[1].matchers), syntaxDollar[3].stages), syntaxDollar[2].dur, syntaxDollar[4].unwrapExpr, nil)
 		}
-	case 39:
+	case 41:
 		syntaxDollar = syntaxS[syntaxpt-5 : syntaxpt+1]
 		{
 			syntaxVAL.logRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(syntaxDollar[1].matchers), syntaxDollar[4].stages), syntaxDollar[2].dur, syntaxDollar[5].unwrapExpr, syntaxDollar[3].offsetExpr)
 		}
-	case 40:
+	case 42:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.logRangeExpr = syntaxDollar[2].logRangeExpr
 		}
-	case 42:
+	case 44:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.subqueryExpr = newSubqueryExpr(syntaxDollar[1].metricExpr, syntaxDollar[2].subqueryRange, nil)
+		}
+	case 45:
+		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
+		{
+			syntaxVAL.subqueryExpr = newSubqueryExpr(syntaxDollar[1].metricExpr, syntaxDollar[2].subqueryRange, syntaxDollar[3].offsetExpr)
+		}
+	case 46:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.unwrapExpr = newUnwrapExpr(syntaxDollar[3].str, "")
 		}
-	case 43:
+	case 47:
 		syntaxDollar = syntaxS[syntaxpt-6 : syntaxpt+1]
 		{
 			syntaxVAL.unwrapExpr = newUnwrapExpr(syntaxDollar[5].str, syntaxDollar[3].op)
 		}
-	case 44:
+	case 48:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.unwrapExpr = syntaxDollar[1].unwrapExpr.addPostFilter(syntaxDollar[3].filterer)
 		}
-	case 45:
+	case 49:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpConvBytes
 		}
-	case 46:
+	case 50:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpConvDuration
 		}
-	case 47:
+	case 51:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpConvDurationSeconds
 		}
-	case 48:
+	case 52:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.metricExpr = newRangeAggregationExpr(syntaxDollar[3].logRangeExpr, syntaxDollar[1].op, nil, nil)
 		}
-	case 49:
+	case 53:
 		syntaxDollar = syntaxS[syntaxpt-6 : syntaxpt+1]
 		{
 			syntaxVAL.metricExpr = newRangeAggregationExpr(syntaxDollar[5].logRangeExpr, syntaxDollar[1].op, nil, &syntaxDollar[3].str)
 		}
-	case 50:
+	case 54:
 		syntaxDollar = syntaxS[syntaxpt-5 : syntaxpt+1]
 		{
 			syntaxVAL.metricExpr = newRangeAggregationExpr(syntaxDollar[3].logRangeExpr, syntaxDollar[1].op, syntaxDollar[5].grouping, nil)
 		}
-	case 51:
+	case 55:
 		syntaxDollar = syntaxS[syntaxpt-7 : syntaxpt+1]
 		{
 			syntaxVAL.metricExpr = newRangeAggregationExpr(syntaxDollar[5].logRangeExpr, syntaxDollar[1].op, syntaxDollar[7].grouping, &syntaxDollar[3].str)
 		}
-	case 52:
-		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
-		{
-			syntaxVAL.metricExpr = mustNewVectorAggregationExpr(syntaxDollar[3].metricExpr, syntaxDollar[1].op, nil, nil)
-		}
-	case 53:
-		syntaxDollar = syntaxS[syntaxpt-5 : syntaxpt+1]
-		{
-			syntaxVAL.metricExpr = mustNewVectorAggregationExpr(syntaxDollar[4].metricExpr, syntaxDollar[1].op, syntaxDollar[2].grouping, nil)
-		}
-	case 54:
-		syntaxDollar = syntaxS[syntaxpt-5 : syntaxpt+1]
-		{
-			syntaxVAL.metricExpr = mustNewVectorAggregationExpr(syntaxDollar[3].metricExpr, syntaxDollar[1].op, syntaxDollar[5].grouping, nil)
-		}
-	case 55:
-		syntaxDollar = syntaxS[syntaxpt-6 : syntaxpt+1]
-		{
-			syntaxVAL.metricExpr = mustNewVectorAggregationExpr(syntaxDollar[5].metricExpr, syntaxDollar[1].op, nil, &syntaxDollar[3].str)
-		}
 	case 56:
-		syntaxDollar = syntaxS[syntaxpt-7 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
-			syntaxVAL.metricExpr = mustNewVectorAggregationExpr(syntaxDollar[5].metricExpr, syntaxDollar[1].op, syntaxDollar[7].grouping, &syntaxDollar[3].str)
+			syntaxVAL.metricExpr = newSubqueryAggregationExpr(syntaxDollar[3].subqueryExpr, syntaxDollar[1].op, nil, nil)
 		}
 	case 57:
-		syntaxDollar = syntaxS[syntaxpt-7 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-6 : syntaxpt+1]
 		{
-			syntaxVAL.metricExpr = mustNewVectorAggregationExpr(syntaxDollar[6].metricExpr, syntaxDollar[1].op, syntaxDollar[2].grouping, &syntaxDollar[4].str)
+			syntaxVAL.metricExpr = newSubqueryAggregationExpr(syntaxDollar[5].subqueryExpr, syntaxDollar[1].op, nil, &syntaxDollar[3].str)
 		}
 	case 58:
-		syntaxDollar = syntaxS[syntaxpt-12 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-5 : syntaxpt+1]
 		{
-			syntaxVAL.metricExpr = mustNewLabelReplaceExpr(syntaxDollar[3].metricExpr, syntaxDollar[5].str, syntaxDollar[7].str, syntaxDollar[9].str, syntaxDollar[11].str)
+			syntaxVAL.metricExpr = newSubqueryAggregationExpr(syntaxDollar[3].subqueryExpr, syntaxDollar[1].op, syntaxDollar[5].grouping, nil)
 		}
 	case 59:
-		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-7 : syntaxpt+1]
 		{
-			syntaxVAL.matchers = syntaxDollar[2].matchers
+			syntaxVAL.metricExpr = newSubqueryAggregationExpr(syntaxDollar[5].subqueryExpr, syntaxDollar[1].op, syntaxDollar[7].grouping, &syntaxDollar[3].str)
 		}
 	case 60:
-		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-5 : syntaxpt+1]
 		{
-			syntaxVAL.matchers = syntaxDollar[2].matchers
+			syntaxVAL.metricExpr = newTrailingParamsRangeAggregationExpr(syntaxDollar[3].logRangeExpr, syntaxDollar[1].op, nil, syntaxDollar[4].strs)
 		}
 	case 61:
-		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-6 : syntaxpt+1]
 		{
+			syntaxVAL.metricExpr = newTrailingParamsRangeAggregationExpr(syntaxDollar[3].logRangeExpr, syntaxDollar[1].op, syntaxDollar[6].grouping, syntaxDollar[4].strs)
 		}
 	case 62:
-		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-5 : syntaxpt+1]
 		{
-			syntaxVAL.matchers = []*labels.Matcher{syntaxDollar[1].matcher}
+			syntaxVAL.metricExpr = newTrailingParamsSubqueryAggregationExpr(syntaxDollar[3].subqueryExpr, syntaxDollar[1].op, nil, syntaxDollar[4].strs)
 		}
 	case 63:
-		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-6 : syntaxpt+1]
 		{
-			syntaxVAL.matchers = append(syntaxDollar[1].matchers, syntaxDollar[3].matcher)
+			syntaxVAL.metricExpr = newTrailingParamsSubqueryAggregationExpr(syntaxDollar[3].subqueryExpr, syntaxDollar[1].op, syntaxDollar[6].grouping, syntaxDollar[4].strs)
 		}
 	case 64:
-		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
 		{
-			syntaxVAL.matcher = mustNewMatcher(labels.MatchEqual, syntaxDollar[1].str, syntaxDollar[3].str)
+			syntaxVAL.strs = []string{syntaxDollar[2].str}
 		}
 	case 65:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
-			syntaxVAL.matcher = mustNewMatcher(labels.MatchNotEqual, syntaxDollar[1].str, syntaxDollar[3].str)
+			syntaxVAL.strs = append(syntaxDollar[1].strs, syntaxDollar[3].str)
 		}
 	case 66:
-		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
-			syntaxVAL.matcher = mustNewMatcher(labels.MatchRegexp, syntaxDollar[1].str, syntaxDollar[3].str)
+			syntaxVAL.metricExpr = mustNewVectorAggregationExpr(syntaxDollar[3].metricExpr, syntaxDollar[1].op, nil, nil)
 		}
 	case 67:
-		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-5 : syntaxpt+1]
 		{
-			syntaxVAL.matcher = mustNewMatcher(labels.MatchNotRegexp, syntaxDollar[1].str, syntaxDollar[3].str)
+			syntaxVAL.metricExpr = mustNewVectorAggregationExpr(syntaxDollar[4].metricExpr, syntaxDollar[1].op, syntaxDollar[2].grouping, nil)
 		}
 	case 68:
-		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-5 : syntaxpt+1]
 		{
-			syntaxVAL.stages = MultiStageExpr{syntaxDollar[1].stage}
+			syntaxVAL.metricExpr = mustNewVectorAggregationExpr(syntaxDollar[3].metricExpr, syntaxDollar[1].op, syntaxDollar[5].grouping, nil)
 		}
 	case 69:
-		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-6 : syntaxpt+1]
 		{
-			syntaxVAL.stages = append(syntaxDollar[1].stages, syntaxDollar[2].stage)
+			syntaxVAL.metricExpr = mustNewVectorAggregationExpr(syntaxDollar[5].metricExpr, syntaxDollar[1].op, nil, &syntaxDollar[3].str)
 		}
 	case 70:
-		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-7 : syntaxpt+1]
 		{
-			syntaxVAL.stage = syntaxDollar[1].lineFilterExpr
+			syntaxVAL.metricExpr = mustNewVectorAggregationExpr(syntaxDollar[5].metricExpr, syntaxDollar[1].op, syntaxDollar[7].grouping, &syntaxDollar[3].str)
 		}
 	case 71:
-		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-7 : syntaxpt+1]
 		{
-			syntaxVAL.stage = syntaxDollar[2].stage
+			syntaxVAL.metricExpr = mustNewVectorAggregationExpr(syntaxDollar[6].metricExpr, syntaxDollar[1].op, syntaxDollar[2].grouping, &syntaxDollar[4].str)
 		}
 	case 72:
-		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-7 : syntaxpt+1]
 		{
-			syntaxVAL.stage = syntaxDollar[2].stage
+			n := "-" + syntaxDollar[4].str
+			syntaxVAL.metricExpr = mustNewVectorAggregationExpr(syntaxDollar[6].metricExpr, syntaxDollar[1].op, nil, &n)
 		}
 	case 73:
-		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-8 : syntaxpt+1]
 		{
-			syntaxVAL.stage = syntaxDollar[2].stage
+			n := "-" + syntaxDollar[4].str
+			syntaxVAL.metricExpr = mustNewVectorAggregationExpr(syntaxDollar[6].metricExpr, syntaxDollar[1].op, syntaxDollar[8].grouping, &n)
 		}
 	case 74:
-		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-8 : syntaxpt+1]
 		{
-			syntaxVAL.stage = syntaxDollar[2].stage
+			n := "-" + syntaxDollar[5].str
+			syntaxVAL.metricExpr = mustNewVectorAggregationExpr(syntaxDollar[7].metricExpr, syntaxDollar[1].op, syntaxDollar[2].grouping, &n)
 		}
 	case 75:
-		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-6 : syntaxpt+1]
 		{
-			syntaxVAL.stage = &LabelFilterExpr{LabelFilterer: syntaxDollar[2].filterer}
+			syntaxVAL.metricExpr = mustNewCountValuesExpr(syntaxDollar[5].metricExpr, syntaxDollar[1].op, nil, syntaxDollar[3].str)
 		}
 	case 76:
-		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-7 : syntaxpt+1]
 		{
-			syntaxVAL.stage = syntaxDollar[2].stage
+			syntaxVAL.metricExpr = mustNewCountValuesExpr(syntaxDollar[5].metricExpr, syntaxDollar[1].op, syntaxDollar[7].grouping, syntaxDollar[3].str)
 		}
 	case 77:
-		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-7 : syntaxpt+1]
 		{
-			syntaxVAL.stage = syntaxDollar[2].stage
+			syntaxVAL.metricExpr = mustNewCountValuesExpr(syntaxDollar[6].metricExpr, syntaxDollar[1].op, syntaxDollar[2].grouping, syntaxDollar[4].str)
 		}
 	case 78:
-		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-12 : syntaxpt+1]
 		{
-			syntaxVAL.stage = syntaxDollar[2].stage
+			syntaxVAL.metricExpr = mustNewLabelReplaceExpr(syntaxDollar[3].metricExpr, syntaxDollar[5].str, syntaxDollar[7].str, syntaxDollar[9].str, syntaxDollar[11].str)
 		}
 	case 79:
-		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-10 : syntaxpt+1]
 		{
-			syntaxVAL.stage = syntaxDollar[2].stage
+			syntaxVAL.metricExpr = mustNewLabelJoinExpr(syntaxDollar[3].metricExpr, syntaxDollar[5].str, syntaxDollar[7].str, syntaxDollar[9].strs)
 		}
 	case 80:
-		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
-			syntaxVAL.stage = syntaxDollar[2].stage
+			syntaxVAL.strs = []string{syntaxDollar[1].str}
 		}
 	case 81:
-		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
-			syntaxVAL.filter = log.LineMatchRegexp
+			syntaxVAL.strs = append(syntaxDollar[1].strs, syntaxDollar[3].str)
 		}
 	case 82:
-		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
-			syntaxVAL.filter = log.LineMatchEqual
+			syntaxVAL.metricExpr = mustNewFunctionExpr(syntaxDollar[3].metricExpr, syntaxDollar[1].op, nil)
 		}
 	case 83:
-		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-6 : syntaxpt+1]
 		{
-			syntaxVAL.filter = log.LineMatchPattern
+			syntaxVAL.metricExpr = mustNewFunctionExpr(syntaxDollar[3].metricExpr, syntaxDollar[1].op, syntaxDollar[5].literalExpr)
 		}
 	case 84:
-		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
-			syntaxVAL.filter = log.LineMatchNotRegexp
+			syntaxVAL.metricExpr = mustNewFunctionExpr(nil, syntaxDollar[1].op, nil)
 		}
 	case 85:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
-			syntaxVAL.filter = log.LineMatchNotEqual
+			syntaxVAL.op = OpFuncAbs
 		}
 	case 86:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
-			syntaxVAL.filter = log.LineMatchNotPattern
+			syntaxVAL.op = OpFuncCeil
 		}
 	case 87:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
-			syntaxVAL.op = OpFilterIP
+			syntaxVAL.op = OpFuncFloor
 		}
 	case 88:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
-			syntaxVAL.lineFilterExpr = newLineFilterExpr(log.LineMatchEqual, "", syntaxDollar[1].str)
+			syntaxVAL.op = OpFuncRound
 		}
 	case 89:
-		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
-			syntaxVAL.lineFilterExpr = newOrLineFilterExpr(newLineFilterExpr(log.LineMatchEqual, "", syntaxDollar[1].str), syntaxDollar[3].lineFilterExpr)
+			syntaxVAL.op = OpFuncClampMin
 		}
 	case 90:
-		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
-			syntaxVAL.lineFilterExpr = newLineFilterExpr(log.LineMatchEqual, syntaxDollar[1].op, syntaxDollar[3].str)
+			syntaxVAL.op = OpFuncClampMax
 		}
 	case 91:
-		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
-			syntaxVAL.lineFilterExpr = newLineFilterExpr(syntaxDollar[1].filter, "", syntaxDollar[2].str)
+			syntaxVAL.op = OpFuncSqrt
 		}
 	case 92:
-		syntaxDollar = syntaxS[syntaxpt-5 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
-			syntaxVAL.lineFilterExpr = newLineFilterExpr(syntaxDollar[1].filter, syntaxDollar[2].op, syntaxDollar[4].str)
+			syntaxVAL.op = OpFuncLn
 		}
 	case 93:
-		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
-			syntaxVAL.lineFilterExpr = newOrLineFilterExpr(syntaxDollar[1].lineFilterExpr, syntaxDollar[3].lineFilterExpr)
+			syntaxVAL.op = OpFuncExp
 		}
 	case 94:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
-			syntaxVAL.lineFilterExpr = syntaxDollar[1].lineFilterExpr
+			syntaxVAL.op = OpFuncTimestamp
 		}
 	case 95:
-		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
-			syntaxVAL.lineFilterExpr = newNestedLineFilterExpr(syntaxDollar[1].lineFilterExpr, syntaxDollar[2].lineFilterExpr)
+			syntaxVAL.op = OpFuncHour
 		}
 	case 96:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
-			syntaxVAL.strs = []string{syntaxDollar[1].str}
+			syntaxVAL.op = OpFuncDayOfWeek
 		}
 	case 97:
-		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
-			syntaxVAL.strs = append(syntaxDollar[1].strs, syntaxDollar[2].str)
+			syntaxVAL.matchers = syntaxDollar[2].matchers
 		}
 	case 98:
-		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
-			syntaxVAL.stage = newLogfmtParserExpr(nil)
+			syntaxVAL.matchers = syntaxDollar[2].matchers
 		}
 	case 99:
 		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
 		{
-			syntaxVAL.stage = newLogfmtParserExpr(syntaxDollar[2].strs)
 		}
 	case 100:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
-			syntaxVAL.stage = newLabelParserExpr(OpParserTypeJSON, "")
+			syntaxVAL.matchers = []*labels.Matcher{syntaxDollar[1].matcher}
 		}
 	case 101:
-		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
-			syntaxVAL.stage = newLabelParserExpr(OpParserTypeRegexp, syntaxDollar[2].str)
+			syntaxVAL.matchers = append(syntaxDollar[1].matchers, syntaxDollar[3].matcher)
 		}
 	case 102:
-		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
-			syntaxVAL.stage = newLabelParserExpr(OpParserTypeUnpack, "")
+			syntaxVAL.matcher = mustNewMatcher(labels.MatchEqual, syntaxDollar[1].str, syntaxDollar[3].str)
 		}
 	case 103:
-		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
-			syntaxVAL.stage = newLabelParserExpr(OpParserTypePattern, syntaxDollar[2].str)
+			syntaxVAL.matcher = mustNewMatcher(labels.MatchNotEqual, syntaxDollar[1].str, syntaxDollar[3].str)
 		}
 	case 104:
+		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
+		{
+			syntaxVAL.matcher = mustNewMatcher(labels.MatchRegexp, syntaxDollar[1].str, syntaxDollar[3].str)
+		}
+	case 105:
+		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
+		{
+			syntaxVAL.matcher = mustNewMatcher(labels.MatchNotRegexp, syntaxDollar[1].str, syntaxDollar[3].str)
+		}
+	case 106:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.stages = MultiStageExpr{syntaxDollar[1].stage}
+		}
+	case 107:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.stages = append(syntaxDollar[1].stages, syntaxDollar[2].stage)
+		}
+	case 108:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.stage = syntaxDollar[1].lineFilterExpr
+		}
+	case 109:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.stage = syntaxDollar[2].stage
+		}
+	case 110:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.stage = syntaxDollar[2].stage
+		}
+	case 111:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.stage = syntaxDollar[2].stage
+		}
+	case 112:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.stage = syntaxDollar[2].stage
+		}
+	case 113:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.stage = syntaxDollar[2].stage
+		}
+	case 114:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.stage = syntaxDollar[2].stage
+		}
+	case 115:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.stage = &LabelFilterExpr{LabelFilterer: syntaxDollar[2].filterer}
+		}
+	case 116:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.stage = syntaxDollar[2].stage
+		}
+	case 117:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.stage = syntaxDollar[2].stage
+		}
+	case 118:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.stage = syntaxDollar[2].stage
+		}
+	case 119:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.stage = syntaxDollar[2].stage
+		}
+	case 120:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.stage = syntaxDollar[2].stage
+		}
+	case 121:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.stage = syntaxDollar[2].stage
+		}
+	case 122:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.stage = syntaxDollar[2].stage
+		}
+	case 123:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.stage = syntaxDollar[2].stage
+		}
+	case 124:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.stage = syntaxDollar[2].stage
+		}
+	case 125:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.stage = syntaxDollar[2].stage
+		}
+	case 126:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.filter = log.LineMatchRegexp
+		}
+	case 127:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.filter = log.LineMatchEqual
+		}
+	case 128:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.filter = log.LineMatchPattern
+		}
+	case 129:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.filter = log.LineMatchNotRegexp
+		}
+	case 130:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.filter = log.LineMatchNotEqual
+		}
+	case 131:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.filter = log.LineMatchNotPattern
+		}
+	case 132:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.op = OpFilterIP
+		}
+	case 133:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.lineFilterExpr = newLineFilterExpr(log.LineMatchEqual, "", syntaxDollar[1].str)
+		}
+	case 134:
+		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
+		{
+			syntaxVAL.lineFilterExpr = newOrLineFilterExpr(newLineFilterExpr(log.LineMatchEqual, "", syntaxDollar[1].str), syntaxDollar[3].lineFilterExpr)
+		}
+	case 135:
+		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
+		{
+			syntaxVAL.lineFilterExpr = newLineFilterExpr(log.LineMatchEqual, syntaxDollar[1].op, syntaxDollar[3].str)
+		}
+	case 136:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.lineFilterExpr = newLineFilterExpr(syntaxDollar[1].filter, "", syntaxDollar[2].str)
+		}
+	case 137:
+		syntaxDollar = syntaxS[syntaxpt-5 : syntaxpt+1]
+		{
+			syntaxVAL.lineFilterExpr = newLineFilterExpr(syntaxDollar[1].filter, syntaxDollar[2].op, syntaxDollar[4].str)
+		}
+	case 138:
+		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
+		{
+			syntaxVAL.lineFilterExpr = newOrLineFilterExpr(syntaxDollar[1].lineFilterExpr, syntaxDollar[3].lineFilterExpr)
+		}
+	case 139:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.lineFilterExpr = syntaxDollar[1].lineFilterExpr
+		}
+	case 140:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.lineFilterExpr = newNestedLineFilterExpr(syntaxDollar[1].lineFilterExpr, syntaxDollar[2].lineFilterExpr)
+		}
+	case 141:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.strs = []string{syntaxDollar[1].str}
+		}
+	case 142:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.strs = append(syntaxDollar[1].strs, syntaxDollar[2].str)
+		}
+	case 143:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.stage = newLogfmtParserExpr(nil)
+		}
+	case 144:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.stage = newLogfmtParserExpr(syntaxDollar[2].strs)
+		}
+	case 145:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.stage = newLabelParserExpr(OpParserTypeJSON, "")
+		}
+	case 146:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.stage = newLabelParserExpr(OpParserTypeRegexp, syntaxDollar[2].str)
+		}
+	case 147:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.stage = newLabelParserExpr(OpParserTypeUnpack, "")
+		}
+	case 148:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.stage = newLabelParserExpr(OpParserTypePattern, syntaxDollar[2].str)
+		}
+	case 149:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.stage = newLabelParserExpr(OpParserTypeXML, "")
+		}
+	case 150:
 		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
 		{
 			syntaxVAL.stage = newJSONExpressionParser(syntaxDollar[2].labelExtractionExpressionList)
 		}
-	case 105:
+	case 151:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.stage = newLogfmtExpressionParser(syntaxDollar[3].labelExtractionExpressionList, syntaxDollar[2].strs)
 		}
-	case 106:
+	case 152:
 		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
 		{
 			syntaxVAL.stage = newLogfmtExpressionParser(syntaxDollar[2].labelExtractionExpressionList, nil)
 		}
-	case 107:
+	case 153:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.stage = newXMLExpressionParser(syntaxDollar[2].labelExtractionExpressionList)
+		}
+	case 154:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.stage = newCSVParserExpr(nil)
+		}
+	case 155:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.stage = newCSVParserExpr(syntaxDollar[2].strs)
+		}
+	case 156:
 		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
 		{
 			syntaxVAL.stage = newLineFmtExpr(syntaxDollar[2].str)
 		}
-	case 108:
+	case 157:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.stage = newDecolorizeExpr()
 		}
-	case 109:
+	case 158:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.stage = newRedactExpr(nil)
+		}
+	case 159:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.stage = newRedactExpr(syntaxDollar[2].strs)
+		}
+	case 160:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.stage = newSamplingExpr(syntaxDollar[2].str, "")
+		}
+	case 161:
+		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
+		{
+			syntaxVAL.stage = newSamplingExpr(syntaxDollar[2].str, syntaxDollar[4].str)
+		}
+	case 162:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.stage = newContextExpr(syntaxDollar[2].strs)
+		}
+	case 163:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.strs = syntaxDollar[1].strs
+		}
+	case 164:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.strs = append(syntaxDollar[1].strs, syntaxDollar[2].strs...)
+		}
+	case 165:
+		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
+		{
+			syntaxVAL.strs = []string{syntaxDollar[1].str, syntaxDollar[3].str}
+		}
+	case 166:
+		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
+		{
+			syntaxVAL.stage = newLookupExpr(syntaxDollar[2].str, syntaxDollar[4].str, "", "")
+		}
+	case 167:
+		syntaxDollar = syntaxS[syntaxpt-6 : syntaxpt+1]
+		{
+			syntaxVAL.stage = newLookupExpr(syntaxDollar[2].str, syntaxDollar[4].str, syntaxDollar[5].str, syntaxDollar[6].str)
+		}
+	case 168:
+		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
+		{
+			syntaxVAL.stage = newDedupExpr(syntaxDollar[2].strs)
+		}
+	case 169:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.labelFormat = log.NewRenameLabelFmt(syntaxDollar[1].str, syntaxDollar[3].str)
 		}
-	case 110:
+	case 170:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.labelFormat = log.NewTemplateLabelFmt(syntaxDollar[1].str, syntaxDollar[3].str)
 		}
-	case 111:
+	case 171:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.labelsFormat = []log.LabelFmt{syntaxDollar[1].labelFormat}
 		}
-	case 112:
+	case 172:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.labelsFormat = append(syntaxDollar[1].labelsFormat, syntaxDollar[3].labelFormat)
 		}
-	case 114:
+	case 174:
 		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
 		{
 			syntaxVAL.stage = newLabelFmtExpr(syntaxDollar[2].labelsFormat)
 		}
-	case 115:
+	case 175:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = log.NewStringLabelFilter(syntaxDollar[1].matcher)
 		}
-	case 116:
+	case 176:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = syntaxDollar[1].filterer
 		}
-	case 117:
+	case 177:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = syntaxDollar[1].filterer
 		}
-	case 118:
+	case 178:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = syntaxDollar[1].filterer
 		}
-	case 119:
+	case 179:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = syntaxDollar[2].filterer
 		}
-	case 120:
+	case 180:
 		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = log.NewAndLabelFilter(syntaxDollar[1].filterer, syntaxDollar[2].filterer)
 		}
-	case 121:
+	case 181:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = log.NewAndLabelFilter(syntaxDollar[1].filterer, syntaxDollar[3].filterer)
 		}
-	case 122:
+	case 182:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = log.NewAndLabelFilter(syntaxDollar[1].filterer, syntaxDollar[3].filterer)
 		}
-	case 123:
+	case 183:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = log.NewOrLabelFilter(syntaxDollar[1].filterer, syntaxDollar[3].filterer)
 		}
-	case 124:
+	case 184:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.labelExtractionExpression = log.NewLabelExtractionExpr(syntaxDollar[1].str, syntaxDollar[3].str)
 		}
-	case 125:
+	case 185:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.labelExtractionExpression = log.NewLabelExtractionExpr(syntaxDollar[1].str, syntaxDollar[1].str)
 		}
-	case 126:
+	case 186:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.labelExtractionExpressionList = []log.LabelExtractionExpr{syntaxDollar[1].labelExtractionExpression}
 		}
-	case 127:
+	case 187:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.labelExtractionExpressionList = append(syntaxDollar[1].labelExtractionExpressionList, syntaxDollar[3].labelExtractionExpression)
 		}
-	case 128:
+	case 188:
 		syntaxDollar = syntaxS[syntaxpt-6 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = log.NewIPLabelFilter(syntaxDollar[5].str, syntaxDollar[1].str, log.LabelFilterEqual)
 		}
-	case 129:
+	case 189:
 		syntaxDollar = syntaxS[syntaxpt-6 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = log.NewIPLabelFilter(syntaxDollar[5].str, syntaxDollar[1].str, log.LabelFilterNotEqual)
 		}
-	case 130:
+	case 190:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = syntaxDollar[1].filterer
 		}
-	case 131:
+	case 191:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = syntaxDollar[1].filterer
 		}
-	case 132:
+	case 192:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = log.NewDurationLabelFilter(log.LabelFilterGreaterThan, syntaxDollar[1].str, syntaxDollar[3].dur)
 		}
-	case 133:
+	case 193:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = log.NewDurationLabelFilter(log.LabelFilterGreaterThanOrEqual, syntaxDollar[1].str, syntaxDollar[3].dur)
 		}
-	case 134:
+	case 194:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = log.NewDurationLabelFilter(log.LabelFilterLesserThan, syntaxDollar[1].str, syntaxDollar[3].dur)
 		}
-	case 135:
+	case 195:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = log.NewDurationLabelFilter(log.LabelFilterLesserThanOrEqual, syntaxDollar[1].str, syntaxDollar[3].dur)
 		}
-	case 136:
+	case 196:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = log.NewDurationLabelFilter(log.LabelFilterNotEqual, syntaxDollar[1].str, syntaxDollar[3].dur)
 		}
-	case 137:
+	case 197:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = log.NewDurationLabelFilter(log.LabelFilterEqual, syntaxDollar[1].str, syntaxDollar[3].dur)
 		}
-	case 138:
+	case 198:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = log.NewDurationLabelFilter(log.LabelFilterEqual, syntaxDollar[1].str, syntaxDollar[3].dur)
 		}
-	case 139:
+	case 199:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = log.NewBytesLabelFilter(log.LabelFilterGreaterThan, syntaxDollar[1].str, syntaxDollar[3].bytes)
 		}
-	case 140:
+	case 200:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = log.NewBytesLabelFilter(log.LabelFilterGreaterThanOrEqual, syntaxDollar[1].str, syntaxDollar[3].bytes)
 		}
-	case 141:
+	case 201:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = log.NewBytesLabelFilter(log.LabelFilterLesserThan, syntaxDollar[1].str, syntaxDollar[3].bytes)
 		}
-	case 142:
+	case 202:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = log.NewBytesLabelFilter(log.LabelFilterLesserThanOrEqual, syntaxDollar[1].str, syntaxDollar[3].bytes)
 		}
-	case 143:
+	case 203:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = log.NewBytesLabelFilter(log.LabelFilterNotEqual, syntaxDollar[1].str, syntaxDollar[3].bytes)
 		}
-	case 144:
+	case 204:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = log.NewBytesLabelFilter(log.LabelFilterEqual, syntaxDollar[1].str, syntaxDollar[3].bytes)
 		}
-	case 145:
+	case 205:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = log.NewBytesLabelFilter(log.LabelFilterEqual, syntaxDollar[1].str, syntaxDollar[3].bytes)
 		}
-	case 146:
+	case 206:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = log.NewNumericLabelFilter(log.LabelFilterGreaterThan, syntaxDollar[1].str, syntaxDollar[3].literalExpr.Val)
 		}
-	case 147:
+	case 207:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = log.NewNumericLabelFilter(log.LabelFilterGreaterThanOrEqual, syntaxDollar[1].str, syntaxDollar[3].literalExpr.Val)
 		}
-	case 148:
+	case 208:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = log.NewNumericLabelFilter(log.LabelFilterLesserThan, syntaxDollar[1].str, syntaxDollar[3].literalExpr.Val)
 		}
-	case 149:
+	case 209:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = log.NewNumericLabelFilter(log.LabelFilterLesserThanOrEqual, syntaxDollar[1].str, syntaxDollar[3].literalExpr.Val)
 		}
-	case 150:
+	case 210:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = log.NewNumericLabelFilter(log.LabelFilterNotEqual, syntaxDollar[1].str, syntaxDollar[3].literalExpr.Val)
 		}
-	case 151:
+	case 211:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = log.NewNumericLabelFilter(log.LabelFilterEqual, syntaxDollar[1].str, syntaxDollar[3].literalExpr.Val)
 		}
-	case 152:
+	case 212:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.filterer = log.NewNumericLabelFilter(log.LabelFilterEqual, syntaxDollar[1].str, syntaxDollar[3].literalExpr.Val)
 		}
-	case 153:
+	case 213:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.namedMatcher = log.NewNamedLabelMatcher(nil, syntaxDollar[1].str)
 		}
-	case 154:
+	case 214:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.namedMatcher = log.NewNamedLabelMatcher(syntaxDollar[1].matcher, "")
 		}
-	case 155:
+	case 215:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.namedMatchers = []log.NamedLabelMatcher{syntaxDollar[1].namedMatcher}
 		}
-	case 156:
+	case 216:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.namedMatchers = append(syntaxDollar[1].namedMatchers, syntaxDollar[3].namedMatcher)
 		}
-	case 157:
+	case 217:
 		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
 		{
 			syntaxVAL.stage = newDropLabelsExpr(syntaxDollar[2].namedMatchers)
 		}
-	case 158:
+	case 218:
 		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
 		{
 			syntaxVAL.stage = newKeepLabelsExpr(syntaxDollar[2].namedMatchers)
 		}
-	case 159:
+	case 219:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.metricExpr = mustNewBinOpExpr("or", syntaxDollar[3].binOpts, syntaxDollar[1].expr, syntaxDollar[4].expr)
 		}
-	case 160:
+	case 220:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.metricExpr = mustNewBinOpExpr("and", syntaxDollar[3].binOpts, syntaxDollar[1].expr, syntaxDollar[4].expr)
 		}
-	case 161:
+	case 221:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.metricExpr = mustNewBinOpExpr("unless", syntaxDollar[3].binOpts, syntaxDollar[1].expr, syntaxDollar[4].expr)
 		}
-	case 162:
+	case 222:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.metricExpr = mustNewBinOpExpr("+", syntaxDollar[3].binOpts, syntaxDollar[1].expr, syntaxDollar[4].expr)
 		}
-	case 163:
+	case 223:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.metricExpr = mustNewBinOpExpr("-", syntaxDollar[3].binOpts, syntaxDollar[1].expr, syntaxDollar[4].expr)
 		}
-	case 164:
+	case 224:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.metricExpr = mustNewBinOpExpr("*", syntaxDollar[3].binOpts, syntaxDollar[1].expr, syntaxDollar[4].expr)
 		}
-	case 165:
+	case 225:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.metricExpr = mustNewBinOpExpr("/", syntaxDollar[3].binOpts, syntaxDollar[1].expr, syntaxDollar[4].expr)
 		}
-	case 166:
+	case 226:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.metricExpr = mustNewBinOpExpr("%", syntaxDollar[3].binOpts, syntaxDollar[1].expr, syntaxDollar[4].expr)
 		}
-	case 167:
+	case 227:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.metricExpr = mustNewBinOpExpr("^", syntaxDollar[3].binOpts, syntaxDollar[1].expr, syntaxDollar[4].expr)
 		}
-	case 168:
+	case 228:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.metricExpr = mustNewBinOpExpr("==", syntaxDollar[3].binOpts, syntaxDollar[1].expr, syntaxDollar[4].expr)
 		}
-	case 169:
+	case 229:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.metricExpr = mustNewBinOpExpr("!=", syntaxDollar[3].binOpts, syntaxDollar[1].expr, syntaxDollar[4].expr)
 		}
-	case 170:
+	case 230:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.metricExpr = mustNewBinOpExpr(">", syntaxDollar[3].binOpts, syntaxDollar[1].expr, syntaxDollar[4].expr)
 		}
-	case 171:
+	case 231:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.metricExpr = mustNewBinOpExpr(">=", syntaxDollar[3].binOpts, syntaxDollar[1].expr, syntaxDollar[4].expr)
 		}
-	case 172:
+	case 232:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.metricExpr = mustNewBinOpExpr("<", syntaxDollar[3].binOpts, syntaxDollar[1].expr, syntaxDollar[4].expr)
 		}
-	case 173:
+	case 233:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.metricExpr = mustNewBinOpExpr("<=", syntaxDollar[3].binOpts, syntaxDollar[1].expr, syntaxDollar[4].expr)
 		}
-	case 174:
+	case 234:
 		syntaxDollar = syntaxS[syntaxpt-0 : syntaxpt+1]
 		{
 			syntaxVAL.binOpts = &BinOpOptions{VectorMatching: &VectorMatching{Card: CardOneToOne}}
 		}
-	case 175:
+	case 235:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.binOpts = &BinOpOptions{VectorMatching: &VectorMatching{Card: CardOneToOne}, ReturnBool: true}
 		}
-	case 176:
+	case 236:
 		syntaxDollar = syntaxS[syntaxpt-5 : syntaxpt+1]
 		{
 			syntaxVAL.binOpts = syntaxDollar[1].binOpts
 			syntaxVAL.binOpts.VectorMatching.On = true
 			syntaxVAL.binOpts.VectorMatching.MatchingLabels = syntaxDollar[4].strs
 		}
-	case 177:
+	case 237:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.binOpts = syntaxDollar[1].binOpts
 			syntaxVAL.binOpts.VectorMatching.On = true
 		}
-	case 178:
+	case 238:
 		syntaxDollar = syntaxS[syntaxpt-5 : syntaxpt+1]
 		{
 			syntaxVAL.binOpts = syntaxDollar[1].binOpts
 			syntaxVAL.binOpts.VectorMatching.MatchingLabels = syntaxDollar[4].strs
 		}
-	case 179:
+	case 239:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.binOpts = syntaxDollar[1].binOpts
 		}
-	case 180:
+	case 240:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.binOpts = syntaxDollar[1].binOpts
 		}
-	case 181:
+	case 241:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.binOpts = syntaxDollar[1].binOpts
 		}
-	case 182:
+	case 242:
 		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
 		{
 			syntaxVAL.binOpts = syntaxDollar[1].binOpts
 			syntaxVAL.binOpts.VectorMatching.Card = CardManyToOne
 		}
-	case 183:
+	case 243:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.binOpts = syntaxDollar[1].binOpts
 			syntaxVAL.binOpts.VectorMatching.Card = CardManyToOne
 		}
-	case 184:
+	case 244:
 		syntaxDollar = syntaxS[syntaxpt-5 : syntaxpt+1]
 		{
 			syntaxVAL.binOpts = syntaxDollar[1].binOpts
 			syntaxVAL.binOpts.VectorMatching.Card = CardManyToOne
 			syntaxVAL.binOpts.VectorMatching.Include = syntaxDollar[4].strs
 		}
-	case 185:
+	case 245:
 		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
 		{
 			syntaxVAL.binOpts = syntaxDollar[1].binOpts
 			syntaxVAL.binOpts.VectorMatching.Card = CardOneToMany
 		}
-	case 186:
+	case 246:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.binOpts = syntaxDollar[1].binOpts
 			syntaxVAL.binOpts.VectorMatching.Card = CardOneToMany
 		}
-	case 187:
+	case 247:
 		syntaxDollar = syntaxS[syntaxpt-5 : syntaxpt+1]
 		{
 			syntaxVAL.binOpts = syntaxDollar[1].binOpts
 			syntaxVAL.binOpts.VectorMatching.Card = CardOneToMany
 			syntaxVAL.binOpts.VectorMatching.Include = syntaxDollar[4].strs
 		}
-	case 188:
+	case 248:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.literalExpr = mustNewLiteralExpr(syntaxDollar[1].str, false)
 		}
-	case 189:
+	case 249:
 		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
 		{
 			syntaxVAL.literalExpr = mustNewLiteralExpr(syntaxDollar[2].str, false)
 		}
-	case 190:
+	case 250:
 		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
 		{
 			syntaxVAL.literalExpr = mustNewLiteralExpr(syntaxDollar[2].str, true)
 		}
-	case 191:
+	case 251:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.metricExpr = NewVectorExpr(syntaxDollar[3].str)
 		}
-	case 192:
+	case 252:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.str = OpTypeVector
 		}
-	case 193:
+	case 253:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpTypeSum
 		}
-	case 194:
+	case 254:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpTypeAvg
 		}
-	case 195:
+	case 255:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpTypeCount
 		}
-	case 196:
+	case 256:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpTypeMax
 		}
-	case 197:
+	case 257:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpTypeMin
 		}
-	case 198:
+	case 258:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpTypeStddev
 		}
-	case 199:
+	case 259:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpTypeStdvar
 		}
-	case 200:
+	case 260:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpTypeBottomK
 		}
-	case 201:
+	case 261:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpTypeTopK
 		}
-	case 202:
+	case 262:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpTypeSort
 		}
-	case 203:
+	case 263:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpTypeSortDesc
 		}
-	case 204:
+	case 264:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpTypeApproxTopK
 		}
-	case 205:
+	case 265:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.op = OpTypeApproxCountDistinct
+		}
+	case 266:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.op = OpTypeHistogramQuantile
+		}
+	case 267:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.op = OpTypeCountValues
+		}
+	case 268:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.op = OpTypeQuantile
+		}
+	case 269:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.op = OpTypeGroup
+		}
+	case 270:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.op = OpTypeLimitK
+		}
+	case 271:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.op = OpTypeLimitRatio
+		}
+	case 272:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpRangeTypeCount
 		}
-	case 206:
+	case 273:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpRangeTypeRate
 		}
-	case 207:
+	case 274:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpRangeTypeRateCounter
 		}
-	case 208:
+	case 275:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpRangeTypeBytes
 		}
-	case 209:
+	case 276:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpRangeTypeBytesRate
 		}
-	case 210:
+	case 277:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpRangeTypeAvg
 		}
-	case 211:
+	case 278:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpRangeTypeSum
 		}
-	case 212:
+	case 279:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpRangeTypeMin
 		}
-	case 213:
+	case 280:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpRangeTypeMax
 		}
-	case 214:
+	case 281:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpRangeTypeStdvar
 		}
-	case 215:
+	case 282:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpRangeTypeStddev
 		}
-	case 216:
+	case 283:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpRangeTypeQuantile
 		}
-	case 217:
+	case 284:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpRangeTypeFirst
 		}
-	case 218:
+	case 285:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpRangeTypeLast
 		}
-	case 219:
+	case 286:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.op = OpRangeTypeAbsent
 		}
-	case 220:
+	case 287:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.op = OpRangeTypeApproxCountDistinct
+		}
+	case 288:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.op = OpRangeTypeHistogram
+		}
+	case 289:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.op = OpRangeTypeChanges
+		}
+	case 290:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.op = OpRangeTypeDeriv
+		}
+	case 291:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.op = OpRangeTypePredictLinear
+		}
+	case 292:
+		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
+		{
+			syntaxVAL.op = OpRangeTypeHoltWinters
+		}
+	case 293:
 		syntaxDollar = syntaxS[syntaxpt-2 : syntaxpt+1]
 		{
 			syntaxVAL.offsetExpr = newOffsetExpr(syntaxDollar[2].dur)
 		}
-	case 221:
+	case 294:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.strs = []string{syntaxDollar[1].str}
 		}
-	case 222:
+	case 295:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.strs = append(syntaxDollar[1].strs, syntaxDollar[3].str)
 		}
-	case 223:
+	case 296:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.grouping = &Grouping{Without: false, Groups: syntaxDollar[3].strs}
 		}
-	case 224:
+	case 297:
 		syntaxDollar = syntaxS[syntaxpt-4 : syntaxpt+1]
 		{
 			syntaxVAL.grouping = &Grouping{Without: true, Groups: syntaxDollar[3].strs}
 		}
-	case 225:
+	case 298:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.grouping = &Grouping{Without: false, Groups: nil}
 		}
-	case 226:
+	case 299:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.grouping = &Grouping{Without: true, Groups: nil}
 		}
-	case 227:
+	case 300:
 		syntaxDollar = syntaxS[syntaxpt-1 : syntaxpt+1]
 		{
 			syntaxVAL.metricExprs = []SampleExpr{syntaxDollar[1].metricExpr}
 		}
-	case 228:
+	case 301:
 		syntaxDollar = syntaxS[syntaxpt-3 : syntaxpt+1]
 		{
 			syntaxVAL.metricExprs = append(syntaxDollar[1].metricExprs, syntaxDollar[3].metricExpr)
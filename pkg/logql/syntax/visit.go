@@ -11,13 +11,17 @@ type RootVisitor interface {
 	VariantsExprVisitor
 
 	VisitLogRange(*LogRangeExpr)
+	VisitSubquery(*SubqueryExpr)
 }
 
 type SampleExprVisitor interface {
 	VisitBinOp(*BinOpExpr)
 	VisitVectorAggregation(*VectorAggregationExpr)
 	VisitRangeAggregation(*RangeAggregationExpr)
+	VisitSubqueryAggregation(*SubqueryAggregationExpr)
 	VisitLabelReplace(*LabelReplaceExpr)
+	VisitLabelJoin(*LabelJoinExpr)
+	VisitFunction(*FunctionExpr)
 	VisitLiteral(*LiteralExpr)
 	VisitVector(*VectorExpr)
 }
@@ -41,6 +45,13 @@ type StageExprVisitor interface {
 	VisitLineFmt(*LineFmtExpr)
 	VisitLogfmtExpressionParser(*LogfmtExpressionParserExpr)
 	VisitLogfmtParser(*LogfmtParserExpr)
+	VisitRedact(*RedactExpr)
+	VisitSampling(*SamplingExpr)
+	VisitContext(*ContextExpr)
+	VisitLookup(*LookupExpr)
+	VisitDedup(*DedupExpr)
+	VisitXMLExpressionParser(*XMLExpressionParserExpr)
+	VisitCSVParser(*CSVParserExpr)
 }
 
 type VariantsExprVisitor interface {
@@ -51,12 +62,15 @@ var _ RootVisitor = &DepthFirstTraversal{}
 
 type DepthFirstTraversal struct {
 	VisitBinOpFn                  func(v RootVisitor, e *BinOpExpr)
+	VisitCSVParserFn              func(v RootVisitor, e *CSVParserExpr)
 	VisitDecolorizeFn             func(v RootVisitor, e *DecolorizeExpr)
 	VisitDropLabelsFn             func(v RootVisitor, e *DropLabelsExpr)
+	VisitFunctionFn               func(v RootVisitor, e *FunctionExpr)
 	VisitJSONExpressionParserFn   func(v RootVisitor, e *JSONExpressionParserExpr)
 	VisitKeepLabelFn              func(v RootVisitor, e *KeepLabelsExpr)
 	VisitLabelFilterFn            func(v RootVisitor, e *LabelFilterExpr)
 	VisitLabelFmtFn               func(v RootVisitor, e *LabelFmtExpr)
+	VisitLabelJoinFn              func(v RootVisitor, e *LabelJoinExpr)
 	VisitLabelParserFn            func(v RootVisitor, e *LineParserExpr)
 	VisitLabelReplaceFn           func(v RootVisitor, e *LabelReplaceExpr)
 	VisitLineFilterFn             func(v RootVisitor, e *LineFilterExpr)
@@ -68,9 +82,17 @@ type DepthFirstTraversal struct {
 	VisitMatchersFn               func(v RootVisitor, e *MatchersExpr)
 	VisitPipelineFn               func(v RootVisitor, e *PipelineExpr)
 	VisitRangeAggregationFn       func(v RootVisitor, e *RangeAggregationExpr)
+	VisitRedactFn                 func(v RootVisitor, e *RedactExpr)
+	VisitSamplingFn               func(v RootVisitor, e *SamplingExpr)
+	VisitContextFn                func(v RootVisitor, e *ContextExpr)
+	VisitLookupFn                 func(v RootVisitor, e *LookupExpr)
+	VisitDedupFn                  func(v RootVisitor, e *DedupExpr)
+	VisitSubqueryFn               func(v RootVisitor, e *SubqueryExpr)
+	VisitSubqueryAggregationFn    func(v RootVisitor, e *SubqueryAggregationExpr)
 	VisitVectorFn                 func(v RootVisitor, e *VectorExpr)
 	VisitVectorAggregationFn      func(v RootVisitor, e *VectorAggregationExpr)
 	VisitVariantsFn               func(v RootVisitor, e *MultiVariantExpr)
+	VisitXMLExpressionParserFn    func(v RootVisitor, e *XMLExpressionParserExpr)
 }
 
 // VisitBinOp implements RootVisitor.
@@ -166,6 +188,30 @@ func (v *DepthFirstTraversal) VisitLabelReplace(e *LabelReplaceExpr) {
 	}
 }
 
+// VisitLabelJoin implements RootVisitor.
+func (v *DepthFirstTraversal) VisitLabelJoin(e *LabelJoinExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitLabelJoinFn != nil {
+		v.VisitLabelJoinFn(v, e)
+	} else {
+		e.Left.Accept(v)
+	}
+}
+
+// VisitFunction implements RootVisitor.
+func (v *DepthFirstTraversal) VisitFunction(e *FunctionExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitFunctionFn != nil {
+		v.VisitFunctionFn(v, e)
+	} else if e.Left != nil {
+		e.Left.Accept(v)
+	}
+}
+
 // VisitLineFilter implements RootVisitor.
 func (v *DepthFirstTraversal) VisitLineFilter(e *LineFilterExpr) {
 	if e == nil {
@@ -272,6 +318,100 @@ func (v *DepthFirstTraversal) VisitRangeAggregation(e *RangeAggregationExpr) {
 	}
 }
 
+// VisitRedact implements RootVisitor.
+func (v *DepthFirstTraversal) VisitRedact(e *RedactExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitRedactFn != nil {
+		v.VisitRedactFn(v, e)
+	}
+}
+
+// VisitSampling implements RootVisitor.
+func (v *DepthFirstTraversal) VisitSampling(e *SamplingExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitSamplingFn != nil {
+		v.VisitSamplingFn(v, e)
+	}
+}
+
+// VisitContext implements RootVisitor.
+func (v *DepthFirstTraversal) VisitContext(e *ContextExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitContextFn != nil {
+		v.VisitContextFn(v, e)
+	}
+}
+
+// VisitLookup implements RootVisitor.
+func (v *DepthFirstTraversal) VisitLookup(e *LookupExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitLookupFn != nil {
+		v.VisitLookupFn(v, e)
+	}
+}
+
+// VisitDedup implements RootVisitor.
+func (v *DepthFirstTraversal) VisitDedup(e *DedupExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitDedupFn != nil {
+		v.VisitDedupFn(v, e)
+	}
+}
+
+// VisitXMLExpressionParser implements RootVisitor.
+func (v *DepthFirstTraversal) VisitXMLExpressionParser(e *XMLExpressionParserExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitXMLExpressionParserFn != nil {
+		v.VisitXMLExpressionParserFn(v, e)
+	}
+}
+
+// VisitCSVParser implements RootVisitor.
+func (v *DepthFirstTraversal) VisitCSVParser(e *CSVParserExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitCSVParserFn != nil {
+		v.VisitCSVParserFn(v, e)
+	}
+}
+
+// VisitSubquery implements RootVisitor.
+func (v *DepthFirstTraversal) VisitSubquery(e *SubqueryExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitSubqueryFn != nil {
+		v.VisitSubqueryFn(v, e)
+	} else {
+		e.Left.Accept(v)
+	}
+}
+
+// VisitSubqueryAggregation implements RootVisitor.
+func (v *DepthFirstTraversal) VisitSubqueryAggregation(e *SubqueryAggregationExpr) {
+	if e == nil {
+		return
+	}
+	if v.VisitSubqueryAggregationFn != nil {
+		v.VisitSubqueryAggregationFn(v, e)
+	} else {
+		e.Left.Accept(v)
+	}
+}
+
 // VisitVector implements RootVisitor.
 func (v *DepthFirstTraversal) VisitVector(e *VectorExpr) {
 	if e == nil {
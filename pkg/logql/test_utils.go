@@ -63,9 +63,13 @@ func (q MockQuerier) SelectLogs(_ context.Context, req SelectLogParams) (iter.En
 
 	matchers := expr.Matchers()
 
+	shards, keep, err := DedupShards(req.Shards, expr)
+	if err != nil {
+		return nil, err
+	}
 	var shard *index.ShardAnnotation
-	if len(req.Shards) > 0 {
-		shard, err = q.extractOldShard(req.Shards)
+	if len(shards) > 0 {
+		shard, err = q.extractOldShard(shards)
 		if err != nil {
 			return nil, err
 		}
@@ -81,6 +85,9 @@ outer:
 		if shard != nil && labels.StableHash(ls)%uint64(shard.Of) != uint64(shard.Shard) {
 			continue
 		}
+		if keep != nil && !keep(ls) {
+			continue
+		}
 
 		for _, matcher := range matchers {
 			if !matcher.Matches(ls.Get(matcher.Name)) {
@@ -200,9 +207,13 @@ func (q MockQuerier) SelectSamples(_ context.Context, req SelectSampleParams) (i
 
 	matchers := selector.Matchers()
 
+	shards, keep, err := DedupShards(req.Shards, expr)
+	if err != nil {
+		return nil, err
+	}
 	var shard *index.ShardAnnotation
-	if len(req.Shards) > 0 {
-		shard, err = q.extractOldShard(req.Shards)
+	if len(shards) > 0 {
+		shard, err = q.extractOldShard(shards)
 		if err != nil {
 			return nil, err
 		}
@@ -218,6 +229,9 @@ outer:
 		if shard != nil && labels.StableHash(ls)%uint64(shard.Of) != uint64(shard.Shard) {
 			continue
 		}
+		if keep != nil && !keep(ls) {
+			continue
+		}
 
 		for _, matcher := range matchers {
 			if !matcher.Matches(ls.Get(matcher.Name)) {
@@ -0,0 +1,101 @@
+package logql
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+// countValuesGrouping returns the grouping of count_values, which also groups
+// the series by the label holding their value.
+func countValuesGrouping(g *syntax.Grouping, label string) *syntax.Grouping {
+	if g.Without {
+		return &syntax.Grouping{
+			Groups: slices.DeleteFunc(slices.Clone(g.Groups), func(l string) bool {
+				return l == label
+			}),
+			Without: true,
+		}
+	}
+	groups := slices.Clone(g.Groups)
+	if !slices.Contains(groups, label) {
+		groups = append(groups, label)
+	}
+	return &syntax.Grouping{Groups: groups}
+}
+
+func newCountValuesEvaluator(nextEvaluator StepEvaluator, label string) *CountValuesEvaluator {
+	return &CountValuesEvaluator{
+		nextEvaluator: nextEvaluator,
+		label:         label,
+		lb:            labels.NewBuilder(labels.EmptyLabels()),
+	}
+}
+
+// CountValuesEvaluator stores the value of each series in a label, so that
+// the series can be counted by value.
+type CountValuesEvaluator struct {
+	nextEvaluator StepEvaluator
+	label         string
+	lb            *labels.Builder
+}
+
+func (e *CountValuesEvaluator) Next() (bool, int64, StepResult) {
+	next, ts, r := e.nextEvaluator.Next()
+	if !next {
+		return false, 0, SampleVector{}
+	}
+	vec := r.SampleVector()
+	for i, s := range vec {
+		e.lb.Reset(s.Metric)
+		e.lb.Set(e.label, strconv.FormatFloat(s.F, 'f', -1, 64))
+		vec[i].Metric = e.lb.Labels()
+	}
+	return next, ts, SampleVector(vec)
+}
+
+func (e *CountValuesEvaluator) Close() error {
+	return e.nextEvaluator.Close()
+}
+
+func (e *CountValuesEvaluator) Error() error {
+	return e.nextEvaluator.Error()
+}
+
+func (e *CountValuesEvaluator) Explain(parent Node) {
+	b := parent.Childf("%s CountValues", e.label)
+	e.nextEvaluator.Explain(b)
+}
+
+// limitRatio keeps the series of vec selected by limit_ratio. As in
+// Prometheus, a series is selected depending on the hash of its labels, so
+// that the same series are selected at every step and on every shard. A
+// negative ratio selects the complement of the series selected by 1+ratio.
+func limitRatio(ratio float64, vec []promql.Sample) []promql.Sample {
+	return slices.DeleteFunc(vec, func(s promql.Sample) bool {
+		offset := float64(s.Metric.Hash()) / float64(math.MaxUint64)
+		keep := (ratio >= 0 && offset < ratio) || (ratio < 0 && offset >= 1+ratio)
+		return !keep
+	})
+}
+
+// limitK adds s to the series kept by limitk, which are the k series with the
+// lowest hash of their labels, sorted by hash. Like limit_ratio, this selects
+// the same series at every step and on every shard.
+func limitK(k int, kept []promql.Sample, s promql.Sample) []promql.Sample {
+	hash := s.Metric.Hash()
+	i := sort.Search(len(kept), func(i int) bool { return kept[i].Metric.Hash() > hash })
+	if i == k {
+		return kept
+	}
+	if len(kept) == k {
+		kept = kept[:k-1]
+	}
+	return slices.Insert(kept, i, s)
+}
@@ -0,0 +1,134 @@
+package logql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/iter"
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+func TestEngine_VectorAggregations(t *testing.T) {
+	querier := errorIteratorQuerier{
+		samples: func() []iter.SampleIterator {
+			return []iter.SampleIterator{
+				iter.NewSeriesIterator(newSeries(10, identity, `{app="foo", pod="a"}`)),
+				iter.NewSeriesIterator(newSeries(5, identity, `{app="foo", pod="b"}`)),
+				iter.NewSeriesIterator(newSeries(5, identity, `{app="bar", pod="c"}`)),
+			}
+		},
+	}
+	eng := NewEngine(EngineOpts{}, querier, NoLimits, log.NewNopLogger())
+
+	for _, tc := range []struct {
+		qs       string
+		expected promql.Vector
+	}{
+		{
+			`count_values("count", count_over_time({app=~".+"}[10s]))`,
+			promql.Vector{
+				{T: 10000, F: 2, Metric: labels.FromStrings("count", "4")},
+				{T: 10000, F: 1, Metric: labels.FromStrings("count", "9")},
+			},
+		},
+		{
+			`count_values("count", count_over_time({app=~".+"}[10s])) by (app)`,
+			promql.Vector{
+				{T: 10000, F: 1, Metric: labels.FromStrings("app", "bar", "count", "4")},
+				{T: 10000, F: 1, Metric: labels.FromStrings("app", "foo", "count", "4")},
+				{T: 10000, F: 1, Metric: labels.FromStrings("app", "foo", "count", "9")},
+			},
+		},
+		{
+			`quantile(0.5, count_over_time({app=~".+"}[10s]))`,
+			promql.Vector{
+				{T: 10000, F: 4, Metric: labels.EmptyLabels()},
+			},
+		},
+		{
+			`quantile by (app) (0.5, count_over_time({app=~".+"}[10s]))`,
+			promql.Vector{
+				{T: 10000, F: 4, Metric: labels.FromStrings("app", "bar")},
+				{T: 10000, F: 6.5, Metric: labels.FromStrings("app", "foo")},
+			},
+		},
+		{
+			`group by (app) (count_over_time({app=~".+"}[10s]))`,
+			promql.Vector{
+				{T: 10000, F: 1, Metric: labels.FromStrings("app", "bar")},
+				{T: 10000, F: 1, Metric: labels.FromStrings("app", "foo")},
+			},
+		},
+		{
+			// limitk keeps the series with the lowest hash of their labels
+			`limitk(2, count_over_time({app=~".+"}[10s]))`,
+			promql.Vector{
+				{T: 10000, F: 9, Metric: labels.FromStrings("app", "foo", "pod", "a")},
+				{T: 10000, F: 4, Metric: labels.FromStrings("app", "foo", "pod", "b")},
+			},
+		},
+		{
+			`limitk by (app) (1, count_over_time({app=~".+"}[10s]))`,
+			promql.Vector{
+				{T: 10000, F: 4, Metric: labels.FromStrings("app", "bar", "pod", "c")},
+				{T: 10000, F: 4, Metric: labels.FromStrings("app", "foo", "pod", "b")},
+			},
+		},
+		{
+			`limit_ratio(1, count_over_time({app=~".+"}[10s]))`,
+			promql.Vector{
+				{T: 10000, F: 4, Metric: labels.FromStrings("app", "bar", "pod", "c")},
+				{T: 10000, F: 9, Metric: labels.FromStrings("app", "foo", "pod", "a")},
+				{T: 10000, F: 4, Metric: labels.FromStrings("app", "foo", "pod", "b")},
+			},
+		},
+		{
+			`limit_ratio(0.5, count_over_time({app=~".+"}[10s])) or limit_ratio(-0.5, count_over_time({app=~".+"}[10s]))`,
+			promql.Vector{
+				{T: 10000, F: 4, Metric: labels.FromStrings("app", "bar", "pod", "c")},
+				{T: 10000, F: 9, Metric: labels.FromStrings("app", "foo", "pod", "a")},
+				{T: 10000, F: 4, Metric: labels.FromStrings("app", "foo", "pod", "b")},
+			},
+		},
+	} {
+		t.Run(tc.qs, func(t *testing.T) {
+			params, err := NewLiteralParams(tc.qs, time.Unix(10, 0), time.Unix(10, 0), 0, 0, logproto.FORWARD, 0, nil, nil)
+			require.NoError(t, err)
+
+			res, err := eng.Query(params).Exec(user.InjectOrgID(context.Background(), "fake"))
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, res.Data)
+		})
+	}
+}
+
+func TestLimitK(t *testing.T) {
+	series := []promql.Sample{
+		{F: 1, Metric: labels.FromStrings("pod", "a")},
+		{F: 2, Metric: labels.FromStrings("pod", "b")},
+		{F: 3, Metric: labels.FromStrings("pod", "c")},
+		{F: 4, Metric: labels.FromStrings("pod", "d")},
+	}
+	var expected []promql.Sample
+	for _, s := range series {
+		expected = limitK(2, expected, s)
+	}
+	require.Len(t, expected, 2)
+	require.Less(t, expected[0].Metric.Hash(), expected[1].Metric.Hash())
+
+	// The same series are kept whatever the order of the input.
+	for i := range series {
+		var kept []promql.Sample
+		for j := range series {
+			kept = limitK(2, kept, series[(i+j)%len(series)])
+		}
+		require.Equal(t, expected, kept)
+	}
+}
@@ -69,6 +69,7 @@ const (
 	BloomFilterCache          CacheType = "bloom-filter"          //nolint:staticcheck
 	BloomBlocksCache          CacheType = "bloom-blocks"          //nolint:staticcheck
 	BloomMetasCache           CacheType = "bloom-metas"           //nolint:staticcheck
+	DataObjCache              CacheType = "dataobj"               //nolint:staticcheck
 )
 
 // NewContext creates a new statistics context
@@ -249,6 +250,10 @@ func (s *Store) ChunksDownloadDuration() time.Duration {
 func (s *Summary) Merge(m Summary) {
 	s.Splits += m.Splits
 	s.Shards += m.Shards
+	// The estimated cost is the one of the whole query, which is not split.
+	if m.EstimatedCost > s.EstimatedCost {
+		s.EstimatedCost = m.EstimatedCost
+	}
 }
 
 func (q *Querier) Merge(m Querier) {
@@ -537,6 +542,11 @@ func (c *Context) AddSplitQueries(num int64) {
 	atomic.AddInt64(&c.result.Summary.Splits, num)
 }
 
+// SetEstimatedCost sets the cost of the query estimated before its execution.
+func (c *Context) SetEstimatedCost(cost int64) {
+	atomic.StoreInt64(&c.result.Summary.EstimatedCost, cost)
+}
+
 func (c *Context) AddPrePredicateDecompressedRows(i int64) {
 	atomic.AddInt64(&c.store.Dataobj.PrePredicateDecompressedRows, i)
 }
@@ -673,6 +683,7 @@ func (s Summary) kvList() []any {
 		"Summary.PostFilterLines", s.TotalPostFilterLines,
 		"Summary.ExecTime", ConvertSecondsToNanoseconds(s.ExecTime),
 		"Summary.QueueTime", ConvertSecondsToNanoseconds(s.QueueTime),
+		"Summary.EstimatedCost", humanize.Bytes(uint64(s.EstimatedCost)),
 	}
 }
 
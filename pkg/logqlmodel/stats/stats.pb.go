@@ -232,6 +232,8 @@ type Summary struct {
 	TotalPostFilterLines int64 `protobuf:"varint,11,opt,name=totalPostFilterLines,proto3" json:"totalPostFilterLines"`
 	// Total bytes processed of metadata.
 	TotalStructuredMetadataBytesProcessed int64 `protobuf:"varint,12,opt,name=totalStructuredMetadataBytesProcessed,proto3" json:"totalStructuredMetadataBytesProcessed"`
+	// Estimated cost of the query, computed before its execution.
+	EstimatedCost int64 `protobuf:"varint,13,opt,name=estimatedCost,proto3" json:"estimatedCost"`
 }
 
 func (m *Summary) Reset()      { *m = Summary{} }
@@ -350,6 +352,13 @@ func (m *Summary) GetTotalStructuredMetadataBytesProcessed() int64 {
 	return 0
 }
 
+func (m *Summary) GetEstimatedCost() int64 {
+	if m != nil {
+		return m.EstimatedCost
+	}
+	return 0
+}
+
 // Statistics from Index queries
 // TODO(owen-d): include bytes.
 // Needs some index methods added to return _sized_ chunk refs to know
@@ -1052,112 +1061,113 @@ func init() {
 func init() { proto.RegisterFile("pkg/logqlmodel/stats/stats.proto", fileDescriptor_6cdfe5d2aea33ebb) }
 
 var fileDescriptor_6cdfe5d2aea33ebb = []byte{
-	// 1676 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x58, 0xbd, 0x6f, 0xdc, 0x46,
-	0x16, 0xd7, 0x6a, 0xcd, 0x95, 0x3c, 0xfa, 0xb2, 0x47, 0xf2, 0x79, 0x7d, 0xf6, 0x2d, 0xe5, 0x3d,
-	0x1b, 0xe7, 0xc3, 0x01, 0x5a, 0xd8, 0x3e, 0x20, 0x48, 0x10, 0x03, 0x09, 0x25, 0x0b, 0x30, 0x20,
-	0x23, 0xca, 0x53, 0x9c, 0x04, 0x49, 0x45, 0x91, 0xa3, 0x15, 0x6d, 0x2e, 0xb9, 0xe2, 0x87, 0x6c,
-	0x01, 0x01, 0x92, 0x3f, 0x21, 0x7d, 0x90, 0x36, 0x48, 0x93, 0x2a, 0x55, 0xea, 0x34, 0x2e, 0x5d,
-	0xba, 0x22, 0x62, 0xb9, 0x09, 0x58, 0x19, 0x29, 0x83, 0x14, 0xc1, 0xbc, 0x99, 0x25, 0x87, 0x1f,
-	0xbb, 0x5a, 0x37, 0x22, 0xdf, 0xef, 0xfd, 0xde, 0x9b, 0xd1, 0xe3, 0xbc, 0x8f, 0x59, 0xb2, 0x3e,
-	0x7c, 0xd2, 0xef, 0xb9, 0x7e, 0xff, 0xc8, 0x1d, 0xf8, 0x36, 0x73, 0x7b, 0x61, 0x64, 0x46, 0xa1,
-	0xf8, 0xbb, 0x31, 0x0c, 0xfc, 0xc8, 0xa7, 0x1a, 0x0a, 0xff, 0x5c, 0xeb, 0xfb, 0x7d, 0x1f, 0x91,
-	0x1e, 0x7f, 0x13, 0xca, 0xee, 0x0f, 0xb3, 0xa4, 0x05, 0x2c, 0x8c, 0xdd, 0x88, 0xbe, 0x4b, 0xe6,
-	0xc2, 0x78, 0x30, 0x30, 0x83, 0x93, 0x76, 0x63, 0xbd, 0x71, 0x6b, 0xe1, 0xce, 0xf2, 0x86, 0x70,
-	0xb3, 0x27, 0x50, 0x63, 0xe5, 0x79, 0xa2, 0xcf, 0xa4, 0x89, 0x3e, 0xa2, 0xc1, 0xe8, 0x85, 0x9b,
-	0x1e, 0xc5, 0x2c, 0x70, 0x58, 0xd0, 0x9e, 0x2d, 0x98, 0x7e, 0x2c, 0xd0, 0xdc, 0x54, 0xd2, 0x60,
-	0xf4, 0x42, 0xef, 0x91, 0x79, 0xc7, 0xeb, 0xb3, 0x30, 0x62, 0x41, 0xbb, 0x89, 0xb6, 0x2b, 0xd2,
-	0xf6, 0x81, 0x84, 0x8d, 0x0b, 0xd2, 0x38, 0x23, 0x42, 0xf6, 0x46, 0xff, 0x4f, 0x5a, 0x96, 0x69,
-	0x1d, 0xb2, 0xb0, 0x7d, 0x0e, 0x8d, 0x97, 0xa4, 0xf1, 0x26, 0x82, 0xc6, 0x92, 0x34, 0xd5, 0x90,
-	0x04, 0x92, 0x4b, 0x6f, 0x13, 0xcd, 0xf1, 0x6c, 0xf6, 0xac, 0xad, 0xa1, 0xd1, 0x62, 0xb6, 0xa2,
-	0xcd, 0x9e, 0xe5, 0x36, 0x48, 0x01, 0xf1, 0xe8, 0x7e, 0x77, 0x8e, 0xb4, 0x36, 0x33, 0x6b, 0xeb,
-	0x30, 0xf6, 0x9e, 0xc8, 0x30, 0x2d, 0xaa, 0x4b, 0x2a, 0x2b, 0x72, 0x0a, 0x88, 0x47, 0xbe, 0xe0,
-	0xec, 0x24, 0x13, 0x75, 0x41, 0xfe, 0x9f, 0x05, 0xf8, 0x61, 0x64, 0x58, 0x8a, 0x36, 0xcb, 0xd2,
-	0x46, 0x72, 0x40, 0x3e, 0xe9, 0x26, 0x59, 0x40, 0x9a, 0xf8, 0xa6, 0x32, 0x28, 0x45, 0xd3, 0x55,
-	0x69, 0xaa, 0x12, 0x41, 0x15, 0xe8, 0x36, 0x59, 0x3c, 0xf6, 0xdd, 0x78, 0xc0, 0xa4, 0x17, 0xad,
-	0xc6, 0xcb, 0x9a, 0xf4, 0x52, 0x60, 0x42, 0x41, 0xe2, 0x7e, 0x42, 0xfe, 0x95, 0x47, 0xbb, 0x69,
-	0x4d, 0xf2, 0xa3, 0x32, 0xa1, 0x20, 0xf1, 0x7f, 0xca, 0x35, 0xf7, 0x99, 0x2b, 0xdd, 0xcc, 0x4d,
-	0xfa, 0xa7, 0x14, 0x22, 0xa8, 0x02, 0xfd, 0x92, 0xac, 0x3a, 0x5e, 0x18, 0x99, 0x5e, 0xf4, 0x90,
-	0x45, 0x81, 0x63, 0x49, 0x67, 0xf3, 0x35, 0xce, 0xae, 0x4a, 0x67, 0x75, 0x06, 0x50, 0x07, 0x76,
-	0x7f, 0x69, 0x91, 0x39, 0x99, 0x26, 0xf4, 0x11, 0xb9, 0xbc, 0x7f, 0x12, 0xb1, 0x70, 0x37, 0xf0,
-	0x2d, 0x16, 0x86, 0xcc, 0xde, 0x65, 0xc1, 0x1e, 0xb3, 0x7c, 0xcf, 0xc6, 0x03, 0xd3, 0x34, 0xae,
-	0xa6, 0x89, 0x3e, 0x8e, 0x02, 0xe3, 0x14, 0xdc, 0xad, 0xeb, 0x78, 0xb5, 0x6e, 0x67, 0x73, 0xb7,
-	0x63, 0x28, 0x30, 0x4e, 0x41, 0x1f, 0x90, 0xd5, 0xc8, 0x8f, 0x4c, 0xd7, 0x28, 0x2c, 0x8b, 0x67,
-	0xae, 0x69, 0x5c, 0xe6, 0x41, 0xa8, 0x51, 0x43, 0x1d, 0x98, 0xb9, 0xda, 0x29, 0x2c, 0x85, 0x67,
-	0x50, 0x75, 0x55, 0x54, 0x43, 0x1d, 0x48, 0x6f, 0x91, 0x79, 0xf6, 0x8c, 0x59, 0x9f, 0x38, 0x03,
-	0x86, 0xa7, 0xaf, 0x61, 0x2c, 0xf2, 0x02, 0x30, 0xc2, 0x20, 0x7b, 0xa3, 0xff, 0x23, 0xe7, 0x8f,
-	0x62, 0x16, 0x33, 0xa4, 0xb6, 0x90, 0xba, 0x94, 0x26, 0x7a, 0x0e, 0x42, 0xfe, 0x4a, 0x37, 0x08,
-	0x09, 0xe3, 0x7d, 0x51, 0x7a, 0x42, 0x3c, 0x47, 0x4d, 0x63, 0x39, 0x4d, 0x74, 0x05, 0x05, 0xe5,
-	0x9d, 0xee, 0x90, 0x35, 0xdc, 0xdd, 0x7d, 0x2f, 0x12, 0xc7, 0x31, 0x8a, 0x03, 0x8f, 0xd9, 0x78,
-	0x68, 0x9a, 0x46, 0x3b, 0x4d, 0xf4, 0x5a, 0x3d, 0xd4, 0xa2, 0xb4, 0x4b, 0x5a, 0xe1, 0xd0, 0x75,
-	0xa2, 0xb0, 0x7d, 0x1e, 0xed, 0x09, 0xcf, 0x5f, 0x81, 0x80, 0x7c, 0x22, 0xe7, 0xd0, 0x0c, 0xec,
-	0xb0, 0x4d, 0x14, 0x0e, 0x22, 0x20, 0x9f, 0xd9, 0xae, 0x76, 0xfd, 0x30, 0xda, 0x76, 0xdc, 0x88,
-	0x05, 0x18, 0xbd, 0xf6, 0x42, 0x69, 0x57, 0x25, 0x3d, 0xd4, 0xa2, 0xf4, 0x6b, 0x72, 0x13, 0xf1,
-	0xbd, 0x28, 0x88, 0xad, 0x28, 0x0e, 0x98, 0xfd, 0x90, 0x45, 0xa6, 0x6d, 0x46, 0x66, 0xe9, 0x48,
-	0x2c, 0xa2, 0xfb, 0xff, 0xa6, 0x89, 0x3e, 0x9d, 0x01, 0x4c, 0x47, 0xeb, 0xfe, 0xd9, 0x20, 0x1a,
-	0x56, 0x5e, 0x7a, 0x9b, 0x2c, 0xa0, 0xc9, 0x26, 0xaf, 0x99, 0xa1, 0xcc, 0x96, 0x15, 0x9e, 0xd5,
-	0x0a, 0x0c, 0xaa, 0x40, 0x3f, 0x20, 0x17, 0x86, 0xd9, 0x3f, 0x24, 0xed, 0x44, 0x3a, 0xac, 0xa5,
-	0x89, 0x5e, 0xd1, 0x41, 0x05, 0xa1, 0xef, 0x91, 0x65, 0x11, 0xd7, 0xad, 0x38, 0x30, 0x23, 0xc7,
-	0xf7, 0xe4, 0xd9, 0xa7, 0x69, 0xa2, 0x97, 0x34, 0x50, 0x92, 0xf9, 0xea, 0x71, 0xc8, 0x6c, 0xc3,
-	0xf5, 0xfd, 0x81, 0x70, 0x2a, 0xfa, 0xd0, 0xbc, 0x58, 0xbd, 0xac, 0x83, 0x0a, 0xd2, 0x7d, 0x9f,
-	0xcc, 0xc9, 0x1e, 0xc9, 0x7b, 0x44, 0x18, 0xf9, 0x01, 0x2b, 0xb5, 0x95, 0x3d, 0x8e, 0xe5, 0x3d,
-	0x02, 0x29, 0x20, 0x1e, 0xdd, 0x9f, 0x66, 0xc9, 0xfc, 0x83, 0xbc, 0x15, 0x2e, 0x62, 0x64, 0x80,
-	0xf1, 0x22, 0x26, 0x8a, 0x8d, 0x66, 0x5c, 0xe0, 0xb5, 0x55, 0xc5, 0xa1, 0x20, 0xd1, 0x6d, 0x42,
-	0x95, 0x78, 0x3e, 0x34, 0x23, 0xb4, 0x15, 0x21, 0xfc, 0x47, 0x9a, 0xe8, 0x35, 0x5a, 0xa8, 0xc1,
-	0xb2, 0xd5, 0x0d, 0x94, 0x43, 0x19, 0xc4, 0x7c, 0x75, 0x89, 0x43, 0x41, 0xe2, 0xc1, 0xcf, 0xd3,
-	0x7f, 0x8f, 0x79, 0x91, 0xac, 0x16, 0x18, 0xfc, 0xa2, 0x06, 0x4a, 0x72, 0x1e, 0x2f, 0x6d, 0xea,
-	0x78, 0xfd, 0xa5, 0x11, 0x0d, 0xf5, 0xd9, 0xc2, 0xf2, 0x58, 0xb0, 0x03, 0x79, 0xda, 0xf2, 0x85,
-	0x33, 0x0d, 0x94, 0x64, 0xfa, 0x11, 0xb9, 0xa4, 0x20, 0x5b, 0xfe, 0x53, 0xcf, 0xf5, 0x4d, 0x3b,
-	0x8b, 0xda, 0x95, 0x34, 0xd1, 0xeb, 0x09, 0x50, 0x0f, 0xf3, 0x6f, 0x60, 0x15, 0x30, 0x2c, 0x66,
-	0xcd, 0xfc, 0x1b, 0x54, 0xb5, 0x50, 0x83, 0x51, 0x8b, 0x5c, 0xe1, 0x95, 0xeb, 0x04, 0xd8, 0x01,
-	0x0b, 0x98, 0x67, 0x31, 0x3b, 0x4f, 0xbe, 0xf6, 0x12, 0x9e, 0xcb, 0x9b, 0x69, 0xa2, 0x5f, 0x1f,
-	0x4b, 0x1a, 0x65, 0x28, 0x8c, 0xf7, 0x43, 0x37, 0xc9, 0x45, 0x54, 0x3e, 0x0a, 0x99, 0xfd, 0xe9,
-	0x9d, 0xfb, 0x5e, 0xdf, 0xf1, 0x58, 0x7b, 0x19, 0x9d, 0x5f, 0x4a, 0x13, 0xbd, 0xaa, 0x84, 0x2a,
-	0x94, 0x8f, 0x50, 0xa5, 0x01, 0x85, 0x63, 0x63, 0x46, 0xa8, 0x51, 0x90, 0x80, 0x1d, 0x84, 0xdb,
-	0x2c, 0xb2, 0x0e, 0xb3, 0xe6, 0xa0, 0x06, 0xa9, 0xa0, 0x85, 0x1a, 0x8c, 0x7e, 0x4e, 0xda, 0x96,
-	0x8f, 0x39, 0xe3, 0xf8, 0xde, 0xa6, 0xef, 0x45, 0x81, 0xef, 0xee, 0x98, 0x11, 0xf3, 0xac, 0x13,
-	0xec, 0x1f, 0x4d, 0xe3, 0x5a, 0x9a, 0xe8, 0x63, 0x39, 0x30, 0x56, 0x43, 0x6d, 0x72, 0x6d, 0xe8,
-	0x0c, 0x19, 0xef, 0xb4, 0x9f, 0x05, 0xe6, 0x70, 0xc8, 0x02, 0x91, 0xe5, 0xcc, 0x16, 0xf5, 0x59,
-	0xf4, 0x9b, 0xf5, 0x34, 0xd1, 0x27, 0xf2, 0x60, 0xa2, 0x96, 0xcf, 0xda, 0xfc, 0x13, 0xf9, 0xfb,
-	0x8f, 0xe5, 0xec, 0x32, 0x9a, 0xb5, 0xb7, 0x04, 0x9a, 0xcf, 0xda, 0x92, 0x06, 0xa3, 0x97, 0xee,
-	0x1f, 0xf3, 0x64, 0x4e, 0xb2, 0x70, 0xb3, 0x01, 0xdb, 0x0d, 0x98, 0xed, 0x58, 0x66, 0xc4, 0xb6,
-	0x98, 0xe5, 0x0f, 0x86, 0x81, 0x28, 0xdc, 0xfe, 0xd3, 0x51, 0xf1, 0x15, 0x9b, 0x9d, 0xc0, 0x83,
-	0x89, 0x5a, 0xda, 0x27, 0xff, 0x1a, 0xa7, 0xc7, 0x2e, 0x20, 0x53, 0xe6, 0x7a, 0x9a, 0xe8, 0x93,
-	0x89, 0x30, 0x59, 0x4d, 0xbf, 0x6f, 0x90, 0xde, 0x38, 0xc6, 0x98, 0x0e, 0x24, 0x13, 0xec, 0x6e,
-	0x9a, 0xe8, 0x6f, 0x6b, 0x0a, 0x6f, 0x6b, 0xc0, 0xb3, 0x86, 0x77, 0x9e, 0xcc, 0x06, 0x63, 0x2c,
-	0x6a, 0x1d, 0x66, 0x4d, 0x45, 0x09, 0x55, 0x88, 0x3e, 0x26, 0x9d, 0x02, 0x58, 0x0d, 0xa7, 0x48,
-	0x87, 0x6e, 0x9a, 0xe8, 0x67, 0x30, 0xe1, 0x0c, 0x3d, 0xfd, 0x8a, 0xdc, 0x28, 0x30, 0xc6, 0x05,
-	0x51, 0xa4, 0xcc, 0xad, 0x34, 0xd1, 0xa7, 0xe2, 0xc3, 0x54, 0x2c, 0x5e, 0x9e, 0xf3, 0x46, 0x8d,
-	0xb1, 0x9a, 0xcb, 0xcb, 0x73, 0x51, 0x03, 0x25, 0x99, 0x77, 0xa2, 0xa1, 0xd9, 0x67, 0xe1, 0x9e,
-	0x65, 0x7a, 0xf9, 0xb0, 0x86, 0x9d, 0x48, 0xc5, 0xa1, 0x20, 0xd1, 0x7b, 0x64, 0x05, 0x65, 0xa5,
-	0x9c, 0x8b, 0x29, 0x6d, 0x35, 0x4d, 0xf4, 0xb2, 0x0a, 0xca, 0x00, 0x9f, 0xc9, 0x4a, 0x90, 0x08,
-	0x0f, 0xc9, 0x67, 0xb2, 0x3a, 0x3d, 0xd4, 0xa2, 0x7c, 0x10, 0xe2, 0xf8, 0xa8, 0x97, 0x2e, 0xe4,
-	0x83, 0x90, 0x02, 0x83, 0x2a, 0x64, 0x7d, 0x9c, 0x87, 0xe0, 0xc3, 0x63, 0xd3, 0x71, 0xcd, 0x7d,
-	0x97, 0xc9, 0x99, 0x2d, 0xef, 0xe3, 0x05, 0x2d, 0xd4, 0x60, 0x59, 0x73, 0xdb, 0x35, 0xfb, 0xac,
-	0xd0, 0x8e, 0x96, 0x4a, 0xcd, 0xad, 0x4c, 0x80, 0x7a, 0xb8, 0xfb, 0xb3, 0x46, 0x34, 0xac, 0xeb,
-	0xfc, 0xa3, 0x1e, 0x32, 0xd3, 0x16, 0x45, 0x1e, 0xa3, 0xa3, 0x34, 0xfb, 0xa2, 0x06, 0x4a, 0x72,
-	0xc1, 0x56, 0x54, 0x53, 0xad, 0xc6, 0x56, 0xd4, 0xcf, 0x92, 0xcc, 0x73, 0xcf, 0xae, 0x64, 0x4a,
-	0x2b, 0xcf, 0xbd, 0x8a, 0x12, 0xaa, 0x50, 0xd9, 0x89, 0x5a, 0xd1, 0x2b, 0x4e, 0xc4, 0x36, 0xaa,
-	0x10, 0x3f, 0x64, 0xe5, 0x7d, 0xcc, 0xe7, 0x87, 0xac, 0xbc, 0x8b, 0x32, 0xc0, 0xcd, 0x31, 0xc6,
-	0x5b, 0xf1, 0xd0, 0xc5, 0xf4, 0x09, 0xd5, 0x33, 0x5a, 0x52, 0x41, 0x19, 0xc0, 0x23, 0x5e, 0xba,
-	0x32, 0x10, 0xe5, 0x88, 0x97, 0x6e, 0x0b, 0x65, 0x80, 0x0e, 0xc9, 0x7a, 0x16, 0xd8, 0x71, 0xd5,
-	0x40, 0x9c, 0xd4, 0x1b, 0x69, 0xa2, 0x9f, 0xc9, 0x85, 0x33, 0x19, 0xf4, 0x84, 0xfc, 0xdb, 0x9e,
-	0xa2, 0x8e, 0x8b, 0x43, 0xfe, 0x9f, 0x34, 0xd1, 0xa7, 0xa1, 0xc3, 0x34, 0xa4, 0xee, 0xaf, 0x4d,
-	0xa2, 0xe1, 0x8f, 0x01, 0xbc, 0x9c, 0x30, 0x71, 0x91, 0xdb, 0xf6, 0x63, 0xaf, 0x30, 0x56, 0xab,
-	0x38, 0x14, 0x24, 0x7e, 0x33, 0x60, 0xa3, 0xeb, 0xdf, 0x51, 0xcc, 0x07, 0x74, 0x31, 0x1e, 0x6a,
-	0xe2, 0x66, 0x50, 0xd6, 0x41, 0x05, 0xa1, 0xef, 0x90, 0x25, 0x89, 0xe1, 0xc4, 0x2a, 0xae, 0xe4,
-	0x9a, 0x71, 0x31, 0x4d, 0xf4, 0xa2, 0x02, 0x8a, 0x22, 0x37, 0xc4, 0xdf, 0x10, 0x80, 0x59, 0xcc,
-	0x39, 0xce, 0x2e, 0xe0, 0x68, 0x58, 0x50, 0x40, 0x51, 0xe4, 0x57, 0x69, 0x04, 0x70, 0x0e, 0x17,
-	0xe9, 0x85, 0x57, 0xe9, 0x0c, 0x84, 0xfc, 0x95, 0xdf, 0xd0, 0x03, 0xb1, 0x57, 0x91, 0x4b, 0x9a,
-	0xb8, 0xa1, 0x8f, 0x30, 0xc8, 0xde, 0x78, 0x00, 0x6d, 0xb5, 0x90, 0xcc, 0xe5, 0xf5, 0x58, 0xc5,
-	0xa1, 0x20, 0x65, 0x63, 0xe6, 0x0e, 0xf3, 0xfa, 0xd1, 0xe1, 0x1e, 0x0b, 0x8e, 0xb3, 0x52, 0x9e,
-	0x8f, 0x99, 0xaa, 0x12, 0xaa, 0x90, 0xc1, 0x5e, 0xbc, 0xea, 0xcc, 0xbc, 0x7c, 0xd5, 0x99, 0x79,
-	0xf3, 0xaa, 0xd3, 0xf8, 0xe6, 0xb4, 0xd3, 0xf8, 0xf1, 0xb4, 0xd3, 0x78, 0x7e, 0xda, 0x69, 0xbc,
-	0x38, 0xed, 0x34, 0x7e, 0x3b, 0xed, 0x34, 0x7e, 0x3f, 0xed, 0xcc, 0xbc, 0x39, 0xed, 0x34, 0xbe,
-	0x7d, 0xdd, 0x99, 0x79, 0xf1, 0xba, 0x33, 0xf3, 0xf2, 0x75, 0x67, 0xe6, 0x8b, 0x5e, 0xdf, 0x89,
-	0x0e, 0xe3, 0xfd, 0x0d, 0xcb, 0x1f, 0xf4, 0xfa, 0x81, 0x79, 0x60, 0x7a, 0x66, 0xcf, 0xf5, 0x9f,
-	0x38, 0xbd, 0xe3, 0xbb, 0xbd, 0xba, 0x5f, 0x5b, 0xf7, 0x5b, 0xf8, 0x5b, 0xea, 0xdd, 0xbf, 0x03,
-	0x00, 0x00, 0xff, 0xff, 0x8e, 0xd9, 0x59, 0xdf, 0x8c, 0x15, 0x00, 0x00,
+	// 1689 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x58, 0xcf, 0x6f, 0xdc, 0xc6,
+	0x15, 0xd6, 0x6a, 0xc5, 0xd5, 0x7a, 0xf4, 0xcb, 0x1e, 0xc9, 0x35, 0x5d, 0xbb, 0x4b, 0x79, 0x6b,
+	0xa3, 0x2e, 0x0a, 0x68, 0x61, 0xbb, 0x40, 0xd1, 0xa2, 0x06, 0xda, 0x95, 0x2c, 0xc0, 0x80, 0x8c,
+	0xaa, 0x4f, 0x75, 0x5b, 0x24, 0x27, 0x8a, 0x1c, 0xad, 0x68, 0x73, 0xc9, 0x15, 0x39, 0x94, 0x2d,
+	0x20, 0x40, 0xf2, 0x27, 0xe4, 0x1e, 0xe4, 0x1a, 0xe4, 0x92, 0x53, 0xfe, 0x84, 0x5c, 0x7c, 0xf4,
+	0xd1, 0x27, 0x22, 0x96, 0x2f, 0x01, 0x4f, 0x86, 0x8f, 0x41, 0x0e, 0xc1, 0xbc, 0x99, 0xe5, 0xef,
+	0x5d, 0xad, 0x2f, 0x22, 0xdf, 0xf7, 0x7d, 0x6f, 0x66, 0xf4, 0x38, 0xef, 0xbd, 0x99, 0x25, 0x9b,
+	0xa3, 0xe7, 0x83, 0x9e, 0xeb, 0x0f, 0x4e, 0xdc, 0xa1, 0x6f, 0x33, 0xb7, 0x17, 0x72, 0x93, 0x87,
+	0xf2, 0xef, 0xd6, 0x28, 0xf0, 0xb9, 0x4f, 0x35, 0x34, 0x7e, 0xbb, 0x31, 0xf0, 0x07, 0x3e, 0x22,
+	0x3d, 0xf1, 0x26, 0xc9, 0xee, 0x37, 0xf3, 0xa4, 0x05, 0x2c, 0x8c, 0x5c, 0x4e, 0xff, 0x4a, 0x16,
+	0xc3, 0x68, 0x38, 0x34, 0x83, 0x33, 0xbd, 0xb1, 0xd9, 0xb8, 0xbb, 0x74, 0x7f, 0x75, 0x4b, 0x0e,
+	0x73, 0x20, 0xd1, 0xfe, 0xda, 0xab, 0xd8, 0x98, 0x4b, 0x62, 0x63, 0x2c, 0x83, 0xf1, 0x8b, 0x70,
+	0x3d, 0x89, 0x58, 0xe0, 0xb0, 0x40, 0x9f, 0x2f, 0xb8, 0xfe, 0x5b, 0xa2, 0x99, 0xab, 0x92, 0xc1,
+	0xf8, 0x85, 0x3e, 0x24, 0x6d, 0xc7, 0x1b, 0xb0, 0x90, 0xb3, 0x40, 0x6f, 0xa2, 0xef, 0x9a, 0xf2,
+	0x7d, 0xac, 0xe0, 0xfe, 0x65, 0xe5, 0x9c, 0x0a, 0x21, 0x7d, 0xa3, 0x7f, 0x26, 0x2d, 0xcb, 0xb4,
+	0x8e, 0x59, 0xa8, 0x2f, 0xa0, 0xf3, 0x8a, 0x72, 0xde, 0x46, 0xb0, 0xbf, 0xa2, 0x5c, 0x35, 0x14,
+	0x81, 0xd2, 0xd2, 0x7b, 0x44, 0x73, 0x3c, 0x9b, 0xbd, 0xd4, 0x35, 0x74, 0x5a, 0x4e, 0x67, 0xb4,
+	0xd9, 0xcb, 0xcc, 0x07, 0x25, 0x20, 0x1f, 0xdd, 0xaf, 0x16, 0x48, 0x6b, 0x3b, 0xf5, 0xb6, 0x8e,
+	0x23, 0xef, 0xb9, 0xde, 0x28, 0x78, 0x23, 0x9b, 0x9b, 0x51, 0x48, 0x40, 0x3e, 0xb2, 0x09, 0xe7,
+	0xa7, 0xb9, 0xe4, 0x27, 0x14, 0xff, 0x59, 0x80, 0x1f, 0x46, 0x6f, 0xd6, 0xf8, 0xac, 0x2a, 0x1f,
+	0xa5, 0x01, 0xf5, 0xa4, 0xdb, 0x64, 0x09, 0x65, 0xf2, 0x9b, 0xea, 0x0b, 0x35, 0xae, 0xeb, 0xca,
+	0x35, 0x2f, 0x84, 0xbc, 0x41, 0x77, 0xc9, 0xf2, 0xa9, 0xef, 0x46, 0x43, 0xa6, 0x46, 0xd1, 0x6a,
+	0x46, 0xd9, 0x50, 0xa3, 0x14, 0x94, 0x50, 0xb0, 0xc4, 0x38, 0xa1, 0xf8, 0xca, 0xe3, 0xd5, 0xb4,
+	0xa6, 0x8d, 0x93, 0x57, 0x42, 0xc1, 0x12, 0xff, 0x94, 0x6b, 0x1e, 0x32, 0x57, 0x0d, 0xb3, 0x38,
+	0xed, 0x9f, 0xca, 0x09, 0x21, 0x6f, 0xd0, 0x4f, 0xc9, 0xba, 0xe3, 0x85, 0xdc, 0xf4, 0xf8, 0x13,
+	0xc6, 0x03, 0xc7, 0x52, 0x83, 0xb5, 0x6b, 0x06, 0xbb, 0xa1, 0x06, 0xab, 0x73, 0x80, 0x3a, 0xb0,
+	0xfb, 0xa1, 0x45, 0x16, 0x55, 0x9a, 0xd0, 0xa7, 0xe4, 0xda, 0xe1, 0x19, 0x67, 0xe1, 0x7e, 0xe0,
+	0x5b, 0x2c, 0x0c, 0x99, 0xbd, 0xcf, 0x82, 0x03, 0x66, 0xf9, 0x9e, 0x8d, 0x1b, 0xa6, 0xd9, 0xbf,
+	0x91, 0xc4, 0xc6, 0x24, 0x09, 0x4c, 0x22, 0xc4, 0xb0, 0xae, 0xe3, 0xd5, 0x0e, 0x3b, 0x9f, 0x0d,
+	0x3b, 0x41, 0x02, 0x93, 0x08, 0xfa, 0x98, 0xac, 0x73, 0x9f, 0x9b, 0x6e, 0xbf, 0x30, 0x2d, 0xee,
+	0xb9, 0x66, 0xff, 0x9a, 0x08, 0x42, 0x0d, 0x0d, 0x75, 0x60, 0x3a, 0xd4, 0x5e, 0x61, 0x2a, 0x7d,
+	0xa1, 0x34, 0x54, 0x91, 0x86, 0x3a, 0x90, 0xde, 0x25, 0x6d, 0xf6, 0x92, 0x59, 0xff, 0x71, 0x86,
+	0x0c, 0x77, 0x5f, 0xa3, 0xbf, 0x2c, 0x0a, 0xc0, 0x18, 0x83, 0xf4, 0x8d, 0xfe, 0x89, 0x5c, 0x3a,
+	0x89, 0x58, 0xc4, 0x50, 0xda, 0x42, 0xe9, 0x4a, 0x12, 0x1b, 0x19, 0x08, 0xd9, 0x2b, 0xdd, 0x22,
+	0x24, 0x8c, 0x0e, 0x65, 0xe9, 0x09, 0x71, 0x1f, 0x35, 0xfb, 0xab, 0x49, 0x6c, 0xe4, 0x50, 0xc8,
+	0xbd, 0xd3, 0x3d, 0xb2, 0x81, 0xab, 0x7b, 0xe4, 0x71, 0xe4, 0x18, 0x8f, 0x02, 0x8f, 0xd9, 0xb8,
+	0x69, 0x9a, 0x7d, 0x3d, 0x89, 0x8d, 0x5a, 0x1e, 0x6a, 0x51, 0xda, 0x25, 0xad, 0x70, 0xe4, 0x3a,
+	0x3c, 0xd4, 0x2f, 0xa1, 0x3f, 0x11, 0xf9, 0x2b, 0x11, 0x50, 0x4f, 0xd4, 0x1c, 0x9b, 0x81, 0x1d,
+	0xea, 0x24, 0xa7, 0x41, 0x04, 0xd4, 0x33, 0x5d, 0xd5, 0xbe, 0x1f, 0xf2, 0x5d, 0xc7, 0xe5, 0x2c,
+	0xc0, 0xe8, 0xe9, 0x4b, 0xa5, 0x55, 0x95, 0x78, 0xa8, 0x45, 0xe9, 0xe7, 0xe4, 0x0e, 0xe2, 0x07,
+	0x3c, 0x88, 0x2c, 0x1e, 0x05, 0xcc, 0x7e, 0xc2, 0xb8, 0x69, 0x9b, 0xdc, 0x2c, 0x6d, 0x89, 0x65,
+	0x1c, 0xfe, 0x8f, 0x49, 0x6c, 0xcc, 0xe6, 0x00, 0xb3, 0xc9, 0xe8, 0x5f, 0xc8, 0x0a, 0x0b, 0xb9,
+	0x33, 0x34, 0x39, 0xb3, 0xb7, 0xfd, 0x90, 0xeb, 0x2b, 0x38, 0xd1, 0x95, 0x24, 0x36, 0x8a, 0x04,
+	0x14, 0xcd, 0xee, 0xcf, 0x0d, 0xa2, 0x61, 0xc9, 0xa6, 0xf7, 0xc8, 0x12, 0xce, 0xb5, 0x2d, 0x8a,
+	0x6d, 0xa8, 0xd2, 0x6c, 0x4d, 0x94, 0x83, 0x1c, 0x0c, 0x79, 0x83, 0xfe, 0x83, 0x5c, 0x1e, 0xa5,
+	0x91, 0x50, 0x7e, 0x32, 0x8f, 0x36, 0x92, 0xd8, 0xa8, 0x70, 0x50, 0x41, 0xe8, 0xdf, 0xc8, 0xaa,
+	0xfc, 0x20, 0x3b, 0x51, 0x60, 0x72, 0xc7, 0xf7, 0x54, 0xd2, 0xd0, 0x24, 0x36, 0x4a, 0x0c, 0x94,
+	0x6c, 0x31, 0x7b, 0x14, 0x32, 0xbb, 0xef, 0xfa, 0xfe, 0x50, 0x0e, 0x2a, 0x1b, 0x58, 0x5b, 0xce,
+	0x5e, 0xe6, 0xa0, 0x82, 0x74, 0xff, 0x4e, 0x16, 0x55, 0x73, 0x15, 0xcd, 0x25, 0xe4, 0x7e, 0xc0,
+	0x4a, 0xfd, 0xe8, 0x40, 0x60, 0x59, 0x73, 0x41, 0x09, 0xc8, 0x47, 0xf7, 0xbb, 0x79, 0xd2, 0x7e,
+	0x9c, 0xf5, 0xd0, 0x65, 0x8c, 0x0c, 0x30, 0x51, 0xfd, 0x64, 0x95, 0xd2, 0xfa, 0x97, 0x45, 0x51,
+	0xce, 0xe3, 0x50, 0xb0, 0xe8, 0x2e, 0xa1, 0xb9, 0x78, 0x3e, 0x31, 0x39, 0xfa, 0xca, 0x10, 0xfe,
+	0x26, 0x89, 0x8d, 0x1a, 0x16, 0x6a, 0xb0, 0x74, 0xf6, 0x3e, 0xda, 0xa1, 0x0a, 0x62, 0x36, 0xbb,
+	0xc2, 0xa1, 0x60, 0x89, 0xe0, 0x67, 0x75, 0xe3, 0x80, 0x79, 0x5c, 0x5f, 0xc8, 0x82, 0x5f, 0x64,
+	0xa0, 0x64, 0x67, 0xf1, 0xd2, 0x66, 0x8e, 0xd7, 0x2f, 0x1a, 0xd1, 0x90, 0x4f, 0x27, 0x56, 0xdb,
+	0x82, 0x1d, 0xe9, 0x8d, 0xd2, 0xc4, 0x29, 0x03, 0x25, 0x9b, 0xfe, 0x8b, 0x5c, 0xcd, 0x21, 0x3b,
+	0xfe, 0x0b, 0xcf, 0xf5, 0x4d, 0x3b, 0x8d, 0xda, 0xf5, 0x24, 0x36, 0xea, 0x05, 0x50, 0x0f, 0x8b,
+	0x6f, 0x60, 0x15, 0x30, 0xac, 0x82, 0xcd, 0xec, 0x1b, 0x54, 0x59, 0xa8, 0xc1, 0xa8, 0x45, 0xae,
+	0x8b, 0x92, 0x77, 0x06, 0xec, 0x88, 0x05, 0xcc, 0xb3, 0x98, 0x9d, 0x65, 0x2d, 0xa6, 0x63, 0xbb,
+	0x7f, 0x27, 0x89, 0x8d, 0x5b, 0x13, 0x45, 0xe3, 0xd4, 0x86, 0xc9, 0xe3, 0xd0, 0x6d, 0x72, 0x05,
+	0xc9, 0xa7, 0x21, 0xb3, 0xff, 0x7b, 0xff, 0x91, 0x37, 0x70, 0x3c, 0xa6, 0xaf, 0xe2, 0xe0, 0x57,
+	0x93, 0xd8, 0xa8, 0x92, 0x50, 0x85, 0xb2, 0xb3, 0x57, 0xe9, 0x64, 0x23, 0xb0, 0x09, 0x67, 0xaf,
+	0x71, 0x90, 0x80, 0x1d, 0x85, 0xbb, 0x8c, 0x5b, 0xc7, 0x69, 0x57, 0xc9, 0x07, 0xa9, 0xc0, 0x42,
+	0x0d, 0x46, 0xff, 0x4f, 0x74, 0xcb, 0xc7, 0x9c, 0x71, 0x7c, 0x6f, 0xdb, 0xf7, 0x78, 0xe0, 0xbb,
+	0x7b, 0x26, 0x67, 0x9e, 0x75, 0x86, 0x8d, 0xa7, 0xd9, 0xbf, 0x99, 0xc4, 0xc6, 0x44, 0x0d, 0x4c,
+	0x64, 0xa8, 0x4d, 0x6e, 0x8e, 0x9c, 0x11, 0x13, 0x2d, 0xfa, 0x7f, 0x81, 0x39, 0x1a, 0xb1, 0x40,
+	0x66, 0x39, 0xb3, 0x65, 0x61, 0x97, 0x8d, 0x6a, 0x33, 0x89, 0x8d, 0xa9, 0x3a, 0x98, 0xca, 0x8a,
+	0x43, 0xba, 0xf8, 0x44, 0xfe, 0xe1, 0x33, 0xbd, 0x5d, 0x38, 0xa4, 0xef, 0x48, 0x34, 0x3b, 0xa4,
+	0x2b, 0x19, 0x8c, 0x5f, 0xba, 0x1f, 0xda, 0x64, 0x51, 0xa9, 0x70, 0xb1, 0x01, 0xdb, 0x0f, 0x98,
+	0xed, 0x58, 0x26, 0x67, 0x3b, 0xcc, 0xf2, 0x87, 0xa3, 0x40, 0x56, 0x7c, 0xff, 0xc5, 0xb8, 0xf8,
+	0xca, 0xc5, 0x4e, 0xd1, 0xc1, 0x54, 0x96, 0x0e, 0xc8, 0xef, 0x26, 0xf1, 0xd8, 0x3e, 0x54, 0xca,
+	0xdc, 0x4a, 0x62, 0x63, 0xba, 0x10, 0xa6, 0xd3, 0xf4, 0xeb, 0x06, 0xe9, 0x4d, 0x52, 0x4c, 0x68,
+	0x5d, 0x2a, 0xc1, 0x1e, 0x24, 0xb1, 0xf1, 0xb1, 0xae, 0xf0, 0xb1, 0x0e, 0x22, 0x6b, 0x44, 0xe7,
+	0x49, 0x7d, 0x30, 0xc6, 0xb2, 0xd6, 0x61, 0xd6, 0x54, 0x48, 0xa8, 0x42, 0xf4, 0x19, 0xe9, 0x14,
+	0xc0, 0x6a, 0x38, 0x65, 0x3a, 0x74, 0x93, 0xd8, 0xb8, 0x40, 0x09, 0x17, 0xf0, 0xf4, 0x33, 0x72,
+	0xbb, 0xa0, 0x98, 0x14, 0x44, 0x99, 0x32, 0x77, 0x93, 0xd8, 0x98, 0x49, 0x0f, 0x33, 0xa9, 0x44,
+	0x79, 0xce, 0x1a, 0x35, 0xc6, 0x6a, 0x31, 0x2b, 0xcf, 0x45, 0x06, 0x4a, 0xb6, 0xe8, 0x44, 0x23,
+	0x73, 0xc0, 0xc2, 0x03, 0xcb, 0xf4, 0xb2, 0x53, 0x1e, 0x76, 0xa2, 0x3c, 0x0e, 0x05, 0x8b, 0x3e,
+	0x24, 0x6b, 0x68, 0xe7, 0xca, 0xb9, 0x3c, 0xde, 0xad, 0x27, 0xb1, 0x51, 0xa6, 0xa0, 0x0c, 0x88,
+	0xc3, 0x5c, 0x09, 0x92, 0xe1, 0x21, 0xd9, 0x61, 0xae, 0x8e, 0x87, 0x5a, 0x54, 0x1c, 0x84, 0x04,
+	0x3e, 0xee, 0xa5, 0x4b, 0xd9, 0x41, 0x28, 0x07, 0x43, 0xde, 0x48, 0xfb, 0xb8, 0x08, 0xc1, 0x3f,
+	0x4f, 0x4d, 0xc7, 0x35, 0x0f, 0x5d, 0xa6, 0x2f, 0x67, 0xe5, 0xb1, 0xca, 0x42, 0x0d, 0x96, 0x36,
+	0xb7, 0x7d, 0x73, 0xc0, 0x0a, 0xed, 0x68, 0xa5, 0xd4, 0xdc, 0xca, 0x02, 0xa8, 0x87, 0xbb, 0xdf,
+	0x6b, 0x44, 0xc3, 0xba, 0x2e, 0x3e, 0xea, 0x31, 0x33, 0x6d, 0x34, 0x64, 0x74, 0x72, 0xcd, 0xbe,
+	0xc8, 0x40, 0xc9, 0x2e, 0xf8, 0xca, 0x6a, 0xaa, 0xd5, 0xf8, 0x22, 0x03, 0x25, 0x5b, 0xe4, 0x9e,
+	0x5d, 0xc9, 0x94, 0x56, 0x96, 0x7b, 0x15, 0x12, 0xaa, 0x50, 0x79, 0x90, 0x7c, 0x45, 0xaf, 0x0c,
+	0x22, 0x97, 0x51, 0x85, 0xc4, 0x26, 0x2b, 0xaf, 0xa3, 0x9d, 0x6d, 0xb2, 0xf2, 0x2a, 0xca, 0x80,
+	0x70, 0xc7, 0x18, 0xef, 0x44, 0x23, 0x17, 0xd3, 0x27, 0xcc, 0xef, 0xd1, 0x12, 0x05, 0x65, 0x00,
+	0xb7, 0x78, 0xe9, 0xae, 0x41, 0x72, 0x5b, 0xbc, 0x48, 0x41, 0x19, 0xa0, 0x23, 0xb2, 0x99, 0x06,
+	0x76, 0x52, 0x35, 0x90, 0x3b, 0xf5, 0x76, 0x12, 0x1b, 0x17, 0x6a, 0xe1, 0x42, 0x05, 0x3d, 0x23,
+	0xbf, 0xb7, 0x67, 0xa8, 0xe3, 0x72, 0x93, 0xff, 0x21, 0x89, 0x8d, 0x59, 0xe4, 0x30, 0x8b, 0xa8,
+	0xfb, 0x43, 0x93, 0x68, 0xf8, 0x2b, 0x82, 0x28, 0x27, 0x4c, 0xde, 0x00, 0x77, 0xfd, 0xc8, 0x2b,
+	0x1c, 0xab, 0xf3, 0x38, 0x14, 0x2c, 0x71, 0x33, 0x60, 0xe3, 0x7b, 0xe3, 0x49, 0xc4, 0x42, 0xae,
+	0x8e, 0x87, 0x9a, 0xbc, 0x19, 0x94, 0x39, 0xa8, 0x20, 0x78, 0x9f, 0x92, 0x18, 0x9e, 0x58, 0xe5,
+	0x5d, 0x5e, 0x53, 0xf7, 0xa9, 0x3c, 0x01, 0x45, 0x53, 0x38, 0xe2, 0x8f, 0x0f, 0xc0, 0x2c, 0xe6,
+	0x9c, 0xa6, 0x37, 0x77, 0x74, 0x2c, 0x10, 0x50, 0x34, 0xc5, 0x1d, 0x1c, 0x01, 0x3c, 0x87, 0xcb,
+	0xf4, 0xc2, 0x3b, 0x78, 0x0a, 0x42, 0xf6, 0x2a, 0xae, 0xf6, 0x81, 0x5c, 0xab, 0xcc, 0x25, 0x4d,
+	0x5e, 0xed, 0xc7, 0x18, 0xa4, 0x6f, 0x22, 0x80, 0x76, 0xbe, 0x90, 0x2c, 0x66, 0xf5, 0x38, 0x8f,
+	0x43, 0xc1, 0x4a, 0x8f, 0x99, 0x7b, 0xcc, 0x1b, 0xf0, 0xe3, 0x03, 0x16, 0x9c, 0xa6, 0xa5, 0x3c,
+	0x3b, 0x66, 0xe6, 0x49, 0xa8, 0x42, 0x7d, 0xf6, 0xfa, 0x6d, 0x67, 0xee, 0xcd, 0xdb, 0xce, 0xdc,
+	0xfb, 0xb7, 0x9d, 0xc6, 0x17, 0xe7, 0x9d, 0xc6, 0xb7, 0xe7, 0x9d, 0xc6, 0xab, 0xf3, 0x4e, 0xe3,
+	0xf5, 0x79, 0xa7, 0xf1, 0xe3, 0x79, 0xa7, 0xf1, 0xd3, 0x79, 0x67, 0xee, 0xfd, 0x79, 0xa7, 0xf1,
+	0xe5, 0xbb, 0xce, 0xdc, 0xeb, 0x77, 0x9d, 0xb9, 0x37, 0xef, 0x3a, 0x73, 0x9f, 0xf4, 0x06, 0x0e,
+	0x3f, 0x8e, 0x0e, 0xb7, 0x2c, 0x7f, 0xd8, 0x1b, 0x04, 0xe6, 0x91, 0xe9, 0x99, 0x3d, 0xd7, 0x7f,
+	0xee, 0xf4, 0x4e, 0x1f, 0xf4, 0xea, 0x7e, 0xa6, 0x3d, 0x6c, 0xe1, 0x8f, 0xb0, 0x0f, 0x7e, 0x1d,
+	0x00, 0x0f, 0xf8, 0x17, 0xea, 0xc5, 0x15, 0x00, 0x00,
 }
 
 func (this *Result) Equal(that interface{}) bool {
@@ -1296,6 +1306,9 @@ func (this *Summary) Equal(that interface{}) bool {
 	if this.TotalStructuredMetadataBytesProcessed != that1.TotalStructuredMetadataBytesProcessed {
 		return false
 	}
+	if this.EstimatedCost != that1.EstimatedCost {
+		return false
+	}
 	return true
 }
 func (this *Index) Equal(that interface{}) bool {
@@ -1644,6 +1657,7 @@ func (this *Summary) GoString() string {
 	s = append(s, "Shards: "+fmt.Sprintf("%#v", this.Shards)+",\n")
 	s = append(s, "TotalPostFilterLines: "+fmt.Sprintf("%#v", this.TotalPostFilterLines)+",\n")
 	s = append(s, "TotalStructuredMetadataBytesProcessed: "+fmt.Sprintf("%#v", this.TotalStructuredMetadataBytesProcessed)+",\n")
+	s = append(s, "EstimatedCost: "+fmt.Sprintf("%#v", this.EstimatedCost)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -1964,6 +1978,11 @@ func (m *Summary) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.EstimatedCost != 0 {
+		i = encodeVarintStats(dAtA, i, uint64(m.EstimatedCost))
+		i--
+		dAtA[i] = 0x68
+	}
 	if m.TotalStructuredMetadataBytesProcessed != 0 {
 		i = encodeVarintStats(dAtA, i, uint64(m.TotalStructuredMetadataBytesProcessed))
 		i--
@@ -2572,6 +2591,9 @@ func (m *Summary) Size() (n int) {
 	if m.TotalStructuredMetadataBytesProcessed != 0 {
 		n += 1 + sovStats(uint64(m.TotalStructuredMetadataBytesProcessed))
 	}
+	if m.EstimatedCost != 0 {
+		n += 1 + sovStats(uint64(m.EstimatedCost))
+	}
 	return n
 }
 
@@ -2838,6 +2860,7 @@ func (this *Summary) String() string {
 		`Shards:` + fmt.Sprintf("%v", this.Shards) + `,`,
 		`TotalPostFilterLines:` + fmt.Sprintf("%v", this.TotalPostFilterLines) + `,`,
 		`TotalStructuredMetadataBytesProcessed:` + fmt.Sprintf("%v", this.TotalStructuredMetadataBytesProcessed) + `,`,
+		`EstimatedCost:` + fmt.Sprintf("%v", this.EstimatedCost) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -3739,6 +3762,25 @@ func (m *Summary) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 13:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EstimatedCost", wireType)
+			}
+			m.EstimatedCost = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.EstimatedCost |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStats(dAtA[iNdEx:])
@@ -39,6 +39,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/dataobj/consumer"
 	dataobjindex "github.com/grafana/loki/v3/pkg/dataobj/index"
 	"github.com/grafana/loki/v3/pkg/distributor"
+	"github.com/grafana/loki/v3/pkg/engine"
 	"github.com/grafana/loki/v3/pkg/indexgateway"
 	"github.com/grafana/loki/v3/pkg/ingester"
 	ingester_client "github.com/grafana/loki/v3/pkg/ingester/client"
@@ -49,6 +50,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/loghttp/push"
 	"github.com/grafana/loki/v3/pkg/loki/common"
 	"github.com/grafana/loki/v3/pkg/lokifrontend"
+	"github.com/grafana/loki/v3/pkg/lokifrontend/export"
 	"github.com/grafana/loki/v3/pkg/lokifrontend/frontend/transport"
 	"github.com/grafana/loki/v3/pkg/pattern"
 	"github.com/grafana/loki/v3/pkg/querier"
@@ -64,8 +66,10 @@ import (
 	internalserver "github.com/grafana/loki/v3/pkg/server"
 	"github.com/grafana/loki/v3/pkg/storage"
 	"github.com/grafana/loki/v3/pkg/storage/config"
+	"github.com/grafana/loki/v3/pkg/storage/lookup"
 	"github.com/grafana/loki/v3/pkg/storage/stores/series/index"
 	"github.com/grafana/loki/v3/pkg/storage/stores/shipper/bloomshipper"
+	"github.com/grafana/loki/v3/pkg/storage/zstddict"
 	"github.com/grafana/loki/v3/pkg/tracing"
 	"github.com/grafana/loki/v3/pkg/ui"
 	"github.com/grafana/loki/v3/pkg/util"
@@ -320,6 +324,9 @@ func (c *Config) Validate() error {
 	if err := c.IngestLimitsFrontendClient.Validate(); err != nil {
 		errs = append(errs, errors.Wrap(err, "CONFIG ERROR: invalid ingest_limits_frontend_client config"))
 	}
+	if err := c.Frontend.Export.Validate(); err != nil {
+		errs = append(errs, errors.Wrap(err, "CONFIG ERROR: invalid frontend export config"))
+	}
 	if err := c.Worker.Validate(); err != nil {
 		errs = append(errs, errors.Wrap(err, "CONFIG ERROR: invalid frontend_worker config"))
 	}
@@ -444,6 +451,10 @@ type Loki struct {
 	dataObjConsumer           *consumer.Service
 	dataObjIndexBuilder       *dataobjindex.Builder
 	scratchStore              scratch.Store
+	zstdDictionaries          *zstddict.Manager
+	lookupTables              *lookup.Tables
+	engineFragmentRunner      *engine.FragmentRunner
+	exports                   *export.Manager
 
 	ClientMetrics       storage.ClientMetrics
 	deleteClientMetrics *deletion.DeleteRequestClientMetrics
@@ -785,6 +796,9 @@ func (t *Loki) setupModuleManager() error {
 	mm.RegisterModule(DataObjConsumer, t.initDataObjConsumer)
 	mm.RegisterModule(DataObjIndexBuilder, t.initDataObjIndexBuilder)
 	mm.RegisterModule(ScratchStore, t.initScratchStore)
+	mm.RegisterModule(ZstdDictionaries, t.initZstdDictionaries, modules.UserInvisibleModule)
+	mm.RegisterModule(LookupTables, t.initLookupTables, modules.UserInvisibleModule)
+	mm.RegisterModule(EngineFragmentRunner, t.initEngineFragmentRunner, modules.UserInvisibleModule)
 
 	mm.RegisterModule(All, nil)
 	mm.RegisterModule(Read, nil)
@@ -805,16 +819,16 @@ func (t *Loki) setupModuleManager() error {
 		IngestLimits:             {MemberlistKV, Overrides, Server},
 		IngestLimitsFrontend:     {IngestLimitsRing, Overrides, Server, MemberlistKV},
 		IngestLimitsFrontendRing: {RuntimeConfig, Server, MemberlistKV},
-		Store:                    {Overrides, IndexGatewayRing},
+		Store:                    {Overrides, IndexGatewayRing, ZstdDictionaries, LookupTables},
 		Ingester:                 {Store, Server, MemberlistKV, TenantConfigs, Analytics, PartitionRing, UIRing},
-		Querier:                  {Store, Ring, Server, IngesterQuerier, PatternRingClient, Overrides, Analytics, CacheGenerationLoader, QuerySchedulerRing, UIRing},
-		QueryFrontendTripperware: {Server, Overrides, TenantConfigs},
+		Querier:                  {Store, Ring, Server, IngesterQuerier, PatternRingClient, Overrides, Analytics, CacheGenerationLoader, QuerySchedulerRing, UIRing, EngineFragmentRunner},
+		QueryFrontendTripperware: {Server, Overrides, TenantConfigs, LookupTables},
 		QueryFrontend:            {QueryFrontendTripperware, Analytics, CacheGenerationLoader, QuerySchedulerRing, UIRing},
 		QueryScheduler:           {Server, Overrides, MemberlistKV, Analytics, QuerySchedulerRing, UIRing},
 		Ruler:                    {Ring, Server, RulerStorage, RuleEvaluator, Overrides, TenantConfigs, Analytics, UIRing},
 		RuleEvaluator:            {Ring, Server, Store, IngesterQuerier, Overrides, TenantConfigs, Analytics},
 		TableManager:             {Server, Analytics, UIRing},
-		Compactor:                {Server, Overrides, MemberlistKV, Analytics, UIRing},
+		Compactor:                {Server, Overrides, MemberlistKV, Analytics, UIRing, ZstdDictionaries},
 		IndexGateway:             {Server, Store, BloomStore, IndexGatewayRing, IndexGatewayInterceptors, Analytics, UIRing},
 		BloomGateway:             {Server, BloomStore, Analytics, UIRing},
 		BloomPlanner:             {Server, BloomStore, Analytics, Store, UIRing},
@@ -828,10 +842,13 @@ func (t *Loki) setupModuleManager() error {
 		IndexGatewayRing:         {Overrides, MemberlistKV},
 		PartitionRing:            {MemberlistKV, Server, Ring},
 		MemberlistKV:             {Server},
-		DataObjExplorer:          {Server, UIRing},
-		DataObjConsumer:          {ScratchStore, PartitionRing, Server, UIRing},
-		DataObjIndexBuilder:      {ScratchStore, Server, UIRing},
+		DataObjExplorer:          {Server, UIRing, ZstdDictionaries},
+		DataObjConsumer:          {ScratchStore, PartitionRing, Server, UIRing, ZstdDictionaries},
+		DataObjIndexBuilder:      {ScratchStore, Server, UIRing, ZstdDictionaries},
 		ScratchStore:             {},
+		ZstdDictionaries:         {},
+		LookupTables:             {},
+		EngineFragmentRunner:     {},
 
 		Read:    {QueryFrontend, Querier},
 		Write:   {Ingester, Distributor, PatternIngester},
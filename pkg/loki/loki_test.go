@@ -88,6 +88,18 @@ func TestLoki_isModuleEnabled(t1 *testing.T) {
 		{name: "Multi target includes querier", target: flagext.StringSliceCSV{"query-frontend", "query-scheduler", "querier"}, module: Querier, want: true},
 		{name: "Multi target does not include distributor", target: flagext.StringSliceCSV{"query-frontend", "query-scheduler", "querier"}, module: Distributor, want: false},
 		{name: "Test recursive dep, Ingester -> TenantConfigs -> RuntimeConfig", target: flagext.StringSliceCSV{"ingester"}, module: RuntimeConfig, want: true},
+		{name: "Target Query Frontend does not include zstd dictionaries", target: flagext.StringSliceCSV{"query-frontend"}, module: ZstdDictionaries, want: false},
+		// Every target reading chunks or data object sections loads the zstd
+		// dictionaries they may be compressed with.
+		{name: "Target Querier includes zstd dictionaries", target: flagext.StringSliceCSV{"querier"}, module: ZstdDictionaries, want: true},
+		{name: "Target Ingester includes zstd dictionaries", target: flagext.StringSliceCSV{"ingester"}, module: ZstdDictionaries, want: true},
+		{name: "Target Ruler includes zstd dictionaries", target: flagext.StringSliceCSV{"ruler"}, module: ZstdDictionaries, want: true},
+		{name: "Target Compactor includes zstd dictionaries", target: flagext.StringSliceCSV{"compactor"}, module: ZstdDictionaries, want: true},
+		{name: "Target Index Gateway includes zstd dictionaries", target: flagext.StringSliceCSV{"index-gateway"}, module: ZstdDictionaries, want: true},
+		{name: "Target Bloom Builder includes zstd dictionaries", target: flagext.StringSliceCSV{"bloom-builder"}, module: ZstdDictionaries, want: true},
+		{name: "Target DataObj Consumer includes zstd dictionaries", target: flagext.StringSliceCSV{"dataobj-consumer"}, module: ZstdDictionaries, want: true},
+		{name: "Target DataObj Index Builder includes zstd dictionaries", target: flagext.StringSliceCSV{"dataobj-index-builder"}, module: ZstdDictionaries, want: true},
+		{name: "Target DataObj Explorer includes zstd dictionaries", target: flagext.StringSliceCSV{"dataobj-explorer"}, module: ZstdDictionaries, want: true},
 	}
 	for _, tt := range tests {
 		t1.Run(tt.name, func(t1 *testing.T) {
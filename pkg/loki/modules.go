@@ -34,6 +34,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/collectors/version"
 	"github.com/prometheus/common/model"
 	"github.com/thanos-io/objstore"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 
@@ -50,7 +51,9 @@ import (
 	"github.com/grafana/loki/v3/pkg/dataobj/consumer"
 	"github.com/grafana/loki/v3/pkg/dataobj/explorer"
 	dataobjindex "github.com/grafana/loki/v3/pkg/dataobj/index"
+	"github.com/grafana/loki/v3/pkg/dataobj/rangecache"
 	"github.com/grafana/loki/v3/pkg/distributor"
+	"github.com/grafana/loki/v3/pkg/engine"
 	"github.com/grafana/loki/v3/pkg/indexgateway"
 	"github.com/grafana/loki/v3/pkg/ingester"
 	"github.com/grafana/loki/v3/pkg/limits"
@@ -58,7 +61,9 @@ import (
 	limitsproto "github.com/grafana/loki/v3/pkg/limits/proto"
 	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/grafana/loki/v3/pkg/logql"
+	lokilog "github.com/grafana/loki/v3/pkg/logql/log"
 	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
+	"github.com/grafana/loki/v3/pkg/lokifrontend/export"
 	"github.com/grafana/loki/v3/pkg/lokifrontend/frontend"
 	"github.com/grafana/loki/v3/pkg/lokifrontend/frontend/transport"
 	"github.com/grafana/loki/v3/pkg/lokifrontend/frontend/v1/frontendv1pb"
@@ -81,6 +86,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/storage/chunk/client"
 	chunk_util "github.com/grafana/loki/v3/pkg/storage/chunk/client/util"
 	"github.com/grafana/loki/v3/pkg/storage/config"
+	"github.com/grafana/loki/v3/pkg/storage/lookup"
 	"github.com/grafana/loki/v3/pkg/storage/stores/series/index"
 	"github.com/grafana/loki/v3/pkg/storage/stores/shipper/bloomshipper"
 	"github.com/grafana/loki/v3/pkg/storage/stores/shipper/indexshipper"
@@ -88,6 +94,7 @@ import (
 	boltdbcompactor "github.com/grafana/loki/v3/pkg/storage/stores/shipper/indexshipper/boltdb/compactor"
 	"github.com/grafana/loki/v3/pkg/storage/stores/shipper/indexshipper/tsdb"
 	"github.com/grafana/loki/v3/pkg/storage/types"
+	"github.com/grafana/loki/v3/pkg/storage/zstddict"
 	"github.com/grafana/loki/v3/pkg/ui"
 	"github.com/grafana/loki/v3/pkg/util/constants"
 	"github.com/grafana/loki/v3/pkg/util/httpreq"
@@ -152,6 +159,9 @@ const (
 	DataObjConsumer          = "dataobj-consumer"
 	DataObjIndexBuilder      = "dataobj-index-builder"
 	ScratchStore             = "scratch-store"
+	ZstdDictionaries         = "zstd-dictionaries"
+	LookupTables             = "lookup-tables"
+	EngineFragmentRunner     = "engine-fragment-runner"
 	UIRing                   = "ui-ring"
 	UI                       = "ui"
 	All                      = "all"
@@ -384,6 +394,9 @@ func (t *Loki) initDistributor() (services.Service, error) {
 		logproto.RegisterPusherServer(t.Server.GRPC, t.distributor)
 	}
 
+	// Register the OTLP LogsService so collectors can push over OTLP/gRPC.
+	plogotlp.RegisterGRPCServer(t.Server.GRPC, distributor.NewOTLPGRPCServer(t.distributor))
+
 	httpPushHandlerMiddleware := middleware.Merge(
 		serverutil.RecoveryHTTPMiddleware,
 		t.HTTPAuthMiddleware,
@@ -582,9 +595,20 @@ func (t *Loki) initQuerier() (services.Service, error) {
 		if err != nil {
 			return nil, err
 		}
+		// The cached bucket is shared by the metastore and the scans of
+		// queries, including plan fragments executed for other queriers.
+		store, err = rangecache.NewBucket(t.Cfg.Querier.EngineV2.DataObjCache, store, prometheus.DefaultRegisterer, logger)
+		if err != nil {
+			return nil, fmt.Errorf("creating data object cache: %w", err)
+		}
 	}
 
-	t.querierAPI = querier.NewQuerierAPI(t.Cfg.Querier, t.Cfg.DataObj.Metastore, t.Querier, t.Overrides, store, prometheus.DefaultRegisterer, logger)
+	t.querierAPI = querier.NewQuerierAPI(t.Cfg.Querier, t.Cfg.DataObj.Metastore, t.Querier, t.ingesterQuerier, t.engineFragmentRunner, t.Overrides, store, prometheus.DefaultRegisterer, logger)
+	if t.Cfg.Querier.EngineV2.Enable {
+		// Every querier executes plan fragments sent by the queriers
+		// coordinating queries with distributed execution enabled.
+		engine.RegisterFragmentExecutor(t.Server.GRPC, t.Cfg.Querier.EngineV2, store, querier.NewEngineIngesterQuerier(t.ingesterQuerier), logger)
+	}
 
 	indexStatsHTTPMiddleware := querier.WrapQuerySpanAndTimeout("query.IndexStats", t.Overrides)
 	indexShardsHTTPMiddleware := querier.WrapQuerySpanAndTimeout("query.IndexShards", t.Overrides)
@@ -699,6 +723,12 @@ func (t *Loki) initQuerier() (services.Service, error) {
 	tailQuerier := tail.NewQuerier(t.ingesterQuerier, t.Querier, deleteStore, t.Overrides, t.Cfg.Querier.TailMaxDuration, tail.NewMetrics(prometheus.DefaultRegisterer), log.With(util_log.Logger, "component", "tail-querier"))
 	t.Server.HTTP.Path("/loki/api/v1/tail").Methods("GET", "POST").Handler(httpMiddleware.Wrap(http.HandlerFunc(tailQuerier.TailHandler)))
 	t.Server.HTTP.Path("/api/prom/tail").Methods("GET", "POST").Handler(httpMiddleware.Wrap(http.HandlerFunc(tailQuerier.TailHandler)))
+	// The LogTail gRPC service serves the same tail queries for clients that cannot use websockets.
+	logproto.RegisterLogTailServer(t.Server.GRPC, tail.NewGRPCServer(tailQuerier))
+
+	if t.lookupTables != nil {
+		t.registerLookupTablesRoutes(httpMiddleware)
+	}
 
 	internalMiddlewares := []queryrangebase.Middleware{
 		serverutil.RecoveryMiddleware,
@@ -741,6 +771,17 @@ func (t *Loki) initIngester() (_ services.Service, err error) {
 	if t.Cfg.Ingester.ShutdownMarkerPath == "" {
 		level.Warn(util_log.Logger).Log("msg", "The config setting shutdown marker path is not set. The /ingester/prepare_shutdown endpoint won't work")
 	}
+	if t.zstdDictionaries != nil {
+		// Only the first replica of a tenant in the ring trains its dictionaries.
+		t.zstdDictionaries.TrainOwnedTenants(zstddict.RingOwner(t.ring, t.Cfg.Ingester.LifecyclerConfig.ID))
+		t.Cfg.Ingester.Dictionaries = t.zstdDictionaries
+	}
+	if t.lookupTables != nil {
+		t.Cfg.Ingester.LookupTables = t.lookupTables
+	}
+	if redactionLimits, ok := t.Overrides.(lokilog.RedactionLimits); ok {
+		t.Cfg.Ingester.RedactionLimits = redactionLimits
+	}
 
 	t.Ingester, err = ingester.New(t.Cfg.Ingester, t.Cfg.IngesterClient, t.Store, t.Overrides, t.tenantConfigs, prometheus.DefaultRegisterer, t.Cfg.Distributor.WriteFailuresLogging, t.Cfg.MetricsNamespace, logger, t.UsageTracker, t.ring, t.PartitionRingWatcher)
 	if err != nil {
@@ -899,6 +940,13 @@ func (t *Loki) initStore() (services.Service, error) {
 		}
 	}
 
+	if t.lookupTables != nil {
+		t.Cfg.StorageConfig.LookupTableProvider = t.lookupTables
+	}
+	if redactionLimits, ok := t.Overrides.(lokilog.RedactionLimits); ok {
+		t.Cfg.StorageConfig.RedactionLimits = redactionLimits
+	}
+
 	store, err := storage.NewStore(t.Cfg.StorageConfig, t.Cfg.ChunkStoreConfig, t.Cfg.SchemaConfig, t.Overrides, t.ClientMetrics, prometheus.DefaultRegisterer, util_log.Logger, t.Cfg.MetricsNamespace)
 	if err != nil {
 		return nil, err
@@ -1143,6 +1191,10 @@ func (i ingesterQueryOptions) QueryIngestersWithin() time.Duration {
 func (t *Loki) initQueryFrontendMiddleware() (_ services.Service, err error) {
 	level.Debug(util_log.Logger).Log("msg", "initializing query frontend tripperware")
 
+	if t.lookupTables != nil {
+		t.Cfg.QueryRange.LookupTables = t.lookupTables
+	}
+
 	middleware, stopper, err := queryrange.NewMiddleware(
 		t.Cfg.QueryRange,
 		t.Cfg.Querier.Engine,
@@ -1252,7 +1304,8 @@ func (t *Loki) initQueryFrontend() (_ services.Service, err error) {
 		level.Debug(util_log.Logger).Log("msg", "no query frontend configured")
 	}
 
-	roundTripper := queryrange.NewSerializeRoundTripper(t.QueryFrontEndMiddleware.Wrap(frontendTripper), queryrange.DefaultCodec, t.Cfg.Frontend.SupportParquetEncoding)
+	queryHandler := t.QueryFrontEndMiddleware.Wrap(frontendTripper)
+	roundTripper := queryrange.NewSerializeRoundTripper(queryHandler, queryrange.DefaultCodec, t.Cfg.Frontend.SupportParquetEncoding)
 
 	frontendHandler := transport.NewHandler(t.Cfg.Frontend.Handler, roundTripper, util_log.Logger, prometheus.DefaultRegisterer, t.Cfg.MetricsNamespace)
 	if t.Cfg.Frontend.CompressResponses {
@@ -1312,6 +1365,7 @@ func (t *Loki) initQueryFrontend() (_ services.Service, err error) {
 	}
 	t.Server.HTTP.Path("/loki/api/v1/query_range").Methods("GET", "POST").Handler(frontendHandler)
 	t.Server.HTTP.Path("/loki/api/v1/query").Methods("GET", "POST").Handler(frontendHandler)
+	t.Server.HTTP.Path("/loki/api/v1/query_estimate").Methods("GET", "POST").Handler(frontendHandler)
 	t.Server.HTTP.Path("/loki/api/v1/label").Methods("GET", "POST").Handler(frontendHandler)
 	t.Server.HTTP.Path("/loki/api/v1/labels").Methods("GET", "POST").Handler(frontendHandler)
 	t.Server.HTTP.Path("/loki/api/v1/label/{name}/values").Methods("GET", "POST").Handler(frontendHandler)
@@ -1337,8 +1391,38 @@ func (t *Loki) initQueryFrontend() (_ services.Service, err error) {
 		t.Server.HTTP.Path("/api/prom/tail").Methods("GET", "POST").Handler(defaultHandler)
 	}
 
+	// Lookup tables are read from and written to object storage directly, so
+	// the frontend serves their API itself. If this process is also a Querier
+	// the Querier registers the lookup table endpoints.
+	if t.lookupTables != nil && !t.isModuleActive(Querier) {
+		t.registerLookupTablesRoutes(middleware.Merge(serverutil.RecoveryHTTPMiddleware, t.HTTPAuthMiddleware))
+	}
+
+	if t.Cfg.Frontend.Export.Enabled {
+		objstoreBucket, err := t.createObjectStoreBucket("exports")
+		if err != nil {
+			return nil, err
+		}
+		if t.Cfg.Frontend.Export.StoragePrefix != "" {
+			objstoreBucket = objstore.NewPrefixedBucket(objstoreBucket, t.Cfg.Frontend.Export.StoragePrefix)
+		}
+
+		logger := log.With(util_log.Logger, "component", "exports")
+		t.exports = export.NewManager(t.Cfg.Frontend.Export, objstoreBucket, queryHandler, t.Overrides, logger, prometheus.DefaultRegisterer)
+		t.exports.Start()
+
+		httpMiddleware := middleware.Merge(serverutil.RecoveryHTTPMiddleware, t.HTTPAuthMiddleware)
+		t.Server.HTTP.Path("/loki/api/v1/export").Methods("POST").Handler(httpMiddleware.Wrap(http.HandlerFunc(t.exports.SubmitHandler)))
+		t.Server.HTTP.Path("/loki/api/v1/export").Methods("GET").Handler(httpMiddleware.Wrap(http.HandlerFunc(t.exports.ListHandler)))
+		t.Server.HTTP.Path("/loki/api/v1/export/{id}").Methods("GET").Handler(httpMiddleware.Wrap(http.HandlerFunc(t.exports.GetHandler)))
+		t.Server.HTTP.Path("/loki/api/v1/export/{id}").Methods("DELETE").Handler(httpMiddleware.Wrap(http.HandlerFunc(t.exports.CancelHandler)))
+	}
+
 	if t.frontend == nil {
 		return services.NewIdleService(nil, func(_ error) error {
+			if t.exports != nil {
+				t.exports.Stop()
+			}
 			if t.stopper != nil {
 				t.stopper.Stop()
 				t.stopper = nil
@@ -1356,6 +1440,9 @@ func (t *Loki) initQueryFrontend() (_ services.Service, err error) {
 			level.Warn(util_log.Logger).Log("msg", "failed to stop frontend service", "err", err)
 		}
 
+		if t.exports != nil {
+			t.exports.Stop()
+		}
 		if t.stopper != nil {
 			t.stopper.Stop()
 		}
@@ -1679,6 +1766,10 @@ func (t *Loki) initCompactor() (services.Service, error) {
 		objectClients[periodConfig.From] = objectClient
 	}
 
+	if t.zstdDictionaries != nil && t.Cfg.CompactorConfig.RetentionEnabled {
+		t.zstdDictionaries.RetireDictionaries(t.Overrides)
+	}
+
 	var deleteRequestStoreClient client.ObjectClient
 	if t.Cfg.CompactorConfig.RetentionEnabled {
 		if deleteStore := t.Cfg.CompactorConfig.DeleteRequestStore; deleteStore != "" {
@@ -2135,6 +2226,10 @@ func (t *Loki) initDataObjConsumer() (services.Service, error) {
 		return nil, err
 	}
 
+	if t.zstdDictionaries != nil {
+		t.Cfg.DataObj.Consumer.BuilderConfig.ZstdDictionary = t.zstdDictionaries.Dictionary
+	}
+
 	level.Info(util_log.Logger).Log("msg", "initializing dataobj consumer", "instance", t.Cfg.Ingester.LifecyclerConfig.ID)
 	t.dataObjConsumer = consumer.New(
 		t.Cfg.KafkaConfig,
@@ -2192,6 +2287,21 @@ func (t *Loki) initScratchStore() (services.Service, error) {
 }
 
 func (t *Loki) createDataObjBucket(clientName string) (objstore.Bucket, error) {
+	objstoreBucket, err := t.createObjectStoreBucket(clientName)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Cfg.DataObj.StorageBucketPrefix != "" {
+		objstoreBucket = objstore.NewPrefixedBucket(objstoreBucket, t.Cfg.DataObj.StorageBucketPrefix)
+	}
+
+	return objstoreBucket, nil
+}
+
+// createObjectStoreBucket creates a bucket client for the object store of the
+// current schema period.
+func (t *Loki) createObjectStoreBucket(clientName string) (objstore.Bucket, error) {
 	schema, err := t.Cfg.SchemaConfig.SchemaForTime(model.Now())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get schema for now: %w", err)
@@ -2208,17 +2318,66 @@ func (t *Loki) createDataObjBucket(clientName string) (objstore.Bucket, error) {
 		}
 	}
 
-	var objstoreBucket objstore.Bucket
-	objstoreBucket, err = bucket.NewClient(context.Background(), backend, cfg.Config, clientName, util_log.Logger)
+	return bucket.NewClient(context.Background(), backend, cfg.Config, clientName, util_log.Logger)
+}
+
+func (t *Loki) initZstdDictionaries() (services.Service, error) {
+	cfg := t.Cfg.StorageConfig.ZstdDictionaries
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	objstoreBucket, err := t.createObjectStoreBucket("zstd-dictionaries")
 	if err != nil {
 		return nil, err
 	}
+	if cfg.StoragePrefix != "" {
+		objstoreBucket = objstore.NewPrefixedBucket(objstoreBucket, cfg.StoragePrefix)
+	}
 
-	if t.Cfg.DataObj.StorageBucketPrefix != "" {
-		objstoreBucket = objstore.NewPrefixedBucket(objstoreBucket, t.Cfg.DataObj.StorageBucketPrefix)
+	logger := log.With(util_log.Logger, "component", "zstd-dictionaries")
+	t.zstdDictionaries = zstddict.NewManager(cfg, objstoreBucket, logger, prometheus.DefaultRegisterer)
+	return t.zstdDictionaries, nil
+}
+
+func (t *Loki) registerLookupTablesRoutes(httpMiddleware middleware.Interface) {
+	t.Server.HTTP.Path("/loki/api/v1/lookup_tables").Methods("GET").Handler(httpMiddleware.Wrap(http.HandlerFunc(t.lookupTables.ListHandler)))
+	t.Server.HTTP.Path("/loki/api/v1/lookup_tables/{name}").Methods("GET").Handler(httpMiddleware.Wrap(http.HandlerFunc(t.lookupTables.GetHandler)))
+	t.Server.HTTP.Path("/loki/api/v1/lookup_tables/{name}").Methods("PUT", "POST").Handler(httpMiddleware.Wrap(http.HandlerFunc(t.lookupTables.PutHandler)))
+	t.Server.HTTP.Path("/loki/api/v1/lookup_tables/{name}").Methods("DELETE").Handler(httpMiddleware.Wrap(http.HandlerFunc(t.lookupTables.DeleteHandler)))
+}
+
+func (t *Loki) initLookupTables() (services.Service, error) {
+	cfg := t.Cfg.StorageConfig.LookupTables
+	if !cfg.Enabled {
+		return nil, nil
 	}
 
-	return objstoreBucket, nil
+	objstoreBucket, err := t.createObjectStoreBucket("lookup-tables")
+	if err != nil {
+		return nil, err
+	}
+	if cfg.StoragePrefix != "" {
+		objstoreBucket = objstore.NewPrefixedBucket(objstoreBucket, cfg.StoragePrefix)
+	}
+
+	logger := log.With(util_log.Logger, "component", "lookup-tables")
+	t.lookupTables, err = lookup.NewTables(cfg, objstoreBucket, logger, prometheus.DefaultRegisterer)
+	return nil, err
+}
+
+func (t *Loki) initEngineFragmentRunner() (services.Service, error) {
+	if !t.Cfg.Querier.EngineV2.Enable || !t.Cfg.Querier.EngineV2.Distributed.Enabled {
+		return nil, nil
+	}
+
+	logger := log.With(util_log.Logger, "component", "querier")
+	runner, err := engine.NewFragmentRunner(t.Cfg.Querier.EngineV2, prometheus.DefaultRegisterer, logger)
+	if err != nil {
+		return nil, fmt.Errorf("creating engine fragment runner: %w", err)
+	}
+	t.engineFragmentRunner = runner
+	return runner, nil
 }
 
 func (t *Loki) deleteRequestsClient(clientType string, limits limiter.CombinedLimits) (deletion.DeleteRequestsClient, error) {
@@ -453,6 +453,7 @@ func minimalWorkingConfig(t *testing.T, dir, target string, cfgTransformers ...f
 	cfg.IndexGateway.Ring.InstanceAddr = localhost
 	cfg.CompactorConfig.CompactorRing.InstanceAddr = localhost
 	cfg.CompactorConfig.WorkingDirectory = filepath.Join(dir, "compactor")
+	cfg.Ingester.WAL.Dir = filepath.Join(dir, "wal")
 
 	cfg.Ruler.Ring.InstanceAddr = localhost
 	cfg.Ruler.StoreConfig.Type = types.StorageTypeLocal
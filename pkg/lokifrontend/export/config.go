@@ -0,0 +1,49 @@
+package export
+
+import (
+	"errors"
+	"flag"
+	"time"
+)
+
+// Config configures the export of log query results to object storage.
+type Config struct {
+	Enabled           bool          `yaml:"enabled"`
+	StoragePrefix     string        `yaml:"storage_prefix"`
+	PartitionInterval time.Duration `yaml:"partition_interval"`
+	MaxConcurrency    int           `yaml:"max_concurrency"`
+	MaxJobsPerTenant  int           `yaml:"max_jobs_per_tenant"`
+	PageSize          int           `yaml:"page_size"`
+	JobRetention      time.Duration `yaml:"job_retention"`
+}
+
+// RegisterFlagsWithPrefix registers flags with the given prefix.
+func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, prefix+"enabled", false, "Experimental: Enable the export API, which runs log queries asynchronously and writes their results as Parquet files to object storage.")
+	f.StringVar(&cfg.StoragePrefix, prefix+"storage-prefix", "exports/", "Prefix of the object storage path under which exports are written.")
+	f.DurationVar(&cfg.PartitionInterval, prefix+"partition-interval", time.Hour, "Time range of the logs written to each Parquet file of an export.")
+	f.IntVar(&cfg.MaxConcurrency, prefix+"max-concurrency", 4, "Maximum number of partitions exported at the same time across all export jobs.")
+	f.IntVar(&cfg.MaxJobsPerTenant, prefix+"max-jobs-per-tenant", 2, "Maximum number of queued or running export jobs of a tenant.")
+	f.IntVar(&cfg.PageSize, prefix+"page-size", 5000, "Maximum number of log entries fetched by each query of an export.")
+	f.DurationVar(&cfg.JobRetention, prefix+"job-retention", 24*time.Hour, "How long the status of finished export jobs is kept. The exported files are not deleted.")
+}
+
+// Validate validates the config.
+func (cfg *Config) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.PartitionInterval < time.Minute {
+		return errors.New("partition interval must be at least 1m")
+	}
+	if cfg.MaxConcurrency <= 0 {
+		return errors.New("max concurrency must be greater than 0")
+	}
+	if cfg.MaxJobsPerTenant <= 0 {
+		return errors.New("max jobs per tenant must be greater than 0")
+	}
+	if cfg.PageSize <= 0 {
+		return errors.New("page size must be greater than 0")
+	}
+	return nil
+}
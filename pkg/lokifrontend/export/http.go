@@ -0,0 +1,111 @@
+package export
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-kit/log/level"
+	"github.com/gorilla/mux"
+	"github.com/grafana/dskit/tenant"
+
+	"github.com/grafana/loki/v3/pkg/loghttp"
+	"github.com/grafana/loki/v3/pkg/util"
+	serverutil "github.com/grafana/loki/v3/pkg/util/server"
+)
+
+type listResponse struct {
+	Jobs []Job `json:"jobs"`
+}
+
+// SubmitHandler starts an export job of the log query of the query, start
+// and end parameters and returns its status.
+func (m *Manager) SubmitHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		serverutil.WriteError(err, w)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req, err := loghttp.ParseRangeQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	job, err := m.Submit(r.Context(), tenantID, req.Query, req.Start, req.End)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrDisabled):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case errors.Is(err, ErrTooManyJobs):
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+		default:
+			serverutil.WriteError(err, w)
+		}
+		return
+	}
+
+	level.Info(m.logger).Log("msg", "export job submitted", "tenant", tenantID, "id", job.ID, "query", job.Query, "partitions", job.Partitions)
+	// Headers set after the status code are ignored.
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	util.WriteJSONResponse(w, job)
+}
+
+// ListHandler lists the export jobs of the tenant.
+func (m *Manager) ListHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		serverutil.WriteError(err, w)
+		return
+	}
+	jobs, err := m.List(r.Context(), tenantID)
+	if err != nil {
+		serverutil.WriteError(err, w)
+		return
+	}
+	util.WriteJSONResponse(w, listResponse{Jobs: jobs})
+}
+
+// GetHandler returns the status of an export job.
+func (m *Manager) GetHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		serverutil.WriteError(err, w)
+		return
+	}
+	job, err := m.Get(r.Context(), tenantID, mux.Vars(r)["id"])
+	if err != nil {
+		if errors.Is(err, ErrJobNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		serverutil.WriteError(err, w)
+		return
+	}
+	util.WriteJSONResponse(w, job)
+}
+
+// CancelHandler cancels an export job.
+func (m *Manager) CancelHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		serverutil.WriteError(err, w)
+		return
+	}
+	if err := m.Cancel(r.Context(), tenantID, mux.Vars(r)["id"]); err != nil {
+		switch {
+		case errors.Is(err, ErrJobNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, ErrJobFinished):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			serverutil.WriteError(err, w)
+		}
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,716 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
+	"github.com/grafana/dskit/concurrency"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/common/model"
+	"github.com/thanos-io/objstore"
+	"golang.org/x/sync/semaphore"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/querier/plan"
+	"github.com/grafana/loki/v3/pkg/querier/queryrange"
+	"github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
+	"github.com/grafana/loki/v3/pkg/util/httpreq"
+	serverutil "github.com/grafana/loki/v3/pkg/util/server"
+)
+
+// successMarker is written next to the files of an export once all its
+// partitions are exported, as done by Spark.
+const successMarker = "_SUCCESS"
+
+// heartbeatInterval is how often a query frontend writes the status of the
+// jobs it runs, which tells the others that they are still running.
+const heartbeatInterval = time.Minute
+
+var (
+	ErrDisabled    = errors.New("export is disabled for this tenant")
+	ErrJobNotFound = errors.New("export job not found")
+	ErrJobFinished = errors.New("export job already finished")
+	ErrTooManyJobs = errors.New("too many export jobs in progress")
+
+	errJobOrphaned = errors.New("export job interrupted: the query frontend running it stopped")
+)
+
+// Limits are the per-tenant limits of exports.
+type Limits interface {
+	ExportEnabled(userID string) bool
+	MaxExportRange(userID string) time.Duration
+}
+
+// Status is the status of an export job.
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	StatusCanceled  Status = "canceled"
+)
+
+func (s Status) finished() bool {
+	return s == StatusSucceeded || s == StatusFailed || s == StatusCanceled
+}
+
+// Job is the status of an export of the results of a log query.
+type Job struct {
+	ID             string     `json:"id"`
+	Query          string     `json:"query"`
+	Start          time.Time  `json:"start"`
+	End            time.Time  `json:"end"`
+	Status         Status     `json:"status"`
+	Error          string     `json:"error,omitempty"`
+	Location       string     `json:"location"`
+	Partitions     int        `json:"partitions"`
+	PartitionsDone int        `json:"partitions_done"`
+	Files          int        `json:"files"`
+	Entries        int64      `json:"entries"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	FinishedAt     *time.Time `json:"finished_at,omitempty"`
+}
+
+type job struct {
+	Job
+
+	tenant string
+	expr   syntax.LogSelectorExpr
+	cancel context.CancelFunc
+	// persistMtx orders the writes of the status of the job.
+	persistMtx sync.Mutex
+}
+
+// partition is the time range of the logs written to a single file.
+type partition struct {
+	start, end time.Time
+}
+
+// objectName returns the name of the file of p, in directories per day so
+// that the files can be read as a Hive partitioned dataset.
+func (p partition) objectName(dir string) string {
+	return path.Join(dir, "date="+p.start.Format("2006-01-02"), "part-"+p.start.Format("150405")+".parquet")
+}
+
+// partitions splits [start, end) into partitions aligned on interval.
+func partitions(start, end time.Time, interval time.Duration) []partition {
+	var parts []partition
+	for from := start; from.Before(end); {
+		to := from.Truncate(interval).Add(interval)
+		if to.After(end) {
+			to = end
+		}
+		parts = append(parts, partition{start: from, end: to})
+		from = to
+	}
+	return parts
+}
+
+type metrics struct {
+	jobs    *prometheus.CounterVec
+	entries prometheus.Counter
+}
+
+func newMetrics(r prometheus.Registerer) *metrics {
+	return &metrics{
+		jobs: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "query_frontend_export_jobs_total",
+			Help:      "Total number of finished export jobs by status.",
+		}, []string{"status"}),
+		entries: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "query_frontend_export_entries_total",
+			Help:      "Total number of log entries written by export jobs.",
+		}),
+	}
+}
+
+// Manager runs export jobs. A job splits the time range of a log query into
+// partitions of Config.PartitionInterval, pages through the results of each
+// partition with the query frontend handler and writes them as a Parquet
+// file to the bucket.
+//
+// The status of a job is written next to its files and refreshed every
+// heartbeat by the Manager running it, so that any Manager sharing the bucket
+// reports and cancels it. A job whose status is not refreshed for several
+// heartbeats is orphaned, as its Manager stopped without finishing it, and
+// is failed by the first Manager reading it.
+type Manager struct {
+	cfg     Config
+	bucket  objstore.Bucket
+	store   *store
+	handler queryrangebase.Handler
+	limits  Limits
+	logger  log.Logger
+	metrics *metrics
+
+	// workers limits the number of partitions exported at the same time.
+	workers *semaphore.Weighted
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mtx sync.Mutex
+	// running holds the jobs run by this Manager, by tenant and ID.
+	running   map[string]*job
+	now       func() time.Time
+	heartbeat time.Duration
+}
+
+// NewManager creates a Manager writing to bucket the results of queries
+// executed by handler.
+func NewManager(cfg Config, bucket objstore.Bucket, handler queryrangebase.Handler, limits Limits, logger log.Logger, r prometheus.Registerer) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		cfg:       cfg,
+		bucket:    bucket,
+		store:     &store{bucket: bucket},
+		handler:   handler,
+		limits:    limits,
+		logger:    logger,
+		metrics:   newMetrics(r),
+		workers:   semaphore.NewWeighted(int64(cfg.MaxConcurrency)),
+		ctx:       ctx,
+		cancel:    cancel,
+		running:   map[string]*job{},
+		now:       time.Now,
+		heartbeat: heartbeatInterval,
+	}
+}
+
+// Start fails the jobs orphaned by the Managers which stopped without
+// finishing them, in the background.
+func (m *Manager) Start() {
+	m.wg.Add(1)
+	go m.failOrphanedJobs()
+}
+
+// Submit starts an export job of the results of query between start and end.
+// The number of jobs in progress of tenant is counted across all the Managers
+// sharing the bucket. As jobs submitted at the same time to different Managers
+// may not see each other, the jobs are counted again once the status of the
+// new job is written, and the new job fails if older jobs reached the limit.
+func (m *Manager) Submit(ctx context.Context, tenant, query string, start, end time.Time) (Job, error) {
+	if !m.limits.ExportEnabled(tenant) {
+		return Job{}, ErrDisabled
+	}
+	expr, err := syntax.ParseExpr(query)
+	if err != nil {
+		return Job{}, err
+	}
+	logExpr, ok := expr.(syntax.LogSelectorExpr)
+	if !ok {
+		return Job{}, serverutil.UserError("only log queries can be exported")
+	}
+	if !end.After(start) {
+		return Job{}, serverutil.UserError("end timestamp must be after start timestamp")
+	}
+	if maxRange := m.limits.MaxExportRange(tenant); maxRange > 0 && end.Sub(start) > maxRange {
+		return Job{}, serverutil.UserError(fmt.Sprintf("the export range (%s) exceeds the limit (%s)", model.Duration(end.Sub(start)), model.Duration(maxRange)))
+	}
+
+	jobs, err := m.List(ctx, tenant)
+	if err != nil {
+		return Job{}, err
+	}
+	var inProgress int
+	for _, j := range jobs {
+		if !j.Status.finished() {
+			inProgress++
+		}
+	}
+	if inProgress >= m.cfg.MaxJobsPerTenant {
+		return Job{}, ErrTooManyJobs
+	}
+
+	id := uuid.NewString()
+	parts := partitions(start.UTC(), end.UTC(), m.cfg.PartitionInterval)
+	now := m.now().UTC()
+	j := &job{
+		Job: Job{
+			ID:         id,
+			Query:      query,
+			Start:      start.UTC(),
+			End:        end.UTC(),
+			Status:     StatusQueued,
+			Location:   path.Join(m.cfg.StoragePrefix, tenant, id),
+			Partitions: len(parts),
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		},
+		tenant: tenant,
+		expr:   logExpr,
+	}
+	if err := m.store.put(ctx, tenant, j.Job); err != nil {
+		return Job{}, fmt.Errorf("failed to store export job: %w", err)
+	}
+	if err := m.checkOlderJobs(ctx, tenant, j.Job); err != nil {
+		m.finish(&j.Job, StatusFailed, err)
+		if putErr := m.store.put(ctx, tenant, j.Job); putErr != nil {
+			level.Warn(m.logger).Log("msg", "failed to store export job status", "tenant", tenant, "id", id, "err", putErr)
+		}
+		return Job{}, err
+	}
+
+	submitted := j.Job
+	var runCtx context.Context
+	runCtx, j.cancel = context.WithCancel(m.ctx)
+	m.mtx.Lock()
+	m.running[path.Join(tenant, id)] = j
+	m.mtx.Unlock()
+
+	m.wg.Add(1)
+	go m.run(runCtx, j, parts)
+
+	return submitted, nil
+}
+
+// checkOlderJobs returns ErrTooManyJobs if Config.MaxJobsPerTenant jobs of
+// tenant in progress were submitted before j. Jobs submitted at the same time
+// are ordered by ID, so that all the Managers agree on which jobs are newer.
+func (m *Manager) checkOlderJobs(ctx context.Context, tenant string, j Job) error {
+	jobs, err := m.List(ctx, tenant)
+	if err != nil {
+		return err
+	}
+	var older int
+	for _, other := range jobs {
+		if other.ID == j.ID || other.Status.finished() {
+			continue
+		}
+		if other.CreatedAt.Before(j.CreatedAt) || other.CreatedAt.Equal(j.CreatedAt) && other.ID < j.ID {
+			older++
+		}
+	}
+	if older >= m.cfg.MaxJobsPerTenant {
+		return ErrTooManyJobs
+	}
+	return nil
+}
+
+// Get returns the job of tenant with the given id.
+func (m *Manager) Get(ctx context.Context, tenant, id string) (Job, error) {
+	j, err := m.get(ctx, tenant, id)
+	if err != nil {
+		return Job{}, err
+	}
+	if m.expired(j) {
+		return Job{}, ErrJobNotFound
+	}
+	return j, nil
+}
+
+// get returns the job of tenant with the given id, failing it if it is
+// orphaned.
+func (m *Manager) get(ctx context.Context, tenant, id string) (Job, error) {
+	m.mtx.Lock()
+	if j, ok := m.running[path.Join(tenant, id)]; ok {
+		defer m.mtx.Unlock()
+		return j.Job, nil
+	}
+	m.mtx.Unlock()
+
+	j, err := m.store.get(ctx, tenant, id)
+	if err != nil || j.Status.finished() {
+		return j, err
+	}
+	canceled, err := m.store.canceled(ctx, tenant, id)
+	if err != nil {
+		return Job{}, err
+	}
+	if m.now().Sub(j.UpdatedAt) <= m.orphanTimeout() {
+		if canceled {
+			// The Manager running the job writes its final status once it
+			// stopped it.
+			j.Status = StatusCanceled
+		}
+		return j, nil
+	}
+
+	if canceled {
+		m.finish(&j, StatusCanceled, nil)
+	} else {
+		level.Warn(m.logger).Log("msg", "export job orphaned", "tenant", tenant, "id", id, "updated_at", j.UpdatedAt)
+		m.finish(&j, StatusFailed, errJobOrphaned)
+	}
+	return j, m.store.put(ctx, tenant, j)
+}
+
+// List returns the jobs of tenant, oldest first. The status of the jobs
+// finished for longer than Config.JobRetention is deleted.
+func (m *Manager) List(ctx context.Context, tenant string) ([]Job, error) {
+	ids, err := m.store.list(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+	jobs := []Job{}
+	for _, id := range ids {
+		j, err := m.get(ctx, tenant, id)
+		if errors.Is(err, ErrJobNotFound) {
+			// The files of a job whose status was deleted.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if m.expired(j) {
+			if err := m.store.delete(ctx, tenant, id); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		jobs = append(jobs, j)
+	}
+	sort.Slice(jobs, func(i, k int) bool {
+		return jobs[i].CreatedAt.Before(jobs[k].CreatedAt)
+	})
+	return jobs, nil
+}
+
+// Cancel cancels the job of tenant with the given id, whichever Manager runs
+// it. Files already written by the job are kept.
+func (m *Manager) Cancel(ctx context.Context, tenant, id string) error {
+	j, err := m.Get(ctx, tenant, id)
+	if err != nil {
+		return err
+	}
+	if j.Status.finished() {
+		return ErrJobFinished
+	}
+	if err := m.store.cancel(ctx, tenant, id); err != nil {
+		return err
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if j, ok := m.running[path.Join(tenant, id)]; ok && !j.Status.finished() {
+		m.finish(&j.Job, StatusCanceled, nil)
+		j.cancel()
+	}
+	return nil
+}
+
+// Stop cancels the jobs in progress and waits for them to return.
+func (m *Manager) Stop() {
+	m.cancel()
+	m.wg.Wait()
+}
+
+// expired returns whether j finished for longer than Config.JobRetention.
+func (m *Manager) expired(j Job) bool {
+	return m.cfg.JobRetention > 0 && j.FinishedAt != nil && m.now().Sub(*j.FinishedAt) > m.cfg.JobRetention
+}
+
+// orphanTimeout is how long after its last heartbeat a job is orphaned.
+func (m *Manager) orphanTimeout() time.Duration {
+	return 5 * m.heartbeat
+}
+
+// failOrphanedJobs fails the jobs of all tenants orphaned by the Managers
+// which stopped without finishing them, so that they no longer count towards
+// the limit of their tenant. The jobs orphaned later are failed once read.
+func (m *Manager) failOrphanedJobs() {
+	defer m.wg.Done()
+
+	tenants, err := m.store.tenants(m.ctx)
+	if err != nil {
+		level.Warn(m.logger).Log("msg", "failed to list the tenants with export jobs", "err", err)
+		return
+	}
+	for _, tenant := range tenants {
+		if _, err := m.List(m.ctx, tenant); err != nil {
+			level.Warn(m.logger).Log("msg", "failed to list export jobs", "tenant", tenant, "err", err)
+		}
+	}
+}
+
+// finish sets the final status of j. It must be called with mtx held for the
+// jobs run by the Manager.
+func (m *Manager) finish(j *Job, status Status, err error) {
+	finishedAt := m.now().UTC()
+	j.Status = status
+	j.FinishedAt = &finishedAt
+	if err != nil {
+		j.Error = err.Error()
+	}
+	m.metrics.jobs.WithLabelValues(string(status)).Inc()
+}
+
+// persist writes the status of j. Failures are only logged, since the status
+// is written again on the next heartbeat.
+func (m *Manager) persist(ctx context.Context, j *job) {
+	j.persistMtx.Lock()
+	defer j.persistMtx.Unlock()
+
+	m.mtx.Lock()
+	j.UpdatedAt = m.now().UTC()
+	status := j.Job
+	m.mtx.Unlock()
+	if err := m.store.put(ctx, j.tenant, status); err != nil {
+		level.Warn(m.logger).Log("msg", "failed to store export job status", "tenant", j.tenant, "id", j.ID, "err", err)
+	}
+}
+
+// heartbeats writes the status of j every heartbeat until ctx is done, and
+// stops j if another Manager canceled it.
+func (m *Manager) heartbeats(ctx context.Context, j *job) {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		canceled, err := m.store.canceled(ctx, j.tenant, j.ID)
+		if err != nil {
+			level.Warn(m.logger).Log("msg", "failed to check export job cancellation", "tenant", j.tenant, "id", j.ID, "err", err)
+		}
+		if canceled {
+			m.mtx.Lock()
+			if !j.Status.finished() {
+				m.finish(&j.Job, StatusCanceled, nil)
+			}
+			m.mtx.Unlock()
+			j.cancel()
+			return
+		}
+		m.persist(ctx, j)
+	}
+}
+
+func (m *Manager) run(ctx context.Context, j *job, parts []partition) {
+	defer m.wg.Done()
+	defer j.cancel()
+
+	m.wg.Add(1)
+	go m.heartbeats(ctx, j)
+
+	dir := path.Join(j.tenant, j.ID)
+	err := concurrency.ForEachJob(ctx, len(parts), m.cfg.MaxConcurrency, func(ctx context.Context, idx int) error {
+		if err := m.workers.Acquire(ctx, 1); err != nil {
+			return err
+		}
+		defer m.workers.Release(1)
+
+		m.mtx.Lock()
+		started := j.Status == StatusQueued
+		if started {
+			j.Status = StatusRunning
+		}
+		m.mtx.Unlock()
+		if started {
+			m.persist(ctx, j)
+		}
+
+		part := parts[idx]
+		entries, err := m.exportPartition(ctx, j, part, part.objectName(dir))
+		if err != nil {
+			return fmt.Errorf("failed to export logs from %s to %s: %w", part.start.Format(time.RFC3339), part.end.Format(time.RFC3339), err)
+		}
+
+		m.mtx.Lock()
+		j.PartitionsDone++
+		j.Entries += entries
+		if entries > 0 {
+			j.Files++
+		}
+		m.mtx.Unlock()
+		m.metrics.entries.Add(float64(entries))
+		m.persist(ctx, j)
+		return nil
+	})
+	if err == nil {
+		err = m.bucket.Upload(ctx, path.Join(dir, successMarker), strings.NewReader(""))
+	}
+
+	m.mtx.Lock()
+	switch {
+	case j.Status.finished():
+		// Canceled.
+	case err == nil:
+		m.finish(&j.Job, StatusSucceeded, nil)
+	case m.ctx.Err() != nil:
+		m.finish(&j.Job, StatusFailed, errors.New("export interrupted by shutdown"))
+	default:
+		level.Warn(m.logger).Log("msg", "export job failed", "tenant", j.tenant, "id", j.ID, "err", err)
+		m.finish(&j.Job, StatusFailed, err)
+	}
+	m.mtx.Unlock()
+
+	// The final status is written even if the Manager is stopping, and the
+	// job is only forgotten once it is written.
+	m.persist(context.WithoutCancel(ctx), j)
+	m.mtx.Lock()
+	delete(m.running, dir)
+	m.mtx.Unlock()
+}
+
+// exportPartition writes the results of the query of j in part to a file
+// and returns the number of entries written. No file is written if there
+// are no results.
+func (m *Manager) exportPartition(ctx context.Context, j *job, part partition, name string) (int64, error) {
+	// Exports are expensive and not interactive, so they are queued with a
+	// low priority.
+	ctx = user.InjectOrgID(ctx, j.tenant)
+	ctx = httpreq.InjectHeader(ctx, httpreq.LokiQueryPriorityHeader, httpreq.LowQueryPriority)
+
+	var (
+		w       *partitionWriter
+		written int64
+		start   = part.start
+		end     = part.end
+		limit   = m.cfg.PageSize
+		// seen counts the entries at start which were written by the
+		// previous pages, by stream and line, since start is inclusive.
+		seen = map[entryKey]int{}
+	)
+	for {
+		resp, err := m.handler.Do(ctx, &queryrange.LokiRequest{
+			Query:     j.Query,
+			Limit:     uint32(limit),
+			Direction: logproto.FORWARD,
+			StartTs:   start,
+			EndTs:     end,
+			Step:      time.Second.Milliseconds(),
+			Path:      "/loki/api/v1/query_range",
+			Plan:      &plan.QueryPlan{AST: j.expr},
+		})
+		if err != nil {
+			return m.abort(w, err)
+		}
+		lokiResp, ok := resp.(*queryrange.LokiResponse)
+		if !ok {
+			return m.abort(w, fmt.Errorf("unexpected response type %T", resp))
+		}
+
+		page := newPage(lokiResp.Data.Result, start, seen)
+		var count int
+		for _, s := range page.streams {
+			count += len(s.Entries)
+		}
+		if count > 0 {
+			if w == nil {
+				w = m.openPartition(ctx, name)
+			}
+			if err := queryrange.WriteLogsParquet(w.writer, page.streams); err != nil {
+				return m.abort(w, err)
+			}
+			// Flush a row group per page to bound the memory buffered by the
+			// writer.
+			if err := w.writer.Flush(); err != nil {
+				return m.abort(w, err)
+			}
+			written += int64(count)
+		}
+
+		switch {
+		case page.returned >= limit && page.last.Equal(start):
+			// The page only holds entries at start, the next page would
+			// return the same entries. The entries at start are read again
+			// with a larger limit until they are all read, which fails once
+			// the limit exceeds the entries limit of the tenant.
+			limit *= 2
+			end = start.Add(time.Nanosecond)
+			seen = page.atLast
+		case page.returned >= limit:
+			start, end, limit = page.last, part.end, m.cfg.PageSize
+			seen = page.atLast
+		case end.Before(part.end):
+			// All the entries at start were read.
+			start, end, limit = end, part.end, m.cfg.PageSize
+			seen = map[entryKey]int{}
+		default:
+			if w == nil {
+				return 0, nil
+			}
+			if err := w.close(); err != nil {
+				return 0, err
+			}
+			return written, nil
+		}
+	}
+}
+
+func (m *Manager) abort(w *partitionWriter, err error) (int64, error) {
+	if w != nil {
+		w.abort(err)
+	}
+	return 0, err
+}
+
+type entryKey struct {
+	labels, line string
+}
+
+// page is a page of the results of an export query.
+type page struct {
+	// streams holds the entries which were not written yet.
+	streams []logproto.Stream
+	// returned is the number of entries of the page, and last the timestamp
+	// of its last entry.
+	returned int
+	last     time.Time
+	// atLast counts the entries of the page at last.
+	atLast map[entryKey]int
+}
+
+// newPage returns the page of the entries of streams starting at start, where
+// the entries counted in seen were already written. Only as many identical
+// entries as counted in seen are skipped, so that identical entries of the
+// same stream are all written.
+func newPage(streams []logproto.Stream, start time.Time, seen map[entryKey]int) page {
+	p := page{
+		streams: make([]logproto.Stream, 0, len(streams)),
+		atLast:  map[entryKey]int{},
+	}
+	for _, s := range streams {
+		for _, e := range s.Entries {
+			p.returned++
+			if e.Timestamp.After(p.last) {
+				p.last = e.Timestamp
+			}
+		}
+	}
+
+	skip := maps.Clone(seen)
+	for _, s := range streams {
+		entries := make([]logproto.Entry, 0, len(s.Entries))
+		for _, e := range s.Entries {
+			key := entryKey{labels: s.Labels, line: e.Line}
+			if e.Timestamp.Equal(p.last) {
+				p.atLast[key]++
+			}
+			if e.Timestamp.Equal(start) && skip[key] > 0 {
+				skip[key]--
+				continue
+			}
+			entries = append(entries, e)
+		}
+		if len(entries) > 0 {
+			p.streams = append(p.streams, logproto.Stream{Labels: s.Labels, Entries: entries})
+		}
+	}
+	return p
+}
@@ -0,0 +1,506 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/grafana/dskit/user"
+	"github.com/parquet-go/parquet-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/querier/queryrange"
+	"github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
+	"github.com/grafana/loki/v3/pkg/util/httpreq"
+)
+
+var testTime = time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+
+type testEntry struct {
+	labels string
+	ts     time.Time
+	line   string
+}
+
+// logsHandler answers forward log queries with the entries between the
+// start and end of the request, up to the limit.
+func logsHandler(t *testing.T, entries []testEntry) queryrangebase.Handler {
+	return queryrangebase.HandlerFunc(func(ctx context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+		require.Equal(t, httpreq.LowQueryPriority, httpreq.ExtractHeader(ctx, httpreq.LokiQueryPriorityHeader))
+		req := r.(*queryrange.LokiRequest)
+
+		var matched []testEntry
+		for _, e := range entries {
+			if !e.ts.Before(req.StartTs) && e.ts.Before(req.EndTs) {
+				matched = append(matched, e)
+			}
+		}
+		sort.SliceStable(matched, func(i, j int) bool { return matched[i].ts.Before(matched[j].ts) })
+		if len(matched) > int(req.Limit) {
+			matched = matched[:req.Limit]
+		}
+
+		var (
+			result  []logproto.Stream
+			streams = map[string]int{}
+		)
+		for _, e := range matched {
+			i, ok := streams[e.labels]
+			if !ok {
+				i = len(result)
+				streams[e.labels] = i
+				result = append(result, logproto.Stream{Labels: e.labels})
+			}
+			result[i].Entries = append(result[i].Entries, logproto.Entry{Timestamp: e.ts, Line: e.line})
+		}
+		return &queryrange.LokiResponse{Status: "success", Data: queryrange.LokiData{ResultType: "streams", Result: result}}, nil
+	})
+}
+
+type fakeLimits struct {
+	disabled bool
+	maxRange time.Duration
+}
+
+func (l fakeLimits) ExportEnabled(string) bool { return !l.disabled }
+
+func (l fakeLimits) MaxExportRange(string) time.Duration { return l.maxRange }
+
+func testManager(cfg Config, bucket objstore.Bucket, handler queryrangebase.Handler) *Manager {
+	return NewManager(cfg, bucket, handler, fakeLimits{}, log.NewNopLogger(), prometheus.NewRegistry())
+}
+
+func testConfig() Config {
+	return Config{
+		Enabled:           true,
+		StoragePrefix:     "exports/",
+		PartitionInterval: time.Hour,
+		MaxConcurrency:    2,
+		MaxJobsPerTenant:  1,
+		PageSize:          3,
+		JobRetention:      time.Hour,
+	}
+}
+
+func waitFinished(t *testing.T, m *Manager, id string) Job {
+	var job Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = m.Get(context.Background(), "tenant-a", id)
+		require.NoError(t, err)
+		return job.Status.finished()
+	}, 5*time.Second, 10*time.Millisecond)
+	return job
+}
+
+func readRows(t *testing.T, bucket objstore.Bucket, name string) []queryrange.LogStreamRowType {
+	r, err := bucket.Get(context.Background(), name)
+	require.NoError(t, err)
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	rows, err := parquet.Read[queryrange.LogStreamRowType](bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	return rows
+}
+
+func TestPartitions(t *testing.T) {
+	require.Equal(t, []partition{
+		{start: testTime.Add(30 * time.Minute), end: testTime.Add(time.Hour)},
+		{start: testTime.Add(time.Hour), end: testTime.Add(2 * time.Hour)},
+		{start: testTime.Add(2 * time.Hour), end: testTime.Add(150 * time.Minute)},
+	}, partitions(testTime.Add(30*time.Minute), testTime.Add(150*time.Minute), time.Hour))
+
+	require.Equal(t, "tenant/id/date=2026-10-18/part-100000.parquet", partition{start: testTime}.objectName("tenant/id"))
+}
+
+func TestManager_Export(t *testing.T) {
+	const foo, bar = `{app="foo"}`, `{app="bar"}`
+	entries := []testEntry{
+		{foo, testTime, "1"},
+		// Entries at the same timestamp across the pages.
+		{foo, testTime.Add(time.Minute), "2"},
+		{bar, testTime.Add(time.Minute), "3"},
+		{bar, testTime.Add(2 * time.Minute), "4"},
+		{foo, testTime.Add(2 * time.Minute), "5"},
+		// No entries in the second partition.
+		{bar, testTime.Add(2 * time.Hour), "6"},
+	}
+	ctx := context.Background()
+	bucket := objstore.NewInMemBucket()
+	m := testManager(testConfig(), bucket, logsHandler(t, entries))
+	defer m.Stop()
+
+	job, err := m.Submit(ctx, "tenant-a", `{app=~"foo|bar"}`, testTime, testTime.Add(3*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, "exports/tenant-a/"+job.ID, job.Location)
+	require.Equal(t, 3, job.Partitions)
+
+	job = waitFinished(t, m, job.ID)
+	require.Equal(t, StatusSucceeded, job.Status, job.Error)
+	require.Equal(t, 3, job.PartitionsDone)
+	require.Equal(t, 2, job.Files)
+	require.Equal(t, int64(6), job.Entries)
+
+	rows := readRows(t, bucket, "tenant-a/"+job.ID+"/date=2026-10-18/part-100000.parquet")
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		lines = append(lines, row.Line)
+	}
+	require.Equal(t, []string{"1", "2", "3", "4", "5"}, lines)
+	require.Equal(t, map[string]string{"app": "bar"}, rows[2].Labels)
+	require.Equal(t, testTime.Add(time.Minute).UnixNano(), rows[2].Timestamp)
+
+	rows = readRows(t, bucket, "tenant-a/"+job.ID+"/date=2026-10-18/part-120000.parquet")
+	require.Len(t, rows, 1)
+
+	exists, err := bucket.Exists(context.Background(), "tenant-a/"+job.ID+"/date=2026-10-18/part-110000.parquet")
+	require.NoError(t, err)
+	require.False(t, exists)
+	exists, err = bucket.Exists(context.Background(), "tenant-a/"+job.ID+"/"+successMarker)
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	exists, err = bucket.Exists(context.Background(), "tenant-a/"+job.ID+"/"+manifestFile)
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	jobs, err := m.List(ctx, "tenant-a")
+	require.NoError(t, err)
+	require.Equal(t, []Job{job}, jobs)
+	jobs, err = m.List(ctx, "tenant-b")
+	require.NoError(t, err)
+	require.Empty(t, jobs)
+	for _, id := range []string{job.ID, "../tenant-a/" + job.ID} {
+		_, err = m.Get(ctx, "tenant-b", id)
+		require.ErrorIs(t, err, ErrJobNotFound)
+	}
+
+	// The status of finished jobs is deleted after the retention period, not
+	// their files.
+	now := time.Now()
+	m.now = func() time.Time { return now.Add(2 * time.Hour) }
+	_, err = m.Get(ctx, "tenant-a", job.ID)
+	require.ErrorIs(t, err, ErrJobNotFound)
+	jobs, err = m.List(ctx, "tenant-a")
+	require.NoError(t, err)
+	require.Empty(t, jobs)
+	exists, err = bucket.Exists(context.Background(), "tenant-a/"+job.ID+"/"+manifestFile)
+	require.NoError(t, err)
+	require.False(t, exists)
+	exists, err = bucket.Exists(context.Background(), "tenant-a/"+job.ID+"/"+successMarker)
+	require.NoError(t, err)
+	require.True(t, exists)
+}
+
+func TestManager_ExportSameTimestamp(t *testing.T) {
+	const foo, bar = `{app="foo"}`, `{app="bar"}`
+	var entries []testEntry
+	// More entries at the same timestamp than twice the page size.
+	for _, line := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		entries = append(entries, testEntry{foo, testTime, line})
+	}
+	entries = append(entries,
+		// Identical entries are all exported, even across pages.
+		testEntry{foo, testTime.Add(time.Minute), "x"},
+		testEntry{foo, testTime.Add(time.Minute), "x"},
+		testEntry{bar, testTime.Add(time.Minute), "y"},
+		testEntry{foo, testTime.Add(2 * time.Minute), "z"},
+	)
+
+	type request struct {
+		start, end time.Time
+		limit      uint32
+	}
+	var (
+		mtx      sync.Mutex
+		requests []request
+	)
+	handler := logsHandler(t, entries)
+	recorder := queryrangebase.HandlerFunc(func(ctx context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+		mtx.Lock()
+		requests = append(requests, request{r.GetStart(), r.GetEnd(), r.(*queryrange.LokiRequest).Limit})
+		mtx.Unlock()
+		return handler.Do(ctx, r)
+	})
+	bucket := objstore.NewInMemBucket()
+	m := testManager(testConfig(), bucket, recorder)
+	defer m.Stop()
+
+	job, err := m.Submit(context.Background(), "tenant-a", `{app=~"foo|bar"}`, testTime, testTime.Add(time.Hour))
+	require.NoError(t, err)
+	job = waitFinished(t, m, job.ID)
+	require.Equal(t, StatusSucceeded, job.Status, job.Error)
+	require.Equal(t, int64(11), job.Entries)
+
+	rows := readRows(t, bucket, "tenant-a/"+job.ID+"/date=2026-10-18/part-100000.parquet")
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		lines = append(lines, row.Line)
+	}
+	require.Equal(t, []string{"a", "b", "c", "d", "e", "f", "g", "x", "x", "y", "z"}, lines)
+
+	end := testTime.Add(time.Hour)
+	require.Equal(t, []request{
+		{testTime, end, 3},
+		// The timestamp is read again with a larger limit until all its
+		// entries are read.
+		{testTime, testTime.Add(time.Nanosecond), 6},
+		{testTime, testTime.Add(time.Nanosecond), 12},
+		{testTime.Add(time.Nanosecond), end, 3},
+		{testTime.Add(time.Minute), end, 3},
+		{testTime.Add(time.Minute), testTime.Add(time.Minute + time.Nanosecond), 6},
+		{testTime.Add(time.Minute + time.Nanosecond), end, 3},
+	}, requests)
+}
+
+func TestManager_Cancel(t *testing.T) {
+	var (
+		once    sync.Once
+		started = make(chan struct{})
+	)
+	handler := queryrangebase.HandlerFunc(func(ctx context.Context, _ queryrangebase.Request) (queryrangebase.Response, error) {
+		once.Do(func() { close(started) })
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	ctx := context.Background()
+	m := testManager(testConfig(), objstore.NewInMemBucket(), handler)
+	defer m.Stop()
+
+	job, err := m.Submit(ctx, "tenant-a", `{app="foo"}`, testTime, testTime.Add(time.Hour))
+	require.NoError(t, err)
+
+	_, err = m.Submit(ctx, "tenant-a", `{app="bar"}`, testTime, testTime.Add(time.Hour))
+	require.ErrorIs(t, err, ErrTooManyJobs)
+
+	<-started
+	require.ErrorIs(t, m.Cancel(ctx, "tenant-b", job.ID), ErrJobNotFound)
+	require.NoError(t, m.Cancel(ctx, "tenant-a", job.ID))
+	require.ErrorIs(t, m.Cancel(ctx, "tenant-a", job.ID), ErrJobFinished)
+
+	job = waitFinished(t, m, job.ID)
+	require.Equal(t, StatusCanceled, job.Status)
+	require.Zero(t, job.PartitionsDone)
+
+	// The canceled job no longer counts towards the limit.
+	_, err = m.Submit(ctx, "tenant-a", `{app="bar"}`, testTime, testTime.Add(time.Hour))
+	require.NoError(t, err)
+}
+
+func TestManager_SharedBucket(t *testing.T) {
+	var (
+		once    sync.Once
+		started = make(chan struct{})
+	)
+	handler := queryrangebase.HandlerFunc(func(ctx context.Context, _ queryrangebase.Request) (queryrangebase.Response, error) {
+		once.Do(func() { close(started) })
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	ctx := context.Background()
+	bucket := objstore.NewInMemBucket()
+	running := testManager(testConfig(), bucket, handler)
+	running.heartbeat = 10 * time.Millisecond
+	defer running.Stop()
+	other := testManager(testConfig(), bucket, handler)
+	defer other.Stop()
+
+	job, err := running.Submit(ctx, "tenant-a", `{app="foo"}`, testTime, testTime.Add(time.Hour))
+	require.NoError(t, err)
+	<-started
+
+	// The jobs of the other frontends are reported, and count towards the
+	// limit of the tenant.
+	require.Eventually(t, func() bool {
+		job, err = other.Get(ctx, "tenant-a", job.ID)
+		require.NoError(t, err)
+		return job.Status == StatusRunning
+	}, 5*time.Second, 10*time.Millisecond)
+	_, err = other.Submit(ctx, "tenant-a", `{app="bar"}`, testTime, testTime.Add(time.Hour))
+	require.ErrorIs(t, err, ErrTooManyJobs)
+
+	// The job is stopped by the frontend running it.
+	require.NoError(t, other.Cancel(ctx, "tenant-a", job.ID))
+	job, err = other.Get(ctx, "tenant-a", job.ID)
+	require.NoError(t, err)
+	require.Equal(t, StatusCanceled, job.Status)
+	require.ErrorIs(t, other.Cancel(ctx, "tenant-a", job.ID), ErrJobFinished)
+
+	job = waitFinished(t, running, job.ID)
+	require.Equal(t, StatusCanceled, job.Status)
+	require.Eventually(t, func() bool {
+		stored, err := running.store.get(ctx, "tenant-a", job.ID)
+		require.NoError(t, err)
+		return stored.Status == StatusCanceled && stored.FinishedAt != nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+// racingBucket writes the status of job before the first status written to
+// it, as if job was submitted to another frontend at the same time.
+type racingBucket struct {
+	objstore.Bucket
+	once sync.Once
+	job  Job
+}
+
+func (b *racingBucket) Upload(ctx context.Context, name string, r io.Reader) error {
+	var err error
+	b.once.Do(func() {
+		err = (&store{bucket: b.Bucket}).put(ctx, "tenant-a", b.job)
+	})
+	if err != nil {
+		return err
+	}
+	return b.Bucket.Upload(ctx, name, r)
+}
+
+func TestManager_SubmitConcurrently(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	bucket := &racingBucket{
+		Bucket: objstore.NewInMemBucket(),
+		job:    Job{ID: uuid.NewString(), Status: StatusRunning, CreatedAt: now.Add(-time.Second), UpdatedAt: now},
+	}
+	m := testManager(testConfig(), bucket, logsHandler(t, nil))
+	defer m.Stop()
+
+	// The older job submitted to another frontend is only seen once the
+	// status of the new job is written, which then fails.
+	_, err := m.Submit(ctx, "tenant-a", `{app="foo"}`, testTime, testTime.Add(time.Hour))
+	require.ErrorIs(t, err, ErrTooManyJobs)
+
+	jobs, err := m.List(ctx, "tenant-a")
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	require.Equal(t, bucket.job.ID, jobs[0].ID)
+	require.Equal(t, StatusRunning, jobs[0].Status)
+	require.Equal(t, StatusFailed, jobs[1].Status)
+	require.Equal(t, ErrTooManyJobs.Error(), jobs[1].Error)
+}
+
+func TestManager_OrphanedJobs(t *testing.T) {
+	ctx := context.Background()
+	bucket := objstore.NewInMemBucket()
+	s := &store{bucket: bucket}
+
+	stale, recent := time.Now().Add(-time.Hour), time.Now()
+	jobs := map[string]Job{
+		"orphaned": {ID: uuid.NewString(), Status: StatusRunning, UpdatedAt: stale},
+		"canceled": {ID: uuid.NewString(), Status: StatusQueued, UpdatedAt: stale},
+		"running":  {ID: uuid.NewString(), Status: StatusRunning, UpdatedAt: recent},
+	}
+	for _, j := range jobs {
+		require.NoError(t, s.put(ctx, "tenant-a", j))
+	}
+	require.NoError(t, s.cancel(ctx, "tenant-a", jobs["canceled"].ID))
+
+	m := testManager(testConfig(), bucket, logsHandler(t, nil))
+	m.Start()
+	defer m.Stop()
+
+	// The orphaned jobs are failed once the frontend starts.
+	require.Eventually(t, func() bool {
+		j, err := s.get(ctx, "tenant-a", jobs["orphaned"].ID)
+		require.NoError(t, err)
+		return j.Status.finished()
+	}, 5*time.Second, 10*time.Millisecond)
+	m.Stop()
+
+	j, err := s.get(ctx, "tenant-a", jobs["orphaned"].ID)
+	require.NoError(t, err)
+	require.Equal(t, StatusFailed, j.Status)
+	require.Equal(t, errJobOrphaned.Error(), j.Error)
+	j, err = s.get(ctx, "tenant-a", jobs["canceled"].ID)
+	require.NoError(t, err)
+	require.Equal(t, StatusCanceled, j.Status)
+	j, err = s.get(ctx, "tenant-a", jobs["running"].ID)
+	require.NoError(t, err)
+	require.Equal(t, StatusRunning, j.Status)
+
+	// The jobs orphaned later are failed once read.
+	m.now = func() time.Time { return recent.Add(time.Hour) }
+	j, err = m.Get(ctx, "tenant-a", jobs["running"].ID)
+	require.NoError(t, err)
+	require.Equal(t, StatusFailed, j.Status)
+	j, err = s.get(ctx, "tenant-a", jobs["running"].ID)
+	require.NoError(t, err)
+	require.Equal(t, StatusFailed, j.Status)
+}
+
+func TestManager_Submit(t *testing.T) {
+	ctx := context.Background()
+	m := testManager(testConfig(), objstore.NewInMemBucket(), logsHandler(t, nil))
+	defer m.Stop()
+
+	m.limits = fakeLimits{maxRange: 24 * time.Hour}
+	for _, tc := range []struct {
+		query      string
+		start, end time.Time
+		err        string
+	}{
+		{query: `{app="foo"}`, start: testTime, end: testTime.Add(25 * time.Hour), err: "the export range (1d1h) exceeds the limit (1d)"},
+		{query: `count_over_time({app="foo"}[5m])`, start: testTime, end: testTime.Add(time.Hour), err: "only log queries can be exported"},
+		{query: `{app="foo"`, start: testTime, end: testTime.Add(time.Hour), err: "parse error"},
+		{query: `{app="foo"}`, start: testTime, end: testTime, err: "end timestamp must be after start timestamp"},
+	} {
+		_, err := m.Submit(ctx, "tenant-a", tc.query, tc.start, tc.end)
+		require.ErrorContains(t, err, tc.err, tc.query)
+	}
+
+	m.limits = fakeLimits{disabled: true}
+	_, err := m.Submit(ctx, "tenant-a", `{app="foo"}`, testTime, testTime.Add(time.Hour))
+	require.ErrorIs(t, err, ErrDisabled)
+}
+
+func TestHandlers(t *testing.T) {
+	m := testManager(testConfig(), objstore.NewInMemBucket(), logsHandler(t, []testEntry{{`{app="foo"}`, testTime, "1"}}))
+	defer m.Stop()
+
+	router := mux.NewRouter()
+	router.Path("/loki/api/v1/export").Methods("POST").HandlerFunc(m.SubmitHandler)
+	router.Path("/loki/api/v1/export/{id}").Methods("GET").HandlerFunc(m.GetHandler)
+	router.Path("/loki/api/v1/export/{id}").Methods("DELETE").HandlerFunc(m.CancelHandler)
+	do := func(method, url, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req = req.WithContext(user.InjectOrgID(req.Context(), "tenant-a"))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodPost, "/loki/api/v1/export", `query=rate({app="foo"}[1m])&start=1792317600&end=1792321200`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = do(http.MethodPost, "/loki/api/v1/export", `query={app="foo"}&start=1792317600&end=1792321200`)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	require.Contains(t, w.Body.String(), `"query":"{app=\"foo\"}"`)
+
+	jobs, err := m.List(context.Background(), "tenant-a")
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	job := waitFinished(t, m, jobs[0].ID)
+	require.Equal(t, StatusSucceeded, job.Status)
+
+	w = do(http.MethodGet, "/loki/api/v1/export/"+job.ID, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"status":"succeeded"`)
+
+	require.Equal(t, http.StatusBadRequest, do(http.MethodDelete, "/loki/api/v1/export/"+job.ID, "").Code)
+	require.Equal(t, http.StatusNotFound, do(http.MethodGet, "/loki/api/v1/export/unknown", "").Code)
+	require.Equal(t, http.StatusNotFound, do(http.MethodDelete, "/loki/api/v1/export/unknown", "").Code)
+}
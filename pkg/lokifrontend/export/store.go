@@ -0,0 +1,138 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/thanos-io/objstore"
+)
+
+const (
+	// manifestFile holds the status of a job, next to its files.
+	manifestFile = "_job.json"
+	// cancelMarker is written next to the files of a job to cancel it, so that
+	// the query frontend running the job stops it.
+	cancelMarker = "_CANCELED"
+)
+
+// store keeps the status of export jobs in the bucket, next to their files,
+// so that every query frontend can report and cancel the jobs of the others.
+type store struct {
+	bucket objstore.Bucket
+}
+
+// validID returns whether id is the ID of a job, which cannot address the
+// files of another job or tenant.
+func validID(id string) bool {
+	u, err := uuid.Parse(id)
+	return err == nil && u.String() == id
+}
+
+func jobKey(tenant, id, file string) (string, error) {
+	if !validID(id) {
+		return "", ErrJobNotFound
+	}
+	return path.Join(tenant, id, file), nil
+}
+
+// put writes the status of a job of tenant.
+func (s *store) put(ctx context.Context, tenant string, j Job) error {
+	key, err := jobKey(tenant, j.ID, manifestFile)
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+	return s.bucket.Upload(ctx, key, bytes.NewReader(raw))
+}
+
+// get reads the status of a job of tenant.
+func (s *store) get(ctx context.Context, tenant, id string) (Job, error) {
+	key, err := jobKey(tenant, id, manifestFile)
+	if err != nil {
+		return Job{}, err
+	}
+	rc, err := s.bucket.Get(ctx, key)
+	if err != nil {
+		if s.bucket.IsObjNotFoundErr(err) {
+			return Job{}, ErrJobNotFound
+		}
+		return Job{}, err
+	}
+	defer rc.Close()
+	raw, err := io.ReadAll(rc)
+	if err != nil {
+		return Job{}, err
+	}
+	var j Job
+	if err := json.Unmarshal(raw, &j); err != nil {
+		return Job{}, fmt.Errorf("invalid status of export job %s: %w", id, err)
+	}
+	return j, nil
+}
+
+// list returns the IDs of the jobs of tenant. Jobs whose status expired are
+// listed too, as long as their files are kept.
+func (s *store) list(ctx context.Context, tenant string) ([]string, error) {
+	var ids []string
+	err := s.bucket.Iter(ctx, tenant+"/", func(key string) error {
+		id := strings.TrimSuffix(strings.TrimPrefix(key, tenant+"/"), "/")
+		if validID(id) {
+			ids = append(ids, id)
+		}
+		return nil
+	})
+	return ids, err
+}
+
+// tenants returns the tenants with export jobs.
+func (s *store) tenants(ctx context.Context) ([]string, error) {
+	var tenants []string
+	err := s.bucket.Iter(ctx, "", func(key string) error {
+		if strings.HasSuffix(key, "/") {
+			tenants = append(tenants, strings.TrimSuffix(key, "/"))
+		}
+		return nil
+	})
+	return tenants, err
+}
+
+// cancel requests the cancellation of a job of tenant.
+func (s *store) cancel(ctx context.Context, tenant, id string) error {
+	key, err := jobKey(tenant, id, cancelMarker)
+	if err != nil {
+		return err
+	}
+	return s.bucket.Upload(ctx, key, strings.NewReader(""))
+}
+
+// canceled returns whether the cancellation of a job of tenant was requested.
+func (s *store) canceled(ctx context.Context, tenant, id string) (bool, error) {
+	key, err := jobKey(tenant, id, cancelMarker)
+	if err != nil {
+		return false, err
+	}
+	return s.bucket.Exists(ctx, key)
+}
+
+// delete deletes the status of a job of tenant. Its files are kept.
+func (s *store) delete(ctx context.Context, tenant, id string) error {
+	for _, file := range []string{cancelMarker, manifestFile} {
+		key, err := jobKey(tenant, id, file)
+		if err != nil {
+			return err
+		}
+		if err := s.bucket.Delete(ctx, key); err != nil && !s.bucket.IsObjNotFoundErr(err) {
+			return err
+		}
+	}
+	return nil
+}
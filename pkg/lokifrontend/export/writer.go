@@ -0,0 +1,49 @@
+package export
+
+import (
+	"context"
+	"io"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/grafana/loki/v3/pkg/querier/queryrange"
+)
+
+// partitionWriter streams the Parquet file of a partition to the bucket.
+type partitionWriter struct {
+	pw     *io.PipeWriter
+	writer *parquet.GenericWriter[queryrange.LogStreamRowType]
+	done   chan error
+}
+
+func (m *Manager) openPartition(ctx context.Context, name string) *partitionWriter {
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		err := m.bucket.Upload(ctx, name, pr)
+		// Unblock the writer if the upload failed before reading everything.
+		_ = pr.CloseWithError(err)
+		done <- err
+	}()
+	return &partitionWriter{
+		pw:     pw,
+		writer: queryrange.NewLogsParquetWriter(pw),
+		done:   done,
+	}
+}
+
+// close writes the footer of the file and waits for the upload to complete.
+func (w *partitionWriter) close() error {
+	if err := w.writer.Close(); err != nil {
+		w.abort(err)
+		return err
+	}
+	_ = w.pw.Close()
+	return <-w.done
+}
+
+// abort fails the upload with err and waits for it to return.
+func (w *partitionWriter) abort(err error) {
+	_ = w.pw.CloseWithError(err)
+	<-w.done
+}
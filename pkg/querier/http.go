@@ -27,6 +27,7 @@ import (
 
 	"github.com/grafana/loki/v3/pkg/dataobj/metastore"
 	"github.com/grafana/loki/v3/pkg/engine"
+	"github.com/grafana/loki/v3/pkg/iter"
 	"github.com/grafana/loki/v3/pkg/loghttp"
 	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/grafana/loki/v3/pkg/logql"
@@ -61,8 +62,11 @@ type QuerierAPI struct {
 	logger   log.Logger
 }
 
-// NewQuerierAPI returns an instance of the QuerierAPI.
-func NewQuerierAPI(cfg Config, mCfg metastore.Config, querier Querier, limits querier_limits.Limits, store objstore.Bucket, reg prometheus.Registerer, logger log.Logger) *QuerierAPI {
+// NewQuerierAPI returns an instance of the QuerierAPI. ingesterQuerier is used
+// by the next generation query engine to read logs which are not flushed to
+// data objects yet, and may be nil. fragmentRunner executes the plan fragments
+// of the next generation query engine on other queriers, and may be nil.
+func NewQuerierAPI(cfg Config, mCfg metastore.Config, querier Querier, ingesterQuerier *IngesterQuerier, fragmentRunner *engine.FragmentRunner, limits querier_limits.Limits, store objstore.Bucket, reg prometheus.Registerer, logger log.Logger) *QuerierAPI {
 	q := &QuerierAPI{
 		cfg:      cfg,
 		limits:   limits,
@@ -72,7 +76,7 @@ func NewQuerierAPI(cfg Config, mCfg metastore.Config, querier Querier, limits qu
 	}
 
 	if cfg.EngineV2.Enable {
-		q.engineV2 = engine.New(cfg.EngineV2, mCfg, store, limits, reg, logger)
+		q.engineV2 = engine.New(cfg.EngineV2, mCfg, store, NewEngineIngesterQuerier(ingesterQuerier), fragmentRunner, limits, reg, logger)
 	}
 
 	return q
@@ -109,6 +113,28 @@ func (q *QuerierAPI) RangeQueryHandler(ctx context.Context, req *queryrange.Loki
 	return query.Exec(ctx)
 }
 
+// NewEngineIngesterQuerier returns an [engine.IngesterQuerier] merging the logs
+// returned by the ingesters. It returns nil if q is nil.
+func NewEngineIngesterQuerier(q *IngesterQuerier) engine.IngesterQuerier {
+	if q == nil {
+		return nil
+	}
+	return &engineIngesterQuerier{q: q}
+}
+
+type engineIngesterQuerier struct {
+	q *IngesterQuerier
+}
+
+func (q *engineIngesterQuerier) SelectLogs(ctx context.Context, params logql.SelectLogParams) (iter.EntryIterator, error) {
+	iters, err := q.q.SelectLogs(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	// The merge iterator deduplicates the entries of replicated streams.
+	return iter.NewMergeEntryIterator(ctx, iters, params.Direction), nil
+}
+
 func hasDataObjectsAvailable(config Config, start, end time.Time) bool {
 	// Data objects in object storage lag behind 20-30 minutes.
 	// We are generous and only enable v2 engine queries that end earlier than 1DataObjStorageLag ago (default 1h),
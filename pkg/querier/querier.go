@@ -102,6 +102,12 @@ func (cfg *Config) Validate() error {
 			return errors.Wrap(err, "data_obj_storage_start must be a valid date")
 		}
 	}
+	if err := cfg.EngineV2.Distributed.Validate(); err != nil {
+		return errors.Wrap(err, "invalid engine_v2.distributed config")
+	}
+	if err := cfg.EngineV2.DataObjCache.Validate(); err != nil {
+		return errors.Wrap(err, "invalid engine_v2.dataobj_cache config")
+	}
 	return nil
 }
 
@@ -1110,19 +1116,9 @@ func parseEntry(entry push.Entry, lbls *logql_log.LabelsBuilder) (map[string][]s
 		parsed[lbl] = values
 	}
 
-	line := entry.Line
-	parser := "json"
-	jsonParser := logql_log.NewJSONParser(true)
-	_, jsonSuccess := jsonParser.Process(0, []byte(line), lbls)
-	if !jsonSuccess || lbls.HasErr() {
-		lbls.Reset()
-
-		logFmtParser := logql_log.NewLogfmtParser(false, false)
-		parser = "logfmt"
-		_, logfmtSuccess := logFmtParser.Process(0, []byte(line), lbls)
-		if !logfmtSuccess || lbls.HasErr() {
-			return parsed, nil
-		}
+	parser, ok := logql_log.DetectParser([]byte(entry.Line), lbls)
+	if !ok {
+		return parsed, nil
 	}
 
 	parsedLabels := map[string]map[string]struct{}{}
@@ -343,6 +343,12 @@ func (Codec) DecodeRequest(_ context.Context, r *http.Request, _ []string) (quer
 			return nil, httpgrpc.Errorf(http.StatusBadRequest, "%s", err.Error())
 		}
 		return req, nil
+	case QueryEstimateOp:
+		req, err := parseRangeQuery(r)
+		if err != nil {
+			return nil, httpgrpc.Errorf(http.StatusBadRequest, "%s", err.Error())
+		}
+		return &QueryEstimateRequest{LokiRequest: req}, nil
 	case InstantQueryOp:
 		req, err := parseInstantQuery(r)
 		if err != nil {
@@ -716,6 +722,11 @@ func (c Codec) EncodeRequest(ctx context.Context, r queryrangebase.Request) (*ht
 		header.Set(httpreq.LokiDisablePipelineWrappersHeader, disableWrappers)
 	}
 
+	// Add query priority
+	if priority := httpreq.ExtractHeader(ctx, httpreq.LokiQueryPriorityHeader); priority != "" {
+		header.Set(httpreq.LokiQueryPriorityHeader, priority)
+	}
+
 	// Add limits
 	if limits := querylimits.ExtractQueryLimitsContext(ctx); limits != nil {
 		err := querylimits.InjectQueryLimitsHeader(&header, limits)
@@ -1267,6 +1278,8 @@ func decodeResponseProtobuf(r *http.Response, req queryrangebase.Request) (query
 			return concrete.TopkSketches.WithHeaders(headers), nil
 		case *QueryResponse_QuantileSketches:
 			return concrete.QuantileSketches.WithHeaders(headers), nil
+		case *QueryResponse_HyperLogLogs:
+			return concrete.HyperLogLogs.WithHeaders(headers), nil
 		default:
 			return nil, httpgrpc.Errorf(http.StatusInternalServerError, "unsupported response type, got (%T)", resp.Response)
 		}
@@ -1377,6 +1390,8 @@ func encodeResponseJSONTo(version loghttp.Version, res queryrangebase.Response,
 		if err := marshal.WriteDetectedLabelsResponseJSON(response.Response, w); err != nil {
 			return err
 		}
+	case *QueryEstimateResponse:
+		return response.encodeTo(w)
 	default:
 		return httpgrpc.Errorf(http.StatusInternalServerError, "%s", fmt.Sprintf("invalid response format, got (%T)", res))
 	}
@@ -2082,6 +2082,7 @@ var (
 		},
 		"summary": {
 			"bytesProcessedPerSecond": 20,
+			"estimatedCost": 0,
 			"execTime": 22,
 			"linesProcessedPerSecond": 23,
 			"queueTime": 21,
@@ -404,19 +404,9 @@ func parseEntry(entry push.Entry, lbls *logql_log.LabelsBuilder) (map[string][]s
 		parsed[lbl] = values
 	}
 
-	line := entry.Line
-	parser := "json"
-	jsonParser := logql_log.NewJSONParser(true)
-	_, jsonSuccess := jsonParser.Process(0, []byte(line), lblBuilder)
-	if !jsonSuccess || lblBuilder.HasErr() {
-		lblBuilder.Reset()
-
-		logFmtParser := logql_log.NewLogfmtParser(false, false)
-		parser = "logfmt"
-		_, logfmtSuccess := logFmtParser.Process(0, []byte(line), lblBuilder)
-		if !logfmtSuccess || lblBuilder.HasErr() {
-			return parsed, nil
-		}
+	parser, ok := logql_log.DetectParser([]byte(entry.Line), lblBuilder)
+	if !ok {
+		return parsed, nil
 	}
 
 	parsedLabels := map[string]map[string]struct{}{}
@@ -136,6 +136,37 @@ func Test_parseDetectedFields(t *testing.T) {
 			}
 		})
 
+		t.Run("detects xml and csv fields", func(t *testing.T) {
+			lbls := `{service_name="gateway"}`
+			metric, err := parser.ParseMetric(lbls)
+			require.NoError(t, err)
+
+			stream := push.Stream{
+				Labels: lbls,
+				Entries: []push.Entry{
+					{
+						Timestamp: now,
+						Line:      `<request method="GET"><status>200</status></request>`,
+					},
+					{
+						Timestamp: now,
+						Line:      `2024-09-05T15:36:38Z,backup,"disk full, aborting"`,
+					},
+				},
+				Hash: labels.StableHash(metric),
+			}
+
+			df := parseDetectedFields(uint32(15), logqlmodel.Streams([]push.Stream{stream}))
+			for _, expected := range []string{"request_method", "request_status"} {
+				require.Contains(t, df, expected)
+				require.Equal(t, []string{"xml"}, df[expected].parsers)
+			}
+			for _, expected := range []string{"column_1", "column_2", "column_3"} {
+				require.Contains(t, df, expected)
+				require.Equal(t, []string{"csv"}, df[expected].parsers)
+			}
+		})
+
 		t.Run("detects mixed fields", func(t *testing.T) {
 			df := parseDetectedFields(
 				uint32(20),
@@ -127,6 +127,10 @@ func withoutOffset(query logql.DownstreamQuery) (string, time.Time, time.Time) {
 				newStart = newStart.Add(-off)
 
 			}
+		case *syntax.SubqueryAggregationExpr:
+			// offsets within subqueries are relative to the steps of the
+			// subquery, not to the query.
+			return false
 		}
 		return true
 	})
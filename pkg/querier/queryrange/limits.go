@@ -266,9 +266,9 @@ func NewQuerySizeLimiterMiddleware(
 	})
 }
 
-// getBytesReadForRequest returns the number of bytes that would be read for the query in r.
+// getIndexStatsForRequest returns the index stats of the data that would be read for the query in r.
 // Since the query expression may contain multiple stream matchers, this function sums up the
-// bytes that will be read for each stream.
+// stats of each stream.
 // E.g. for the following query:
 //
 //	count_over_time({job="foo"}[5m]) / count_over_time({job="bar"}[5m] offset 10m)
@@ -277,18 +277,18 @@ func NewQuerySizeLimiterMiddleware(
 // individual intervals and offsets
 //   - {job="foo"}
 //   - {job="bar"}
-func (q *querySizeLimiter) getBytesReadForRequest(ctx context.Context, r queryrangebase.Request) (uint64, error) {
-	ctx, sp := tracer.Start(ctx, "querySizeLimiter.getBytesReadForRequest")
+func (q *querySizeLimiter) getIndexStatsForRequest(ctx context.Context, r queryrangebase.Request) (stats.Stats, error) {
+	ctx, sp := tracer.Start(ctx, "querySizeLimiter.getIndexStatsForRequest")
 	defer sp.End()
 
 	expr, err := syntax.ParseExpr(r.GetQuery())
 	if err != nil {
-		return 0, err
+		return stats.Stats{}, err
 	}
 
 	matcherGroups, err := syntax.MatcherGroups(expr)
 	if err != nil {
-		return 0, err
+		return stats.Stats{}, err
 	}
 
 	// TODO: Set concurrency dynamically as in shardResolverForConf?
@@ -296,7 +296,7 @@ func (q *querySizeLimiter) getBytesReadForRequest(ctx context.Context, r queryra
 	const maxConcurrentIndexReq = 10
 	matcherStats, err := getStatsForMatchers(ctx, q.logger, q.statsHandler, model.Time(r.GetStart().UnixMilli()), model.Time(r.GetEnd().UnixMilli()), matcherGroups, maxConcurrentIndexReq, q.maxLookBackPeriod)
 	if err != nil {
-		return 0, err
+		return stats.Stats{}, err
 	}
 
 	combinedStats := stats.MergeStats(matcherStats...)
@@ -313,10 +313,49 @@ func (q *querySizeLimiter) getBytesReadForRequest(ctx context.Context, r queryra
 		)...,
 	)
 
-	return combinedStats.Bytes, nil
+	return combinedStats, nil
+}
+
+type indexStatsContextKey struct{}
+
+// requestIndexStats are the index stats of the data read by a request, shared
+// through the context with the next middlewares, so that they do not request
+// them again.
+type requestIndexStats struct {
+	query      string
+	start, end time.Time
+	stats      stats.Stats
+}
+
+// injectIndexStats returns a context holding the index stats s of the data
+// read by r.
+func injectIndexStats(ctx context.Context, r queryrangebase.Request, s stats.Stats) context.Context {
+	return context.WithValue(ctx, indexStatsContextKey{}, requestIndexStats{
+		query: r.GetQuery(),
+		start: r.GetStart(),
+		end:   r.GetEnd(),
+		stats: s,
+	})
+}
+
+// extractIndexStats returns the index stats of the data read by r held by ctx,
+// if any. They are only returned for the query and the range they were
+// requested for, since middlewares may modify the request.
+func extractIndexStats(ctx context.Context, r queryrangebase.Request) (stats.Stats, bool) {
+	s, ok := ctx.Value(indexStatsContextKey{}).(requestIndexStats)
+	if !ok || s.query != r.GetQuery() || !s.start.Equal(r.GetStart()) || !s.end.Equal(r.GetEnd()) {
+		return stats.Stats{}, false
+	}
+	return s.stats, true
 }
 
 func (q *querySizeLimiter) getSchemaCfg(r queryrangebase.Request) (config.PeriodConfig, error) {
+	return shardingSchemaCfg(q.cfg, r)
+}
+
+// shardingSchemaCfg returns the schema config of the data read by r, including
+// the range and offset of its range vectors.
+func shardingSchemaCfg(cfg []config.PeriodConfig, r queryrangebase.Request) (config.PeriodConfig, error) {
 	maxRVDuration, maxOffset, err := maxRangeVectorAndOffsetDurationFromQueryString(r.GetQuery())
 	if err != nil {
 		return config.PeriodConfig{}, errors.New("failed to get range-vector and offset duration: " + err.Error())
@@ -325,7 +364,7 @@ func (q *querySizeLimiter) getSchemaCfg(r queryrangebase.Request) (config.Period
 	adjustedStart := int64(model.Time(r.GetStart().UnixMilli()).Add(-maxRVDuration).Add(-maxOffset))
 	adjustedEnd := int64(model.Time(r.GetEnd().UnixMilli()).Add(-maxOffset))
 
-	return ShardingConfigs(q.cfg).ValidRange(adjustedStart, adjustedEnd)
+	return ShardingConfigs(cfg).ValidRange(adjustedStart, adjustedEnd)
 }
 
 func (q *querySizeLimiter) guessLimitName() string {
@@ -360,10 +399,12 @@ func (q *querySizeLimiter) Do(ctx context.Context, r queryrangebase.Request) (qu
 
 	limitFuncCapture := func(id string) int { return q.limitFunc(ctx, id) }
 	if maxBytesRead := validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, limitFuncCapture); maxBytesRead > 0 {
-		bytesRead, err := q.getBytesReadForRequest(ctx, r)
+		indexStats, err := q.getIndexStatsForRequest(ctx, r)
 		if err != nil {
 			return nil, httpgrpc.Errorf(http.StatusInternalServerError, "Failed to get bytes read stats for query: %s", err.Error())
 		}
+		ctx = injectIndexStats(ctx, r, indexStats)
+		bytesRead := indexStats.Bytes
 
 		statsBytesStr := humanize.IBytes(bytesRead)
 		maxBytesReadStr := humanize.IBytes(uint64(maxBytesRead))
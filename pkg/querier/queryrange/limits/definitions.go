@@ -33,9 +33,16 @@ type Limits interface {
 	RequiredNumberLabels(context.Context, string) int
 	MaxQueryBytesRead(context.Context, string) int
 	MaxQuerierBytesRead(context.Context, string) int
+	// QueryCostLowPriorityThreshold returns the estimated cost from which
+	// queries are queued with a low priority.
+	QueryCostLowPriorityThreshold(context.Context, string) int
 	MaxStatsCacheFreshness(context.Context, string) time.Duration
 	MaxMetadataCacheFreshness(context.Context, string) time.Duration
 	VolumeEnabled(string) bool
+	// ExportEnabled and MaxExportRange are the limits of the export jobs
+	// of the query frontend.
+	ExportEnabled(string) bool
+	MaxExportRange(string) time.Duration
 
 	ShardAggregations(string) []string
 }
@@ -0,0 +1,51 @@
+package queryrange
+
+import (
+	"context"
+
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/querier/plan"
+	"github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
+)
+
+// LookupTablePinner pins the lookup stages of a query to the latest version of
+// their table.
+type LookupTablePinner interface {
+	PinLookupTables(ctx context.Context, expr syntax.Expr) (syntax.Expr, error)
+}
+
+// NewLookupTablesMiddleware rewrites queries with lookup stages so that they
+// use the latest version of their tables. It must run before the results
+// caches: the version is part of the query and therefore of the cache key, so
+// results computed with a previous version of a table are not reused.
+func NewLookupTablesMiddleware(pinner LookupTablePinner) queryrangebase.Middleware {
+	return queryrangebase.MiddlewareFunc(func(next queryrangebase.Handler) queryrangebase.Handler {
+		return queryrangebase.HandlerFunc(func(ctx context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+			switch req := r.(type) {
+			case *LokiRequest:
+				if req.Plan != nil && syntax.HasLookupStage(req.Plan.AST) {
+					expr, err := pinner.PinLookupTables(ctx, req.Plan.AST)
+					if err != nil {
+						return nil, err
+					}
+					pinned := *req
+					pinned.Query = expr.String()
+					pinned.Plan = &plan.QueryPlan{AST: expr}
+					r = &pinned
+				}
+			case *LokiInstantRequest:
+				if req.Plan != nil && syntax.HasLookupStage(req.Plan.AST) {
+					expr, err := pinner.PinLookupTables(ctx, req.Plan.AST)
+					if err != nil {
+						return nil, err
+					}
+					pinned := *req
+					pinned.Query = expr.String()
+					pinned.Plan = &plan.QueryPlan{AST: expr}
+					r = &pinned
+				}
+			}
+			return next.Do(ctx, r)
+		})
+	})
+}
@@ -0,0 +1,65 @@
+package queryrange
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/querier/plan"
+	"github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
+)
+
+type lookupTablePinnerFunc func(ctx context.Context, expr syntax.Expr) (syntax.Expr, error)
+
+func (f lookupTablePinnerFunc) PinLookupTables(ctx context.Context, expr syntax.Expr) (syntax.Expr, error) {
+	return f(ctx, expr)
+}
+
+func TestLookupTablesMiddleware(t *testing.T) {
+	pinner := lookupTablePinnerFunc(func(_ context.Context, expr syntax.Expr) (syntax.Expr, error) {
+		pinned := syntax.MustClone(expr)
+		pinned.Walk(func(e syntax.Expr) bool {
+			if l, ok := e.(*syntax.LookupExpr); ok {
+				l.Version = "0f3a0f3a0f3a0f3a"
+			}
+			return true
+		})
+		return pinned, nil
+	})
+
+	var received queryrangebase.Request
+	next := queryrangebase.HandlerFunc(func(_ context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+		received = r
+		return nil, nil
+	})
+	handler := NewLookupTablesMiddleware(pinner).Wrap(next)
+
+	for _, tc := range []struct {
+		query, expected string
+	}{
+		{`{app="foo"} | json`, `{app="foo"} | json`},
+		{`{app="foo"} | json | lookup customers on customer_id`, `{app="foo"} | json | lookup customers on customer_id version "0f3a0f3a0f3a0f3a"`},
+		{`sum by (tier) (rate({app="foo"} | lookup customers on customer_id [5m]))`, `sum by (tier)(rate({app="foo"} | lookup customers on customer_id version "0f3a0f3a0f3a0f3a"[5m]))`},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			for _, req := range []queryrangebase.Request{
+				&LokiRequest{Query: tc.query, StartTs: time.Unix(0, 0), EndTs: time.Unix(3600, 0), Plan: &plan.QueryPlan{AST: syntax.MustParseExpr(tc.query)}},
+				&LokiInstantRequest{Query: tc.query, TimeTs: time.Unix(3600, 0), Plan: &plan.QueryPlan{AST: syntax.MustParseExpr(tc.query)}},
+			} {
+				_, err := handler.Do(context.Background(), req)
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, received.GetQuery())
+				switch r := received.(type) {
+				case *LokiRequest:
+					require.Equal(t, tc.expected, r.Plan.AST.String())
+				case *LokiInstantRequest:
+					require.Equal(t, tc.expected, r.Plan.AST.String())
+				}
+				require.Equal(t, tc.query, req.GetQuery(), "the original request is not modified")
+			}
+		})
+	}
+}
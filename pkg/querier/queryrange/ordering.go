@@ -1,6 +1,7 @@
 package queryrange
 
 import (
+	"slices"
 	"sort"
 
 	"github.com/grafana/loki/v3/pkg/logproto"
@@ -47,11 +48,65 @@ func (a byDir) merge() []logproto.Entry {
 
 	sort.Sort(a)
 	for _, m := range a.markers {
+		if len(result) > 0 && len(m) > 0 && !a.before(result[len(result)-1], m[0]) {
+			// The responses of queries with a context stage overlap, as the
+			// context of a match may be on the other side of a split boundary.
+			result = mergeOverlapping(result, m, a.direction)
+			continue
+		}
 		result = append(result, m...)
 	}
 	return result
 }
 
+// before returns whether x comes before y in the direction of the query.
+func (a byDir) before(x, y logproto.Entry) bool {
+	if a.direction == logproto.BACKWARD {
+		return x.Timestamp.After(y.Timestamp)
+	}
+	return x.Timestamp.Before(y.Timestamp)
+}
+
+// mergeOverlapping merges the ordered entries x and y, skipping the entries of
+// y which are also in x.
+func mergeOverlapping(x, y []logproto.Entry, direction logproto.Direction) []logproto.Entry {
+	cmp := func(a, b logproto.Entry) int {
+		if direction == logproto.BACKWARD {
+			return b.Timestamp.Compare(a.Timestamp)
+		}
+		return a.Timestamp.Compare(b.Timestamp)
+	}
+
+	result := make([]logproto.Entry, 0, len(x)+len(y))
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch c := cmp(x[i], y[j]); {
+		case c < 0:
+			result = append(result, x[i])
+			i++
+		case c > 0:
+			result = append(result, y[j])
+			j++
+		default:
+			// Append the entries of x at this timestamp, then the ones of y
+			// which are not among them.
+			k := i
+			for k < len(x) && cmp(x[k], y[j]) == 0 {
+				k++
+			}
+			result = append(result, x[i:k]...)
+			for ; j < len(y) && cmp(x[i], y[j]) == 0; j++ {
+				if !slices.ContainsFunc(x[i:k], func(e logproto.Entry) bool { return e.Line == y[j].Line }) {
+					result = append(result, y[j])
+				}
+			}
+			i = k
+		}
+	}
+	result = append(result, x[i:]...)
+	return append(result, y[j:]...)
+}
+
 // priorityqueue is used for extracting a limited # of entries from a set of sorted streams
 type priorityqueue struct {
 	streams   []*logproto.Stream
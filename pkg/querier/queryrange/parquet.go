@@ -12,6 +12,7 @@ import (
 
 	serverutil "github.com/grafana/loki/v3/pkg/util/server"
 
+	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
 )
 
@@ -83,10 +84,23 @@ func encodeMetricsParquetTo(response *LokiPromResponse, w io.Writer) error {
 }
 
 func encodeLogsParquetTo(response *LokiResponse, w io.Writer) error {
+	writer := NewLogsParquetWriter(w)
+	if err := WriteLogsParquet(writer, response.Data.Result); err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// NewLogsParquetWriter returns a writer encoding log entries as Parquet rows
+// to w. The writer must be closed to flush the rows.
+func NewLogsParquetWriter(w io.Writer) *parquet.GenericWriter[LogStreamRowType] {
 	schema := parquet.SchemaOf(new(LogStreamRowType))
-	writer := parquet.NewGenericWriter[LogStreamRowType](w, schema)
+	return parquet.NewGenericWriter[LogStreamRowType](w, schema)
+}
 
-	for _, stream := range response.Data.Result {
+// WriteLogsParquet writes the entries of streams as rows to writer.
+func WriteLogsParquet(writer *parquet.GenericWriter[LogStreamRowType], streams []logproto.Stream) error {
+	for _, stream := range streams {
 		lbls, err := parser.ParseMetric(stream.Labels)
 		if err != nil {
 			return err
@@ -107,6 +121,5 @@ func encodeLogsParquetTo(response *LokiResponse, w io.Writer) error {
 			}
 		}
 	}
-
-	return writer.Close()
+	return nil
 }
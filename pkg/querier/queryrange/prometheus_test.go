@@ -183,6 +183,7 @@ var emptyStats = `"stats": {
 	},
 	"summary": {
 		"bytesProcessedPerSecond": 0,
+		"estimatedCost": 0,
 		"execTime": 0,
 		"linesProcessedPerSecond": 0,
 		"queueTime": 0,
@@ -0,0 +1,356 @@
+package queryrange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/tenant"
+	regexsyn "github.com/grafana/regexp/syntax"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/v3/pkg/logql"
+	logqllog "github.com/grafana/loki/v3/pkg/logql/log"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	logqlstats "github.com/grafana/loki/v3/pkg/logqlmodel/stats"
+	"github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
+	"github.com/grafana/loki/v3/pkg/storage/config"
+	"github.com/grafana/loki/v3/pkg/storage/stores/index/stats"
+	"github.com/grafana/loki/v3/pkg/storage/stores/shipper/indexshipper/tsdb/sharding"
+	"github.com/grafana/loki/v3/pkg/storage/types"
+	"github.com/grafana/loki/v3/pkg/util/httpreq"
+	"github.com/grafana/loki/v3/pkg/util/spanlogger"
+	"github.com/grafana/loki/v3/pkg/util/validation"
+)
+
+// The weights of the cost model. The cost of a query is expressed in bytes:
+// the bytes the query reads according to the index, increased by the work
+// spent on each of these bytes and by the overhead of the shards and range of
+// the query.
+const (
+	// costPerShard is the overhead of scheduling a shard and merging its
+	// results.
+	costPerShard = 16 << 20
+	// costPerRangeHour is the overhead of each hour of the range of the query,
+	// which yields more splits and steps.
+	costPerRangeHour = 1 << 20
+	// costPerParserStage is the extra work of each parser stage, relative to
+	// the bytes read.
+	costPerParserStage = 0.5
+	// costPerRegexInst is the extra work of each instruction of the regular
+	// expressions matched against lines and labels, relative to the bytes read.
+	costPerRegexInst = 0.02
+)
+
+// QueryCost is the estimated cost of a query, computed before its execution.
+type QueryCost struct {
+	// Bytes is the number of bytes read by the query according to the index.
+	Bytes uint64 `json:"bytes"`
+	// Shards is the number of shards the query is split into.
+	Shards int `json:"shards"`
+	// Range is the range of data read by the query, including the range of
+	// its range vectors.
+	Range model.Duration `json:"range"`
+	// ParserStages is the number of parser stages of the query.
+	ParserStages int `json:"parserStages"`
+	// RegexComplexity is the number of instructions of the regular
+	// expressions of the query matched against lines and labels.
+	RegexComplexity int `json:"regexComplexity"`
+	// Cost is the cost of the query, in bytes, combining all of the above.
+	Cost int64 `json:"cost"`
+}
+
+func (c QueryCost) total() int64 {
+	work := 1 + float64(c.ParserStages)*costPerParserStage + float64(c.RegexComplexity)*costPerRegexInst
+	return int64(float64(c.Bytes)*work) +
+		int64(c.Shards)*costPerShard +
+		int64(time.Duration(c.Range).Hours()*costPerRangeHour)
+}
+
+// parserStages returns the number of parser stages of expr.
+func parserStages(expr syntax.Expr) int {
+	var n int
+	expr.Walk(func(e syntax.Expr) bool {
+		switch e.(type) {
+		case *syntax.LogfmtParserExpr, *syntax.LineParserExpr, *syntax.JSONExpressionParserExpr,
+			*syntax.LogfmtExpressionParserExpr, *syntax.XMLExpressionParserExpr, *syntax.CSVParserExpr:
+			n++
+		}
+		return true
+	})
+	return n
+}
+
+// regexComplexity returns the number of instructions of the regular
+// expressions of expr matched against each line: line filters, label filters
+// and regexp parsers. The matchers of stream selectors are resolved by the
+// index and are not counted.
+func regexComplexity(expr syntax.Expr) int {
+	var n int
+	expr.Walk(func(e syntax.Expr) bool {
+		switch e := e.(type) {
+		case *syntax.LineFilterExpr:
+			if e.Ty == logqllog.LineMatchRegexp || e.Ty == logqllog.LineMatchNotRegexp {
+				n += regexInsts(e.Match)
+			}
+		case *syntax.LineParserExpr:
+			if e.Op == syntax.OpParserTypeRegexp {
+				n += regexInsts(e.Param)
+			}
+		case *syntax.LabelFilterExpr:
+			n += labelFilterRegexComplexity(e.LabelFilterer)
+		}
+		return true
+	})
+	return n
+}
+
+func labelFilterRegexComplexity(f logqllog.LabelFilterer) int {
+	var m *labels.Matcher
+	switch f := f.(type) {
+	case *logqllog.BinaryLabelFilter:
+		return labelFilterRegexComplexity(f.Left) + labelFilterRegexComplexity(f.Right)
+	case *logqllog.StringLabelFilter:
+		m = f.Matcher
+	case *logqllog.LineFilterLabelFilter:
+		m = f.Matcher
+	default:
+		return 0
+	}
+	if m.Type != labels.MatchRegexp && m.Type != labels.MatchNotRegexp {
+		return 0
+	}
+	return regexInsts(m.Value)
+}
+
+// regexInsts returns the number of instructions of the program compiled from
+// the regular expression re, or 0 if re is invalid.
+func regexInsts(re string) int {
+	parsed, err := regexsyn.Parse(re, regexsyn.Perl)
+	if err != nil {
+		return 0
+	}
+	prog, err := regexsyn.Compile(parsed.Simplify())
+	if err != nil {
+		return 0
+	}
+	return len(prog.Inst)
+}
+
+// queryCostEstimator estimates the cost of queries from the index stats of
+// their matchers.
+type queryCostEstimator struct {
+	logger            log.Logger
+	statsHandler      queryrangebase.Handler
+	limits            Limits
+	cfg               []config.PeriodConfig
+	maxLookBackPeriod time.Duration
+}
+
+// estimable returns whether the cost of r can be estimated, which requires
+// the index stats of TSDB.
+func (e *queryCostEstimator) estimable(r queryrangebase.Request) (bool, error) {
+	schemaCfg, err := shardingSchemaCfg(e.cfg, r)
+	if err != nil {
+		return false, err
+	}
+	return schemaCfg.IndexType == types.TSDBType, nil
+}
+
+func (e *queryCostEstimator) Estimate(ctx context.Context, r queryrangebase.Request) (QueryCost, error) {
+	ctx, sp := tracer.Start(ctx, "queryCostEstimator.Estimate")
+	defer sp.End()
+
+	expr, err := syntax.ParseExpr(r.GetQuery())
+	if err != nil {
+		return QueryCost{}, err
+	}
+
+	// The index stats are only requested if the query size limiter did not.
+	combinedStats, ok := extractIndexStats(ctx, r)
+	if !ok {
+		matcherGroups, err := syntax.MatcherGroups(expr)
+		if err != nil {
+			return QueryCost{}, err
+		}
+
+		const maxConcurrentIndexReq = 10
+		matcherStats, err := getStatsForMatchers(ctx, e.logger, e.statsHandler, model.Time(r.GetStart().UnixMilli()), model.Time(r.GetEnd().UnixMilli()), matcherGroups, maxConcurrentIndexReq, e.maxLookBackPeriod)
+		if err != nil {
+			return QueryCost{}, err
+		}
+		combinedStats = stats.MergeStats(matcherStats...)
+	}
+
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return QueryCost{}, err
+	}
+	maxBytesPerShard := validation.SmallestPositiveIntPerTenant(tenantIDs, e.limits.TSDBMaxBytesPerShard)
+
+	maxRVDuration, _ := maxRangeVectorAndOffsetDuration(expr)
+	cost := QueryCost{
+		Bytes:           combinedStats.Bytes,
+		Shards:          sharding.GuessShardFactor(combinedStats.Bytes, uint64(maxBytesPerShard), 0),
+		Range:           model.Duration(r.GetEnd().Sub(r.GetStart()) + maxRVDuration),
+		ParserStages:    parserStages(expr),
+		RegexComplexity: regexComplexity(expr),
+	}
+	cost.Cost = cost.total()
+	return cost, nil
+}
+
+type queryCostMiddleware struct {
+	estimator *queryCostEstimator
+	next      queryrangebase.Handler
+}
+
+// NewQueryCostMiddleware creates a new Middleware estimating the cost of the
+// queries, reusing the index stats of the query size limiter if it ran before.
+// Without a low priority threshold, the cost is only estimated from these
+// stats, so that it requests no index stats. The estimated cost is added to
+// the statistics of the query, and queries costing at least the low priority
+// threshold of their tenants are queued in the low priority lane of the
+// scheduler instead of being rejected.
+func NewQueryCostMiddleware(
+	cfg []config.PeriodConfig,
+	engineOpts logql.EngineOpts,
+	logger log.Logger,
+	limits Limits,
+	statsHandler queryrangebase.Handler,
+) queryrangebase.Middleware {
+	return queryrangebase.MiddlewareFunc(func(next queryrangebase.Handler) queryrangebase.Handler {
+		return &queryCostMiddleware{
+			estimator: &queryCostEstimator{
+				logger:            logger,
+				statsHandler:      statsHandler,
+				limits:            limits,
+				cfg:               cfg,
+				maxLookBackPeriod: engineOpts.MaxLookBackPeriod,
+			},
+			next: next,
+		}
+	})
+}
+
+func (q *queryCostMiddleware) Do(ctx context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+	log := spanlogger.FromContext(ctx, q.estimator.logger)
+
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return nil, httpgrpc.Errorf(http.StatusBadRequest, "%s", err.Error())
+	}
+
+	threshold := validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, func(id string) int {
+		return q.estimator.limits.QueryCostLowPriorityThreshold(ctx, id)
+	})
+	if _, ok := extractIndexStats(ctx, r); threshold == 0 && !ok {
+		return q.next.Do(ctx, r)
+	}
+
+	if ok, err := q.estimator.estimable(r); err != nil || !ok {
+		if err != nil {
+			level.Warn(log).Log("msg", "failed to get schema config, not estimating query cost", "err", err)
+		}
+		return q.next.Do(ctx, r)
+	}
+
+	cost, err := q.estimator.Estimate(ctx, r)
+	if err != nil {
+		// The query is queued with the regular priority rather than failed.
+		level.Warn(log).Log("msg", "failed to estimate query cost", "err", err)
+		return q.next.Do(ctx, r)
+	}
+	logqlstats.FromContext(ctx).SetEstimatedCost(cost.Cost)
+
+	if threshold > 0 && cost.Cost >= int64(threshold) {
+		level.Info(log).Log("msg", "queueing query with a low priority", "estimated_cost", humanize.IBytes(uint64(cost.Cost)), "threshold", humanize.IBytes(uint64(threshold)))
+		ctx = httpreq.InjectHeader(ctx, httpreq.LokiQueryPriorityHeader, httpreq.LowQueryPriority)
+	}
+	return q.next.Do(ctx, r)
+}
+
+// QueryEstimateRequest is a request for the estimated cost of a query,
+// without executing it.
+type QueryEstimateRequest struct {
+	*LokiRequest
+}
+
+// QueryEstimateResponse is the estimated cost of a query.
+type QueryEstimateResponse struct {
+	Cost    QueryCost
+	headers []queryrangebase.PrometheusResponseHeader
+}
+
+var _ queryrangebase.Response = &QueryEstimateResponse{}
+
+func (r *QueryEstimateResponse) GetHeaders() []*queryrangebase.PrometheusResponseHeader {
+	return convertPrometheusResponseHeadersToPointers(r.headers)
+}
+
+func (r *QueryEstimateResponse) WithHeaders(h []queryrangebase.PrometheusResponseHeader) queryrangebase.Response {
+	r.headers = h
+	return r
+}
+
+func (r *QueryEstimateResponse) SetHeader(name, value string) {
+	r.headers = setHeader(r.headers, name, value)
+}
+
+// Implement proto.Message
+func (r *QueryEstimateResponse) Reset()         {}
+func (r *QueryEstimateResponse) String() string { return fmt.Sprintf("%+v", r.Cost) }
+func (r *QueryEstimateResponse) ProtoMessage()  {}
+
+func (r *QueryEstimateResponse) encodeTo(w io.Writer) error {
+	return json.NewEncoder(w).Encode(struct {
+		Status string    `json:"status"`
+		Data   QueryCost `json:"data"`
+	}{
+		Status: "success",
+		Data:   r.Cost,
+	})
+}
+
+// NewQueryEstimateTripperware creates a new frontend tripperware answering
+// query estimate requests.
+func NewQueryEstimateTripperware(
+	cfg []config.PeriodConfig,
+	engineOpts logql.EngineOpts,
+	logger log.Logger,
+	limits Limits,
+	indexStatsTripperware queryrangebase.Middleware,
+) queryrangebase.Middleware {
+	return queryrangebase.MiddlewareFunc(func(next queryrangebase.Handler) queryrangebase.Handler {
+		estimator := &queryCostEstimator{
+			logger:            logger,
+			statsHandler:      indexStatsTripperware.Wrap(next),
+			limits:            limits,
+			cfg:               cfg,
+			maxLookBackPeriod: engineOpts.MaxLookBackPeriod,
+		}
+		return queryrangebase.HandlerFunc(func(ctx context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+			ok, err := estimator.estimable(r)
+			if err != nil {
+				return nil, httpgrpc.Errorf(http.StatusBadRequest, "%s", err.Error())
+			}
+			if !ok {
+				return nil, httpgrpc.Errorf(http.StatusBadRequest, "query cost can only be estimated with the TSDB index")
+			}
+
+			cost, err := estimator.Estimate(ctx, r)
+			if err != nil {
+				return nil, httpgrpc.Errorf(http.StatusInternalServerError, "failed to estimate query cost: %s", err.Error())
+			}
+			return &QueryEstimateResponse{Cost: cost}, nil
+		})
+	})
+}
@@ -0,0 +1,152 @@
+package queryrange
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/v3/pkg/loghttp"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
+	"github.com/grafana/loki/v3/pkg/querier/plan"
+	"github.com/grafana/loki/v3/pkg/querier/queryrange/queryrangebase"
+	"github.com/grafana/loki/v3/pkg/storage/config"
+	"github.com/grafana/loki/v3/pkg/storage/types"
+	"github.com/grafana/loki/v3/pkg/util/httpreq"
+	util_log "github.com/grafana/loki/v3/pkg/util/log"
+)
+
+func TestQueryCost(t *testing.T) {
+	for _, tc := range []struct {
+		query                 string
+		parserStages, regexes int
+	}{
+		{query: `{app="foo"} |= "foo"`},
+		{query: `{app=~"foo|bar"} |= "foo"`},
+		{query: `{app="foo"} | json | logfmt`, parserStages: 2},
+		{query: `{app="foo"} |~ "fo+"`, regexes: 5},
+		{query: `{app="foo"} | logfmt | level=~"warn|error" or status!~"2.."`, parserStages: 1, regexes: 17},
+		{query: `sum(rate({app="foo"} | regexp "(?P<status>\\d+)" [5m]))`, parserStages: 1, regexes: 6},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			expr := syntax.MustParseExpr(tc.query)
+			require.Equal(t, tc.parserStages, parserStages(expr))
+			require.Equal(t, tc.regexes, regexComplexity(expr))
+		})
+	}
+
+	cost := QueryCost{Bytes: 1 << 30, Shards: 4, Range: model.Duration(2 * time.Hour), ParserStages: 2, RegexComplexity: 50}
+	require.Equal(t, int64(3<<30+4*costPerShard+2*costPerRangeHour), cost.total())
+}
+
+func TestQueryCostMiddleware(t *testing.T) {
+	const statsBytes = 100 << 20
+
+	schemas := []config.PeriodConfig{
+		{
+			From:      config.DayTime{Time: model.TimeFromUnix(testTime.Add(-48 * time.Hour).Unix())},
+			IndexType: types.TSDBType,
+		},
+	}
+	query := `{app="foo"} | json |= "foo"`
+	expectedCost := QueryCost{
+		Bytes:        statsBytes,
+		Range:        model.Duration(time.Hour),
+		ParserStages: 1,
+	}
+	expectedCost.Cost = expectedCost.total()
+
+	for _, tc := range []struct {
+		desc         string
+		threshold    int
+		maxBytesRead int
+		estimated    bool
+		lowPriority  bool
+	}{
+		{desc: "disabled"},
+		{desc: "no threshold", maxBytesRead: statsBytes, estimated: true},
+		{desc: "below threshold", threshold: int(expectedCost.Cost) + 1, estimated: true},
+		{desc: "above threshold", threshold: int(expectedCost.Cost), estimated: true, lowPriority: true},
+		{desc: "stats of the query size limiter", threshold: int(expectedCost.Cost), maxBytesRead: statsBytes, estimated: true, lowPriority: true},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			statsHits, statsHandler := indexStatsResult(logproto.IndexStatsResponse{Bytes: statsBytes})
+
+			var priority string
+			next := queryrangebase.HandlerFunc(func(ctx context.Context, _ queryrangebase.Request) (queryrangebase.Response, error) {
+				priority = httpreq.ExtractHeader(ctx, httpreq.LokiQueryPriorityHeader)
+				return &LokiResponse{}, nil
+			})
+
+			statsCtx, ctx := stats.NewContext(user.InjectOrgID(context.Background(), "foo"))
+			limits := fakeLimits{queryCostLowPriority: tc.threshold, maxQueryBytesRead: tc.maxBytesRead}
+			_, err := queryrangebase.MergeMiddlewares(
+				NewQuerySizeLimiterMiddleware(schemas, testEngineOpts, util_log.Logger, limits, statsHandler),
+				NewQueryCostMiddleware(schemas, testEngineOpts, util_log.Logger, limits, statsHandler),
+			).Wrap(next).Do(ctx, &LokiRequest{
+				Query:   query,
+				StartTs: testTime.Add(-time.Hour),
+				EndTs:   testTime,
+				Plan:    &plan.QueryPlan{AST: syntax.MustParseExpr(query)},
+			})
+			require.NoError(t, err)
+
+			// The cost is estimated from the index stats of the query size
+			// limiter if it requested them, and otherwise only with a
+			// threshold.
+			if tc.estimated {
+				require.Equal(t, 1, *statsHits)
+				require.Equal(t, expectedCost.Cost, statsCtx.Result(0, 0, 0).Summary.EstimatedCost)
+			} else {
+				require.Equal(t, 0, *statsHits)
+				require.Zero(t, statsCtx.Result(0, 0, 0).Summary.EstimatedCost)
+			}
+			if tc.lowPriority {
+				require.Equal(t, httpreq.LowQueryPriority, priority)
+			} else {
+				require.Empty(t, priority)
+			}
+		})
+	}
+}
+
+func TestQueryEstimateTripperware(t *testing.T) {
+	schemas := []config.PeriodConfig{
+		{
+			From:      config.DayTime{Time: model.TimeFromUnix(0)},
+			IndexType: types.TSDBType,
+		},
+	}
+	_, statsHandler := indexStatsResult(logproto.IndexStatsResponse{Bytes: 1 << 20})
+	indexStatsTripperware := queryrangebase.MiddlewareFunc(func(queryrangebase.Handler) queryrangebase.Handler {
+		return statsHandler
+	})
+	next := queryrangebase.HandlerFunc(func(context.Context, queryrangebase.Request) (queryrangebase.Response, error) {
+		t.Error("the query is not executed")
+		return nil, nil
+	})
+	handler := NewQueryEstimateTripperware(schemas, testEngineOpts, util_log.Logger, fakeLimits{}, indexStatsTripperware).Wrap(next)
+
+	httpReq, err := http.NewRequest(http.MethodGet, `/loki/api/v1/query_estimate?query={app="foo"}|~"fo%2B"&start=0&end=3600000000000`, nil)
+	require.NoError(t, err)
+	req, err := DefaultCodec.DecodeRequest(context.Background(), httpReq, nil)
+	require.NoError(t, err)
+	require.IsType(t, &QueryEstimateRequest{}, req)
+
+	resp, err := handler.Do(user.InjectOrgID(context.Background(), "foo"), req)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, encodeResponseJSONTo(loghttp.VersionV1, resp, &buf, nil))
+	require.JSONEq(t, `{
+		"status": "success",
+		"data": {"bytes": 1048576, "shards": 0, "range": "1h", "parserStages": 0, "regexComplexity": 5, "cost": 2202009}
+	}`, buf.String())
+}
@@ -48,6 +48,7 @@ const (
 type Config struct {
 	base.Config                  `yaml:",inline"`
 	Transformer                  UserIDTransformer        `yaml:"-"`
+	LookupTables                 LookupTablePinner        `yaml:"-"`
 	CacheIndexStatsResults       bool                     `yaml:"cache_index_stats_results"`
 	StatsCacheConfig             IndexStatsCacheConfig    `yaml:"index_stats_results_cache" doc:"description=If a cache config is not specified and cache_index_stats_results is true, the config for the results cache is used."`
 	CacheVolumeResults           bool                     `yaml:"cache_volume_results"`
@@ -289,6 +290,8 @@ func NewMiddleware(
 		return nil, nil, err
 	}
 
+	queryEstimateTripperware := NewQueryEstimateTripperware(schema.Configs, v1EngineOpts, log, limits, indexStatsTripperware)
+
 	return base.MiddlewareFunc(func(next base.Handler) base.Handler {
 		var (
 			metricRT         = metricsTripperware.Wrap(next)
@@ -302,9 +305,10 @@ func NewMiddleware(
 			detectedFieldsRT = detectedFieldsTripperware.Wrap(next)
 			detectedLabelsRT = detectedLabelsTripperware.Wrap(next)
 			patternRT        = patternTripperware.Wrap(next)
+			estimateRT       = queryEstimateTripperware.Wrap(next)
 		)
 
-		return newRoundTripper(
+		rt := newRoundTripper(
 			log,
 			next,
 			limitedRT,
@@ -318,8 +322,13 @@ func NewMiddleware(
 			detectedFieldsRT,
 			detectedLabelsRT,
 			patternRT,
+			estimateRT,
 			limits,
 		)
+		if cfg.LookupTables != nil {
+			return NewLookupTablesMiddleware(cfg.LookupTables).Wrap(rt)
+		}
+		return rt
 	}), StopperWrapper{resultsCache, statsCache, volumeCache}, nil
 }
 
@@ -373,7 +382,7 @@ func NewDetectedLabelsCardinalityFilter(rt base.Handler) base.Handler {
 type roundTripper struct {
 	logger log.Logger
 
-	next, limited, log, metric, series, labels, instantMetric, indexStats, seriesVolume, detectedFields, detectedLabels, pattern base.Handler
+	next, limited, log, metric, series, labels, instantMetric, indexStats, seriesVolume, detectedFields, detectedLabels, pattern, estimate base.Handler
 
 	limits Limits
 }
@@ -381,7 +390,7 @@ type roundTripper struct {
 // newRoundTripper creates a new queryrange roundtripper
 func newRoundTripper(
 	logger log.Logger,
-	next, limited, log, metric, series, labels, instantMetric, indexStats, seriesVolume, detectedFields, detectedLabels, pattern base.Handler,
+	next, limited, log, metric, series, labels, instantMetric, indexStats, seriesVolume, detectedFields, detectedLabels, pattern, estimate base.Handler,
 	limits Limits,
 ) roundTripper {
 	return roundTripper{
@@ -398,6 +407,7 @@ func newRoundTripper(
 		detectedFields: detectedFields,
 		detectedLabels: detectedLabels,
 		pattern:        pattern,
+		estimate:       estimate,
 		next:           next,
 	}
 }
@@ -575,6 +585,13 @@ func (r roundTripper) Do(ctx context.Context, req base.Request) (base.Response,
 			}
 		}
 		return r.pattern.Do(ctx, req)
+	case *QueryEstimateRequest:
+		logQueryExecution(ctx, logger,
+			"type", "estimate",
+			"query", op.Query,
+			"length", op.EndTs.Sub(op.StartTs),
+		)
+		return r.estimate.Do(ctx, req)
 	default:
 		return r.next.Do(ctx, req)
 	}
@@ -602,6 +619,7 @@ func transformRegexQuery(req *http.Request, expr syntax.LogSelectorExpr) (syntax
 const (
 	InstantQueryOp   = "instant_query"
 	QueryRangeOp     = "query_range"
+	QueryEstimateOp  = "query_estimate"
 	SeriesOp         = "series"
 	LabelNamesOp     = "labels"
 	IndexStatsOp     = "index_stats"
@@ -619,6 +637,8 @@ func getOperation(path string) string {
 		return QueryRangeOp
 	case strings.HasSuffix(path, "/query"):
 		return InstantQueryOp
+	case strings.HasSuffix(path, "/query_estimate"):
+		return QueryEstimateOp
 	case strings.HasSuffix(path, "/series"):
 		return SeriesOp
 	case strings.HasSuffix(path, "/labels") || strings.HasSuffix(path, "/label"):
@@ -666,6 +686,7 @@ func NewLogFilterTripperware(cfg Config, engineOpts logql.EngineOpts, v2EngineCf
 			StatsCollectorMiddleware(),
 			NewLimitsMiddleware(limits),
 			NewQuerySizeLimiterMiddleware(schema.Configs, engineOpts, log, limits, statsHandler),
+			NewQueryCostMiddleware(schema.Configs, engineOpts, log, limits, statsHandler),
 		}
 
 		// Splitting, sharding, caching and retry middlwares are not added to v2 engine splits
@@ -1031,6 +1052,7 @@ func NewMetricTripperware(cfg Config, engineOpts logql.EngineOpts, v2EngineCfg e
 		queryRangeMiddleware = append(
 			queryRangeMiddleware,
 			NewQuerySizeLimiterMiddleware(schema.Configs, engineOpts, log, limits, statsHandler),
+			NewQueryCostMiddleware(schema.Configs, engineOpts, log, limits, statsHandler),
 		)
 
 		// Splitting, sharding, caching and retry middlwares are not added to v2 engine splits
@@ -1166,6 +1188,7 @@ func NewInstantMetricTripperware(
 			StatsCollectorMiddleware(),
 			NewLimitsMiddleware(limits),
 			NewQuerySizeLimiterMiddleware(schema.Configs, engineOpts, log, limits, statsHandler),
+			NewQueryCostMiddleware(schema.Configs, engineOpts, log, limits, statsHandler),
 			NewSplitByRangeMiddleware(log, engineOpts, limits, cfg.InstantMetricQuerySplitAlign, metrics.rangeMapper),
 		}
 
@@ -995,6 +995,7 @@ func TestPostQueries(t *testing.T) {
 		handler,
 		handler,
 		handler,
+		handler,
 		fakeLimits{},
 	).Do(ctx, lreq)
 	require.NoError(t, err)
@@ -1399,6 +1400,7 @@ type fakeLimits struct {
 	requiredNumberLabels        int
 	maxQueryBytesRead           int
 	maxQuerierBytesRead         int
+	queryCostLowPriority        int
 	maxStatsCacheFreshness      time.Duration
 	maxMetadataCacheFreshness   time.Duration
 	volumeEnabled               bool
@@ -1494,6 +1496,18 @@ func (f fakeLimits) MaxQuerierBytesRead(context.Context, string) int {
 	return f.maxQuerierBytesRead
 }
 
+func (f fakeLimits) QueryCostLowPriorityThreshold(context.Context, string) int {
+	return f.queryCostLowPriority
+}
+
+func (f fakeLimits) ExportEnabled(string) bool {
+	return true
+}
+
+func (f fakeLimits) MaxExportRange(string) time.Duration {
+	return 0
+}
+
 func (f fakeLimits) QueryTimeout(context.Context, string) time.Duration {
 	return f.queryTimeout
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"runtime"
+	"slices"
 	"strconv"
 	"sync"
 	"testing"
@@ -1948,3 +1949,72 @@ func assertSplits(t *testing.T, want, splits []queryrangebase.Request) {
 		}
 	}
 }
+
+func Test_splitByInterval_ContextStage(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), "1")
+	// Each split returns a match at its start, with the two entries before it,
+	// and a match a minute before its end, with the two entries after it. The
+	// contexts overlap the neighbouring splits.
+	entriesAt := func(minutes ...int64) []logproto.Entry {
+		res := make([]logproto.Entry, 0, len(minutes))
+		for _, m := range minutes {
+			res = append(res, logproto.Entry{Timestamp: time.Unix(m*60, 0), Line: fmt.Sprintf("%d", m)})
+		}
+		return res
+	}
+	var splits int
+	next := queryrangebase.HandlerFunc(func(_ context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+		splits++
+		req := r.(*LokiRequest)
+		start, end := req.StartTs.Unix()/60, req.EndTs.Unix()/60
+		entries := entriesAt(start-2, start-1, start, end-1, end, end+1)
+		if req.Direction == logproto.BACKWARD {
+			slices.Reverse(entries)
+		}
+		return &LokiResponse{
+			Status:    loghttp.QueryStatusSuccess,
+			Direction: req.Direction,
+			Limit:     req.Limit,
+			Version:   uint32(loghttp.VersionV1),
+			Data: LokiData{
+				ResultType: loghttp.ResultTypeStream,
+				Result:     []logproto.Stream{{Labels: `{foo="bar"}`, Entries: entries}},
+			},
+		}, nil
+	})
+
+	split := SplitByIntervalMiddleware(
+		testSchemas,
+		WithSplitByLimits(fakeLimits{maxQueryParallelism: 1}, time.Hour),
+		DefaultCodec,
+		newDefaultSplitter(fakeLimits{}, nil),
+		nilMetrics,
+	).Wrap(next)
+
+	for _, direction := range []logproto.Direction{logproto.FORWARD, logproto.BACKWARD} {
+		t.Run(direction.String(), func(t *testing.T) {
+			splits = 0
+			query := `{foo="bar"} |= "panic" | context before=2 after=2`
+			res, err := split.Do(ctx, &LokiRequest{
+				StartTs:   time.Unix(0, 0),
+				EndTs:     time.Unix(0, (3 * time.Hour).Nanoseconds()),
+				Query:     query,
+				Limit:     1000,
+				Direction: direction,
+				Path:      "/loki/api/v1/query_range",
+				Plan: &plan.QueryPlan{
+					AST: syntax.MustParseExpr(query),
+				},
+			})
+			require.NoError(t, err)
+			require.Equal(t, 3, splits)
+
+			// The entries returned by several splits are only returned once.
+			expected := entriesAt(-2, -1, 0, 58, 59, 60, 61, 118, 119, 120, 121, 179, 180, 181)
+			if direction == logproto.BACKWARD {
+				slices.Reverse(expected)
+			}
+			require.Equal(t, []logproto.Stream{{Labels: `{foo="bar"}`, Entries: expected}}, res.(*LokiResponse).Data.Result)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package tail
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/tenant"
+
+	"github.com/grafana/loki/v3/pkg/loghttp"
+	loghttp_legacy "github.com/grafana/loki/v3/pkg/loghttp/legacy"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	util_log "github.com/grafana/loki/v3/pkg/util/log"
+)
+
+// GRPCServer serves tail queries over the LogTail gRPC service, as an
+// alternative to the websocket endpoint for clients behind proxies that
+// don't support websockets.
+type GRPCServer struct {
+	querier *Querier
+}
+
+var _ logproto.LogTailServer = &GRPCServer{}
+
+// NewGRPCServer returns a LogTail gRPC server tailing with the given querier.
+func NewGRPCServer(querier *Querier) *GRPCServer {
+	return &GRPCServer{querier: querier}
+}
+
+// Tail implements logproto.LogTailServer. The stream ends with an error when
+// tailing fails, and without one when the client cancels it.
+func (s *GRPCServer) Tail(req *logproto.TailRequest, srv logproto.LogTail_TailServer) error {
+	ctx := srv.Context()
+	logger := util_log.WithContext(ctx, s.querier.logger)
+
+	if req.DelayFor > loghttp.MaxDelayForInTailing {
+		return httpgrpc.Errorf(http.StatusBadRequest, "delay_for can't be greater than %d", loghttp.MaxDelayForInTailing)
+	}
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return httpgrpc.Errorf(http.StatusBadRequest, "%s", err.Error())
+	}
+
+	tailer, err := s.querier.Tail(ctx, req, false)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tailer.close(); err != nil {
+			level.Error(logger).Log("msg", "Error closing Tailer", "err", err)
+		}
+	}()
+
+	level.Info(logger).Log("msg", "starting to tail logs", "tenant", tenantID, "selectors", req.Query, "transport", "grpc")
+	defer func() {
+		level.Info(logger).Log("msg", "ended tailing logs", "tenant", tenantID, "selectors", req.Query, "transport", "grpc")
+	}()
+
+	responseChan := tailer.getResponseChan()
+	closeErrChan := tailer.getCloseErrorChan()
+
+	for {
+		select {
+		case response := <-responseChan:
+			if err := srv.Send(toLiveTailResponse(response)); err != nil {
+				level.Error(logger).Log("msg", "Error sending tail response", "err", err)
+				return err
+			}
+		case err := <-closeErrChan:
+			level.Error(logger).Log("msg", "Error from iterator", "err", err)
+			return fmt.Errorf("tailing failed: %w", err)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func toLiveTailResponse(r *loghttp_legacy.TailResponse) *logproto.LiveTailResponse {
+	resp := &logproto.LiveTailResponse{
+		Streams: r.Streams,
+	}
+	if len(r.DroppedEntries) > 0 {
+		resp.DroppedEntries = make([]logproto.DroppedEntry, 0, len(r.DroppedEntries))
+		for _, e := range r.DroppedEntries {
+			resp.DroppedEntries = append(resp.DroppedEntries, logproto.DroppedEntry{
+				Timestamp: e.Timestamp,
+				Labels:    e.Labels,
+			})
+		}
+	}
+	return resp
+}
@@ -0,0 +1,78 @@
+package tail
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	loghttp_legacy "github.com/grafana/loki/v3/pkg/loghttp/legacy"
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+type mockLogTailServer struct {
+	grpc.ServerStream
+	ctx       context.Context
+	responses chan *logproto.LiveTailResponse
+}
+
+func (s *mockLogTailServer) Context() context.Context { return s.ctx }
+
+func (s *mockLogTailServer) Send(r *logproto.LiveTailResponse) error {
+	s.responses <- r
+	return nil
+}
+
+func TestGRPCServer_Tail(t *testing.T) {
+	stream := logproto.Stream{
+		Labels:  `{app="loki"}`,
+		Entries: []logproto.Entry{{Timestamp: time.Unix(0, 1), Line: "line 1"}},
+	}
+	server := NewGRPCServer(newStreamingTailQuerier(t, stream))
+
+	ctx, cancel := context.WithCancel(user.InjectOrgID(context.Background(), "test"))
+	srv := &mockLogTailServer{ctx: ctx, responses: make(chan *logproto.LiveTailResponse, 100)}
+
+	done := make(chan error)
+	go func() {
+		done <- server.Tail(&logproto.TailRequest{Query: `{app="loki"}`, Limit: 10, Start: time.Now()}, srv)
+	}()
+
+	select {
+	case resp := <-srv.responses:
+		require.Len(t, resp.Streams, 1)
+		require.Equal(t, stream.Labels, resp.Streams[0].Labels)
+		require.Equal(t, "line 1", resp.Streams[0].Entries[0].Line)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no tail response received")
+	}
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestGRPCServer_Tail_InvalidDelay(t *testing.T) {
+	server := NewGRPCServer(newStreamingTailQuerier(t, logproto.Stream{}))
+	srv := &mockLogTailServer{ctx: user.InjectOrgID(context.Background(), "test")}
+
+	err := server.Tail(&logproto.TailRequest{Query: `{app="loki"}`, DelayFor: 10}, srv)
+	require.ErrorContains(t, err, "delay_for can't be greater than 5")
+}
+
+func TestToLiveTailResponse(t *testing.T) {
+	ts := time.Unix(0, 1)
+	resp := toLiveTailResponse(&loghttp_legacy.TailResponse{
+		Streams: []logproto.Stream{{Labels: `{app="loki"}`, Entries: []logproto.Entry{{Timestamp: ts, Line: "line"}}}},
+		DroppedEntries: []loghttp_legacy.DroppedEntry{
+			{Timestamp: ts, Labels: `{app="loki"}`},
+		},
+	})
+
+	require.Equal(t, &logproto.LiveTailResponse{
+		Streams:        []logproto.Stream{{Labels: `{app="loki"}`, Entries: []logproto.Entry{{Timestamp: ts, Line: "line"}}}},
+		DroppedEntries: []logproto.DroppedEntry{{Timestamp: ts, Labels: `{app="loki"}`}},
+	}, resp)
+}
@@ -23,7 +23,9 @@ const (
 	wsPingPeriod = 1 * time.Second
 )
 
-// TailHandler is a http.HandlerFunc for handling tail queries.
+// TailHandler is a http.HandlerFunc for handling tail queries. The responses
+// are streamed over a websocket, or as Server-Sent Events when the request is
+// not a websocket upgrade and accepts text/event-stream.
 func (q *Querier) TailHandler(w http.ResponseWriter, r *http.Request) {
 	upgrader := websocket.Upgrader{
 		CheckOrigin: func(_ *http.Request) bool { return true },
@@ -46,6 +48,11 @@ func (q *Querier) TailHandler(w http.ResponseWriter, r *http.Request) {
 	encodingFlags := httpreq.ExtractEncodingFlags(r)
 	version := loghttp.GetVersion(r.RequestURI)
 
+	if !websocket.IsWebSocketUpgrade(r) && acceptsEventStream(r) {
+		q.tailSSE(r.Context(), w, req, tenantID, encodingFlags, version, logger)
+		return
+	}
+
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		level.Error(logger).Log("msg", "Error in upgrading websocket", "err", err)
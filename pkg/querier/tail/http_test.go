@@ -1,16 +1,27 @@
 package tail
 
 import (
+	"bufio"
+	"context"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-kit/log"
 	"github.com/grafana/dskit/flagext"
 	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/loki/v3/pkg/compactor/deletion/deletionproto"
+	"github.com/grafana/loki/v3/pkg/iter"
+	"github.com/grafana/loki/v3/pkg/loghttp"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/querier/testutil"
+	"github.com/grafana/loki/v3/pkg/util/unmarshal"
 	"github.com/grafana/loki/v3/pkg/validation"
 )
 
@@ -41,6 +52,91 @@ func TestTailHandler(t *testing.T) {
 	require.Equal(t, "multiple org IDs present", rr.Body.String())
 }
 
+func TestTailHandler_ServerSentEvents(t *testing.T) {
+	tailQuerier := newStreamingTailQuerier(t, logproto.Stream{
+		Labels:  `{app="loki"}`,
+		Entries: []logproto.Entry{{Timestamp: time.Unix(0, 1), Line: "line 1"}},
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		tailQuerier.TailHandler(w, r.WithContext(user.InjectOrgID(r.Context(), "test")))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/loki/api/v1/tail?"+url.Values{"query": {`{app="loki"}`}}.Encode(), nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(resp.Body)
+	var data string
+	for data == "" {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		if strings.HasPrefix(line, "data: ") {
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+
+	var tailResponse loghttp.TailResponse
+	require.NoError(t, unmarshal.ReadTailResponseJSON(&tailResponse, staticMessage(data)))
+	require.Len(t, tailResponse.Streams, 1)
+	require.Equal(t, loghttp.LabelSet{"app": "loki"}, tailResponse.Streams[0].Labels)
+	require.Equal(t, "line 1", tailResponse.Streams[0].Entries[0].Line)
+}
+
+func TestAcceptsEventStream(t *testing.T) {
+	for accept, expected := range map[string]bool{
+		"":                  false,
+		"application/json":  false,
+		"text/event-stream": true,
+		"application/json, text/event-stream;q=0.9": true,
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/loki/api/v1/tail", nil)
+		if accept != "" {
+			r.Header.Set("Accept", accept)
+		}
+		require.Equal(t, expected, acceptsEventStream(r), accept)
+	}
+}
+
+type staticMessage string
+
+func (m staticMessage) ReadMessage() (int, []byte, error) {
+	return 0, []byte(m), nil
+}
+
+// newStreamingTailQuerier returns a querier tailing an ingester that
+// keeps sending the given stream.
+func newStreamingTailQuerier(t *testing.T, stream logproto.Stream) *Querier {
+	tailClient := newTailClientMock()
+	tailClient.On("Recv").Return(mockTailResponse(stream), nil)
+
+	ingester := newMockTailIngester()
+	ingester.On("Tail", mock.Anything, mock.Anything).Return(map[string]logproto.Querier_TailClient{"ingester-1": tailClient}, nil)
+	ingester.On("TailersCount", mock.Anything).Return([]uint32{0}, nil)
+	ingester.On("TailDisconnectedIngesters", mock.Anything, mock.Anything, mock.Anything).Return(map[string]logproto.Querier_TailClient{}, nil).Maybe()
+
+	logSelector := newMockTailLogSelector()
+	logSelector.On("SelectLogs", mock.Anything, mock.Anything).Return(iter.NoopEntryIterator, nil)
+
+	limits := &testutil.MockLimits{
+		MaxQueryTimeoutVal:            queryTimeout,
+		MaxStreamsMatchersPerQueryVal: 100,
+		MaxConcurrentTailRequestsVal:  10,
+	}
+
+	return NewQuerier(ingester, logSelector, newMockDeleteGettter("test", []deletionproto.DeleteRequest{}), limits, 7*24*time.Hour, NewMetrics(nil), log.NewNopLogger())
+}
+
 func defaultLimitsTestConfig() validation.Limits {
 	limits := validation.Limits{}
 	flagext.DefaultValues(&limits)
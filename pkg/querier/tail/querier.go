@@ -77,6 +77,12 @@ func (q *Querier) Tail(ctx context.Context, req *logproto.TailRequest, categoriz
 			AST: parsed,
 		}
 	}
+	if syntax.HasContextStage(req.Plan.AST) {
+		return nil, httpgrpc.Errorf(http.StatusBadRequest, "the context stage is not supported when tailing")
+	}
+	if syntax.HasDedupStage(req.Plan.AST) {
+		return nil, httpgrpc.Errorf(http.StatusBadRequest, "the dedup stage is not supported when tailing")
+	}
 
 	deletes, err := deletion.DeletesForUserQuery(ctx, req.Start, time.Now(), q.deleteGetter)
 	if err != nil {
@@ -0,0 +1,160 @@
+package tail
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/grafana/loki/v3/pkg/loghttp"
+	loghttp_legacy "github.com/grafana/loki/v3/pkg/loghttp/legacy"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/util/httpreq"
+	"github.com/grafana/loki/v3/pkg/util/marshal"
+	serverutil "github.com/grafana/loki/v3/pkg/util/server"
+)
+
+const (
+	sseContentType = "text/event-stream"
+	// ssePingPeriod is longer than the websocket one: comments are sent
+	// through proxies as regular data, so they are only needed to detect
+	// dead connections and keep idle ones open.
+	ssePingPeriod = 10 * time.Second
+)
+
+// acceptsEventStream returns whether the client asked for a
+// Server-Sent Events response.
+func acceptsEventStream(r *http.Request) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		for _, mediaType := range strings.Split(accept, ",") {
+			mediaType, _, _ = strings.Cut(mediaType, ";")
+			if strings.TrimSpace(mediaType) == sseContentType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sseWriter writes each message as a Server-Sent Event and flushes it.
+type sseWriter struct {
+	w  http.ResponseWriter
+	rc *http.ResponseController
+}
+
+func newSSEWriter(w http.ResponseWriter) *sseWriter {
+	return &sseWriter{w: w, rc: http.NewResponseController(w)}
+}
+
+// writeEvent writes one event. Every line of data is sent as its own data
+// field, so that clients join them back with newlines.
+func (s *sseWriter) writeEvent(event string, data []byte) error {
+	var buf bytes.Buffer
+	if event != "" {
+		buf.WriteString("event: ")
+		buf.WriteString(event)
+		buf.WriteByte('\n')
+	}
+	for _, line := range bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n")) {
+		buf.WriteString("data: ")
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	buf.WriteByte('\n')
+	return s.write(buf.Bytes())
+}
+
+func (s *sseWriter) ping() error {
+	return s.write([]byte(": ping\n\n"))
+}
+
+func (s *sseWriter) write(p []byte) error {
+	if _, err := s.w.Write(p); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+// tailSSE streams the tail responses as Server-Sent Events. Each response is
+// sent as a message event with the same JSON body as a websocket message, and
+// a tailing error is sent as an error event before the response ends.
+func (q *Querier) tailSSE(ctx context.Context, w http.ResponseWriter, req *logproto.TailRequest, tenantID string, encodingFlags httpreq.EncodingFlags, version loghttp.Version, logger log.Logger) {
+	tailer, err := q.Tail(ctx, req, encodingFlags.Has(httpreq.FlagCategorizeLabels))
+	if err != nil {
+		serverutil.WriteError(err, w)
+		return
+	}
+	defer func() {
+		if err := tailer.close(); err != nil {
+			level.Error(logger).Log("msg", "Error closing Tailer", "err", err)
+		}
+	}()
+
+	level.Info(logger).Log("msg", "starting to tail logs", "tenant", tenantID, "selectors", req.Query, "transport", "sse")
+	defer func() {
+		level.Info(logger).Log("msg", "ended tailing logs", "tenant", tenantID, "selectors", req.Query, "transport", "sse")
+	}()
+
+	w.Header().Set("Content-Type", sseContentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	// Disables response buffering in nginx based proxies.
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	sse := newSSEWriter(w)
+	if err := sse.ping(); err != nil {
+		level.Error(logger).Log("msg", "Error writing to event stream", "err", err)
+		return
+	}
+
+	ticker := time.NewTicker(ssePingPeriod)
+	defer ticker.Stop()
+
+	var (
+		buf          bytes.Buffer
+		response     *loghttp_legacy.TailResponse
+		responseChan = tailer.getResponseChan()
+		closeErrChan = tailer.getCloseErrorChan()
+	)
+
+	for {
+		select {
+		case response = <-responseChan:
+			buf.Reset()
+			var err error
+			if version == loghttp.VersionV1 {
+				err = marshal.WriteTailResponseJSON(*response, &buf, encodingFlags)
+			} else {
+				err = json.NewEncoder(&buf).Encode(response)
+			}
+			if err == nil {
+				err = sse.writeEvent("", buf.Bytes())
+			}
+			if err != nil {
+				level.Error(logger).Log("msg", "Error writing to event stream", "err", err)
+				return
+			}
+
+		case err := <-closeErrChan:
+			level.Error(logger).Log("msg", "Error from iterator", "err", err)
+			if err := sse.writeEvent("error", []byte(err.Error())); err != nil {
+				level.Error(logger).Log("msg", "Error writing error event to event stream", "err", err)
+			}
+			return
+
+		case <-ticker.C:
+			if err := sse.ping(); err != nil {
+				level.Error(logger).Log("msg", "Error writing ping to event stream", "err", err)
+				return
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -86,6 +86,17 @@ func NewRequestQueue(maxOutstandingPerTenant int, forgetDelay time.Duration, lim
 // Enqueue puts the request into the queue.
 // If request is successfully enqueued, successFn is called with the lock held, before any querier can receive the request.
 func (q *RequestQueue) Enqueue(tenant string, path []string, req Request, successFn func()) error {
+	return q.enqueue(tenant, path, false, req, successFn)
+}
+
+// EnqueueLowPriority puts the request into the low priority lane of the
+// tenant, whose requests are only dequeued when the other queues of the
+// tenant are empty, or once in a while so they are not starved.
+func (q *RequestQueue) EnqueueLowPriority(tenant string, path []string, req Request, successFn func()) error {
+	return q.enqueue(tenant, path, true, req, successFn)
+}
+
+func (q *RequestQueue) enqueue(tenant string, path []string, lowPriority bool, req Request, successFn func()) error {
 	q.mtx.Lock()
 	defer q.mtx.Unlock()
 
@@ -93,7 +104,7 @@ func (q *RequestQueue) Enqueue(tenant string, path []string, req Request, succes
 		return ErrStopped
 	}
 
-	queue, err := q.queues.getOrAddQueue(tenant, path)
+	queue, err := q.queues.getOrAddQueue(tenant, path, lowPriority)
 	if err != nil {
 		return fmt.Errorf("no queue found: %w", err)
 	}
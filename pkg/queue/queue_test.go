@@ -334,6 +334,50 @@ func TestMaxQueueSize(t *testing.T) {
 	})
 }
 
+func TestEnqueueLowPriority(t *testing.T) {
+	queue := NewRequestQueue(100, 0, noQueueLimits, NewMetrics(nil, constants.Loki, "query_scheduler"))
+	queue.RegisterConsumerConnection("querier")
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, queue.EnqueueLowPriority("tenant", []string{"user-a"}, fmt.Sprintf("low-%d", i), nil))
+	}
+	for i := 0; i < 2*lowPriorityInterval; i++ {
+		require.NoError(t, queue.Enqueue("tenant", []string{"user-a"}, fmt.Sprintf("high-%d", i), nil))
+	}
+
+	var dequeued []Request
+	idx := StartIndexWithLocalQueue
+	for i := 0; i < 3+2*lowPriorityInterval; i++ {
+		req, newIdx, err := queue.Dequeue(context.Background(), idx, "querier")
+		require.NoError(t, err)
+		dequeued = append(dequeued, req)
+		idx = newIdx.ReuseLastIndex()
+	}
+
+	// Low priority requests are dequeued once every lowPriorityInterval
+	// requests, and after all other requests.
+	require.Equal(t, "low-0", dequeued[lowPriorityInterval])
+	require.Equal(t, "low-1", dequeued[2*lowPriorityInterval+1])
+	require.Equal(t, "low-2", dequeued[2*lowPriorityInterval+2])
+	require.True(t, queue.queues.hasNoTenantQueues())
+}
+
+func TestEnqueueLowPriority_QueueSize(t *testing.T) {
+	queue := NewRequestQueue(3, 0, noQueueLimits, NewMetrics(nil, constants.Loki, "query_scheduler"))
+	queue.RegisterConsumerConnection("querier")
+
+	// Both lanes of a tenant count against the same limit.
+	require.NoError(t, queue.Enqueue("tenant", []string{"user-a"}, "high-0", nil))
+	require.NoError(t, queue.EnqueueLowPriority("tenant", []string{"user-a"}, "low-0", nil))
+	require.NoError(t, queue.EnqueueLowPriority("tenant", nil, "low-1", nil))
+	require.Equal(t, ErrTooManyRequests, queue.EnqueueLowPriority("tenant", nil, "low-2", nil))
+	require.Equal(t, ErrTooManyRequests, queue.Enqueue("tenant", nil, "high-1", nil))
+
+	_, _, err := queue.Dequeue(context.Background(), StartIndexWithLocalQueue, "querier")
+	require.NoError(t, err)
+	require.NoError(t, queue.EnqueueLowPriority("tenant", nil, "low-2", nil))
+}
+
 type mockLimits struct {
 	maxConsumer int
 }
@@ -101,6 +101,47 @@ type tenantQueue struct {
 	// Seed for shuffle sharding of consumers. This seed is based on userID only and is therefore consistent
 	// between different frontends.
 	seed int64
+
+	// lowPriority is the lane of the requests which are only dequeued when
+	// the other queues of the tenant are empty, or once every
+	// lowPriorityInterval requests so that they are not starved. Its requests
+	// count against the same maxUserQueueSize as the other queues of the
+	// tenant, see perUserQueueLen.
+	lowPriority *TreeQueue
+	// dequeuedSinceLowPriority is the number of requests dequeued from the
+	// other queues while the low priority lane was not empty.
+	dequeuedSinceLowPriority int
+}
+
+// lowPriorityInterval is the number of requests dequeued from the other
+// queues of a tenant before a request of its low priority lane is dequeued.
+const lowPriorityInterval = 10
+
+// Dequeue implements Queue
+func (q *tenantQueue) Dequeue() Request {
+	if q.lowPriority == nil || q.lowPriority.Len() == 0 {
+		return q.TreeQueue.Dequeue()
+	}
+
+	if q.dequeuedSinceLowPriority < lowPriorityInterval {
+		if item := q.TreeQueue.Dequeue(); item != nil {
+			q.dequeuedSinceLowPriority++
+			return item
+		}
+	}
+	q.dequeuedSinceLowPriority = 0
+	return q.lowPriority.Dequeue()
+}
+
+// Len implements Queue
+// It returns the length of all queues of the tenant, including the low
+// priority lane.
+func (q *tenantQueue) Len() int {
+	count := q.TreeQueue.Len()
+	if q.lowPriority != nil {
+		count += q.lowPriority.Len()
+	}
+	return count
 }
 
 func newTenantQueues(maxUserQueueSize int, forgetDelay time.Duration, limits Limits) *tenantQueues {
@@ -125,8 +166,10 @@ func (q *tenantQueues) deleteQueue(tenant string) {
 	q.mapping.Remove(tenant)
 }
 
-// Returns existing or new queue for a tenant.
-func (q *tenantQueues) getOrAddQueue(tenantID string, path []string) (Queue, error) {
+// Returns existing or new queue for a tenant. Requests of the queues of the
+// low priority lane are only dequeued when the other queues of the tenant are
+// empty, or once in a while so they are not starved.
+func (q *tenantQueues) getOrAddQueue(tenantID string, path []string, lowPriority bool) (Queue, error) {
 	// Empty tenant is not allowed, as that would break our tenants list ("" is used for free spot).
 	if tenantID == "" {
 		return nil, fmt.Errorf("empty tenant is not allowed")
@@ -158,6 +201,12 @@ func (q *tenantQueues) getOrAddQueue(tenantID string, path []string) (Queue, err
 		uq.consumers = shuffleConsumersForTenants(uq.seed, consumersToSelect, q.sortedConsumers, nil)
 	}
 
+	if lowPriority {
+		if uq.lowPriority == nil {
+			uq.lowPriority = newTreeQueue(q.maxUserQueueSize, tenantID)
+		}
+		return uq.lowPriority.add(path), nil
+	}
 	if len(path) == 0 {
 		return uq, nil
 	}
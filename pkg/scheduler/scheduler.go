@@ -391,14 +391,34 @@ func (s *Scheduler) enqueueRequest(frontendContext context.Context, frontendAddr
 		}
 	}
 
-	s.activeUsers.UpdateUserTimestamp(req.tenantID, now)
-	return s.requestQueue.Enqueue(req.tenantID, queuePath, req, func() {
+	successFn := func() {
 		shouldCancel = false
 
 		s.pendingRequestsMu.Lock()
 		defer s.pendingRequestsMu.Unlock()
 		s.pendingRequests[requestKey{frontendAddr: frontendAddr, queryID: msg.QueryID}] = req
-	})
+	}
+
+	s.activeUsers.UpdateUserTimestamp(req.tenantID, now)
+	if isLowPriority(msg) {
+		return s.requestQueue.EnqueueLowPriority(req.tenantID, queuePath, req, successFn)
+	}
+	return s.requestQueue.Enqueue(req.tenantID, queuePath, req, successFn)
+}
+
+// isLowPriority returns whether the frontend marked the request as low
+// priority, because of its estimated cost.
+func isLowPriority(msg *schedulerpb.FrontendToScheduler) bool {
+	if r := msg.GetQueryRequest(); r != nil {
+		return r.Metadata[lokihttpreq.LokiQueryPriorityHeader] == lokihttpreq.LowQueryPriority
+	}
+	key := textproto.CanonicalMIMEHeaderKey(lokihttpreq.LokiQueryPriorityHeader)
+	for _, h := range msg.GetHttpRequest().GetHeaders() {
+		if textproto.CanonicalMIMEHeaderKey(h.Key) == key {
+			return len(h.Values) > 0 && h.Values[0] == lokihttpreq.LowQueryPriority
+		}
+	}
+	return false
 }
 
 // This method doesn't do removal from the queue.
@@ -12,7 +12,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc/metadata"
 
+	"github.com/grafana/loki/v3/pkg/querier/queryrange"
 	"github.com/grafana/loki/v3/pkg/scheduler/schedulerpb"
+	"github.com/grafana/loki/v3/pkg/util/httpreq"
 	util_log "github.com/grafana/loki/v3/pkg/util/log"
 )
 
@@ -113,6 +115,45 @@ func TestProtobufBackwardsCompatibility(t *testing.T) {
 	})
 }
 
+func TestIsLowPriority(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		msg      *schedulerpb.FrontendToScheduler
+		expected bool
+	}{
+		{
+			name: "http request",
+			msg: &schedulerpb.FrontendToScheduler{Request: &schedulerpb.FrontendToScheduler_HttpRequest{
+				HttpRequest: &httpgrpc.HTTPRequest{Headers: []*httpgrpc.Header{{Key: "foo", Values: []string{"bar"}}}},
+			}},
+		},
+		{
+			name: "low priority http request",
+			msg: &schedulerpb.FrontendToScheduler{Request: &schedulerpb.FrontendToScheduler_HttpRequest{
+				HttpRequest: &httpgrpc.HTTPRequest{Headers: []*httpgrpc.Header{{Key: "x-loki-query-priority", Values: []string{httpreq.LowQueryPriority}}}},
+			}},
+			expected: true,
+		},
+		{
+			name: "query request",
+			msg: &schedulerpb.FrontendToScheduler{Request: &schedulerpb.FrontendToScheduler_QueryRequest{
+				QueryRequest: &queryrange.QueryRequest{Metadata: map[string]string{"foo": "bar"}},
+			}},
+		},
+		{
+			name: "low priority query request",
+			msg: &schedulerpb.FrontendToScheduler{Request: &schedulerpb.FrontendToScheduler_QueryRequest{
+				QueryRequest: &queryrange.QueryRequest{Metadata: map[string]string{httpreq.LokiQueryPriorityHeader: httpreq.LowQueryPriority}},
+			}},
+			expected: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isLowPriority(tc.msg))
+		})
+	}
+}
+
 type mockSchedulerForFrontendFrontendLoopServer struct {
 	msg *schedulerpb.SchedulerToFrontend
 }
@@ -86,6 +86,8 @@ type Config struct {
 
 	StoragePrefix string `yaml:"storage_prefix"`
 
+	Encryption EncryptionConfig `yaml:"encryption" category:"experimental"`
+
 	// Used to inject additional backends into the config. Allows for this config to
 	// be embedded in multiple contexts and support non-object storage based backends.
 	ExtraBackends []string `yaml:"-"`
@@ -114,6 +116,7 @@ func (cfg *Config) RegisterFlagsWithPrefixAndDefaultDirectory(prefix, dir string
 	cfg.Alibaba.RegisterFlagsWithPrefix(prefix, f)
 	cfg.BOS.RegisterFlagsWithPrefix(prefix, f)
 	f.StringVar(&cfg.StoragePrefix, prefix+"storage-prefix", "", "Prefix for all objects stored in the backend storage. For simplicity, it may only contain digits, English alphabet letters and dashes.")
+	cfg.Encryption.RegisterFlagsWithPrefix(prefix, f)
 }
 
 func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
@@ -132,6 +135,10 @@ func (cfg *Config) Validate() error {
 		return err
 	}
 
+	if err := cfg.Encryption.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -221,6 +228,14 @@ func NewClient(ctx context.Context, backend string, cfg Config, name string, log
 		client = NewPrefixedBucketClient(client, cfg.StoragePrefix)
 	}
 
+	if cfg.Encryption.Enabled {
+		provider, err := cfg.Encryption.keyProvider()
+		if err != nil {
+			return nil, err
+		}
+		client = NewEncryptingBucketClient(client, provider)
+	}
+
 	instrumentedClient := objstoretracing.WrapWithTraces(objstore.WrapWith(client, metrics))
 
 	// Wrap the client with any provided middleware
@@ -0,0 +1,491 @@
+package bucket
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/grafana/dskit/tenant"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/thanos-io/objstore"
+)
+
+const objectMetaCacheSize = 10000
+
+// EncryptionConfig configures client-side envelope encryption of objects.
+type EncryptionConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	KeyringFile string `yaml:"keyring_file"`
+
+	// KeyProvider overrides the keyring, to wrap data keys with an external
+	// key management service.
+	KeyProvider KeyProvider `yaml:"-"`
+}
+
+// RegisterFlagsWithPrefix registers flags with the given prefix.
+func (cfg *EncryptionConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, prefix+"encryption.enabled", false, "Experimental: Encrypt objects before uploading them, using per-tenant data keys wrapped by a key encryption key. Objects written before encryption was enabled remain readable. In storage_config, requires use_thanos_objstore to be enabled.")
+	f.StringVar(&cfg.KeyringFile, prefix+"encryption.keyring-file", "", "Path of the YAML keyring holding the key encryption keys. Keys are rotated by adding a new key to the keyring and making it the current one; old keys must be kept to read objects encrypted with them.")
+}
+
+// Validate validates the config.
+func (cfg *EncryptionConfig) Validate() error {
+	if cfg.Enabled && cfg.KeyProvider == nil && cfg.KeyringFile == "" {
+		return errors.New("encryption requires a keyring file")
+	}
+	return nil
+}
+
+func (cfg *EncryptionConfig) keyProvider() (KeyProvider, error) {
+	if cfg.KeyProvider != nil {
+		return cfg.KeyProvider, nil
+	}
+	return NewKeyring(cfg.KeyringFile)
+}
+
+// EncryptingBucketClient is a wrapper around an objstore.Bucket which
+// encrypts objects before they are uploaded and decrypts them when they are
+// read.
+//
+// Objects are encrypted with a key derived from a data key of the tenant found
+// in the context, or of the empty tenant if there is none, and a random salt.
+// Data keys are wrapped by the current key of the KeyProvider and stored in the
+// header of every object along with the key ID and the salt, so rotating keys
+// does not require rewriting objects.
+type EncryptingBucketClient struct {
+	bucket objstore.Bucket
+	keys   *dataKeys
+	metas  *lru.Cache[string, *encryptedObjectMeta]
+}
+
+// NewEncryptingBucketClient makes a new EncryptingBucketClient.
+func NewEncryptingBucketClient(bucket objstore.Bucket, provider KeyProvider) *EncryptingBucketClient {
+	metas, _ := lru.New[string, *encryptedObjectMeta](objectMetaCacheSize)
+	return &EncryptingBucketClient{
+		bucket: bucket,
+		keys:   newDataKeys(provider),
+		metas:  metas,
+	}
+}
+
+// dataKeys caches the current data key of every tenant, as well as unwrapped
+// data keys, to avoid calling the KeyProvider for every object.
+type dataKeys struct {
+	provider KeyProvider
+
+	mtx       sync.Mutex
+	current   map[string]*dataKey
+	unwrapped map[string][]byte
+}
+
+type dataKey struct {
+	keyID   string
+	wrapped []byte
+	key     []byte
+}
+
+func newDataKeys(provider KeyProvider) *dataKeys {
+	return &dataKeys{
+		provider:  provider,
+		current:   map[string]*dataKey{},
+		unwrapped: map[string][]byte{},
+	}
+}
+
+// forTenant returns the data key to encrypt new objects of the tenant with. A
+// new data key is created whenever the current key of the tenant changes.
+func (k *dataKeys) forTenant(ctx context.Context, tenant string) (*dataKey, error) {
+	keyID, err := k.provider.CurrentKeyID(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	k.mtx.Lock()
+	defer k.mtx.Unlock()
+
+	if dk, ok := k.current[tenant]; ok && dk.keyID == keyID {
+		return dk, nil
+	}
+
+	key, err := newDataKey()
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := k.provider.WrapKey(ctx, keyID, tenant, key)
+	if err != nil {
+		return nil, fmt.Errorf("wrapping data key of tenant %s with key %s: %w", tenant, keyID, err)
+	}
+	dk := &dataKey{keyID: keyID, wrapped: wrapped, key: key}
+	k.current[tenant] = dk
+	k.unwrapped[unwrappedKey(keyID, tenant, wrapped)] = key
+	return dk, nil
+}
+
+// unwrap returns the data key referenced by an object header.
+func (k *dataKeys) unwrap(ctx context.Context, h *envelopeHeader) ([]byte, error) {
+	cacheKey := unwrappedKey(h.keyID, h.tenant, h.wrappedKey)
+
+	k.mtx.Lock()
+	key, ok := k.unwrapped[cacheKey]
+	k.mtx.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	key, err := k.provider.UnwrapKey(ctx, h.keyID, h.tenant, h.wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapping data key of tenant %s with key %s: %w", h.tenant, h.keyID, err)
+	}
+	if len(key) != dataKeySize {
+		return nil, fmt.Errorf("unwrapping data key of tenant %s with key %s: invalid key size %d", h.tenant, h.keyID, len(key))
+	}
+
+	k.mtx.Lock()
+	k.unwrapped[cacheKey] = key
+	k.mtx.Unlock()
+	return key, nil
+}
+
+func unwrappedKey(keyID, tenant string, wrapped []byte) string {
+	return keyID + "\x00" + tenant + "\x00" + string(wrapped)
+}
+
+// encryptedObjectMeta describes the layout of a stored object, so ranges of it
+// can be read.
+type encryptedObjectMeta struct {
+	plain      bool // the object is not encrypted
+	size       int64
+	headerSize int64
+	cipher     *segmentCipher
+}
+
+func (m *encryptedObjectMeta) plaintextSize() int64 {
+	if m.plain {
+		return m.size
+	}
+	return m.cipher.plaintextSize(m.size - m.headerSize)
+}
+
+func (b *EncryptingBucketClient) cipherFor(ctx context.Context, h *envelopeHeader) (*segmentCipher, error) {
+	key, err := b.keys.unwrap(ctx, h)
+	if err != nil {
+		return nil, err
+	}
+	return newSegmentCipher(key, h.salt, h.segmentSize)
+}
+
+// Close implements objstore.Bucket.
+func (b *EncryptingBucketClient) Close() error {
+	return b.bucket.Close()
+}
+
+// Upload encrypts the contents of the reader and uploads it as an object into
+// the bucket.
+func (b *EncryptingBucketClient) Upload(ctx context.Context, name string, r io.Reader) error {
+	er, err := b.encrypt(ctx, r)
+	if err != nil {
+		return err
+	}
+	b.metas.Remove(name)
+	return b.bucket.Upload(ctx, name, er)
+}
+
+func (b *EncryptingBucketClient) encrypt(ctx context.Context, r io.Reader) (*encryptingReader, error) {
+	// Objects without a tenant, like multi-tenant data objects, are encrypted
+	// with the data key of the empty tenant.
+	tenantID, _ := tenant.TenantID(ctx)
+
+	dk, err := b.keys.forTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	h := envelopeHeader{
+		keyID:       dk.keyID,
+		tenant:      tenantID,
+		wrappedKey:  dk.wrapped,
+		segmentSize: defaultSegmentSize,
+	}
+	if _, err := rand.Read(h.salt[:]); err != nil {
+		return nil, err
+	}
+	c, err := newSegmentCipher(dk.key, h.salt, h.segmentSize)
+	if err != nil {
+		return nil, err
+	}
+	return newEncryptingReader(r, h.encode(), c), nil
+}
+
+// GetAndReplace implements objstore.Bucket.
+func (b *EncryptingBucketClient) GetAndReplace(ctx context.Context, name string, fn func(existing io.ReadCloser) (io.ReadCloser, error)) error {
+	b.metas.Remove(name)
+	return b.bucket.GetAndReplace(ctx, name, func(existing io.ReadCloser) (io.ReadCloser, error) {
+		if existing != nil {
+			decrypted, err := b.decrypt(ctx, existing)
+			if err != nil {
+				_ = existing.Close()
+				return nil, err
+			}
+			existing = decrypted
+		}
+
+		replacement, err := fn(existing)
+		if err != nil || replacement == nil {
+			return replacement, err
+		}
+		return b.encrypt(ctx, replacement)
+	})
+}
+
+// Delete implements objstore.Bucket.
+func (b *EncryptingBucketClient) Delete(ctx context.Context, name string) error {
+	b.metas.Remove(name)
+	return b.bucket.Delete(ctx, name)
+}
+
+// Name implements objstore.Bucket.
+func (b *EncryptingBucketClient) Name() string {
+	return b.bucket.Name()
+}
+
+// SupportedIterOptions returns a list of supported IterOptions by the underlying provider.
+func (b *EncryptingBucketClient) SupportedIterOptions() []objstore.IterOptionType {
+	return b.bucket.SupportedIterOptions()
+}
+
+// Iter implements objstore.Bucket.
+func (b *EncryptingBucketClient) Iter(ctx context.Context, dir string, f func(string) error, options ...objstore.IterOption) error {
+	return b.bucket.Iter(ctx, dir, f, options...)
+}
+
+// IterWithAttributes implements objstore.Bucket.
+func (b *EncryptingBucketClient) IterWithAttributes(ctx context.Context, dir string, f func(attrs objstore.IterObjectAttributes) error, options ...objstore.IterOption) error {
+	return b.bucket.IterWithAttributes(ctx, dir, f, options...)
+}
+
+// Get implements objstore.Bucket.
+func (b *EncryptingBucketClient) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	rc, err := b.bucket.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	decrypted, err := b.decrypt(ctx, rc)
+	if err != nil {
+		_ = rc.Close()
+		return nil, err
+	}
+	return decrypted, nil
+}
+
+// decrypt returns a reader decrypting the object read from rc. Objects which
+// are not encrypted are returned as is.
+func (b *EncryptingBucketClient) decrypt(ctx context.Context, rc io.ReadCloser) (io.ReadCloser, error) {
+	br := bufio.NewReader(rc)
+	prefix, err := br.Peek(envelopePrefixSize)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if !isEnvelope(prefix) {
+		return &readCloser{Reader: br, Closer: rc}, nil
+	}
+
+	headerSize, err := decodeEnvelopePrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := br.Discard(envelopePrefixSize); err != nil {
+		return nil, err
+	}
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, err
+	}
+	h, err := decodeEnvelopeHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	c, err := b.cipherFor(ctx, h)
+	if err != nil {
+		return nil, err
+	}
+	return newDecryptingReader(br, rc, c), nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// GetRange implements objstore.Bucket. Only the segments overlapping the
+// range are downloaded and decrypted.
+func (b *EncryptingBucketClient) GetRange(ctx context.Context, name string, off, length int64) (io.ReadCloser, error) {
+	meta, err := b.objectMeta(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if meta.plain {
+		return b.bucket.GetRange(ctx, name, off, length)
+	}
+
+	size := meta.plaintextSize()
+	if off < 0 || off > size {
+		return nil, fmt.Errorf("invalid range offset %d for object of size %d", off, size)
+	}
+	end := size
+	if length >= 0 && off+length < size {
+		end = off + length
+	}
+
+	var (
+		segmentSize   = int64(meta.cipher.segmentSize)
+		encryptedSize = meta.cipher.encryptedSegmentSize()
+		lastSegment   = (size - 1) / segmentSize
+		first         = off / segmentSize
+		last          = (end - 1) / segmentSize
+	)
+	if size == 0 {
+		lastSegment = 0
+	}
+	if end <= off {
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	}
+
+	encOff := meta.headerSize + first*encryptedSize
+	encEnd := min(meta.headerSize+(last+1)*encryptedSize, meta.size)
+	rc, err := b.bucket.GetRange(ctx, name, encOff, encEnd-encOff)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	encrypted, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext := make([]byte, 0, (last-first+1)*segmentSize)
+	for i := first; i <= last; i++ {
+		segment := encrypted[:min(encryptedSize, int64(len(encrypted)))]
+		encrypted = encrypted[len(segment):]
+		plaintext, err = meta.cipher.open(plaintext, segment, uint32(i), i == lastSegment)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	start := off - first*segmentSize
+	return io.NopCloser(bytes.NewReader(plaintext[start : start+end-off])), nil
+}
+
+// objectMeta reads the envelope header of an object. Objects are immutable,
+// so the result is cached until the object is replaced through this client.
+func (b *EncryptingBucketClient) objectMeta(ctx context.Context, name string) (*encryptedObjectMeta, error) {
+	if meta, ok := b.metas.Get(name); ok {
+		return meta, nil
+	}
+
+	attrs, err := b.bucket.Attributes(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	meta := &encryptedObjectMeta{size: attrs.Size, plain: true}
+	if attrs.Size >= envelopePrefixSize {
+		prefix, err := b.readRange(ctx, name, 0, envelopePrefixSize)
+		if err != nil {
+			return nil, err
+		}
+		if isEnvelope(prefix) {
+			headerSize, err := decodeEnvelopePrefix(prefix)
+			if err != nil {
+				return nil, err
+			}
+			header, err := b.readRange(ctx, name, envelopePrefixSize, int64(headerSize))
+			if err != nil {
+				return nil, err
+			}
+			h, err := decodeEnvelopeHeader(header)
+			if err != nil {
+				return nil, err
+			}
+			meta.plain = false
+			meta.headerSize = envelopePrefixSize + int64(headerSize)
+			if meta.cipher, err = b.cipherFor(ctx, h); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	b.metas.Add(name, meta)
+	return meta, nil
+}
+
+func (b *EncryptingBucketClient) readRange(ctx context.Context, name string, off, length int64) ([]byte, error) {
+	rc, err := b.bucket.GetRange(ctx, name, off, length)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	buf := make([]byte, length)
+	if _, err := io.ReadFull(rc, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// Exists implements objstore.Bucket.
+func (b *EncryptingBucketClient) Exists(ctx context.Context, name string) (bool, error) {
+	return b.bucket.Exists(ctx, name)
+}
+
+// IsObjNotFoundErr implements objstore.Bucket.
+func (b *EncryptingBucketClient) IsObjNotFoundErr(err error) bool {
+	return b.bucket.IsObjNotFoundErr(err)
+}
+
+// IsAccessDeniedErr returns true if access to object is denied.
+func (b *EncryptingBucketClient) IsAccessDeniedErr(err error) bool {
+	return b.bucket.IsAccessDeniedErr(err)
+}
+
+// Attributes implements objstore.Bucket. The reported size is the size of the
+// decrypted object.
+func (b *EncryptingBucketClient) Attributes(ctx context.Context, name string) (objstore.ObjectAttributes, error) {
+	attrs, err := b.bucket.Attributes(ctx, name)
+	if err != nil {
+		return objstore.ObjectAttributes{}, err
+	}
+	meta, err := b.objectMeta(ctx, name)
+	if err != nil {
+		return objstore.ObjectAttributes{}, err
+	}
+	attrs.Size = meta.plaintextSize()
+	return attrs, nil
+}
+
+// Provider returns the provider of the bucket.
+func (b *EncryptingBucketClient) Provider() objstore.ObjProvider {
+	return b.bucket.Provider()
+}
+
+// ReaderWithExpectedErrs implements objstore.Bucket.
+func (b *EncryptingBucketClient) ReaderWithExpectedErrs(fn objstore.IsOpFailureExpectedFunc) objstore.BucketReader {
+	return b.WithExpectedErrs(fn)
+}
+
+// WithExpectedErrs implements objstore.Bucket.
+func (b *EncryptingBucketClient) WithExpectedErrs(fn objstore.IsOpFailureExpectedFunc) objstore.Bucket {
+	if ib, ok := b.bucket.(objstore.InstrumentedBucket); ok {
+		return &EncryptingBucketClient{
+			bucket: ib.WithExpectedErrs(fn),
+			keys:   b.keys,
+			metas:  b.metas,
+		}
+	}
+
+	return b
+}
@@ -0,0 +1,254 @@
+package bucket
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+	"gopkg.in/yaml.v2"
+)
+
+func writeKeyring(t *testing.T, path string, file KeyringFile) {
+	t.Helper()
+	b, err := yaml.Marshal(file)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, b, 0o600))
+	// Make sure the keyring notices the change even on coarse mtime filesystems.
+	now := time.Now().Add(time.Duration(len(file.Keys)) * time.Second)
+	require.NoError(t, os.Chtimes(path, now, now))
+}
+
+func randomKey(t *testing.T) string {
+	key := make([]byte, dataKeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func newTestEncryptingBucket(t *testing.T) (*EncryptingBucketClient, objstore.Bucket, string, KeyringFile) {
+	path := filepath.Join(t.TempDir(), "keyring.yaml")
+	file := KeyringFile{
+		CurrentKey: "key-1",
+		Keys:       map[string]string{"key-1": randomKey(t)},
+	}
+	writeKeyring(t, path, file)
+
+	keyring, err := NewKeyring(path)
+	require.NoError(t, err)
+
+	inner := objstore.NewInMemBucket()
+	return NewEncryptingBucketClient(inner, keyring), inner, path, file
+}
+
+// readAll returns a function reading the whole object returned by Get or GetRange.
+func readAll(t *testing.T) func(io.ReadCloser, error) []byte {
+	return func(rc io.ReadCloser, err error) []byte {
+		t.Helper()
+		require.NoError(t, err)
+		defer rc.Close()
+		b, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		return b
+	}
+}
+
+func TestEncryptingBucketClient_RoundTrip(t *testing.T) {
+	bkt, inner, _, _ := newTestEncryptingBucket(t)
+	ctx := user.InjectOrgID(context.Background(), "tenant-a")
+
+	for _, size := range []int{0, 1, defaultSegmentSize - 1, defaultSegmentSize, defaultSegmentSize + 1, 3*defaultSegmentSize + 123} {
+		data := make([]byte, size)
+		_, _ = rand.Read(data)
+
+		require.NoError(t, bkt.Upload(ctx, "object", bytes.NewReader(data)))
+
+		stored := readAll(t)(inner.Get(ctx, "object"))
+		// Short random data may appear in the ciphertext by chance.
+		if size >= 16 {
+			require.False(t, bytes.Contains(stored, data), "object stored in plaintext")
+		}
+
+		require.Equal(t, data, readAll(t)(bkt.Get(ctx, "object")))
+
+		attrs, err := bkt.Attributes(ctx, "object")
+		require.NoError(t, err)
+		require.Equal(t, int64(size), attrs.Size)
+
+		for _, r := range [][2]int64{{0, 1}, {0, -1}, {10, 100}, {defaultSegmentSize - 5, 10}, {int64(size) / 2, -1}, {int64(size) / 3, int64(size)}} {
+			off, length := r[0], r[1]
+			if off > int64(size) {
+				continue
+			}
+			end := int64(size)
+			if length >= 0 && off+length < end {
+				end = off + length
+			}
+			require.Equal(t, data[off:end], readAll(t)(bkt.GetRange(ctx, "object", off, length)), "size %d range %v", size, r)
+		}
+	}
+}
+
+func TestEncryptingBucketClient_KeyRotation(t *testing.T) {
+	bkt, _, path, file := newTestEncryptingBucket(t)
+	ctx := user.InjectOrgID(context.Background(), "tenant-a")
+
+	require.NoError(t, bkt.Upload(ctx, "old", strings.NewReader("written with key-1")))
+
+	file.Keys["key-2"] = randomKey(t)
+	file.CurrentKey = "key-2"
+	writeKeyring(t, path, file)
+
+	require.NoError(t, bkt.Upload(ctx, "new", strings.NewReader("written with key-2")))
+
+	// A fresh client, with nothing cached, reads objects of both keys.
+	keyring, err := NewKeyring(path)
+	require.NoError(t, err)
+	reader := NewEncryptingBucketClient(bkt.bucket, keyring)
+	require.Equal(t, "written with key-1", string(readAll(t)(reader.Get(ctx, "old"))))
+	require.Equal(t, "written with key-2", string(readAll(t)(reader.Get(ctx, "new"))))
+
+	meta, err := reader.objectMeta(ctx, "new")
+	require.NoError(t, err)
+	require.False(t, meta.plain)
+
+	// Dropping the old key makes its objects unreadable.
+	delete(file.Keys, "key-1")
+	writeKeyring(t, path, file)
+	keyring, err = NewKeyring(path)
+	require.NoError(t, err)
+	reader = NewEncryptingBucketClient(bkt.bucket, keyring)
+	_, err = reader.Get(ctx, "old")
+	require.ErrorContains(t, err, `key "key-1" not found`)
+}
+
+func TestEncryptingBucketClient_TenantKeys(t *testing.T) {
+	bkt, inner, path, file := newTestEncryptingBucket(t)
+
+	file.Keys["key-b"] = randomKey(t)
+	file.TenantKeys = map[string]string{"tenant-b": "key-b"}
+	writeKeyring(t, path, file)
+
+	ctxA := user.InjectOrgID(context.Background(), "tenant-a")
+	ctxB := user.InjectOrgID(context.Background(), "tenant-b")
+	require.NoError(t, bkt.Upload(ctxB, "object", strings.NewReader("tenant-b data")))
+
+	stored := readAll(t)(inner.Get(ctxB, "object"))
+	headerSize, err := decodeEnvelopePrefix(stored)
+	require.NoError(t, err)
+	h, err := decodeEnvelopeHeader(stored[envelopePrefixSize : envelopePrefixSize+headerSize])
+	require.NoError(t, err)
+	require.Equal(t, "key-b", h.keyID)
+	require.Equal(t, "tenant-b", h.tenant)
+
+	// The data key is bound to its tenant.
+	_, err = bkt.keys.provider.UnwrapKey(ctxA, h.keyID, "tenant-a", h.wrappedKey)
+	require.Error(t, err)
+}
+
+func TestEncryptingBucketClient_ObjectKeys(t *testing.T) {
+	bkt, inner, _, _ := newTestEncryptingBucket(t)
+	ctx := user.InjectOrgID(context.Background(), "tenant-a")
+
+	data := make([]byte, defaultSegmentSize+10)
+	require.NoError(t, bkt.Upload(ctx, "object-1", bytes.NewReader(data)))
+	require.NoError(t, bkt.Upload(ctx, "object-2", bytes.NewReader(data)))
+
+	var (
+		headers []*envelopeHeader
+		bodies  [][]byte
+	)
+	for _, name := range []string{"object-1", "object-2"} {
+		stored := readAll(t)(inner.Get(ctx, name))
+		headerSize, err := decodeEnvelopePrefix(stored)
+		require.NoError(t, err)
+		h, err := decodeEnvelopeHeader(stored[envelopePrefixSize : envelopePrefixSize+headerSize])
+		require.NoError(t, err)
+		headers = append(headers, h)
+		bodies = append(bodies, stored[envelopePrefixSize+headerSize:])
+	}
+
+	// Objects share the data key of their tenant, but are encrypted with keys
+	// derived from their own salt, so segment nonces are never reused.
+	require.Equal(t, headers[0].wrappedKey, headers[1].wrappedKey)
+	require.NotEqual(t, headers[0].salt, headers[1].salt)
+	require.NotEqual(t, bodies[0][:defaultSegmentSize], bodies[1][:defaultSegmentSize])
+}
+
+func TestEncryptingBucketClient_PlaintextObjects(t *testing.T) {
+	bkt, inner, _, _ := newTestEncryptingBucket(t)
+	ctx := context.Background()
+
+	// Objects written before encryption was enabled are still readable.
+	require.NoError(t, inner.Upload(ctx, "plain", strings.NewReader("plaintext object")))
+	require.Equal(t, "plaintext object", string(readAll(t)(bkt.Get(ctx, "plain"))))
+	require.Equal(t, "text", string(readAll(t)(bkt.GetRange(ctx, "plain", 5, 4))))
+
+	attrs, err := bkt.Attributes(ctx, "plain")
+	require.NoError(t, err)
+	require.Equal(t, int64(len("plaintext object")), attrs.Size)
+}
+
+func TestEncryptingBucketClient_Tampering(t *testing.T) {
+	bkt, inner, _, _ := newTestEncryptingBucket(t)
+	ctx := context.Background()
+
+	data := make([]byte, 2*defaultSegmentSize+10)
+	require.NoError(t, bkt.Upload(ctx, "object", bytes.NewReader(data)))
+	stored := readAll(t)(inner.Get(ctx, "object"))
+
+	for name, modified := range map[string][]byte{
+		"truncated":        stored[:len(stored)-len(stored)%defaultSegmentSize-100],
+		"truncated at end": stored[:len(stored)-(10+16)],
+		"flipped bit": func() []byte {
+			b := bytes.Clone(stored)
+			b[len(b)-1] ^= 1
+			return b
+		}(),
+		"modified salt": func() []byte {
+			headerSize, err := decodeEnvelopePrefix(stored)
+			require.NoError(t, err)
+			b := bytes.Clone(stored)
+			b[envelopePrefixSize+headerSize-1] ^= 1
+			return b
+		}(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, inner.Upload(ctx, "object", bytes.NewReader(modified)))
+			rc, err := bkt.Get(ctx, "object")
+			require.NoError(t, err)
+			_, err = io.ReadAll(rc)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestEncryptingBucketClient_GetAndReplace(t *testing.T) {
+	bkt, _, _, _ := newTestEncryptingBucket(t)
+	ctx := user.InjectOrgID(context.Background(), "tenant-a")
+
+	appendLine := func(existing io.ReadCloser) (io.ReadCloser, error) {
+		var b []byte
+		if existing != nil {
+			defer existing.Close()
+			var err error
+			if b, err = io.ReadAll(existing); err != nil {
+				return nil, err
+			}
+		}
+		return io.NopCloser(bytes.NewReader(append(b, "line\n"...))), nil
+	}
+
+	require.NoError(t, bkt.GetAndReplace(ctx, "object", appendLine))
+	require.NoError(t, bkt.GetAndReplace(ctx, "object", appendLine))
+	require.Equal(t, "line\nline\n", string(readAll(t)(bkt.Get(ctx, "object"))))
+}
@@ -0,0 +1,361 @@
+package bucket
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/thanos-io/objstore"
+)
+
+// Encrypted objects are stored in the following envelope:
+//
+//	| magic (4 bytes) | version (1 byte) | header length (uint32) | header | segments... |
+//
+// The header holds the ID of the key which wrapped the data key, the tenant the
+// data key belongs to, the wrapped data key, the segment size and a random
+// salt:
+//
+//	| uvarint len | key ID | uvarint len | tenant | uvarint len | wrapped data key | segment size (uint32) | salt (32 bytes) |
+//
+// Every object is encrypted with its own key, derived from the data key and the
+// salt with HKDF-SHA256, so data keys can be reused across objects without
+// reusing nonces. The plaintext is split into segments of segment size bytes
+// which are sealed individually with AES-256-GCM, so ranges of an object can be
+// decrypted without downloading it entirely. The nonce of a segment is its
+// index, the additional data is the segment index followed by a flag marking
+// the final segment, which detects reordered and truncated segments. An object
+// always holds at least one segment.
+const (
+	envelopeVersion    = 1
+	envelopePrefixSize = 4 + 1 + 4
+	envelopeSaltSize   = 32
+	maxEnvelopeHeader  = 64 << 10
+
+	defaultSegmentSize = 64 << 10
+	dataKeySize        = 32
+)
+
+var envelopeMagic = []byte{'L', 'K', 'E', 'N'}
+
+var errInvalidEnvelope = errors.New("invalid encryption envelope")
+
+// objectKeyInfo binds the keys derived from data keys to their use.
+var objectKeyInfo = "loki object encryption key"
+
+type envelopeHeader struct {
+	keyID       string
+	tenant      string
+	wrappedKey  []byte
+	segmentSize int
+	salt        [envelopeSaltSize]byte
+}
+
+// encode returns the serialized envelope prefix and header.
+func (h *envelopeHeader) encode() []byte {
+	var buf []byte
+	buf = binary.AppendUvarint(buf, uint64(len(h.keyID)))
+	buf = append(buf, h.keyID...)
+	buf = binary.AppendUvarint(buf, uint64(len(h.tenant)))
+	buf = append(buf, h.tenant...)
+	buf = binary.AppendUvarint(buf, uint64(len(h.wrappedKey)))
+	buf = append(buf, h.wrappedKey...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(h.segmentSize))
+	buf = append(buf, h.salt[:]...)
+
+	out := make([]byte, 0, envelopePrefixSize+len(buf))
+	out = append(out, envelopeMagic...)
+	out = append(out, envelopeVersion)
+	out = binary.BigEndian.AppendUint32(out, uint32(len(buf)))
+	return append(out, buf...)
+}
+
+// isEnvelope returns whether prefix starts like an encryption envelope.
+// Objects written before encryption was enabled are not wrapped in an envelope.
+func isEnvelope(prefix []byte) bool {
+	return len(prefix) >= len(envelopeMagic) && bytes.Equal(prefix[:len(envelopeMagic)], envelopeMagic)
+}
+
+// decodeEnvelopePrefix validates the envelope prefix and returns the length of
+// the header following it.
+func decodeEnvelopePrefix(prefix []byte) (int, error) {
+	if len(prefix) < envelopePrefixSize || !isEnvelope(prefix) {
+		return 0, errInvalidEnvelope
+	}
+	if prefix[len(envelopeMagic)] != envelopeVersion {
+		return 0, fmt.Errorf("unsupported encryption envelope version %d", prefix[len(envelopeMagic)])
+	}
+	n := binary.BigEndian.Uint32(prefix[len(envelopeMagic)+1:])
+	if n > maxEnvelopeHeader {
+		return 0, errInvalidEnvelope
+	}
+	return int(n), nil
+}
+
+func decodeEnvelopeHeader(b []byte) (*envelopeHeader, error) {
+	var h envelopeHeader
+	readBytes := func() ([]byte, error) {
+		n, sz := binary.Uvarint(b)
+		if sz <= 0 || uint64(len(b)-sz) < n {
+			return nil, errInvalidEnvelope
+		}
+		v := b[sz : sz+int(n)]
+		b = b[sz+int(n):]
+		return v, nil
+	}
+
+	keyID, err := readBytes()
+	if err != nil {
+		return nil, err
+	}
+	tenant, err := readBytes()
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := readBytes()
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != 4+envelopeSaltSize {
+		return nil, errInvalidEnvelope
+	}
+	h.keyID = string(keyID)
+	h.tenant = string(tenant)
+	h.wrappedKey = append([]byte(nil), wrapped...)
+	h.segmentSize = int(binary.BigEndian.Uint32(b))
+	copy(h.salt[:], b[4:])
+	if h.segmentSize <= 0 {
+		return nil, errInvalidEnvelope
+	}
+	return &h, nil
+}
+
+// newSegmentCipher returns the cipher of the object with the given salt,
+// encrypted with a key derived from dataKey.
+func newSegmentCipher(dataKey []byte, salt [envelopeSaltSize]byte, segmentSize int) (*segmentCipher, error) {
+	key, err := hkdf.Key(sha256.New, dataKey, salt[:], objectKeyInfo, dataKeySize)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return &segmentCipher{aead: aead, segmentSize: segmentSize}, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func newDataKey() ([]byte, error) {
+	key := make([]byte, dataKeySize)
+	_, err := rand.Read(key)
+	return key, err
+}
+
+// segmentCipher seals and opens the segments of one object.
+type segmentCipher struct {
+	aead        cipher.AEAD
+	segmentSize int
+}
+
+func (c *segmentCipher) nonceAndAD(index uint32, final bool) ([]byte, []byte) {
+	nonce := make([]byte, c.aead.NonceSize())
+	binary.BigEndian.PutUint32(nonce[len(nonce)-4:], index)
+
+	ad := binary.BigEndian.AppendUint32(make([]byte, 0, 5), index)
+	if final {
+		ad = append(ad, 1)
+	} else {
+		ad = append(ad, 0)
+	}
+	return nonce, ad
+}
+
+func (c *segmentCipher) seal(dst, plaintext []byte, index uint32, final bool) []byte {
+	nonce, ad := c.nonceAndAD(index, final)
+	return c.aead.Seal(dst, nonce, plaintext, ad)
+}
+
+func (c *segmentCipher) open(dst, ciphertext []byte, index uint32, final bool) ([]byte, error) {
+	nonce, ad := c.nonceAndAD(index, final)
+	out, err := c.aead.Open(dst, nonce, ciphertext, ad)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting segment %d: %w", index, err)
+	}
+	return out, nil
+}
+
+func (c *segmentCipher) encryptedSegmentSize() int64 {
+	return int64(c.segmentSize + c.aead.Overhead())
+}
+
+// plaintextSize returns the plaintext size of an object whose segments take
+// bodySize bytes.
+func (c *segmentCipher) plaintextSize(bodySize int64) int64 {
+	segments := (bodySize + c.encryptedSegmentSize() - 1) / c.encryptedSegmentSize()
+	return bodySize - segments*int64(c.aead.Overhead())
+}
+
+// encryptingReader encrypts the plaintext read from r into an envelope.
+type encryptingReader struct {
+	r      *bufio.Reader
+	cipher *segmentCipher
+	size   int64 // size of the plaintext, -1 if unknown
+	header []byte
+	buf    []byte
+	sealed []byte
+	out    []byte
+	index  uint32
+	done   bool
+	closer io.Closer
+}
+
+func newEncryptingReader(r io.Reader, header []byte, c *segmentCipher) *encryptingReader {
+	size, err := objstore.TryToGetSize(r)
+	if err != nil {
+		size = -1
+	}
+	er := &encryptingReader{
+		r:      bufio.NewReaderSize(r, c.segmentSize),
+		cipher: c,
+		size:   size,
+		header: header,
+		buf:    make([]byte, c.segmentSize),
+	}
+	if rc, ok := r.(io.Closer); ok {
+		er.closer = rc
+	}
+	er.out = append(er.out, header...)
+	return er
+}
+
+// ObjectSize implements objstore.ObjectSizer, so backends can upload the
+// encrypted object in one request when the plaintext size is known.
+func (e *encryptingReader) ObjectSize() (int64, error) {
+	if e.size < 0 {
+		return 0, errors.New("unknown size")
+	}
+	segments := (e.size + int64(e.cipher.segmentSize) - 1) / int64(e.cipher.segmentSize)
+	if segments == 0 {
+		segments = 1
+	}
+	return int64(len(e.header)) + e.size + segments*int64(e.cipher.aead.Overhead()), nil
+}
+
+func (e *encryptingReader) Read(p []byte) (int, error) {
+	for len(e.out) == 0 {
+		if e.done {
+			return 0, io.EOF
+		}
+		if err := e.nextSegment(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, e.out)
+	e.out = e.out[n:]
+	return n, nil
+}
+
+func (e *encryptingReader) nextSegment() error {
+	n, err := io.ReadFull(e.r, e.buf)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return err
+	}
+	final := n < len(e.buf)
+	if !final {
+		// Look ahead to know whether this is the final segment.
+		if _, err := e.r.Peek(1); errors.Is(err, io.EOF) {
+			final = true
+		} else if err != nil {
+			return err
+		}
+	}
+	e.sealed = e.cipher.seal(e.sealed[:0], e.buf[:n], e.index, final)
+	e.out = e.sealed
+	e.index++
+	e.done = final
+	return nil
+}
+
+func (e *encryptingReader) Close() error {
+	if e.closer == nil {
+		return nil
+	}
+	return e.closer.Close()
+}
+
+// decryptingReader decrypts the segments of an envelope read from r. The
+// header must have been consumed from r already.
+type decryptingReader struct {
+	r      *bufio.Reader
+	closer io.Closer
+	cipher *segmentCipher
+	buf    []byte
+	out    []byte
+	index  uint32
+	done   bool
+}
+
+func newDecryptingReader(r io.Reader, closer io.Closer, c *segmentCipher) *decryptingReader {
+	return &decryptingReader{
+		r:      bufio.NewReader(r),
+		closer: closer,
+		cipher: c,
+		buf:    make([]byte, c.encryptedSegmentSize()),
+	}
+}
+
+func (d *decryptingReader) Read(p []byte) (int, error) {
+	for len(d.out) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.nextSegment(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.out)
+	d.out = d.out[n:]
+	return n, nil
+}
+
+func (d *decryptingReader) nextSegment() error {
+	n, err := io.ReadFull(d.r, d.buf)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return err
+	}
+	final := n < len(d.buf)
+	if !final {
+		if _, err := d.r.Peek(1); errors.Is(err, io.EOF) {
+			final = true
+		} else if err != nil {
+			return err
+		}
+	}
+	out, err := d.cipher.open(d.buf[:0], d.buf[:n], d.index, final)
+	if err != nil {
+		return err
+	}
+	d.out = out
+	d.index++
+	d.done = final
+	return nil
+}
+
+func (d *decryptingReader) Close() error {
+	return d.closer.Close()
+}
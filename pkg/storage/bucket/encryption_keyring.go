@@ -0,0 +1,179 @@
+package bucket
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// KeyProvider wraps and unwraps data keys with key encryption keys it
+// controls, like a KMS does. Wrapped data keys are stored alongside the
+// encrypted objects, together with the ID of the key which wrapped them.
+type KeyProvider interface {
+	// CurrentKeyID returns the ID of the key to wrap new data keys of the
+	// tenant with. Rotating keys means changing the current key ID, keys
+	// previously returned must still be able to unwrap data keys.
+	CurrentKeyID(ctx context.Context, tenant string) (string, error)
+
+	// WrapKey encrypts the data key of the tenant with the given key.
+	WrapKey(ctx context.Context, keyID, tenant string, dataKey []byte) ([]byte, error)
+
+	// UnwrapKey decrypts a data key of the tenant wrapped with the given key.
+	UnwrapKey(ctx context.Context, keyID, tenant string, wrapped []byte) ([]byte, error)
+}
+
+// KeyringFile is the content of a keyring file.
+//
+//	current_key: key-2
+//	tenant_keys:
+//	  tenant-a: key-3
+//	keys:
+//	  key-1: <base64 encoded 32 bytes key>
+//	  key-2: ...
+//	  key-3: ...
+type KeyringFile struct {
+	CurrentKey string            `yaml:"current_key"`
+	TenantKeys map[string]string `yaml:"tenant_keys"`
+	Keys       map[string]string `yaml:"keys"`
+}
+
+// Keyring is a KeyProvider backed by keys in a local file. It is meant for
+// testing and small deployments, production setups should use a KMS.
+//
+// The file is reloaded when it changes, so keys are rotated by adding a new
+// key and making it the current one. Old keys must be kept in the file for as
+// long as objects encrypted with them exist.
+type Keyring struct {
+	path string
+
+	mtx     sync.Mutex
+	modTime time.Time
+	file    KeyringFile
+	keys    map[string][]byte
+}
+
+// NewKeyring loads a keyring from path.
+func NewKeyring(path string) (*Keyring, error) {
+	k := &Keyring{path: path}
+	if err := k.reload(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func (k *Keyring) reload() error {
+	k.mtx.Lock()
+	defer k.mtx.Unlock()
+
+	info, err := os.Stat(k.path)
+	if err != nil {
+		return err
+	}
+	if k.keys != nil && info.ModTime().Equal(k.modTime) {
+		return nil
+	}
+
+	b, err := os.ReadFile(k.path)
+	if err != nil {
+		return err
+	}
+	var file KeyringFile
+	if err := yaml.UnmarshalStrict(b, &file); err != nil {
+		return fmt.Errorf("parsing keyring %s: %w", k.path, err)
+	}
+
+	keys := make(map[string][]byte, len(file.Keys))
+	for id, encoded := range file.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return fmt.Errorf("decoding key %s: %w", id, err)
+		}
+		if len(key) != dataKeySize {
+			return fmt.Errorf("key %s must be %d bytes long", id, dataKeySize)
+		}
+		keys[id] = key
+	}
+	if _, ok := keys[file.CurrentKey]; !ok {
+		return fmt.Errorf("current key %q not found in keyring", file.CurrentKey)
+	}
+	for tenant, id := range file.TenantKeys {
+		if _, ok := keys[id]; !ok {
+			return fmt.Errorf("key %q of tenant %s not found in keyring", id, tenant)
+		}
+	}
+
+	k.file = file
+	k.keys = keys
+	k.modTime = info.ModTime()
+	return nil
+}
+
+// CurrentKeyID implements KeyProvider.
+func (k *Keyring) CurrentKeyID(_ context.Context, tenant string) (string, error) {
+	if err := k.reload(); err != nil {
+		return "", err
+	}
+
+	k.mtx.Lock()
+	defer k.mtx.Unlock()
+	if id, ok := k.file.TenantKeys[tenant]; ok {
+		return id, nil
+	}
+	return k.file.CurrentKey, nil
+}
+
+// WrapKey implements KeyProvider.
+func (k *Keyring) WrapKey(_ context.Context, keyID, tenant string, dataKey []byte) ([]byte, error) {
+	aead, err := k.aead(keyID)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	// The tenant is authenticated, so a data key can't be used for another tenant.
+	return aead.Seal(nonce, nonce, dataKey, []byte(tenant)), nil
+}
+
+// UnwrapKey implements KeyProvider.
+func (k *Keyring) UnwrapKey(_ context.Context, keyID, tenant string, wrapped []byte) ([]byte, error) {
+	aead, err := k.aead(keyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, errors.New("wrapped key too short")
+	}
+	nonce, ciphertext := wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, []byte(tenant))
+}
+
+func (k *Keyring) aead(keyID string) (cipher.AEAD, error) {
+	key, ok := k.key(keyID)
+	if !ok {
+		// The key may have been added since the last reload.
+		if err := k.reload(); err != nil {
+			return nil, err
+		}
+		if key, ok = k.key(keyID); !ok {
+			return nil, fmt.Errorf("key %q not found in keyring", keyID)
+		}
+	}
+	return newAEAD(key)
+}
+
+func (k *Keyring) key(keyID string) ([]byte, bool) {
+	k.mtx.Lock()
+	defer k.mtx.Unlock()
+	key, ok := k.keys[keyID]
+	return key, ok
+}
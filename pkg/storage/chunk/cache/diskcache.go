@@ -0,0 +1,325 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/loki/v3/pkg/logqlmodel/stats"
+	"github.com/grafana/loki/v3/pkg/util/constants"
+)
+
+// diskCacheTempPrefix is the prefix of files being written to the cache
+// directory. They are removed on startup.
+const diskCacheTempPrefix = ".tmp-"
+
+// DiskCacheConfig represents the config of a cache on local disk.
+type DiskCacheConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	Directory string `yaml:"directory"`
+	MaxSizeMB int64  `yaml:"max_size_mb"`
+}
+
+func (cfg *DiskCacheConfig) RegisterFlagsWithPrefix(prefix, description string, f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, prefix+"enabled", false, description+"Whether the disk cache is enabled.")
+	f.StringVar(&cfg.Directory, prefix+"directory", "", description+"Directory to store the cache entries in. Entries found in the directory on startup are reused. The directory must not be shared with other caches.")
+	f.Int64Var(&cfg.MaxSizeMB, prefix+"max-size-mb", 1024, description+"Maximum size of the cache on disk in MB.")
+}
+
+func (cfg *DiskCacheConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.Directory == "" {
+		return errors.New("disk cache directory must be set")
+	}
+	if cfg.MaxSizeMB <= 0 {
+		return errors.New("disk cache max size must be greater than 0")
+	}
+	return nil
+}
+
+type diskCacheEntry struct {
+	file string
+	size uint64
+}
+
+// DiskCache is a cache storing entries as files in a local directory, for
+// example on an SSD. The total size of the files is bounded, the least
+// recently used entries are evicted first.
+//
+// The entries outlive the process: the cache picks up the files in the
+// directory on startup, ordered by their modification time.
+type DiskCache struct {
+	name      string
+	cacheType stats.CacheType
+	dir       string
+	logger    log.Logger
+
+	lock          sync.Mutex
+	maxSizeBytes  uint64
+	currSizeBytes uint64
+	entries       map[string]*list.Element
+	lru           *list.List
+
+	entriesAddedNew prometheus.Counter
+	entriesEvicted  *prometheus.CounterVec
+	entriesCurrent  prometheus.Gauge
+	sizeBytes       prometheus.Gauge
+}
+
+// NewDiskCache returns a new DiskCache storing its entries in cfg.Directory.
+func NewDiskCache(name string, cfg DiskCacheConfig, reg prometheus.Registerer, logger log.Logger, cacheType stats.CacheType) (*DiskCache, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(cfg.Directory, 0o750); err != nil {
+		return nil, fmt.Errorf("creating disk cache directory: %w", err)
+	}
+
+	c := &DiskCache{
+		name:      name,
+		cacheType: cacheType,
+		dir:       cfg.Directory,
+		logger:    log.With(logger, "cache", name),
+
+		maxSizeBytes: uint64(cfg.MaxSizeMB * 1e6),
+		entries:      make(map[string]*list.Element),
+		lru:          list.New(),
+
+		entriesAddedNew: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace:   constants.Loki,
+			Subsystem:   "diskcache",
+			Name:        "added_new_total",
+			Help:        "The total number of new entries added to the cache",
+			ConstLabels: prometheus.Labels{"cache": name},
+		}),
+		entriesEvicted: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace:   constants.Loki,
+			Subsystem:   "diskcache",
+			Name:        "evicted_total",
+			Help:        "The total number of evicted entries",
+			ConstLabels: prometheus.Labels{"cache": name},
+		}, []string{"reason"}),
+		entriesCurrent: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace:   constants.Loki,
+			Subsystem:   "diskcache",
+			Name:        "entries",
+			Help:        "Current number of entries in the cache",
+			ConstLabels: prometheus.Labels{"cache": name},
+		}),
+		sizeBytes: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace:   constants.Loki,
+			Subsystem:   "diskcache",
+			Name:        "size_bytes",
+			Help:        "The current size of the cache entries on disk in bytes",
+			ConstLabels: prometheus.Labels{"cache": name},
+		}),
+	}
+
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// load adds the files already present in the cache directory to the cache.
+func (c *DiskCache) load() error {
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("reading disk cache directory: %w", err)
+	}
+
+	type existingFile struct {
+		name    string
+		size    int64
+		modTime time.Time
+	}
+	files := make([]existingFile, 0, len(dirEntries))
+	for _, e := range dirEntries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		if strings.HasPrefix(e.Name(), diskCacheTempPrefix) {
+			// Leftover of a write interrupted by a restart.
+			_ = os.Remove(filepath.Join(c.dir, e.Name()))
+			continue
+		}
+		info, err := e.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("reading disk cache entry: %w", err)
+		}
+		files = append(files, existingFile{name: e.Name(), size: info.Size(), modTime: info.ModTime()})
+	}
+	slices.SortFunc(files, func(a, b existingFile) int { return a.modTime.Compare(b.modTime) })
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, f := range files {
+		c.add(f.name, uint64(f.size))
+	}
+	c.evict()
+	level.Info(c.logger).Log("msg", "loaded disk cache entries", "entries", c.lru.Len(), "size_bytes", c.currSizeBytes)
+	return nil
+}
+
+// Store implements Cache.
+func (c *DiskCache) Store(_ context.Context, keys []string, values [][]byte) error {
+	var errs []error
+	for i, key := range keys {
+		if err := c.store(key, values[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("storing disk cache entries: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+func (c *DiskCache) store(key string, value []byte) error {
+	size := uint64(len(value))
+	if size > c.maxSizeBytes {
+		c.entriesEvicted.WithLabelValues(tooBigReason).Inc()
+		return nil
+	}
+
+	// Write the entry to a temporary file first so that readers and restarts
+	// never see partially written entries.
+	tmp, err := os.CreateTemp(c.dir, diskCacheTempPrefix+"*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(value); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	file := diskCacheFile(key)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := os.Rename(tmp.Name(), filepath.Join(c.dir, file)); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if elem, ok := c.entries[file]; ok {
+		c.remove(elem)
+		c.entriesEvicted.WithLabelValues(replacedReason).Inc()
+	} else {
+		c.entriesAddedNew.Inc()
+	}
+	c.add(file, size)
+	c.evict()
+	return nil
+}
+
+// Fetch implements Cache.
+func (c *DiskCache) Fetch(_ context.Context, keys []string) (found []string, bufs [][]byte, missing []string, err error) {
+	for _, key := range keys {
+		value, ok := c.fetch(key)
+		if !ok {
+			missing = append(missing, key)
+			continue
+		}
+		found = append(found, key)
+		bufs = append(bufs, value)
+	}
+	return found, bufs, missing, nil
+}
+
+func (c *DiskCache) fetch(key string) ([]byte, bool) {
+	file := diskCacheFile(key)
+
+	c.lock.Lock()
+	elem, ok := c.entries[file]
+	if ok {
+		c.lru.MoveToFront(elem)
+	}
+	c.lock.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	// The entry may be evicted or replaced concurrently. Files which are
+	// already open remain readable after they have been removed.
+	value, err := os.ReadFile(filepath.Join(c.dir, file))
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			level.Warn(c.logger).Log("msg", "failed to read disk cache entry", "file", file, "err", err)
+		}
+		return nil, false
+	}
+	return value, true
+}
+
+// Stop implements Cache. The entries are kept on disk.
+func (c *DiskCache) Stop() {}
+
+// GetCacheType implements Cache.
+func (c *DiskCache) GetCacheType() stats.CacheType {
+	return c.cacheType
+}
+
+// add adds the file to the front of the LRU list. The lock must be held.
+func (c *DiskCache) add(file string, size uint64) {
+	c.entries[file] = c.lru.PushFront(&diskCacheEntry{file: file, size: size})
+	c.currSizeBytes += size
+	c.entriesCurrent.Inc()
+	c.sizeBytes.Set(float64(c.currSizeBytes))
+}
+
+// remove removes the entry from the index, but not from disk. The lock must be
+// held.
+func (c *DiskCache) remove(elem *list.Element) {
+	entry := c.lru.Remove(elem).(*diskCacheEntry)
+	delete(c.entries, entry.file)
+	c.currSizeBytes -= entry.size
+	c.entriesCurrent.Dec()
+	c.sizeBytes.Set(float64(c.currSizeBytes))
+}
+
+// evict removes the least recently used entries until the cache fits its
+// maximum size. The lock must be held.
+func (c *DiskCache) evict() {
+	for c.currSizeBytes > c.maxSizeBytes {
+		elem := c.lru.Back()
+		if elem == nil {
+			return
+		}
+		file := elem.Value.(*diskCacheEntry).file
+		c.remove(elem)
+		if err := os.Remove(filepath.Join(c.dir, file)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			level.Warn(c.logger).Log("msg", "failed to remove evicted disk cache entry", "file", file, "err", err)
+		}
+		c.entriesEvicted.WithLabelValues(fullReason).Inc()
+	}
+}
+
+// diskCacheFile returns the name of the file storing the entry for key. Keys
+// are hashed as they may contain characters not allowed in file names.
+func diskCacheFile(key string) string {
+	return HashKey(key)
+}
@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskCache(t *testing.T) {
+	ctx := context.Background()
+	cfg := DiskCacheConfig{Enabled: true, Directory: t.TempDir(), MaxSizeMB: 1}
+
+	c, err := NewDiskCache("test", cfg, nil, log.NewNopLogger(), "test")
+	require.NoError(t, err)
+
+	// Three values of 300KB each fit into the cache.
+	value := func(b byte) []byte {
+		v := make([]byte, 300_000)
+		for i := range v {
+			v[i] = b
+		}
+		return v
+	}
+	require.NoError(t, c.Store(ctx, []string{"a", "b", "c"}, [][]byte{value('a'), value('b'), value('c')}))
+
+	found, bufs, missing, err := c.Fetch(ctx, []string{"a", "b", "c", "d"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "c"}, found)
+	require.Equal(t, [][]byte{value('a'), value('b'), value('c')}, bufs)
+	require.Equal(t, []string{"d"}, missing)
+
+	// Fetching "a" makes "b" the least recently used entry, which is evicted
+	// when "d" is stored.
+	_, _, _, err = c.Fetch(ctx, []string{"a"})
+	require.NoError(t, err)
+	require.NoError(t, c.Store(ctx, []string{"d"}, [][]byte{value('d')}))
+
+	found, _, missing, err = c.Fetch(ctx, []string{"a", "b", "c", "d"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "c", "d"}, found)
+	require.Equal(t, []string{"b"}, missing)
+	require.Equal(t, float64(1), testutil.ToFloat64(c.entriesEvicted.WithLabelValues(fullReason)))
+	require.Equal(t, float64(3), testutil.ToFloat64(c.entriesCurrent))
+	require.Equal(t, float64(900_000), testutil.ToFloat64(c.sizeBytes))
+
+	files, err := os.ReadDir(cfg.Directory)
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+
+	// Values larger than the cache are not stored.
+	require.NoError(t, c.Store(ctx, []string{"e"}, [][]byte{make([]byte, 2_000_000)}))
+	_, _, missing, err = c.Fetch(ctx, []string{"e"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"e"}, missing)
+	require.Equal(t, float64(1), testutil.ToFloat64(c.entriesEvicted.WithLabelValues(tooBigReason)))
+}
+
+func TestDiskCache_Reload(t *testing.T) {
+	ctx := context.Background()
+	cfg := DiskCacheConfig{Enabled: true, Directory: t.TempDir(), MaxSizeMB: 1}
+
+	c, err := NewDiskCache("test", cfg, nil, log.NewNopLogger(), "test")
+	require.NoError(t, err)
+	require.NoError(t, c.Store(ctx, []string{"a", "b"}, [][]byte{[]byte("foo"), []byte("bar")}))
+
+	// Leftovers of interrupted writes are removed on startup.
+	require.NoError(t, os.WriteFile(cfg.Directory+"/"+diskCacheTempPrefix+"1", []byte("partial"), 0o600))
+
+	reloaded, err := NewDiskCache("test", cfg, prometheus.NewRegistry(), log.NewNopLogger(), "test")
+	require.NoError(t, err)
+
+	found, bufs, missing, err := reloaded.Fetch(ctx, []string{"a", "b", "c"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, found)
+	require.Equal(t, [][]byte{[]byte("foo"), []byte("bar")}, bufs)
+	require.Equal(t, []string{"c"}, missing)
+	require.Equal(t, float64(6), testutil.ToFloat64(reloaded.sizeBytes))
+
+	files, err := os.ReadDir(cfg.Directory)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+}
+
+func TestDiskCacheConfig_Validate(t *testing.T) {
+	require.NoError(t, (&DiskCacheConfig{}).Validate())
+	require.Error(t, (&DiskCacheConfig{Enabled: true, MaxSizeMB: 1}).Validate())
+	require.Error(t, (&DiskCacheConfig{Enabled: true, Directory: "/tmp"}).Validate())
+}
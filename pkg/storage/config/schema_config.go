@@ -46,6 +46,7 @@ var (
 	errCurrentBoltdbShipperNon24Hours  = errors.New("boltdb-shipper works best with 24h periodic index config. Either add a new config with future date set to 24h to retain the existing index or change the existing config to use 24h period")
 	errUpcomingBoltdbShipperNon24Hours = errors.New("boltdb-shipper with future date must always have periodic config for index set to 24h")
 	errTSDBNon24HoursIndexPeriod       = errors.New("tsdb must always have periodic config for index set to 24h")
+	errSchemaV14NonTSDB                = errors.New("schema v14 is only supported with the tsdb index type")
 	errZeroLengthConfig                = errors.New("must specify at least one schema configuration")
 
 	// regexp for finding the trailing index table number at the end of the table name
@@ -439,8 +440,10 @@ func (cfg *PeriodConfig) ChunkFormat() (byte, chunkenc.HeadBlockFmt, error) {
 	switch {
 	case sver <= 12:
 		return chunkenc.ChunkFormatV3, chunkenc.ChunkHeadFormatFor(chunkenc.ChunkFormatV3), nil
-	default: // for v13 and above
+	case sver == 13:
 		return chunkenc.ChunkFormatV4, chunkenc.ChunkHeadFormatFor(chunkenc.ChunkFormatV4), nil
+	default: // for v14 and above
+		return chunkenc.ChunkFormatV5, chunkenc.ChunkHeadFormatFor(chunkenc.ChunkFormatV5), nil
 	}
 }
 
@@ -484,8 +487,12 @@ func (cfg PeriodConfig) validate() error {
 		return err
 	}
 
+	if v == 14 && cfg.IndexType != types.TSDBType {
+		return errSchemaV14NonTSDB
+	}
+
 	switch v {
-	case 10, 11, 12, 13:
+	case 10, 11, 12, 13, 14:
 		if cfg.RowShards == 0 {
 			return fmt.Errorf("must have row_shards > 0 (current: %d) for schema (%s)", cfg.RowShards, cfg.Schema)
 		}
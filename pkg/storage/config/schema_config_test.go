@@ -483,6 +483,33 @@ func TestPeriodConfig_Validate(t *testing.T) {
 				ChunkTables: PeriodicTableConfig{Period: 0},
 			},
 		},
+		{
+			desc: "v14",
+			in: PeriodConfig{
+				Schema:    "v14",
+				IndexType: types.TSDBType,
+				RowShards: 16,
+				IndexTables: IndexPeriodicTableConfig{
+					PathPrefix:          "index/",
+					PeriodicTableConfig: PeriodicTableConfig{Period: ObjectStorageIndexRequiredPeriod},
+				},
+				ChunkTables: PeriodicTableConfig{Period: 0},
+			},
+		},
+		{
+			desc: "error v14 without tsdb",
+			in: PeriodConfig{
+				Schema:    "v14",
+				IndexType: types.BoltDBShipperType,
+				RowShards: 16,
+				IndexTables: IndexPeriodicTableConfig{
+					PathPrefix:          "index/",
+					PeriodicTableConfig: PeriodicTableConfig{Period: ObjectStorageIndexRequiredPeriod},
+				},
+				ChunkTables: PeriodicTableConfig{Period: 0},
+			},
+			err: errSchemaV14NonTSDB.Error(),
+		},
 	} {
 		t.Run(tc.desc, func(t *testing.T) {
 			if tc.err == "" {
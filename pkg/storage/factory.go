@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/dskit/flagext"
 
 	"github.com/grafana/loki/v3/pkg/indexgateway"
+	lokilog "github.com/grafana/loki/v3/pkg/logql/log"
 	"github.com/grafana/loki/v3/pkg/storage/bucket"
 	"github.com/grafana/loki/v3/pkg/storage/chunk/cache"
 	"github.com/grafana/loki/v3/pkg/storage/chunk/client"
@@ -33,6 +34,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/storage/chunk/client/openstack"
 	"github.com/grafana/loki/v3/pkg/storage/chunk/client/testutils"
 	"github.com/grafana/loki/v3/pkg/storage/config"
+	"github.com/grafana/loki/v3/pkg/storage/lookup"
 	"github.com/grafana/loki/v3/pkg/storage/stores"
 	"github.com/grafana/loki/v3/pkg/storage/stores/series/index"
 	bloomshipperconfig "github.com/grafana/loki/v3/pkg/storage/stores/shipper/bloomshipper/config"
@@ -40,6 +42,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/storage/stores/shipper/indexshipper/boltdb"
 	"github.com/grafana/loki/v3/pkg/storage/stores/shipper/indexshipper/downloads"
 	"github.com/grafana/loki/v3/pkg/storage/types"
+	"github.com/grafana/loki/v3/pkg/storage/zstddict"
 	"github.com/grafana/loki/v3/pkg/util"
 	"github.com/grafana/loki/v3/pkg/util/constants"
 	util_log "github.com/grafana/loki/v3/pkg/util/log"
@@ -303,11 +306,19 @@ type Config struct {
 	BoltDBShipperConfig boltdb.IndexCfg           `yaml:"boltdb_shipper" doc:"description=Configures storing index in an Object Store (GCS/S3/Azure/Swift/COS/Filesystem) in the form of boltdb files. Required fields only required when boltdb-shipper is defined in config."`
 	TSDBShipperConfig   indexshipper.Config       `yaml:"tsdb_shipper" doc:"description=Configures storing index in an Object Store (GCS/S3/Azure/Swift/COS/Filesystem) in a prometheus TSDB-like format. Required fields only required when TSDB is defined in config."`
 	BloomShipperConfig  bloomshipperconfig.Config `yaml:"bloom_shipper" category:"experimental" doc:"description=Experimental: Configures the bloom shipper component, which contains the store abstraction to fetch bloom filters from and put them to object storage."`
+	ZstdDictionaries    zstddict.Config           `yaml:"zstd_dictionaries" category:"experimental" doc:"description=Experimental: Configures per-tenant zstd dictionaries used by the zstd-dict chunk encoding."`
+	LookupTables        lookup.Config             `yaml:"lookup_tables" category:"experimental" doc:"description=Experimental: Configures per-tenant lookup tables used by the lookup stage of LogQL queries."`
 
 	// Config for using AsyncStore when using async index stores like `boltdb-shipper`.
 	// It is required for getting chunk ids of recently flushed chunks from the ingesters.
 	EnableAsyncStore bool          `yaml:"-"`
 	AsyncStoreConfig AsyncStoreCfg `yaml:"-"`
+
+	// Optional provider of the lookup tables used by the lookup stage of queries.
+	LookupTableProvider lokilog.LookupTables `yaml:"-"`
+
+	// Optional redaction config of tenants applied by queries.
+	RedactionLimits lokilog.RedactionLimits `yaml:"-"`
 }
 
 // RegisterFlags adds the flags required to configure this flag set.
@@ -339,6 +350,8 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	f.IntVar(&cfg.MaxChunkBatchSize, "store.max-chunk-batch-size", 50, "The maximum number of chunks to fetch per batch.")
 	cfg.TSDBShipperConfig.RegisterFlagsWithPrefix("tsdb.", f)
 	cfg.BloomShipperConfig.RegisterFlagsWithPrefix("bloom.", f)
+	cfg.ZstdDictionaries.RegisterFlagsWithPrefix("store.zstd-dictionaries.", f)
+	cfg.LookupTables.RegisterFlagsWithPrefix("store.lookup-tables.", f)
 }
 
 // Validate config and returns error on failure
@@ -367,9 +380,20 @@ func (cfg *Config) Validate() error {
 	if err := cfg.BloomShipperConfig.Validate(); err != nil {
 		return errors.Wrap(err, "invalid bloom shipper config")
 	}
+	if err := cfg.ZstdDictionaries.Validate(); err != nil {
+		return errors.Wrap(err, "invalid zstd dictionaries config")
+	}
+	if err := cfg.LookupTables.Validate(); err != nil {
+		return errors.Wrap(err, "invalid lookup tables config")
+	}
 	if err := cfg.ObjectStore.Validate(); err != nil {
 		return errors.Wrap(err, "invalid object store config")
 	}
+	// The legacy storage clients do not support encryption, chunks and
+	// indexes would be written unencrypted.
+	if cfg.ObjectStore.Encryption.Enabled && !cfg.UseThanosObjstore {
+		return errors.New("invalid object store config: encryption requires use_thanos_objstore to be enabled")
+	}
 	if err := cfg.AlibabaStorageConfig.Validate(); err != nil {
 		return errors.Wrap(err, "invalid Alibaba Storage config")
 	}
@@ -281,6 +281,17 @@ func TestNewObjectClient_prefixing(t *testing.T) {
 	})
 }
 
+func TestConfig_ValidateEncryption(t *testing.T) {
+	var cfg Config
+	flagext.DefaultValues(&cfg)
+	cfg.ObjectStore.Encryption.Enabled = true
+	cfg.ObjectStore.Encryption.KeyringFile = "keyring.yaml"
+	require.EqualError(t, cfg.Validate(), "invalid object store config: encryption requires use_thanos_objstore to be enabled")
+
+	cfg.UseThanosObjstore = true
+	require.NoError(t, cfg.Validate())
+}
+
 // DefaultSchemaConfig creates a simple schema config for testing
 func DefaultSchemaConfig(store, schema string, from model.Time) config.SchemaConfig {
 	s := config.SchemaConfig{
@@ -0,0 +1,42 @@
+package lookup
+
+import (
+	"errors"
+	"flag"
+	"time"
+
+	"github.com/grafana/loki/v3/pkg/util/flagext"
+)
+
+// Config configures storing and loading per-tenant lookup tables.
+type Config struct {
+	Enabled          bool             `yaml:"enabled"`
+	StoragePrefix    string           `yaml:"storage_prefix"`
+	CacheSize        int              `yaml:"cache_size"`
+	LatestVersionTTL time.Duration    `yaml:"latest_version_ttl"`
+	MaxTableSize     flagext.ByteSize `yaml:"max_table_size"`
+}
+
+// RegisterFlagsWithPrefix registers flags with the given prefix.
+func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, prefix+"enabled", false, "Experimental: Enable per-tenant lookup tables. Tables are uploaded through the lookup tables API, stored in object storage and used by the lookup stage of LogQL queries.")
+	f.StringVar(&cfg.StoragePrefix, prefix+"storage-prefix", "lookup-tables/", "Prefix of the object storage path under which lookup tables are stored.")
+	f.IntVar(&cfg.CacheSize, prefix+"cache-size", 100, "Maximum number of lookup table versions kept in memory.")
+	f.DurationVar(&cfg.LatestVersionTTL, prefix+"latest-version-ttl", time.Minute, "How long the latest version of a lookup table is cached before checking object storage for a newer one.")
+	_ = cfg.MaxTableSize.Set("10MB")
+	f.Var(&cfg.MaxTableSize, prefix+"max-table-size", "Maximum size of an uploaded lookup table.")
+}
+
+// Validate validates the config.
+func (cfg *Config) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.CacheSize <= 0 {
+		return errors.New("cache size must be greater than 0")
+	}
+	if cfg.MaxTableSize <= 0 {
+		return errors.New("max table size must be greater than 0")
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package lookup
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/go-kit/log/level"
+	"github.com/gorilla/mux"
+	"github.com/grafana/dskit/tenant"
+	"github.com/prometheus/common/model"
+
+	lokilog "github.com/grafana/loki/v3/pkg/logql/log"
+	"github.com/grafana/loki/v3/pkg/util"
+	serverutil "github.com/grafana/loki/v3/pkg/util/server"
+)
+
+type tableResponse struct {
+	Name    string     `json:"name"`
+	Version string     `json:"version"`
+	Columns []string   `json:"columns,omitempty"`
+	Rows    [][]string `json:"rows,omitempty"`
+}
+
+type listResponse struct {
+	Tables []tableResponse `json:"tables"`
+}
+
+// ListHandler lists the tables of the tenant with their latest version.
+func (t *Tables) ListHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		serverutil.WriteError(err, w)
+		return
+	}
+	names, err := t.store.List(r.Context(), tenantID)
+	if err != nil {
+		serverutil.WriteError(err, w)
+		return
+	}
+
+	resp := listResponse{Tables: make([]tableResponse, 0, len(names))}
+	for _, name := range names {
+		version, err := t.store.Latest(r.Context(), tenantID, name)
+		if err != nil {
+			if errors.Is(err, ErrTableNotFound) {
+				// Deleted while listing.
+				continue
+			}
+			serverutil.WriteError(err, w)
+			return
+		}
+		resp.Tables = append(resp.Tables, tableResponse{Name: name, Version: version})
+	}
+	util.WriteJSONResponse(w, resp)
+}
+
+// GetHandler returns a table, its latest version unless the version
+// parameter is set.
+func (t *Tables) GetHandler(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	version := r.URL.Query().Get("version")
+	if version != "" && !lokilog.ValidLookupVersion(version) {
+		http.Error(w, fmt.Sprintf("invalid lookup table version '%s'", version), http.StatusBadRequest)
+		return
+	}
+	table, err := t.LookupTable(r.Context(), name, version)
+	if err != nil {
+		serverutil.WriteError(err, w)
+		return
+	}
+	util.WriteJSONResponse(w, tableResponse{
+		Name:    table.Name,
+		Version: table.Version,
+		Columns: table.Columns,
+		Rows:    table.Rows,
+	})
+}
+
+// PutHandler uploads a new version of a table from a text/csv or
+// application/json body and returns the version.
+func (t *Tables) PutHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		serverutil.WriteError(err, w)
+		return
+	}
+	name := mux.Vars(r)["name"]
+	if !model.LabelName(name).IsValidLegacy() {
+		http.Error(w, fmt.Sprintf("invalid lookup table name '%s'", name), http.StatusBadRequest)
+		return
+	}
+	contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid content type: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	raw, err := io.ReadAll(http.MaxBytesReader(w, r.Body, int64(t.cfg.MaxTableSize)))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, fmt.Sprintf("lookup table exceeds the maximum size of %s", t.cfg.MaxTableSize.String()), http.StatusRequestEntityTooLarge)
+			return
+		}
+		serverutil.WriteError(err, w)
+		return
+	}
+	table, err := parseTable(contentType, raw)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid lookup table: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	version, err := t.put(r.Context(), tenantID, name, table)
+	if err != nil {
+		level.Error(t.logger).Log("msg", "failed to store lookup table", "tenant", tenantID, "name", name, "err", err)
+		serverutil.WriteError(err, w)
+		return
+	}
+	util.WriteJSONResponse(w, tableResponse{Name: name, Version: version})
+}
+
+// DeleteHandler deletes all versions of a table.
+func (t *Tables) DeleteHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		serverutil.WriteError(err, w)
+		return
+	}
+	name := mux.Vars(r)["name"]
+	if err := t.delete(r.Context(), tenantID, name); err != nil {
+		if errors.Is(err, ErrTableNotFound) {
+			http.Error(w, fmt.Sprintf("lookup table %s not found", name), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, ErrInvalidKey) {
+			http.Error(w, fmt.Sprintf("invalid lookup table name '%s'", name), http.StatusBadRequest)
+			return
+		}
+		serverutil.WriteError(err, w)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,212 @@
+package lookup
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/tenant"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/common/model"
+	"github.com/thanos-io/objstore"
+
+	lokilog "github.com/grafana/loki/v3/pkg/logql/log"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+type metrics struct {
+	loads *prometheus.CounterVec
+}
+
+func newMetrics(r prometheus.Registerer) *metrics {
+	return &metrics{
+		loads: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "lookup_table_loads_total",
+			Help:      "Total number of lookup table versions loaded from object storage by status.",
+		}, []string{"status"}),
+	}
+}
+
+type latestVersion struct {
+	version string
+	expires time.Time
+}
+
+// Tables loads the lookup tables of tenants from object storage and caches
+// them. Versions of a table are immutable and cached until evicted, the latest
+// version of a table is cached for Config.LatestVersionTTL.
+type Tables struct {
+	cfg     Config
+	store   *Store
+	logger  log.Logger
+	metrics *metrics
+
+	tables *lru.Cache[string, *lokilog.LookupTable]
+
+	mtx    sync.Mutex
+	latest map[string]latestVersion
+	now    func() time.Time
+}
+
+var _ lokilog.LookupTables = &Tables{}
+
+// NewTables creates new Tables stored in bucket.
+func NewTables(cfg Config, bucket objstore.Bucket, logger log.Logger, r prometheus.Registerer) (*Tables, error) {
+	tables, err := lru.New[string, *lokilog.LookupTable](cfg.CacheSize)
+	if err != nil {
+		return nil, err
+	}
+	return &Tables{
+		cfg:     cfg,
+		store:   NewStore(bucket),
+		logger:  logger,
+		metrics: newMetrics(r),
+		tables:  tables,
+		latest:  map[string]latestVersion{},
+		now:     time.Now,
+	}, nil
+}
+
+// LookupTable implements log.LookupTables for the tenant of ctx.
+func (t *Tables) LookupTable(ctx context.Context, name, version string) (*lokilog.LookupTable, error) {
+	tenantID, err := queryTenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// The cache is keyed by the path of the table, so the name and the version
+	// are checked before, not only when the table is loaded.
+	if !model.LabelName(name).IsValidLegacy() || (version != "" && !lokilog.ValidLookupVersion(version)) {
+		return nil, t.wrapErr(name, version, ErrInvalidKey)
+	}
+	if version == "" {
+		if version, err = t.latestVersion(ctx, tenantID, name); err != nil {
+			return nil, t.wrapErr(name, "", err)
+		}
+	}
+
+	key := path.Join(tenantID, name, version)
+	if table, ok := t.tables.Get(key); ok {
+		return table, nil
+	}
+	stored, err := t.store.Get(ctx, tenantID, name, version)
+	if err != nil {
+		t.metrics.loads.WithLabelValues("failure").Inc()
+		return nil, t.wrapErr(name, version, err)
+	}
+	t.metrics.loads.WithLabelValues("success").Inc()
+	table := stored.lookupTable(name, version)
+	t.tables.Add(key, table)
+	return table, nil
+}
+
+// PinLookupTables returns a copy of expr whose lookup stages without a version
+// use the latest version of their table, or expr itself if it has none. Queries
+// are pinned before their results are cached, so that results computed with a
+// previous version of a table are not reused.
+func (t *Tables) PinLookupTables(ctx context.Context, expr syntax.Expr) (syntax.Expr, error) {
+	if !syntax.HasLookupStage(expr) {
+		return expr, nil
+	}
+	tenantID, err := queryTenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pinned, err := syntax.Clone(expr)
+	if err != nil {
+		return nil, err
+	}
+	pinned.Walk(func(e syntax.Expr) bool {
+		l, ok := e.(*syntax.LookupExpr)
+		if !ok || l.Version != "" {
+			return true
+		}
+		l.Version, err = t.latestVersion(ctx, tenantID, l.Table)
+		if err != nil {
+			err = t.wrapErr(l.Table, "", err)
+		}
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pinned, nil
+}
+
+// queryTenantID returns the tenant of a query using lookup tables. Tables are
+// stored per tenant, so they can't be used by queries of multiple tenants.
+func queryTenantID(ctx context.Context) (string, error) {
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(tenantIDs) > 1 {
+		return "", httpgrpc.Errorf(http.StatusBadRequest, "lookup tables cannot be used in queries of multiple tenants")
+	}
+	return tenantIDs[0], nil
+}
+
+func (t *Tables) latestVersion(ctx context.Context, tenantID, name string) (string, error) {
+	key := path.Join(tenantID, name)
+
+	t.mtx.Lock()
+	latest, ok := t.latest[key]
+	t.mtx.Unlock()
+	if ok && t.now().Before(latest.expires) {
+		return latest.version, nil
+	}
+
+	version, err := t.store.Latest(ctx, tenantID, name)
+	if err != nil {
+		return "", err
+	}
+	t.setLatest(tenantID, name, version)
+	return version, nil
+}
+
+func (t *Tables) setLatest(tenantID, name, version string) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.latest[path.Join(tenantID, name)] = latestVersion{version: version, expires: t.now().Add(t.cfg.LatestVersionTTL)}
+}
+
+// wrapErr turns missing tables into client errors.
+func (t *Tables) wrapErr(name, version string, err error) error {
+	if errors.Is(err, ErrInvalidKey) {
+		return httpgrpc.Errorf(http.StatusBadRequest, "invalid lookup table name '%s' or version '%s'", name, version)
+	}
+	if !errors.Is(err, ErrTableNotFound) {
+		return err
+	}
+	if version == "" {
+		return httpgrpc.Errorf(http.StatusBadRequest, "lookup table %s not found", name)
+	}
+	return httpgrpc.Errorf(http.StatusBadRequest, "lookup table %s version %s not found", name, version)
+}
+
+// put stores a new version of a table of tenant and returns it.
+func (t *Tables) put(ctx context.Context, tenantID, name string, stored table) (string, error) {
+	version, err := t.store.Put(ctx, tenantID, name, stored)
+	if err != nil {
+		return "", err
+	}
+	t.setLatest(tenantID, name, version)
+	return version, nil
+}
+
+func (t *Tables) delete(ctx context.Context, tenantID, name string) error {
+	if err := t.store.Delete(ctx, tenantID, name); err != nil {
+		return err
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	delete(t.latest, path.Join(tenantID, name))
+	return nil
+}
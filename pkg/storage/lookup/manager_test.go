@@ -0,0 +1,183 @@
+package lookup
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gorilla/mux"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
+)
+
+func testTables(t *testing.T) *Tables {
+	cfg := Config{Enabled: true, CacheSize: 10, LatestVersionTTL: time.Minute, MaxTableSize: 1 << 10}
+	tables, err := NewTables(cfg, objstore.NewInMemBucket(), log.NewNopLogger(), prometheus.NewRegistry())
+	require.NoError(t, err)
+	return tables
+}
+
+func TestParseTable(t *testing.T) {
+	csvTable, err := parseTable("text/csv", []byte("customer_id,tier\n1,gold\n2,silver\n"))
+	require.NoError(t, err)
+	require.Equal(t, table{Columns: []string{"customer_id", "tier"}, Rows: [][]string{{"1", "gold"}, {"2", "silver"}}}, csvTable)
+
+	jsonTable, err := parseTable("application/json", []byte(`[{"tier": "gold", "customer_id": 1}, {"customer_id": 2, "tier": "silver", "trial": true}]`))
+	require.NoError(t, err)
+	require.Equal(t, table{Columns: []string{"customer_id", "tier", "trial"}, Rows: [][]string{{"1", "gold", ""}, {"2", "silver", "true"}}}, jsonTable)
+
+	for _, tc := range []struct {
+		contentType, body, err string
+	}{
+		{"text/csv", "", "missing csv header"},
+		{"text/csv", "customer-id,tier\n1,gold\n", "invalid column name 'customer-id'"},
+		{"text/csv", "tier,tier\ngold,silver\n", "duplicate column 'tier'"},
+		{"application/json", `[]`, "a lookup table must have at least one column"},
+		{"application/json", `[{"customer_id": {"id": 1}}]`, "row 1: value of column 'customer_id' must be a string, a number or a boolean"},
+		{"text/plain", "", "unsupported content type 'text/plain', expected text/csv or application/json"},
+	} {
+		_, err := parseTable(tc.contentType, []byte(tc.body))
+		require.EqualError(t, err, tc.err, tc.body)
+	}
+}
+
+func TestTables(t *testing.T) {
+	ctx := user.InjectOrgID(context.Background(), "tenant-a")
+	tables := testTables(t)
+	now := time.Now()
+	tables.now = func() time.Time { return now }
+
+	v1, err := tables.put(ctx, "tenant-a", "customers", table{Columns: []string{"customer_id", "tier"}, Rows: [][]string{{"1", "gold"}}})
+	require.NoError(t, err)
+	require.Len(t, v1, versionLength)
+
+	lt, err := tables.LookupTable(ctx, "customers", "")
+	require.NoError(t, err)
+	require.Equal(t, v1, lt.Version)
+	require.Equal(t, [][]string{{"1", "gold"}}, lt.Rows)
+
+	expr := syntax.MustParseExpr(`count_over_time({app="foo"} | logfmt | lookup customers on customer_id [5m])`)
+	pinned, err := tables.PinLookupTables(ctx, expr)
+	require.NoError(t, err)
+	require.Equal(t, `count_over_time({app="foo"} | logfmt | lookup customers on customer_id version "`+v1+`"[5m])`, pinned.String())
+	require.Equal(t, `count_over_time({app="foo"} | logfmt | lookup customers on customer_id[5m])`, expr.String())
+
+	// Another querier only sees the new version once its cached latest
+	// version expires.
+	other := testTables(t)
+	other.store = tables.store
+	other.now = tables.now
+	v2, err := other.put(ctx, "tenant-a", "customers", table{Columns: []string{"customer_id", "tier"}, Rows: [][]string{{"1", "platinum"}}})
+	require.NoError(t, err)
+	require.NotEqual(t, v1, v2)
+
+	lt, err = tables.LookupTable(ctx, "customers", "")
+	require.NoError(t, err)
+	require.Equal(t, v1, lt.Version)
+	now = now.Add(2 * time.Minute)
+	lt, err = tables.LookupTable(ctx, "customers", "")
+	require.NoError(t, err)
+	require.Equal(t, v2, lt.Version)
+
+	// Pinned versions remain available.
+	lt, err = tables.LookupTable(ctx, "customers", v1)
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"1", "gold"}}, lt.Rows)
+
+	// Tables are per tenant.
+	_, err = tables.LookupTable(user.InjectOrgID(context.Background(), "tenant-b"), "customers", "")
+	require.EqualError(t, err, "rpc error: code = Code(400) desc = lookup table customers not found")
+	_, err = tables.PinLookupTables(user.InjectOrgID(context.Background(), "tenant-b"), expr)
+	require.EqualError(t, err, "rpc error: code = Code(400) desc = lookup table customers not found")
+	// Queries of multiple tenants can't use lookup tables.
+	multiTenant := user.InjectOrgID(context.Background(), "tenant-a|tenant-b")
+	_, err = tables.LookupTable(multiTenant, "customers", "")
+	require.EqualError(t, err, "rpc error: code = Code(400) desc = lookup tables cannot be used in queries of multiple tenants")
+	_, err = tables.PinLookupTables(multiTenant, expr)
+	require.EqualError(t, err, "rpc error: code = Code(400) desc = lookup tables cannot be used in queries of multiple tenants")
+
+	// Names and versions cannot address the tables of another tenant.
+	vb, err := tables.put(ctx, "tenant-b", "customers", table{Columns: []string{"customer_id", "tier"}, Rows: [][]string{{"1", "bronze"}}})
+	require.NoError(t, err)
+	for _, tc := range []struct{ name, version string }{
+		{"customers", "../../tenant-b/customers/" + vb},
+		{"customers", "../tenant-b/customers/" + vb},
+		{"../tenant-b/customers", vb},
+		{"../tenant-b/customers", ""},
+		{"customers", strings.ToUpper(vb)},
+	} {
+		_, err = tables.LookupTable(ctx, tc.name, tc.version)
+		require.EqualError(t, err, "rpc error: code = Code(400) desc = invalid lookup table name '"+tc.name+"' or version '"+tc.version+"'", tc)
+		_, err = tables.store.Get(ctx, "tenant-a", tc.name, tc.version)
+		require.ErrorIs(t, err, ErrInvalidKey, tc)
+	}
+	_, err = tables.LookupTable(ctx, "customers", vb)
+	require.EqualError(t, err, "rpc error: code = Code(400) desc = lookup table customers version "+vb+" not found")
+	require.ErrorIs(t, tables.delete(ctx, "tenant-a", "../tenant-b/customers"), ErrInvalidKey)
+
+	require.NoError(t, tables.delete(ctx, "tenant-a", "customers"))
+	_, err = tables.LookupTable(ctx, "customers", "")
+	require.Error(t, err)
+	_, err = tables.store.Get(ctx, "tenant-a", "customers", v1)
+	require.ErrorIs(t, err, ErrTableNotFound)
+}
+
+func TestHandlers(t *testing.T) {
+	tables := testTables(t)
+	router := mux.NewRouter()
+	router.Path("/loki/api/v1/lookup_tables").Methods("GET").HandlerFunc(tables.ListHandler)
+	router.Path("/loki/api/v1/lookup_tables/{name}").Methods("GET").HandlerFunc(tables.GetHandler)
+	router.Path("/loki/api/v1/lookup_tables/{name}").Methods("PUT").HandlerFunc(tables.PutHandler)
+	router.Path("/loki/api/v1/lookup_tables/{name}").Methods("DELETE").HandlerFunc(tables.DeleteHandler)
+
+	do := func(method, path, contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req = req.WithContext(user.InjectOrgID(req.Context(), "tenant-a"))
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("PUT", "/loki/api/v1/lookup_tables/customers", "text/csv; charset=utf-8", "customer_id,tier\n1,gold\n")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	version := tables.latest["tenant-a/customers"].version
+	require.JSONEq(t, `{"name": "customers", "version": "`+version+`"}`, w.Body.String())
+
+	w = do("GET", "/loki/api/v1/lookup_tables", "", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.JSONEq(t, `{"tables": [{"name": "customers", "version": "`+version+`"}]}`, w.Body.String())
+
+	w = do("GET", "/loki/api/v1/lookup_tables/customers", "", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.JSONEq(t, `{"name": "customers", "version": "`+version+`", "columns": ["customer_id", "tier"], "rows": [["1", "gold"]]}`, w.Body.String())
+
+	for _, v := range []string{"..%2F..%2Ftenant-b%2Fcustomers%2F" + version, "0f3a", strings.ToUpper(version)} {
+		w = do("GET", "/loki/api/v1/lookup_tables/customers?version="+v, "", "")
+		require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	}
+
+	w = do("PUT", "/loki/api/v1/lookup_tables/customers", "text/csv", "customer_id,tier\n"+strings.Repeat("1,gold\n", 200))
+	require.Equal(t, http.StatusRequestEntityTooLarge, w.Code, w.Body.String())
+
+	w = do("PUT", "/loki/api/v1/lookup_tables/customers", "text/csv", "customer_id,tier\n1\n")
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+	w = do("PUT", "/loki/api/v1/lookup_tables/my-customers", "text/csv", "customer_id,tier\n1,gold\n")
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+	w = do("DELETE", "/loki/api/v1/lookup_tables/customers", "", "")
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	w = do("DELETE", "/loki/api/v1/lookup_tables/customers", "", "")
+	require.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+	w = do("GET", "/loki/api/v1/lookup_tables/customers", "", "")
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+}
@@ -0,0 +1,151 @@
+package lookup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/prometheus/common/model"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/loki/v3/pkg/logql/log"
+)
+
+const (
+	fileExtension = ".json"
+	latestFile    = "latest"
+)
+
+var (
+	// ErrTableNotFound is returned for a table or a version which does not exist.
+	ErrTableNotFound = errors.New("lookup table not found")
+	// ErrInvalidKey is returned for a table name or a version which is not
+	// well-formed, so that it cannot address the objects of another table or
+	// tenant.
+	ErrInvalidKey = errors.New("invalid lookup table name or version")
+)
+
+// Store stores versioned lookup tables of tenants in object storage. Versions
+// are stored under <tenant>/<name>/<version>.json and never modified, the
+// <tenant>/<name>/latest object holds the latest version of a table.
+type Store struct {
+	bucket objstore.Bucket
+}
+
+// NewStore creates a new Store on top of bucket.
+func NewStore(bucket objstore.Bucket) *Store {
+	return &Store{bucket: bucket}
+}
+
+func versionKey(tenant, name, version string) (string, error) {
+	if !model.LabelName(name).IsValidLegacy() || !log.ValidLookupVersion(version) {
+		return "", ErrInvalidKey
+	}
+	return path.Join(tenant, name, version+fileExtension), nil
+}
+
+func latestKey(tenant, name string) (string, error) {
+	if !model.LabelName(name).IsValidLegacy() {
+		return "", ErrInvalidKey
+	}
+	return path.Join(tenant, name, latestFile), nil
+}
+
+// Put uploads a new version of a table and makes it the latest one.
+func (s *Store) Put(ctx context.Context, tenant, name string, t table) (string, error) {
+	raw, version, err := t.encode()
+	if err != nil {
+		return "", err
+	}
+	key, err := versionKey(tenant, name, version)
+	if err != nil {
+		return "", err
+	}
+	latest, err := latestKey(tenant, name)
+	if err != nil {
+		return "", err
+	}
+	if err := s.bucket.Upload(ctx, key, bytes.NewReader(raw)); err != nil {
+		return "", err
+	}
+	return version, s.bucket.Upload(ctx, latest, strings.NewReader(version))
+}
+
+// Get downloads a version of a table.
+func (s *Store) Get(ctx context.Context, tenant, name, version string) (table, error) {
+	key, err := versionKey(tenant, name, version)
+	if err != nil {
+		return table{}, err
+	}
+	raw, err := s.get(ctx, key)
+	if err != nil {
+		return table{}, err
+	}
+	t, err := decodeTable(raw)
+	if err != nil {
+		return table{}, fmt.Errorf("invalid lookup table %s version %s: %w", name, version, err)
+	}
+	return t, nil
+}
+
+// Latest returns the latest version of a table.
+func (s *Store) Latest(ctx context.Context, tenant, name string) (string, error) {
+	key, err := latestKey(tenant, name)
+	if err != nil {
+		return "", err
+	}
+	raw, err := s.get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	if !log.ValidLookupVersion(string(raw)) {
+		return "", fmt.Errorf("invalid latest version of lookup table %s", name)
+	}
+	return string(raw), nil
+}
+
+// List returns the names of the tables of tenant.
+func (s *Store) List(ctx context.Context, tenant string) ([]string, error) {
+	var names []string
+	err := s.bucket.Iter(ctx, tenant+"/", func(key string) error {
+		name, file := path.Split(strings.TrimPrefix(key, tenant+"/"))
+		if file == latestFile {
+			names = append(names, strings.TrimSuffix(name, "/"))
+		}
+		return nil
+	}, objstore.WithRecursiveIter())
+	return names, err
+}
+
+// Delete deletes all versions of a table.
+func (s *Store) Delete(ctx context.Context, tenant, name string) error {
+	key, err := latestKey(tenant, name)
+	if err != nil {
+		return err
+	}
+	if err := s.bucket.Delete(ctx, key); err != nil {
+		if s.bucket.IsObjNotFoundErr(err) {
+			return ErrTableNotFound
+		}
+		return err
+	}
+	return s.bucket.Iter(ctx, path.Join(tenant, name)+"/", func(key string) error {
+		return s.bucket.Delete(ctx, key)
+	})
+}
+
+func (s *Store) get(ctx context.Context, key string) ([]byte, error) {
+	rc, err := s.bucket.Get(ctx, key)
+	if err != nil {
+		if s.bucket.IsObjNotFoundErr(err) {
+			return nil, ErrTableNotFound
+		}
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
@@ -0,0 +1,143 @@
+package lookup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/v3/pkg/logql/log"
+)
+
+// versionLength is the number of hexadecimal characters of a table version.
+const versionLength = log.LookupVersionLength
+
+// table is the stored representation of a lookup table.
+type table struct {
+	Columns []string   `json:"columns"`
+	Rows    [][]string `json:"rows"`
+}
+
+// encode returns the stored representation of t and its version, derived from
+// its content so that uploading the same table twice yields the same version.
+func (t table) encode() ([]byte, string, error) {
+	raw, err := json.Marshal(t)
+	if err != nil {
+		return nil, "", err
+	}
+	sum := sha256.Sum256(raw)
+	return raw, hex.EncodeToString(sum[:])[:versionLength], nil
+}
+
+func (t table) validate() error {
+	if len(t.Columns) == 0 {
+		return errors.New("a lookup table must have at least one column")
+	}
+	seen := make(map[string]struct{}, len(t.Columns))
+	for _, c := range t.Columns {
+		if !model.LabelName(c).IsValidLegacy() {
+			return fmt.Errorf("invalid column name '%s'", c)
+		}
+		if _, ok := seen[c]; ok {
+			return fmt.Errorf("duplicate column '%s'", c)
+		}
+		seen[c] = struct{}{}
+	}
+	for i, row := range t.Rows {
+		if len(row) != len(t.Columns) {
+			return fmt.Errorf("row %d has %d values, expected %d", i+1, len(row), len(t.Columns))
+		}
+	}
+	return nil
+}
+
+func (t table) lookupTable(name, version string) *log.LookupTable {
+	return log.NewLookupTable(name, version, t.Columns, t.Rows)
+}
+
+func decodeTable(raw []byte) (table, error) {
+	var t table
+	if err := json.Unmarshal(raw, &t); err != nil {
+		return table{}, err
+	}
+	return t, t.validate()
+}
+
+// parseCSV parses a lookup table from csv. The first record names the
+// columns.
+func parseCSV(r io.Reader) (table, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return table{}, err
+	}
+	if len(records) == 0 {
+		return table{}, errors.New("missing csv header")
+	}
+	t := table{Columns: records[0], Rows: records[1:]}
+	return t, t.validate()
+}
+
+// parseJSON parses a lookup table from a json array of objects whose fields
+// are the columns of a row. Columns are sorted by name and missing fields are
+// empty.
+func parseJSON(r io.Reader) (table, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var objects []map[string]any
+	if err := dec.Decode(&objects); err != nil {
+		return table{}, err
+	}
+
+	var t table
+	for _, o := range objects {
+		for c := range o {
+			if !slices.Contains(t.Columns, c) {
+				t.Columns = append(t.Columns, c)
+			}
+		}
+	}
+	slices.Sort(t.Columns)
+
+	t.Rows = make([][]string, 0, len(objects))
+	for i, o := range objects {
+		row := make([]string, len(t.Columns))
+		for j, c := range t.Columns {
+			v, ok := o[c]
+			if !ok || v == nil {
+				continue
+			}
+			switch v := v.(type) {
+			case string:
+				row[j] = v
+			case json.Number:
+				row[j] = v.String()
+			case bool:
+				row[j] = strconv.FormatBool(v)
+			default:
+				return table{}, fmt.Errorf("row %d: value of column '%s' must be a string, a number or a boolean", i+1, c)
+			}
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t, t.validate()
+}
+
+// parseTable parses an uploaded lookup table of the given content type.
+func parseTable(contentType string, raw []byte) (table, error) {
+	switch contentType {
+	case "text/csv":
+		return parseCSV(bytes.NewReader(raw))
+	case "application/json":
+		return parseJSON(bytes.NewReader(raw))
+	default:
+		return table{}, fmt.Errorf("unsupported content type '%s', expected text/csv or application/json", contentType)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/util/httpreq"
 
 	lokilog "github.com/grafana/loki/v3/pkg/logql/log"
+	"github.com/grafana/loki/v3/pkg/logql/syntax"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
@@ -343,7 +344,11 @@ func decodeReq(req logql.QueryParams) ([]*labels.Matcher, model.Time, model.Time
 		return nil, 0, 0, err
 	}
 	matchers = append(matchers, nameLabelMatcher)
-	matchers, err = injectShardLabel(req.GetShards(), matchers)
+	shards, _, err := logql.DedupShards(req.GetShards(), expr)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	matchers, err = injectShardLabel(shards, matchers)
 	if err != nil {
 		return nil, 0, 0, err
 	}
@@ -351,6 +356,22 @@ func decodeReq(req logql.QueryParams) ([]*labels.Matcher, model.Time, model.Time
 	return matchers, from, through, nil
 }
 
+// dedupShard returns the chunks precomputed for the shard of req, if any, and
+// for queries with a dedup stage, a function keeping the streams of the shard
+// of req. The shards of such queries are not selected in the index, so their
+// precomputed chunks cannot be used, see logql.DedupShards.
+func dedupShard(req logql.QueryParams, storeChunks *logproto.ChunkRefGroup) (*logproto.ChunkRefGroup, func(labels.Labels) bool, error) {
+	expr, err := req.LogSelector()
+	if err != nil {
+		return nil, nil, err
+	}
+	_, keep, err := logql.DedupShards(req.GetShards(), expr)
+	if err != nil || keep == nil {
+		return storeChunks, nil, err
+	}
+	return nil, keep, nil
+}
+
 // TODO(owen-d): refactor this. Injecting shard labels via matchers is a big hack and we shouldn't continue
 // doing it, _but_ it requires adding `fingerprintfilter` support to much of our storage interfaces
 // or a way to transform the base store into a more specialized variant.
@@ -483,7 +504,12 @@ func (s *LokiStore) SelectLogs(ctx context.Context, req logql.SelectLogParams) (
 		return nil, err
 	}
 
-	lazyChunks, err := s.lazyChunks(ctx, from, through, chunk.NewPredicate(matchers, req.Plan), req.GetStoreChunks())
+	storeChunks, keep, err := dedupShard(req, req.GetStoreChunks())
+	if err != nil {
+		return nil, err
+	}
+
+	lazyChunks, err := s.lazyChunks(ctx, from, through, chunk.NewPredicate(matchers, req.Plan), storeChunks)
 	if err != nil {
 		return nil, err
 	}
@@ -496,6 +522,14 @@ func (s *LokiStore) SelectLogs(ctx context.Context, req logql.SelectLogParams) (
 	if err != nil {
 		return nil, err
 	}
+	expr, err = syntax.BindLookupTables(ctx, expr, s.cfg.LookupTableProvider)
+	if err != nil {
+		return nil, err
+	}
+	expr, err = syntax.BindRedaction(ctx, expr, s.cfg.RedactionLimits)
+	if err != nil {
+		return nil, err
+	}
 
 	pipeline, err := expr.Pipeline()
 	if err != nil {
@@ -507,6 +541,10 @@ func (s *LokiStore) SelectLogs(ctx context.Context, req logql.SelectLogParams) (
 		return nil, err
 	}
 
+	if keep != nil {
+		pipeline = lokilog.NewStreamFilteringPipeline(keep, pipeline)
+	}
+
 	if s.pipelineWrapper != nil && httpreq.ExtractHeader(ctx, httpreq.LokiDisablePipelineWrappersHeader) != "true" {
 		userID, err := tenant.TenantID(ctx)
 		if err != nil {
@@ -530,7 +568,12 @@ func (s *LokiStore) SelectSamples(ctx context.Context, req logql.SelectSamplePar
 		return nil, err
 	}
 
-	lazyChunks, err := s.lazyChunks(ctx, from, through, chunk.NewPredicate(matchers, req.Plan), req.GetStoreChunks())
+	storeChunks, keep, err := dedupShard(req, req.GetStoreChunks())
+	if err != nil {
+		return nil, err
+	}
+
+	lazyChunks, err := s.lazyChunks(ctx, from, through, chunk.NewPredicate(matchers, req.Plan), storeChunks)
 	if err != nil {
 		return nil, err
 	}
@@ -543,6 +586,14 @@ func (s *LokiStore) SelectSamples(ctx context.Context, req logql.SelectSamplePar
 	if err != nil {
 		return nil, err
 	}
+	expr, err = syntax.BindLookupTables(ctx, expr, s.cfg.LookupTableProvider)
+	if err != nil {
+		return nil, err
+	}
+	expr, err = syntax.BindRedaction(ctx, expr, s.cfg.RedactionLimits)
+	if err != nil {
+		return nil, err
+	}
 
 	extractors, err := expr.Extractors()
 	if err != nil {
@@ -555,6 +606,10 @@ func (s *LokiStore) SelectSamples(ctx context.Context, req logql.SelectSamplePar
 			return nil, err
 		}
 
+		if keep != nil {
+			extractor = lokilog.NewStreamFilteringSampleExtractor(keep, extractor)
+		}
+
 		if s.extractorWrapper != nil &&
 			httpreq.ExtractHeader(ctx, httpreq.LokiDisablePipelineWrappersHeader) != "true" {
 			userID, err := tenant.TenantID(ctx)
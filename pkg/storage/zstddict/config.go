@@ -0,0 +1,50 @@
+package zstddict
+
+import (
+	"errors"
+	"flag"
+	"time"
+
+	"github.com/grafana/loki/v3/pkg/util/flagext"
+)
+
+// Config configures training, storing and loading per-tenant zstd
+// dictionaries.
+type Config struct {
+	Enabled        bool             `yaml:"enabled"`
+	StoragePrefix  string           `yaml:"storage_prefix"`
+	SyncInterval   time.Duration    `yaml:"sync_interval"`
+	TrainInterval  time.Duration    `yaml:"train_interval"`
+	MaxSamples     int              `yaml:"max_samples"`
+	MinSamples     int              `yaml:"min_samples"`
+	DictionarySize flagext.ByteSize `yaml:"dictionary_size"`
+}
+
+// RegisterFlagsWithPrefix registers flags with the given prefix.
+func (cfg *Config) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, prefix+"enabled", false, "Experimental: Enable per-tenant zstd dictionaries. Dictionaries are trained from log lines sampled by the ingester owning each tenant in the ring and stored in object storage. Chunks using the zstd-dict encoding are compressed with the latest dictionary of their tenant. When retention is enabled, the compactor deletes the dictionaries replaced for longer than the retention period of their tenant.")
+	f.StringVar(&cfg.StoragePrefix, prefix+"storage-prefix", "zstd-dictionaries/", "Prefix of the object storage path under which dictionaries are stored.")
+	f.DurationVar(&cfg.SyncInterval, prefix+"sync-interval", 5*time.Minute, "How often to list the dictionaries in object storage. New dictionaries are used for compression two sync intervals after they were stored, so that every reader knows about them. Dictionaries are downloaded when data compressed with them is read.")
+	f.DurationVar(&cfg.TrainInterval, prefix+"train-interval", 24*time.Hour, "How often to train new dictionaries from sampled log lines. 0 disables training.")
+	f.IntVar(&cfg.MaxSamples, prefix+"max-samples", 10000, "Maximum number of log lines sampled per tenant between two trainings.")
+	f.IntVar(&cfg.MinSamples, prefix+"min-samples", 1000, "Minimum number of log lines sampled for a tenant to train a dictionary.")
+	_ = cfg.DictionarySize.Set("64KB")
+	f.Var(&cfg.DictionarySize, prefix+"dictionary-size", "Maximum size of a trained dictionary.")
+}
+
+// Validate validates the config.
+func (cfg *Config) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.SyncInterval <= 0 {
+		return errors.New("sync interval must be greater than 0")
+	}
+	if cfg.MinSamples <= 0 || cfg.MinSamples > cfg.MaxSamples {
+		return errors.New("min samples must be greater than 0 and not exceed max samples")
+	}
+	if cfg.DictionarySize < 1<<10 {
+		return errors.New("dictionary size must be at least 1KB")
+	}
+	return nil
+}
@@ -0,0 +1,484 @@
+package zstddict
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math/rand"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/ring"
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/loki/v3/pkg/compression"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	lokiring "github.com/grafana/loki/v3/pkg/util/ring"
+	"github.com/grafana/loki/v3/pkg/validation"
+)
+
+const (
+	// evaluationBlockSize is the size of the blocks used to compare the
+	// compression with and without a newly trained dictionary.
+	evaluationBlockSize = 64 << 10
+	// holdoutEvery is the fraction of samples kept aside to evaluate a newly
+	// trained dictionary.
+	holdoutEvery = 10
+	// usableAfterSyncs is the number of sync intervals after which a stored
+	// dictionary is used for compression. Readers only learn about new
+	// dictionaries when they sync, and fail to decompress chunks compressed
+	// with a dictionary they do not know.
+	usableAfterSyncs = 2
+	// retireInterval is how often replaced dictionaries are looked for
+	// deletion.
+	retireInterval = time.Hour
+	// retireDelay is added to the retention period of a tenant before deleting
+	// a replaced dictionary, which covers chunks flushed after the dictionary
+	// was replaced and the delay of the retention itself.
+	retireDelay = 24 * time.Hour
+	// loadTimeout bounds the download of a dictionary.
+	loadTimeout = time.Minute
+)
+
+// RetentionLimits are the limits used to retire dictionaries which no stored
+// data is compressed with anymore.
+type RetentionLimits interface {
+	RetentionPeriod(userID string) time.Duration
+	StreamRetention(userID string) []validation.StreamRetention
+}
+
+type metrics struct {
+	dictionaries prometheus.Gauge
+	trainings    *prometheus.CounterVec
+	gain         *prometheus.GaugeVec
+	retired      prometheus.Counter
+}
+
+func newMetrics(r prometheus.Registerer) *metrics {
+	return &metrics{
+		dictionaries: promauto.With(r).NewGauge(prometheus.GaugeOpts{
+			Namespace: "loki",
+			Name:      "zstd_dictionaries_stored",
+			Help:      "Number of zstd dictionaries stored in object storage.",
+		}),
+		trainings: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "zstd_dictionary_trainings_total",
+			Help:      "Total number of zstd dictionary trainings by status.",
+		}, []string{"status"}),
+		gain: promauto.With(r).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "loki",
+			Name:      "zstd_dictionary_compression_ratio_gain",
+			Help:      "Compression ratio with the latest trained dictionary of a tenant divided by the compression ratio without it.",
+		}, []string{"tenant"}),
+		retired: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "zstd_dictionaries_retired_total",
+			Help:      "Total number of replaced zstd dictionaries deleted after the retention period of their tenant.",
+		}),
+	}
+}
+
+type tenantDictionary struct {
+	ref Ref
+	// usableAt is the time after which all readers know the dictionary.
+	usableAt time.Time
+
+	dictionary atomic.Pointer[compression.Dictionary]
+	loading    atomic.Bool
+}
+
+// Manager indexes the dictionaries of all tenants stored in object storage,
+// and loads them when data compressed with them is read. It also trains new
+// dictionaries from the log lines passed to Sample.
+type Manager struct {
+	services.Service
+
+	cfg     Config
+	store   *Store
+	sampler *Sampler
+	logger  log.Logger
+	metrics *metrics
+
+	mtx sync.RWMutex
+	// stored indexes the stored dictionaries by ID.
+	stored map[uint32]Ref
+	// latest holds the dictionaries of every tenant used for compression from
+	// the oldest to the newest version. Only the newest usable dictionary and
+	// the ones after it are kept.
+	latest      map[string][]*tenantDictionary
+	lastTrained time.Time
+	lastRetired time.Time
+
+	// owns returns whether the Manager trains the dictionaries of a tenant,
+	// see TrainOwnedTenants. The result is cached until the next sync.
+	owns  func(tenant string) bool
+	owned sync.Map
+	// retention enables retiring replaced dictionaries, see RetireDictionaries.
+	retention RetentionLimits
+
+	now func() time.Time
+}
+
+// NewManager creates a new Manager storing dictionaries in bucket.
+func NewManager(cfg Config, bucket objstore.Bucket, logger log.Logger, r prometheus.Registerer) *Manager {
+	m := &Manager{
+		cfg:         cfg,
+		store:       NewStore(bucket),
+		sampler:     NewSampler(cfg.MaxSamples),
+		logger:      logger,
+		metrics:     newMetrics(r),
+		stored:      map[uint32]Ref{},
+		latest:      map[string][]*tenantDictionary{},
+		lastTrained: time.Now(),
+		now:         time.Now,
+	}
+	m.Service = services.NewTimerService(cfg.SyncInterval, m.starting, m.iteration, nil)
+	return m
+}
+
+// TrainOwnedTenants restricts sampling and training to the tenants for which
+// owns returns true, so that every tenant has a single trainer. It must be
+// called before the Manager is started.
+func (m *Manager) TrainOwnedTenants(owns func(tenant string) bool) {
+	m.owns = owns
+}
+
+// RetireDictionaries enables deleting the dictionaries which were replaced by
+// a newer version for longer than the retention period of their tenant. It
+// must be called before the Manager is started.
+func (m *Manager) RetireDictionaries(limits RetentionLimits) {
+	m.retention = limits
+}
+
+// RingOwner returns whether the instance with the given ID is the first
+// replica of a tenant in the ring, so that a single instance of the ring
+// trains the dictionaries of each tenant.
+func RingOwner(r ring.ReadRing, instanceID string) func(tenant string) bool {
+	return func(tenant string) bool {
+		rs, err := r.Get(lokiring.TokenFor(tenant, ""), ring.WriteNoExtend, nil, nil, nil)
+		if err != nil || len(rs.Instances) == 0 {
+			return false
+		}
+		return rs.Instances[0].Id == instanceID
+	}
+}
+
+func (m *Manager) starting(ctx context.Context) error {
+	compression.SetDictionaryLoader(m.load)
+	// Dictionaries must be indexed before any chunk referencing them is read.
+	return m.sync(ctx)
+}
+
+func (m *Manager) iteration(ctx context.Context) error {
+	if err := m.sync(ctx); err != nil {
+		level.Error(m.logger).Log("msg", "failed to list zstd dictionaries", "err", err)
+	}
+	m.owned.Clear()
+	if m.cfg.TrainInterval > 0 && time.Since(m.lastTrained) >= m.cfg.TrainInterval {
+		m.train(ctx)
+		m.lastTrained = time.Now()
+	}
+	if m.retention != nil && time.Since(m.lastRetired) >= retireInterval {
+		if err := m.retire(ctx); err != nil {
+			level.Error(m.logger).Log("msg", "failed to retire zstd dictionaries", "err", err)
+		}
+		m.lastRetired = time.Now()
+	}
+	return nil
+}
+
+// Dictionary returns the latest dictionary of tenant which all readers had
+// the time to learn about, or nil if there is none. The dictionary is
+// downloaded in the background the first time, and data is compressed without
+// a dictionary until then.
+func (m *Manager) Dictionary(tenant string) *compression.Dictionary {
+	m.mtx.RLock()
+	var latest *tenantDictionary
+	now := m.now()
+	for i := len(m.latest[tenant]) - 1; i >= 0; i-- {
+		if !now.Before(m.latest[tenant][i].usableAt) {
+			latest = m.latest[tenant][i]
+			break
+		}
+	}
+	m.mtx.RUnlock()
+
+	if latest == nil {
+		return nil
+	}
+	if d := latest.dictionary.Load(); d != nil {
+		return d
+	}
+	if latest.loading.CompareAndSwap(false, true) {
+		go func() {
+			defer latest.loading.Store(false)
+			d, err := compression.LoadDictionary(latest.ref.ID)
+			if err != nil {
+				level.Warn(m.logger).Log("msg", "failed to load zstd dictionary", "tenant", tenant, "version", latest.ref.Version, "id", latest.ref.ID, "err", err)
+				return
+			}
+			latest.dictionary.Store(d)
+		}()
+	}
+	return nil
+}
+
+// Sample offers log lines of tenant for training its next dictionary.
+func (m *Manager) Sample(tenant string, entries []logproto.Entry) {
+	if !m.trains(tenant) {
+		return
+	}
+	m.sampler.Sample(tenant, entries)
+}
+
+// trains returns whether the Manager trains the dictionaries of tenant.
+func (m *Manager) trains(tenant string) bool {
+	if m.owns == nil {
+		return true
+	}
+	if owned, ok := m.owned.Load(tenant); ok {
+		return owned.(bool)
+	}
+	owned := m.owns(tenant)
+	m.owned.Store(tenant, owned)
+	return owned
+}
+
+// load downloads a stored dictionary by ID, it is the loader of the
+// dictionaries that aren't cached for decompression.
+func (m *Manager) load(id uint32) (*compression.Dictionary, error) {
+	m.mtx.RLock()
+	ref, ok := m.stored[id]
+	m.mtx.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("zstd dictionary %d is not stored", id)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), loadTimeout)
+	defer cancel()
+	return m.store.Get(ctx, ref)
+}
+
+// sync indexes the stored dictionaries and looks for new versions to use for
+// compression. Dictionaries are only downloaded when needed.
+func (m *Manager) sync(ctx context.Context) error {
+	refs, err := m.store.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	stored := make(map[uint32]Ref, len(refs))
+	for _, ref := range refs {
+		stored[ref.ID] = ref
+	}
+	m.mtx.Lock()
+	m.stored = stored
+	m.mtx.Unlock()
+	m.metrics.dictionaries.Set(float64(len(refs)))
+
+	for tenant, refs := range byTenant(refs) {
+		// Only the newest usable version and the ones after it are used, which
+		// are found from the newest version backwards.
+		var newer []Ref
+		var storedAt []time.Time
+		for i := len(refs) - 1; i >= 0 && m.isNewer(refs[i]); i-- {
+			at, err := m.store.StoredAt(ctx, refs[i])
+			if err != nil {
+				level.Warn(m.logger).Log("msg", "failed to get the upload time of zstd dictionary", "tenant", tenant, "version", refs[i].Version, "id", refs[i].ID, "err", err)
+				break
+			}
+			newer = append(newer, refs[i])
+			storedAt = append(storedAt, at)
+			if !m.now().Before(at.Add(usableAfterSyncs * m.cfg.SyncInterval)) {
+				break
+			}
+		}
+		for i := len(newer) - 1; i >= 0; i-- {
+			m.setLatest(newer[i], nil, storedAt[i])
+		}
+	}
+	return nil
+}
+
+// byTenant groups refs by tenant, from the oldest to the newest version.
+func byTenant(refs []Ref) map[string][]Ref {
+	grouped := map[string][]Ref{}
+	for _, ref := range refs {
+		grouped[ref.Tenant] = append(grouped[ref.Tenant], ref)
+	}
+	for _, refs := range grouped {
+		slices.SortFunc(refs, func(a, b Ref) int { return cmp.Compare(a.Version, b.Version) })
+	}
+	return grouped
+}
+
+// isNewer returns whether ref is newer than the known dictionaries of its
+// tenant.
+func (m *Manager) isNewer(ref Ref) bool {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+	return ref.Version > m.latestVersion(ref.Tenant)
+}
+
+func (m *Manager) latestVersion(tenant string) uint32 {
+	latest := m.latest[tenant]
+	if len(latest) == 0 {
+		return 0
+	}
+	return latest[len(latest)-1].ref.Version
+}
+
+// setLatest adds the dictionary stored at storedAt to the ones of its tenant
+// if it is newer than all of them. d is nil when the dictionary isn't loaded
+// yet.
+func (m *Manager) setLatest(ref Ref, d *compression.Dictionary, storedAt time.Time) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if ref.Version <= m.latestVersion(ref.Tenant) {
+		return
+	}
+
+	td := &tenantDictionary{
+		ref:      ref,
+		usableAt: storedAt.Add(usableAfterSyncs * m.cfg.SyncInterval),
+	}
+	if d != nil {
+		td.dictionary.Store(d)
+	}
+	latest := append(m.latest[ref.Tenant], td)
+	// Drop the dictionaries replaced by a usable one.
+	now := m.now()
+	for i := len(latest) - 1; i > 0; i-- {
+		if !now.Before(latest[i].usableAt) {
+			latest = latest[i:]
+			break
+		}
+	}
+	m.latest[ref.Tenant] = latest
+}
+
+func (m *Manager) nextRef(tenant string) Ref {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	ref := Ref{Tenant: tenant, Version: m.latestVersion(tenant) + 1}
+	for {
+		ref.ID = compression.MinDictionaryID + uint32(rand.Int63n(compression.MaxDictionaryID-compression.MinDictionaryID+1))
+		if _, ok := m.stored[ref.ID]; !ok {
+			return ref
+		}
+	}
+}
+
+// train trains and stores a new dictionary for every tenant with enough
+// samples, unless the dictionary does not improve compression.
+func (m *Manager) train(ctx context.Context) {
+	for tenant, samples := range m.sampler.Drain() {
+		if len(samples) < m.cfg.MinSamples || !m.trains(tenant) {
+			continue
+		}
+		if err := m.trainTenant(ctx, tenant, samples); err != nil {
+			m.metrics.trainings.WithLabelValues("failure").Inc()
+			level.Error(m.logger).Log("msg", "failed to train zstd dictionary", "tenant", tenant, "err", err)
+		}
+	}
+}
+
+func (m *Manager) trainTenant(ctx context.Context, tenant string, samples [][]byte) error {
+	var training, holdout [][]byte
+	for i, s := range samples {
+		if i%holdoutEvery == 0 {
+			holdout = append(holdout, s)
+		} else {
+			training = append(training, s)
+		}
+	}
+
+	ref := m.nextRef(tenant)
+	d, err := compression.TrainDictionary(ref.ID, training, int(m.cfg.DictionarySize))
+	if err != nil {
+		return err
+	}
+	report, err := Evaluate(d, holdout, evaluationBlockSize)
+	if err != nil {
+		return err
+	}
+	if report.Gain() <= 1 {
+		m.metrics.trainings.WithLabelValues("discarded").Inc()
+		level.Info(m.logger).Log("msg", "discarding zstd dictionary without compression gain", "tenant", tenant, "report", report)
+		return nil
+	}
+
+	if err := m.store.Put(ctx, ref, d); err != nil {
+		return err
+	}
+	if err := compression.RegisterDictionary(d); err != nil {
+		return err
+	}
+	m.mtx.Lock()
+	m.stored[ref.ID] = ref
+	m.mtx.Unlock()
+	m.setLatest(ref, d, m.now())
+
+	m.metrics.trainings.WithLabelValues("success").Inc()
+	m.metrics.gain.WithLabelValues(tenant).Set(report.Gain())
+	level.Info(m.logger).Log("msg", "trained zstd dictionary", "tenant", tenant, "version", ref.Version, "id", ref.ID, "report", report)
+	return nil
+}
+
+// retire deletes the dictionaries which were replaced by a newer usable
+// version for longer than the retention period of their tenant, as no stored
+// data is compressed with them anymore. The dictionaries of tenants without
+// retention are kept.
+func (m *Manager) retire(ctx context.Context) error {
+	refs, err := m.store.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	for tenant, refs := range byTenant(refs) {
+		retention := m.maxRetention(tenant)
+		if retention <= 0 {
+			continue
+		}
+		// The newest version is never replaced, and versions are replaced in
+		// order so the first one which isn't expired ends the search.
+		for i := 0; i < len(refs)-1; i++ {
+			replacedAt, err := m.store.StoredAt(ctx, refs[i+1])
+			if err != nil {
+				return err
+			}
+			replacedAt = replacedAt.Add(usableAfterSyncs * m.cfg.SyncInterval)
+			if m.now().Before(replacedAt.Add(retention + retireDelay)) {
+				break
+			}
+			if err := m.store.Delete(ctx, refs[i]); err != nil {
+				return err
+			}
+			m.metrics.retired.Inc()
+			level.Info(m.logger).Log("msg", "retired zstd dictionary", "tenant", tenant, "version", refs[i].Version, "id", refs[i].ID)
+		}
+	}
+	return nil
+}
+
+// maxRetention returns the longest retention period of the streams of tenant,
+// or 0 if they are never deleted.
+func (m *Manager) maxRetention(tenant string) time.Duration {
+	retention := m.retention.RetentionPeriod(tenant)
+	if retention <= 0 {
+		return 0
+	}
+	for _, r := range m.retention.StreamRetention(tenant) {
+		retention = max(retention, time.Duration(r.Period))
+	}
+	return retention
+}
@@ -0,0 +1,195 @@
+package zstddict
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/loki/v3/pkg/compression"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/grafana/loki/v3/pkg/validation"
+)
+
+func testEntries(n int) []logproto.Entry {
+	entries := make([]logproto.Entry, 0, n)
+	for i := 0; i < n; i++ {
+		entries = append(entries, logproto.Entry{
+			Timestamp: time.Unix(0, int64(i)),
+			Line:      fmt.Sprintf(`level=info ts=2025-01-01T00:00:%02dZ caller=handler.go:%d msg="request served" method=GET status=200 path=/api/v1/items/%d duration=%dms`, i%60, i%17, i, i%300),
+		})
+	}
+	return entries
+}
+
+func TestRef(t *testing.T) {
+	ref := Ref{Tenant: "tenant-a", Version: 3, ID: compression.MinDictionaryID}
+	require.Equal(t, "tenant-a/0000000003-0000032768.zdict", ref.key())
+
+	parsed, ok := parseRef(ref.key())
+	require.True(t, ok)
+	require.Equal(t, ref, parsed)
+
+	for _, key := range []string{"0000000003-0000032768.zdict", "tenant-a/foo.zdict", "tenant-a/0000000003-0000032768.txt"} {
+		_, ok := parseRef(key)
+		require.False(t, ok, key)
+	}
+}
+
+func TestSampler(t *testing.T) {
+	s := NewSampler(100)
+	s.Sample("a", testEntries(1000))
+	s.Sample("b", testEntries(10))
+
+	samples := s.Drain()
+	require.Len(t, samples["a"], 100)
+	require.Len(t, samples["b"], 10)
+	require.Empty(t, s.Drain())
+}
+
+func TestManager(t *testing.T) {
+	cfg := Config{
+		Enabled:        true,
+		SyncInterval:   time.Minute,
+		TrainInterval:  time.Hour,
+		MaxSamples:     2000,
+		MinSamples:     100,
+		DictionarySize: 16 << 10,
+	}
+	bucket := objstore.NewInMemBucket()
+	ctx := context.Background()
+
+	now := time.Now()
+	trainer := NewManager(cfg, bucket, log.NewNopLogger(), prometheus.NewRegistry())
+	trainer.now = func() time.Time { return now }
+	trainer.TrainOwnedTenants(func(tenant string) bool { return tenant != "tenant-c" })
+	trainer.Sample("tenant-a", testEntries(2000))
+	trainer.Sample("tenant-b", testEntries(10))
+	trainer.Sample("tenant-c", testEntries(2000))
+	trainer.train(ctx)
+
+	// New dictionaries are used once every reader had the time to learn about
+	// them.
+	require.Nil(t, trainer.Dictionary("tenant-a"))
+	now = now.Add(2 * cfg.SyncInterval)
+	d := trainer.Dictionary("tenant-a")
+	require.NotNil(t, d)
+	require.Nil(t, trainer.Dictionary("tenant-b"), "not enough samples")
+	require.Nil(t, trainer.Dictionary("tenant-c"), "not owned")
+
+	refs, err := trainer.store.List(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []Ref{{Tenant: "tenant-a", Version: 1, ID: d.ID}}, refs)
+
+	// A second training creates a new version.
+	trainer.Sample("tenant-a", testEntries(2000))
+	trainer.train(ctx)
+	require.Same(t, d, trainer.Dictionary("tenant-a"))
+
+	now = now.Add(2 * cfg.SyncInterval)
+	latest := trainer.Dictionary("tenant-a")
+	require.NotEqual(t, d.ID, latest.ID)
+
+	// Another instance indexes the dictionaries of every tenant, and uses them
+	// after the same delay since they were stored.
+	reader := NewManager(cfg, bucket, log.NewNopLogger(), prometheus.NewRegistry())
+	require.NoError(t, reader.sync(ctx))
+	require.Nil(t, reader.Dictionary("tenant-a"))
+	reader.now = func() time.Time { return time.Now().Add(2*cfg.SyncInterval + time.Second) }
+	require.Eventually(t, func() bool {
+		d := reader.Dictionary("tenant-a")
+		return d != nil && d.ID == latest.ID
+	}, time.Second, 10*time.Millisecond)
+
+	// Instances starting later only look at the newest usable version.
+	late := NewManager(cfg, bucket, log.NewNopLogger(), prometheus.NewRegistry())
+	late.now = reader.now
+	require.NoError(t, late.sync(ctx))
+	require.Len(t, late.latest["tenant-a"], 1)
+
+	// All stored dictionaries can be loaded for decompression.
+	loaded, err := reader.load(d.ID)
+	require.NoError(t, err)
+	require.Equal(t, d.Bytes(), loaded.Bytes())
+	_, err = reader.load(compression.MinDictionaryID - 1)
+	require.Error(t, err)
+}
+
+type fakeRetentionLimits map[string]time.Duration
+
+func (l fakeRetentionLimits) RetentionPeriod(userID string) time.Duration {
+	return l[userID]
+}
+
+func (l fakeRetentionLimits) StreamRetention(userID string) []validation.StreamRetention {
+	if userID == "tenant-c" {
+		return []validation.StreamRetention{{Period: model.Duration(48 * time.Hour)}}
+	}
+	return nil
+}
+
+func TestManager_Retire(t *testing.T) {
+	cfg := Config{SyncInterval: time.Minute}
+	bucket := objstore.NewInMemBucket()
+	ctx := context.Background()
+
+	m := NewManager(cfg, bucket, log.NewNopLogger(), prometheus.NewRegistry())
+	m.RetireDictionaries(fakeRetentionLimits{"tenant-a": 24 * time.Hour, "tenant-c": 24 * time.Hour})
+
+	var samples [][]byte
+	for _, e := range testEntries(500) {
+		samples = append(samples, []byte(e.Line))
+	}
+	id := uint32(compression.MinDictionaryID + 100)
+	for _, tenant := range []string{"tenant-a", "tenant-b", "tenant-c"} {
+		for version := uint32(1); version <= 3; version++ {
+			d, err := compression.TrainDictionary(id, samples, 4<<10)
+			require.NoError(t, err)
+			require.NoError(t, m.store.Put(ctx, Ref{Tenant: tenant, Version: version, ID: id}, d))
+			id++
+		}
+	}
+	versions := func() map[string][]uint32 {
+		refs, err := m.store.List(ctx)
+		require.NoError(t, err)
+		versions := map[string][]uint32{}
+		for tenant, refs := range byTenant(refs) {
+			for _, ref := range refs {
+				versions[tenant] = append(versions[tenant], ref.Version)
+			}
+		}
+		return versions
+	}
+
+	// Replaced dictionaries are kept during the retention period.
+	m.now = func() time.Time { return time.Now().Add(24*time.Hour + retireDelay) }
+	require.NoError(t, m.retire(ctx))
+	require.Equal(t, map[string][]uint32{"tenant-a": {1, 2, 3}, "tenant-b": {1, 2, 3}, "tenant-c": {1, 2, 3}}, versions())
+
+	// Only the newest dictionary is kept afterwards. Tenants without retention
+	// keep all of them, and the longest stream retention applies.
+	m.now = func() time.Time { return time.Now().Add(2*cfg.SyncInterval + 24*time.Hour + retireDelay + time.Second) }
+	require.NoError(t, m.retire(ctx))
+	require.Equal(t, map[string][]uint32{"tenant-a": {3}, "tenant-b": {1, 2, 3}, "tenant-c": {1, 2, 3}}, versions())
+}
+
+func TestEvaluate(t *testing.T) {
+	var samples [][]byte
+	for _, e := range testEntries(2000) {
+		samples = append(samples, []byte(e.Line))
+	}
+	d, err := compression.TrainDictionary(compression.MinDictionaryID+10, samples[:1000], 16<<10)
+	require.NoError(t, err)
+
+	report, err := Evaluate(d, samples[1000:], 4<<10)
+	require.NoError(t, err)
+	require.Greater(t, report.Blocks, 1)
+	require.Greater(t, report.Gain(), 1.0)
+	require.Greater(t, report.DictionaryRatio(), report.PlainRatio())
+}
@@ -0,0 +1,99 @@
+package zstddict
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/grafana/loki/v3/pkg/compression"
+)
+
+// Report compares the compression of data with and without a dictionary.
+type Report struct {
+	Blocks            int
+	UncompressedBytes int
+	PlainBytes        int
+	DictionaryBytes   int
+}
+
+// PlainRatio returns the compression ratio of zstd without the dictionary.
+func (r Report) PlainRatio() float64 {
+	return ratio(r.UncompressedBytes, r.PlainBytes)
+}
+
+// DictionaryRatio returns the compression ratio of zstd with the dictionary.
+func (r Report) DictionaryRatio() float64 {
+	return ratio(r.UncompressedBytes, r.DictionaryBytes)
+}
+
+// Gain returns how much better the dictionary compresses, e.g. 1.5 means the
+// compression ratio is 50% higher with the dictionary.
+func (r Report) Gain() float64 {
+	return ratio(r.PlainBytes, r.DictionaryBytes)
+}
+
+func (r Report) String() string {
+	return fmt.Sprintf("blocks=%d uncompressed=%d zstd=%d (ratio %.2f) zstd-dict=%d (ratio %.2f) gain=%.2fx",
+		r.Blocks, r.UncompressedBytes, r.PlainBytes, r.PlainRatio(), r.DictionaryBytes, r.DictionaryRatio(), r.Gain())
+}
+
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
+
+// Evaluate compresses samples grouped into blocks of up to blockSize bytes
+// with and without d and reports the resulting sizes.
+func Evaluate(d *compression.Dictionary, samples [][]byte, blockSize int) (Report, error) {
+	var (
+		report Report
+		block  []byte
+	)
+	flush := func() error {
+		if len(block) == 0 {
+			return nil
+		}
+		plain, err := compressedSize(compression.GetWriterPool(compression.Zstd), block)
+		if err != nil {
+			return err
+		}
+		withDict, err := compressedSize(d, block)
+		if err != nil {
+			return err
+		}
+		report.Blocks++
+		report.UncompressedBytes += len(block)
+		report.PlainBytes += plain
+		report.DictionaryBytes += withDict
+		block = block[:0]
+		return nil
+	}
+
+	for _, s := range samples {
+		if len(block) > 0 && len(block)+len(s) > blockSize {
+			if err := flush(); err != nil {
+				return Report{}, err
+			}
+		}
+		block = append(block, s...)
+		block = append(block, '\n')
+	}
+	if err := flush(); err != nil {
+		return Report{}, err
+	}
+	return report, nil
+}
+
+func compressedSize(pool compression.WriterPool, data []byte) (int, error) {
+	var buf bytes.Buffer
+	w := pool.GetWriter(&buf)
+	defer pool.PutWriter(w)
+	if _, err := w.Write(data); err != nil {
+		return 0, err
+	}
+	if err := w.Close(); err != nil {
+		return 0, err
+	}
+	return buf.Len(), nil
+}
@@ -0,0 +1,83 @@
+package zstddict
+
+import (
+	"math/rand"
+	"sync"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+)
+
+// maxSampleSize caps the size of a single sampled line, longer lines are
+// truncated.
+const maxSampleSize = 4 << 10
+
+// Sampler keeps a uniform random sample of log lines per tenant using
+// reservoir sampling.
+type Sampler struct {
+	maxSamples int
+
+	mtx     sync.Mutex
+	tenants map[string]*reservoir
+}
+
+type reservoir struct {
+	seen    int
+	samples [][]byte
+}
+
+// NewSampler creates a Sampler keeping up to maxSamples lines per tenant.
+func NewSampler(maxSamples int) *Sampler {
+	return &Sampler{
+		maxSamples: maxSamples,
+		tenants:    map[string]*reservoir{},
+	}
+}
+
+// Sample offers the lines of entries to the sample of tenant.
+func (s *Sampler) Sample(tenant string, entries []logproto.Entry) {
+	if s.maxSamples <= 0 || len(entries) == 0 {
+		return
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	r, ok := s.tenants[tenant]
+	if !ok {
+		r = &reservoir{}
+		s.tenants[tenant] = r
+	}
+	for _, e := range entries {
+		r.seen++
+		idx := len(r.samples)
+		if idx >= s.maxSamples {
+			idx = rand.Intn(r.seen)
+			if idx >= s.maxSamples {
+				continue
+			}
+		}
+
+		line := e.Line
+		if len(line) > maxSampleSize {
+			line = line[:maxSampleSize]
+		}
+		if idx == len(r.samples) {
+			r.samples = append(r.samples, []byte(line))
+		} else {
+			r.samples[idx] = []byte(line)
+		}
+	}
+}
+
+// Drain returns and resets the samples of all tenants.
+func (s *Sampler) Drain() map[string][][]byte {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	res := make(map[string][][]byte, len(s.tenants))
+	for tenant, r := range s.tenants {
+		res[tenant] = r.samples
+	}
+	s.tenants = map[string]*reservoir{}
+	return res
+}
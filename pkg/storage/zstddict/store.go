@@ -0,0 +1,111 @@
+package zstddict
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/thanos-io/objstore"
+
+	"github.com/grafana/loki/v3/pkg/compression"
+)
+
+const fileExtension = ".zdict"
+
+// Ref references a stored dictionary of a tenant.
+type Ref struct {
+	Tenant  string
+	Version uint32
+	ID      uint32
+}
+
+// key returns the object storage key of the dictionary, which is
+// <tenant>/<version>-<id>.zdict. Versions are zero padded so listing a tenant
+// returns its dictionaries from the oldest to the newest one.
+func (r Ref) key() string {
+	return path.Join(r.Tenant, fmt.Sprintf("%010d-%010d%s", r.Version, r.ID, fileExtension))
+}
+
+func parseRef(key string) (Ref, bool) {
+	tenant, name := path.Split(key)
+	tenant = strings.TrimSuffix(tenant, "/")
+	if tenant == "" || !strings.HasSuffix(name, fileExtension) {
+		return Ref{}, false
+	}
+	var ref Ref
+	if _, err := fmt.Sscanf(strings.TrimSuffix(name, fileExtension), "%d-%d", &ref.Version, &ref.ID); err != nil {
+		return Ref{}, false
+	}
+	ref.Tenant = tenant
+	return ref, true
+}
+
+// Store stores versioned dictionaries of tenants in object storage.
+type Store struct {
+	bucket objstore.Bucket
+}
+
+// NewStore creates a new Store on top of bucket.
+func NewStore(bucket objstore.Bucket) *Store {
+	return &Store{bucket: bucket}
+}
+
+// Put uploads a dictionary.
+func (s *Store) Put(ctx context.Context, ref Ref, d *compression.Dictionary) error {
+	if ref.ID != d.ID {
+		return fmt.Errorf("dictionary ID %d does not match reference ID %d", d.ID, ref.ID)
+	}
+	return s.bucket.Upload(ctx, ref.key(), bytes.NewReader(d.Bytes()))
+}
+
+// Get downloads a dictionary.
+func (s *Store) Get(ctx context.Context, ref Ref) (*compression.Dictionary, error) {
+	rc, err := s.bucket.Get(ctx, ref.key())
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	raw, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+	d, err := compression.NewDictionary(raw)
+	if err != nil {
+		return nil, err
+	}
+	if d.ID != ref.ID {
+		return nil, fmt.Errorf("dictionary %s has ID %d", ref.key(), d.ID)
+	}
+	return d, nil
+}
+
+// Delete deletes a dictionary.
+func (s *Store) Delete(ctx context.Context, ref Ref) error {
+	return s.bucket.Delete(ctx, ref.key())
+}
+
+// StoredAt returns the time a dictionary was uploaded.
+func (s *Store) StoredAt(ctx context.Context, ref Ref) (time.Time, error) {
+	attrs, err := s.bucket.Attributes(ctx, ref.key())
+	if err != nil {
+		return time.Time{}, err
+	}
+	return attrs.LastModified, nil
+}
+
+// List returns the references of all stored dictionaries.
+func (s *Store) List(ctx context.Context) ([]Ref, error) {
+	var refs []Ref
+	err := s.bucket.Iter(ctx, "", func(key string) error {
+		if ref, ok := parseRef(key); ok {
+			refs = append(refs, ref)
+		}
+		return nil
+	}, objstore.WithRecursiveIter())
+	return refs, err
+}
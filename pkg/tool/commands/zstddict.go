@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"math/rand"
+	"os"
+
+	"github.com/alecthomas/kingpin/v2"
+
+	"github.com/grafana/loki/v3/pkg/compression"
+	"github.com/grafana/loki/v3/pkg/storage/zstddict"
+)
+
+// ZstdDictCommand trains zstd dictionaries and reports their compression
+// ratio gains.
+type ZstdDictCommand struct {
+	samplesFile    string
+	dictionaryFile string
+	dictionarySize int
+	blockSize      int
+	id             uint32
+}
+
+func (c *ZstdDictCommand) train(_ *kingpin.ParseContext) error {
+	samples, err := readSamples(c.samplesFile)
+	if err != nil {
+		return err
+	}
+
+	id := c.id
+	if id == 0 {
+		id = compression.MinDictionaryID + uint32(rand.Int63n(compression.MaxDictionaryID-compression.MinDictionaryID+1))
+	}
+	d, err := compression.TrainDictionary(id, samples, c.dictionarySize)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.dictionaryFile, d.Bytes(), 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("wrote dictionary %d (%d bytes) to %s\n", d.ID, len(d.Bytes()), c.dictionaryFile)
+	return nil
+}
+
+func (c *ZstdDictCommand) evaluate(_ *kingpin.ParseContext) error {
+	raw, err := os.ReadFile(c.dictionaryFile)
+	if err != nil {
+		return err
+	}
+	d, err := compression.NewDictionary(raw)
+	if err != nil {
+		return err
+	}
+	samples, err := readSamples(c.samplesFile)
+	if err != nil {
+		return err
+	}
+
+	report, err := zstddict.Evaluate(d, samples, c.blockSize)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("dictionary %d: %s\n", d.ID, report)
+	return nil
+}
+
+// readSamples reads the lines of a file.
+func readSamples(path string) ([][]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var samples [][]byte
+	s := bufio.NewScanner(f)
+	s.Buffer(nil, 1<<20)
+	for s.Scan() {
+		samples = append(samples, append([]byte(nil), s.Bytes()...))
+	}
+	return samples, s.Err()
+}
+
+func (c *ZstdDictCommand) Register(app *kingpin.Application) {
+	cmd := app.Command("zstd-dict", "Train zstd dictionaries and report their compression gains.")
+
+	trainCmd := cmd.Command("train", "Train a dictionary from a file of log lines.").Action(c.train)
+	trainCmd.Flag("samples.file", "File with one sample log line per line.").Required().StringVar(&c.samplesFile)
+	trainCmd.Flag("dictionary.file", "File to write the dictionary to.").Required().StringVar(&c.dictionaryFile)
+	trainCmd.Flag("dictionary.size", "Maximum size of the dictionary in bytes.").Default("65536").IntVar(&c.dictionarySize)
+	trainCmd.Flag("dictionary.id", "ID of the dictionary. A random ID is used if not set.").Uint32Var(&c.id)
+
+	evaluateCmd := cmd.Command("evaluate", "Report the compression ratio of log lines with and without a dictionary.").Action(c.evaluate)
+	evaluateCmd.Flag("samples.file", "File with one log line per line.").Required().StringVar(&c.samplesFile)
+	evaluateCmd.Flag("dictionary.file", "File holding the dictionary.").Required().StringVar(&c.dictionaryFile)
+	evaluateCmd.Flag("block.size", "Size of the blocks the log lines are compressed in.").Default("262144").IntVar(&c.blockSize)
+}
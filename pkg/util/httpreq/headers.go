@@ -15,6 +15,13 @@ var (
 	LokiActorPathHeader               = "X-Loki-Actor-Path"
 	LokiDisablePipelineWrappersHeader = "X-Loki-Disable-Pipeline-Wrappers"
 
+	// LokiQueryPriorityHeader is the name of the header holding the priority
+	// with which the scheduler queues a request.
+	LokiQueryPriorityHeader = "X-Loki-Query-Priority"
+	// LowQueryPriority is the value of LokiQueryPriorityHeader of the requests
+	// queued in the low priority lane of the scheduler.
+	LowQueryPriority = "low"
+
 	// LokiActorPathDelimiter is the delimiter used to serialise the hierarchy of the actor.
 	LokiActorPathDelimiter = "|"
 )
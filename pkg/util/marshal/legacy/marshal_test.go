@@ -226,6 +226,7 @@ var queryTests = []struct {
 				},
 				"summary": {
 					"bytesProcessedPerSecond": 0,
+					"estimatedCost": 0,
 					"execTime": 0,
 					"linesProcessedPerSecond": 0,
 					"queueTime": 0,
@@ -197,6 +197,7 @@ const emptyStats = `{
 	},
 	"summary": {
 		"bytesProcessedPerSecond": 0,
+		"estimatedCost": 0,
 		"execTime": 0,
 		"linesProcessedPerSecond": 0,
 		"queueTime": 0,
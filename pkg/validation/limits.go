@@ -24,6 +24,7 @@ import (
 	"github.com/grafana/loki/v3/pkg/distributor/shardstreams"
 	"github.com/grafana/loki/v3/pkg/loghttp/push"
 	"github.com/grafana/loki/v3/pkg/logql"
+	"github.com/grafana/loki/v3/pkg/logql/log"
 	"github.com/grafana/loki/v3/pkg/logql/syntax"
 	ruler_config "github.com/grafana/loki/v3/pkg/ruler/config"
 	"github.com/grafana/loki/v3/pkg/ruler/util"
@@ -138,6 +139,9 @@ type Limits struct {
 	MinShardingLookback              model.Duration   `yaml:"min_sharding_lookback" json:"min_sharding_lookback"`
 	MaxQueryBytesRead                flagext.ByteSize `yaml:"max_query_bytes_read" json:"max_query_bytes_read"`
 	MaxQuerierBytesRead              flagext.ByteSize `yaml:"max_querier_bytes_read" json:"max_querier_bytes_read"`
+	QueryCostLowPriorityThreshold    flagext.ByteSize `yaml:"query_cost_low_priority_threshold" json:"query_cost_low_priority_threshold"`
+	ExportEnabled                    bool             `yaml:"export_enabled" json:"export_enabled"`
+	MaxExportRange                   model.Duration   `yaml:"max_export_range" json:"max_export_range"`
 	VolumeEnabled                    bool             `yaml:"volume_enabled" json:"volume_enabled" doc:"description=Enable log-volume endpoints."`
 	VolumeMaxSeries                  int              `yaml:"volume_max_series" json:"volume_max_series" doc:"description=The maximum number of aggregated series in a log-volume response"`
 
@@ -233,6 +237,9 @@ type Limits struct {
 	OTLPConfig                        *push.OTLPConfig      `yaml:"otlp_config" json:"otlp_config" doc:"description=OTLP log ingestion configurations"`
 	GlobalOTLPConfig                  push.GlobalOTLPConfig `yaml:"-" json:"-"`
 
+	IngestPipelines []IngestPipeline `yaml:"ingest_pipelines,omitempty" json:"ingest_pipelines,omitempty" category:"experimental" doc:"description=Experimental: LogQL log queries whose pipeline stages are applied by the distributor to the entries of the streams matching their stream selector, before the entries are validated. Each pipeline has a 'name', used in metrics, and a 'query'. Entries filtered out by a pipeline are dropped. Labels extracted or changed by a pipeline are stored as structured metadata, stream labels are never changed."`
+	Redaction       RedactionConfig  `yaml:"redaction" json:"redaction" category:"experimental" doc:"description=Experimental: Redaction of sensitive data from log lines and structured metadata by the distributor, before the entries are written. Redaction is enabled when at least one detector or pattern is configured."`
+
 	BlockIngestionPolicyUntil map[string]dskit_flagext.Time `yaml:"block_ingestion_policy_until" json:"block_ingestion_policy_until" category:"experimental" doc:"description=Block ingestion for policy until the configured date. The policy '*' is the global policy, which is applied to all streams not matching a policy and can be overridden by other policies. The time should be in RFC3339 format. The policy is based on the policy_stream_mapping configuration."`
 	BlockIngestionUntil       dskit_flagext.Time            `yaml:"block_ingestion_until" json:"block_ingestion_until" category:"experimental"`
 	BlockIngestionStatusCode  int                           `yaml:"block_ingestion_status_code" json:"block_ingestion_status_code"`
@@ -277,6 +284,26 @@ type StreamRetention struct {
 	Matchers []*labels.Matcher `yaml:"-" json:"-"` // populated during validation.
 }
 
+// IngestPipeline is a LogQL log query whose pipeline stages are applied to the
+// entries of the matching streams at ingestion time.
+type IngestPipeline struct {
+	Name  string                 `yaml:"name" json:"name" doc:"description=Name of the pipeline, used in metrics."`
+	Query string                 `yaml:"query" json:"query" doc:"description=LogQL log query."`
+	Expr  syntax.LogSelectorExpr `yaml:"-" json:"-"` // populated during validation.
+}
+
+// RedactionConfig configures the detectors used to redact sensitive data at ingestion time.
+type RedactionConfig struct {
+	Detectors []string          `yaml:"detectors,omitempty" json:"detectors,omitempty" doc:"description=Built-in detectors to apply. Supported values: credit_card (Luhn-validated payment card numbers), email, jwt, aws_key (AWS access key IDs)."`
+	Patterns  map[string]string `yaml:"patterns,omitempty" json:"patterns,omitempty" doc:"description=Custom regular expressions to redact, keyed by the name used in the replacement text <redacted:name>."`
+	Redactor  *log.Redactor     `yaml:"-" json:"-"` // populated during validation.
+}
+
+// Enabled returns true if at least one detector or pattern is configured.
+func (c RedactionConfig) Enabled() bool {
+	return len(c.Detectors) > 0 || len(c.Patterns) > 0
+}
+
 // LimitError are errors that do not comply with the limits specified.
 type LimitError string
 
@@ -376,6 +403,12 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	_ = l.MaxQuerierBytesRead.Set("150GB")
 	f.Var(&l.MaxQuerierBytesRead, "frontend.max-querier-bytes-read", "Max number of bytes a query can fetch after splitting and sharding. Enforced in log and metric queries only when TSDB is used. This limit is not enforced on log queries without filters. The default value of 0 disables this limit.")
 
+	f.Var(&l.QueryCostLowPriorityThreshold, "frontend.query-cost-low-priority-threshold", "Estimated cost, in bytes, from which queries are queued in the low priority lane of the scheduler instead of the regular one. The cost combines the bytes read according to the index with the shards, the range, the parser stages and the regular expressions of the query. Estimated only when TSDB is used. The default value of 0 disables the low priority lane.")
+
+	f.BoolVar(&l.ExportEnabled, "frontend.export-enabled", true, "Allow the tenant to submit export jobs, when the export API is enabled with -frontend.export.enabled.")
+	_ = l.MaxExportRange.Set("30d")
+	f.Var(&l.MaxExportRange, "frontend.max-export-range", "Maximum time range of the log queries exported by the tenant. The default value of 0 disables this limit.")
+
 	_ = l.MaxCacheFreshness.Set("10m")
 	f.Var(&l.MaxCacheFreshness, "frontend.max-cache-freshness", "Most recent allowed cacheable result per-tenant, to prevent caching very recent results that might still be in flux.")
 
@@ -575,6 +608,27 @@ func (l *Limits) Validate() error {
 		}
 	}
 
+	for i, p := range l.IngestPipelines {
+		expr, err := syntax.ParseLogSelector(p.Query, true)
+		if err != nil {
+			return fmt.Errorf("invalid ingest pipeline %q: %w", p.Name, err)
+		}
+		if p.Name == "" {
+			l.IngestPipelines[i].Name = strconv.Itoa(i)
+		}
+		// populate the expression during validation
+		l.IngestPipelines[i].Expr = expr
+	}
+
+	if l.Redaction.Enabled() {
+		redactor, err := log.NewRedactor(l.Redaction.Detectors, l.Redaction.Patterns)
+		if err != nil {
+			return fmt.Errorf("invalid redaction config: %w", err)
+		}
+		// populate the redactor during validation
+		l.Redaction.Redactor = redactor
+	}
+
 	if l.PolicyStreamMapping != nil {
 		if err := l.PolicyStreamMapping.Validate(); err != nil {
 			return err
@@ -883,6 +937,21 @@ func (o *Overrides) MaxQuerierBytesRead(_ context.Context, userID string) int {
 	return o.getOverridesForUser(userID).MaxQuerierBytesRead.Val()
 }
 
+// QueryCostLowPriorityThreshold returns the estimated cost from which queries are queued with a low priority.
+func (o *Overrides) QueryCostLowPriorityThreshold(_ context.Context, userID string) int {
+	return o.getOverridesForUser(userID).QueryCostLowPriorityThreshold.Val()
+}
+
+// ExportEnabled returns whether a user can submit export jobs.
+func (o *Overrides) ExportEnabled(userID string) bool {
+	return o.getOverridesForUser(userID).ExportEnabled
+}
+
+// MaxExportRange returns the maximum time range of the log queries exported by a user.
+func (o *Overrides) MaxExportRange(userID string) time.Duration {
+	return time.Duration(o.getOverridesForUser(userID).MaxExportRange)
+}
+
 // MaxConcurrentTailRequests returns the limit to number of concurrent tail requests.
 func (o *Overrides) MaxConcurrentTailRequests(_ context.Context, userID string) int {
 	return o.getOverridesForUser(userID).MaxConcurrentTailRequests
@@ -1120,6 +1189,30 @@ func (o *Overrides) DiscoverGenericFields(userID string) map[string][]string {
 	return o.getOverridesForUser(userID).DiscoverGenericFields.Fields
 }
 
+func (o *Overrides) IngestPipelines(userID string) []IngestPipeline {
+	return o.getOverridesForUser(userID).IngestPipelines
+}
+
+func (o *Overrides) Redaction(userID string) RedactionConfig {
+	return o.getOverridesForUser(userID).Redaction
+}
+
+// QueryRedaction implements log.RedactionLimits.
+func (o *Overrides) QueryRedaction(userID string) (*log.Redactor, map[string]string, error) {
+	cfg := o.Redaction(userID)
+	if !cfg.Enabled() {
+		return nil, nil, nil
+	}
+	if cfg.Redactor != nil {
+		return cfg.Redactor, cfg.Patterns, nil
+	}
+	redactor, err := log.NewRedactor(cfg.Detectors, cfg.Patterns)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid redaction config of tenant %s: %w", userID, err)
+	}
+	return redactor, cfg.Patterns, nil
+}
+
 func (o *Overrides) DiscoverServiceName(userID string) []string {
 	return o.getOverridesForUser(userID).DiscoverServiceName
 }
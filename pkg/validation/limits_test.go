@@ -940,3 +940,76 @@ otlp_config:
 		})
 	}
 }
+
+func Test_IngestPipelines(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		yaml          string
+		expectedErr   string
+		expectedNames []string
+	}{
+		{
+			name: "valid pipelines",
+			yaml: `
+ingest_pipelines:
+  - name: drop_debug
+    query: '{app="api"} | json | level != "debug"'
+  - query: '{app="web"} | line_format "{{.msg}}"'
+`,
+			expectedNames: []string{"drop_debug", "1"},
+		},
+		{
+			name: "invalid query",
+			yaml: `
+ingest_pipelines:
+  - name: broken
+    query: '{app="api"} | json |'
+`,
+			expectedErr: `invalid ingest pipeline "broken"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			limits := Limits{DeletionMode: "disabled", BloomBlockEncoding: "none", OTLPConfig: &push.OTLPConfig{}}
+			require.NoError(t, yaml.Unmarshal([]byte(tc.yaml), &limits))
+
+			limits.TSDBShardingStrategy = logql.PowerOfTwoVersion.String()
+			limits.TSDBMaxBytesPerShard = DefaultTSDBMaxBytesPerShard
+			err := limits.Validate()
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			overrides := Overrides{defaultLimits: &limits}
+			pipelines := overrides.IngestPipelines("fake")
+			require.Len(t, pipelines, len(tc.expectedNames))
+			for i, p := range pipelines {
+				require.Equal(t, tc.expectedNames[i], p.Name)
+				require.NotNil(t, p.Expr)
+			}
+		})
+	}
+}
+
+func Test_Redaction(t *testing.T) {
+	limits := Limits{DeletionMode: "disabled", BloomBlockEncoding: "none", OTLPConfig: &push.OTLPConfig{}}
+	limits.TSDBShardingStrategy = logql.PowerOfTwoVersion.String()
+	limits.TSDBMaxBytesPerShard = DefaultTSDBMaxBytesPerShard
+	require.NoError(t, yaml.Unmarshal([]byte(`
+redaction:
+  detectors: [email]
+  patterns:
+    ssn: '\d{3}-\d{2}-\d{4}'
+`), &limits))
+	require.NoError(t, limits.Validate())
+
+	overrides := Overrides{defaultLimits: &limits}
+	redaction := overrides.Redaction("fake")
+	require.True(t, redaction.Enabled())
+	require.NotNil(t, redaction.Redactor)
+	require.Equal(t, "<redacted:email> <redacted:ssn>", redaction.Redactor.RedactString("jane@example.com 123-45-6789", nil))
+
+	limits.Redaction = RedactionConfig{Detectors: []string{"phone_number"}}
+	require.ErrorContains(t, limits.Validate(), "invalid redaction config")
+}